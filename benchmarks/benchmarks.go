@@ -0,0 +1,181 @@
+// Package benchmarks provides a deterministic benchmark suite over
+// canonical workloads (key generation, encoding, encryption, decryption,
+// addition, multiplication with relinearization and rescaling, rotation
+// and bootstrapping), producing machine-readable results. All inputs and
+// encryption randomness are derived from a user-provided seed, so runs of
+// the same suite time the same computation: the results can be used to
+// compare parameter sets and hardware, and to track performance
+// regressions quantitatively.
+//
+// A Suite is instantiated for a parameter set with NewFloatSuite,
+// NewIntSuite or NewBootstrapSuite, and its workloads are timed with the
+// standard library benchmark harness:
+//
+//	suite, err := benchmarks.NewFloatSuite(paramsLit, 0)
+//	// handle err
+//	results, err := suite.Run()
+//	// handle err
+//	results.WriteJSON(os.Stdout)
+package benchmarks
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/luxdefi/lattice/v5/utils/sampling"
+)
+
+// Result holds the measurements of a single workload. The fields mirror
+// the standard library testing.BenchmarkResult and serialize to JSON.
+type Result struct {
+	// Workload is the name of the timed workload.
+	Workload string `json:"workload"`
+	// Scheme is the name of the scheme the suite was instantiated for.
+	Scheme string `json:"scheme"`
+	// LogN is the base 2 logarithm of the ring degree of the parameters.
+	LogN int `json:"logN"`
+	// LogQP is the size of the modulus QP of the parameters in bits.
+	LogQP float64 `json:"logQP"`
+	// Iterations is the number of times the workload was executed.
+	Iterations int `json:"iterations"`
+	// NsPerOp is the average time per execution in nanoseconds.
+	NsPerOp int64 `json:"nsPerOp"`
+	// AllocBytesPerOp is the average number of bytes allocated per execution.
+	AllocBytesPerOp int64 `json:"allocBytesPerOp"`
+	// AllocsPerOp is the average number of allocations per execution.
+	AllocsPerOp int64 `json:"allocsPerOp"`
+}
+
+// Results is a list of workload measurements.
+type Results []Result
+
+// WriteJSON writes the results on w as indented JSON.
+func (r Results) WriteJSON(w io.Writer) (err error) {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// workload is a named, self-contained benchmark case executing one
+// operation per call.
+type workload struct {
+	name string
+	f    func() error
+}
+
+// Suite is a set of canonical workloads instantiated for a fixed
+// parameter set and seed.
+type Suite struct {
+	scheme    string
+	logN      int
+	logQP     float64
+	workloads []workload
+}
+
+// Workloads returns the names of the workloads of the suite, in the order
+// in which Run executes them.
+func (s Suite) Workloads() (names []string) {
+	names = make([]string, len(s.workloads))
+	for i := range s.workloads {
+		names[i] = s.workloads[i].name
+	}
+	return
+}
+
+// Run times all the workloads of the suite and returns their results.
+// Each workload is executed enough times for the measurement to
+// stabilize (see testing.Benchmark).
+func (s Suite) Run() (results Results, err error) {
+	results = make(Results, 0, len(s.workloads))
+	for _, w := range s.workloads {
+		var res Result
+		if res, err = s.run(w); err != nil {
+			return nil, err
+		}
+		results = append(results, res)
+	}
+	return
+}
+
+// RunWorkload times the workload of the given name (see Workloads) and
+// returns its result.
+func (s Suite) RunWorkload(name string) (result Result, err error) {
+	for _, w := range s.workloads {
+		if w.name == name {
+			return s.run(w)
+		}
+	}
+	return Result{}, fmt.Errorf("cannot RunWorkload: suite has no workload %q", name)
+}
+
+func (s Suite) run(w workload) (result Result, err error) {
+
+	res := testing.Benchmark(func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if errW := w.f(); errW != nil {
+				err = fmt.Errorf("cannot run workload %q: %w", w.name, errW)
+				return
+			}
+		}
+	})
+
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{
+		Workload:        w.name,
+		Scheme:          s.scheme,
+		LogN:            s.logN,
+		LogQP:           s.logQP,
+		Iterations:      res.N,
+		NsPerOp:         res.NsPerOp(),
+		AllocBytesPerOp: res.AllocedBytesPerOp(),
+		AllocsPerOp:     res.AllocsPerOp(),
+	}, nil
+}
+
+// newKeyedPRNG returns a PRNG keyed with the given seed.
+func newKeyedPRNG(seed int64) *sampling.KeyedPRNG {
+	key := make([]byte, 8)
+	binary.LittleEndian.PutUint64(key, uint64(seed))
+	prng, err := sampling.NewKeyedPRNG(key)
+	if err != nil {
+		// Sanity check, this error should not happen.
+		panic(err)
+	}
+	return prng
+}
+
+// randomFloats returns n floats in [-1, 1] derived from the PRNG.
+func randomFloats(prng io.Reader, n int) (values []float64) {
+	buff := make([]byte, 8*n)
+	if _, err := io.ReadFull(prng, buff); err != nil {
+		// Sanity check, this error should not happen.
+		panic(err)
+	}
+	values = make([]float64, n)
+	for i := range values {
+		u := binary.LittleEndian.Uint64(buff[i*8:])
+		values[i] = 2*float64(u>>11)/(1<<53) - 1
+	}
+	return
+}
+
+// randomUints returns n uints in [0, max) derived from the PRNG.
+func randomUints(prng io.Reader, n int, max uint64) (values []uint64) {
+	buff := make([]byte, 8*n)
+	if _, err := io.ReadFull(prng, buff); err != nil {
+		// Sanity check, this error should not happen.
+		panic(err)
+	}
+	values = make([]uint64, n)
+	for i := range values {
+		values[i] = binary.LittleEndian.Uint64(buff[i*8:]) % max
+	}
+	return
+}
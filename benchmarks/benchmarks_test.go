@@ -0,0 +1,106 @@
+package benchmarks_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/luxdefi/lattice/v5/benchmarks"
+	"github.com/luxdefi/lattice/v5/he/hefloat"
+	"github.com/luxdefi/lattice/v5/he/hefloat/bootstrapping"
+	"github.com/luxdefi/lattice/v5/he/heint"
+	"github.com/luxdefi/lattice/v5/utils"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFloatSuite(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	suite, err := benchmarks.NewFloatSuite(hefloat.ParametersLiteral{
+		LogN:            10,
+		LogQ:            []int{55, 45, 45},
+		LogP:            []int{60},
+		LogDefaultScale: 45,
+	}, 1)
+	require.NoError(t, err)
+
+	names := suite.Workloads()
+	for _, name := range []string{"KeyGen/SecretKey", "Encode", "Encrypt", "Decrypt", "Add", "MulRelinRescale", "Rotate"} {
+		require.Contains(t, names, name)
+	}
+
+	res, err := suite.RunWorkload("Encrypt")
+	require.NoError(t, err)
+	require.Equal(t, "Encrypt", res.Workload)
+	require.Equal(t, "hefloat", res.Scheme)
+	require.Equal(t, 10, res.LogN)
+	require.Greater(t, res.Iterations, 0)
+	require.Greater(t, res.NsPerOp, int64(0))
+
+	res, err = suite.RunWorkload("MulRelinRescale")
+	require.NoError(t, err)
+	require.Greater(t, res.NsPerOp, int64(0))
+
+	_, err = suite.RunWorkload("Bootstrap")
+	require.Error(t, err)
+
+	buff := new(bytes.Buffer)
+	require.NoError(t, benchmarks.Results{res}.WriteJSON(buff))
+
+	var results benchmarks.Results
+	require.NoError(t, json.Unmarshal(buff.Bytes(), &results))
+	require.Equal(t, benchmarks.Results{res}, results)
+}
+
+func TestIntSuite(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	suite, err := benchmarks.NewIntSuite(heint.ParametersLiteral{
+		LogN:             10,
+		LogQ:             []int{45, 45},
+		LogP:             []int{60},
+		PlaintextModulus: 0x10001,
+	}, 1)
+	require.NoError(t, err)
+
+	res, err := suite.RunWorkload("MulRelinRescale")
+	require.NoError(t, err)
+	require.Equal(t, "heint", res.Scheme)
+	require.Greater(t, res.NsPerOp, int64(0))
+}
+
+func TestBootstrapSuite(t *testing.T) {
+
+	if testing.Short() {
+		t.Skip("skipping bootstrapping benchmark suite in short mode")
+	}
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := hefloat.NewParametersFromLiteral(hefloat.ParametersLiteral{
+		LogN:            10,
+		LogQ:            []int{60, 40},
+		LogP:            []int{61},
+		LogDefaultScale: 40,
+	})
+	require.NoError(t, err)
+
+	btpParams, err := bootstrapping.NewParametersFromLiteral(params, bootstrapping.ParametersLiteral{
+		LogN: utils.Pointy(params.LogN()),
+	})
+	require.NoError(t, err)
+
+	// Insecure params for fast testing only
+	btpParams.SlotsToCoeffsParameters.LogSlots = btpParams.BootstrappingParameters.LogN() - 1
+	btpParams.CoeffsToSlotsParameters.LogSlots = btpParams.BootstrappingParameters.LogN() - 1
+	btpParams.Mod1ParametersLiteral.LogMessageRatio += 16 - params.LogN()
+
+	suite, err := benchmarks.NewBootstrapSuite(btpParams, 1)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"Bootstrap"}, suite.Workloads())
+
+	res, err := suite.RunWorkload("Bootstrap")
+	require.NoError(t, err)
+	require.Greater(t, res.NsPerOp, int64(0))
+}
@@ -0,0 +1,171 @@
+package benchmarks
+
+import (
+	"fmt"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/he"
+	"github.com/luxdefi/lattice/v5/he/hefloat"
+	"github.com/luxdefi/lattice/v5/he/hefloat/bootstrapping"
+	"github.com/luxdefi/lattice/v5/he/heint"
+	"github.com/luxdefi/lattice/v5/utils/sampling"
+)
+
+// NewFloatSuite instantiates the canonical workloads for a fixed-point
+// approximate arithmetic (hefloat) parameter set. The plaintext values
+// and the encryption randomness are derived from the seed.
+func NewFloatSuite(paramsLit hefloat.ParametersLiteral, seed int64) (s *Suite, err error) {
+
+	params, err := hefloat.NewParametersFromLiteral(paramsLit)
+	if err != nil {
+		return nil, fmt.Errorf("cannot NewFloatSuite: %w", err)
+	}
+
+	prng := newKeyedPRNG(seed)
+
+	values := randomFloats(prng, params.MaxSlots())
+
+	return newSchemeSuite(
+		"hefloat",
+		*params.GetRLWEParameters(),
+		params.GaloisElement(1),
+		hefloat.NewEncoder(params),
+		func(evk rlwe.EvaluationKeySet) he.Evaluator { return hefloat.NewEvaluator(params, evk) },
+		values,
+		hefloat.NewPlaintext(params, params.MaxLevel()),
+		prng,
+	)
+}
+
+// NewIntSuite instantiates the canonical workloads for a modular
+// arithmetic (heint) parameter set. The plaintext values and the
+// encryption randomness are derived from the seed.
+func NewIntSuite(paramsLit heint.ParametersLiteral, seed int64) (s *Suite, err error) {
+
+	params, err := heint.NewParametersFromLiteral(paramsLit)
+	if err != nil {
+		return nil, fmt.Errorf("cannot NewIntSuite: %w", err)
+	}
+
+	prng := newKeyedPRNG(seed)
+
+	values := randomUints(prng, params.MaxSlots(), params.PlaintextModulus())
+
+	return newSchemeSuite(
+		"heint",
+		*params.GetRLWEParameters(),
+		params.GaloisElement(1),
+		heint.NewEncoder(params),
+		func(evk rlwe.EvaluationKeySet) he.Evaluator { return heint.NewEvaluator(params, evk) },
+		values,
+		heint.NewPlaintext(params, params.MaxLevel()),
+		prng,
+	)
+}
+
+// NewBootstrapSuite instantiates the bootstrapping workload for a
+// bootstrapping parameter set. The suite generates the bootstrapping
+// evaluation keys, which can take a substantial amount of time and
+// memory for secure parameters. The reported ring degree and modulus
+// sizes are the ones of the bootstrapping circuit.
+func NewBootstrapSuite(btpParams bootstrapping.Parameters, seed int64) (s *Suite, err error) {
+
+	params := btpParams.ResidualParameters
+
+	prng := newKeyedPRNG(seed)
+
+	sk := rlwe.NewKeyGenerator(btpParams.BootstrappingParameters).GenSecretKeyNew()
+
+	btpKeys, _, err := btpParams.GenEvaluationKeys(sk)
+	if err != nil {
+		return nil, fmt.Errorf("cannot NewBootstrapSuite: %w", err)
+	}
+
+	eval, err := bootstrapping.NewEvaluator(btpParams, btpKeys)
+	if err != nil {
+		return nil, fmt.Errorf("cannot NewBootstrapSuite: %w", err)
+	}
+
+	ecd := hefloat.NewEncoder(params)
+	enc := rlwe.NewEncryptor(params, sk).WithPRNG(prng)
+
+	values := randomFloats(prng, params.MaxSlots())
+
+	pt := hefloat.NewPlaintext(params, 0)
+	if err = ecd.Encode(values, pt); err != nil {
+		return nil, fmt.Errorf("cannot NewBootstrapSuite: %w", err)
+	}
+
+	ct, err := enc.EncryptNew(pt)
+	if err != nil {
+		return nil, fmt.Errorf("cannot NewBootstrapSuite: %w", err)
+	}
+
+	btpParamsN2 := btpParams.BootstrappingParameters
+
+	return &Suite{
+		scheme: "hefloat",
+		logN:   btpParamsN2.LogN(),
+		logQP:  btpParamsN2.LogQP(),
+		workloads: []workload{
+			{"Bootstrap", func() error {
+				_, errW := eval.Bootstrap(ct.CopyNew())
+				return errW
+			}},
+		},
+	}, nil
+}
+
+// newSchemeSuite assembles the canonical scheme-level workloads from the
+// scheme-agnostic front-end handles.
+func newSchemeSuite(schemeName string, params rlwe.Parameters, galEl uint64, ecd he.SlotEncoder, newEval func(rlwe.EvaluationKeySet) he.Evaluator, values interface{}, pt *rlwe.Plaintext, prng sampling.PRNG) (s *Suite, err error) {
+
+	kgen := rlwe.NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+	evk := rlwe.NewMemEvaluationKeySet(kgen.GenRelinearizationKeyNew(sk), kgen.GenGaloisKeysNew([]uint64{galEl}, sk)...)
+
+	enc := rlwe.NewEncryptor(params, sk).WithPRNG(prng)
+	dec := rlwe.NewDecryptor(params, sk)
+	eval := newEval(evk)
+
+	if err = ecd.EncodeSlots(values, pt); err != nil {
+		return nil, fmt.Errorf("cannot newSchemeSuite: %w", err)
+	}
+
+	ct, err := enc.EncryptNew(pt)
+	if err != nil {
+		return nil, fmt.Errorf("cannot newSchemeSuite: %w", err)
+	}
+
+	ctOut := ct.CopyNew()
+	ctRescale := ct.CopyNew()
+	ptOut := pt.CopyNew()
+
+	workloads := []workload{
+		{"KeyGen/SecretKey", func() error { kgen.GenSecretKeyNew(); return nil }},
+		{"KeyGen/PublicKey", func() error { kgen.GenPublicKeyNew(sk); return nil }},
+		{"KeyGen/RelinearizationKey", func() error { kgen.GenRelinearizationKeyNew(sk); return nil }},
+		{"KeyGen/GaloisKey", func() error { kgen.GenGaloisKeysNew([]uint64{galEl}, sk); return nil }},
+		{"Encode", func() error { return ecd.EncodeSlots(values, pt) }},
+		{"Encrypt", func() error { return enc.Encrypt(pt, ct) }},
+		{"Decrypt", func() error { dec.Decrypt(ct, ptOut); return nil }},
+		{"Add", func() error { return eval.Add(ct, ct, ctOut) }},
+		{"MulRelinRescale", func() error {
+			if errW := eval.MulRelin(ct, ct, ctOut); errW != nil {
+				return errW
+			}
+			return eval.Rescale(ctOut, ctRescale)
+		}},
+	}
+
+	if rot, ok := eval.(he.SlotRotator); ok {
+		workloads = append(workloads, workload{"Rotate", func() error { return rot.RotateSlots(ct, 1, ctOut) }})
+	}
+
+	return &Suite{
+		scheme:    schemeName,
+		logN:      params.LogN(),
+		logQP:     params.LogQP(),
+		workloads: workloads,
+	}, nil
+}
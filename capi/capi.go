@@ -0,0 +1,418 @@
+// Package main implements a C ABI export layer for the library, allowing
+// cross-language bindings (e.g., Python or Node) to be generated without
+// reimplementing the schemes.
+//
+// The layer follows a handle + byte-buffer model: Go objects (parameters, keys,
+// ciphertexts, evaluators) live on the Go side and are referred to through
+// opaque int64 handles, while serialized objects and encoded values cross the
+// boundary as C byte buffers. All exported functions are safe for concurrent
+// use, with the exception that an evaluator handle must not be used by two
+// goroutines/threads at once.
+//
+// Build it as a shared library with:
+//
+//	go build -buildmode=c-shared -o liblattice.so ./capi
+//
+// which also produces the liblattice.h header consumed by the bindings.
+package main
+
+/*
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"unsafe"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/he/hefloat"
+)
+
+// handles maps the opaque identifiers handed out to the caller to their Go objects.
+var handles = struct {
+	sync.Mutex
+	next    int64
+	objects map[int64]interface{}
+}{next: 1, objects: map[int64]interface{}{}}
+
+var lastError = struct {
+	sync.Mutex
+	msg string
+}{}
+
+func setError(err error) int64 {
+	lastError.Lock()
+	lastError.msg = err.Error()
+	lastError.Unlock()
+	return 0
+}
+
+func register(obj interface{}) int64 {
+	handles.Lock()
+	defer handles.Unlock()
+	h := handles.next
+	handles.next++
+	handles.objects[h] = obj
+	return h
+}
+
+func lookup(h int64) (interface{}, error) {
+	handles.Lock()
+	defer handles.Unlock()
+	obj, ok := handles.objects[h]
+	if !ok {
+		return nil, fmt.Errorf("invalid handle %d", h)
+	}
+	return obj, nil
+}
+
+func lookupParams(h int64) (hefloat.Parameters, error) {
+	obj, err := lookup(h)
+	if err != nil {
+		return hefloat.Parameters{}, err
+	}
+	params, ok := obj.(hefloat.Parameters)
+	if !ok {
+		return hefloat.Parameters{}, fmt.Errorf("handle %d is a %T, not parameters", h, obj)
+	}
+	return params, nil
+}
+
+// evaluatorContext ties an evaluator to the parameters it was instantiated with.
+type evaluatorContext struct {
+	params hefloat.Parameters
+	eval   *hefloat.Evaluator
+}
+
+func goBytes(buf *C.char, size C.size_t) []byte {
+	return C.GoBytes(unsafe.Pointer(buf), C.int(size))
+}
+
+//export lattice_last_error
+// lattice_last_error returns the message of the last error that occurred, as a
+// C string that must be released with lattice_free_buffer. It returns NULL if
+// no error occurred yet.
+func lattice_last_error() *C.char {
+	lastError.Lock()
+	defer lastError.Unlock()
+	if lastError.msg == "" {
+		return nil
+	}
+	return C.CString(lastError.msg)
+}
+
+//export lattice_free_handle
+// lattice_free_handle releases the Go object behind the given handle.
+func lattice_free_handle(h int64) {
+	handles.Lock()
+	delete(handles.objects, h)
+	handles.Unlock()
+}
+
+//export lattice_free_buffer
+// lattice_free_buffer releases a buffer allocated by this library.
+func lattice_free_buffer(buf *C.char) {
+	C.free(unsafe.Pointer(buf))
+}
+
+//export lattice_params_new
+// lattice_params_new instantiates parameters from a JSON-encoded
+// hefloat.ParametersLiteral and returns their handle, or 0 on error.
+func lattice_params_new(literalJSON *C.char) int64 {
+	var literal hefloat.ParametersLiteral
+	if err := json.Unmarshal([]byte(C.GoString(literalJSON)), &literal); err != nil {
+		return setError(err)
+	}
+	params, err := hefloat.NewParametersFromLiteral(literal)
+	if err != nil {
+		return setError(err)
+	}
+	return register(params)
+}
+
+//export lattice_keygen_secret
+// lattice_keygen_secret generates a new secret key and returns its handle, or 0 on error.
+func lattice_keygen_secret(paramsH int64) int64 {
+	params, err := lookupParams(paramsH)
+	if err != nil {
+		return setError(err)
+	}
+	return register(hefloat.NewKeyGenerator(params).GenSecretKeyNew())
+}
+
+//export lattice_keygen_public
+// lattice_keygen_public generates the public key of the given secret key and
+// returns its handle, or 0 on error.
+func lattice_keygen_public(paramsH, skH int64) int64 {
+	params, err := lookupParams(paramsH)
+	if err != nil {
+		return setError(err)
+	}
+	obj, err := lookup(skH)
+	if err != nil {
+		return setError(err)
+	}
+	sk, ok := obj.(*rlwe.SecretKey)
+	if !ok {
+		return setError(fmt.Errorf("handle %d is a %T, not a secret key", skH, obj))
+	}
+	return register(hefloat.NewKeyGenerator(params).GenPublicKeyNew(sk))
+}
+
+//export lattice_keygen_relinearization
+// lattice_keygen_relinearization generates the relinearization key of the given
+// secret key and returns its handle, or 0 on error.
+func lattice_keygen_relinearization(paramsH, skH int64) int64 {
+	params, err := lookupParams(paramsH)
+	if err != nil {
+		return setError(err)
+	}
+	obj, err := lookup(skH)
+	if err != nil {
+		return setError(err)
+	}
+	sk, ok := obj.(*rlwe.SecretKey)
+	if !ok {
+		return setError(fmt.Errorf("handle %d is a %T, not a secret key", skH, obj))
+	}
+	return register(hefloat.NewKeyGenerator(params).GenRelinearizationKeyNew(sk))
+}
+
+//export lattice_evaluator_new
+// lattice_evaluator_new instantiates an evaluator from parameters and an
+// optional (0 for none) relinearization-key handle, returning its handle or 0
+// on error. Evaluator handles must not be shared across threads.
+func lattice_evaluator_new(paramsH, rlkH int64) int64 {
+	params, err := lookupParams(paramsH)
+	if err != nil {
+		return setError(err)
+	}
+	var evk rlwe.EvaluationKeySet
+	if rlkH != 0 {
+		obj, err := lookup(rlkH)
+		if err != nil {
+			return setError(err)
+		}
+		rlk, ok := obj.(*rlwe.RelinearizationKey)
+		if !ok {
+			return setError(fmt.Errorf("handle %d is a %T, not a relinearization key", rlkH, obj))
+		}
+		evk = rlwe.NewMemEvaluationKeySet(rlk)
+	}
+	return register(&evaluatorContext{params: params, eval: hefloat.NewEvaluator(params, evk)})
+}
+
+func lookupEvaluator(evalH int64) (*evaluatorContext, error) {
+	obj, err := lookup(evalH)
+	if err != nil {
+		return nil, err
+	}
+	ctx, ok := obj.(*evaluatorContext)
+	if !ok {
+		return nil, fmt.Errorf("handle %d is a %T, not an evaluator", evalH, obj)
+	}
+	return ctx, nil
+}
+
+func lookupCiphertext(h int64) (*rlwe.Ciphertext, error) {
+	obj, err := lookup(h)
+	if err != nil {
+		return nil, err
+	}
+	ct, ok := obj.(*rlwe.Ciphertext)
+	if !ok {
+		return nil, fmt.Errorf("handle %d is a %T, not a ciphertext", h, obj)
+	}
+	return ct, nil
+}
+
+//export lattice_encrypt
+// lattice_encrypt encodes the n doubles in values at the maximum level and
+// encrypts them under the given encryption key (secret or public), returning
+// the ciphertext handle or 0 on error.
+func lattice_encrypt(paramsH, keyH int64, values *C.double, n C.size_t) int64 {
+	params, err := lookupParams(paramsH)
+	if err != nil {
+		return setError(err)
+	}
+	obj, err := lookup(keyH)
+	if err != nil {
+		return setError(err)
+	}
+	key, ok := obj.(rlwe.EncryptionKey)
+	if !ok {
+		return setError(fmt.Errorf("handle %d is a %T, not an encryption key", keyH, obj))
+	}
+
+	slots := unsafe.Slice((*float64)(values), int(n))
+
+	pt := hefloat.NewPlaintext(params, params.MaxLevel())
+	if err := hefloat.NewEncoder(params).Encode(slots, pt); err != nil {
+		return setError(err)
+	}
+	ct, err := hefloat.NewEncryptor(params, key).EncryptNew(pt)
+	if err != nil {
+		return setError(err)
+	}
+	return register(ct)
+}
+
+//export lattice_decrypt
+// lattice_decrypt decrypts the given ciphertext and decodes n doubles into
+// values. It returns 0 on success and -1 on error.
+func lattice_decrypt(paramsH, skH, ctH int64, values *C.double, n C.size_t) C.int {
+	params, err := lookupParams(paramsH)
+	if err != nil {
+		setError(err)
+		return -1
+	}
+	obj, err := lookup(skH)
+	if err != nil {
+		setError(err)
+		return -1
+	}
+	sk, ok := obj.(*rlwe.SecretKey)
+	if !ok {
+		setError(fmt.Errorf("handle %d is a %T, not a secret key", skH, obj))
+		return -1
+	}
+	ct, err := lookupCiphertext(ctH)
+	if err != nil {
+		setError(err)
+		return -1
+	}
+
+	slots := unsafe.Slice((*float64)(values), int(n))
+
+	pt := hefloat.NewDecryptor(params, sk).DecryptNew(ct)
+	if err := hefloat.NewEncoder(params).Decode(pt, slots); err != nil {
+		setError(err)
+		return -1
+	}
+	return 0
+}
+
+//export lattice_add
+// lattice_add homomorphically adds the two ciphertexts and returns the handle
+// of the result, or 0 on error.
+func lattice_add(evalH, ct0H, ct1H int64) int64 {
+	ctx, err := lookupEvaluator(evalH)
+	if err != nil {
+		return setError(err)
+	}
+	ct0, err := lookupCiphertext(ct0H)
+	if err != nil {
+		return setError(err)
+	}
+	ct1, err := lookupCiphertext(ct1H)
+	if err != nil {
+		return setError(err)
+	}
+	ct, err := ctx.eval.AddNew(ct0, ct1)
+	if err != nil {
+		return setError(err)
+	}
+	return register(ct)
+}
+
+//export lattice_mul_relin
+// lattice_mul_relin homomorphically multiplies the two ciphertexts,
+// relinearizes and rescales the result, and returns its handle, or 0 on error.
+// The evaluator must have been instantiated with a relinearization key.
+func lattice_mul_relin(evalH, ct0H, ct1H int64) int64 {
+	ctx, err := lookupEvaluator(evalH)
+	if err != nil {
+		return setError(err)
+	}
+	ct0, err := lookupCiphertext(ct0H)
+	if err != nil {
+		return setError(err)
+	}
+	ct1, err := lookupCiphertext(ct1H)
+	if err != nil {
+		return setError(err)
+	}
+	ct, err := ctx.eval.MulRelinNew(ct0, ct1)
+	if err != nil {
+		return setError(err)
+	}
+	if err := ctx.eval.Rescale(ct, ct); err != nil {
+		return setError(err)
+	}
+	return register(ct)
+}
+
+//export lattice_marshal
+// lattice_marshal serializes the object behind the given handle (key or
+// ciphertext) into a buffer that must be released with lattice_free_buffer.
+// It returns 0 on success and -1 on error.
+func lattice_marshal(h int64, buf **C.char, size *C.size_t) C.int {
+	obj, err := lookup(h)
+	if err != nil {
+		setError(err)
+		return -1
+	}
+	m, ok := obj.(interface{ MarshalBinary() ([]byte, error) })
+	if !ok {
+		setError(fmt.Errorf("handle %d is a %T, which is not serializable", h, obj))
+		return -1
+	}
+	p, err := m.MarshalBinary()
+	if err != nil {
+		setError(err)
+		return -1
+	}
+	*buf = (*C.char)(C.CBytes(p))
+	*size = C.size_t(len(p))
+	return 0
+}
+
+//export lattice_unmarshal_ciphertext
+// lattice_unmarshal_ciphertext deserializes a ciphertext produced by
+// lattice_marshal and returns its handle, or 0 on error.
+func lattice_unmarshal_ciphertext(buf *C.char, size C.size_t) int64 {
+	ct := new(rlwe.Ciphertext)
+	if err := ct.UnmarshalBinary(goBytes(buf, size)); err != nil {
+		return setError(err)
+	}
+	return register(ct)
+}
+
+//export lattice_unmarshal_secret_key
+// lattice_unmarshal_secret_key deserializes a secret key produced by
+// lattice_marshal and returns its handle, or 0 on error.
+func lattice_unmarshal_secret_key(buf *C.char, size C.size_t) int64 {
+	sk := new(rlwe.SecretKey)
+	if err := sk.UnmarshalBinary(goBytes(buf, size)); err != nil {
+		return setError(err)
+	}
+	return register(sk)
+}
+
+//export lattice_unmarshal_public_key
+// lattice_unmarshal_public_key deserializes a public key produced by
+// lattice_marshal and returns its handle, or 0 on error.
+func lattice_unmarshal_public_key(buf *C.char, size C.size_t) int64 {
+	pk := new(rlwe.PublicKey)
+	if err := pk.UnmarshalBinary(goBytes(buf, size)); err != nil {
+		return setError(err)
+	}
+	return register(pk)
+}
+
+//export lattice_unmarshal_relinearization_key
+// lattice_unmarshal_relinearization_key deserializes a relinearization key
+// produced by lattice_marshal and returns its handle, or 0 on error.
+func lattice_unmarshal_relinearization_key(buf *C.char, size C.size_t) int64 {
+	rlk := new(rlwe.RelinearizationKey)
+	if err := rlk.UnmarshalBinary(goBytes(buf, size)); err != nil {
+		return setError(err)
+	}
+	return register(rlk)
+}
+
+// main is required by -buildmode=c-shared; it is never executed.
+func main() {}
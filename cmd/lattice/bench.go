@@ -0,0 +1,98 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/he"
+)
+
+// runBench times the main operations (key generation, encoding, encryption,
+// decryption, addition, multiplication with relinearization and slot
+// rotation) of the parameter set of the given parameter file.
+func runBench(args []string, out io.Writer) error {
+
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	paramsPath := fs.String("params", "", "parameter file written by the params command (required)")
+	n := fs.Int("n", 10, "number of iterations per operation")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *paramsPath == "" {
+		return fmt.Errorf("bench: missing -params")
+	}
+
+	if *n < 1 {
+		return fmt.Errorf("bench: -n must be at least 1")
+	}
+
+	sch, err := loadScheme(*paramsPath)
+	if err != nil {
+		return fmt.Errorf("bench: %w", err)
+	}
+
+	printSummary(out, sch)
+
+	params := sch.Params()
+	kgen := rlwe.NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+	evk := rlwe.NewMemEvaluationKeySet(kgen.GenRelinearizationKeyNew(sk), kgen.GenGaloisKeysNew([]uint64{sch.GaloisElement(1)}, sk)...)
+
+	ecd := sch.NewEncoder()
+	enc := rlwe.NewEncryptor(params, sk)
+	dec := rlwe.NewDecryptor(params, sk)
+	eval := sch.NewEvaluator(evk)
+
+	values := sch.NewValues(sch.MaxSlots())
+	pt := sch.NewPlaintext()
+	if err := ecd.EncodeSlots(values, pt); err != nil {
+		return fmt.Errorf("bench: %w", err)
+	}
+
+	ct, err := enc.EncryptNew(pt)
+	if err != nil {
+		return fmt.Errorf("bench: %w", err)
+	}
+
+	ctOut := ct.CopyNew()
+
+	benchmarks := []struct {
+		name string
+		f    func() error
+	}{
+		{"KeyGen/SecretKey", func() error { kgen.GenSecretKeyNew(); return nil }},
+		{"KeyGen/PublicKey", func() error { kgen.GenPublicKeyNew(sk); return nil }},
+		{"KeyGen/RelinearizationKey", func() error { kgen.GenRelinearizationKeyNew(sk); return nil }},
+		{"Encode", func() error { return ecd.EncodeSlots(values, pt) }},
+		{"Encrypt", func() error { return enc.Encrypt(pt, ct) }},
+		{"Decrypt", func() error { dec.Decrypt(ct, pt); return nil }},
+		{"Add", func() error { return eval.Add(ct, ct, ctOut) }},
+		{"MulRelin", func() error { return eval.MulRelin(ct, ct, ctOut) }},
+	}
+
+	if rot, ok := eval.(he.SlotRotator); ok {
+		benchmarks = append(benchmarks, struct {
+			name string
+			f    func() error
+		}{"Rotate", func() error { return rot.RotateSlots(ct, 1, ctOut) }})
+	}
+
+	fmt.Fprintf(out, "%-28s %12s (%d iterations)\n", "operation", "avg", *n)
+	for _, b := range benchmarks {
+
+		start := time.Now()
+		for i := 0; i < *n; i++ {
+			if err := b.f(); err != nil {
+				return fmt.Errorf("bench: %s: %w", b.name, err)
+			}
+		}
+
+		fmt.Fprintf(out, "%-28s %12s\n", b.name, (time.Since(start) / time.Duration(*n)).Round(time.Microsecond))
+	}
+
+	return nil
+}
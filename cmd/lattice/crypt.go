@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+)
+
+// loadSecretKey reads a secret key written by the keygen command.
+func loadSecretKey(path string) (sk *rlwe.SecretKey, err error) {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sk = new(rlwe.SecretKey)
+	if err = sk.UnmarshalBinary(data); err != nil {
+		return nil, fmt.Errorf("cannot parse secret key %s: %w", path, err)
+	}
+
+	return sk, nil
+}
+
+// runEncrypt encodes and encrypts a file of values, one per line, under
+// either the secret key (-sk) or the public key of a key archive (-keys).
+func runEncrypt(args []string, out io.Writer) error {
+
+	fs := flag.NewFlagSet("encrypt", flag.ContinueOnError)
+	paramsPath := fs.String("params", "", "parameter file written by the params command (required)")
+	skPath := fs.String("sk", "", "secret key file (this or -keys is required)")
+	keysPath := fs.String("keys", "", "key archive file, encrypts under its public key")
+	inPath := fs.String("in", "", "input values file, one value per line (required)")
+	outPath := fs.String("out", "", "output ciphertext file (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *paramsPath == "" || *inPath == "" || *outPath == "" {
+		return fmt.Errorf("encrypt: missing -params, -in or -out")
+	}
+
+	if (*skPath == "") == (*keysPath == "") {
+		return fmt.Errorf("encrypt: exactly one of -sk and -keys is required")
+	}
+
+	sch, err := loadScheme(*paramsPath)
+	if err != nil {
+		return fmt.Errorf("encrypt: %w", err)
+	}
+
+	var key rlwe.EncryptionKey
+	if *skPath != "" {
+		if key, err = loadSecretKey(*skPath); err != nil {
+			return fmt.Errorf("encrypt: %w", err)
+		}
+	} else {
+
+		data, err := os.ReadFile(*keysPath)
+		if err != nil {
+			return fmt.Errorf("encrypt: %w", err)
+		}
+
+		kar, err := rlwe.OpenKeyArchive(bytes.NewReader(data), sch.Params())
+		if err != nil {
+			return fmt.Errorf("encrypt: %w", err)
+		}
+
+		if key, err = kar.PublicKey(); err != nil {
+			return fmt.Errorf("encrypt: %w", err)
+		}
+	}
+
+	raw, err := os.ReadFile(*inPath)
+	if err != nil {
+		return fmt.Errorf("encrypt: %w", err)
+	}
+
+	fields := strings.Fields(string(raw))
+	if len(fields) == 0 {
+		return fmt.Errorf("encrypt: %s contains no values", *inPath)
+	}
+
+	if len(fields) > sch.MaxSlots() {
+		return fmt.Errorf("encrypt: %d values exceed the %d plaintext slots", len(fields), sch.MaxSlots())
+	}
+
+	values, err := sch.ParseValues(fields)
+	if err != nil {
+		return fmt.Errorf("encrypt: %w", err)
+	}
+
+	pt := sch.NewPlaintext()
+	if err := sch.NewEncoder().EncodeSlots(values, pt); err != nil {
+		return fmt.Errorf("encrypt: %w", err)
+	}
+
+	ct, err := rlwe.NewEncryptor(sch.Params(), key).EncryptNew(pt)
+	if err != nil {
+		return fmt.Errorf("encrypt: %w", err)
+	}
+
+	data, err := ct.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("encrypt: %w", err)
+	}
+
+	if err := os.WriteFile(*outPath, data, 0600); err != nil {
+		return fmt.Errorf("encrypt: %w", err)
+	}
+
+	fmt.Fprintf(out, "encrypted %d values to %s (%d bytes)\n", len(fields), *outPath, len(data))
+	return nil
+}
+
+// runDecrypt decrypts a ciphertext file and prints its values, one per line,
+// on the output file or on standard output.
+func runDecrypt(args []string, out io.Writer) error {
+
+	fs := flag.NewFlagSet("decrypt", flag.ContinueOnError)
+	paramsPath := fs.String("params", "", "parameter file written by the params command (required)")
+	skPath := fs.String("sk", "", "secret key file (required)")
+	inPath := fs.String("in", "", "input ciphertext file (required)")
+	outPath := fs.String("out", "", "output values file (default: standard output)")
+	n := fs.Int("n", 0, "number of values to print (default: all slots)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *paramsPath == "" || *skPath == "" || *inPath == "" {
+		return fmt.Errorf("decrypt: missing -params, -sk or -in")
+	}
+
+	sch, err := loadScheme(*paramsPath)
+	if err != nil {
+		return fmt.Errorf("decrypt: %w", err)
+	}
+
+	sk, err := loadSecretKey(*skPath)
+	if err != nil {
+		return fmt.Errorf("decrypt: %w", err)
+	}
+
+	data, err := os.ReadFile(*inPath)
+	if err != nil {
+		return fmt.Errorf("decrypt: %w", err)
+	}
+
+	ct := new(rlwe.Ciphertext)
+	if err := ct.UnmarshalBinary(data); err != nil {
+		return fmt.Errorf("decrypt: cannot parse ciphertext %s: %w", *inPath, err)
+	}
+
+	values := sch.NewValues(sch.MaxSlots())
+	if err := sch.NewEncoder().DecodeSlots(rlwe.NewDecryptor(sch.Params(), sk).DecryptNew(ct), values); err != nil {
+		return fmt.Errorf("decrypt: %w", err)
+	}
+
+	if *n <= 0 || *n > sch.MaxSlots() {
+		*n = sch.MaxSlots()
+	}
+
+	w := out
+	if *outPath != "" {
+		f, err := os.Create(*outPath)
+		if err != nil {
+			return fmt.Errorf("decrypt: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := sch.FormatValues(w, values, *n); err != nil {
+		return fmt.Errorf("decrypt: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+)
+
+// runKeygen generates a secret key and a key archive (public key,
+// relinearization key and Galois keys for the requested rotations) for the
+// parameter set of the given parameter file.
+func runKeygen(args []string, out io.Writer) error {
+
+	fs := flag.NewFlagSet("keygen", flag.ContinueOnError)
+	paramsPath := fs.String("params", "", "parameter file written by the params command (required)")
+	rotations := fs.String("rotations", "", "comma-separated slot rotations to generate Galois keys for, e.g. 1,2,-4")
+	skPath := fs.String("sk", "", "output secret key file (required)")
+	outPath := fs.String("out", "", "output key archive file (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *paramsPath == "" || *skPath == "" || *outPath == "" {
+		return fmt.Errorf("keygen: missing -params, -sk or -out")
+	}
+
+	sch, err := loadScheme(*paramsPath)
+	if err != nil {
+		return fmt.Errorf("keygen: %w", err)
+	}
+
+	ks, err := parseInts(*rotations)
+	if err != nil {
+		return fmt.Errorf("keygen: invalid -rotations: %w", err)
+	}
+
+	galEls := make([]uint64, len(ks))
+	for i, k := range ks {
+		galEls[i] = sch.GaloisElement(k)
+	}
+
+	params := sch.Params()
+	kgen := rlwe.NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+
+	ka := rlwe.NewKeyArchive()
+	ka.AddPublicKey(params, kgen.GenPublicKeyNew(sk))
+	ka.AddRelinearizationKey(params, kgen.GenRelinearizationKeyNew(sk))
+	ka.AddGaloisKeys(params, kgen.GenGaloisKeysNew(galEls, sk)...)
+
+	skData, err := sk.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("keygen: %w", err)
+	}
+
+	if err := os.WriteFile(*skPath, skData, 0600); err != nil {
+		return fmt.Errorf("keygen: %w", err)
+	}
+
+	kaData, err := ka.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("keygen: %w", err)
+	}
+
+	if err := os.WriteFile(*outPath, kaData, 0600); err != nil {
+		return fmt.Errorf("keygen: %w", err)
+	}
+
+	fmt.Fprintf(out, "wrote secret key to %s (%d bytes)\n", *skPath, len(skData))
+	fmt.Fprintf(out, "wrote key archive to %s (%d bytes, %d Galois keys)\n", *outPath, len(kaData), len(galEls))
+	return nil
+}
@@ -0,0 +1,70 @@
+// The lattice command is a toolkit for operating services built on the
+// library without writing Go: it generates parameter sets and key material,
+// encrypts and decrypts files of vectors, inspects serialized objects and
+// benchmarks parameter sets.
+//
+// Usage:
+//
+//	lattice params  -scheme float -logn 14 -logq 55,45,45 -logp 61 -out params.json
+//	lattice keygen  -params params.json -rotations 1,2 -sk sk.bin -out keys.bin
+//	lattice encrypt -params params.json -sk sk.bin -in values.txt -out ct.bin
+//	lattice decrypt -params params.json -sk sk.bin -in ct.bin -n 16
+//	lattice inspect params.json keys.bin ct.bin
+//	lattice bench   -params params.json -n 10
+//
+// Run lattice <command> -h for the flags of each command.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+const usage = `Usage: lattice <command> [flags]
+
+Commands:
+  params   generate a parameter set and write it to a JSON file
+  keygen   generate a secret key and a key archive for a parameter set
+  encrypt  encode and encrypt a file of values (one per line)
+  decrypt  decrypt a ciphertext and print its values (one per line)
+  inspect  identify and describe serialized objects
+  bench    benchmark the main operations of a parameter set
+
+Run lattice <command> -h for the flags of each command.`
+
+// run dispatches to the subcommand named by args[0], writing regular output
+// on out. It is the testable core of main.
+func run(args []string, out io.Writer) error {
+
+	if len(args) == 0 {
+		return fmt.Errorf("missing command\n%s", usage)
+	}
+
+	switch cmd := args[0]; cmd {
+	case "params":
+		return runParams(args[1:], out)
+	case "keygen":
+		return runKeygen(args[1:], out)
+	case "encrypt":
+		return runEncrypt(args[1:], out)
+	case "decrypt":
+		return runDecrypt(args[1:], out)
+	case "inspect":
+		return runInspect(args[1:], out)
+	case "bench":
+		return runBench(args[1:], out)
+	case "help", "-h", "--help":
+		fmt.Fprintln(out, usage)
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q\n%s", cmd, usage)
+	}
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "lattice:", err)
+		os.Exit(1)
+	}
+}
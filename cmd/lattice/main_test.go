@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// runToString runs a subcommand capturing its output.
+func runToString(t *testing.T, args ...string) string {
+	out := new(bytes.Buffer)
+	require.NoError(t, run(args, out))
+	return out.String()
+}
+
+func TestPipelineFloat(t *testing.T) {
+
+	dir := t.TempDir()
+	paramsPath := filepath.Join(dir, "params.json")
+	skPath := filepath.Join(dir, "sk.bin")
+	keysPath := filepath.Join(dir, "keys.bin")
+	ctPath := filepath.Join(dir, "ct.bin")
+	inPath := filepath.Join(dir, "values.txt")
+	outPath := filepath.Join(dir, "decrypted.txt")
+
+	// <<<<!Insecure parameters!>>>>
+	summary := runToString(t, "params", "-scheme", "float", "-logn", "10", "-logq", "55,45,45", "-logp", "61", "-logscale", "45", "-out", paramsPath)
+	require.Contains(t, summary, "scheme:   float")
+	require.Contains(t, summary, "logN:     10")
+
+	runToString(t, "keygen", "-params", paramsPath, "-rotations", "1,2", "-sk", skPath, "-out", keysPath)
+
+	values := []float64{1.25, -2.5, 3.75, 4}
+	lines := make([]string, len(values))
+	for i, v := range values {
+		lines[i] = strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	require.NoError(t, os.WriteFile(inPath, []byte(strings.Join(lines, "\n")), 0600))
+
+	// Encrypts under the public key of the archive and decrypts with the
+	// secret key.
+	runToString(t, "encrypt", "-params", paramsPath, "-keys", keysPath, "-in", inPath, "-out", ctPath)
+	runToString(t, "decrypt", "-params", paramsPath, "-sk", skPath, "-in", ctPath, "-out", outPath, "-n", strconv.Itoa(len(values)))
+
+	decrypted, err := os.ReadFile(outPath)
+	require.NoError(t, err)
+
+	fields := strings.Fields(string(decrypted))
+	require.Len(t, fields, len(values))
+	for i, field := range fields {
+		v, err := strconv.ParseFloat(field, 64)
+		require.NoError(t, err)
+		require.InDelta(t, values[i], v, 1e-3)
+	}
+}
+
+func TestPipelineInt(t *testing.T) {
+
+	dir := t.TempDir()
+	paramsPath := filepath.Join(dir, "params.json")
+	skPath := filepath.Join(dir, "sk.bin")
+	keysPath := filepath.Join(dir, "keys.bin")
+	ctPath := filepath.Join(dir, "ct.bin")
+	inPath := filepath.Join(dir, "values.txt")
+
+	// <<<<!Insecure parameters!>>>>
+	summary := runToString(t, "params", "-scheme", "int", "-logn", "10", "-logq", "55,45", "-logp", "61", "-t", "65537", "-out", paramsPath)
+	require.Contains(t, summary, "scheme:   int")
+
+	runToString(t, "keygen", "-params", paramsPath, "-rotations", "1", "-sk", skPath, "-out", keysPath)
+
+	require.NoError(t, os.WriteFile(inPath, []byte("1 2 3 -4 5"), 0600))
+
+	runToString(t, "encrypt", "-params", paramsPath, "-sk", skPath, "-in", inPath, "-out", ctPath)
+	decrypted := runToString(t, "decrypt", "-params", paramsPath, "-sk", skPath, "-in", ctPath, "-n", "5")
+
+	require.Equal(t, []string{"1", "2", "3", "-4", "5"}, strings.Fields(decrypted))
+}
+
+func TestInspect(t *testing.T) {
+
+	dir := t.TempDir()
+	paramsPath := filepath.Join(dir, "params.json")
+	skPath := filepath.Join(dir, "sk.bin")
+	keysPath := filepath.Join(dir, "keys.bin")
+	ctPath := filepath.Join(dir, "ct.bin")
+	inPath := filepath.Join(dir, "values.txt")
+
+	// <<<<!Insecure parameters!>>>>
+	runToString(t, "params", "-scheme", "float", "-logn", "10", "-logq", "55,45", "-logp", "61", "-out", paramsPath)
+	runToString(t, "keygen", "-params", paramsPath, "-rotations", "1", "-sk", skPath, "-out", keysPath)
+	require.NoError(t, os.WriteFile(inPath, []byte("1 2 3"), 0600))
+	runToString(t, "encrypt", "-params", paramsPath, "-sk", skPath, "-in", inPath, "-out", ctPath)
+
+	report := runToString(t, "inspect", paramsPath, skPath, keysPath, ctPath)
+	require.Contains(t, report, "float parameters (logN 10")
+	require.Contains(t, report, "secret key (levelQ 1, levelP 0)")
+	require.Contains(t, report, "key archive (3 entries)")
+	require.Contains(t, report, "public key (id 0")
+	require.Contains(t, report, "relinearization key (id 0")
+	require.Contains(t, report, "Galois key (id")
+	require.Contains(t, report, "ciphertext (degree 1, level 1")
+
+	// Unrecognized content is reported, not an error.
+	junkPath := filepath.Join(dir, "junk.bin")
+	require.NoError(t, os.WriteFile(junkPath, []byte("not a lattice object"), 0600))
+	require.Contains(t, runToString(t, "inspect", junkPath), "unrecognized object")
+}
+
+func TestBench(t *testing.T) {
+
+	if testing.Short() {
+		t.Skip("skipped in -short mode")
+	}
+
+	dir := t.TempDir()
+	paramsPath := filepath.Join(dir, "params.json")
+
+	// <<<<!Insecure parameters!>>>>
+	runToString(t, "params", "-scheme", "float", "-logn", "10", "-logq", "55,45", "-logp", "61", "-out", paramsPath)
+
+	report := runToString(t, "bench", "-params", paramsPath, "-n", "1")
+	for _, op := range []string{"KeyGen/SecretKey", "Encrypt", "Decrypt", "MulRelin", "Rotate"} {
+		require.Contains(t, report, op)
+	}
+}
+
+func TestBadInvocations(t *testing.T) {
+	out := new(bytes.Buffer)
+	require.Error(t, run(nil, out))
+	require.Error(t, run([]string{"frobnicate"}, out))
+	require.Error(t, run([]string{"params", "-scheme", "float"}, out))
+	require.Error(t, run([]string{"keygen"}, out))
+	require.NoError(t, run([]string{"help"}, out))
+	require.Contains(t, out.String(), "Usage: lattice")
+}
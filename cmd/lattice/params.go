@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/luxdefi/lattice/v5/he/hefloat"
+	"github.com/luxdefi/lattice/v5/he/heint"
+)
+
+// parseInts parses a comma-separated list of integers, e.g. "55,45,45".
+func parseInts(s string) (values []int, err error) {
+	if s == "" {
+		return nil, nil
+	}
+	for _, field := range strings.Split(s, ",") {
+		v, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse %q: %w", s, err)
+		}
+		values = append(values, v)
+	}
+	return
+}
+
+// runParams instantiates a parameter set from the flags, writes it to the
+// output file and prints a summary.
+func runParams(args []string, out io.Writer) error {
+
+	fs := flag.NewFlagSet("params", flag.ContinueOnError)
+	schemeName := fs.String("scheme", "float", "scheme: float (approximate reals) or int (modular integers)")
+	logN := fs.Int("logn", 14, "log2 of the ring degree")
+	logQ := fs.String("logq", "55,45,45,45,45", "comma-separated log2 of the ciphertext modulus primes")
+	logP := fs.String("logp", "61", "comma-separated log2 of the auxiliary modulus primes")
+	logScale := fs.Int("logscale", 45, "log2 of the default scale (float scheme only)")
+	t := fs.Uint64("t", 0x10001, "plaintext modulus (int scheme only)")
+	outPath := fs.String("out", "", "output parameter file (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *outPath == "" {
+		return fmt.Errorf("params: missing -out")
+	}
+
+	logQi, err := parseInts(*logQ)
+	if err != nil {
+		return fmt.Errorf("params: invalid -logq: %w", err)
+	}
+
+	logPi, err := parseInts(*logP)
+	if err != nil {
+		return fmt.Errorf("params: invalid -logp: %w", err)
+	}
+
+	var sch scheme
+	switch *schemeName {
+	case "float":
+		params, err := hefloat.NewParametersFromLiteral(hefloat.ParametersLiteral{
+			LogN:            *logN,
+			LogQ:            logQi,
+			LogP:            logPi,
+			LogDefaultScale: *logScale,
+		})
+		if err != nil {
+			return fmt.Errorf("params: %w", err)
+		}
+		sch = floatScheme{params: params}
+	case "int":
+		params, err := heint.NewParametersFromLiteral(heint.ParametersLiteral{
+			LogN:             *logN,
+			LogQ:             logQi,
+			LogP:             logPi,
+			PlaintextModulus: *t,
+		})
+		if err != nil {
+			return fmt.Errorf("params: %w", err)
+		}
+		sch = intScheme{params: params}
+	default:
+		return fmt.Errorf("params: unknown scheme %q (must be float or int)", *schemeName)
+	}
+
+	raw, err := sch.MarshalParameters()
+	if err != nil {
+		return fmt.Errorf("params: %w", err)
+	}
+
+	data, err := json.MarshalIndent(paramsFile{Scheme: sch.Name(), Parameters: raw}, "", "\t")
+	if err != nil {
+		return fmt.Errorf("params: %w", err)
+	}
+
+	if err := os.WriteFile(*outPath, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("params: %w", err)
+	}
+
+	printSummary(out, sch)
+	return nil
+}
+
+// printSummary prints the main figures of a parameter set.
+func printSummary(out io.Writer, sch scheme) {
+	params := sch.Params()
+	fmt.Fprintf(out, "scheme:   %s\n", sch.Name())
+	fmt.Fprintf(out, "logN:     %d\n", params.LogN())
+	fmt.Fprintf(out, "logQP:    %.1f\n", params.LogQP())
+	fmt.Fprintf(out, "levels:   %d\n", params.MaxLevel()+1)
+	fmt.Fprintf(out, "slots:    %d\n", sch.MaxSlots())
+}
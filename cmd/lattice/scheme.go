@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/he"
+	"github.com/luxdefi/lattice/v5/he/hefloat"
+	"github.com/luxdefi/lattice/v5/he/heint"
+)
+
+// paramsFile is the on-disk representation of a parameter set: the front-end
+// name and the JSON serialization of its parameters.
+type paramsFile struct {
+	Scheme     string          `json:"scheme"`
+	Parameters json.RawMessage `json:"parameters"`
+}
+
+// scheme bridges the hefloat and heint front-ends behind a common surface, so
+// that the subcommands are written once. The scheme agnostic operations go
+// through the interfaces of the he package; the remaining methods cover the
+// scheme-specific parts: allocating plaintexts, converting values to and from
+// their textual representation and serializing the parameters.
+type scheme interface {
+	Name() string
+	Params() *rlwe.Parameters
+	MaxSlots() int
+	GaloisElement(k int) uint64
+	NewPlaintext() *rlwe.Plaintext
+	NewEncoder() he.SlotEncoder
+	NewEvaluator(evk rlwe.EvaluationKeySet) he.Evaluator
+	NewValues(n int) interface{}
+	ParseValues(fields []string) (interface{}, error)
+	FormatValues(w io.Writer, values interface{}, n int) error
+	MarshalParameters() (json.RawMessage, error)
+}
+
+// floatScheme is the hefloat (approximate arithmetic over the reals) bridge.
+type floatScheme struct {
+	params hefloat.Parameters
+}
+
+func (s floatScheme) Name() string               { return "float" }
+func (s floatScheme) Params() *rlwe.Parameters   { return s.params.GetRLWEParameters() }
+func (s floatScheme) MaxSlots() int              { return s.params.MaxSlots() }
+func (s floatScheme) GaloisElement(k int) uint64 { return s.params.GaloisElement(k) }
+func (s floatScheme) NewPlaintext() *rlwe.Plaintext {
+	return hefloat.NewPlaintext(s.params, s.params.MaxLevel())
+}
+func (s floatScheme) NewEncoder() he.SlotEncoder { return hefloat.NewEncoder(s.params) }
+func (s floatScheme) NewEvaluator(evk rlwe.EvaluationKeySet) he.Evaluator {
+	return hefloat.NewEvaluator(s.params, evk)
+}
+func (s floatScheme) NewValues(n int) interface{} { return make([]float64, n) }
+
+func (s floatScheme) ParseValues(fields []string) (interface{}, error) {
+	values := make([]float64, len(fields))
+	for i, field := range fields {
+		v, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return nil, fmt.Errorf("value %d: %w", i, err)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+func (s floatScheme) FormatValues(w io.Writer, values interface{}, n int) error {
+	for _, v := range values.([]float64)[:n] {
+		if _, err := fmt.Fprintln(w, strconv.FormatFloat(v, 'g', -1, 64)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s floatScheme) MarshalParameters() (json.RawMessage, error) {
+	return s.params.MarshalJSON()
+}
+
+// intScheme is the heint (exact modular arithmetic over the integers) bridge.
+type intScheme struct {
+	params heint.Parameters
+}
+
+func (s intScheme) Name() string               { return "int" }
+func (s intScheme) Params() *rlwe.Parameters   { return s.params.GetRLWEParameters() }
+func (s intScheme) MaxSlots() int              { return s.params.MaxSlots() }
+func (s intScheme) GaloisElement(k int) uint64 { return s.params.GaloisElement(k) }
+func (s intScheme) NewPlaintext() *rlwe.Plaintext {
+	return heint.NewPlaintext(s.params, s.params.MaxLevel())
+}
+func (s intScheme) NewEncoder() he.SlotEncoder { return heint.NewEncoder(s.params) }
+func (s intScheme) NewEvaluator(evk rlwe.EvaluationKeySet) he.Evaluator {
+	return heint.NewEvaluator(s.params, evk)
+}
+func (s intScheme) NewValues(n int) interface{} { return make([]int64, n) }
+
+func (s intScheme) ParseValues(fields []string) (interface{}, error) {
+	values := make([]int64, len(fields))
+	for i, field := range fields {
+		v, err := strconv.ParseInt(field, 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("value %d: %w", i, err)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+func (s intScheme) FormatValues(w io.Writer, values interface{}, n int) error {
+	for _, v := range values.([]int64)[:n] {
+		if _, err := fmt.Fprintln(w, strconv.FormatInt(v, 10)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s intScheme) MarshalParameters() (json.RawMessage, error) {
+	return s.params.MarshalJSON()
+}
+
+// decodeScheme instantiates the scheme described by a paramsFile.
+func decodeScheme(file paramsFile) (scheme, error) {
+	switch file.Scheme {
+	case "float":
+		var params hefloat.Parameters
+		if err := params.UnmarshalJSON(file.Parameters); err != nil {
+			return nil, fmt.Errorf("cannot parse float parameters: %w", err)
+		}
+		return floatScheme{params: params}, nil
+	case "int":
+		var params heint.Parameters
+		if err := params.UnmarshalJSON(file.Parameters); err != nil {
+			return nil, fmt.Errorf("cannot parse int parameters: %w", err)
+		}
+		return intScheme{params: params}, nil
+	default:
+		return nil, fmt.Errorf("unknown scheme %q (must be float or int)", file.Scheme)
+	}
+}
+
+// loadScheme reads a parameter file written by the params subcommand.
+func loadScheme(path string) (scheme, error) {
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file paramsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("cannot parse parameter file %s: %w", path, err)
+	}
+
+	return decodeScheme(file)
+}
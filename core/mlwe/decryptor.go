@@ -0,0 +1,102 @@
+package mlwe
+
+import (
+	"fmt"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/ring"
+	"github.com/luxdefi/lattice/v5/utils"
+	"github.com/luxdefi/lattice/v5/utils/metrics"
+)
+
+// Decryptor is a structure used to decrypt MLWE ciphertexts. It stores
+// the secret key.
+type Decryptor struct {
+	params Parameters
+	ringQ  *ring.Ring
+	buff   ring.Poly
+	sk     *SecretKey
+}
+
+// NewDecryptor instantiates a new MLWE Decryptor.
+func NewDecryptor(params Parameters, sk *SecretKey) *Decryptor {
+
+	if sk.Rank() != params.Rank() {
+		panic(fmt.Errorf("cannot NewDecryptor: secret-key rank %d does not match parameters rank %d", sk.Rank(), params.Rank()))
+	}
+
+	if sk.Value[0].N() != params.N() {
+		panic(fmt.Errorf("cannot NewDecryptor: secret-key ring degree does not match parameters ring degree"))
+	}
+
+	return &Decryptor{
+		params: params,
+		ringQ:  params.RingQ(),
+		buff:   params.RingQ().NewPoly(),
+		sk:     sk,
+	}
+}
+
+// GetRLWEParameters returns the underlying rlwe.Parameters.
+func (d Decryptor) GetRLWEParameters() *rlwe.Parameters {
+	return d.params.GetRLWEParameters()
+}
+
+// DecryptNew decrypts the Ciphertext and returns the result in a new Plaintext.
+// Output pt MetaData will match the input ct MetaData.
+func (d Decryptor) DecryptNew(ct *rlwe.Ciphertext) (pt *rlwe.Plaintext) {
+	pt = rlwe.NewPlaintext(d.params, ct.Level())
+	d.Decrypt(ct, pt)
+	return
+}
+
+// Decrypt decrypts the Ciphertext, which must be of degree params.Rank(),
+// and writes the result in pt.
+// The level of the output Plaintext is min(ct.Level(), pt.Level())
+// Output pt MetaData will match the input ct MetaData.
+func (d Decryptor) Decrypt(ct *rlwe.Ciphertext, pt *rlwe.Plaintext) {
+
+	metrics.IncCounter(metrics.Decrypt, 1)
+
+	rank := d.params.Rank()
+
+	if ct.Degree() != rank {
+		panic(fmt.Errorf("cannot Decrypt: ciphertext degree %d does not match the module rank %d", ct.Degree(), rank))
+	}
+
+	level := utils.Min(ct.Level(), pt.Level())
+
+	ringQ := d.ringQ.AtLevel(level)
+
+	pt.Resize(0, level)
+
+	*pt.MetaData = *ct.MetaData
+
+	// pt = c0 + sum(c_i * s_i), evaluated in the NTT domain.
+	if ct.IsNTT {
+		pt.Value.CopyLvl(level, ct.Value[0])
+	} else {
+		ringQ.NTTLazy(ct.Value[0], pt.Value)
+	}
+
+	for i := 1; i <= rank; i++ {
+		if ct.IsNTT {
+			ringQ.MulCoeffsMontgomeryThenAdd(ct.Value[i], d.sk.Value[i-1], pt.Value)
+		} else {
+			ringQ.NTTLazy(ct.Value[i], d.buff)
+			ringQ.MulCoeffsMontgomeryThenAdd(d.buff, d.sk.Value[i-1], pt.Value)
+		}
+
+		if i&7 == 7 {
+			ringQ.Reduce(pt.Value, pt.Value)
+		}
+	}
+
+	if rank&7 != 7 {
+		ringQ.Reduce(pt.Value, pt.Value)
+	}
+
+	if !ct.IsNTT {
+		ringQ.INTT(pt.Value, pt.Value)
+	}
+}
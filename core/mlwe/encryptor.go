@@ -0,0 +1,164 @@
+package mlwe
+
+import (
+	"fmt"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/ring"
+	"github.com/luxdefi/lattice/v5/utils"
+	"github.com/luxdefi/lattice/v5/utils/metrics"
+	"github.com/luxdefi/lattice/v5/utils/sampling"
+)
+
+// Encryptor is a type for encrypting MLWE ciphertexts. Only secret-key
+// encryption is supported: a Module-LWE public key is a matrix of uniform
+// polynomials and is out of the scope of this package.
+type Encryptor struct {
+	params         Parameters
+	sk             *SecretKey
+	xeSampler      ring.Sampler
+	uniformSampler *ring.UniformSampler
+	buffQ          ring.Poly
+}
+
+// NewEncryptor creates a new Encryptor from a secret key.
+func NewEncryptor(params Parameters, sk *SecretKey) *Encryptor {
+
+	if sk.Rank() != params.Rank() {
+		panic(fmt.Errorf("cannot NewEncryptor: secret-key rank %d does not match parameters rank %d", sk.Rank(), params.Rank()))
+	}
+
+	if sk.Value[0].N() != params.N() {
+		panic(fmt.Errorf("cannot NewEncryptor: secret-key ring degree does not match parameters ring degree"))
+	}
+
+	prng, err := sampling.NewPRNG()
+	if err != nil {
+		// Sanity check, this error should not happen.
+		panic(err)
+	}
+
+	xeSampler, err := ring.NewSampler(prng, params.RingQ(), params.Xe(), false)
+	if err != nil {
+		// Sanity check, this error should not happen.
+		panic(fmt.Errorf("noise sampler: %w", err))
+	}
+
+	return &Encryptor{
+		params:         params,
+		sk:             sk,
+		xeSampler:      xeSampler,
+		uniformSampler: ring.NewUniformSampler(prng, params.RingQ()),
+		buffQ:          params.RingQ().NewPoly(),
+	}
+}
+
+// GetRLWEParameters returns the underlying rlwe.Parameters.
+func (enc Encryptor) GetRLWEParameters() *rlwe.Parameters {
+	return enc.params.GetRLWEParameters()
+}
+
+// Encrypt encrypts the input plaintext using the stored secret key and
+// writes the result on ct, which must be of degree params.Rank() (see
+// NewCiphertext). The output ciphertext MetaData will match the Plaintext
+// MetaData.
+//
+// The encryption procedure masks the plaintext by adding a fresh
+// encryption of zero sampled in Q.
+func (enc Encryptor) Encrypt(pt *rlwe.Plaintext, ct *rlwe.Ciphertext) (err error) {
+
+	metrics.IncCounter(metrics.Encrypt, 1)
+
+	*ct.MetaData = *pt.MetaData
+
+	level := utils.Min(pt.Level(), ct.Level())
+
+	ct.Resize(ct.Degree(), level)
+
+	if err = enc.EncryptZero(ct); err != nil {
+		return fmt.Errorf("cannot Encrypt: %w", err)
+	}
+
+	enc.addPtToCt(level, pt, ct)
+
+	return
+}
+
+// EncryptNew encrypts the input plaintext using the stored secret key and
+// returns a newly allocated Ciphertext of degree params.Rank() containing
+// the result.
+func (enc Encryptor) EncryptNew(pt *rlwe.Plaintext) (ct *rlwe.Ciphertext, err error) {
+	ct = NewCiphertext(enc.params, pt.Level())
+	return ct, enc.Encrypt(pt, ct)
+}
+
+// EncryptZero generates an encryption of zero under the stored secret key
+// and writes the result on ct, which must be of degree params.Rank().
+// The zero encryption is generated according to the given Ciphertext
+// MetaData.
+func (enc Encryptor) EncryptZero(ct *rlwe.Ciphertext) (err error) {
+
+	rank := enc.params.Rank()
+
+	if ct.Degree() != rank {
+		return fmt.Errorf("cannot EncryptZero: ciphertext degree %d does not match the module rank %d", ct.Degree(), rank)
+	}
+
+	level := ct.Level()
+
+	ringQ := enc.params.RingQ().AtLevel(level)
+
+	c0 := ct.Value[0]
+
+	// Samples the mask polynomials a_i in the NTT domain and accumulates
+	// c0 = NTT(sum(a_i * s_i)).
+	for i := 1; i <= rank; i++ {
+		ci := ct.Value[i]
+		enc.uniformSampler.AtLevel(level).Read(ci)
+		if i == 1 {
+			ringQ.MulCoeffsMontgomery(ci, enc.sk.Value[0], c0)
+		} else {
+			ringQ.MulCoeffsMontgomeryThenAdd(ci, enc.sk.Value[i-1], c0)
+		}
+	}
+
+	ringQ.Reduce(c0, c0)
+	ringQ.Neg(c0, c0) // c0 = NTT(-sum(a_i * s_i))
+
+	if ct.IsNTT {
+		enc.xeSampler.AtLevel(level).Read(enc.buffQ) // e
+		ringQ.NTT(enc.buffQ, enc.buffQ)              // NTT(e)
+		ringQ.Add(c0, enc.buffQ, c0)                 // c0 = NTT(-sum(a_i * s_i) + e)
+	} else {
+		ringQ.INTT(c0, c0) // c0 = -sum(a_i * s_i)
+		for i := 1; i <= rank; i++ {
+			ringQ.INTT(ct.Value[i], ct.Value[i])
+		}
+		enc.xeSampler.AtLevel(level).ReadAndAdd(c0) // c0 = -sum(a_i * s_i) + e
+	}
+
+	return
+}
+
+func (enc Encryptor) addPtToCt(level int, pt *rlwe.Plaintext, ct *rlwe.Ciphertext) {
+
+	ringQ := enc.params.RingQ().AtLevel(level)
+	var buff ring.Poly
+	if pt.IsNTT {
+		if ct.IsNTT {
+			buff = pt.Value
+		} else {
+			buff = enc.buffQ
+			ringQ.NTT(pt.Value, buff)
+		}
+	} else {
+		if ct.IsNTT {
+			buff = enc.buffQ
+			ringQ.INTT(pt.Value, buff)
+		} else {
+			buff = pt.Value
+		}
+	}
+
+	ringQ.Add(ct.Value[0], buff, ct.Value[0])
+}
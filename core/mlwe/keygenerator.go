@@ -0,0 +1,140 @@
+package mlwe
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/luxdefi/lattice/v5/ring"
+	"github.com/luxdefi/lattice/v5/utils/sampling"
+	"github.com/luxdefi/lattice/v5/utils/structs"
+)
+
+// SecretKey is a type for MLWE secret keys. The Value field stores the
+// vector of polynomials, each in NTT and Montgomery form. The vector
+// length is the module rank of the parameters.
+//
+// Unlike the rlwe.SecretKey, the key is sampled over the ring Q only, as
+// the auxiliary modulus P is used for key-switching operations that are
+// out of the scope of this package.
+type SecretKey struct {
+	Value structs.Vector[ring.Poly]
+}
+
+// NewSecretKey generates a new SecretKey with zero values.
+func NewSecretKey(params Parameters) *SecretKey {
+	sk := &SecretKey{Value: make(structs.Vector[ring.Poly], params.Rank())}
+	for i := range sk.Value {
+		sk.Value[i] = params.RingQ().NewPoly()
+	}
+	return sk
+}
+
+// Rank returns the module rank of the key, i.e. the number of polynomials
+// composing it.
+func (sk SecretKey) Rank() int {
+	return len(sk.Value)
+}
+
+// LevelQ returns the level of the modulus Q of the target.
+func (sk SecretKey) LevelQ() int {
+	return sk.Value[0].Level()
+}
+
+// Equal performs a deep equal. The comparison runs in constant time with
+// respect to the key coefficients, so that it is safe to use on secret
+// material (see ring.Poly.EqualConstantTime).
+func (sk SecretKey) Equal(other *SecretKey) bool {
+	v := len(sk.Value) == len(other.Value)
+	for i := range sk.Value {
+		if i < len(other.Value) {
+			v = sk.Value[i].EqualConstantTime(&other.Value[i]) && v
+		}
+	}
+	return v
+}
+
+// CopyNew creates a deep copy of the receiver secret key and returns it.
+func (sk SecretKey) CopyNew() *SecretKey {
+	return &SecretKey{Value: sk.Value.CopyNew()}
+}
+
+// BinarySize returns the serialized size of the object in bytes.
+func (sk SecretKey) BinarySize() (dataLen int) {
+	return sk.Value.BinarySize()
+}
+
+// WriteTo writes the object on an io.Writer. It implements the io.WriterTo
+// interface, and is faster on a buffer.Writer (see buffer.Writer).
+func (sk SecretKey) WriteTo(w io.Writer) (n int64, err error) {
+	return sk.Value.WriteTo(w)
+}
+
+// ReadFrom reads on the object from an io.Writer. It implements the
+// io.ReaderFrom interface, and is faster on a buffer.Reader (see
+// buffer.Reader).
+func (sk *SecretKey) ReadFrom(r io.Reader) (n int64, err error) {
+	return sk.Value.ReadFrom(r)
+}
+
+// MarshalBinary encodes the object into a binary form on a newly allocated slice of bytes.
+func (sk SecretKey) MarshalBinary() (p []byte, err error) {
+	return sk.Value.MarshalBinary()
+}
+
+// UnmarshalBinary decodes a slice of bytes generated by
+// MarshalBinary or WriteTo on the object.
+func (sk *SecretKey) UnmarshalBinary(p []byte) (err error) {
+	return sk.Value.UnmarshalBinary(p)
+}
+
+// KeyGenerator is a structure that stores the elements required to sample
+// new MLWE secret keys.
+type KeyGenerator struct {
+	params    Parameters
+	xsSampler ring.Sampler
+}
+
+// NewKeyGenerator creates a new KeyGenerator, from which secret keys can
+// be generated.
+func NewKeyGenerator(params Parameters) *KeyGenerator {
+
+	prng, err := sampling.NewPRNG()
+	if err != nil {
+		// Sanity check, this error should not happen.
+		panic(err)
+	}
+
+	xsSampler, err := ring.NewSampler(prng, params.RingQ(), params.Xs(), false)
+	if err != nil {
+		// Sanity check, this error should not happen.
+		panic(fmt.Errorf("secret sampler: %w", err))
+	}
+
+	return &KeyGenerator{
+		params:    params,
+		xsSampler: xsSampler,
+	}
+}
+
+// GenSecretKeyNew generates a new SecretKey.
+// Distribution is set according to `rlwe.Parameters.Xs()`.
+func (kgen KeyGenerator) GenSecretKeyNew() (sk *SecretKey) {
+	sk = NewSecretKey(kgen.params)
+	kgen.GenSecretKey(sk)
+	return
+}
+
+// GenSecretKey generates a SecretKey.
+// Distribution is set according to `rlwe.Parameters.Xs()`.
+func (kgen KeyGenerator) GenSecretKey(sk *SecretKey) {
+
+	levelQ := sk.LevelQ()
+
+	ringQ := kgen.params.RingQ().AtLevel(levelQ)
+
+	for i := range sk.Value {
+		kgen.xsSampler.AtLevel(levelQ).Read(sk.Value[i])
+		ringQ.NTT(sk.Value[i], sk.Value[i])
+		ringQ.MForm(sk.Value[i], sk.Value[i])
+	}
+}
@@ -0,0 +1,88 @@
+// Package mlwe implements encryption and decryption for the Module-LWE
+// (MLWE) problem, a generalization of Ring-LWE in which ciphertexts and
+// secret keys are vectors of polynomials over a smaller ring. The module
+// rank is configured in the parameters: rank-1 MLWE is plain Ring-LWE,
+// while a rank-k instance over a ring of degree N provides a lattice
+// dimension of k*N, enabling smaller rings with equivalent security for
+// shallow circuits and aligning with Kyber-style hardware accelerators
+// that operate on fixed small ring degrees.
+//
+// The package reuses the rlwe element types: a rank-k MLWE ciphertext
+// (c0, c1, ..., ck), decryptable as c0 + c1*s1 + ... + ck*sk, is stored
+// as a degree-k rlwe.Ciphertext, so the existing serialization, metadata
+// and component-wise ring operations (e.g. additions) apply unchanged.
+// Operations that are specific to the rank-1 setting,
+// such as relinearization, key-switching and public-key encryption, are
+// out of the scope of this package.
+package mlwe
+
+import (
+	"fmt"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+)
+
+// MaxRank is the maximum module rank supported by the parameters.
+const MaxRank = 16
+
+// ParametersLiteral is a literal representation of MLWE parameters. It
+// extends the rlwe.ParametersLiteral with the module rank. A Rank of 0
+// defaults to 1, in which case the parameters describe a plain Ring-LWE
+// instance.
+//
+// Users must also delegate the parameter validation to the constructor
+// NewParametersFromLiteral, and the security of a rank-k instance must be
+// assessed for the lattice dimension k*N rather than for the ring degree
+// alone.
+type ParametersLiteral struct {
+	rlwe.ParametersLiteral
+	Rank int
+}
+
+// Parameters represents a parameter set for the MLWE cryptosystem. Its
+// fields are private and immutable. See ParametersLiteral for user-specified
+// parameters.
+type Parameters struct {
+	rlwe.Parameters
+	rank int
+}
+
+// NewParametersFromLiteral instantiates a set of MLWE parameters from a
+// ParametersLiteral specification. It returns the empty parameters
+// Parameters{} and a non-nil error if the specified parameters are invalid.
+func NewParametersFromLiteral(paramDef ParametersLiteral) (params Parameters, err error) {
+
+	rank := paramDef.Rank
+	if rank == 0 {
+		rank = 1
+	}
+
+	if rank < 1 || rank > MaxRank {
+		return Parameters{}, fmt.Errorf("cannot NewParametersFromLiteral: invalid Rank: %d is not in [1, %d]", paramDef.Rank, MaxRank)
+	}
+
+	rlweParams, err := rlwe.NewParametersFromLiteral(paramDef.ParametersLiteral)
+	if err != nil {
+		return Parameters{}, fmt.Errorf("cannot NewParametersFromLiteral: %w", err)
+	}
+
+	return Parameters{Parameters: rlweParams, rank: rank}, nil
+}
+
+// Rank returns the module rank of the parameters, i.e. the number of
+// polynomials composing the secret key.
+func (p Parameters) Rank() int {
+	return p.rank
+}
+
+// Equal returns true if the target parameters are identical to the
+// received parameters, including the module rank.
+func (p Parameters) Equal(other *Parameters) bool {
+	return p.Parameters.Equal(&other.Parameters) && p.rank == other.rank
+}
+
+// NewCiphertext allocates a new zero MLWE ciphertext at the given level.
+// The ciphertext is a degree params.Rank() rlwe.Ciphertext.
+func NewCiphertext(params Parameters, level int) *rlwe.Ciphertext {
+	return rlwe.NewCiphertext(params, params.Rank(), level)
+}
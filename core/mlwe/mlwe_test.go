@@ -0,0 +1,130 @@
+package mlwe
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/utils/buffer"
+
+	"github.com/stretchr/testify/require"
+)
+
+// noiseNorm returns the log2 of the standard deviation, minimum and maximum
+// absolute norm of the decryption of ct under dec, before the decoding.
+func noiseNorm(params Parameters, dec *Decryptor, ct *rlwe.Ciphertext) (std, min, max float64) {
+
+	pt := dec.DecryptNew(ct)
+
+	ringQ := params.RingQ().AtLevel(ct.Level())
+
+	if pt.IsNTT {
+		ringQ.INTT(pt.Value, pt.Value)
+	}
+
+	coeffsBigint := make([]*big.Int, params.N())
+	for i := range coeffsBigint {
+		coeffsBigint[i] = new(big.Int)
+	}
+
+	ringQ.PolyToBigintCentered(pt.Value, 1, coeffsBigint)
+
+	return rlwe.NormStats(coeffsBigint)
+}
+
+func TestMLWE(t *testing.T) {
+
+	t.Run("Parameters", func(t *testing.T) {
+
+		// <<<<!Insecure parameters!>>>>
+		literal := rlwe.ParametersLiteral{
+			LogN: 10,
+			LogQ: []int{45, 35},
+		}
+
+		// Rank 0 defaults to 1.
+		params, err := NewParametersFromLiteral(ParametersLiteral{ParametersLiteral: literal})
+		require.NoError(t, err)
+		require.Equal(t, 1, params.Rank())
+
+		_, err = NewParametersFromLiteral(ParametersLiteral{ParametersLiteral: literal, Rank: -1})
+		require.Error(t, err)
+
+		_, err = NewParametersFromLiteral(ParametersLiteral{ParametersLiteral: literal, Rank: MaxRank + 1})
+		require.Error(t, err)
+
+		other, err := NewParametersFromLiteral(ParametersLiteral{ParametersLiteral: literal, Rank: 2})
+		require.NoError(t, err)
+		require.False(t, params.Equal(&other))
+	})
+
+	for _, rank := range []int{1, 2, 3} {
+		for _, NTTFlag := range []bool{true, false} {
+
+			// <<<<!Insecure parameters!>>>>
+			params, err := NewParametersFromLiteral(ParametersLiteral{
+				ParametersLiteral: rlwe.ParametersLiteral{
+					LogN:    10,
+					LogQ:    []int{45, 35},
+					NTTFlag: NTTFlag,
+				},
+				Rank: rank,
+			})
+			require.NoError(t, err)
+
+			kgen := NewKeyGenerator(params)
+			sk := kgen.GenSecretKeyNew()
+			enc := NewEncryptor(params, sk)
+			dec := NewDecryptor(params, sk)
+
+			t.Run(fmt.Sprintf("EncryptDecrypt/Rank=%d/NTT=%t", rank, NTTFlag), func(t *testing.T) {
+
+				pt := rlwe.NewPlaintext(params, params.MaxLevel())
+
+				ct, err := enc.EncryptNew(pt)
+				require.NoError(t, err)
+				require.Equal(t, rank, ct.Degree())
+
+				// The decryption of a fresh encryption of zero is the
+				// fresh noise, whose standard deviation grows with the
+				// square root of the rank.
+				std, _, _ := noiseNorm(params, dec, ct)
+				require.GreaterOrEqual(t, 10.0, std)
+			})
+
+			t.Run(fmt.Sprintf("Additivity/Rank=%d/NTT=%t", rank, NTTFlag), func(t *testing.T) {
+
+				pt := rlwe.NewPlaintext(params, params.MaxLevel())
+
+				ct0, err := enc.EncryptNew(pt)
+				require.NoError(t, err)
+
+				ct1, err := enc.EncryptNew(pt)
+				require.NoError(t, err)
+
+				// MLWE decryption is linear in the ciphertext, so the
+				// component-wise sum of two ciphertexts decrypts to the
+				// sum of the plaintexts.
+				ringQ := params.RingQ().AtLevel(ct0.Level())
+				for i := range ct0.Value {
+					ringQ.Add(ct0.Value[i], ct1.Value[i], ct0.Value[i])
+				}
+
+				std, _, _ := noiseNorm(params, dec, ct0)
+				require.GreaterOrEqual(t, 11.0, std)
+			})
+
+			t.Run(fmt.Sprintf("DegreeMismatch/Rank=%d/NTT=%t", rank, NTTFlag), func(t *testing.T) {
+
+				pt := rlwe.NewPlaintext(params, params.MaxLevel())
+				ct := rlwe.NewCiphertext(params, rank+1, params.MaxLevel())
+				require.Error(t, enc.Encrypt(pt, ct))
+			})
+
+			t.Run(fmt.Sprintf("SecretKeySerialization/Rank=%d/NTT=%t", rank, NTTFlag), func(t *testing.T) {
+				buffer.RequireSerializerCorrect(t, sk)
+			})
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package rgsw
+
+import (
+	"fmt"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/ring/ringqp"
+)
+
+// ExternalProductAccumulator is a buffer accumulating external products in the
+// extended basis QP, deferring the division by P (ModDown) to a single call to
+// Evaluator.FinalizeExternalProduct. In gadget-heavy circuits summing many
+// external products (e.g. PIR or blind rotation), this replaces one ModDown
+// per external product by a single ModDown per sum.
+type ExternalProductAccumulator struct {
+	// Value are the two components of the accumulated sum in the basis QP.
+	Value [2]ringqp.Poly
+
+	levelQ, levelP int
+}
+
+// NewExternalProductAccumulator allocates a zeroed ExternalProductAccumulator
+// at the given levels, which must match the levels of the RGSW operands that
+// will be accumulated. Returns an error if levelP < 0, since lazy
+// accumulation requires an extended basis P.
+func NewExternalProductAccumulator(params rlwe.ParameterProvider, levelQ, levelP int) (acc *ExternalProductAccumulator, err error) {
+
+	if levelP < 0 {
+		return nil, fmt.Errorf("cannot NewExternalProductAccumulator: accumulation requires an extended basis P but levelP is %d", levelP)
+	}
+
+	ringQP := params.GetRLWEParameters().RingQP().AtLevel(levelQ, levelP)
+
+	return &ExternalProductAccumulator{
+		Value:  [2]ringqp.Poly{ringQP.NewPoly(), ringQP.NewPoly()},
+		levelQ: levelQ,
+		levelP: levelP,
+	}, nil
+}
+
+// Reset zeroes the accumulator, enabling its reuse for a new sum.
+func (acc *ExternalProductAccumulator) Reset() {
+	for i := range acc.Value {
+		acc.Value[i].Q.Zero()
+		acc.Value[i].P.Zero()
+	}
+}
+
+// ExternalProductLazy computes the external product op0 x op1 in the extended
+// basis QP and adds it on acc, without dividing by P. The accumulated sum is
+// mapped back to a ciphertext in the basis Q with
+// Evaluator.FinalizeExternalProduct.
+//
+// The method returns an error if the levels of op1 do not match the levels of
+// acc.
+func (eval Evaluator) ExternalProductLazy(op0 *rlwe.Ciphertext, op1 *Ciphertext, acc *ExternalProductAccumulator) (err error) {
+
+	levelQ, levelP := op1.LevelQ(), op1.LevelP()
+
+	if levelQ != acc.levelQ || levelP != acc.levelP {
+		return fmt.Errorf("cannot ExternalProductLazy: op1 levels (%d, %d) do not match the accumulator levels (%d, %d)", levelQ, levelP, acc.levelQ, acc.levelP)
+	}
+
+	c0QP, c1QP := eval.BuffQP[1], eval.BuffQP[2]
+
+	if levelP == 0 {
+		eval.externalProductInPlaceSinglePAndBitDecomp(op0, op1, c0QP, c1QP)
+	} else {
+		eval.externalProductInPlaceMultipleP(levelQ, levelP, op0, op1, c0QP.Q, c0QP.P, c1QP.Q, c1QP.P)
+	}
+
+	ringQP := eval.GetRLWEParameters().RingQP().AtLevel(levelQ, levelP)
+	ringQP.Add(acc.Value[0], c0QP, acc.Value[0])
+	ringQP.Add(acc.Value[1], c1QP, acc.Value[1])
+
+	return
+}
+
+// FinalizeExternalProduct divides the sum accumulated on acc by P with a
+// single ModDown and writes the resulting ciphertext on opOut, whose level
+// must be at least the Q level of acc. The accumulator is left untouched, so
+// the sum can be extended with further calls to ExternalProductLazy.
+func (eval Evaluator) FinalizeExternalProduct(acc *ExternalProductAccumulator, opOut *rlwe.Ciphertext) (err error) {
+
+	if opOut.Level() < acc.levelQ {
+		return fmt.Errorf("cannot FinalizeExternalProduct: opOut level %d is smaller than the accumulator Q level %d", opOut.Level(), acc.levelQ)
+	}
+
+	eval.BasisExtender.ModDownQPtoQNTT(acc.levelQ, acc.levelP, acc.Value[0].Q, acc.Value[0].P, opOut.Value[0])
+	eval.BasisExtender.ModDownQPtoQNTT(acc.levelQ, acc.levelP, acc.Value[1].Q, acc.Value[1].P, opOut.Value[1])
+
+	return
+}
@@ -1,7 +1,6 @@
 package rgsw
 
 import (
-	"bufio"
 	"io"
 
 	"github.com/luxdefi/lattice/v5/core/rlwe"
@@ -62,7 +61,7 @@ func (ct Ciphertext) WriteTo(w io.Writer) (n int64, err error) {
 		return n + inc, err
 
 	default:
-		return ct.WriteTo(bufio.NewWriter(w))
+		return buffer.WriteWithPool(w, ct.WriteTo)
 	}
 }
 
@@ -90,7 +89,7 @@ func (ct *Ciphertext) ReadFrom(r io.Reader) (n int64, err error) {
 		return n + inc, err
 
 	default:
-		return ct.ReadFrom(bufio.NewReader(r))
+		return buffer.ReadWithPool(r, ct.ReadFrom)
 	}
 }
 
@@ -72,7 +72,7 @@ func (eval Evaluator) ExternalProduct(op0 *rlwe.Ciphertext, op1 *Ciphertext, opO
 			}
 		}
 	} else {
-		eval.externalProductInPlaceMultipleP(levelQ, levelP, op0, op1, eval.BuffQP[1].Q, eval.BuffQP[1].P, eval.BuffQP[2].Q, eval.BuffQP[2].P)
+		eval.externalProductInPlaceMultipleP(levelQ, levelP, op0, op1, c0QP.Q, c0QP.P, c1QP.Q, c1QP.P)
 		eval.BasisExtender.ModDownQPtoQNTT(levelQ, levelP, c0QP.Q, c0QP.P, opOut.Value[0])
 		eval.BasisExtender.ModDownQPtoQNTT(levelQ, levelP, c1QP.Q, c1QP.P, opOut.Value[1])
 
@@ -295,6 +295,13 @@ func AddLazy(op interface{}, ringQP ringqp.Ring, opOut *Ciphertext) {
 	}
 }
 
+// Add adds op to opOut and applies the modular reduction on the result.
+// op can be either a *rgsw.Plaintext or a *rgsw.Ciphertext.
+func Add(op interface{}, ringQP ringqp.Ring, opOut *Ciphertext) {
+	AddLazy(op, ringQP, opOut)
+	Reduce(opOut, ringQP, opOut)
+}
+
 // Reduce applies the modular reduction on ctIn and returns the result on opOut.
 func Reduce(ctIn *Ciphertext, ringQP ringqp.Ring, opOut *Ciphertext) {
 	for i := range ctIn.Value[0].Value {
@@ -0,0 +1,126 @@
+package rgsw
+
+import (
+	"fmt"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/ring"
+)
+
+// InternalProduct computes RGSW x RGSW -> RGSW and writes the result on opOut,
+// which encrypts the product of the two operand plaintexts. Each RLWE row of
+// the gadget ciphertext of op1 is multiplied with op0 by an external product,
+// so the noise of opOut grows by one external product with op0 over the noise
+// of op1. Chaining internal products is the core operation of blind-rotation
+// based primitives, where the operand plaintexts are monomials.
+//
+// The operands op1 and opOut must hold a gadget without special modulus
+// (levelP = -1, i.e. with a base-two decomposition), since the rows of a
+// gadget in the extended basis QP cannot be re-encrypted modulo Q alone.
+// The operand op0 can hold any gadget, but must be distinct from opOut and
+// share the Q level of op1. The method is safe for op1 = opOut.
+func (eval Evaluator) InternalProduct(op0 *Ciphertext, op1 *Ciphertext, opOut *Ciphertext) (err error) {
+
+	if op0 == opOut {
+		return fmt.Errorf("cannot InternalProduct: op0 and opOut must be distinct")
+	}
+
+	if op1.LevelP() != -1 || opOut.LevelP() != -1 {
+		return fmt.Errorf("cannot InternalProduct: op1 and opOut must hold a gadget without special modulus (levelP = -1)")
+	}
+
+	levelQ := op0.LevelQ()
+
+	if op1.LevelQ() != levelQ || opOut.LevelQ() != levelQ {
+		return fmt.Errorf("cannot InternalProduct: operands Q levels do not match")
+	}
+
+	if op1.Value[0].BaseTwoDecomposition != opOut.Value[0].BaseTwoDecomposition {
+		return fmt.Errorf("cannot InternalProduct: op1 and opOut base-two decompositions do not match")
+	}
+
+	params := eval.GetRLWEParameters()
+	ringQ := params.RingQ().AtLevel(levelQ)
+
+	// The rows of a gadget ciphertext are stored in the NTT and Montgomery
+	// domain, while the external product consumes and produces ciphertexts in
+	// the standard representation.
+	ctRow := &rlwe.Ciphertext{}
+	ctRow.Value = []ring.Poly{eval.BuffQP[4].Q, eval.BuffQP[5].Q}
+	ctRow.MetaData = &rlwe.MetaData{CiphertextMetaData: rlwe.CiphertextMetaData{IsNTT: true}}
+
+	ctOut := &rlwe.Ciphertext{}
+	ctOut.Value = make([]ring.Poly, 2)
+	ctOut.MetaData = ctRow.MetaData
+
+	for k := range op1.Value {
+		for i := range op1.Value[k].Value {
+			for j := range op1.Value[k].Value[i] {
+
+				row := op1.Value[k].Value[i][j]
+				rowOut := opOut.Value[k].Value[i][j]
+
+				ringQ.IMForm(row[0].Q, ctRow.Value[0])
+				ringQ.IMForm(row[1].Q, ctRow.Value[1])
+
+				ctOut.Value[0] = rowOut[0].Q
+				ctOut.Value[1] = rowOut[1].Q
+
+				eval.ExternalProduct(ctRow, op0, ctOut)
+
+				ringQ.MForm(rowOut[0].Q, rowOut[0].Q)
+				ringQ.MForm(rowOut[1].Q, rowOut[1].Q)
+			}
+		}
+	}
+
+	return
+}
+
+// CMux computes the controlled multiplexer opOut = op0 + sel x (op1 - op0),
+// where sel is a RGSW encryption of a bit: opOut re-encrypts the plaintext of
+// op0 if the bit is 0 and the plaintext of op1 if the bit is 1. The gate is
+// evaluated with a single external product, so the noise of opOut grows by
+// one external product with sel over the noise of the selected operand.
+//
+// The operands op0 and op1 must be of degree 1, in the NTT domain and at a
+// level at least equal to the Q level of sel, at which opOut is returned.
+// The method is safe for opOut = op0 or opOut = op1.
+func (eval Evaluator) CMux(op0, op1 *rlwe.Ciphertext, sel *Ciphertext, opOut *rlwe.Ciphertext) (err error) {
+
+	if op0.Degree() != 1 || op1.Degree() != 1 || opOut.Degree() != 1 {
+		return fmt.Errorf("cannot CMux: op0, op1 and opOut must be of degree 1")
+	}
+
+	levelQ := sel.LevelQ()
+
+	if op0.Level() < levelQ || op1.Level() < levelQ {
+		return fmt.Errorf("cannot CMux: op0 and op1 levels cannot be smaller than the Q level of sel")
+	}
+
+	params := eval.GetRLWEParameters()
+	ringQ := params.RingQ().AtLevel(levelQ)
+
+	diff := &rlwe.Ciphertext{}
+	diff.Value = []ring.Poly{eval.BuffCt.Value[0], eval.BuffCt.Value[1]}
+	diff.MetaData = op0.MetaData
+
+	ringQ.Sub(op1.Value[0], op0.Value[0], diff.Value[0])
+	ringQ.Sub(op1.Value[1], op0.Value[1], diff.Value[1])
+
+	// sel x (op1 - op0), kept in a buffer so that opOut can alias the operands.
+	tmp := &rlwe.Ciphertext{}
+	tmp.Value = []ring.Poly{eval.BuffQP[1].Q, eval.BuffQP[2].Q}
+	tmp.MetaData = op0.MetaData
+
+	eval.ExternalProduct(diff, sel, tmp)
+
+	opOut.Resize(opOut.Degree(), levelQ)
+
+	ringQ.Add(tmp.Value[0], op0.Value[0], opOut.Value[0])
+	ringQ.Add(tmp.Value[1], op0.Value[1], opOut.Value[1])
+
+	*opOut.MetaData = *op0.MetaData
+
+	return
+}
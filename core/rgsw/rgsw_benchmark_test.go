@@ -0,0 +1,69 @@
+package rgsw
+
+import (
+	"testing"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+
+	"github.com/stretchr/testify/require"
+)
+
+func BenchmarkRGSW(b *testing.B) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := rlwe.NewParametersFromLiteral(rlwe.ParametersLiteral{
+		LogN:    10,
+		LogQ:    []int{35, 20},
+		LogP:    []int{61, 61},
+		NTTFlag: true,
+	})
+
+	require.NoError(b, err)
+
+	sk := rlwe.NewKeyGenerator(params).GenSecretKeyNew()
+
+	enc := NewEncryptor(params, sk)
+	eval := NewEvaluator(params, nil)
+
+	ctRGSW := NewCiphertext(params, params.MaxLevelQ(), params.MaxLevelP(), 0)
+	enc.Encrypt(nil, ctRGSW)
+
+	ctRGSWQ := NewCiphertext(params, params.MaxLevelQ(), -1, 7)
+	enc.Encrypt(nil, ctRGSWQ)
+
+	ctRGSWOut := NewCiphertext(params, params.MaxLevelQ(), -1, 7)
+
+	ctRLWE0 := rlwe.NewEncryptor(params, sk).EncryptZeroNew(params.MaxLevel())
+	ctRLWE1 := rlwe.NewEncryptor(params, sk).EncryptZeroNew(params.MaxLevel())
+	ctRLWEOut := rlwe.NewCiphertext(params, 1, params.MaxLevelQ())
+
+	b.Run("Evaluator/ExternalProduct", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			eval.ExternalProduct(ctRLWE0, ctRGSW, ctRLWEOut)
+		}
+	})
+
+	b.Run("Evaluator/InternalProduct", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if err := eval.InternalProduct(ctRGSW, ctRGSWQ, ctRGSWOut); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("Evaluator/CMux", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if err := eval.CMux(ctRLWE0, ctRLWE1, ctRGSW, ctRLWEOut); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("Evaluator/Add", func(b *testing.B) {
+		ctSum := NewCiphertext(params, params.MaxLevelQ(), params.MaxLevelP(), 0)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			Add(ctRGSW, *params.RingQP(), ctSum)
+		}
+	})
+}
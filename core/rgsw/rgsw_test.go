@@ -1,6 +1,7 @@
 package rgsw
 
 import (
+	"bytes"
 	"math/big"
 	"testing"
 
@@ -112,6 +113,321 @@ func TestRGSW(t *testing.T) {
 		require.Equal(t, have, want)
 	})
 
+	t.Run("Evaluator/ExternalProductLazy", func(t *testing.T) {
+
+		scale := new(big.Int).SetUint64(params.Q()[0])
+
+		// Two independent external products X^{k0} * Scale * X^{k1},
+		// accumulated in the extended basis and finalized with a single ModDown.
+		pairs := [][2]int{{0, 1}, {2, 1}}
+
+		encRGSW := NewEncryptor(params, sk)
+		encRLWE := rlwe.NewEncryptor(params, sk)
+		eval := NewEvaluator(params, nil)
+
+		acc, err := NewExternalProductAccumulator(params, params.MaxLevelQ(), params.MaxLevelP())
+		require.NoError(t, err)
+
+		for _, pair := range pairs {
+
+			ptRGSW := rlwe.NewPlaintext(params, params.MaxLevel())
+			ptRLWE := rlwe.NewPlaintext(params, params.MaxLevel())
+
+			setPlaintext(params, ptRGSW, pair[0]) // X^{k0}
+			setPlaintext(params, ptRLWE, pair[1]) // X^{k1}
+
+			// Scale * X^{k1}
+			params.RingQ().MulScalarBigint(ptRLWE.Value, scale, ptRLWE.Value)
+
+			ctRGSW := NewCiphertext(params, params.MaxLevelQ(), params.MaxLevelP(), 0)
+			ctRLWE := rlwe.NewCiphertext(params, 1, params.MaxLevelQ())
+
+			encRGSW.Encrypt(ptRGSW, ctRGSW)
+			encRLWE.Encrypt(ptRLWE, ctRLWE)
+
+			require.NoError(t, eval.ExternalProductLazy(ctRLWE, ctRGSW, acc))
+		}
+
+		res := rlwe.NewCiphertext(params, 1, params.MaxLevelQ())
+
+		require.NoError(t, eval.FinalizeExternalProduct(acc, res))
+
+		ptHave := rlwe.NewDecryptor(params, sk).DecryptNew(res)
+
+		params.RingQ().INTT(ptHave.Value, ptHave.Value)
+
+		coeffs := make([]*big.Int, params.N())
+
+		for i := range coeffs {
+			coeffs[i] = new(big.Int)
+		}
+
+		params.RingQ().PolyToBigintCentered(ptHave.Value, 1, coeffs)
+
+		// Sum_{(k0, k1)} X^{k0} * Scale * X^{k1} / Scale
+		for i := range coeffs {
+			bignum.DivRound(coeffs[i], scale, coeffs[i])
+		}
+
+		have := make([]uint64, params.N())
+		want := make([]uint64, params.N())
+
+		for i := range coeffs {
+			have[i] = coeffs[i].Uint64()
+		}
+
+		for _, pair := range pairs {
+			want[pair[0]+pair[1]]++
+		}
+
+		require.Equal(t, have, want)
+
+		_, err = NewExternalProductAccumulator(params, params.MaxLevelQ(), -1)
+		require.Error(t, err)
+	})
+
+	t.Run("Evaluator/InternalProduct", func(t *testing.T) {
+
+		ptRGSW0 := rlwe.NewPlaintext(params, params.MaxLevel())
+		ptRGSW1 := rlwe.NewPlaintext(params, params.MaxLevel())
+		ptRLWE := rlwe.NewPlaintext(params, params.MaxLevel())
+
+		k0 := 1
+		k1 := 2
+		k2 := 3
+
+		setPlaintext(params, ptRGSW0, k0) // X^{k0}
+		setPlaintext(params, ptRGSW1, k1) // X^{k1}
+		setPlaintext(params, ptRLWE, k2)  // X^{k2}
+
+		scale := new(big.Int).SetUint64(params.Q()[0])
+
+		// Scale * X^{k2}
+		params.RingQ().MulScalarBigint(ptRLWE.Value, scale, ptRLWE.Value)
+
+		// The multiplicand gadget has no special modulus, so that its rows
+		// can be re-encrypted modulo Q by the external products.
+		ctRGSW0 := NewCiphertext(params, params.MaxLevelQ(), params.MaxLevelP(), 0)
+		ctRGSW1 := NewCiphertext(params, params.MaxLevelQ(), -1, 7)
+		ctRGSW2 := NewCiphertext(params, params.MaxLevelQ(), -1, 7)
+		ctRLWE := rlwe.NewCiphertext(params, 1, params.MaxLevelQ())
+
+		enc := NewEncryptor(params, sk)
+		enc.Encrypt(ptRGSW0, ctRGSW0)
+		enc.Encrypt(ptRGSW1, ctRGSW1)
+		rlwe.NewEncryptor(params, sk).Encrypt(ptRLWE, ctRLWE)
+
+		eval := NewEvaluator(params, nil)
+
+		// RGSW(X^{k0}) x RGSW(X^{k1}) = RGSW(X^{k0+k1})
+		require.NoError(t, eval.InternalProduct(ctRGSW0, ctRGSW1, ctRGSW2))
+
+		// X^{k0+k1} * Scale * X^{k2}
+		eval.ExternalProduct(ctRLWE, ctRGSW2, ctRLWE)
+
+		ptHave := rlwe.NewDecryptor(params, sk).DecryptNew(ctRLWE)
+
+		params.RingQ().INTT(ptHave.Value, ptHave.Value)
+
+		coeffs := make([]*big.Int, params.N())
+
+		for i := range coeffs {
+			coeffs[i] = new(big.Int)
+		}
+
+		params.RingQ().PolyToBigintCentered(ptHave.Value, 1, coeffs)
+
+		// X^{k0+k1} * Scale * X^{k2} / Scale
+		for i := range coeffs {
+			bignum.DivRound(coeffs[i], scale, coeffs[i])
+		}
+
+		have := make([]uint64, params.N())
+		want := make([]uint64, params.N())
+
+		for i := range coeffs {
+			have[i] = coeffs[i].Uint64()
+		}
+
+		want[k0+k1+k2] = 1
+
+		require.Equal(t, have, want)
+
+		// Gadgets in the extended basis QP cannot be re-encrypted.
+		require.Error(t, eval.InternalProduct(ctRGSW0, ctRGSW0, ctRGSW2))
+
+		// The multiplier cannot be overwritten.
+		require.Error(t, eval.InternalProduct(ctRGSW1, ctRGSW1, ctRGSW1))
+	})
+
+	t.Run("Evaluator/CMux", func(t *testing.T) {
+
+		scale := new(big.Int).SetUint64(params.Q()[0])
+
+		k0 := 1
+		k1 := 2
+
+		pt0 := rlwe.NewPlaintext(params, params.MaxLevel())
+		pt1 := rlwe.NewPlaintext(params, params.MaxLevel())
+
+		setPlaintext(params, pt0, k0) // X^{k0}
+		setPlaintext(params, pt1, k1) // X^{k1}
+
+		// Scale * X^{k}
+		params.RingQ().MulScalarBigint(pt0.Value, scale, pt0.Value)
+		params.RingQ().MulScalarBigint(pt1.Value, scale, pt1.Value)
+
+		ct0 := rlwe.NewCiphertext(params, 1, params.MaxLevelQ())
+		ct1 := rlwe.NewCiphertext(params, 1, params.MaxLevelQ())
+
+		encRLWE := rlwe.NewEncryptor(params, sk)
+		encRLWE.Encrypt(pt0, ct0)
+		encRLWE.Encrypt(pt1, ct1)
+
+		enc := NewEncryptor(params, sk)
+		eval := NewEvaluator(params, nil)
+
+		for _, bit := range []int{0, 1} {
+
+			ptSel := rlwe.NewPlaintext(params, params.MaxLevel())
+			if bit == 1 {
+				setPlaintext(params, ptSel, 0) // 1
+			}
+
+			ctSel := NewCiphertext(params, params.MaxLevelQ(), params.MaxLevelP(), 0)
+			enc.Encrypt(ptSel, ctSel)
+
+			ctOut := rlwe.NewCiphertext(params, 1, params.MaxLevelQ())
+			require.NoError(t, eval.CMux(ct0, ct1, ctSel, ctOut))
+
+			ptHave := rlwe.NewDecryptor(params, sk).DecryptNew(ctOut)
+
+			params.RingQ().INTT(ptHave.Value, ptHave.Value)
+
+			coeffs := make([]*big.Int, params.N())
+
+			for i := range coeffs {
+				coeffs[i] = new(big.Int)
+			}
+
+			params.RingQ().PolyToBigintCentered(ptHave.Value, 1, coeffs)
+
+			for i := range coeffs {
+				bignum.DivRound(coeffs[i], scale, coeffs[i])
+			}
+
+			have := make([]uint64, params.N())
+			want := make([]uint64, params.N())
+
+			for i := range coeffs {
+				have[i] = coeffs[i].Uint64()
+			}
+
+			// X^{k0} if the selector is 0, X^{k1} if it is 1.
+			if bit == 0 {
+				want[k0] = 1
+			} else {
+				want[k1] = 1
+			}
+
+			require.Equal(t, have, want)
+		}
+	})
+
+	t.Run("Evaluator/Add", func(t *testing.T) {
+
+		pt0 := rlwe.NewPlaintext(params, params.MaxLevel())
+		pt1 := rlwe.NewPlaintext(params, params.MaxLevel())
+		ptRLWE := rlwe.NewPlaintext(params, params.MaxLevel())
+
+		setPlaintext(params, pt0, 0) // 1
+		setPlaintext(params, pt1, 1) // X
+		setPlaintext(params, ptRLWE, 0)
+
+		scale := new(big.Int).SetUint64(params.Q()[0])
+
+		// Scale * 1
+		params.RingQ().MulScalarBigint(ptRLWE.Value, scale, ptRLWE.Value)
+
+		ct0 := NewCiphertext(params, params.MaxLevelQ(), params.MaxLevelP(), 0)
+		ct1 := NewCiphertext(params, params.MaxLevelQ(), params.MaxLevelP(), 0)
+		ctRLWE := rlwe.NewCiphertext(params, 1, params.MaxLevelQ())
+
+		enc := NewEncryptor(params, sk)
+		enc.Encrypt(pt0, ct0)
+		enc.Encrypt(pt1, ct1)
+		rlwe.NewEncryptor(params, sk).Encrypt(ptRLWE, ctRLWE)
+
+		// RGSW(1) + RGSW(X) = RGSW(1 + X)
+		Add(ct1, *params.RingQP(), ct0)
+
+		// (1 + X) * Scale * 1
+		NewEvaluator(params, nil).ExternalProduct(ctRLWE, ct0, ctRLWE)
+
+		ptHave := rlwe.NewDecryptor(params, sk).DecryptNew(ctRLWE)
+
+		params.RingQ().INTT(ptHave.Value, ptHave.Value)
+
+		coeffs := make([]*big.Int, params.N())
+
+		for i := range coeffs {
+			coeffs[i] = new(big.Int)
+		}
+
+		params.RingQ().PolyToBigintCentered(ptHave.Value, 1, coeffs)
+
+		for i := range coeffs {
+			bignum.DivRound(coeffs[i], scale, coeffs[i])
+		}
+
+		have := make([]uint64, params.N())
+		want := make([]uint64, params.N())
+
+		for i := range coeffs {
+			have[i] = coeffs[i].Uint64()
+		}
+
+		want[0] = 1
+		want[1] = 1
+
+		require.Equal(t, have, want)
+	})
+
+	t.Run("Evaluator/ExternalProductFromReader", func(t *testing.T) {
+
+		ptRGSW := rlwe.NewPlaintext(params, params.MaxLevel())
+		ptRLWE := rlwe.NewPlaintext(params, params.MaxLevel())
+
+		setPlaintext(params, ptRGSW, 0) // X^{k0}
+		setPlaintext(params, ptRLWE, 1) // X^{k1}
+
+		scale := new(big.Int).SetUint64(params.Q()[0])
+
+		// Scale * X^{k1}
+		params.RingQ().MulScalarBigint(ptRLWE.Value, scale, ptRLWE.Value)
+
+		ctRGSW := NewCiphertext(params, params.MaxLevelQ(), params.MaxLevelP(), 0)
+		ctRLWE := rlwe.NewCiphertext(params, 1, params.MaxLevelQ())
+
+		NewEncryptor(params, sk).Encrypt(ptRGSW, ctRGSW)
+		rlwe.NewEncryptor(params, sk).Encrypt(ptRLWE, ctRLWE)
+
+		eval := NewEvaluator(params, nil)
+
+		ctWant := rlwe.NewCiphertext(params, 1, params.MaxLevelQ())
+		eval.ExternalProduct(ctRLWE, ctRGSW, ctWant)
+
+		data, err := ctRGSW.MarshalBinary()
+		require.NoError(t, err)
+
+		// The streamed evaluation is bit-exact with the in-memory one.
+		ctHave := rlwe.NewCiphertext(params, 1, params.MaxLevelQ())
+		n, err := eval.ExternalProductFromReader(ctRLWE, bytes.NewReader(data), ctHave)
+		require.NoError(t, err)
+		require.Equal(t, int64(ctRGSW.BinarySize()), n)
+		require.True(t, ctWant.Equal(ctHave))
+	})
+
 	t.Run("WriteAndRead", func(t *testing.T) {
 		ct := NewCiphertext(params, params.MaxLevelQ(), params.MaxLevelP(), 0)
 		NewEncryptor(params, pk).Encrypt(nil, ct)
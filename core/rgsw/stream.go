@@ -0,0 +1,167 @@
+package rgsw
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/ring"
+	"github.com/luxdefi/lattice/v5/ring/ringqp"
+	"github.com/luxdefi/lattice/v5/utils/buffer"
+)
+
+// ExternalProductFromReader computes RLWE x RGSW -> RLWE like
+// Evaluator.ExternalProduct, with the RGSW operand read from r one gadget
+// digit at a time instead of being held in memory. The stream must contain
+// the serialization of a Ciphertext (see Ciphertext.WriteTo), e.g. one record
+// of an RGSW database too large to be loaded at once: the memory footprint of
+// the evaluation is a single digit, i.e. two ringqp.Poly, independently of
+// the decomposition size.
+//
+// The method requires an extended basis P (levelP > 0) and no base-2
+// decomposition, i.e. the parameters of the multiple-P code path of
+// Evaluator.ExternalProduct, with which it is bit-exact. It returns the
+// number of bytes read from r, which is exactly the BinarySize of the
+// streamed Ciphertext on success.
+func (eval Evaluator) ExternalProductFromReader(op0 *rlwe.Ciphertext, r io.Reader, opOut *rlwe.Ciphertext) (n int64, err error) {
+	switch r := r.(type) {
+	case buffer.Reader:
+		return eval.externalProductFromReader(op0, r, opOut)
+	default:
+		return buffer.ReadWithPool(r, func(r io.Reader) (int64, error) {
+			return eval.externalProductFromReader(op0, r.(buffer.Reader), opOut)
+		})
+	}
+}
+
+func (eval Evaluator) externalProductFromReader(op0 *rlwe.Ciphertext, r buffer.Reader, opOut *rlwe.Ciphertext) (n int64, err error) {
+
+	params := eval.GetRLWEParameters()
+
+	c0QP, c1QP := eval.BuffQP[1], eval.BuffQP[2]
+	c2QP := eval.BuffQP[0]
+
+	var digit rlwe.VectorQP
+
+	var levelQ, levelP int
+	var ringQP ringqp.Ring
+	var ringQ, ringP *ring.Ring
+	var QiOverF, PiOverF int
+
+	var c2NTT, c2InvNTT ring.Poly
+
+	var inc int64
+	var reduce int
+
+	for k := 0; k < 2; k++ {
+
+		var baseTwoDecomposition int
+		if inc, err = buffer.ReadAsUint64[int](r, &baseTwoDecomposition); err != nil {
+			return n + inc, fmt.Errorf("cannot ExternalProductFromReader: %w", err)
+		}
+
+		n += inc
+
+		if baseTwoDecomposition != 0 {
+			return n, fmt.Errorf("cannot ExternalProductFromReader: method requires BaseTwoDecomposition = 0 but stream has %d", baseTwoDecomposition)
+		}
+
+		var rows int
+		if inc, err = buffer.ReadAsUint64[int](r, &rows); err != nil {
+			return n + inc, fmt.Errorf("cannot ExternalProductFromReader: %w", err)
+		}
+
+		n += inc
+
+		for i := 0; i < rows; i++ {
+
+			var cols int
+			if inc, err = buffer.ReadAsUint64[int](r, &cols); err != nil {
+				return n + inc, fmt.Errorf("cannot ExternalProductFromReader: %w", err)
+			}
+
+			n += inc
+
+			if cols != 1 {
+				return n, fmt.Errorf("cannot ExternalProductFromReader: stream has %d digits per row but BaseTwoDecomposition = 0 implies 1", cols)
+			}
+
+			// The single VectorQP buffer is allocated by the first read and
+			// reused by the following ones.
+			if inc, err = digit.ReadFrom(r); err != nil {
+				return n + inc, fmt.Errorf("cannot ExternalProductFromReader: %w", err)
+			}
+
+			n += inc
+
+			if len(digit) != 2 {
+				return n, fmt.Errorf("cannot ExternalProductFromReader: stream digit has degree %d but the method requires degree 1", len(digit)-1)
+			}
+
+			if k == 0 && i == 0 {
+
+				levelQ, levelP = digit.LevelQ(), digit.LevelP()
+
+				if levelP < 1 {
+					return n, fmt.Errorf("cannot ExternalProductFromReader: method requires an extended basis P")
+				}
+
+				ringQP = params.RingQP().AtLevel(levelQ, levelP)
+				ringQ = ringQP.RingQ
+				ringP = ringQP.RingP
+
+				QiOverF = params.QiOverflowMargin(levelQ) >> 1
+				PiOverF = params.PiOverflowMargin(levelP) >> 1
+			}
+
+			if i == 0 {
+				if op0.IsNTT {
+					c2NTT = op0.Value[k]
+					c2InvNTT = eval.BuffInvNTT
+					ringQ.INTT(c2NTT, c2InvNTT)
+				} else {
+					c2NTT = eval.BuffInvNTT
+					c2InvNTT = op0.Value[k]
+					ringQ.NTT(c2InvNTT, c2NTT)
+				}
+			}
+
+			eval.DecomposeSingleNTT(levelQ, levelP, levelP+1, i, c2NTT, c2InvNTT, c2QP.Q, c2QP.P)
+
+			if k == 0 && i == 0 {
+				ringQP.MulCoeffsMontgomeryLazy(digit[0], c2QP, c0QP)
+				ringQP.MulCoeffsMontgomeryLazy(digit[1], c2QP, c1QP)
+			} else {
+				ringQP.MulCoeffsMontgomeryLazyThenAddLazy(digit[0], c2QP, c0QP)
+				ringQP.MulCoeffsMontgomeryLazyThenAddLazy(digit[1], c2QP, c1QP)
+			}
+
+			if reduce%QiOverF == QiOverF-1 {
+				ringQ.Reduce(c0QP.Q, c0QP.Q)
+				ringQ.Reduce(c1QP.Q, c1QP.Q)
+			}
+
+			if reduce%PiOverF == PiOverF-1 {
+				ringP.Reduce(c0QP.P, c0QP.P)
+				ringP.Reduce(c1QP.P, c1QP.P)
+			}
+
+			reduce++
+		}
+	}
+
+	if reduce%QiOverF != 0 {
+		ringQ.Reduce(c0QP.Q, c0QP.Q)
+		ringQ.Reduce(c1QP.Q, c1QP.Q)
+	}
+
+	if reduce%PiOverF != 0 {
+		ringP.Reduce(c0QP.P, c0QP.P)
+		ringP.Reduce(c1QP.P, c1QP.P)
+	}
+
+	eval.BasisExtender.ModDownQPtoQNTT(levelQ, levelP, c0QP.Q, c0QP.P, opOut.Value[0])
+	eval.BasisExtender.ModDownQPtoQNTT(levelQ, levelP, c1QP.Q, c1QP.P, opOut.Value[1])
+
+	return
+}
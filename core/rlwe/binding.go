@@ -0,0 +1,34 @@
+package rlwe
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+)
+
+// ErrParameterMismatch is the error returned (possibly wrapped) when an
+// operation mixes objects bound to different parameter sets, e.g. a ciphertext
+// encrypted under one parameter set handed to an evaluator instantiated with
+// another. Use errors.Is to test for it.
+var ErrParameterMismatch = errors.New("object is bound to a different parameter set")
+
+// Fingerprint returns a 64-bit digest of the parameter set, used as a
+// process-local binding tag: freshly allocated ciphertexts and plaintexts
+// record it in their MetaData (see MetaData.ParameterFingerprint) and
+// encryptors, evaluators and decryptors verify it, failing with
+// ErrParameterMismatch instead of producing silently wrong results when
+// objects from different parameter sets are mixed.
+func (p Parameters) Fingerprint() uint64 {
+	return p.fingerprint
+}
+
+// fingerprintOf digests the serialization of the parameter set into a 64-bit
+// binding tag. A zero fingerprint disables the binding checks.
+func fingerprintOf(p Parameters) uint64 {
+	s, err := p.MarshalBinary()
+	if err != nil {
+		return 0
+	}
+	digest := sha256.Sum256(s)
+	return binary.LittleEndian.Uint64(digest[:8])
+}
@@ -0,0 +1,65 @@
+package rlwe
+
+import (
+	"crypto/sha256"
+	"fmt"
+)
+
+// Canonicalize brings the ciphertext to its canonical normal form under the
+// given parameters: the polynomials are brought to the default NTT domain of
+// the parameters, their coefficients are fully reduced and an empty tag map
+// is normalized to nil. Two ciphertexts representing the same element have
+// identical serializations once canonicalized, enabling byte-level
+// deduplication and stable fingerprints (see Fingerprint).
+func (ct *Ciphertext) Canonicalize(params ParameterProvider) (err error) {
+
+	if ct.MetaData == nil {
+		return fmt.Errorf("cannot Canonicalize: MetaData is nil")
+	}
+
+	p := params.GetRLWEParameters()
+
+	ringQ := p.RingQ().AtLevel(ct.Level())
+
+	if ct.IsNTT != p.NTTFlag() {
+		for i := range ct.Value {
+			if p.NTTFlag() {
+				ringQ.NTT(ct.Value[i], ct.Value[i])
+			} else {
+				ringQ.INTT(ct.Value[i], ct.Value[i])
+			}
+		}
+		ct.IsNTT = p.NTTFlag()
+	}
+
+	for i := range ct.Value {
+		ringQ.Reduce(ct.Value[i], ct.Value[i])
+	}
+
+	if len(ct.Tags) == 0 {
+		ct.Tags = nil
+	}
+
+	return
+}
+
+// Fingerprint returns the sha256 digest of the canonical serialization of the
+// ciphertext: equal ciphertexts (up to NTT domain and coefficient
+// representation) have equal fingerprints, making the digest a stable cache
+// or deduplication key. The receiver is left untouched, the canonicalization
+// being applied on a copy.
+func (ct Ciphertext) Fingerprint(params ParameterProvider) (digest [32]byte, err error) {
+
+	cpy := ct.CopyNew()
+
+	if err = cpy.Canonicalize(params); err != nil {
+		return [32]byte{}, fmt.Errorf("cannot Fingerprint: %w", err)
+	}
+
+	data, err := cpy.MarshalBinary()
+	if err != nil {
+		return [32]byte{}, fmt.Errorf("cannot Fingerprint: %w", err)
+	}
+
+	return sha256.Sum256(data), nil
+}
@@ -0,0 +1,150 @@
+package rlwe
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/luxdefi/lattice/v5/ring"
+	"github.com/luxdefi/lattice/v5/ring/ringqp"
+	"github.com/luxdefi/lattice/v5/utils/buffer"
+	"github.com/luxdefi/lattice/v5/utils/sampling"
+)
+
+// CiphertextSeedSize is the byte-size of the seed of a CompressedCiphertext.
+const CiphertextSeedSize = 32
+
+// CompressedCiphertext is a compact form of a degree-1 Ciphertext in which
+// the uniform polynomial of a fresh secret-key encryption is stored as the
+// PRNG seed it is expanded from, halving the size of the ciphertext on the
+// wire. It is produced by Encryptor.EncryptCompressedNew and must be
+// expanded back into a full Ciphertext with Decompress before any
+// homomorphic operation.
+//
+// The stored polynomial is the non-uniform element c0 = -c1 * s + m + e.
+type CompressedCiphertext struct {
+	Element[ring.Poly]
+	Seed [CiphertextSeedSize]byte
+}
+
+// NewCompressedCiphertext returns a new CompressedCiphertext with zero values
+// and an associated MetaData set to the Parameters default value.
+func NewCompressedCiphertext(params ParameterProvider, level ...int) (ct *CompressedCiphertext) {
+	return &CompressedCiphertext{Element: *NewElement(params, 0, level...)}
+}
+
+// CopyNew creates a new element as a copy of the target element.
+func (ct CompressedCiphertext) CopyNew() *CompressedCiphertext {
+	return &CompressedCiphertext{Element: *ct.Element.CopyNew(), Seed: ct.Seed}
+}
+
+// Equal performs a deep equal.
+func (ct CompressedCiphertext) Equal(other *CompressedCiphertext) bool {
+	return ct.Seed == other.Seed && ct.Element.Equal(&other.Element)
+}
+
+// Decompress expands the seed into the uniform polynomial and returns the
+// full Ciphertext. The same seed and parameters always expand to the same
+// ciphertext.
+func (ct CompressedCiphertext) Decompress(params ParameterProvider) (ctOut *Ciphertext, err error) {
+
+	ctOut = NewCiphertext(params, 1, ct.Level())
+	*ctOut.MetaData = *ct.MetaData
+	ctOut.Value[0].Copy(ct.Value[0])
+
+	if err = expandCiphertextSeed(params, ct.Seed, ctOut.Value[1]); err != nil {
+		return nil, fmt.Errorf("cannot Decompress: %w", err)
+	}
+
+	return
+}
+
+// expandCiphertextSeed expands the seed into the uniform polynomial c1 of a
+// ciphertext, sampled at the level of c1 with a PRNG keyed with the seed.
+// The sampled polynomial is interpreted in the domain (NTT or not) of the
+// ciphertext.
+func expandCiphertextSeed(params ParameterProvider, seed [CiphertextSeedSize]byte, c1 ring.Poly) (err error) {
+
+	prng, err := sampling.NewKeyedPRNG(seed[:])
+	if err != nil {
+		return err
+	}
+
+	ringqp.NewUniformSampler(prng, *params.GetRLWEParameters().RingQP()).AtLevel(c1.Level(), -1).Read(ringqp.Poly{Q: c1})
+
+	return
+}
+
+// BinarySize returns the serialized size of the object in bytes.
+func (ct CompressedCiphertext) BinarySize() int {
+	return CiphertextSeedSize + ct.Element.BinarySize()
+}
+
+// WriteTo writes the object on an io.Writer. It implements the io.WriterTo
+// interface, and will write exactly object.BinarySize() bytes on w.
+//
+// See Ciphertext.WriteTo for the recommended writer types.
+func (ct CompressedCiphertext) WriteTo(w io.Writer) (n int64, err error) {
+	switch w := w.(type) {
+	case buffer.Writer:
+
+		var inc int64
+
+		if inc, err = buffer.Write(w, ct.Seed[:]); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		if inc, err = ct.Element.WriteTo(w); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		return
+	default:
+		return buffer.WriteWithPool(w, ct.WriteTo)
+	}
+}
+
+// ReadFrom reads on the object from an io.Writer. It implements the
+// io.ReaderFrom interface.
+//
+// See Ciphertext.ReadFrom for the recommended reader types.
+func (ct *CompressedCiphertext) ReadFrom(r io.Reader) (n int64, err error) {
+	switch r := r.(type) {
+	case buffer.Reader:
+
+		var inc int64
+
+		if inc, err = buffer.Read(r, ct.Seed[:]); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		if inc, err = ct.Element.ReadFrom(r); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		return
+	default:
+		return buffer.ReadWithPool(r, ct.ReadFrom)
+	}
+}
+
+// MarshalBinary encodes the object into a binary form on a newly allocated slice of bytes.
+func (ct CompressedCiphertext) MarshalBinary() ([]byte, error) {
+	buf := buffer.NewBufferSize(ct.BinarySize())
+	_, err := ct.WriteTo(buf)
+	return buf.Bytes(), err
+}
+
+// UnmarshalBinary decodes a slice of bytes generated by
+// MarshalBinary or WriteTo on the object.
+func (ct *CompressedCiphertext) UnmarshalBinary(b []byte) error {
+	_, err := ct.ReadFrom(buffer.NewBuffer(b))
+	return err
+}
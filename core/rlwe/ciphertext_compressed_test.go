@@ -0,0 +1,81 @@
+package rlwe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressedCiphertext(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN: 10,
+		LogQ: []int{55, 45},
+		LogP: []int{60},
+	})
+	require.NoError(t, err)
+
+	kgen := NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+	enc := NewEncryptor(params, sk)
+	dec := NewDecryptor(params, sk)
+
+	seed := make([]byte, CiphertextSeedSize)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+
+	level := params.MaxLevel()
+	ringQ := params.RingQ().AtLevel(level)
+
+	pt := NewPlaintext(params, level)
+	for i, s := range ringQ.SubRings[:level+1] {
+		for j := range pt.Value.Coeffs[i] {
+			pt.Value.Coeffs[i][j] = (uint64(j%16) << 40) % s.Modulus
+		}
+	}
+
+	cct, err := enc.EncryptCompressedNew(pt, seed)
+	require.NoError(t, err)
+
+	// The decompressed ciphertext decrypts to the plaintext.
+	ct, err := cct.Decompress(params)
+	require.NoError(t, err)
+
+	ptOut := dec.DecryptNew(ct)
+	ringQ.Sub(ptOut.Value, pt.Value, ptOut.Value)
+	if ptOut.IsNTT {
+		ringQ.INTT(ptOut.Value, ptOut.Value)
+	}
+	require.GreaterOrEqual(t, float64(params.LogN()), ringQ.Log2OfStandardDeviation(ptOut.Value))
+
+	// Decompression is deterministic.
+	ctBis, err := cct.Decompress(params)
+	require.NoError(t, err)
+	require.True(t, ct.Equal(ctBis))
+
+	// The compressed form stores a single polynomial instead of two.
+	require.Less(t, cct.BinarySize(), ct.BinarySize()*3/4)
+
+	// Serialization roundtrip.
+	data, err := cct.MarshalBinary()
+	require.NoError(t, err)
+	require.Len(t, data, cct.BinarySize())
+
+	cctOut := new(CompressedCiphertext)
+	require.NoError(t, cctOut.UnmarshalBinary(data))
+	require.True(t, cct.Equal(cctOut))
+
+	ctOut, err := cctOut.Decompress(params)
+	require.NoError(t, err)
+	require.True(t, ct.Equal(ctOut))
+
+	// Invalid seed size.
+	_, err = enc.EncryptCompressedNew(pt, seed[:16])
+	require.Error(t, err)
+
+	// Compressed encryption requires a secret key.
+	_, err = NewEncryptor(params, kgen.GenPublicKeyNew(sk)).EncryptCompressedNew(pt, seed)
+	require.Error(t, err)
+}
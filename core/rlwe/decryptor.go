@@ -5,6 +5,7 @@ import (
 
 	"github.com/luxdefi/lattice/v5/ring"
 	"github.com/luxdefi/lattice/v5/utils"
+	"github.com/luxdefi/lattice/v5/utils/metrics"
 )
 
 // Decryptor is a structure used to decrypt Ciphertext. It stores the secret-key.
@@ -48,8 +49,17 @@ func (d Decryptor) DecryptNew(ct *Ciphertext) (pt *Plaintext) {
 // Decrypt decrypts the Ciphertext and writes the result in pt.
 // The level of the output Plaintext is min(ct.Level(), pt.Level())
 // Output pt MetaData will match the input ct MetaData.
+// The method panics with an error wrapping ErrParameterMismatch if the input
+// Ciphertext is bound to a different parameter set than the decryptor's (see
+// Parameters.Fingerprint).
 func (d Decryptor) Decrypt(ct *Ciphertext, pt *Plaintext) {
 
+	metrics.IncCounter(metrics.Decrypt, 1)
+
+	if ct.ParameterFingerprint != 0 && d.params.fingerprint != 0 && ct.ParameterFingerprint != d.params.fingerprint {
+		panic(fmt.Errorf("cannot Decrypt: %w", ErrParameterMismatch))
+	}
+
 	level := utils.Min(ct.Level(), pt.Level())
 
 	ringQ := d.ringQ.AtLevel(level)
@@ -1,7 +1,6 @@
 package rlwe
 
 import (
-	"bufio"
 	"fmt"
 	"io"
 
@@ -9,6 +8,7 @@ import (
 	"github.com/luxdefi/lattice/v5/ring"
 	"github.com/luxdefi/lattice/v5/ring/ringqp"
 	"github.com/luxdefi/lattice/v5/utils/buffer"
+	"github.com/luxdefi/lattice/v5/utils/metrics"
 	"github.com/luxdefi/lattice/v5/utils/sampling"
 	"github.com/luxdefi/lattice/v5/utils/structs"
 )
@@ -45,6 +45,7 @@ func NewElement(params ParameterProvider, degree int, levelQ ...int) *Element[ri
 			CiphertextMetaData: CiphertextMetaData{
 				IsNTT: p.NTTFlag(),
 			},
+			ParameterFingerprint: p.Fingerprint(),
 		},
 	}
 }
@@ -67,6 +68,7 @@ func NewElementExtended(params ParameterProvider, degree, levelQ, levelP int) *E
 			CiphertextMetaData: CiphertextMetaData{
 				IsNTT: p.NTTFlag(),
 			},
+			ParameterFingerprint: p.Fingerprint(),
 		},
 	}
 }
@@ -261,7 +263,11 @@ func SwitchCiphertextRingDegreeNTT(ctIn *Element[ring.Poly], ringQLargeDim *ring
 		}
 	}
 
+	// opOut belongs to another parameter set than ctIn: it keeps its own
+	// parameter binding instead of inheriting ctIn's.
+	fingerprint := opOut.ParameterFingerprint
 	*opOut.MetaData = *ctIn.MetaData
+	opOut.ParameterFingerprint = fingerprint
 }
 
 // SwitchCiphertextRingDegree changes the ring degree of ctIn to the one of opOut.
@@ -286,7 +292,11 @@ func SwitchCiphertextRingDegree(ctIn, opOut *Element[ring.Poly]) {
 		}
 	}
 
+	// opOut belongs to another parameter set than ctIn: it keeps its own
+	// parameter binding instead of inheriting ctIn's.
+	fingerprint := opOut.ParameterFingerprint
 	*opOut.MetaData = *ctIn.MetaData
+	opOut.ParameterFingerprint = fingerprint
 }
 
 // BinarySize returns the serialized size of the object in bytes.
@@ -341,10 +351,16 @@ func (op Element[T]) WriteTo(w io.Writer) (n int64, err error) {
 
 		inc, err = op.Value.WriteTo(w)
 
-		return n + inc, err
+		n += inc
+
+		if err == nil {
+			metrics.Observe(metrics.WrittenBytes, float64(n))
+		}
+
+		return n, err
 
 	default:
-		return op.WriteTo(bufio.NewWriter(w))
+		return buffer.WriteWithPool(w, op.WriteTo)
 	}
 }
 
@@ -393,10 +409,16 @@ func (op *Element[T]) ReadFrom(r io.Reader) (n int64, err error) {
 
 		inc, err = op.Value.ReadFrom(r)
 
-		return n + inc, err
+		n += inc
+
+		if err == nil {
+			metrics.Observe(metrics.ReadBytes, float64(n))
+		}
+
+		return n, err
 
 	default:
-		return op.ReadFrom(bufio.NewReader(r))
+		return buffer.ReadWithPool(r, op.ReadFrom)
 	}
 }
 
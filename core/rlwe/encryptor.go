@@ -7,6 +7,7 @@ import (
 	"github.com/luxdefi/lattice/v5/ring"
 	"github.com/luxdefi/lattice/v5/ring/ringqp"
 	"github.com/luxdefi/lattice/v5/utils"
+	"github.com/luxdefi/lattice/v5/utils/metrics"
 	"github.com/luxdefi/lattice/v5/utils/sampling"
 )
 
@@ -54,6 +55,10 @@ type Encryptor struct {
 	xsSampler      ring.Sampler
 	basisextender  *ring.BasisExtender
 	uniformSampler ringqp.UniformSampler
+
+	// crp, if set, fixes the uniform element c1 of fresh encryptions of
+	// zero to a common reference polynomial (see WithCRP).
+	crp *ringqp.Poly
 }
 
 // GetRLWEParameters returns the underlying rlwe.Parameters.
@@ -122,6 +127,20 @@ func newEncryptorBuffers(params Parameters) *encryptorBuffers {
 	}
 }
 
+// checkParameterBinding returns ErrParameterMismatch if an operand is bound to
+// a different parameter set than the encryptor's (see Parameters.Fingerprint).
+// Operands with a nil MetaData or a zero fingerprint (e.g. deserialized ones)
+// are not checked.
+func (enc Encryptor) checkParameterBinding(ms ...*MetaData) (err error) {
+	fingerprint := enc.params.Fingerprint()
+	for _, m := range ms {
+		if m != nil && m.ParameterFingerprint != 0 && fingerprint != 0 && m.ParameterFingerprint != fingerprint {
+			return ErrParameterMismatch
+		}
+	}
+	return
+}
+
 // Encrypt encrypts the input plaintext using the stored encryption key and writes the result on ct.
 // The method currently accepts only *rlwe.Ciphertext as ct.
 // If a Plaintext is given, then the output Ciphertext MetaData will match the Plaintext MetaData.
@@ -132,11 +151,17 @@ func newEncryptorBuffers(params Parameters) *encryptorBuffers {
 // The encryption procedure depends on the parameters: If the auxiliary modulus P is defined, the
 // encryption of zero is sampled in QP before being rescaled by P; otherwise, it is directly sampled in Q.
 func (enc Encryptor) Encrypt(pt *Plaintext, ct interface{}) (err error) {
+
+	metrics.IncCounter(metrics.Encrypt, 1)
+
 	if pt == nil {
 		return enc.EncryptZero(ct)
 	} else {
 		switch ct := ct.(type) {
 		case *Ciphertext:
+			if err = enc.checkParameterBinding(pt.MetaData, ct.MetaData); err != nil {
+				return fmt.Errorf("cannot Encrypt: %w", err)
+			}
 			*ct.MetaData = *pt.MetaData
 			level := utils.Min(pt.Level(), ct.Level())
 			ct.Resize(ct.Degree(), level)
@@ -174,10 +199,20 @@ func (enc Encryptor) EncryptNew(pt *Plaintext) (ct *Ciphertext, err error) {
 // encryption of zero is sampled in QP before being rescaled by P; otherwise, it is directly sampled in Q.
 // The zero encryption is generated according to the given Ciphertext MetaData.
 func (enc Encryptor) EncryptZero(ct interface{}) (err error) {
+
+	if cti, isCt := ct.(*Ciphertext); isCt {
+		if err = enc.checkParameterBinding(cti.MetaData); err != nil {
+			return fmt.Errorf("cannot EncryptZero: %w", err)
+		}
+	}
+
 	switch key := enc.encKey.(type) {
 	case *SecretKey:
 		return enc.encryptZeroSk(key, ct)
 	case *PublicKey:
+		if enc.crp != nil {
+			return fmt.Errorf("cannot EncryptZero: CRP encryption is only supported with a secret key")
+		}
 		if cti, isCt := ct.(*Ciphertext); isCt && enc.params.PCount() == 0 {
 			return enc.encryptZeroPkNoP(key, cti.Element)
 		}
@@ -201,6 +236,49 @@ func (enc Encryptor) EncryptZeroNew(level int) (ct *Ciphertext) {
 	return
 }
 
+// EncryptCompressedNew encrypts the input plaintext using the stored secret key and
+// returns the result as a CompressedCiphertext, in which the uniform element c1 is
+// represented by the seed it is expanded from instead of being stored, halving the
+// size of the ciphertext on the wire (see CompressedCiphertext.Decompress).
+// The seed must be CiphertextSeedSize bytes of fresh randomness and must not be
+// reused across encryptions. If pt is nil, an encryption of zero at the maximum
+// level is returned.
+// The method returns an error if the stored encryption key is not a secret key,
+// since public-key encryptions have no uniformly distributed element.
+func (enc Encryptor) EncryptCompressedNew(pt *Plaintext, seed []byte) (ct *CompressedCiphertext, err error) {
+
+	if _, isSk := enc.encKey.(*SecretKey); !isSk {
+		return nil, fmt.Errorf("cannot EncryptCompressedNew: compressed encryption is only supported with a secret key")
+	}
+
+	if len(seed) != CiphertextSeedSize {
+		return nil, fmt.Errorf("cannot EncryptCompressedNew: seed must be %d bytes but is %d", CiphertextSeedSize, len(seed))
+	}
+
+	level := enc.params.MaxLevel()
+	if pt != nil {
+		level = pt.Level()
+	}
+
+	ct = NewCompressedCiphertext(enc.params, level)
+	copy(ct.Seed[:], seed)
+
+	ctFull := NewCiphertext(enc.params, 1, level)
+
+	if err = expandCiphertextSeed(enc.params, ct.Seed, ctFull.Value[1]); err != nil {
+		return nil, fmt.Errorf("cannot EncryptCompressedNew: %w", err)
+	}
+
+	if err = enc.WithCRP(ringqp.Poly{Q: ctFull.Value[1]}).Encrypt(pt, ctFull); err != nil {
+		return nil, fmt.Errorf("cannot EncryptCompressedNew: %w", err)
+	}
+
+	*ct.MetaData = *ctFull.MetaData
+	ct.Value[0].Copy(ctFull.Value[0])
+
+	return
+}
+
 func (enc Encryptor) encryptZeroPk(pk *PublicKey, ct interface{}) (err error) {
 
 	var ct0QP, ct1QP ringqp.Poly
@@ -349,7 +427,14 @@ func (enc Encryptor) encryptZeroSk(sk *SecretKey, ct interface{}) (err error) {
 			c1 = enc.buffQ[1]
 		}
 
-		enc.uniformSampler.AtLevel(ct.Level(), -1).Read(ringqp.Poly{Q: c1})
+		if enc.crp != nil {
+			if enc.crp.Q.Level() < ct.Level() {
+				return fmt.Errorf("cannot EncryptZero: CRP level %d is smaller than the ciphertext level %d", enc.crp.Q.Level(), ct.Level())
+			}
+			c1.CopyLvl(ct.Level(), enc.crp.Q)
+		} else {
+			enc.uniformSampler.AtLevel(ct.Level(), -1).Read(ringqp.Poly{Q: c1})
+		}
 
 		if !ct.IsNTT {
 			enc.params.RingQ().AtLevel(ct.Level()).NTT(c1, c1)
@@ -368,7 +453,14 @@ func (enc Encryptor) encryptZeroSk(sk *SecretKey, ct interface{}) (err error) {
 		}
 
 		// ct = (e, a)
-		enc.uniformSampler.AtLevel(ct.LevelQ(), ct.LevelP()).Read(c1)
+		if enc.crp != nil {
+			if enc.crp.Q.Level() < ct.LevelQ() || (ct.LevelP() > -1 && enc.crp.P.Level() < ct.LevelP()) {
+				return fmt.Errorf("cannot EncryptZero: CRP level is smaller than the ciphertext level")
+			}
+			c1.CopyLvl(ct.LevelQ(), ct.LevelP(), *enc.crp)
+		} else {
+			enc.uniformSampler.AtLevel(ct.LevelQ(), ct.LevelP()).Read(c1)
+		}
 
 		if !ct.IsNTT {
 			enc.params.RingQP().AtLevel(ct.LevelQ(), ct.LevelP()).NTT(c1, c1)
@@ -457,7 +549,36 @@ func (enc *Encryptor) WithPRNG(prng sampling.PRNG) *Encryptor {
 		xsSampler:        enc.xsSampler,
 		basisextender:    enc.basisextender,
 		uniformSampler:   ringqp.NewUniformSampler(prng, *enc.params.RingQP()),
+		crp:              enc.crp,
+	}
+}
+
+// WithCRP returns this encryptor with the uniform element c1 of its fresh
+// encryptions of zero fixed to the provided common reference polynomial,
+// instead of sampled. The polynomial must be uniformly distributed, e.g.
+// sampled from the common reference string of an interactive protocol, and is
+// interpreted in the domain (NTT or not) of the destination ciphertext, whose
+// level cannot exceed the one of the polynomial. Parties fixing the same
+// polynomial produce encryptions of zero under a common c1, whose sum is an
+// encryption of zero under the sum of their secret keys. The mode is only
+// supported for secret-key encryption.
+// The returned encryptor isn't safe to use concurrently with the original encryptor.
+func (enc Encryptor) WithCRP(crp ringqp.Poly) *Encryptor {
+	enc.crp = &crp
+	return &enc
+}
+
+// WithCRPSeed returns this encryptor with the uniform element c1 of its fresh
+// encryptions derived from the provided seed (see WithPRNG), so that distinct
+// parties seeded identically produce encryptions of zero with identical c1
+// components, and so that ciphertexts can be transmitted without them and
+// reconstructed from the seed alone.
+func (enc *Encryptor) WithCRPSeed(seed []byte) (*Encryptor, error) {
+	prng, err := sampling.NewKeyedPRNG(seed)
+	if err != nil {
+		return nil, fmt.Errorf("cannot WithCRPSeed: %w", err)
 	}
+	return enc.WithPRNG(prng), nil
 }
 
 func (enc Encryptor) ShallowCopy() *Encryptor {
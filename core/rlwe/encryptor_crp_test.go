@@ -0,0 +1,87 @@
+package rlwe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxdefi/lattice/v5/ring/ringqp"
+	"github.com/luxdefi/lattice/v5/utils/sampling"
+)
+
+func TestEncryptorCRP(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN: 10,
+		LogQ: []int{55, 45},
+		LogP: []int{60},
+	})
+	require.NoError(t, err)
+
+	prng, err := sampling.NewPRNG()
+	require.NoError(t, err)
+
+	ringQP := params.RingQP()
+
+	crp := ringQP.NewPoly()
+	ringqp.NewUniformSampler(prng, *ringQP).Read(crp)
+
+	kgen := NewKeyGenerator(params)
+	sk1 := kgen.GenSecretKeyNew()
+	sk2 := kgen.GenSecretKeyNew()
+
+	enc1 := NewEncryptor(params, sk1).WithCRP(crp)
+	enc2 := NewEncryptor(params, sk2).WithCRP(crp)
+
+	level := params.MaxLevel()
+
+	ct1 := NewCiphertext(params, 1, level)
+	ct2 := NewCiphertext(params, 1, level)
+	require.NoError(t, enc1.EncryptZero(ct1))
+	require.NoError(t, enc2.EncryptZero(ct2))
+
+	// Both encryptions of zero share the CRP as their uniform element.
+	require.True(t, ct1.Value[1].Equal(&crp.Q))
+	require.True(t, ct2.Value[1].Equal(&crp.Q))
+
+	// Their aggregation is an encryption of zero under the sum of the
+	// secret keys.
+	skSum := NewSecretKey(params)
+	ringQP.Add(sk1.Value, sk2.Value, skSum.Value)
+
+	ringQ := params.RingQ().AtLevel(level)
+
+	ctSum := ct1.CopyNew()
+	ringQ.Add(ct1.Value[0], ct2.Value[0], ctSum.Value[0])
+
+	pt := NewDecryptor(params, skSum).DecryptNew(ctSum)
+	if pt.IsNTT {
+		ringQ.INTT(pt.Value, pt.Value)
+	}
+	require.GreaterOrEqual(t, float64(params.LogN()), ringQ.Log2OfStandardDeviation(pt.Value))
+
+	// Encryptors seeded identically derive identical uniform elements,
+	// which advance in lockstep across successive encryptions.
+	seed := []byte{'c', 'r', 'p'}
+
+	encSeed1, err := NewEncryptor(params, sk1).WithCRPSeed(seed)
+	require.NoError(t, err)
+	encSeed2, err := NewEncryptor(params, sk2).WithCRPSeed(seed)
+	require.NoError(t, err)
+
+	require.NoError(t, encSeed1.EncryptZero(ct1))
+	require.NoError(t, encSeed2.EncryptZero(ct2))
+	require.True(t, ct1.Value[1].Equal(&ct2.Value[1]))
+
+	require.NoError(t, encSeed1.EncryptZero(ctSum))
+	require.False(t, ct1.Value[1].Equal(&ctSum.Value[1]))
+
+	// CRP encryption is rejected with a public key, and with a CRP whose
+	// level is smaller than the one of the ciphertext.
+	pk := kgen.GenPublicKeyNew(sk1)
+	require.Error(t, NewEncryptor(params, pk).WithCRP(crp).EncryptZero(ct1))
+
+	crpLow := ringQP.AtLevel(0, params.MaxLevelP()).NewPoly()
+	require.Error(t, enc1.WithCRP(crpLow).EncryptZero(ct1))
+}
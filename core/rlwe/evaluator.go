@@ -17,6 +17,11 @@ type Evaluator struct {
 
 	automorphismIndex map[uint64][]uint64
 
+	// allowKeyComposition enables the realization of rotations whose Galois
+	// key is absent as compositions of available power-of-two rotation keys
+	// (see AllowKeyComposition).
+	allowKeyComposition bool
+
 	BasisExtender *ring.BasisExtender
 	Decomposer    *ring.Decomposer
 }
@@ -86,7 +91,7 @@ func NewEvaluator(params ParameterProvider, evk EvaluationKeySet) (eval *Evaluat
 
 			var err error
 			for _, galEl := range galEls {
-				if AutomorphismIndex[galEl], err = ring.AutomorphismNTTIndex(N, NthRoot, galEl); err != nil {
+				if AutomorphismIndex[galEl], err = ring.AutomorphismNTTIndexShared(N, NthRoot, galEl); err != nil {
 					// Sanity check, this error should not happen.
 					panic(err)
 				}
@@ -103,6 +108,31 @@ func (eval *Evaluator) GetRLWEParameters() *Parameters {
 	return &eval.params
 }
 
+// WarmupGaloisElements precomputes and caches the automorphism tables for a
+// declared set of Galois elements, so that the first automorphism applied
+// with each of them does not pay the table generation cost. The tables are
+// shared process-wide (see ring.AutomorphismNTTIndexShared).
+func (eval *Evaluator) WarmupGaloisElements(galEls ...uint64) (err error) {
+
+	if eval.automorphismIndex == nil {
+		eval.automorphismIndex = map[uint64][]uint64{}
+	}
+
+	N := eval.params.N()
+	NthRoot := eval.params.RingQ().NthRoot()
+
+	for _, galEl := range galEls {
+		if _, ok := eval.automorphismIndex[galEl]; ok {
+			continue
+		}
+		if eval.automorphismIndex[galEl], err = ring.AutomorphismNTTIndexShared(N, NthRoot, galEl); err != nil {
+			return fmt.Errorf("cannot WarmupGaloisElements: %w", err)
+		}
+	}
+
+	return
+}
+
 // CheckAndGetGaloisKey returns an error if the GaloisKey for the given Galois element is missing or the EvaluationKey interface is nil.
 func (eval Evaluator) CheckAndGetGaloisKey(galEl uint64) (evk *GaloisKey, err error) {
 	if eval.EvaluationKeySet != nil {
@@ -118,9 +148,9 @@ func (eval Evaluator) CheckAndGetGaloisKey(galEl uint64) (evk *GaloisKey, err er
 	}
 
 	if _, ok := eval.automorphismIndex[galEl]; !ok {
-		if eval.automorphismIndex[galEl], err = ring.AutomorphismNTTIndex(eval.params.N(), eval.params.RingQ().NthRoot(), galEl); err != nil {
+		if eval.automorphismIndex[galEl], err = ring.AutomorphismNTTIndexShared(eval.params.N(), eval.params.RingQ().NthRoot(), galEl); err != nil {
 			// Sanity check, this error should not happen.
-			panic(err)
+			return nil, Fault(err)
 		}
 	}
 
@@ -140,6 +170,20 @@ func (eval Evaluator) CheckAndGetRelinearizationKey() (evk *RelinearizationKey,
 	return
 }
 
+// checkParameterBinding returns ErrParameterMismatch if an operand is bound to
+// a different parameter set than the evaluator's (see Parameters.Fingerprint).
+// Operands with a zero fingerprint (e.g. deserialized ones) are not checked.
+// Operands MetaData must not be nil.
+func (eval Evaluator) checkParameterBinding(ops ...*Element[ring.Poly]) (err error) {
+	fingerprint := eval.params.Fingerprint()
+	for _, op := range ops {
+		if op.ParameterFingerprint != 0 && fingerprint != 0 && op.ParameterFingerprint != fingerprint {
+			return ErrParameterMismatch
+		}
+	}
+	return
+}
+
 // InitOutputBinaryOp initializes the output Element opOut for receiving the result of a binary operation over
 // op0 and op1. The method also performs the following checks:
 //
@@ -165,6 +209,10 @@ func (eval Evaluator) InitOutputBinaryOp(op0, op1 *Element[ring.Poly], opInTotal
 		return 0, 0, fmt.Errorf("op0, op1 and opOut MetaData cannot be nil")
 	}
 
+	if err = eval.checkParameterBinding(op0, op1, opOut); err != nil {
+		return 0, 0, err
+	}
+
 	degree = utils.Max(op0.Degree(), op1.Degree())
 	degree = utils.Max(degree, opOut.Degree())
 	level = utils.Min(op0.Level(), op1.Level())
@@ -222,6 +270,10 @@ func (eval Evaluator) InitOutputUnaryOp(op0, opOut *Element[ring.Poly]) (degree,
 		return 0, 0, fmt.Errorf("op0 and opOut MetaData cannot be nil")
 	}
 
+	if err = eval.checkParameterBinding(op0, opOut); err != nil {
+		return 0, 0, err
+	}
+
 	if op0.El().IsNTT != eval.params.NTTFlag() {
 		return 0, 0, fmt.Errorf("op0.IsNTT() != %t", eval.params.NTTFlag())
 	} else {
@@ -234,17 +286,30 @@ func (eval Evaluator) InitOutputUnaryOp(op0, opOut *Element[ring.Poly]) (degree,
 	return utils.Max(op0.Degree(), opOut.Degree()), utils.Min(op0.Level(), opOut.Level()), nil
 }
 
+// AllowKeyComposition returns a shallow copy of the receiver Evaluator for which
+// a rotation whose Galois key is absent from the key set is realized, when
+// possible, as a composition of available power-of-two rotation keys (e.g. the
+// keys of a CircuitRotationPlan generated with Decompose set), at the cost of
+// one key-switch, and its noise, per composed component. The receiver and the
+// returned Evaluator share all data structures, including the temporary buffers,
+// and cannot be used concurrently.
+func (eval Evaluator) AllowKeyComposition(allow bool) *Evaluator {
+	eval.allowKeyComposition = allow
+	return &eval
+}
+
 // ShallowCopy creates a shallow copy of this Evaluator in which all the read-only data-structures are
 // shared with the receiver and the temporary buffers are reallocated. The receiver and the returned
 // Evaluators can be used concurrently.
 func (eval Evaluator) ShallowCopy() *Evaluator {
 	return &Evaluator{
-		params:            eval.params,
-		Decomposer:        eval.Decomposer,
-		BasisExtender:     eval.BasisExtender.ShallowCopy(),
-		EvaluatorBuffers:  NewEvaluatorBuffers(eval.params),
-		EvaluationKeySet:  eval.EvaluationKeySet,
-		automorphismIndex: eval.automorphismIndex,
+		params:              eval.params,
+		Decomposer:          eval.Decomposer,
+		BasisExtender:       eval.BasisExtender.ShallowCopy(),
+		EvaluatorBuffers:    NewEvaluatorBuffers(eval.params),
+		EvaluationKeySet:    eval.EvaluationKeySet,
+		automorphismIndex:   eval.automorphismIndex,
+		allowKeyComposition: eval.allowKeyComposition,
 	}
 }
 
@@ -262,7 +327,7 @@ func (eval Evaluator) WithKey(evk EvaluationKeySet) *Evaluator {
 
 		var err error
 		for _, galEl := range galEls {
-			if AutomorphismIndex[galEl], err = ring.AutomorphismNTTIndex(N, NthRoot, galEl); err != nil {
+			if AutomorphismIndex[galEl], err = ring.AutomorphismNTTIndexShared(N, NthRoot, galEl); err != nil {
 				// Sanity check, this error should not happen.
 				panic(err)
 			}
@@ -270,12 +335,13 @@ func (eval Evaluator) WithKey(evk EvaluationKeySet) *Evaluator {
 	}
 
 	return &Evaluator{
-		params:            eval.params,
-		EvaluatorBuffers:  eval.EvaluatorBuffers,
-		Decomposer:        eval.Decomposer,
-		BasisExtender:     eval.BasisExtender,
-		EvaluationKeySet:  evk,
-		automorphismIndex: AutomorphismIndex,
+		params:              eval.params,
+		EvaluatorBuffers:    eval.EvaluatorBuffers,
+		Decomposer:          eval.Decomposer,
+		BasisExtender:       eval.BasisExtender,
+		EvaluationKeySet:    evk,
+		automorphismIndex:   AutomorphismIndex,
+		allowKeyComposition: eval.allowKeyComposition,
 	}
 }
 
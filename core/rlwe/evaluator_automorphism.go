@@ -6,13 +6,17 @@ import (
 	"github.com/luxdefi/lattice/v5/ring"
 	"github.com/luxdefi/lattice/v5/ring/ringqp"
 	"github.com/luxdefi/lattice/v5/utils"
+	"github.com/luxdefi/lattice/v5/utils/metrics"
 )
 
 // Automorphism computes phi(ct), where phi is the map X -> X^galEl. The method requires
-// that the corresponding RotationKey has been added to the Evaluator. The method will
+// that the corresponding RotationKey has been added to the Evaluator, unless key
+// composition is enabled (see AllowKeyComposition). The method will
 // return an error if either ctIn or opOut degree is not equal to 1.
 func (eval Evaluator) Automorphism(ctIn *Ciphertext, galEl uint64, opOut *Ciphertext) (err error) {
 
+	metrics.IncCounter(metrics.Automorphism, 1)
+
 	if ctIn.Degree() != 1 || opOut.Degree() != 1 {
 		return fmt.Errorf("cannot apply Automorphism: input and output Ciphertext must be of degree 1")
 	}
@@ -26,6 +30,9 @@ func (eval Evaluator) Automorphism(ctIn *Ciphertext, galEl uint64, opOut *Cipher
 
 	var evk *GaloisKey
 	if evk, err = eval.CheckAndGetGaloisKey(galEl); err != nil {
+		if eval.allowKeyComposition {
+			return eval.automorphismComposed(ctIn, galEl, opOut)
+		}
 		return fmt.Errorf("cannot apply Automorphism: %w", err)
 	}
 
@@ -59,6 +66,9 @@ func (eval Evaluator) Automorphism(ctIn *Ciphertext, galEl uint64, opOut *Cipher
 // decomposition of its element of degree 1. This decomposition can be obtained with DecomposeNTT.
 // The method requires that the corresponding RotationKey has been added to the Evaluator.
 // The method will return an error if either ctIn or opOut degree is not equal to 1.
+// If the gadget parameters of the Galois key differ from the ones the decomposition was
+// computed for (i.e. a different special-prime set or a non-zero base-two decomposition),
+// the method transparently falls back to the non-hoisted Automorphism.
 func (eval Evaluator) AutomorphismHoisted(level int, ctIn *Ciphertext, c1DecompQP []ringqp.Poly, galEl uint64, opOut *Ciphertext) (err error) {
 
 	if ctIn.Degree() != 1 || opOut.Degree() != 1 {
@@ -74,9 +84,30 @@ func (eval Evaluator) AutomorphismHoisted(level int, ctIn *Ciphertext, c1DecompQ
 
 	var evk *GaloisKey
 	if evk, err = eval.CheckAndGetGaloisKey(galEl); err != nil {
+		if eval.allowKeyComposition {
+			// As for the fallback below, the decomposition buffer used as
+			// scratch by the composed key-switches is preserved.
+			buffDecomp := eval.BuffDecompQP[0].CopyNew()
+			err = eval.automorphismComposed(ctIn, galEl, opOut)
+			eval.BuffDecompQP[0].Copy(*buffDecomp)
+			return err
+		}
 		return fmt.Errorf("cannot apply AutomorphismHoisted: %w", err)
 	}
 
+	// The shared decomposition c1DecompQP is computed for the default special-prime
+	// set and without base-two decomposition; keys with different gadget parameters
+	// cannot consume it and are handled with the non-hoisted key-switch instead.
+	if evk.LevelP() != eval.params.MaxLevelP() || evk.BaseTwoDecomposition != 0 {
+		// The non-hoisted key-switch uses the first decomposition buffer as scratch;
+		// it is saved and restored so that c1DecompQP, which usually aliases it,
+		// remains valid for subsequent hoisted operations with other keys.
+		buffDecomp := eval.BuffDecompQP[0].CopyNew()
+		err = eval.Automorphism(ctIn, galEl, opOut)
+		eval.BuffDecompQP[0].Copy(*buffDecomp)
+		return err
+	}
+
 	opOut.Resize(opOut.Degree(), level)
 
 	ringQ := eval.params.RingQ().AtLevel(level)
@@ -104,15 +135,39 @@ func (eval Evaluator) AutomorphismHoisted(level int, ctIn *Ciphertext, c1DecompQ
 // AutomorphismHoistedLazy is similar to AutomorphismHoisted, except that it returns a ciphertext modulo QP and scaled by P.
 // The method requires that the corresponding RotationKey has been added to the Evaluator.
 // Result NTT domain is returned according to the NTT flag of ctQP.
+// If the gadget parameters of the Galois key differ from the ones the decomposition was
+// computed for (i.e. a different special-prime set or a non-zero base-two decomposition),
+// the method transparently falls back to the non-hoisted key-switch, lifting its result
+// back to the representation modulo QP and scaled by P.
 func (eval Evaluator) AutomorphismHoistedLazy(levelQ int, ctIn *Ciphertext, c1DecompQP []ringqp.Poly, galEl uint64, ctQP *Element[ringqp.Poly]) (err error) {
 
 	var evk *GaloisKey
 	if evk, err = eval.CheckAndGetGaloisKey(galEl); err != nil {
+		if eval.allowKeyComposition {
+			// As for the fallback below, the decomposition buffer used as
+			// scratch by the composed key-switches is preserved.
+			buffDecomp := eval.BuffDecompQP[0].CopyNew()
+			err = eval.automorphismComposedLazy(levelQ, ctIn, galEl, ctQP)
+			eval.BuffDecompQP[0].Copy(*buffDecomp)
+			return err
+		}
 		return fmt.Errorf("cannot apply AutomorphismHoistedLazy: %w", err)
 	}
 
 	levelP := evk.LevelP()
 
+	// The shared decomposition c1DecompQP is computed for the default special-prime
+	// set and without base-two decomposition; keys with different gadget parameters
+	// cannot consume it and are handled with the non-hoisted key-switch instead.
+	if levelP != eval.params.MaxLevelP() || evk.BaseTwoDecomposition != 0 {
+		// As in AutomorphismHoisted, the decomposition buffer used as scratch by the
+		// non-hoisted key-switch is preserved across the fallback.
+		buffDecomp := eval.BuffDecompQP[0].CopyNew()
+		err = eval.automorphismLazy(levelQ, ctIn, galEl, evk, ctQP)
+		eval.BuffDecompQP[0].Copy(*buffDecomp)
+		return err
+	}
+
 	ctTmp := &Element[ringqp.Poly]{}
 	ctTmp.Value = []ringqp.Poly{eval.BuffQP[0], eval.BuffQP[1]}
 	ctTmp.MetaData = ctIn.MetaData
@@ -152,3 +207,48 @@ func (eval Evaluator) AutomorphismHoistedLazy(levelQ int, ctIn *Ciphertext, c1De
 
 	return
 }
+
+// automorphismLazy computes phi(ctIn) modulo QP and scaled by P with the non-hoisted
+// key-switch, for Galois keys whose gadget parameters are incompatible with the shared
+// hoisted decomposition. The key-switch is carried out modulo Q with GadgetProduct and
+// its output is lifted back to the representation expected from AutomorphismHoistedLazy
+// by multiplying it by P: the lifted element is then zero modulo each special prime, so
+// its P part is simply set to zero and the division by P performed by the caller remains
+// exact up to the key-switch rounding.
+func (eval Evaluator) automorphismLazy(levelQ int, ctIn *Ciphertext, galEl uint64, evk *GaloisKey, ctQP *Element[ringqp.Poly]) (err error) {
+
+	levelP := eval.params.MaxLevelP()
+
+	ringQP := eval.params.RingQP().AtLevel(levelQ, levelP)
+
+	ringQ := ringQP.RingQ
+
+	ctTmp := &Ciphertext{Element: Element[ring.Poly]{Value: []ring.Poly{eval.BuffQP[0].Q, eval.BuffQP[1].Q}}}
+	ctTmp.MetaData = ctIn.MetaData
+
+	eval.GadgetProduct(levelQ, ctIn.Value[1], &evk.GadgetCiphertext, ctTmp)
+
+	ringQ.Add(ctTmp.Value[0], ctIn.Value[0], ctTmp.Value[0])
+
+	if levelP > -1 {
+		P := ringQP.RingP.ModulusAtLevel[levelP]
+		ringQ.MulScalarBigint(ctTmp.Value[0], P, ctTmp.Value[0])
+		ringQ.MulScalarBigint(ctTmp.Value[1], P, ctTmp.Value[1])
+	}
+
+	if ctQP.IsNTT {
+		index := eval.automorphismIndex[galEl]
+		ringQ.AutomorphismNTTWithIndex(ctTmp.Value[0], index, ctQP.Value[0].Q)
+		ringQ.AutomorphismNTTWithIndex(ctTmp.Value[1], index, ctQP.Value[1].Q)
+	} else {
+		ringQ.Automorphism(ctTmp.Value[0], galEl, ctQP.Value[0].Q)
+		ringQ.Automorphism(ctTmp.Value[1], galEl, ctQP.Value[1].Q)
+	}
+
+	if levelP > -1 {
+		ctQP.Value[0].P.Zero()
+		ctQP.Value[1].P.Zero()
+	}
+
+	return
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/luxdefi/lattice/v5/ring"
 	"github.com/luxdefi/lattice/v5/utils"
+	"github.com/luxdefi/lattice/v5/utils/metrics"
 )
 
 // ApplyEvaluationKey is a generic method to apply an EvaluationKey on a ciphertext.
@@ -97,7 +98,11 @@ func (eval Evaluator) ApplyEvaluationKey(ctIn *Ciphertext, evk *EvaluationKey, o
 		eval.applyEvaluationKey(level, ctIn, evk, opOut)
 	}
 
+	// The ring-degree switching cases re-encrypt between two parameter sets:
+	// opOut keeps its own parameter binding instead of inheriting ctIn's.
+	fingerprint := opOut.ParameterFingerprint
 	*opOut.MetaData = *ctIn.MetaData
+	opOut.ParameterFingerprint = fingerprint
 
 	return
 }
@@ -124,6 +129,8 @@ func (eval Evaluator) applyEvaluationKey(level int, ctIn *Ciphertext, evk *Evalu
 // is missing.
 func (eval Evaluator) Relinearize(ctIn *Ciphertext, opOut *Ciphertext) (err error) {
 
+	metrics.IncCounter(metrics.Relinearize, 1)
+
 	if ctIn.Degree() != 2 {
 		return fmt.Errorf("cannot relinearize: ctIn.Degree() should be 2 but is %d", ctIn.Degree())
 	}
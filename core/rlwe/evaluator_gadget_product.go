@@ -6,6 +6,7 @@ import (
 	"github.com/luxdefi/lattice/v5/ring"
 	"github.com/luxdefi/lattice/v5/ring/ringqp"
 	"github.com/luxdefi/lattice/v5/utils"
+	"github.com/luxdefi/lattice/v5/utils/metrics"
 )
 
 // GadgetProduct evaluates poly x Gadget -> RLWE where
@@ -96,6 +97,9 @@ func (eval Evaluator) ModDown(levelQ, levelP int, ctQP *Element[ringqp.Poly], ct
 //
 // Result NTT domain is returned according to the NTT flag of ct.
 func (eval Evaluator) GadgetProductLazy(levelQ int, cx ring.Poly, gadgetCt *GadgetCiphertext, ct *Element[ringqp.Poly]) {
+
+	metrics.IncCounter(metrics.KeySwitch, 1)
+
 	if gadgetCt.LevelP() > 0 {
 		eval.gadgetProductMultiplePLazy(levelQ, cx, gadgetCt, ct)
 	} else {
@@ -328,11 +332,14 @@ func (eval Evaluator) GadgetProductHoisted(levelQ int, BuffQPDecompQP []ringqp.P
 // BuffQP2 = dot(BuffQPDecompQ||BuffQPDecompP * gadgetCt[0]) mod QP
 // BuffQP3 = dot(BuffQPDecompQ||BuffQPDecompP * gadgetCt[1]) mod QP
 //
-// BuffQPDecompQP is expected to be in the NTT domain.
+// BuffQPDecompQP is expected to be in the NTT domain and to have been computed with
+// DecomposeNTT for the special-prime set of gadgetCt (i.e. with levelP = gadgetCt.LevelP()).
 //
 // Result NTT domain is returned according to the NTT flag of ct.
 func (eval Evaluator) GadgetProductHoistedLazy(levelQ int, BuffQPDecompQP []ringqp.Poly, gadgetCt *GadgetCiphertext, ct *Element[ringqp.Poly]) {
 
+	metrics.IncCounter(metrics.KeySwitch, 1)
+
 	// Sanity check for invalid parameters.
 	if gadgetCt.BaseTwoDecomposition != 0 {
 		panic(fmt.Errorf("cannot GadgetProductHoistedLazy: method is unsupported for BaseTwoDecomposition != 0"))
@@ -0,0 +1,59 @@
+package rlwe
+
+import (
+	"fmt"
+
+	"github.com/luxdefi/lattice/v5/utils"
+)
+
+// NegacyclicShift multiplies op0 by the monomial X^k and returns the result
+// in opOut. Unlike an automorphism, the multiplication by a public monomial
+// acts on the ciphertext polynomials only and leaves the secret untouched, so
+// the operation requires no Galois key and adds no noise.
+//
+// For a coefficient-encoded (non-batched) plaintext m(X), the operation
+// shifts the coefficients by k positions towards the higher degrees, the
+// coefficients wrapping around negacyclically: a coefficient shifted past
+// X^{N-1} reappears negated at the bottom. For batched plaintexts the
+// operation is not a slot permutation.
+func (eval Evaluator) NegacyclicShift(op0 *Ciphertext, k int, opOut *Ciphertext) (err error) {
+
+	if op0.Degree() != opOut.Degree() {
+		return fmt.Errorf("cannot NegacyclicShift: op0 and opOut degrees do not match: %d != %d", op0.Degree(), opOut.Degree())
+	}
+
+	level := utils.Min(op0.Level(), opOut.Level())
+
+	opOut.Resize(opOut.Degree(), level)
+
+	ringQ := eval.params.RingQ().AtLevel(level)
+
+	if op0.IsNTT {
+
+		// X^k is sparse in the coefficient domain but dense in the NTT
+		// domain, where the multiplication becomes point-wise.
+		xi := ringQ.NewMonomialXi(k)
+		ringQ.NTT(xi, xi)
+		ringQ.MForm(xi, xi)
+
+		for i := range op0.Value {
+			ringQ.MulCoeffsMontgomery(op0.Value[i], xi, opOut.Value[i])
+		}
+
+	} else {
+		for i := range op0.Value {
+			ringQ.MultByMonomial(op0.Value[i], k, opOut.Value[i])
+		}
+	}
+
+	*opOut.MetaData = *op0.MetaData
+
+	return
+}
+
+// NegacyclicShiftNew multiplies op0 by the monomial X^k and returns the
+// result in a new ciphertext (see NegacyclicShift).
+func (eval Evaluator) NegacyclicShiftNew(op0 *Ciphertext, k int) (opOut *Ciphertext, err error) {
+	opOut = NewCiphertext(eval.params, op0.Degree(), op0.Level())
+	return opOut, eval.NegacyclicShift(op0, k, opOut)
+}
@@ -0,0 +1,138 @@
+package rlwe
+
+import (
+	"fmt"
+	"math"
+)
+
+// EstimateKeySwitchingNoise returns a heuristic estimate of the log2 of the
+// standard deviation of the noise added by one key switch at levelQ with an
+// EvaluationKey generated with the given levelP and base-2 decomposition.
+//
+// The estimate follows the usual hybrid gadget key-switching analysis: each of
+// the d decomposed digits contributes a fresh-noise term scaled by the digit
+// magnitude, and the result is divided by the auxiliary modulus P, i.e.
+//
+//	log2(std) ≈ log2(sigma) + log2(d*N)/2 + max(0, log2(maxDigit) - log2(P)),
+//
+// where the digit magnitude is 2^w for a base-2 decomposition with limbs of w
+// bits, and the product of the levelP+1 moduli spanned by the digit otherwise.
+// The estimate is floored at the mod-down rounding noise and is a guide for
+// choosing a decomposition, not a proven bound.
+func EstimateKeySwitchingNoise(params Parameters, levelQ, levelP, BaseTwoDecomposition int) float64 {
+
+	N := float64(params.N())
+	sigma := params.NoiseFreshSK()
+
+	qi := params.Q()[:levelQ+1]
+	pi := params.P()
+
+	var logP float64
+	for _, p := range pi[:levelP+1] {
+		logP += math.Log2(float64(p))
+	}
+
+	var logNoise float64
+
+	if BaseTwoDecomposition > 0 && levelP < 1 {
+
+		// Base-2 decomposition: one digit of magnitude 2^w per limb.
+		var limbs float64
+		for _, base := range params.BaseTwoDecompositionVectorSize(levelQ, levelP, BaseTwoDecomposition) {
+			limbs += float64(base)
+		}
+
+		logNoise = math.Log2(sigma) + 0.5*math.Log2(limbs*N) + float64(BaseTwoDecomposition-1) - logP
+
+	} else {
+
+		// RNS decomposition: one digit per group of levelP+1 moduli.
+		d := params.BaseRNSDecompositionVectorSize(levelQ, levelP)
+
+		groupSize := levelP + 1
+		if levelP == -1 {
+			groupSize = 1
+		}
+
+		var maxLogD float64
+		for i := 0; i < d; i++ {
+			var logD float64
+			for j := i * groupSize; j < (i+1)*groupSize && j < levelQ+1; j++ {
+				logD += math.Log2(float64(qi[j]))
+			}
+			maxLogD = math.Max(maxLogD, logD)
+		}
+
+		logNoise = math.Log2(sigma) + 0.5*math.Log2(float64(d)*N) + math.Max(0, maxLogD-logP)
+	}
+
+	// Mod-down rounding noise floor.
+	return math.Max(logNoise, 0.5*math.Log2(N/12.0))
+}
+
+// EvaluationKeyApproximateSize returns the approximate serialized size in
+// bytes of an EvaluationKey generated at levelQ with the given levelP and
+// base-2 decomposition (coefficient data only, ignoring the encoding
+// overhead).
+func EvaluationKeyApproximateSize(params Parameters, levelQ, levelP, BaseTwoDecomposition int) (size int) {
+	for _, base := range params.BaseTwoDecompositionVectorSize(levelQ, levelP, BaseTwoDecomposition) {
+		size += base * 2 * (levelQ + levelP + 2) * params.N() * 8
+	}
+	return
+}
+
+// OptimizeEvaluationKeyParameters returns the EvaluationKeyParameters with
+// the smallest approximate key size among those whose estimated key-switching
+// noise at the maximum level (see EstimateKeySwitchingNoise) does not exceed
+// logNoiseBudget, searching over the admissible levelP and base-2
+// decompositions. Ties are broken in favor of the lower noise. The chosen
+// trade-off can be inspected with EstimateKeySwitchingNoise and
+// EvaluationKeyApproximateSize. An error is returned if no decomposition
+// meets the budget.
+func OptimizeEvaluationKeyParameters(params Parameters, logNoiseBudget float64) (evkParams EvaluationKeyParameters, err error) {
+
+	levelQ := params.MaxLevelQ()
+
+	var found bool
+	var bestLevelP, bestBase2, bestSize int
+	var bestNoise float64
+
+	lowestNoise := math.Inf(1)
+
+	consider := func(levelP, base2 int) {
+
+		noise := EstimateKeySwitchingNoise(params, levelQ, levelP, base2)
+
+		lowestNoise = math.Min(lowestNoise, noise)
+
+		if noise > logNoiseBudget {
+			return
+		}
+
+		size := EvaluationKeyApproximateSize(params, levelQ, levelP, base2)
+
+		if !found || size < bestSize || (size == bestSize && noise < bestNoise) {
+			found = true
+			bestLevelP, bestBase2, bestSize, bestNoise = levelP, base2, size, noise
+		}
+	}
+
+	for levelP := params.MaxLevelP(); levelP >= -1; levelP-- {
+
+		consider(levelP, 0)
+
+		// The base-2 decomposition is only effective for levelP < 1
+		// (see Parameters.BaseTwoDecompositionVectorSize).
+		if levelP < 1 {
+			for base2 := 1; base2 < 63; base2++ {
+				consider(levelP, base2)
+			}
+		}
+	}
+
+	if !found {
+		return evkParams, fmt.Errorf("cannot OptimizeEvaluationKeyParameters: no gadget decomposition meets the noise budget %f (lowest achievable is %f)", logNoiseBudget, lowestNoise)
+	}
+
+	return EvaluationKeyParameters{LevelQ: &levelQ, LevelP: &bestLevelP, BaseTwoDecomposition: &bestBase2}, nil
+}
@@ -1,7 +1,6 @@
 package rlwe
 
 import (
-	"bufio"
 	"fmt"
 	"io"
 
@@ -110,7 +109,7 @@ func (ct GadgetCiphertext) WriteTo(w io.Writer) (n int64, err error) {
 		return n + inc, err
 
 	default:
-		return ct.WriteTo(bufio.NewWriter(w))
+		return buffer.WriteWithPool(w, ct.WriteTo)
 	}
 }
 
@@ -142,7 +141,7 @@ func (ct *GadgetCiphertext) ReadFrom(r io.Reader) (n int64, err error) {
 		return n + inc, err
 
 	default:
-		return ct.ReadFrom(bufio.NewReader(r))
+		return buffer.ReadWithPool(r, ct.ReadFrom)
 	}
 }
 
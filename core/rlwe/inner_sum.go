@@ -44,7 +44,7 @@ func (eval Evaluator) InnerSum(ctIn *Ciphertext, batchSize, n int, opOut *Cipher
 	// Sanity check, this error should not happen unless the
 	// evaluator's buffer thave been improperly tempered with.
 	if err != nil {
-		panic(err)
+		return Fault(err)
 	}
 
 	ctInNTT.MetaData = &MetaData{}
@@ -81,7 +81,7 @@ func (eval Evaluator) InnerSum(ctIn *Ciphertext, batchSize, n int, opOut *Cipher
 		// Sanity check, this error should not happen unless the
 		// evaluator's buffer thave been improperly tempered with.
 		if err != nil {
-			panic(err)
+			return Fault(err)
 		}
 
 		cQ.MetaData = ctInNTT.MetaData
@@ -224,7 +224,7 @@ func (eval Evaluator) InnerFunction(ctIn *Ciphertext, batchSize, n int, f func(a
 		// Sanity check, this error should not happen unless the
 		// evaluator's buffer thave been improperly tempered with.
 		if err != nil {
-			panic(err)
+			return Fault(err)
 		}
 
 		// Buffer mod Q
@@ -234,7 +234,7 @@ func (eval Evaluator) InnerFunction(ctIn *Ciphertext, batchSize, n int, f func(a
 		// Sanity check, this error should not happen unless the
 		// evaluator's buffer thave been improperly tempered with.
 		if err != nil {
-			panic(err)
+			return Fault(err)
 		}
 
 		state := false
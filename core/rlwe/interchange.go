@@ -0,0 +1,193 @@
+package rlwe
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// ParametersInterchangeVersion is the version of the interchange encoding
+// produced by this package. Parsers reject envelopes of any other version.
+const ParametersInterchangeVersion = 1
+
+// InterchangeSchemeRLWE is the scheme identifier of an envelope carrying a
+// generic rlwe.ParametersLiteral. Scheme packages use their own identifiers
+// (e.g. "CKKS", "BGV") for literals carrying scheme-specific fields such as
+// the plaintext modulus.
+const InterchangeSchemeRLWE = "RLWE"
+
+// ParametersInterchange is the canonical, versioned interchange envelope for
+// a parameters literal, meant to be exchanged (and signed) between parties
+// that must agree on a parameter set. Exactly one of Name and Literal is
+// set: Name references a set of the registry (see
+// RegisterInterchangeLiteral), while Literal carries the full literal
+// inline.
+type ParametersInterchange struct {
+	Version int
+	Scheme  string
+	Name    string          `json:",omitempty"`
+	Literal json.RawMessage `json:",omitempty"`
+}
+
+var (
+	interchangeRegistryMu sync.RWMutex
+	interchangeRegistry   = map[string]json.RawMessage{}
+)
+
+func interchangeKey(scheme, name string) string {
+	return scheme + "/" + name
+}
+
+// RegisterInterchangeLiteral registers the literal of the given scheme under
+// the given name, making it referenceable by name in interchange envelopes.
+// Registering the same name twice is an error unless the literals have the
+// same encoding.
+func RegisterInterchangeLiteral(scheme, name string, literal interface{}) (err error) {
+
+	if scheme == "" || name == "" {
+		return fmt.Errorf("cannot RegisterInterchangeLiteral: scheme and name must be non-empty")
+	}
+
+	raw, err := json.Marshal(literal)
+	if err != nil {
+		return fmt.Errorf("cannot RegisterInterchangeLiteral: %w", err)
+	}
+
+	interchangeRegistryMu.Lock()
+	defer interchangeRegistryMu.Unlock()
+
+	key := interchangeKey(scheme, name)
+
+	if prev, ok := interchangeRegistry[key]; ok && !bytes.Equal(prev, raw) {
+		return fmt.Errorf("cannot RegisterInterchangeLiteral: %q is already registered with a different literal", key)
+	}
+
+	interchangeRegistry[key] = raw
+
+	return
+}
+
+// MarshalInterchangeNamed returns the interchange envelope referencing the
+// registered literal of the given scheme and name, i.e. the smallest blob
+// two parties sharing the registry can exchange.
+func MarshalInterchangeNamed(scheme, name string) (data []byte, err error) {
+
+	interchangeRegistryMu.RLock()
+	_, ok := interchangeRegistry[interchangeKey(scheme, name)]
+	interchangeRegistryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("cannot MarshalInterchangeNamed: no literal registered under %q", interchangeKey(scheme, name))
+	}
+
+	return json.Marshal(ParametersInterchange{
+		Version: ParametersInterchangeVersion,
+		Scheme:  scheme,
+		Name:    name,
+	})
+}
+
+// MarshalInterchange returns the canonical interchange encoding of the
+// literal: the literal is validated and normalized by instantiating the
+// parameters, so that two literals describing the same parameter set (e.g.
+// through Q or through LogQ) produce identical bytes, making the blob
+// suitable for signing and byte-level comparison.
+func (p ParametersLiteral) MarshalInterchange() (data []byte, err error) {
+
+	params, err := NewParametersFromLiteral(p)
+	if err != nil {
+		return nil, fmt.Errorf("cannot MarshalInterchange: %w", err)
+	}
+
+	raw, err := json.Marshal(params.ParametersLiteral())
+	if err != nil {
+		return nil, fmt.Errorf("cannot MarshalInterchange: %w", err)
+	}
+
+	return json.Marshal(ParametersInterchange{
+		Version: ParametersInterchangeVersion,
+		Scheme:  InterchangeSchemeRLWE,
+		Literal: raw,
+	})
+}
+
+// UnmarshalInterchange strictly parses an interchange envelope: unknown or
+// trailing fields, unsupported versions and ambiguous envelopes are
+// rejected, and a Name reference is resolved against the registry, so that
+// the returned envelope always carries the literal inline.
+func UnmarshalInterchange(data []byte) (pi ParametersInterchange, err error) {
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	if err = dec.Decode(&pi); err != nil {
+		return pi, fmt.Errorf("cannot UnmarshalInterchange: %w", err)
+	}
+
+	if dec.More() {
+		return pi, fmt.Errorf("cannot UnmarshalInterchange: trailing data after the envelope")
+	}
+
+	if pi.Version != ParametersInterchangeVersion {
+		return pi, fmt.Errorf("cannot UnmarshalInterchange: unsupported version %d (supported: %d)", pi.Version, ParametersInterchangeVersion)
+	}
+
+	if pi.Scheme == "" {
+		return pi, fmt.Errorf("cannot UnmarshalInterchange: missing scheme")
+	}
+
+	if (pi.Name == "") == (len(pi.Literal) == 0) {
+		return pi, fmt.Errorf("cannot UnmarshalInterchange: exactly one of Name and Literal must be set")
+	}
+
+	if pi.Name != "" {
+
+		interchangeRegistryMu.RLock()
+		raw, ok := interchangeRegistry[interchangeKey(pi.Scheme, pi.Name)]
+		interchangeRegistryMu.RUnlock()
+
+		if !ok {
+			return pi, fmt.Errorf("cannot UnmarshalInterchange: no literal registered under %q", interchangeKey(pi.Scheme, pi.Name))
+		}
+
+		pi.Literal = raw
+	}
+
+	return
+}
+
+// DecodeLiteral decodes the literal of the envelope into the given scheme
+// literal, e.g. a *ParametersLiteral for the RLWE scheme.
+func (pi ParametersInterchange) DecodeLiteral(literal interface{}) (err error) {
+	if err = json.Unmarshal(pi.Literal, literal); err != nil {
+		return fmt.Errorf("cannot DecodeLiteral: %w", err)
+	}
+	return
+}
+
+// ParametersFromInterchange parses an interchange envelope of the RLWE
+// scheme and instantiates its parameters, validating the literal in the
+// process.
+func ParametersFromInterchange(data []byte) (params Parameters, err error) {
+
+	pi, err := UnmarshalInterchange(data)
+	if err != nil {
+		return Parameters{}, fmt.Errorf("cannot ParametersFromInterchange: %w", err)
+	}
+
+	if pi.Scheme != InterchangeSchemeRLWE {
+		return Parameters{}, fmt.Errorf("cannot ParametersFromInterchange: envelope carries scheme %q, expected %q", pi.Scheme, InterchangeSchemeRLWE)
+	}
+
+	var p ParametersLiteral
+	if err = pi.DecodeLiteral(&p); err != nil {
+		return Parameters{}, fmt.Errorf("cannot ParametersFromInterchange: %w", err)
+	}
+
+	if params, err = NewParametersFromLiteral(p); err != nil {
+		return Parameters{}, fmt.Errorf("cannot ParametersFromInterchange: %w", err)
+	}
+
+	return
+}
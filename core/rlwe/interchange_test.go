@@ -0,0 +1,84 @@
+package rlwe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParametersInterchange(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	litLog := ParametersLiteral{
+		LogN: 10,
+		LogQ: []int{55, 45},
+		LogP: []int{60},
+	}
+
+	params, err := NewParametersFromLiteral(litLog)
+	require.NoError(t, err)
+
+	t.Run("Canonical", func(t *testing.T) {
+
+		// The same parameter set specified through LogQ or through the
+		// explicit moduli encodes to identical bytes.
+		data, err := litLog.MarshalInterchange()
+		require.NoError(t, err)
+
+		dataExplicit, err := params.ParametersLiteral().MarshalInterchange()
+		require.NoError(t, err)
+
+		require.Equal(t, data, dataExplicit)
+
+		paramsHave, err := ParametersFromInterchange(data)
+		require.NoError(t, err)
+		require.True(t, params.Equal(&paramsHave))
+	})
+
+	t.Run("StrictParser", func(t *testing.T) {
+
+		data, err := litLog.MarshalInterchange()
+		require.NoError(t, err)
+
+		_, err = UnmarshalInterchange(append(data, data...))
+		require.Error(t, err)
+
+		_, err = UnmarshalInterchange([]byte(`{"Version":1,"Scheme":"RLWE","Literal":{"LogN":10},"Extra":0}`))
+		require.Error(t, err)
+
+		_, err = UnmarshalInterchange([]byte(`{"Version":2,"Scheme":"RLWE","Literal":{"LogN":10}}`))
+		require.Error(t, err)
+
+		_, err = UnmarshalInterchange([]byte(`{"Version":1,"Literal":{"LogN":10}}`))
+		require.Error(t, err)
+
+		_, err = UnmarshalInterchange([]byte(`{"Version":1,"Scheme":"RLWE"}`))
+		require.Error(t, err)
+
+		_, err = UnmarshalInterchange([]byte(`{"Version":1,"Scheme":"RLWE","Name":"a","Literal":{"LogN":10}}`))
+		require.Error(t, err)
+	})
+
+	t.Run("Registry", func(t *testing.T) {
+
+		require.NoError(t, RegisterInterchangeLiteral(InterchangeSchemeRLWE, "test-insecure-logn10", params.ParametersLiteral()))
+
+		// Re-registering the same literal is idempotent, a different one is
+		// rejected.
+		require.NoError(t, RegisterInterchangeLiteral(InterchangeSchemeRLWE, "test-insecure-logn10", params.ParametersLiteral()))
+		require.Error(t, RegisterInterchangeLiteral(InterchangeSchemeRLWE, "test-insecure-logn10", litLog))
+
+		data, err := MarshalInterchangeNamed(InterchangeSchemeRLWE, "test-insecure-logn10")
+		require.NoError(t, err)
+
+		paramsHave, err := ParametersFromInterchange(data)
+		require.NoError(t, err)
+		require.True(t, params.Equal(&paramsHave))
+
+		_, err = MarshalInterchangeNamed(InterchangeSchemeRLWE, "unknown")
+		require.Error(t, err)
+
+		_, err = UnmarshalInterchange([]byte(`{"Version":1,"Scheme":"RLWE","Name":"unknown"}`))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,374 @@
+package rlwe
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/luxdefi/lattice/v5/utils/buffer"
+)
+
+const (
+	// keyArchiveMagic identifies the key archive format ("RLWEKEYS" in ASCII).
+	keyArchiveMagic uint64 = 0x524c57454b455953
+	// keyArchiveVersion is the current version of the key archive format.
+	keyArchiveVersion uint64 = 1
+)
+
+// KeyArchiveEntryType tags the type of a key stored in a KeyArchive.
+type KeyArchiveEntryType uint64
+
+const (
+	// KeyArchiveEntryPublicKey tags a PublicKey entry.
+	KeyArchiveEntryPublicKey KeyArchiveEntryType = iota + 1
+	// KeyArchiveEntryRelinearizationKey tags a RelinearizationKey entry.
+	KeyArchiveEntryRelinearizationKey
+	// KeyArchiveEntryGaloisKey tags a GaloisKey entry, identified by its
+	// Galois element.
+	KeyArchiveEntryGaloisKey
+	// KeyArchiveEntryEvaluationKey tags a generic EvaluationKey entry (e.g. a
+	// bootstrapping ring-switching key), identified by a caller-chosen ID.
+	KeyArchiveEntryEvaluationKey
+)
+
+// KeyArchiveEntry describes one key stored in a KeyArchive: its type, its
+// identifier (the Galois element for Galois keys, the caller-chosen ID for
+// generic evaluation keys, zero otherwise), the fingerprint of the parameter
+// set it was generated under (see Parameters.Fingerprint) and its location in
+// the archive.
+type KeyArchiveEntry struct {
+	Type        KeyArchiveEntryType
+	ID          uint64
+	Fingerprint uint64
+	Offset      int64
+	Size        int64
+}
+
+// keyArchiveHeaderSize returns the serialized size in bytes of the archive
+// header (magic, version and entry count) followed by the index.
+func keyArchiveHeaderSize(entries int) int {
+	return 3*8 + entries*5*8
+}
+
+type keyArchiveEntry struct {
+	KeyArchiveEntry
+	key interface {
+		io.WriterTo
+		BinarySize() int
+	}
+}
+
+// KeyArchive bundles public, relinearization, Galois and generic evaluation
+// keys (e.g. bootstrapping keys) into a single streamable file: the header and
+// index are written first, so the archive can be produced in one pass and
+// individual keys can later be extracted with a KeyArchiveReader without
+// reading the whole file.
+type KeyArchive struct {
+	entries []keyArchiveEntry
+}
+
+// NewKeyArchive returns an empty KeyArchive.
+func NewKeyArchive() *KeyArchive {
+	return &KeyArchive{}
+}
+
+func (ka *KeyArchive) add(typ KeyArchiveEntryType, id uint64, params ParameterProvider, key interface {
+	io.WriterTo
+	BinarySize() int
+}) {
+	ka.entries = append(ka.entries, keyArchiveEntry{
+		KeyArchiveEntry: KeyArchiveEntry{
+			Type:        typ,
+			ID:          id,
+			Fingerprint: params.GetRLWEParameters().Fingerprint(),
+			Size:        int64(key.BinarySize()),
+		},
+		key: key,
+	})
+}
+
+// AddPublicKey adds pk, generated under params, to the archive.
+func (ka *KeyArchive) AddPublicKey(params ParameterProvider, pk *PublicKey) {
+	ka.add(KeyArchiveEntryPublicKey, 0, params, pk)
+}
+
+// AddRelinearizationKey adds rlk, generated under params, to the archive.
+func (ka *KeyArchive) AddRelinearizationKey(params ParameterProvider, rlk *RelinearizationKey) {
+	ka.add(KeyArchiveEntryRelinearizationKey, 0, params, rlk)
+}
+
+// AddGaloisKeys adds gks, generated under params, to the archive, indexed by
+// their Galois elements.
+func (ka *KeyArchive) AddGaloisKeys(params ParameterProvider, gks ...*GaloisKey) {
+	for _, gk := range gks {
+		ka.add(KeyArchiveEntryGaloisKey, gk.GaloisElement, params, gk)
+	}
+}
+
+// AddEvaluationKey adds evk, generated under params, to the archive under the
+// caller-chosen ID, e.g. a bootstrapping ring-switching key.
+func (ka *KeyArchive) AddEvaluationKey(params ParameterProvider, id uint64, evk *EvaluationKey) {
+	ka.add(KeyArchiveEntryEvaluationKey, id, params, evk)
+}
+
+// BinarySize returns the serialized size of the archive in bytes.
+func (ka *KeyArchive) BinarySize() (size int) {
+	size = keyArchiveHeaderSize(len(ka.entries))
+	for _, entry := range ka.entries {
+		size += int(entry.Size)
+	}
+	return
+}
+
+// WriteTo writes the archive on an io.Writer. It implements the io.WriterTo
+// interface, and will write exactly object.BinarySize() bytes on w.
+//
+// Unless w implements the buffer.Writer interface (see lattice/utils/buffer/writer.go),
+// it will be wrapped into a bufio.Writer. Since this requires allocations, it
+// is preferable to pass a buffer.Writer directly:
+//
+//   - When writing multiple times to a io.Writer, it is preferable to first wrap the
+//     io.Writer in a pre-allocated bufio.Writer.
+//   - When writing to a pre-allocated var b []byte, it is preferable to pass
+//     buffer.NewBuffer(b) as w (see lattice/utils/buffer/buffer.go).
+func (ka *KeyArchive) WriteTo(w io.Writer) (n int64, err error) {
+	switch w := w.(type) {
+	case buffer.Writer:
+
+		var inc int64
+
+		for _, c := range []uint64{keyArchiveMagic, keyArchiveVersion, uint64(len(ka.entries))} {
+			if inc, err = buffer.WriteUint64(w, c); err != nil {
+				return n + inc, err
+			}
+			n += inc
+		}
+
+		offset := int64(keyArchiveHeaderSize(len(ka.entries)))
+
+		for _, entry := range ka.entries {
+			for _, c := range []uint64{uint64(entry.Type), entry.ID, entry.Fingerprint, uint64(offset), uint64(entry.Size)} {
+				if inc, err = buffer.WriteUint64(w, c); err != nil {
+					return n + inc, err
+				}
+				n += inc
+			}
+			offset += entry.Size
+		}
+
+		for _, entry := range ka.entries {
+
+			if inc, err = entry.key.WriteTo(w); err != nil {
+				return n + inc, err
+			}
+
+			if inc != entry.Size {
+				return n + inc, fmt.Errorf("cannot WriteTo: entry of type %d wrote %d bytes instead of %d", entry.Type, inc, entry.Size)
+			}
+
+			n += inc
+		}
+
+		return n, w.Flush()
+
+	default:
+		return buffer.WriteWithPool(w, ka.WriteTo)
+	}
+}
+
+// MarshalBinary encodes the object into a binary form on a newly allocated slice of bytes.
+func (ka *KeyArchive) MarshalBinary() (p []byte, err error) {
+	buf := buffer.NewBufferSize(ka.BinarySize())
+	_, err = ka.WriteTo(buf)
+	return buf.Bytes(), err
+}
+
+// KeyArchiveReader provides selective extraction of the keys stored in a
+// KeyArchive: opening it reads only the header and index, and each extraction
+// seeks to and reads only the requested entry. Entries whose fingerprint does
+// not match the parameter set the reader was opened with fail with
+// ErrParameterMismatch (the check is skipped when either fingerprint is zero,
+// e.g. for archives holding keys under several parameter sets, which should be
+// opened once per parameter set).
+//
+// A KeyArchiveReader is not safe for concurrent use, as extractions seek on
+// the shared underlying reader.
+type KeyArchiveReader struct {
+	r           io.ReadSeeker
+	fingerprint uint64
+	entries     []KeyArchiveEntry
+}
+
+// OpenKeyArchive reads the header and index of the archive in r and returns a
+// KeyArchiveReader extracting keys for the given parameter set.
+func OpenKeyArchive(r io.ReadSeeker, params ParameterProvider) (kar *KeyArchiveReader, err error) {
+
+	if _, err = r.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("cannot OpenKeyArchive: %w", err)
+	}
+
+	br := bufio.NewReader(r)
+
+	var magic, version, count uint64
+
+	if _, err = buffer.ReadUint64(br, &magic); err != nil {
+		return nil, fmt.Errorf("cannot OpenKeyArchive: %w", err)
+	}
+
+	if magic != keyArchiveMagic {
+		return nil, fmt.Errorf("cannot OpenKeyArchive: invalid magic number")
+	}
+
+	if _, err = buffer.ReadUint64(br, &version); err != nil {
+		return nil, fmt.Errorf("cannot OpenKeyArchive: %w", err)
+	}
+
+	if version != keyArchiveVersion {
+		return nil, fmt.Errorf("cannot OpenKeyArchive: unsupported version %d", version)
+	}
+
+	if _, err = buffer.ReadUint64(br, &count); err != nil {
+		return nil, fmt.Errorf("cannot OpenKeyArchive: %w", err)
+	}
+
+	entries := make([]KeyArchiveEntry, count)
+
+	for i := range entries {
+
+		var typ, id, fingerprint, offset, size uint64
+
+		for _, c := range []*uint64{&typ, &id, &fingerprint, &offset, &size} {
+			if _, err = buffer.ReadUint64(br, c); err != nil {
+				return nil, fmt.Errorf("cannot OpenKeyArchive: %w", err)
+			}
+		}
+
+		entries[i] = KeyArchiveEntry{
+			Type:        KeyArchiveEntryType(typ),
+			ID:          id,
+			Fingerprint: fingerprint,
+			Offset:      int64(offset),
+			Size:        int64(size),
+		}
+	}
+
+	return &KeyArchiveReader{
+		r:           r,
+		fingerprint: params.GetRLWEParameters().Fingerprint(),
+		entries:     entries,
+	}, nil
+}
+
+// Entries returns a copy of the archive index.
+func (kar *KeyArchiveReader) Entries() []KeyArchiveEntry {
+	entries := make([]KeyArchiveEntry, len(kar.entries))
+	copy(entries, kar.entries)
+	return entries
+}
+
+// GaloisElements returns the Galois elements of the Galois keys stored in the
+// archive, in index order.
+func (kar *KeyArchiveReader) GaloisElements() (galEls []uint64) {
+	for _, entry := range kar.entries {
+		if entry.Type == KeyArchiveEntryGaloisKey {
+			galEls = append(galEls, entry.ID)
+		}
+	}
+	return
+}
+
+func (kar *KeyArchiveReader) readEntry(typ KeyArchiveEntryType, id uint64, key io.ReaderFrom) error {
+
+	for _, entry := range kar.entries {
+
+		if entry.Type != typ || entry.ID != id {
+			continue
+		}
+
+		if entry.Fingerprint != 0 && kar.fingerprint != 0 && entry.Fingerprint != kar.fingerprint {
+			return fmt.Errorf("cannot read entry: %w", ErrParameterMismatch)
+		}
+
+		if _, err := kar.r.Seek(entry.Offset, io.SeekStart); err != nil {
+			return fmt.Errorf("cannot read entry: %w", err)
+		}
+
+		n, err := buffer.ReadWithPool(io.LimitReader(kar.r, entry.Size), key.ReadFrom)
+		if err != nil {
+			return fmt.Errorf("cannot read entry: %w", err)
+		}
+
+		if n != entry.Size {
+			return fmt.Errorf("cannot read entry: read %d bytes instead of %d", n, entry.Size)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("no entry of type %d with ID %d", typ, id)
+}
+
+// PublicKey extracts the public key from the archive.
+func (kar *KeyArchiveReader) PublicKey() (pk *PublicKey, err error) {
+	pk = new(PublicKey)
+	if err = kar.readEntry(KeyArchiveEntryPublicKey, 0, pk); err != nil {
+		return nil, fmt.Errorf("cannot PublicKey: %w", err)
+	}
+	return
+}
+
+// RelinearizationKey extracts the relinearization key from the archive.
+func (kar *KeyArchiveReader) RelinearizationKey() (rlk *RelinearizationKey, err error) {
+	rlk = new(RelinearizationKey)
+	if err = kar.readEntry(KeyArchiveEntryRelinearizationKey, 0, rlk); err != nil {
+		return nil, fmt.Errorf("cannot RelinearizationKey: %w", err)
+	}
+	return
+}
+
+// GaloisKey extracts the Galois key for the given Galois element from the
+// archive.
+func (kar *KeyArchiveReader) GaloisKey(galEl uint64) (gk *GaloisKey, err error) {
+	gk = new(GaloisKey)
+	if err = kar.readEntry(KeyArchiveEntryGaloisKey, galEl, gk); err != nil {
+		return nil, fmt.Errorf("cannot GaloisKey: %w", err)
+	}
+	return
+}
+
+// EvaluationKey extracts the generic evaluation key stored under the given ID
+// from the archive.
+func (kar *KeyArchiveReader) EvaluationKey(id uint64) (evk *EvaluationKey, err error) {
+	evk = new(EvaluationKey)
+	if err = kar.readEntry(KeyArchiveEntryEvaluationKey, id, evk); err != nil {
+		return nil, fmt.Errorf("cannot EvaluationKey: %w", err)
+	}
+	return
+}
+
+// MemEvaluationKeySet extracts the relinearization key (if present) and all
+// the Galois keys from the archive and packages them into a
+// MemEvaluationKeySet, ready to back an Evaluator.
+func (kar *KeyArchiveReader) MemEvaluationKeySet() (evk *MemEvaluationKeySet, err error) {
+
+	var rlk *RelinearizationKey
+	for _, entry := range kar.entries {
+		if entry.Type == KeyArchiveEntryRelinearizationKey {
+			if rlk, err = kar.RelinearizationKey(); err != nil {
+				return nil, fmt.Errorf("cannot MemEvaluationKeySet: %w", err)
+			}
+			break
+		}
+	}
+
+	gks := make([]*GaloisKey, 0)
+	for _, galEl := range kar.GaloisElements() {
+		gk, err := kar.GaloisKey(galEl)
+		if err != nil {
+			return nil, fmt.Errorf("cannot MemEvaluationKeySet: %w", err)
+		}
+		gks = append(gks, gk)
+	}
+
+	return NewMemEvaluationKeySet(rlk, gks...), nil
+}
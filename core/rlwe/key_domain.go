@@ -0,0 +1,77 @@
+package rlwe
+
+// KeyDomain identifies the polynomial domain in which a KeyGenerator emits
+// the generated public and evaluation keys.
+type KeyDomain int
+
+const (
+	// KeyDomainNTT emits keys in the NTT and Montgomery domain, ready for
+	// use by the evaluators. This is the default.
+	KeyDomainNTT KeyDomain = iota
+	// KeyDomainCoefficient emits keys in the coefficient (positional)
+	// domain, which compresses better for transport and storage and matches
+	// the convention of other libraries. Keys emitted in this domain must be
+	// switched back to KeyDomainNTT on load (see SwitchPublicKeyDomain,
+	// SwitchEvaluationKeyDomain and SwitchGadgetCiphertextDomain) before
+	// being used.
+	KeyDomainCoefficient
+)
+
+// WithKeyDomain returns a shallow copy of the receiver that emits the
+// generated public and evaluation keys in the given domain. Secret keys are
+// always kept in the NTT and Montgomery domain.
+func (kgen KeyGenerator) WithKeyDomain(domain KeyDomain) *KeyGenerator {
+	return &KeyGenerator{Encryptor: kgen.Encryptor, domain: domain}
+}
+
+// SwitchPublicKeyDomain switches pk in-place to the given domain. Public keys
+// do not carry domain metadata: they are in the NTT and Montgomery domain by
+// convention, unless generated by a KeyGenerator set to KeyDomainCoefficient,
+// so the caller is responsible for tracking the current domain and must not
+// switch to the domain pk is already in.
+func SwitchPublicKeyDomain(params ParameterProvider, domain KeyDomain, pk *PublicKey) {
+
+	ringQP := params.GetRLWEParameters().RingQP().AtLevel(pk.LevelQ(), pk.LevelP())
+
+	for i := range pk.Value {
+		switch domain {
+		case KeyDomainCoefficient:
+			ringQP.INTT(pk.Value[i], pk.Value[i])
+			ringQP.IMForm(pk.Value[i], pk.Value[i])
+		case KeyDomainNTT:
+			ringQP.NTT(pk.Value[i], pk.Value[i])
+			ringQP.MForm(pk.Value[i], pk.Value[i])
+		}
+	}
+}
+
+// SwitchEvaluationKeyDomain switches evk in-place to the given domain (see
+// SwitchGadgetCiphertextDomain).
+func SwitchEvaluationKeyDomain(params ParameterProvider, domain KeyDomain, evk *EvaluationKey) {
+	SwitchGadgetCiphertextDomain(params, domain, &evk.GadgetCiphertext)
+}
+
+// SwitchGadgetCiphertextDomain switches ct in-place to the given domain.
+// Gadget ciphertexts do not carry domain metadata: they are in the NTT and
+// Montgomery domain by convention, unless generated by a KeyGenerator set to
+// KeyDomainCoefficient, so the caller is responsible for tracking the current
+// domain and must not switch to the domain ct is already in.
+func SwitchGadgetCiphertextDomain(params ParameterProvider, domain KeyDomain, ct *GadgetCiphertext) {
+
+	ringQP := params.GetRLWEParameters().RingQP().AtLevel(ct.LevelQ(), ct.LevelP())
+
+	for i := range ct.Value {
+		for j := range ct.Value[i] {
+			for k := range ct.Value[i][j] {
+				switch domain {
+				case KeyDomainCoefficient:
+					ringQP.INTT(ct.Value[i][j][k], ct.Value[i][j][k])
+					ringQP.IMForm(ct.Value[i][j][k], ct.Value[i][j][k])
+				case KeyDomainNTT:
+					ringQP.NTT(ct.Value[i][j][k], ct.Value[i][j][k])
+					ringQP.MForm(ct.Value[i][j][k], ct.Value[i][j][k])
+				}
+			}
+		}
+	}
+}
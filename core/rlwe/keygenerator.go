@@ -6,12 +6,16 @@ import (
 	"github.com/luxdefi/lattice/v5/ring"
 	"github.com/luxdefi/lattice/v5/ring/ringqp"
 	"github.com/luxdefi/lattice/v5/utils"
+	"github.com/luxdefi/lattice/v5/utils/sampling"
 )
 
 // KeyGenerator is a structure that stores the elements required to create new keys,
 // as well as a memory buffer for intermediate values.
 type KeyGenerator struct {
 	*Encryptor
+	// domain is the polynomial domain in which the generated public and
+	// evaluation keys are emitted (see WithKeyDomain).
+	domain KeyDomain
 }
 
 // NewKeyGenerator creates a new KeyGenerator, from which the secret and public keys, as well as EvaluationKeys.
@@ -66,6 +70,116 @@ func (kgen KeyGenerator) genSecretKeyFromSampler(sampler ring.Sampler, sk *Secre
 	ringQP.MForm(sk.Value, sk.Value)
 }
 
+// ImportSecretKeyNew returns a new SecretKey whose value is set to the provided
+// coefficients (see ImportSecretKey).
+func (kgen KeyGenerator) ImportSecretKeyNew(coeffs []int64) (sk *SecretKey, err error) {
+	sk = NewSecretKey(kgen.params)
+	if err = kgen.ImportSecretKey(coeffs, sk); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// ImportSecretKey sets the value of sk to the provided centered coefficients,
+// which must have been generated externally (e.g. by another library or an HSM
+// ceremony) according to the secret distribution of the parameters. The
+// coefficients are validated against the constraints of the distribution
+// (values in {-1, 0, 1} and, if fixed, the Hamming weight for Ternary; the
+// bound for DiscreteGaussian) before being converted to the NTT and Montgomery
+// domain.
+func (kgen KeyGenerator) ImportSecretKey(coeffs []int64, sk *SecretKey) (err error) {
+
+	params := kgen.params
+
+	if len(coeffs) != params.N() {
+		return fmt.Errorf("cannot ImportSecretKey: len(coeffs)=%d does not match the ring degree N=%d", len(coeffs), params.N())
+	}
+
+	switch xs := params.Xs().(type) {
+	case ring.Ternary:
+
+		var hw int
+		for _, c := range coeffs {
+			if c < -1 || c > 1 {
+				return fmt.Errorf("cannot ImportSecretKey: coefficient %d lies outside of {-1, 0, 1} required by the Ternary secret distribution", c)
+			}
+			if c != 0 {
+				hw++
+			}
+		}
+
+		if xs.H != 0 && hw != xs.H {
+			return fmt.Errorf("cannot ImportSecretKey: Hamming weight %d does not match the fixed Hamming weight %d of the Ternary secret distribution", hw, xs.H)
+		}
+
+	case ring.DiscreteGaussian:
+
+		bound := int64(xs.Bound)
+		for _, c := range coeffs {
+			if c < -bound || c > bound {
+				return fmt.Errorf("cannot ImportSecretKey: coefficient %d exceeds the bound %d of the DiscreteGaussian secret distribution", c, bound)
+			}
+		}
+
+	default:
+		return fmt.Errorf("cannot ImportSecretKey: unsupported secret distribution %T", xs)
+	}
+
+	ringQP := params.RingQP().AtLevel(sk.LevelQ(), sk.LevelP())
+
+	for i, s := range ringQP.RingQ.SubRings[:sk.LevelQ()+1] {
+		skCoeffs := sk.Value.Q.Coeffs[i]
+		for j, c := range coeffs {
+			if c < 0 {
+				skCoeffs[j] = s.Modulus - uint64(-c)
+			} else {
+				skCoeffs[j] = uint64(c)
+			}
+		}
+	}
+
+	if levelP := sk.LevelP(); levelP > -1 {
+		ringQP.ExtendBasisSmallNormAndCenter(sk.Value.Q, levelP, sk.Value.Q, sk.Value.P)
+	}
+
+	ringQP.NTT(sk.Value, sk.Value)
+	ringQP.MForm(sk.Value, sk.Value)
+
+	return
+}
+
+// ImportSecretKeyFromSeedNew returns a new SecretKey derived deterministically
+// from the provided seed (see ImportSecretKeyFromSeed).
+func (kgen KeyGenerator) ImportSecretKeyFromSeedNew(seed []byte) (sk *SecretKey, err error) {
+	sk = NewSecretKey(kgen.params)
+	if err = kgen.ImportSecretKeyFromSeed(seed, sk); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// ImportSecretKeyFromSeed derives the value of sk deterministically from the
+// provided seed, sampling the secret distribution of the parameters with a
+// PRNG keyed with the seed. The same seed and parameters always derive the
+// same key, so a seed held externally (e.g. in an HSM) can re-derive the key
+// on demand.
+func (kgen KeyGenerator) ImportSecretKeyFromSeed(seed []byte, sk *SecretKey) (err error) {
+
+	prng, err := sampling.NewKeyedPRNG(seed)
+	if err != nil {
+		return fmt.Errorf("cannot ImportSecretKeyFromSeed: %w", err)
+	}
+
+	Xs, err := ring.NewSampler(prng, kgen.params.RingQ(), kgen.params.Xs(), false)
+	if err != nil {
+		return fmt.Errorf("cannot ImportSecretKeyFromSeed: %w", err)
+	}
+
+	kgen.genSecretKeyFromSampler(Xs, sk)
+
+	return
+}
+
 // GenPublicKeyNew generates a new public key from the provided SecretKey.
 func (kgen KeyGenerator) GenPublicKeyNew(sk *SecretKey) (pk *PublicKey) {
 	pk = NewPublicKey(kgen.params)
@@ -82,6 +196,48 @@ func (kgen KeyGenerator) GenPublicKey(sk *SecretKey, pk *PublicKey) {
 		// Sanity check, this error should not happen.
 		panic(err)
 	}
+
+	if kgen.domain == KeyDomainCoefficient {
+		SwitchPublicKeyDomain(kgen.params, KeyDomainCoefficient, pk)
+	}
+}
+
+// GenSeededPublicKeyNew generates a public key from the provided SecretKey
+// in the compact seeded form, in which the uniform polynomial is derived
+// from the provided 32-byte seed instead of being stored (see
+// SeededPublicKey). The error sampling remains non-deterministic, so the
+// seed only commits the uniform component.
+//
+// The procedure will return an error if the seed is not PublicKeySeedSize
+// bytes or if the KeyGenerator emits keys in the coefficient domain (see
+// WithKeyDomain), which the seeded form does not support.
+func (kgen KeyGenerator) GenSeededPublicKeyNew(sk *SecretKey, seed []byte) (pk *SeededPublicKey, err error) {
+
+	if len(seed) != PublicKeySeedSize {
+		return nil, fmt.Errorf("cannot GenSeededPublicKeyNew: seed must be %d bytes but is %d", PublicKeySeedSize, len(seed))
+	}
+
+	if kgen.domain == KeyDomainCoefficient {
+		return nil, fmt.Errorf("cannot GenSeededPublicKeyNew: coefficient key domain is not supported")
+	}
+
+	pk = NewSeededPublicKey(kgen.params)
+	copy(pk.Seed[:], seed)
+
+	a := kgen.params.RingQP().NewPoly()
+
+	if err = expandPublicKeySeed(kgen.params, pk.Seed, a); err != nil {
+		return nil, fmt.Errorf("cannot GenSeededPublicKeyNew: %w", err)
+	}
+
+	if err = kgen.WithKey(sk).encryptZeroSkFromC1QP(sk, Element[ringqp.Poly]{
+		MetaData: &MetaData{CiphertextMetaData: CiphertextMetaData{IsNTT: true, IsMontgomery: true}},
+		Value:    []ringqp.Poly{pk.Value[0], a},
+	}, a); err != nil {
+		return nil, fmt.Errorf("cannot GenSeededPublicKeyNew: %w", err)
+	}
+
+	return
 }
 
 // GenKeyPairNew generates a new SecretKey and a corresponding public key.
@@ -135,7 +291,7 @@ func (kgen KeyGenerator) GenGaloisKey(galEl uint64, sk *SecretKey, gk *GaloisKey
 	// on the ciphertext.
 	galElInv := kgen.params.ModInvGaloisElement(galEl)
 
-	index, err := ring.AutomorphismNTTIndex(ringQ.N(), ringQ.NthRoot(), galElInv)
+	index, err := ring.AutomorphismNTTIndexShared(ringQ.N(), ringQ.NthRoot(), galElInv)
 
 	// Sanity check, this error should not happen unless the
 	// evaluator's buffer thave been improperly tempered with.
@@ -273,4 +429,8 @@ func (kgen KeyGenerator) genEvaluationKey(skIn ring.Poly, skOut ringqp.Poly, evk
 		// Sanity check, this error should not happen.
 		panic(err)
 	}
+
+	if kgen.domain == KeyDomainCoefficient {
+		SwitchGadgetCiphertextDomain(kgen.params, KeyDomainCoefficient, &evk.GadgetCiphertext)
+	}
 }
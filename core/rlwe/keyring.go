@@ -0,0 +1,198 @@
+package rlwe
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/luxdefi/lattice/v5/utils"
+)
+
+// ErrUnknownTenant is the error returned (possibly wrapped) by the Keyring
+// when a lookup names a tenant that has not been registered. Use errors.Is to
+// test for it.
+var ErrUnknownTenant = errors.New("unknown tenant")
+
+// Keyring isolates the key material of multiple tenants sharing a single FHE
+// service process. Each tenant owns a namespace holding its parameter set,
+// public key and evaluation-key set, and every key lookup requires both the
+// tenant identifier and the fingerprint of the parameter set the caller
+// believes it is operating under: a stale tenant identifier or a ciphertext
+// routed to the wrong namespace fails with ErrParameterMismatch instead of
+// silently evaluating under another tenant's keys.
+//
+// The Keyring is safe for concurrent use.
+type Keyring struct {
+	mu      sync.RWMutex
+	tenants map[string]*tenant
+}
+
+type tenant struct {
+	params Parameters
+	pk     *PublicKey
+	evk    EvaluationKeySet
+}
+
+// NewKeyring instantiates an empty Keyring.
+func NewKeyring() *Keyring {
+	return &Keyring{tenants: map[string]*tenant{}}
+}
+
+// Register creates the namespace of the tenant with its parameter set.
+// Registering an already registered tenant is an error, so a tenant cannot be
+// silently rebound to different parameters.
+func (kr *Keyring) Register(tenantID string, params Parameters) error {
+
+	if tenantID == "" {
+		return fmt.Errorf("cannot Register: tenant identifier must be non-empty")
+	}
+
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+
+	if _, ok := kr.tenants[tenantID]; ok {
+		return fmt.Errorf("cannot Register: tenant %q is already registered", tenantID)
+	}
+
+	kr.tenants[tenantID] = &tenant{params: params}
+
+	return nil
+}
+
+// Deregister removes the namespace of the tenant and all its key material.
+func (kr *Keyring) Deregister(tenantID string) {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	delete(kr.tenants, tenantID)
+}
+
+// Tenants returns the sorted list of the registered tenant identifiers.
+func (kr *Keyring) Tenants() []string {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+	return utils.GetSortedKeys(kr.tenants)
+}
+
+// Parameters returns the parameter set of the tenant.
+func (kr *Keyring) Parameters(tenantID string) (Parameters, error) {
+
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+
+	t, ok := kr.tenants[tenantID]
+	if !ok {
+		return Parameters{}, fmt.Errorf("cannot Parameters: %q: %w", tenantID, ErrUnknownTenant)
+	}
+
+	return t.params, nil
+}
+
+// SetPublicKey stores the public key of the tenant, replacing any previous
+// one. The fingerprint must match the fingerprint of the parameter set the
+// tenant was registered with.
+func (kr *Keyring) SetPublicKey(tenantID string, fingerprint uint64, pk *PublicKey) error {
+
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+
+	t, err := kr.tenant("SetPublicKey", tenantID, fingerprint)
+	if err != nil {
+		return err
+	}
+
+	t.pk = pk
+
+	return nil
+}
+
+// PublicKey returns the public key of the tenant. The fingerprint must match
+// the fingerprint of the parameter set the tenant was registered with.
+func (kr *Keyring) PublicKey(tenantID string, fingerprint uint64) (*PublicKey, error) {
+
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+
+	t, err := kr.tenant("PublicKey", tenantID, fingerprint)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.pk == nil {
+		return nil, fmt.Errorf("cannot PublicKey: tenant %q has no public key", tenantID)
+	}
+
+	return t.pk, nil
+}
+
+// SetEvaluationKeys stores the evaluation-key set of the tenant, replacing
+// any previous one. The fingerprint must match the fingerprint of the
+// parameter set the tenant was registered with.
+func (kr *Keyring) SetEvaluationKeys(tenantID string, fingerprint uint64, evk EvaluationKeySet) error {
+
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+
+	t, err := kr.tenant("SetEvaluationKeys", tenantID, fingerprint)
+	if err != nil {
+		return err
+	}
+
+	t.evk = evk
+
+	return nil
+}
+
+// EvaluationKeys returns the evaluation-key set of the tenant. The
+// fingerprint must match the fingerprint of the parameter set the tenant was
+// registered with.
+func (kr *Keyring) EvaluationKeys(tenantID string, fingerprint uint64) (EvaluationKeySet, error) {
+
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+
+	t, err := kr.tenant("EvaluationKeys", tenantID, fingerprint)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.evk == nil {
+		return nil, fmt.Errorf("cannot EvaluationKeys: tenant %q has no evaluation keys", tenantID)
+	}
+
+	return t.evk, nil
+}
+
+// CheckCiphertext verifies that the ciphertext is bound to the parameter set
+// of the tenant, returning an error wrapping ErrParameterMismatch otherwise,
+// e.g. when a ciphertext of one tenant is routed to the namespace of another.
+// The check is skipped if the ciphertext carries no binding tag (see
+// MetaData.ParameterFingerprint).
+func (kr *Keyring) CheckCiphertext(tenantID string, ct *Ciphertext) error {
+
+	params, err := kr.Parameters(tenantID)
+	if err != nil {
+		return fmt.Errorf("cannot CheckCiphertext: %w", err)
+	}
+
+	if fp := ct.ParameterFingerprint; fp != 0 && params.fingerprint != 0 && fp != params.fingerprint {
+		return fmt.Errorf("cannot CheckCiphertext: ciphertext is not bound to the parameters of tenant %q: %w", tenantID, ErrParameterMismatch)
+	}
+
+	return nil
+}
+
+// tenant resolves the namespace of the tenant and enforces the fingerprint,
+// under a lock taken by the caller.
+func (kr *Keyring) tenant(op, tenantID string, fingerprint uint64) (*tenant, error) {
+
+	t, ok := kr.tenants[tenantID]
+	if !ok {
+		return nil, fmt.Errorf("cannot %s: %q: %w", op, tenantID, ErrUnknownTenant)
+	}
+
+	if t.params.Fingerprint() != fingerprint {
+		return nil, fmt.Errorf("cannot %s: fingerprint does not match the parameters of tenant %q: %w", op, tenantID, ErrParameterMismatch)
+	}
+
+	return t, nil
+}
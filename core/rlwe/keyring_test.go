@@ -0,0 +1,82 @@
+package rlwe
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyring(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	paramsA, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN: 10,
+		LogQ: []int{55, 45},
+		LogP: []int{60},
+	})
+	require.NoError(t, err)
+
+	// <<<<!Insecure parameters!>>>>
+	paramsB, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN: 10,
+		LogQ: []int{55, 45, 45},
+		LogP: []int{60},
+	})
+	require.NoError(t, err)
+
+	kgenA := NewKeyGenerator(paramsA)
+	skA, pkA := kgenA.GenKeyPairNew()
+	evkA := NewMemEvaluationKeySet(kgenA.GenRelinearizationKeyNew(skA))
+
+	kr := NewKeyring()
+
+	require.NoError(t, kr.Register("alice", paramsA))
+	require.NoError(t, kr.Register("bob", paramsB))
+	require.Error(t, kr.Register("alice", paramsB))
+	require.Error(t, kr.Register("", paramsA))
+	require.Equal(t, []string{"alice", "bob"}, kr.Tenants())
+
+	params, err := kr.Parameters("alice")
+	require.NoError(t, err)
+	require.True(t, paramsA.Equal(&params))
+
+	_, err = kr.Parameters("carol")
+	require.ErrorIs(t, err, ErrUnknownTenant)
+
+	fpA := paramsA.Fingerprint()
+	fpB := paramsB.Fingerprint()
+
+	require.NoError(t, kr.SetPublicKey("alice", fpA, pkA))
+	require.NoError(t, kr.SetEvaluationKeys("alice", fpA, evkA))
+
+	// Lookups with the fingerprint of another tenant's parameters fail.
+	_, err = kr.PublicKey("alice", fpB)
+	require.ErrorIs(t, err, ErrParameterMismatch)
+	_, err = kr.EvaluationKeys("alice", fpB)
+	require.ErrorIs(t, err, ErrParameterMismatch)
+	require.ErrorIs(t, kr.SetPublicKey("bob", fpA, pkA), ErrParameterMismatch)
+
+	pk, err := kr.PublicKey("alice", fpA)
+	require.NoError(t, err)
+	require.True(t, pk.Equal(pkA))
+
+	evk, err := kr.EvaluationKeys("alice", fpA)
+	require.NoError(t, err)
+	require.NotNil(t, evk)
+
+	// Registered but not provisioned tenants report missing key material.
+	_, err = kr.PublicKey("bob", fpB)
+	require.Error(t, err)
+	require.False(t, errors.Is(err, ErrParameterMismatch))
+
+	// Ciphertexts are bound to the parameters of their tenant.
+	ctA := NewCiphertext(paramsA, 1, paramsA.MaxLevel())
+	require.NoError(t, kr.CheckCiphertext("alice", ctA))
+	require.ErrorIs(t, kr.CheckCiphertext("bob", ctA), ErrParameterMismatch)
+
+	kr.Deregister("alice")
+	_, err = kr.Parameters("alice")
+	require.ErrorIs(t, err, ErrUnknownTenant)
+	require.Equal(t, []string{"bob"}, kr.Tenants())
+}
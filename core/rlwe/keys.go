@@ -1,9 +1,9 @@
 package rlwe
 
 import (
-	"bufio"
 	"fmt"
 	"io"
+	"sync"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/luxdefi/lattice/v5/ring/ringqp"
@@ -22,8 +22,11 @@ func NewSecretKey(params ParameterProvider) *SecretKey {
 	return &SecretKey{Value: params.GetRLWEParameters().RingQP().NewPoly()}
 }
 
+// Equal performs a deep equal. The comparison runs in constant time with
+// respect to the key coefficients, so that it is safe to use on secret
+// material (see ringqp.Poly.EqualConstantTime).
 func (sk SecretKey) Equal(other *SecretKey) bool {
-	return cmp.Equal(sk.Value, other.Value)
+	return sk.Value.EqualConstantTime(&other.Value)
 }
 
 // LevelQ returns the level of the modulus Q of the target.
@@ -461,7 +464,7 @@ func (gk GaloisKey) WriteTo(w io.Writer) (n int64, err error) {
 		return
 
 	default:
-		return gk.WriteTo(bufio.NewWriter(w))
+		return buffer.WriteWithPool(w, gk.WriteTo)
 	}
 }
 
@@ -502,7 +505,7 @@ func (gk *GaloisKey) ReadFrom(r io.Reader) (n int64, err error) {
 
 		return
 	default:
-		return gk.ReadFrom(bufio.NewReader(r))
+		return buffer.ReadWithPool(r, gk.ReadFrom)
 	}
 }
 
@@ -537,7 +540,16 @@ type EvaluationKeySet interface {
 }
 
 // MemEvaluationKeySet is a basic in-memory implementation of the EvaluationKeySet interface.
+//
+// The methods of the type are safe for concurrent use: keys can be added,
+// removed or replaced through AddGaloisKeys, RemoveGaloisKeys and
+// SetRelinearizationKey while evaluators concurrently read from the set, and
+// the updates are copy-on-write so that Snapshot can hand out consistent
+// views to in-flight evaluations. Accessing the RelinearizationKey and
+// GaloisKeys fields directly bypasses this synchronization and is only safe
+// when no other goroutine uses the set.
 type MemEvaluationKeySet struct {
+	mu                 sync.RWMutex
 	RelinearizationKey *RelinearizationKey
 	GaloisKeys         structs.Map[uint64, GaloisKey]
 }
@@ -552,8 +564,69 @@ func NewMemEvaluationKeySet(relinKey *RelinearizationKey, galoisKeys ...*GaloisK
 	return eks
 }
 
+// AddGaloisKeys inserts the given Galois keys in the set, replacing any
+// previous key stored for the same Galois element. It is safe to call
+// concurrently with the read methods of the set. The update is
+// copy-on-write: the underlying map is replaced rather than mutated, so
+// views returned by Snapshot are unaffected.
+func (evk *MemEvaluationKeySet) AddGaloisKeys(gks ...*GaloisKey) {
+	evk.mu.Lock()
+	defer evk.mu.Unlock()
+	next := make(structs.Map[uint64, GaloisKey], len(evk.GaloisKeys)+len(gks))
+	for galEl, k := range evk.GaloisKeys {
+		next[galEl] = k
+	}
+	for _, k := range gks {
+		next[k.GaloisElement] = k
+	}
+	evk.GaloisKeys = next
+}
+
+// RemoveGaloisKeys removes the Galois keys of the given Galois elements from
+// the set, ignoring elements for which no key is stored. It is safe to call
+// concurrently with the read methods of the set. The update is
+// copy-on-write: the underlying map is replaced rather than mutated, so
+// views returned by Snapshot are unaffected.
+func (evk *MemEvaluationKeySet) RemoveGaloisKeys(galEls ...uint64) {
+	evk.mu.Lock()
+	defer evk.mu.Unlock()
+	next := make(structs.Map[uint64, GaloisKey], len(evk.GaloisKeys))
+	for galEl, k := range evk.GaloisKeys {
+		next[galEl] = k
+	}
+	for _, galEl := range galEls {
+		delete(next, galEl)
+	}
+	evk.GaloisKeys = next
+}
+
+// Snapshot returns a consistent view of the set: concurrent AddGaloisKeys,
+// RemoveGaloisKeys and SetRelinearizationKey calls on evk replace its
+// underlying map and are not reflected in the snapshot, so a long-running
+// evaluation can resolve all its keys from a single generation of the set
+// while new keys are being provisioned. The keys themselves are shared, not
+// copied.
+func (evk *MemEvaluationKeySet) Snapshot() *MemEvaluationKeySet {
+	evk.mu.RLock()
+	defer evk.mu.RUnlock()
+	return &MemEvaluationKeySet{
+		RelinearizationKey: evk.RelinearizationKey,
+		GaloisKeys:         evk.GaloisKeys,
+	}
+}
+
+// SetRelinearizationKey sets the relinearization key of the set, replacing any
+// previous one. It is safe to call concurrently with the read methods of the set.
+func (evk *MemEvaluationKeySet) SetRelinearizationKey(rlk *RelinearizationKey) {
+	evk.mu.Lock()
+	defer evk.mu.Unlock()
+	evk.RelinearizationKey = rlk
+}
+
 // GetGaloisKey retrieves the Galois key for the automorphism X^{i} -> X^{i*galEl}.
-func (evk MemEvaluationKeySet) GetGaloisKey(galEl uint64) (gk *GaloisKey, err error) {
+func (evk *MemEvaluationKeySet) GetGaloisKey(galEl uint64) (gk *GaloisKey, err error) {
+	evk.mu.RLock()
+	defer evk.mu.RUnlock()
 	var ok bool
 	if gk, ok = evk.GaloisKeys[galEl]; !ok {
 		return nil, fmt.Errorf("GaloisKey[%d] is nil", galEl)
@@ -564,7 +637,10 @@ func (evk MemEvaluationKeySet) GetGaloisKey(galEl uint64) (gk *GaloisKey, err er
 
 // GetGaloisKeysList returns the list of all the Galois elements
 // for which a Galois key exists in the object.
-func (evk MemEvaluationKeySet) GetGaloisKeysList() (galEls []uint64) {
+func (evk *MemEvaluationKeySet) GetGaloisKeysList() (galEls []uint64) {
+
+	evk.mu.RLock()
+	defer evk.mu.RUnlock()
 
 	if evk.GaloisKeys == nil {
 		return []uint64{}
@@ -582,7 +658,9 @@ func (evk MemEvaluationKeySet) GetGaloisKeysList() (galEls []uint64) {
 }
 
 // GetRelinearizationKey retrieves the RelinearizationKey.
-func (evk MemEvaluationKeySet) GetRelinearizationKey() (rk *RelinearizationKey, err error) {
+func (evk *MemEvaluationKeySet) GetRelinearizationKey() (rk *RelinearizationKey, err error) {
+	evk.mu.RLock()
+	defer evk.mu.RUnlock()
 	if evk.RelinearizationKey != nil {
 		return evk.RelinearizationKey, nil
 	}
@@ -590,7 +668,15 @@ func (evk MemEvaluationKeySet) GetRelinearizationKey() (rk *RelinearizationKey,
 	return nil, fmt.Errorf("RelinearizationKey is nil")
 }
 
-func (evk MemEvaluationKeySet) BinarySize() (size int) {
+// Equal performs a deep equal.
+func (evk *MemEvaluationKeySet) Equal(other *MemEvaluationKeySet) bool {
+	return cmp.Equal(evk.RelinearizationKey, other.RelinearizationKey) && cmp.Equal(evk.GaloisKeys, other.GaloisKeys)
+}
+
+func (evk *MemEvaluationKeySet) BinarySize() (size int) {
+
+	evk.mu.RLock()
+	defer evk.mu.RUnlock()
 
 	size++
 	if evk.RelinearizationKey != nil {
@@ -616,10 +702,13 @@ func (evk MemEvaluationKeySet) BinarySize() (size int) {
 //     io.Writer in a pre-allocated bufio.Writer.
 //   - When writing to a pre-allocated var b []byte, it is preferable to pass
 //     buffer.NewBuffer(b) as w (see lattice/utils/buffer/buffer.go).
-func (evk MemEvaluationKeySet) WriteTo(w io.Writer) (n int64, err error) {
+func (evk *MemEvaluationKeySet) WriteTo(w io.Writer) (n int64, err error) {
 	switch w := w.(type) {
 	case buffer.Writer:
 
+		evk.mu.RLock()
+		defer evk.mu.RUnlock()
+
 		var inc int64
 
 		if evk.RelinearizationKey != nil {
@@ -665,7 +754,7 @@ func (evk MemEvaluationKeySet) WriteTo(w io.Writer) (n int64, err error) {
 		return n, w.Flush()
 
 	default:
-		return evk.WriteTo(bufio.NewWriter(w))
+		return buffer.WriteWithPool(w, evk.WriteTo)
 	}
 }
 
@@ -684,6 +773,9 @@ func (evk *MemEvaluationKeySet) ReadFrom(r io.Reader) (n int64, err error) {
 	switch r := r.(type) {
 	case buffer.Reader:
 
+		evk.mu.Lock()
+		defer evk.mu.Unlock()
+
 		var inc int64
 
 		var hasKey uint8
@@ -729,12 +821,12 @@ func (evk *MemEvaluationKeySet) ReadFrom(r io.Reader) (n int64, err error) {
 		return n, nil
 
 	default:
-		return evk.ReadFrom(bufio.NewReader(r))
+		return buffer.ReadWithPool(r, evk.ReadFrom)
 	}
 }
 
 // MarshalBinary encodes the object into a binary form on a newly allocated slice of bytes.
-func (evk MemEvaluationKeySet) MarshalBinary() (p []byte, err error) {
+func (evk *MemEvaluationKeySet) MarshalBinary() (p []byte, err error) {
 	buf := buffer.NewBufferSize(evk.BinarySize())
 	_, err = evk.WriteTo(buf)
 	return buf.Bytes(), err
@@ -0,0 +1,61 @@
+package rlwe
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+)
+
+// fingerprint returns the SHA-256 hash of the canonical serialization of
+// the object, streamed through WriteTo without materializing it.
+func fingerprint(o io.WriterTo) (fp [32]byte) {
+	h := sha256.New()
+	if _, err := o.WriteTo(h); err != nil {
+		// hash.Hash never fails as a writer, so an error here means the
+		// object itself cannot be serialized.
+		panic(fmt.Errorf("cannot fingerprint: %w", err))
+	}
+	copy(fp[:], h.Sum(nil))
+	return
+}
+
+// Fingerprint returns the SHA-256 hash of the canonical serialization of
+// the key, enabling integrity checks and key identification without
+// shipping the key itself.
+func (sk SecretKey) Fingerprint() [32]byte {
+	return fingerprint(sk)
+}
+
+// Fingerprint returns the SHA-256 hash of the canonical serialization of
+// the key, enabling integrity checks and key identification without
+// shipping the key itself.
+func (p PublicKey) Fingerprint() [32]byte {
+	return fingerprint(p)
+}
+
+// Fingerprint returns the SHA-256 hash of the canonical serialization of
+// the key, enabling integrity checks and key identification without
+// shipping the key itself.
+func (evk EvaluationKey) Fingerprint() [32]byte {
+	return fingerprint(evk)
+}
+
+// Fingerprint returns the SHA-256 hash of the canonical serialization of
+// the key, enabling integrity checks and key identification without
+// shipping the key itself.
+func (rlk RelinearizationKey) Fingerprint() [32]byte {
+	return fingerprint(rlk)
+}
+
+// Fingerprint returns the SHA-256 hash of the canonical serialization of
+// the key (which includes the Galois element), enabling integrity checks
+// and key identification without shipping the key itself.
+func (gk GaloisKey) Fingerprint() [32]byte {
+	return fingerprint(gk)
+}
+
+// Fingerprint returns the SHA-256 hash of the canonical serialization of
+// the key set, enabling integrity checks over all its keys at once.
+func (evk *MemEvaluationKeySet) Fingerprint() [32]byte {
+	return fingerprint(evk)
+}
@@ -0,0 +1,63 @@
+package rlwe
+
+import (
+	"fmt"
+
+	"github.com/luxdefi/lattice/v5/utils/sampling"
+)
+
+// KeySwitchingMatrix stores the full matrix of pairwise key-switching keys of
+// a set of n secret keys: the entry (i, j) is the EvaluationKey re-encrypting
+// ciphertexts encrypted under the i-th key into ciphertexts encrypted under
+// the j-th key, and the diagonal entries are nil. It supports data-sharing
+// topologies in which ciphertexts move between a fixed set of keys (e.g.
+// department-specific keys), with all of the n(n-1) ordered transitions
+// available.
+type KeySwitchingMatrix [][]*EvaluationKey
+
+// SwitchingKey returns the evaluation key re-encrypting ciphertexts encrypted
+// under the i-th key into ciphertexts encrypted under the j-th key.
+func (m KeySwitchingMatrix) SwitchingKey(i, j int) (evk *EvaluationKey, err error) {
+	if i < 0 || j < 0 || i >= len(m) || j >= len(m) {
+		return nil, fmt.Errorf("cannot SwitchingKey: pair (%d, %d) out of range for %d keys", i, j, len(m))
+	}
+	if i == j {
+		return nil, fmt.Errorf("cannot SwitchingKey: no key-switching key from the key %d to itself", i)
+	}
+	return m[i][j], nil
+}
+
+// GenKeySwitchingMatrixNew generates the full KeySwitchingMatrix of the given
+// secret keys, i.e. the n(n-1) evaluation keys of all the ordered pairs of
+// distinct keys. The optional evkParams apply to all the generated keys.
+func (kgen KeyGenerator) GenKeySwitchingMatrixNew(sks []*SecretKey, evkParams ...EvaluationKeyParameters) (m KeySwitchingMatrix) {
+	m = make(KeySwitchingMatrix, len(sks))
+	for i := range sks {
+		m[i] = make([]*EvaluationKey, len(sks))
+		for j := range sks {
+			if i != j {
+				m[i][j] = kgen.GenEvaluationKeyNew(sks[i], sks[j], evkParams...)
+			}
+		}
+	}
+	return
+}
+
+// GenSeededKeySwitchingMatrixNew generates the full KeySwitchingMatrix of the
+// given secret keys with the uniform components of the keys derived from a
+// PRNG keyed with seed: a party holding the seed can regenerate these
+// components, so that only the remaining halves of the keys need to be
+// transported or stored, halving the size of the n(n-1) generated keys. The
+// error components are still sampled from the generator's own source of
+// randomness.
+func (kgen KeyGenerator) GenSeededKeySwitchingMatrixNew(seed []byte, sks []*SecretKey, evkParams ...EvaluationKeyParameters) (m KeySwitchingMatrix, err error) {
+
+	prng, err := sampling.NewKeyedPRNG(seed)
+	if err != nil {
+		return nil, fmt.Errorf("cannot GenSeededKeySwitchingMatrixNew: %w", err)
+	}
+
+	kgen = KeyGenerator{Encryptor: kgen.Encryptor.WithPRNG(prng), domain: kgen.domain}
+
+	return kgen.GenKeySwitchingMatrixNew(sks, evkParams...), nil
+}
@@ -0,0 +1,154 @@
+package rlwe
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/luxdefi/lattice/v5/ring/ringqp"
+	"github.com/luxdefi/lattice/v5/utils/buffer"
+	"github.com/luxdefi/lattice/v5/utils/sampling"
+)
+
+// PublicKeySeedSize is the byte-size of the seed of a SeededPublicKey.
+const PublicKeySeedSize = 32
+
+// SeededPublicKey is a compact form of PublicKey in which the uniform
+// polynomial is stored as the PRNG seed it is expanded from, halving the
+// size of the key. This notably reduces the bandwidth of distributing
+// public keys in federated settings, at the cost of one polynomial
+// expansion on the receiving end (see Expand).
+//
+// The stored polynomial is the non-uniform component -as + e, in NTT and
+// Montgomery form (like PublicKey).
+type SeededPublicKey struct {
+	Value VectorQP
+	Seed  [PublicKeySeedSize]byte
+}
+
+// NewSeededPublicKey returns a new SeededPublicKey with zero values.
+func NewSeededPublicKey(params ParameterProvider) (pk *SeededPublicKey) {
+	p := params.GetRLWEParameters()
+	return &SeededPublicKey{Value: NewVectorQP(params, 1, p.MaxLevelQ(), p.MaxLevelP())}
+}
+
+func (p SeededPublicKey) LevelQ() int {
+	return p.Value.LevelQ()
+}
+
+func (p SeededPublicKey) LevelP() int {
+	return p.Value.LevelP()
+}
+
+// CopyNew creates a deep copy of the target SeededPublicKey and returns it.
+func (p SeededPublicKey) CopyNew() *SeededPublicKey {
+	return &SeededPublicKey{Value: *p.Value.CopyNew(), Seed: p.Seed}
+}
+
+// Equal performs a deep equal.
+func (p SeededPublicKey) Equal(other *SeededPublicKey) bool {
+	return p.Seed == other.Seed && p.Value.Equal(&other.Value)
+}
+
+// Expand expands the seed into the uniform polynomial and returns the
+// full PublicKey. The same seed and parameters always expand to the same
+// key.
+func (p SeededPublicKey) Expand(params ParameterProvider) (pk *PublicKey, err error) {
+
+	pk = &PublicKey{Value: NewVectorQP(params, 2, p.LevelQ(), p.LevelP())}
+	pk.Value[0].Copy(p.Value[0])
+
+	if err = expandPublicKeySeed(params, p.Seed, pk.Value[1]); err != nil {
+		return nil, fmt.Errorf("cannot Expand: %w", err)
+	}
+
+	return
+}
+
+// expandPublicKeySeed expands the seed into the uniform polynomial a of a
+// public key, sampled directly in the NTT and Montgomery domain with a
+// PRNG keyed with the seed.
+func expandPublicKeySeed(params ParameterProvider, seed [PublicKeySeedSize]byte, a ringqp.Poly) (err error) {
+
+	prng, err := sampling.NewKeyedPRNG(seed[:])
+	if err != nil {
+		return err
+	}
+
+	ringqp.NewUniformSampler(prng, *params.GetRLWEParameters().RingQP()).AtLevel(a.LevelQ(), a.LevelP()).Read(a)
+
+	return
+}
+
+func (p SeededPublicKey) BinarySize() int {
+	return PublicKeySeedSize + p.Value.BinarySize()
+}
+
+// WriteTo writes the object on an io.Writer. It implements the io.WriterTo
+// interface, and will write exactly object.BinarySize() bytes on w.
+//
+// See PublicKey.WriteTo for the recommended writer types.
+func (p SeededPublicKey) WriteTo(w io.Writer) (n int64, err error) {
+	switch w := w.(type) {
+	case buffer.Writer:
+
+		var inc int64
+
+		if inc, err = buffer.Write(w, p.Seed[:]); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		if inc, err = p.Value.WriteTo(w); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		return
+	default:
+		return buffer.WriteWithPool(w, p.WriteTo)
+	}
+}
+
+// ReadFrom reads on the object from an io.Writer. It implements the
+// io.ReaderFrom interface.
+//
+// See PublicKey.ReadFrom for the recommended reader types.
+func (p *SeededPublicKey) ReadFrom(r io.Reader) (n int64, err error) {
+	switch r := r.(type) {
+	case buffer.Reader:
+
+		var inc int64
+
+		if inc, err = buffer.Read(r, p.Seed[:]); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		if inc, err = p.Value.ReadFrom(r); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		return
+	default:
+		return buffer.ReadWithPool(r, p.ReadFrom)
+	}
+}
+
+// MarshalBinary encodes the object into a binary form on a newly allocated slice of bytes.
+func (p SeededPublicKey) MarshalBinary() ([]byte, error) {
+	buf := buffer.NewBufferSize(p.BinarySize())
+	_, err := p.WriteTo(buf)
+	return buf.Bytes(), err
+}
+
+// UnmarshalBinary decodes a slice of bytes generated by
+// MarshalBinary or WriteTo on the object.
+func (p *SeededPublicKey) UnmarshalBinary(b []byte) error {
+	_, err := p.ReadFrom(buffer.NewBuffer(b))
+	return err
+}
@@ -0,0 +1,73 @@
+package rlwe
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeededPublicKey(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN: 10,
+		LogQ: []int{55, 45},
+		LogP: []int{60},
+	})
+	require.NoError(t, err)
+
+	kgen := NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+
+	seed := make([]byte, PublicKeySeedSize)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+
+	spk, err := kgen.GenSeededPublicKeyNew(sk, seed)
+	require.NoError(t, err)
+
+	pk, err := spk.Expand(params)
+	require.NoError(t, err)
+
+	// The expanded key is a valid public key under sk.
+	require.GreaterOrEqual(t, math.Log2(params.NoiseFreshSK())+1, NoisePublicKey(pk, sk, params))
+
+	// Expansion is deterministic.
+	pkBis, err := spk.Expand(params)
+	require.NoError(t, err)
+	require.True(t, pk.Equal(pkBis))
+
+	// The same seed derives the same uniform polynomial across generations.
+	spkBis, err := kgen.GenSeededPublicKeyNew(sk, seed)
+	require.NoError(t, err)
+	pkTer, err := spkBis.Expand(params)
+	require.NoError(t, err)
+	require.True(t, pk.Value[1].Equal(&pkTer.Value[1]))
+	require.False(t, pk.Value[0].Equal(&pkTer.Value[0])) // fresh error sampling
+
+	// The seeded form stores a single polynomial instead of two.
+	require.Less(t, spk.BinarySize(), pk.BinarySize()*3/4)
+
+	// Serialization roundtrip.
+	data, err := spk.MarshalBinary()
+	require.NoError(t, err)
+	require.Len(t, data, spk.BinarySize())
+
+	spkOut := new(SeededPublicKey)
+	require.NoError(t, spkOut.UnmarshalBinary(data))
+	require.True(t, spk.Equal(spkOut))
+
+	pkOut, err := spkOut.Expand(params)
+	require.NoError(t, err)
+	require.True(t, pk.Equal(pkOut))
+
+	// Invalid seed size.
+	_, err = kgen.GenSeededPublicKeyNew(sk, seed[:16])
+	require.Error(t, err)
+
+	// The coefficient key domain is not supported.
+	_, err = kgen.WithKeyDomain(KeyDomainCoefficient).GenSeededPublicKeyNew(sk, seed)
+	require.Error(t, err)
+}
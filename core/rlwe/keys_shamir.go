@@ -0,0 +1,240 @@
+package rlwe
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/luxdefi/lattice/v5/ring"
+	"github.com/luxdefi/lattice/v5/ring/ringqp"
+	"github.com/luxdefi/lattice/v5/utils/buffer"
+	"github.com/luxdefi/lattice/v5/utils/sampling"
+)
+
+// ShamirSecretKeyShare is one of the n Shamir shares of a SecretKey produced
+// by SecretKey.Split. Index is the non-zero evaluation point of the share and
+// Threshold the number of shares required to reconstruct the key with
+// CombineSecretKeyShares. Any set of fewer than Threshold shares is
+// statistically independent of the key.
+//
+// This type provides a standalone threshold custody and backup mechanism for
+// secret keys; for an interactive t-out-of-N protocol over shared secrets,
+// see the Thresholdizer and Combiner types of the mhe package.
+type ShamirSecretKeyShare struct {
+	Index     uint64
+	Threshold uint64
+	Value     ringqp.Poly
+}
+
+// Split splits the receiver key into n Shamir shares such that any threshold
+// of them reconstruct the key with CombineSecretKeyShares, by sampling a
+// random polynomial of degree threshold - 1 with the key as constant term
+// and evaluating it at the points 1, ..., n. The input key is not modified.
+func (sk SecretKey) Split(params ParameterProvider, threshold, n int) ([]ShamirSecretKeyShare, error) {
+
+	if threshold < 1 || threshold > n {
+		return nil, fmt.Errorf("cannot Split: threshold must satisfy 1 <= threshold <= n")
+	}
+
+	ringQP := *params.GetRLWEParameters().RingQP()
+
+	prng, err := sampling.NewPRNG()
+
+	// Sanity check, this error should not happen.
+	if err != nil {
+		panic(fmt.Errorf("could not initialize PRNG: %s", err))
+	}
+
+	usampler := ringqp.NewUniformSampler(prng, ringQP)
+
+	coeffs := make([]ringqp.Poly, threshold)
+	coeffs[0] = *sk.Value.CopyNew()
+	for i := 1; i < threshold; i++ {
+		coeffs[i] = ringQP.NewPoly()
+		usampler.Read(coeffs[i])
+	}
+
+	shares := make([]ShamirSecretKeyShare, n)
+	for i := range shares {
+		shares[i] = ShamirSecretKeyShare{Index: uint64(i + 1), Threshold: uint64(threshold), Value: ringQP.NewPoly()}
+		ringQP.EvalPolyScalar(coeffs, shares[i].Index, shares[i].Value)
+	}
+
+	return shares, nil
+}
+
+// CombineSecretKeyShares reconstructs a SecretKey from Shamir shares produced
+// by SecretKey.Split, by Lagrange interpolation at zero over the first
+// threshold shares. At least threshold shares with distinct indices must be
+// provided.
+func CombineSecretKeyShares(params ParameterProvider, shares []ShamirSecretKeyShare) (sk *SecretKey, err error) {
+
+	if len(shares) == 0 {
+		return nil, fmt.Errorf("cannot CombineSecretKeyShares: no shares provided")
+	}
+
+	threshold := int(shares[0].Threshold)
+
+	if len(shares) < threshold {
+		return nil, fmt.Errorf("cannot CombineSecretKeyShares: %d shares provided but %d required", len(shares), threshold)
+	}
+
+	seen := map[uint64]bool{}
+	for _, share := range shares[:threshold] {
+		if int(share.Threshold) != threshold {
+			return nil, fmt.Errorf("cannot CombineSecretKeyShares: shares have mismatched thresholds")
+		}
+		if share.Index == 0 || seen[share.Index] {
+			return nil, fmt.Errorf("cannot CombineSecretKeyShares: share indices must be distinct and non-zero")
+		}
+		seen[share.Index] = true
+	}
+
+	ringQP := *params.GetRLWEParameters().RingQP()
+
+	// one in Montgomery form, the neutral element of the Lagrange
+	// coefficient products (see mhe.NewCombiner).
+	one := ringQP.NewRNSScalarFromUInt64(1)
+	qlen := ringQP.RingQ.ModuliChainLength()
+	for i, s := range ringQP.RingQ.SubRings {
+		one[i] = ring.MForm(one[i], s.Modulus, s.BRedConstant)
+	}
+	if ringQP.RingP != nil {
+		for i, s := range ringQP.RingP.SubRings {
+			one[i+qlen] = ring.MForm(one[i+qlen], s.Modulus, s.BRedConstant)
+		}
+	}
+
+	sk = &SecretKey{Value: ringQP.NewPoly()}
+
+	lag := ringQP.NewRNSScalar()
+	factor := ringQP.NewRNSScalar()
+	tmp := ringQP.NewPoly()
+
+	for i, share := range shares[:threshold] {
+
+		copy(lag, one)
+
+		// Lagrange coefficient at zero: prod_{j != i} x_j / (x_j - x_i).
+		for j, other := range shares[:threshold] {
+
+			if j == i {
+				continue
+			}
+
+			this := ringQP.NewRNSScalarFromUInt64(share.Index)
+			that := ringQP.NewRNSScalarFromUInt64(other.Index)
+
+			ringQP.SubRNSScalar(that, this, factor)
+			ringQP.Inverse(factor)
+			ringQP.MulRNSScalar(factor, that, factor)
+
+			ringQP.MulRNSScalar(lag, factor, lag)
+		}
+
+		ringQP.MulRNSScalarMontgomery(share.Value, lag, tmp)
+		ringQP.Add(sk.Value, tmp, sk.Value)
+	}
+
+	return
+}
+
+// Equal performs a deep equal. The comparison runs in constant time with
+// respect to the share coefficients, so that it is safe to use on secret
+// material (see ringqp.Poly.EqualConstantTime).
+func (s ShamirSecretKeyShare) Equal(other *ShamirSecretKeyShare) bool {
+	return s.Index == other.Index && s.Threshold == other.Threshold && s.Value.EqualConstantTime(&other.Value)
+}
+
+// CopyNew creates a deep copy of the receiver share and returns it.
+func (s ShamirSecretKeyShare) CopyNew() *ShamirSecretKeyShare {
+	return &ShamirSecretKeyShare{Index: s.Index, Threshold: s.Threshold, Value: *s.Value.CopyNew()}
+}
+
+// BinarySize returns the serialized size of the object in bytes.
+func (s ShamirSecretKeyShare) BinarySize() int {
+	return 16 + s.Value.BinarySize()
+}
+
+// WriteTo writes the object on an io.Writer. It implements the io.WriterTo
+// interface, and will write exactly object.BinarySize() bytes on w.
+//
+// See SecretKey.WriteTo for the recommended writer types.
+func (s ShamirSecretKeyShare) WriteTo(w io.Writer) (n int64, err error) {
+	switch w := w.(type) {
+	case buffer.Writer:
+
+		var inc int64
+
+		if inc, err = buffer.WriteUint64(w, s.Index); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		if inc, err = buffer.WriteUint64(w, s.Threshold); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		if inc, err = s.Value.WriteTo(w); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		return
+
+	default:
+		return buffer.WriteWithPool(w, s.WriteTo)
+	}
+}
+
+// ReadFrom reads on the object from an io.Writer. It implements the
+// io.ReaderFrom interface.
+//
+// See SecretKey.ReadFrom for the recommended reader types.
+func (s *ShamirSecretKeyShare) ReadFrom(r io.Reader) (n int64, err error) {
+	switch r := r.(type) {
+	case buffer.Reader:
+
+		var inc int64
+
+		if inc, err = buffer.ReadUint64(r, &s.Index); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		if inc, err = buffer.ReadUint64(r, &s.Threshold); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		if inc, err = s.Value.ReadFrom(r); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		return
+
+	default:
+		return buffer.ReadWithPool(r, s.ReadFrom)
+	}
+}
+
+// MarshalBinary encodes the object into a binary form on a newly allocated slice of bytes.
+func (s ShamirSecretKeyShare) MarshalBinary() (p []byte, err error) {
+	buf := buffer.NewBufferSize(s.BinarySize())
+	_, err = s.WriteTo(buf)
+	return buf.Bytes(), err
+}
+
+// UnmarshalBinary decodes a slice of bytes generated by
+// MarshalBinary or WriteTo on the object.
+func (s *ShamirSecretKeyShare) UnmarshalBinary(p []byte) (err error) {
+	_, err = s.ReadFrom(buffer.NewBuffer(p))
+	return
+}
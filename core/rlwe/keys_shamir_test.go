@@ -0,0 +1,71 @@
+package rlwe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShamirSecretKeyShare(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN: 10,
+		LogQ: []int{55, 45},
+		LogP: []int{60},
+	})
+	require.NoError(t, err)
+
+	kgen := NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+
+	shares, err := sk.Split(params, 3, 5)
+	require.NoError(t, err)
+	require.Len(t, shares, 5)
+
+	// Any threshold shares reconstruct the key.
+	for _, subset := range [][]ShamirSecretKeyShare{
+		{shares[0], shares[1], shares[2]},
+		{shares[4], shares[1], shares[3]},
+		{shares[2], shares[4], shares[0]},
+	} {
+		skOut, err := CombineSecretKeyShares(params, subset)
+		require.NoError(t, err)
+		require.True(t, sk.Equal(skOut))
+	}
+
+	// Extra shares beyond the threshold are ignored.
+	skOut, err := CombineSecretKeyShares(params, shares)
+	require.NoError(t, err)
+	require.True(t, sk.Equal(skOut))
+
+	// Fewer than threshold shares do not reconstruct.
+	_, err = CombineSecretKeyShares(params, shares[:2])
+	require.Error(t, err)
+
+	// Duplicated shares are rejected.
+	_, err = CombineSecretKeyShares(params, []ShamirSecretKeyShare{shares[0], shares[0], shares[1]})
+	require.Error(t, err)
+
+	// Invalid split parameters.
+	_, err = sk.Split(params, 0, 5)
+	require.Error(t, err)
+	_, err = sk.Split(params, 6, 5)
+	require.Error(t, err)
+
+	// A 1-out-of-n sharing stores the key in each share.
+	sharesTriv, err := sk.Split(params, 1, 2)
+	require.NoError(t, err)
+	skOut, err = CombineSecretKeyShares(params, sharesTriv[1:])
+	require.NoError(t, err)
+	require.True(t, sk.Equal(skOut))
+
+	// Serialization roundtrip.
+	data, err := shares[0].MarshalBinary()
+	require.NoError(t, err)
+	require.Len(t, data, shares[0].BinarySize())
+
+	shareOut := new(ShamirSecretKeyShare)
+	require.NoError(t, shareOut.UnmarshalBinary(data))
+	require.True(t, shares[0].Equal(shareOut))
+}
@@ -0,0 +1,119 @@
+package rlwe
+
+import (
+	"io"
+
+	"github.com/luxdefi/lattice/v5/ring/ringqp"
+	"github.com/luxdefi/lattice/v5/utils/sampling"
+)
+
+// SecretKeyShare is one of the two additive shares of a SecretKey produced by
+// NewSecretKeyShares. Each share taken alone is uniformly distributed and
+// statistically independent of the key, so an at-rest capture of a single
+// serialized share carries no information about the key. Both shares are
+// required to recombine the key with NewSecretKeyFromShares.
+type SecretKeyShare struct {
+	Value ringqp.Poly
+}
+
+// NewSecretKeyShares splits sk into two additive shares such that
+// share0 + share1 = sk mod QP. The first share is sampled uniformly at random
+// from the provided prng and acts as a one-time mask of the key; the shares
+// are only protective if they are stored in distinct locations.
+// The input key is not modified.
+func NewSecretKeyShares(params ParameterProvider, prng sampling.PRNG, sk *SecretKey) (share0, share1 *SecretKeyShare) {
+
+	ringQP := params.GetRLWEParameters().RingQP().AtLevel(sk.LevelQ(), sk.LevelP())
+
+	share0 = &SecretKeyShare{Value: ringQP.NewPoly()}
+	share1 = &SecretKeyShare{Value: ringQP.NewPoly()}
+
+	ringqp.NewUniformSampler(prng, ringQP).Read(share0.Value)
+
+	// share1 = sk - share0
+	ringQP.Sub(sk.Value, share0.Value, share1.Value)
+
+	return
+}
+
+// NewSecretKeyFromShares recombines two additive shares produced by
+// NewSecretKeyShares into the SecretKey share0 + share1 mod QP.
+func NewSecretKeyFromShares(params ParameterProvider, share0, share1 *SecretKeyShare) (sk *SecretKey) {
+
+	ringQP := params.GetRLWEParameters().RingQP().AtLevel(share0.LevelQ(), share0.LevelP())
+
+	sk = &SecretKey{Value: ringQP.NewPoly()}
+
+	ringQP.Add(share0.Value, share1.Value, sk.Value)
+
+	return
+}
+
+// Equal performs a deep equal. The comparison runs in constant time with
+// respect to the share coefficients, so that it is safe to use on secret
+// material (see ringqp.Poly.EqualConstantTime).
+func (s SecretKeyShare) Equal(other *SecretKeyShare) bool {
+	return s.Value.EqualConstantTime(&other.Value)
+}
+
+// LevelQ returns the level of the modulus Q of the target.
+func (s SecretKeyShare) LevelQ() int {
+	return s.Value.Q.Level()
+}
+
+// LevelP returns the level of the modulus P of the target.
+// Returns -1 if P is absent.
+func (s SecretKeyShare) LevelP() int {
+	return s.Value.P.Level()
+}
+
+// CopyNew creates a deep copy of the receiver share and returns it.
+func (s SecretKeyShare) CopyNew() *SecretKeyShare {
+	return &SecretKeyShare{*s.Value.CopyNew()}
+}
+
+// BinarySize returns the serialized size of the object in bytes.
+func (s SecretKeyShare) BinarySize() (dataLen int) {
+	return s.Value.BinarySize()
+}
+
+// WriteTo writes the object on an io.Writer. It implements the io.WriterTo
+// interface, and will write exactly object.BinarySize() bytes on w.
+//
+// Unless w implements the buffer.Writer interface (see lattice/utils/buffer/writer.go),
+// it will be wrapped into a bufio.Writer. Since this requires allocations, it
+// is preferable to pass a buffer.Writer directly:
+//
+//   - When writing multiple times to a io.Writer, it is preferable to first wrap the
+//     io.Writer in a pre-allocated bufio.Writer.
+//   - When writing to a pre-allocated var b []byte, it is preferable to pass
+//     buffer.NewBuffer(b) as w (see lattice/utils/buffer/buffer.go).
+func (s SecretKeyShare) WriteTo(w io.Writer) (n int64, err error) {
+	return s.Value.WriteTo(w)
+}
+
+// ReadFrom reads on the object from an io.Writer. It implements the
+// io.ReaderFrom interface.
+//
+// Unless r implements the buffer.Reader interface (see see lattice/utils/buffer/reader.go),
+// it will be wrapped into a bufio.Reader. Since this requires allocation, it
+// is preferable to pass a buffer.Reader directly:
+//
+//   - When reading multiple values from a io.Reader, it is preferable to first
+//     first wrap io.Reader in a pre-allocated bufio.Reader.
+//   - When reading from a var b []byte, it is preferable to pass a buffer.NewBuffer(b)
+//     as w (see lattice/utils/buffer/buffer.go).
+func (s *SecretKeyShare) ReadFrom(r io.Reader) (n int64, err error) {
+	return s.Value.ReadFrom(r)
+}
+
+// MarshalBinary encodes the object into a binary form on a newly allocated slice of bytes.
+func (s SecretKeyShare) MarshalBinary() (p []byte, err error) {
+	return s.Value.MarshalBinary()
+}
+
+// UnmarshalBinary decodes a slice of bytes generated by
+// MarshalBinary or WriteTo on the object.
+func (s *SecretKeyShare) UnmarshalBinary(p []byte) (err error) {
+	return s.Value.UnmarshalBinary(p)
+}
@@ -0,0 +1,128 @@
+package keystore
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+)
+
+const (
+	galoisKeyFilePrefix        = "galois_"
+	keyFileSuffix              = ".key"
+	relinearizationKeyFileName = "relinearization" + keyFileSuffix
+)
+
+// FSStore is a Store over a directory, with one file per key: Galois keys are
+// stored as "galois_<element>.key" and the relinearization key as
+// "relinearization.key". Writes go through a temporary file and a rename, so
+// that concurrent readers never observe a partially written key.
+type FSStore struct {
+	dir string
+}
+
+// NewFSStore instantiates a new Store over the given directory, creating it if
+// necessary.
+func NewFSStore(dir string) (*FSStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("cannot NewFSStore: %w", err)
+	}
+	return &FSStore{dir: dir}, nil
+}
+
+// write atomically writes p to the given file name inside the store directory.
+func (s *FSStore) write(name string, p []byte) error {
+	tmp, err := os.CreateTemp(s.dir, name+".tmp*")
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(p); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(s.dir, name))
+}
+
+// PutGaloisKey stores the Galois key under its Galois element.
+func (s *FSStore) PutGaloisKey(gk *rlwe.GaloisKey) error {
+	p, err := gk.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("cannot PutGaloisKey: %w", err)
+	}
+	if err = s.write(fmt.Sprintf("%s%d%s", galoisKeyFilePrefix, gk.GaloisElement, keyFileSuffix), p); err != nil {
+		return fmt.Errorf("cannot PutGaloisKey: %w", err)
+	}
+	return nil
+}
+
+// GetGaloisKey retrieves the Galois key for the given Galois element.
+func (s *FSStore) GetGaloisKey(galEl uint64) (*rlwe.GaloisKey, error) {
+	p, err := os.ReadFile(filepath.Join(s.dir, fmt.Sprintf("%s%d%s", galoisKeyFilePrefix, galEl, keyFileSuffix)))
+	if err != nil {
+		return nil, fmt.Errorf("cannot GetGaloisKey: %w", err)
+	}
+	gk := new(rlwe.GaloisKey)
+	if err = gk.UnmarshalBinary(p); err != nil {
+		return nil, fmt.Errorf("cannot GetGaloisKey: %w", err)
+	}
+	return gk, nil
+}
+
+// ListGaloisKeys returns the Galois elements for which a key is stored.
+func (s *FSStore) ListGaloisKeys() (galEls []uint64, err error) {
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot ListGaloisKeys: %w", err)
+	}
+
+	for _, entry := range entries {
+
+		name := entry.Name()
+		if !strings.HasPrefix(name, galoisKeyFilePrefix) || !strings.HasSuffix(name, keyFileSuffix) {
+			continue
+		}
+
+		galEl, err := strconv.ParseUint(name[len(galoisKeyFilePrefix):len(name)-len(keyFileSuffix)], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		galEls = append(galEls, galEl)
+	}
+
+	return
+}
+
+// PutRelinearizationKey stores the relinearization key.
+func (s *FSStore) PutRelinearizationKey(rlk *rlwe.RelinearizationKey) error {
+	p, err := rlk.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("cannot PutRelinearizationKey: %w", err)
+	}
+	if err = s.write(relinearizationKeyFileName, p); err != nil {
+		return fmt.Errorf("cannot PutRelinearizationKey: %w", err)
+	}
+	return nil
+}
+
+// GetRelinearizationKey retrieves the relinearization key.
+func (s *FSStore) GetRelinearizationKey() (*rlwe.RelinearizationKey, error) {
+	p, err := os.ReadFile(filepath.Join(s.dir, relinearizationKeyFileName))
+	if err != nil {
+		return nil, fmt.Errorf("cannot GetRelinearizationKey: %w", err)
+	}
+	rlk := new(rlwe.RelinearizationKey)
+	if err = rlk.UnmarshalBinary(p); err != nil {
+		return nil, fmt.Errorf("cannot GetRelinearizationKey: %w", err)
+	}
+	return rlk, nil
+}
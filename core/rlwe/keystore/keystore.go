@@ -0,0 +1,155 @@
+// Package keystore provides pluggable storage backends for evaluation keys.
+// It defines a small storage interface keyed by Galois element, an adapter
+// exposing any such backend as an rlwe.EvaluationKeySet, and ships two
+// implementations: an on-disk directory backend and an adapter over a generic
+// key-value store, so that evaluation keys can live in Redis/S3-like systems
+// with rlwe.MemEvaluationKeySet as just the in-memory special case.
+package keystore
+
+import (
+	"fmt"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+)
+
+// Store is a storage backend for evaluation keys. Implementations must be safe
+// for concurrent use if the EvaluationKeySet adapter is shared between
+// evaluators.
+type Store interface {
+
+	// PutGaloisKey stores the Galois key under its Galois element, replacing
+	// any previous key for that element.
+	PutGaloisKey(gk *rlwe.GaloisKey) error
+
+	// GetGaloisKey retrieves the Galois key for the given Galois element.
+	GetGaloisKey(galEl uint64) (*rlwe.GaloisKey, error)
+
+	// ListGaloisKeys returns the Galois elements for which a key is stored.
+	ListGaloisKeys() ([]uint64, error)
+
+	// PutRelinearizationKey stores the relinearization key, replacing any
+	// previous one.
+	PutRelinearizationKey(rlk *rlwe.RelinearizationKey) error
+
+	// GetRelinearizationKey retrieves the relinearization key.
+	GetRelinearizationKey() (*rlwe.RelinearizationKey, error)
+}
+
+// EvaluationKeySet adapts a Store to the rlwe.EvaluationKeySet interface, so
+// that evaluators can source their keys directly from a persistent backend.
+type EvaluationKeySet struct {
+	Store
+}
+
+// NewEvaluationKeySet wraps the given store into an rlwe.EvaluationKeySet.
+func NewEvaluationKeySet(store Store) *EvaluationKeySet {
+	return &EvaluationKeySet{Store: store}
+}
+
+// GetGaloisKey retrieves the Galois key for the automorphism X^{i} -> X^{i*galEl}.
+func (evk EvaluationKeySet) GetGaloisKey(galEl uint64) (*rlwe.GaloisKey, error) {
+	return evk.Store.GetGaloisKey(galEl)
+}
+
+// GetGaloisKeysList returns the list of all the Galois elements for which a
+// Galois key exists in the backend. Backend errors result in an empty list, as
+// the rlwe.EvaluationKeySet interface does not surface errors on this method.
+func (evk EvaluationKeySet) GetGaloisKeysList() (galEls []uint64) {
+	galEls, err := evk.Store.ListGaloisKeys()
+	if err != nil {
+		return []uint64{}
+	}
+	return
+}
+
+// GetRelinearizationKey retrieves the RelinearizationKey.
+func (evk EvaluationKeySet) GetRelinearizationKey() (*rlwe.RelinearizationKey, error) {
+	return evk.Store.GetRelinearizationKey()
+}
+
+// KV is a minimal key-value store interface, easily implemented over Redis,
+// object stores or embedded databases. List returns the keys starting with the
+// given prefix.
+type KV interface {
+	Get(key string) ([]byte, error)
+	Put(key string, value []byte) error
+	List(prefix string) ([]string, error)
+}
+
+const (
+	galoisKeyPrefix        = "lattice/galois/"
+	relinearizationKeyName = "lattice/relinearization"
+)
+
+// KVStore is a Store over a generic key-value store. Galois keys are stored
+// under "lattice/galois/<element>" and the relinearization key under
+// "lattice/relinearization".
+type KVStore struct {
+	kv KV
+}
+
+// NewKVStore instantiates a new Store over the given key-value store.
+func NewKVStore(kv KV) *KVStore {
+	return &KVStore{kv: kv}
+}
+
+// PutGaloisKey stores the Galois key under its Galois element.
+func (s *KVStore) PutGaloisKey(gk *rlwe.GaloisKey) error {
+	p, err := gk.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("cannot PutGaloisKey: %w", err)
+	}
+	return s.kv.Put(fmt.Sprintf("%s%d", galoisKeyPrefix, gk.GaloisElement), p)
+}
+
+// GetGaloisKey retrieves the Galois key for the given Galois element.
+func (s *KVStore) GetGaloisKey(galEl uint64) (*rlwe.GaloisKey, error) {
+	p, err := s.kv.Get(fmt.Sprintf("%s%d", galoisKeyPrefix, galEl))
+	if err != nil {
+		return nil, fmt.Errorf("cannot GetGaloisKey: %w", err)
+	}
+	gk := new(rlwe.GaloisKey)
+	if err = gk.UnmarshalBinary(p); err != nil {
+		return nil, fmt.Errorf("cannot GetGaloisKey: %w", err)
+	}
+	return gk, nil
+}
+
+// ListGaloisKeys returns the Galois elements for which a key is stored.
+func (s *KVStore) ListGaloisKeys() (galEls []uint64, err error) {
+	keys, err := s.kv.List(galoisKeyPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("cannot ListGaloisKeys: %w", err)
+	}
+	galEls = make([]uint64, 0, len(keys))
+	for _, key := range keys {
+		var galEl uint64
+		if _, err = fmt.Sscanf(key[len(galoisKeyPrefix):], "%d", &galEl); err != nil {
+			return nil, fmt.Errorf("cannot ListGaloisKeys: malformed key %q: %w", key, err)
+		}
+		galEls = append(galEls, galEl)
+	}
+	return
+}
+
+// PutRelinearizationKey stores the relinearization key.
+func (s *KVStore) PutRelinearizationKey(rlk *rlwe.RelinearizationKey) error {
+	p, err := rlk.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("cannot PutRelinearizationKey: %w", err)
+	}
+	return s.kv.Put(relinearizationKeyName, p)
+}
+
+// GetRelinearizationKey retrieves the relinearization key.
+func (s *KVStore) GetRelinearizationKey() (*rlwe.RelinearizationKey, error) {
+	p, err := s.kv.Get(relinearizationKeyName)
+	if err != nil {
+		return nil, fmt.Errorf("cannot GetRelinearizationKey: %w", err)
+	}
+	rlk := new(rlwe.RelinearizationKey)
+	if err = rlk.UnmarshalBinary(p); err != nil {
+		return nil, fmt.Errorf("cannot GetRelinearizationKey: %w", err)
+	}
+	return rlk, nil
+}
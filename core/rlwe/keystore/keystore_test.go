@@ -0,0 +1,99 @@
+package keystore
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+)
+
+// mapKV is an in-memory KV used to test the KVStore adapter.
+type mapKV map[string][]byte
+
+func (kv mapKV) Get(key string) ([]byte, error) {
+	p, ok := kv[key]
+	if !ok {
+		return nil, fmt.Errorf("key %q not found", key)
+	}
+	return p, nil
+}
+
+func (kv mapKV) Put(key string, value []byte) error {
+	kv[key] = value
+	return nil
+}
+
+func (kv mapKV) List(prefix string) (keys []string, err error) {
+	for key := range kv {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	return
+}
+
+func TestStores(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := rlwe.NewParametersFromLiteral(rlwe.ParametersLiteral{
+		LogN: 10,
+		LogQ: []int{35, 20},
+		LogP: []int{61},
+	})
+	require.NoError(t, err)
+
+	kgen := rlwe.NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+
+	rlk := kgen.GenRelinearizationKeyNew(sk)
+	galEls := params.GaloisElements([]int{1, 2, 4})
+	gks := kgen.GenGaloisKeysNew(galEls, sk)
+
+	fsStore, err := NewFSStore(t.TempDir())
+	require.NoError(t, err)
+
+	for _, store := range []Store{fsStore, NewKVStore(mapKV{})} {
+
+		t.Run(fmt.Sprintf("%T", store), func(t *testing.T) {
+
+			// Empty store.
+			list, err := store.ListGaloisKeys()
+			require.NoError(t, err)
+			require.Empty(t, list)
+			_, err = store.GetRelinearizationKey()
+			require.Error(t, err)
+			_, err = store.GetGaloisKey(galEls[0])
+			require.Error(t, err)
+
+			require.NoError(t, store.PutRelinearizationKey(rlk))
+			for _, gk := range gks {
+				require.NoError(t, store.PutGaloisKey(gk))
+			}
+
+			evk := NewEvaluationKeySet(store)
+
+			haveRlk, err := evk.GetRelinearizationKey()
+			require.NoError(t, err)
+			require.True(t, rlk.Equal(haveRlk))
+
+			haveEls := evk.GetGaloisKeysList()
+			sort.Slice(haveEls, func(i, j int) bool { return haveEls[i] < haveEls[j] })
+			wantEls := append([]uint64{}, galEls...)
+			sort.Slice(wantEls, func(i, j int) bool { return wantEls[i] < wantEls[j] })
+			require.Equal(t, wantEls, haveEls)
+
+			for _, gk := range gks {
+				have, err := evk.GetGaloisKey(gk.GaloisElement)
+				require.NoError(t, err)
+				require.True(t, gk.Equal(have))
+			}
+
+			// The adapter satisfies the rlwe interface.
+			var _ rlwe.EvaluationKeySet = evk
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package keystore
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/utils"
+)
+
+// Transport fetches serialized evaluation keys from a remote key service.
+// A gRPC (or HTTP) client implements it by mapping each method to one RPC
+// whose payload is the MarshalBinary form of the corresponding key, the
+// server side being the mirror of StoreTransport over its local Store.
+// Implementations must be safe for concurrent use.
+type Transport interface {
+
+	// FetchGaloisKey fetches the serialized Galois key for the given
+	// Galois element.
+	FetchGaloisKey(galEl uint64) ([]byte, error)
+
+	// FetchGaloisKeyList fetches the Galois elements for which the
+	// service holds a key.
+	FetchGaloisKeyList() ([]uint64, error)
+
+	// FetchRelinearizationKey fetches the serialized relinearization key.
+	FetchRelinearizationKey() ([]byte, error)
+}
+
+// RemoteEvaluationKeySet is an rlwe.EvaluationKeySet that pulls its keys
+// over a Transport, so that evaluators on workers can source them from a
+// central key service. Fetched keys are cached, each key being fetched
+// and deserialized at most once until Invalidate is called, and the
+// methods are safe for concurrent use by multiple evaluators.
+type RemoteEvaluationKeySet struct {
+	transport Transport
+
+	mu  sync.RWMutex
+	gks map[uint64]*rlwe.GaloisKey
+	rlk *rlwe.RelinearizationKey
+}
+
+// NewRemoteEvaluationKeySet instantiates a new RemoteEvaluationKeySet
+// over the given transport.
+func NewRemoteEvaluationKeySet(transport Transport) *RemoteEvaluationKeySet {
+	return &RemoteEvaluationKeySet{transport: transport, gks: map[uint64]*rlwe.GaloisKey{}}
+}
+
+// GetGaloisKey retrieves the Galois key for the automorphism
+// X^{i} -> X^{i*galEl}, fetching it over the transport on the first
+// access.
+func (evk *RemoteEvaluationKeySet) GetGaloisKey(galEl uint64) (*rlwe.GaloisKey, error) {
+
+	evk.mu.RLock()
+	gk, ok := evk.gks[galEl]
+	evk.mu.RUnlock()
+
+	if ok {
+		return gk, nil
+	}
+
+	p, err := evk.transport.FetchGaloisKey(galEl)
+	if err != nil {
+		return nil, fmt.Errorf("cannot GetGaloisKey: %w", err)
+	}
+
+	gk = new(rlwe.GaloisKey)
+	if err = gk.UnmarshalBinary(p); err != nil {
+		return nil, fmt.Errorf("cannot GetGaloisKey: %w", err)
+	}
+
+	evk.mu.Lock()
+	defer evk.mu.Unlock()
+
+	// Another goroutine may have fetched the key concurrently; keep the
+	// cached one so all callers observe the same key.
+	if cached, ok := evk.gks[galEl]; ok {
+		return cached, nil
+	}
+
+	evk.gks[galEl] = gk
+
+	return gk, nil
+}
+
+// GetGaloisKeysList returns the list of all the Galois elements for which
+// the service holds a key. Transport errors result in the list of the
+// locally cached keys, as the rlwe.EvaluationKeySet interface does not
+// surface errors on this method.
+func (evk *RemoteEvaluationKeySet) GetGaloisKeysList() (galEls []uint64) {
+
+	if galEls, err := evk.transport.FetchGaloisKeyList(); err == nil {
+		return galEls
+	}
+
+	evk.mu.RLock()
+	defer evk.mu.RUnlock()
+
+	return utils.GetSortedKeys(evk.gks)
+}
+
+// GetRelinearizationKey retrieves the RelinearizationKey, fetching it over
+// the transport on the first access.
+func (evk *RemoteEvaluationKeySet) GetRelinearizationKey() (*rlwe.RelinearizationKey, error) {
+
+	evk.mu.RLock()
+	rlk := evk.rlk
+	evk.mu.RUnlock()
+
+	if rlk != nil {
+		return rlk, nil
+	}
+
+	p, err := evk.transport.FetchRelinearizationKey()
+	if err != nil {
+		return nil, fmt.Errorf("cannot GetRelinearizationKey: %w", err)
+	}
+
+	rlk = new(rlwe.RelinearizationKey)
+	if err = rlk.UnmarshalBinary(p); err != nil {
+		return nil, fmt.Errorf("cannot GetRelinearizationKey: %w", err)
+	}
+
+	evk.mu.Lock()
+	defer evk.mu.Unlock()
+
+	if evk.rlk != nil {
+		return evk.rlk, nil
+	}
+
+	evk.rlk = rlk
+
+	return rlk, nil
+}
+
+// Invalidate drops all cached keys, so that subsequent accesses fetch
+// them again, e.g. after a key rotation on the service.
+func (evk *RemoteEvaluationKeySet) Invalidate() {
+	evk.mu.Lock()
+	defer evk.mu.Unlock()
+	evk.gks = map[uint64]*rlwe.GaloisKey{}
+	evk.rlk = nil
+}
+
+// StoreTransport adapts a Store to the Transport interface, serializing
+// the keys like a remote service would. It is the in-process reference
+// implementation of the protocol: a key service exposes its Store through
+// the same three methods and a client-side Transport undoes the
+// serialization via RemoteEvaluationKeySet.
+type StoreTransport struct {
+	Store
+}
+
+// FetchGaloisKey fetches the serialized Galois key for the given Galois
+// element from the underlying store.
+func (t StoreTransport) FetchGaloisKey(galEl uint64) ([]byte, error) {
+	gk, err := t.Store.GetGaloisKey(galEl)
+	if err != nil {
+		return nil, err
+	}
+	return gk.MarshalBinary()
+}
+
+// FetchGaloisKeyList fetches the Galois elements for which the underlying
+// store holds a key.
+func (t StoreTransport) FetchGaloisKeyList() ([]uint64, error) {
+	return t.Store.ListGaloisKeys()
+}
+
+// FetchRelinearizationKey fetches the serialized relinearization key from
+// the underlying store.
+func (t StoreTransport) FetchRelinearizationKey() ([]byte, error) {
+	rlk, err := t.Store.GetRelinearizationKey()
+	if err != nil {
+		return nil, err
+	}
+	return rlk.MarshalBinary()
+}
@@ -0,0 +1,134 @@
+package keystore
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+)
+
+// countingTransport wraps a Transport and counts the fetches, to assert
+// the caching behavior of the RemoteEvaluationKeySet.
+type countingTransport struct {
+	Transport
+	fetches int64
+}
+
+func (t *countingTransport) FetchGaloisKey(galEl uint64) ([]byte, error) {
+	atomic.AddInt64(&t.fetches, 1)
+	return t.Transport.FetchGaloisKey(galEl)
+}
+
+func (t *countingTransport) FetchRelinearizationKey() ([]byte, error) {
+	atomic.AddInt64(&t.fetches, 1)
+	return t.Transport.FetchRelinearizationKey()
+}
+
+func TestRemoteEvaluationKeySet(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := rlwe.NewParametersFromLiteral(rlwe.ParametersLiteral{
+		LogN: 10,
+		LogQ: []int{35, 20},
+		LogP: []int{61},
+	})
+	require.NoError(t, err)
+
+	kgen := rlwe.NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+
+	rlk := kgen.GenRelinearizationKeyNew(sk)
+	galEls := params.GaloisElements([]int{1, 2, 4})
+	gks := kgen.GenGaloisKeysNew(galEls, sk)
+
+	store := NewKVStore(mapKV{})
+	require.NoError(t, store.PutRelinearizationKey(rlk))
+	for _, gk := range gks {
+		require.NoError(t, store.PutGaloisKey(gk))
+	}
+
+	transport := &countingTransport{Transport: StoreTransport{Store: store}}
+	evk := NewRemoteEvaluationKeySet(transport)
+
+	// The set satisfies the rlwe interface.
+	var _ rlwe.EvaluationKeySet = evk
+
+	haveEls := evk.GetGaloisKeysList()
+	sort.Slice(haveEls, func(i, j int) bool { return haveEls[i] < haveEls[j] })
+	wantEls := append([]uint64{}, galEls...)
+	sort.Slice(wantEls, func(i, j int) bool { return wantEls[i] < wantEls[j] })
+	require.Equal(t, wantEls, haveEls)
+
+	// Concurrent accesses agree on the keys and each key is fetched a
+	// bounded number of times.
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, gk := range gks {
+				have, err := evk.GetGaloisKey(gk.GaloisElement)
+				require.NoError(t, err)
+				require.True(t, gk.Equal(have))
+			}
+			have, err := evk.GetRelinearizationKey()
+			require.NoError(t, err)
+			require.True(t, rlk.Equal(have))
+		}()
+	}
+	wg.Wait()
+
+	// Repeated accesses are served from the cache.
+	fetches := atomic.LoadInt64(&transport.fetches)
+	for _, gk := range gks {
+		have, err := evk.GetGaloisKey(gk.GaloisElement)
+		require.NoError(t, err)
+		require.True(t, gk.Equal(have))
+	}
+	_, err = evk.GetRelinearizationKey()
+	require.NoError(t, err)
+	require.Equal(t, fetches, atomic.LoadInt64(&transport.fetches))
+
+	// Concurrent callers observe the same cached key instance.
+	gk0, err := evk.GetGaloisKey(galEls[0])
+	require.NoError(t, err)
+	gk0Bis, err := evk.GetGaloisKey(galEls[0])
+	require.NoError(t, err)
+	require.Same(t, gk0, gk0Bis)
+
+	// Invalidate drops the cache and the keys are fetched again.
+	evk.Invalidate()
+	_, err = evk.GetGaloisKey(galEls[0])
+	require.NoError(t, err)
+	require.Greater(t, atomic.LoadInt64(&transport.fetches), fetches)
+
+	// Unknown keys surface the transport error.
+	_, err = evk.GetGaloisKey(uint64(12345))
+	require.Error(t, err)
+
+	// Transport failures on the list fall back to the cached elements.
+	evkOffline := NewRemoteEvaluationKeySet(failingTransport{transport: transport})
+	require.Empty(t, evkOffline.GetGaloisKeysList())
+}
+
+// failingTransport fails the list fetch, to exercise the cached fallback.
+type failingTransport struct {
+	transport Transport
+}
+
+func (t failingTransport) FetchGaloisKey(galEl uint64) ([]byte, error) {
+	return t.transport.FetchGaloisKey(galEl)
+}
+
+func (t failingTransport) FetchGaloisKeyList() ([]uint64, error) {
+	return nil, fmt.Errorf("service unavailable")
+}
+
+func (t failingTransport) FetchRelinearizationKey() ([]byte, error) {
+	return t.transport.FetchRelinearizationKey()
+}
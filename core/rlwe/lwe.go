@@ -0,0 +1,283 @@
+package rlwe
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/luxdefi/lattice/v5/utils"
+	"github.com/luxdefi/lattice/v5/utils/buffer"
+	"github.com/luxdefi/lattice/v5/utils/structs"
+)
+
+// LWECiphertext is a coefficient-wise LWE sample (b, a) extracted from an
+// RLWE ciphertext with SampleExtract: its phase b + <a, s> mod Q is the
+// extracted coefficient of the phase of the source ciphertext, where s is
+// the coefficient vector of the RLWE secret. Value[i] stores the sample
+// (b, a[0], ..., a[N-1]) modulo the i-th modulus of the chain. The MetaData
+// is inherited from the source ciphertext, with IsNTT unset as an LWE
+// sample is inherently in the coefficient domain.
+//
+// Together with Evaluator.RepackLWE, this type is the glue for
+// scheme-switching and TFHE-style pipelines that process individual
+// coefficients outside of the RLWE ring.
+type LWECiphertext struct {
+	Value    structs.Matrix[uint64]
+	MetaData *MetaData
+}
+
+// LevelQ returns the level of the modulus Q of the target.
+func (ct LWECiphertext) LevelQ() int {
+	return len(ct.Value) - 1
+}
+
+// Level returns the level of the target.
+func (ct LWECiphertext) Level() int {
+	return ct.LevelQ()
+}
+
+// N returns the dimension of the LWE sample.
+func (ct LWECiphertext) N() int {
+	return len(ct.Value[0]) - 1
+}
+
+// CopyNew creates a deep copy of the target LWECiphertext and returns it.
+func (ct LWECiphertext) CopyNew() *LWECiphertext {
+	return &LWECiphertext{Value: ct.Value.CopyNew(), MetaData: ct.MetaData.CopyNew()}
+}
+
+// Equal performs a deep equal.
+func (ct LWECiphertext) Equal(other *LWECiphertext) bool {
+	return ct.Value.Equal(other.Value) && ct.MetaData.Equal(other.MetaData)
+}
+
+// SampleExtract extracts the LWE sample of the coefficient of the given
+// index of the input RLWE ciphertext, which must be of degree one. The
+// input ciphertext is not modified.
+func SampleExtract(params ParameterProvider, ct *Ciphertext, index int) (lwe *LWECiphertext, err error) {
+
+	p := params.GetRLWEParameters()
+
+	N := p.N()
+
+	if ct.Degree() != 1 {
+		return nil, fmt.Errorf("cannot SampleExtract: ct.Degree() != 1")
+	}
+
+	if index < 0 || index >= N {
+		return nil, fmt.Errorf("cannot SampleExtract: index=%d lies outside [0, %d)", index, N)
+	}
+
+	level := ct.Level()
+
+	ringQ := p.RingQ().AtLevel(level)
+
+	c0, c1 := ct.Value[0], ct.Value[1]
+
+	if ct.IsNTT {
+		buff0, buff1 := ringQ.NewPoly(), ringQ.NewPoly()
+		ringQ.INTT(c0, buff0)
+		ringQ.INTT(c1, buff1)
+		c0, c1 = buff0, buff1
+	}
+
+	value := make(structs.Matrix[uint64], level+1)
+
+	for i, s := range ringQ.SubRings[:level+1] {
+
+		q := s.Modulus
+
+		row := make([]uint64, N+1)
+
+		row[0] = c0.Coeffs[i][index]
+
+		// The phase of the sample is the coefficient of index `index` of
+		// the negacyclic convolution c1 * s: a[j] = c1[index-j] for
+		// j <= index and -c1[N+index-j] for j > index.
+		for j := 0; j <= index; j++ {
+			row[j+1] = c1.Coeffs[i][index-j]
+		}
+
+		for j := index + 1; j < N; j++ {
+			if v := c1.Coeffs[i][N+index-j]; v != 0 {
+				row[j+1] = q - v
+			}
+		}
+
+		value[i] = row
+	}
+
+	md := ct.MetaData.CopyNew()
+	md.IsNTT = false
+
+	return &LWECiphertext{Value: value, MetaData: md}, nil
+}
+
+// RepackLWE repacks a batch of LWE samples into a single RLWE ciphertext,
+// where the index in the map is the position of the phase of the indexed
+// sample in the coefficients of the returned ciphertext; the remaining
+// coefficients are zeroed. All samples must share the same metadata.
+//
+// Each sample is re-embedded into an RLWE ciphertext, cleaned of its
+// garbage coefficients with a full Trace and shifted to its position, for
+// a total of len(cts) * LogN key-switches. The evaluator must hold the
+// Galois keys for the elements returned by
+// GaloisElementsForTrace(params, 0).
+func (eval Evaluator) RepackLWE(cts map[int]*LWECiphertext) (ct *Ciphertext, err error) {
+
+	params := eval.GetRLWEParameters()
+
+	if len(cts) == 0 {
+		return nil, fmt.Errorf("cannot RepackLWE: no samples provided")
+	}
+
+	N := params.N()
+
+	keys := utils.GetSortedKeys(cts)
+
+	level := cts[keys[0]].Level()
+	md := cts[keys[0]].MetaData
+
+	for _, key := range keys {
+
+		if key < 0 || key >= N {
+			return nil, fmt.Errorf("cannot RepackLWE: index=%d lies outside [0, %d)", key, N)
+		}
+
+		if !cts[key].MetaData.Equal(md) {
+			return nil, fmt.Errorf("cannot RepackLWE: samples have mismatched metadata")
+		}
+
+		level = utils.Min(level, cts[key].Level())
+	}
+
+	ringQ := params.RingQ().AtLevel(level)
+
+	buff := NewCiphertext(params, 1, level)
+
+	for _, key := range keys {
+
+		lwe := cts[key]
+
+		rlwe := NewCiphertext(params, 1, level)
+		*rlwe.MetaData = *lwe.MetaData
+
+		// Embeds the sample into an RLWE ciphertext whose phase holds
+		// b + <a, s> in its constant coefficient (and garbage in the
+		// others): c0 = b and c1 = a[0] - sum_{j>0} a[N-j] X^{j}, the
+		// negacyclic mirror of a.
+		for i, s := range ringQ.SubRings[:level+1] {
+
+			q := s.Modulus
+
+			row := lwe.Value[i]
+
+			rlwe.Value[0].Coeffs[i][0] = row[0]
+			rlwe.Value[1].Coeffs[i][0] = row[1]
+
+			for j := 1; j < N; j++ {
+				if v := row[N-j+1]; v != 0 {
+					rlwe.Value[1].Coeffs[i][j] = q - v
+				}
+			}
+		}
+
+		// The full trace zeroes all but the constant coefficient.
+		if err = eval.Trace(rlwe, 0, rlwe); err != nil {
+			return nil, fmt.Errorf("cannot RepackLWE: %w", err)
+		}
+
+		if err = eval.NegacyclicShift(rlwe, key, buff); err != nil {
+			return nil, fmt.Errorf("cannot RepackLWE: %w", err)
+		}
+
+		if ct == nil {
+			ct = buff.CopyNew()
+		} else {
+			ringQ.Add(ct.Value[0], buff.Value[0], ct.Value[0])
+			ringQ.Add(ct.Value[1], buff.Value[1], ct.Value[1])
+		}
+	}
+
+	return
+}
+
+// BinarySize returns the serialized size of the object in bytes.
+func (ct LWECiphertext) BinarySize() int {
+	return ct.MetaData.BinarySize() + ct.Value.BinarySize()
+}
+
+// WriteTo writes the object on an io.Writer. It implements the io.WriterTo
+// interface, and will write exactly object.BinarySize() bytes on w.
+//
+// See Ciphertext.WriteTo for the recommended writer types.
+func (ct LWECiphertext) WriteTo(w io.Writer) (n int64, err error) {
+	switch w := w.(type) {
+	case buffer.Writer:
+
+		var inc int64
+
+		if inc, err = ct.MetaData.WriteTo(w); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		if inc, err = ct.Value.WriteTo(w); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		return
+
+	default:
+		return buffer.WriteWithPool(w, ct.WriteTo)
+	}
+}
+
+// ReadFrom reads on the object from an io.Writer. It implements the
+// io.ReaderFrom interface.
+//
+// See Ciphertext.ReadFrom for the recommended reader types.
+func (ct *LWECiphertext) ReadFrom(r io.Reader) (n int64, err error) {
+	switch r := r.(type) {
+	case buffer.Reader:
+
+		var inc int64
+
+		if ct.MetaData == nil {
+			ct.MetaData = &MetaData{}
+		}
+
+		if inc, err = ct.MetaData.ReadFrom(r); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		if inc, err = ct.Value.ReadFrom(r); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		return
+
+	default:
+		return buffer.ReadWithPool(r, ct.ReadFrom)
+	}
+}
+
+// MarshalBinary encodes the object into a binary form on a newly allocated slice of bytes.
+func (ct LWECiphertext) MarshalBinary() (p []byte, err error) {
+	buf := buffer.NewBufferSize(ct.BinarySize())
+	_, err = ct.WriteTo(buf)
+	return buf.Bytes(), err
+}
+
+// UnmarshalBinary decodes a slice of bytes generated by
+// MarshalBinary or WriteTo on the object.
+func (ct *LWECiphertext) UnmarshalBinary(p []byte) (err error) {
+	_, err = ct.ReadFrom(buffer.NewBuffer(p))
+	return
+}
@@ -0,0 +1,149 @@
+package rlwe
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLWE(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN: 10,
+		LogQ: []int{55, 45},
+		LogP: []int{60},
+	})
+	require.NoError(t, err)
+
+	kgen := NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+	enc := NewEncryptor(params, sk)
+	dec := NewDecryptor(params, sk)
+
+	N := params.N()
+	level := params.MaxLevel()
+	ringQ := params.RingQ().AtLevel(level)
+
+	// Message coefficients scaled by 2^{40}, so that the noise of a fresh
+	// encryption and of the repacking is negligible in comparison.
+	scale := uint64(1) << 40
+	coeffs := make([]uint64, N)
+	for i := range coeffs {
+		coeffs[i] = uint64(i%16) * scale
+	}
+
+	pt := NewPlaintext(params, level)
+	for i, s := range ringQ.SubRings[:level+1] {
+		for j, c := range coeffs {
+			pt.Value.Coeffs[i][j] = c % s.Modulus
+		}
+	}
+
+	ct := NewCiphertext(params, 1, level)
+	require.NoError(t, enc.Encrypt(pt, ct))
+
+	// The secret in the coefficient domain, for manual LWE decryption.
+	skCoeffs := sk.Value.Q.CopyNew()
+	ringQ.INTT(*skCoeffs, *skCoeffs)
+	ringQ.IMForm(*skCoeffs, *skCoeffs)
+
+	// The phase of an extracted sample is the extracted coefficient of
+	// the phase of the ciphertext.
+	for _, index := range []int{0, 1, 17, N - 1} {
+
+		lwe, err := SampleExtract(params, ct, index)
+		require.NoError(t, err)
+		require.Equal(t, level, lwe.Level())
+		require.Equal(t, N, lwe.N())
+		require.False(t, lwe.MetaData.IsNTT)
+
+		for i, s := range ringQ.SubRings[:level+1] {
+
+			q := new(big.Int).SetUint64(s.Modulus)
+
+			// b + <a, s> mod q
+			phase := new(big.Int).SetUint64(lwe.Value[i][0])
+			tmp := new(big.Int)
+			for j := 0; j < N; j++ {
+				phase.Add(phase, tmp.Mul(tmp.SetUint64(lwe.Value[i][j+1]), new(big.Int).SetUint64(skCoeffs.Coeffs[i][j])))
+			}
+			phase.Mod(phase, q)
+
+			// The centered difference with the message coefficient is
+			// the (small) encryption noise.
+			diff := phase.Sub(phase, tmp.SetUint64(coeffs[index]%s.Modulus))
+			diff.Mod(diff, q)
+			if diff.Cmp(new(big.Int).Rsh(q, 1)) > 0 {
+				diff.Sub(diff, q)
+			}
+
+			require.Less(t, diff.BitLen(), 20)
+		}
+	}
+
+	// Out-of-range index and degree-two ciphertexts are rejected.
+	_, err = SampleExtract(params, ct, N)
+	require.Error(t, err)
+	_, err = SampleExtract(params, NewCiphertext(params, 2, level), 0)
+	require.Error(t, err)
+
+	// Repacking the extracted samples restores the selected coefficients
+	// and zeroes the remaining ones.
+	indices := []int{0, 3, 5, 64}
+
+	cts := map[int]*LWECiphertext{}
+	for _, index := range indices {
+		cts[index], err = SampleExtract(params, ct, index)
+		require.NoError(t, err)
+	}
+
+	galEls := GaloisElementsForTrace(params, 0)
+	eval := NewEvaluator(params, NewMemEvaluationKeySet(nil, kgen.GenGaloisKeysNew(galEls, sk)...))
+
+	ctPack, err := eval.RepackLWE(cts)
+	require.NoError(t, err)
+
+	ptPack := NewPlaintext(params, ctPack.Level())
+	dec.Decrypt(ctPack, ptPack)
+
+	if ptPack.IsNTT {
+		ringQ.INTT(ptPack.Value, ptPack.Value)
+	}
+
+	selected := map[int]bool{}
+	for _, index := range indices {
+		selected[index] = true
+	}
+
+	q0 := ringQ.SubRings[0].Modulus
+	for j := 0; j < N; j++ {
+
+		want := uint64(0)
+		if selected[j] {
+			want = coeffs[j] % q0
+		}
+
+		diff := (ptPack.Value.Coeffs[0][j] + q0 - want) % q0
+		if diff > q0>>1 {
+			diff = q0 - diff
+		}
+
+		require.Less(t, diff, uint64(1)<<30, "coefficient %d", j)
+	}
+
+	// Serialization roundtrip.
+	lwe := cts[indices[0]]
+	data, err := lwe.MarshalBinary()
+	require.NoError(t, err)
+	require.Len(t, data, lwe.BinarySize())
+
+	lweOut := new(LWECiphertext)
+	require.NoError(t, lweOut.UnmarshalBinary(data))
+	require.True(t, lwe.Equal(lweOut))
+
+	// An empty batch cannot be repacked.
+	_, err = eval.RepackLWE(map[int]*LWECiphertext{})
+	require.Error(t, err)
+}
@@ -0,0 +1,123 @@
+package rlwe
+
+import (
+	"unsafe"
+
+	"github.com/luxdefi/lattice/v5/ring"
+	"github.com/luxdefi/lattice/v5/ring/ringqp"
+)
+
+// This file implements MemoryFootprint, the deep in-RAM size in bytes of the
+// main objects of the library (ciphertexts, keys, key sets and evaluators),
+// as opposed to the serialized size returned by BinarySize. Services use it
+// to enforce per-tenant memory quotas and to predict capacity. The footprint
+// counts the object's owned storage: shared state, such as the parameters or
+// an evaluation key set referenced by an evaluator, is accounted for once on
+// its own.
+
+// MemoryFootprint returns the deep in-RAM size of the element in bytes.
+func (op Element[T]) MemoryFootprint() (size int) {
+
+	size = int(unsafe.Sizeof(op))
+
+	if op.MetaData != nil {
+		size += int(unsafe.Sizeof(*op.MetaData))
+	}
+
+	for i := range op.Value {
+		switch p := any(op.Value[i]).(type) {
+		case ring.Poly:
+			size += p.MemoryFootprint()
+		case ringqp.Poly:
+			size += p.MemoryFootprint()
+		}
+	}
+
+	return
+}
+
+// MemoryFootprint returns the deep in-RAM size of the secret key in bytes.
+func (sk SecretKey) MemoryFootprint() (size int) {
+	return int(unsafe.Sizeof(sk)) + sk.Value.MemoryFootprint()
+}
+
+// MemoryFootprint returns the deep in-RAM size of the vector in bytes.
+func (v VectorQP) MemoryFootprint() (size int) {
+	for i := range v {
+		size += v[i].MemoryFootprint()
+	}
+	return
+}
+
+// MemoryFootprint returns the deep in-RAM size of the gadget ciphertext in
+// bytes.
+func (ct GadgetCiphertext) MemoryFootprint() (size int) {
+	size = int(unsafe.Sizeof(ct))
+	for i := range ct.Value {
+		for j := range ct.Value[i] {
+			size += ct.Value[i][j].MemoryFootprint()
+		}
+	}
+	return
+}
+
+// MemoryFootprint returns the deep in-RAM size of the key in bytes.
+func (gk GaloisKey) MemoryFootprint() (size int) {
+	return int(unsafe.Sizeof(gk.GaloisElement)+unsafe.Sizeof(gk.NthRoot)) + gk.EvaluationKey.MemoryFootprint()
+}
+
+// MemoryFootprint returns the deep in-RAM size of the key set in bytes.
+func (evk *MemEvaluationKeySet) MemoryFootprint() (size int) {
+
+	evk.mu.RLock()
+	defer evk.mu.RUnlock()
+
+	if evk.RelinearizationKey != nil {
+		size += evk.RelinearizationKey.MemoryFootprint()
+	}
+
+	for _, gk := range evk.GaloisKeys {
+		size += gk.MemoryFootprint()
+	}
+
+	return
+}
+
+// MemoryFootprint returns the deep in-RAM size of the evaluator's own
+// buffers in bytes. The shared parameters and the evaluation key set are not
+// counted: they are accounted for once, on their own.
+func (eval Evaluator) MemoryFootprint() (size int) {
+
+	size = eval.EvaluatorBuffers.MemoryFootprint()
+
+	for _, index := range eval.automorphismIndex {
+		size += sliceHeaderBytes + 8*cap(index)
+	}
+
+	return
+}
+
+// MemoryFootprint returns the deep in-RAM size of the buffers in bytes.
+func (buff EvaluatorBuffers) MemoryFootprint() (size int) {
+
+	size = int(unsafe.Sizeof(buff))
+
+	if buff.BuffCt != nil {
+		size += buff.BuffCt.MemoryFootprint()
+	}
+
+	for i := range buff.BuffQP {
+		size += buff.BuffQP[i].MemoryFootprint()
+	}
+
+	size += buff.BuffInvNTT.MemoryFootprint()
+
+	for i := range buff.BuffDecompQP {
+		size += buff.BuffDecompQP[i].MemoryFootprint()
+	}
+
+	return size + 8*cap(buff.BuffBitDecomp)
+}
+
+// sliceHeaderBytes is the in-RAM size of a slice header on a 64-bit platform.
+const sliceHeaderBytes = 24
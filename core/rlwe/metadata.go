@@ -1,6 +1,8 @@
 package rlwe
 
 import (
+	"bytes"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,80 +13,208 @@ import (
 	"github.com/luxdefi/lattice/v5/utils/bignum"
 )
 
+// MaxTagBytes is the bound on the total size of the user-defined tags of a
+// MetaData: the sum over all tags of the length of the key plus the length of
+// the value may not exceed it.
+const MaxTagBytes = 1 << 16
+
 // MetaData is a struct storing metadata.
 type MetaData struct {
 	PlaintextMetaData
 	CiphertextMetaData
+
+	// Tags is an optional application-defined map of string keys to opaque
+	// byte values (e.g. a dataset id or a slot layout descriptor) that
+	// travels with the object: it survives WriteTo/ReadFrom and is deep
+	// copied by CopyNew. The library never interprets it. Use SetTag to
+	// enforce the MaxTagBytes bound.
+	Tags map[string][]byte
+
+	// ParameterFingerprint binds the object to the parameter set it was
+	// allocated for (see Parameters.Fingerprint). The field is process-local:
+	// it is not serialized and is zero on deserialized or manually constructed
+	// objects, for which the binding checks are skipped.
+	ParameterFingerprint uint64
+}
+
+// SetTag records an application-defined tag, returning an error if the total
+// size of the tags would exceed MaxTagBytes.
+func (m *MetaData) SetTag(key string, value []byte) (err error) {
+
+	size := len(key) + len(value)
+	for k, v := range m.Tags {
+		if k != key {
+			size += len(k) + len(v)
+		}
+	}
+
+	if size > MaxTagBytes {
+		return fmt.Errorf("cannot SetTag: total tag size %d exceeds the bound of %d bytes", size, MaxTagBytes)
+	}
+
+	if m.Tags == nil {
+		m.Tags = map[string][]byte{}
+	}
+
+	m.Tags[key] = value
+
+	return
+}
+
+// Tag returns the value of the application-defined tag with the given key,
+// or nil if the tag is not set.
+func (m *MetaData) Tag(key string) []byte {
+	return m.Tags[key]
+}
+
+// DeleteTag removes the application-defined tag with the given key.
+func (m *MetaData) DeleteTag(key string) {
+	delete(m.Tags, key)
+}
+
+// tagsSize returns the total size of the tags, as bounded by MaxTagBytes.
+func tagsSize(tags map[string][]byte) (size int) {
+	for k, v := range tags {
+		size += len(k) + len(v)
+	}
+	return
 }
 
-// CopyNew returns a copy of the target.
+// tagsEqual returns true if the two tag maps hold the same entries.
+func tagsEqual(a, b map[string][]byte) bool {
+
+	if len(a) != len(b) {
+		return false
+	}
+
+	for k, v := range a {
+		w, ok := b[k]
+		if !ok || !bytes.Equal(v, w) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CopyNew returns a copy of the target, deep copying the tags.
 func (m MetaData) CopyNew() *MetaData {
+	if m.Tags != nil {
+		tags := make(map[string][]byte, len(m.Tags))
+		for k, v := range m.Tags {
+			tags[k] = append([]byte(nil), v...)
+		}
+		m.Tags = tags
+	}
 	return &m
 }
 
 func (m *MetaData) Equal(other *MetaData) (res bool) {
-	return m.PlaintextMetaData.Equal(&other.PlaintextMetaData) && m.CiphertextMetaData.Equal(&other.CiphertextMetaData)
+	return m.PlaintextMetaData.Equal(&other.PlaintextMetaData) && m.CiphertextMetaData.Equal(&other.CiphertextMetaData) && tagsEqual(m.Tags, other.Tags)
 }
 
 // BinarySize returns the size in bytes that the object once marshalled into a binary form.
 func (m MetaData) BinarySize() int {
-	return 44 + m.PlaintextMetaData.BinarySize() + m.CiphertextMetaData.BinarySize()
+	p, err := m.MarshalJSON()
+	if err != nil {
+		return 0
+	}
+	return 8 + len(p)
 }
 
 // WriteTo writes the object on an io.Writer. It implements the io.WriterTo
-// interface, and will write exactly object.BinarySize() bytes on w.
+// interface, and will write exactly object.BinarySize() bytes on w. The
+// serialization is length-prefixed, as the presence of tags makes it
+// variable-sized.
 func (m MetaData) WriteTo(w io.Writer) (int64, error) {
-	if p, err := m.MarshalBinary(); err != nil {
+
+	p, err := m.MarshalJSON()
+	if err != nil {
 		return 0, err
-	} else {
-		if n, err := w.Write(p); err != nil {
-			return int64(n), err
-		} else {
-			return int64(n), nil
-		}
 	}
+
+	var prefix [8]byte
+	binary.LittleEndian.PutUint64(prefix[:], uint64(len(p)))
+
+	n, err := w.Write(prefix[:])
+	if err != nil {
+		return int64(n), err
+	}
+
+	inc, err := w.Write(p)
+
+	return int64(n + inc), err
 }
 
 // ReadFrom reads on the object from an io.Writer. It implements the
 // io.ReaderFrom interface.
-//
-// Unless r implements the buffer.Reader interface (see see lattice/utils/buffer/reader.go),
-// it will be wrapped into a bufio.Reader. Since this requires allocation, it
-// is preferable to pass a buffer.Reader directly:
-//
-//   - When reading multiple values from a io.Reader, it is preferable to first
-//     first wrap io.Reader in a pre-allocated bufio.Reader.
-//   - When reading from a var b []byte, it is preferable to pass a buffer.NewBuffer(b)
-//     as w (see lattice/utils/buffer/buffer.go).
 func (m *MetaData) ReadFrom(r io.Reader) (int64, error) {
-	p := make([]byte, m.BinarySize())
-	if n, err := r.Read(p); err != nil {
+
+	var prefix [8]byte
+	n, err := io.ReadFull(r, prefix[:])
+	if err != nil {
 		return int64(n), err
-	} else {
-		return int64(n), m.UnmarshalBinary(p)
 	}
+
+	size := binary.LittleEndian.Uint64(prefix[:])
+	if size > metaDataMaxSerializedBytes {
+		return int64(n), fmt.Errorf("cannot ReadFrom: metadata size %d exceeds the bound of %d bytes", size, metaDataMaxSerializedBytes)
+	}
+
+	p := make([]byte, size)
+	inc, err := io.ReadFull(r, p)
+	if err != nil {
+		return int64(n + inc), err
+	}
+
+	return int64(n + inc), m.UnmarshalJSON(p)
 }
 
+// metaDataMaxSerializedBytes bounds the serialized size of a MetaData: the
+// fixed part plus the tags, whose JSON encoding expands each byte by at most
+// a factor two (base64 plus quoting and separators), with some slack.
+const metaDataMaxSerializedBytes = 1<<12 + 2*MaxTagBytes
+
 func (m MetaData) MarshalJSON() (p []byte, err error) {
+
+	if size := tagsSize(m.Tags); size > MaxTagBytes {
+		return nil, fmt.Errorf("cannot MarshalJSON: total tag size %d exceeds the bound of %d bytes", size, MaxTagBytes)
+	}
+
 	aux := &struct {
 		PlaintextMetaData  PlaintextMetaData
 		CiphertextMetaData CiphertextMetaData
+		Tags               map[string][]byte `json:",omitempty"`
 	}{
 		PlaintextMetaData:  m.PlaintextMetaData,
 		CiphertextMetaData: m.CiphertextMetaData,
+		Tags:               m.Tags,
 	}
 
 	return json.Marshal(aux)
 }
 
+// MarshalBinary encodes the object into a binary form on a newly allocated
+// slice of bytes, in the same length-prefixed format as WriteTo.
 func (m MetaData) MarshalBinary() (p []byte, err error) {
-	return m.MarshalJSON()
+
+	j, err := m.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	p = make([]byte, 8+len(j))
+	binary.LittleEndian.PutUint64(p, uint64(len(j)))
+	copy(p[8:], j)
+
+	return
 }
 
 func (m *MetaData) UnmarshalJSON(p []byte) (err error) {
 	aux := &struct {
 		PlaintextMetaData  PlaintextMetaData
 		CiphertextMetaData CiphertextMetaData
+		Tags               map[string][]byte `json:",omitempty"`
 	}{
 		PlaintextMetaData:  m.PlaintextMetaData,
 		CiphertextMetaData: m.CiphertextMetaData,
@@ -94,13 +224,30 @@ func (m *MetaData) UnmarshalJSON(p []byte) (err error) {
 		return
 	}
 
+	if size := tagsSize(aux.Tags); size > MaxTagBytes {
+		return fmt.Errorf("cannot UnmarshalJSON: total tag size %d exceeds the bound of %d bytes", size, MaxTagBytes)
+	}
+
 	m.PlaintextMetaData = aux.PlaintextMetaData
 	m.CiphertextMetaData = aux.CiphertextMetaData
+	m.Tags = aux.Tags
 	return
 }
 
+// UnmarshalBinary decodes a slice of bytes generated by
+// MarshalBinary or WriteTo on the object.
 func (m *MetaData) UnmarshalBinary(p []byte) (err error) {
-	return m.UnmarshalJSON(p)
+
+	if len(p) < 8 {
+		return fmt.Errorf("cannot UnmarshalBinary: invalid length prefix")
+	}
+
+	size := binary.LittleEndian.Uint64(p)
+	if size > uint64(len(p)-8) {
+		return fmt.Errorf("cannot UnmarshalBinary: metadata size %d exceeds the %d available bytes", size, len(p)-8)
+	}
+
+	return m.UnmarshalJSON(p[8 : 8+size])
 }
 
 // PlaintextMetaData is a struct storing metadata related to the plaintext.
@@ -0,0 +1,206 @@
+package rlwe
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/luxdefi/lattice/v5/utils"
+	"github.com/luxdefi/lattice/v5/utils/concurrency"
+)
+
+// Migrator converts ciphertexts encrypted under a source parameter set into
+// ciphertexts encrypted under a target parameter set, non interactively and
+// without intermediate decryption, using an EvaluationKey re-encrypting the
+// source key under the target key. It is intended for the bulk conversion of
+// stored ciphertexts when upgrading the parameters of archived data, e.g. to
+// a larger ring degree for a higher security level.
+//
+// The source and target parameter sets must be compatible with the ring
+// degree switching of ApplyEvaluationKey: the moduli Q of the parameter set
+// with the smaller ring degree must be the same or a prefix of the moduli Q
+// of the other one.
+type Migrator struct {
+	paramsIn  Parameters
+	paramsOut Parameters
+	evk       *EvaluationKey
+	eval      *Evaluator
+}
+
+// GenMigrationKey generates the EvaluationKey re-encrypting skIn (under the
+// source parameters) to skOut (under the target parameters), using the
+// key-generator of the parameter set with the larger ring degree, as
+// required by ApplyEvaluationKey.
+func GenMigrationKey(paramsIn, paramsOut Parameters, skIn, skOut *SecretKey, evkParams ...EvaluationKeyParameters) *EvaluationKey {
+	if paramsIn.N() > paramsOut.N() {
+		return NewKeyGenerator(paramsIn).GenEvaluationKeyNew(skIn, skOut, evkParams...)
+	}
+	return NewKeyGenerator(paramsOut).GenEvaluationKeyNew(skIn, skOut, evkParams...)
+}
+
+// NewMigrator instantiates a new Migrator converting ciphertexts encrypted
+// under paramsIn into ciphertexts encrypted under paramsOut, with an
+// EvaluationKey obtained with GenMigrationKey (or generated manually
+// following the ApplyEvaluationKey requirements).
+func NewMigrator(paramsIn, paramsOut Parameters, evk *EvaluationKey) (*Migrator, error) {
+
+	paramsSmall, paramsLarge := paramsIn, paramsOut
+	if paramsIn.N() > paramsOut.N() || (paramsIn.N() == paramsOut.N() && paramsIn.QCount() > paramsOut.QCount()) {
+		paramsSmall, paramsLarge = paramsOut, paramsIn
+	}
+
+	qSmall, qLarge := paramsSmall.Q(), paramsLarge.Q()
+
+	if len(qSmall) > len(qLarge) {
+		return nil, fmt.Errorf("cannot NewMigrator: moduli Q of the smaller ring degree parameters must be the same or a prefix of the moduli Q of the larger one")
+	}
+
+	for i := range qSmall {
+		if qSmall[i] != qLarge[i] {
+			return nil, fmt.Errorf("cannot NewMigrator: moduli Q of the smaller ring degree parameters must be the same or a prefix of the moduli Q of the larger one")
+		}
+	}
+
+	return &Migrator{
+		paramsIn:  paramsIn,
+		paramsOut: paramsOut,
+		evk:       evk,
+		eval:      NewEvaluator(paramsLarge, nil),
+	}, nil
+}
+
+// ShallowCopy creates a shallow copy of the receiver, sharing the migration
+// key but not the internal buffers, which can be used concurrently with it.
+func (m *Migrator) ShallowCopy() *Migrator {
+	return &Migrator{
+		paramsIn:  m.paramsIn,
+		paramsOut: m.paramsOut,
+		evk:       m.evk,
+		eval:      m.eval.ShallowCopy(),
+	}
+}
+
+// Migrate converts a single degree-one ciphertext encrypted under the source
+// parameters into a new ciphertext encrypted under the target parameters.
+// The level of the output is the minimum of the level of the input and of
+// the maximum level of the target parameters.
+func (m *Migrator) Migrate(ctIn *Ciphertext) (ctOut *Ciphertext, err error) {
+
+	ctOut = NewCiphertext(m.paramsOut, 1, utils.Min(ctIn.Level(), m.paramsOut.MaxLevel()))
+
+	if err = m.eval.ApplyEvaluationKey(ctIn, m.evk, ctOut); err != nil {
+		return nil, fmt.Errorf("cannot Migrate: %w", err)
+	}
+
+	return
+}
+
+// MigrateSlice converts the ciphertexts of the slice with up to workers
+// concurrent goroutines, preserving their order. If workers is not strictly
+// positive, it defaults to runtime.GOMAXPROCS(0). The input ciphertexts are
+// not modified.
+func (m *Migrator) MigrateSlice(cts []*Ciphertext, workers int) (out []*Ciphertext, err error) {
+
+	out = make([]*Ciphertext, len(cts))
+
+	if err = m.migrateSlice(concurrency.NewPool(m, workers), cts, out); err != nil {
+		return nil, fmt.Errorf("cannot MigrateSlice: %w", err)
+	}
+
+	return
+}
+
+// MigrateStream reads ciphertexts serialized back-to-back from r until EOF,
+// converts them with up to workers concurrent goroutines and writes them
+// serialized to w, preserving their order. The stream is processed in
+// batches of workers ciphertexts, bounding the memory usage independently of
+// the size of the stream. If workers is not strictly positive, it defaults
+// to runtime.GOMAXPROCS(0). Returns the number of ciphertexts written to w.
+func (m *Migrator) MigrateStream(r io.Reader, w io.Writer, workers int) (n int, err error) {
+
+	br := bufio.NewReader(r)
+	bw := bufio.NewWriter(w)
+
+	pool := concurrency.NewPool(m, workers)
+
+	in := make([]*Ciphertext, 0, pool.Size())
+	out := make([]*Ciphertext, pool.Size())
+
+	flush := func() (err error) {
+
+		if err = m.migrateSlice(pool, in, out[:len(in)]); err != nil {
+			return
+		}
+
+		for _, ct := range out[:len(in)] {
+			if _, err = ct.WriteTo(bw); err != nil {
+				return
+			}
+			n++
+		}
+
+		in = in[:0]
+
+		return
+	}
+
+	for {
+
+		if _, err = br.Peek(1); err == io.EOF {
+			break
+		} else if err != nil {
+			return n, fmt.Errorf("cannot MigrateStream: %w", err)
+		}
+
+		ct := new(Ciphertext)
+		if _, err = ct.ReadFrom(br); err != nil {
+			return n, fmt.Errorf("cannot MigrateStream: ciphertext %d: %w", n+len(in), err)
+		}
+
+		in = append(in, ct)
+
+		if len(in) == cap(in) {
+			if err = flush(); err != nil {
+				return n, fmt.Errorf("cannot MigrateStream: %w", err)
+			}
+		}
+	}
+
+	if err = flush(); err != nil {
+		return n, fmt.Errorf("cannot MigrateStream: %w", err)
+	}
+
+	if err = bw.Flush(); err != nil {
+		return n, fmt.Errorf("cannot MigrateStream: %w", err)
+	}
+
+	return
+}
+
+// migrateSlice converts in[i] into out[i] with the workers of the pool.
+func (m *Migrator) migrateSlice(pool *concurrency.Pool[*Migrator], in, out []*Ciphertext) error {
+
+	errs := make([]error, len(in))
+
+	var wg sync.WaitGroup
+	for i := range in {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = pool.Borrow(func(worker *Migrator) (err error) {
+				out[i], err = worker.Migrate(in[i])
+				return
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range errs {
+		if errs[i] != nil {
+			return fmt.Errorf("ciphertext %d: %w", i, errs[i])
+		}
+	}
+
+	return nil
+}
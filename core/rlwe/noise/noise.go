@@ -0,0 +1,97 @@
+// Package noise provides executable variance-tracking models of the noise
+// growth of the rlwe.Evaluator operations. Each function mirrors the actual
+// algorithm of the operation it is named after (the doubling loop of InnerSum,
+// the shared premultiplication of Trace, the hybrid gadget key switch), so
+// that security and correctness reviews can reference a model that is checked
+// against the implementation by the package tests instead of a paper appendix.
+// The models treat the noise coefficients as independent sub-Gaussians and
+// track their variance; they are estimates for parameter selection and
+// review, not proven bounds.
+package noise
+
+import (
+	"math"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+)
+
+// Variance is the per-coefficient variance of the noise of a ciphertext, in
+// the coefficient domain.
+type Variance float64
+
+// Log2Std returns the log2 of the standard deviation of the noise, the unit
+// used by the rlwe.Noise* measurement helpers and by
+// ring.Ring.Log2OfStandardDeviation.
+func (v Variance) Log2Std() float64 {
+	return 0.5 * math.Log2(float64(v))
+}
+
+// FreshSK returns the noise variance of a fresh secret-key encryption, i.e.
+// the variance of the error distribution of the parameters.
+func FreshSK(params rlwe.Parameters) Variance {
+	std := params.NoiseFreshSK()
+	return Variance(std * std)
+}
+
+// FreshPK returns the noise variance of a fresh public-key encryption, which
+// additionally carries the noise of the public key spread by the ternary
+// encryption randomness.
+func FreshPK(params rlwe.Parameters) Variance {
+	std := params.NoiseFreshPK()
+	return Variance(std * std)
+}
+
+// Add returns the noise variance of the sum (or difference) of two
+// ciphertexts with independent noises: the variances add.
+func Add(a, b Variance) Variance {
+	return a + b
+}
+
+// MulPlaintext returns the noise variance after the multiplication by a
+// plaintext polynomial whose coefficients have standard deviation ptStd: the
+// negacyclic convolution sums N products of a noise coefficient by a
+// plaintext coefficient.
+func MulPlaintext(params rlwe.Parameters, in Variance, ptStd float64) Variance {
+	return in * Variance(float64(params.N())*ptStd*ptStd)
+}
+
+// KeySwitch returns the noise variance added by one key switch at levelQ with
+// an evaluation key generated at levelP with the given base-2 decomposition,
+// as estimated by rlwe.EstimateKeySwitchingNoise.
+func KeySwitch(params rlwe.Parameters, levelQ, levelP, baseTwoDecomposition int) Variance {
+	return Variance(math.Exp2(2 * rlwe.EstimateKeySwitchingNoise(params, levelQ, levelP, baseTwoDecomposition)))
+}
+
+// Automorphism returns the noise variance after an automorphism: the
+// permutation of the noise coefficients leaves the variance unchanged and the
+// key switch back to the original key adds its own noise.
+func Automorphism(params rlwe.Parameters, in Variance, levelQ, levelP, baseTwoDecomposition int) Variance {
+	return in + KeySwitch(params, levelQ, levelP, baseTwoDecomposition)
+}
+
+// InnerSum returns the noise variance after InnerSum with the given batch and
+// n. The implementation aggregates the n rotations with a doubling loop, so
+// each of the ~log2(n) steps doubles the accumulated variance and adds one
+// key-switch noise: unrolling gives n times the input variance plus n-1
+// key-switch noises.
+func InnerSum(params rlwe.Parameters, in Variance, n, levelQ, levelP, baseTwoDecomposition int) Variance {
+	return Variance(float64(n))*in + Variance(float64(n-1))*KeySwitch(params, levelQ, levelP, baseTwoDecomposition)
+}
+
+// Trace returns the noise variance after Trace(ct, logN) in the standard
+// ring. The premultiplication by (N/n)^-1 cancels exactly against the
+// multiplicity of the trace on both the message and the input noise, whose
+// variance therefore survives unchanged, and each of the N/n - 1 automorphism
+// terms of the expanded doubling loop contributes one key-switch noise.
+func Trace(params rlwe.Parameters, in Variance, logN, levelQ, levelP, baseTwoDecomposition int) Variance {
+	gap := 1 << (params.LogN() - 1 - logN)
+	return in + Variance(float64(gap-1))*KeySwitch(params, levelQ, levelP, baseTwoDecomposition)
+}
+
+// Rescale returns the noise variance after the division of the ciphertext by
+// its last modulus q: the noise is scaled down by q and the rounding of the
+// two polynomials adds an error of variance 1/12 per coefficient, spread on
+// the decryption by the secret of Hamming weight h, i.e. (1+h)/12.
+func Rescale(params rlwe.Parameters, in Variance, q uint64) Variance {
+	return in/Variance(float64(q)*float64(q)) + Variance(float64(1+params.XsHammingWeight())/12.0)
+}
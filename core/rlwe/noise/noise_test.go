@@ -0,0 +1,106 @@
+package noise_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/core/rlwe/noise"
+	"github.com/luxdefi/lattice/v5/ring"
+)
+
+// The models are executable documentation: each prediction is checked against
+// the noise measured on the matching rlwe.Evaluator operation.
+func TestNoiseModels(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := rlwe.NewParametersFromLiteral(rlwe.ParametersLiteral{
+		LogN: 10,
+		Q:    ring.Qi60[:4],
+		P:    ring.Pi60[:1],
+	})
+	require.NoError(t, err)
+
+	kgen := rlwe.NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+	enc := rlwe.NewEncryptor(params, sk)
+	dec := rlwe.NewDecryptor(params, sk)
+
+	levelQ := params.MaxLevelQ()
+	levelP := params.MaxLevelP()
+	ringQ := params.RingQ().AtLevel(levelQ)
+
+	measure := func(ct *rlwe.Ciphertext) float64 {
+		pt := dec.DecryptNew(ct)
+		if pt.IsNTT {
+			ringQ.INTT(pt.Value, pt.Value)
+		}
+		return ringQ.Log2OfStandardDeviation(pt.Value)
+	}
+
+	encryptZero := func() *rlwe.Ciphertext {
+		ct := rlwe.NewCiphertext(params, 1, levelQ)
+		require.NoError(t, enc.EncryptZero(ct))
+		return ct
+	}
+
+	t.Run("FreshSK", func(t *testing.T) {
+		have := measure(encryptZero())
+		want := noise.FreshSK(params).Log2Std()
+		require.InDelta(t, want, have, 0.5)
+	})
+
+	t.Run("Add", func(t *testing.T) {
+		v := noise.FreshSK(params)
+		ct0, ct1 := encryptZero(), encryptZero()
+		ringQ.Add(ct0.Value[0], ct1.Value[0], ct0.Value[0])
+		ringQ.Add(ct0.Value[1], ct1.Value[1], ct0.Value[1])
+		have := measure(ct0)
+		want := noise.Add(v, v).Log2Std()
+		require.InDelta(t, want, have, 0.5)
+	})
+
+	t.Run("Automorphism", func(t *testing.T) {
+		galEl := params.GaloisElement(1)
+		evk := rlwe.NewMemEvaluationKeySet(nil, kgen.GenGaloisKeysNew([]uint64{galEl}, sk)...)
+		eval := rlwe.NewEvaluator(params, evk)
+
+		ct := encryptZero()
+		require.NoError(t, eval.Automorphism(ct, galEl, ct))
+
+		have := measure(ct)
+		want := noise.Automorphism(params, noise.FreshSK(params), levelQ, levelP, 0).Log2Std()
+
+		// The key-switch term is a heuristic estimate
+		require.InDelta(t, want, have, 2)
+	})
+
+	t.Run("InnerSum", func(t *testing.T) {
+		batch, n := 1, 8
+		evk := rlwe.NewMemEvaluationKeySet(nil, kgen.GenGaloisKeysNew(rlwe.GaloisElementsForInnerSum(params, batch, n), sk)...)
+		eval := rlwe.NewEvaluator(params, evk)
+
+		ct := encryptZero()
+		require.NoError(t, eval.InnerSum(ct, batch, n, ct))
+
+		have := measure(ct)
+		want := noise.InnerSum(params, noise.FreshSK(params), n, levelQ, levelP, 0).Log2Std()
+
+		require.InDelta(t, want, have, 2)
+	})
+
+	t.Run("Trace", func(t *testing.T) {
+		logN := params.LogN() - 3
+		evk := rlwe.NewMemEvaluationKeySet(nil, kgen.GenGaloisKeysNew(rlwe.GaloisElementsForTrace(params, logN), sk)...)
+		eval := rlwe.NewEvaluator(params, evk)
+
+		ct := encryptZero()
+		require.NoError(t, eval.Trace(ct, logN, ct))
+
+		have := measure(ct)
+		want := noise.Trace(params, noise.FreshSK(params), logN, levelQ, levelP, 0).Log2Std()
+
+		require.InDelta(t, want, have, 2)
+	})
+}
@@ -0,0 +1,212 @@
+package rlwe
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// NoiseBoundTag is the metadata tag under which the NoiseEstimator stores
+// the tracked log2 upper bound on the absolute noise magnitude of a
+// ciphertext (see MetaData.SetTag).
+const NoiseBoundTag = "rlwe/noise"
+
+// NoiseEstimator estimates the noise of ciphertexts, both exactly, by
+// decrypting with the secret key and subtracting the expected plaintext
+// (see MeasureNoise), and statically, by tracking a worst-case bound on the
+// noise magnitude through the homomorphic operations via a metadata tag on
+// the ciphertexts (see MarkFreshSK and the Update methods).
+//
+// The tracked bounds follow standard worst-case heuristics and are
+// scheme-agnostic: they bound the noise polynomial of the rlwe ciphertext
+// and do not account for scheme-specific scaling (e.g. the CKKS scale or
+// the BGV plaintext modulus). NoiseBudget and RemainingLevels compare the
+// bound against the modulus at the level of the ciphertext, so users can
+// decide when to rescale or bootstrap.
+type NoiseEstimator struct {
+	params Parameters
+}
+
+// NewNoiseEstimator instantiates a new NoiseEstimator for the given
+// parameters.
+func NewNoiseEstimator(params ParameterProvider) NoiseEstimator {
+	return NoiseEstimator{params: *params.GetRLWEParameters()}
+}
+
+// SetNoiseBound sets the tracked log2 noise bound of the metadata.
+func SetNoiseBound(md *MetaData, bound float64) (err error) {
+	buff := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buff, math.Float64bits(bound))
+	return md.SetTag(NoiseBoundTag, buff)
+}
+
+// NoiseBound returns the tracked log2 noise bound of the metadata and
+// whether one is set.
+func NoiseBound(md *MetaData) (bound float64, ok bool) {
+	buff := md.Tag(NoiseBoundTag)
+	if len(buff) != 8 {
+		return 0, false
+	}
+	return math.Float64frombits(binary.LittleEndian.Uint64(buff)), true
+}
+
+// MeasureNoise returns the log2 of the standard deviation of the noise of
+// the input ciphertext with respect to the given expected plaintext and
+// secret key, by decrypting and subtracting the plaintext.
+func (est NoiseEstimator) MeasureNoise(ct *Ciphertext, want *Plaintext, sk *SecretKey) float64 {
+
+	have := NewPlaintext(est.params, ct.Level())
+
+	NewDecryptor(est.params, sk).Decrypt(ct, have)
+
+	ringQ := est.params.RingQ().AtLevel(ct.Level())
+
+	diff := ringQ.NewPoly()
+
+	// Maps the expected plaintext to the domain of the decryption.
+	ref := want.Value
+	if want.IsNTT != have.IsNTT {
+		ref = ringQ.NewPoly()
+		if have.IsNTT {
+			ringQ.NTT(want.Value, ref)
+		} else {
+			ringQ.INTT(want.Value, ref)
+		}
+	}
+
+	ringQ.Sub(have.Value, ref, diff)
+
+	if have.IsNTT {
+		ringQ.INTT(diff, diff)
+	}
+
+	return ringQ.Log2OfStandardDeviation(diff)
+}
+
+// MarkFreshSK tags the ciphertext with the noise bound of a fresh
+// secret-key encryption under the target parameters.
+func (est NoiseEstimator) MarkFreshSK(ct *Ciphertext) (err error) {
+	return SetNoiseBound(ct.MetaData, math.Log2(6*est.params.NoiseFreshSK()))
+}
+
+// MarkFreshPK tags the ciphertext with the noise bound of a fresh
+// public-key encryption under the target parameters.
+func (est NoiseEstimator) MarkFreshPK(ct *Ciphertext) (err error) {
+	return SetNoiseBound(ct.MetaData, math.Log2(6*est.params.NoiseFreshPK()))
+}
+
+// UpdateAdd sets the noise bound of opOut to the bound of the addition (or
+// subtraction) of op0 and op1, i.e. the sum of their bounds. Both operands
+// must carry a bound.
+func (est NoiseEstimator) UpdateAdd(op0, op1 *Ciphertext, opOut *Ciphertext) (err error) {
+
+	b0, b1, err := operandNoiseBounds(op0, op1)
+	if err != nil {
+		return fmt.Errorf("cannot UpdateAdd: %w", err)
+	}
+
+	return SetNoiseBound(opOut.MetaData, log2Add(b0, b1))
+}
+
+// UpdateMul sets the noise bound of opOut to the worst-case bound of the
+// tensoring of op0 and op1, i.e. N times the product of their bounds (the
+// ring expansion factor of the negacyclic convolution). Both operands must
+// carry a bound.
+func (est NoiseEstimator) UpdateMul(op0, op1 *Ciphertext, opOut *Ciphertext) (err error) {
+
+	b0, b1, err := operandNoiseBounds(op0, op1)
+	if err != nil {
+		return fmt.Errorf("cannot UpdateMul: %w", err)
+	}
+
+	return SetNoiseBound(opOut.MetaData, b0+b1+float64(est.params.LogN()))
+}
+
+// UpdateKeySwitch adds to the noise bound of ct the additive noise of a
+// key-switch (e.g. a relinearization or an automorphism) with the default
+// gadget parameters, estimated as a fresh encryption noise amplified by
+// the square root of the dimension of the gadget decomposition times the
+// ring degree. The ciphertext must carry a bound.
+func (est NoiseEstimator) UpdateKeySwitch(ct *Ciphertext) (err error) {
+
+	b, ok := NoiseBound(ct.MetaData)
+	if !ok {
+		return fmt.Errorf("cannot UpdateKeySwitch: input does not carry a noise bound")
+	}
+
+	ks := math.Log2(6*est.params.NoiseFreshSK()) + 0.5*math.Log2(float64(est.params.N()*(ct.Level()+1)))
+
+	return SetNoiseBound(ct.MetaData, log2Add(b, ks))
+}
+
+// NoiseBudget returns the number of noise bits left before the tracked
+// bound of the ciphertext reaches the modulus at its level, i.e.
+// log2(Q_level) - 1 - bound. A negative budget means decryption can no
+// longer be guaranteed to succeed.
+func (est NoiseEstimator) NoiseBudget(ct *Ciphertext) (budget float64, err error) {
+
+	b, ok := NoiseBound(ct.MetaData)
+	if !ok {
+		return 0, fmt.Errorf("cannot NoiseBudget: input does not carry a noise bound")
+	}
+
+	var logQ float64
+	for _, qi := range est.params.Q()[:ct.Level()+1] {
+		logQ += math.Log2(float64(qi))
+	}
+
+	return logQ - 1 - b, nil
+}
+
+// RemainingLevels returns the number of levels the ciphertext can still
+// descend before its noise budget is exhausted, i.e. the largest number of
+// top moduli of its chain that fit in its NoiseBudget. A return value of
+// zero indicates that the ciphertext should be bootstrapped (or decrypted)
+// before further rescaling.
+func (est NoiseEstimator) RemainingLevels(ct *Ciphertext) (levels int, err error) {
+
+	budget, err := est.NoiseBudget(ct)
+	if err != nil {
+		return 0, fmt.Errorf("cannot RemainingLevels: %w", err)
+	}
+
+	q := est.params.Q()
+	for l := ct.Level(); l > 0; l-- {
+
+		logql := math.Log2(float64(q[l]))
+
+		if budget < logql {
+			break
+		}
+
+		budget -= logql
+		levels++
+	}
+
+	return
+}
+
+// operandNoiseBounds returns the noise bounds of the two operands, erroring
+// if either does not carry one.
+func operandNoiseBounds(op0, op1 *Ciphertext) (b0, b1 float64, err error) {
+
+	var ok bool
+
+	if b0, ok = NoiseBound(op0.MetaData); !ok {
+		return 0, 0, fmt.Errorf("first operand does not carry a noise bound")
+	}
+
+	if b1, ok = NoiseBound(op1.MetaData); !ok {
+		return 0, 0, fmt.Errorf("second operand does not carry a noise bound")
+	}
+
+	return
+}
+
+// log2Add returns log2(2^a + 2^b) without overflowing the exponents.
+func log2Add(a, b float64) float64 {
+	if a < b {
+		a, b = b, a
+	}
+	return a + math.Log2(1+math.Exp2(b-a))
+}
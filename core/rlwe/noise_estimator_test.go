@@ -0,0 +1,79 @@
+package rlwe
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoiseEstimator(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN: 10,
+		LogQ: []int{55, 45, 45},
+		LogP: []int{60},
+	})
+	require.NoError(t, err)
+
+	kgen := NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+	enc := NewEncryptor(params, sk)
+
+	est := NewNoiseEstimator(params)
+
+	ct := NewCiphertext(params, 1, params.MaxLevel())
+	require.NoError(t, enc.EncryptZero(ct))
+
+	// The measured noise of a fresh encryption of zero matches the
+	// fresh secret-key noise of the parameters.
+	want := NewPlaintext(params, ct.Level())
+	require.InDelta(t, math.Log2(params.NoiseFreshSK()), est.MeasureNoise(ct, want, sk), 1)
+
+	// The tracked bound upper-bounds the measured noise.
+	require.NoError(t, est.MarkFreshSK(ct))
+	bound, ok := NoiseBound(ct.MetaData)
+	require.True(t, ok)
+	require.Greater(t, bound, est.MeasureNoise(ct, want, sk))
+
+	// A fresh ciphertext has its full budget: all levels remain.
+	budget, err := est.NoiseBudget(ct)
+	require.NoError(t, err)
+	require.Greater(t, budget, 0.0)
+
+	levels, err := est.RemainingLevels(ct)
+	require.NoError(t, err)
+	require.Equal(t, params.MaxLevel(), levels)
+
+	// Addition adds the bounds, multiplication compounds them by the
+	// ring expansion factor, and a key-switch adds a small term.
+	ctAdd := ct.CopyNew()
+	require.NoError(t, est.UpdateAdd(ct, ct, ctAdd))
+	bAdd, ok := NoiseBound(ctAdd.MetaData)
+	require.True(t, ok)
+	require.InDelta(t, bound+1, bAdd, 1e-9)
+
+	ctMul := ct.CopyNew()
+	require.NoError(t, est.UpdateMul(ct, ct, ctMul))
+	bMul, ok := NoiseBound(ctMul.MetaData)
+	require.True(t, ok)
+	require.InDelta(t, 2*bound+float64(params.LogN()), bMul, 1e-9)
+
+	require.NoError(t, est.UpdateKeySwitch(ctMul))
+	bKS, ok := NoiseBound(ctMul.MetaData)
+	require.True(t, ok)
+	require.Greater(t, bKS, bMul)
+
+	// The multiplied ciphertext has a smaller budget.
+	budgetMul, err := est.NoiseBudget(ctMul)
+	require.NoError(t, err)
+	require.Less(t, budgetMul, budget)
+
+	// Untracked ciphertexts are rejected.
+	ctBare := NewCiphertext(params, 1, params.MaxLevel())
+	_, err = est.NoiseBudget(ctBare)
+	require.Error(t, err)
+	require.Error(t, est.UpdateAdd(ct, ctBare, ctAdd))
+	require.Error(t, est.UpdateKeySwitch(ctBare))
+}
@@ -6,6 +6,7 @@ import (
 	"math/bits"
 
 	"github.com/luxdefi/lattice/v5/ring"
+	"github.com/luxdefi/lattice/v5/ring/ringqp"
 	"github.com/luxdefi/lattice/v5/utils"
 )
 
@@ -79,7 +80,7 @@ func (eval Evaluator) Trace(ctIn *Ciphertext, logN int, opOut *Ciphertext) (err
 		// Sanity check, this error should not happen unless the
 		// evaluator's buffer thave been improperly tempered with.
 		if err != nil {
-			panic(err)
+			return Fault(err)
 		}
 
 		buff.IsNTT = true
@@ -119,6 +120,149 @@ func (eval Evaluator) Trace(ctIn *Ciphertext, logN int, opOut *Ciphertext) (err
 	return
 }
 
+// TraceHoisted is similar to Trace, except that it evaluates the sum over the subgroup with a single
+// hoisted RNS decomposition of the input shared by all the key-switches, and accumulates the
+// key-switched ciphertexts in the extended basis QP with a single division by P, instead of chaining
+// log2(N/n) automorphisms each with its own decomposition and division.
+// It key-switches by all the 2^{LogN-1-logN}-1 non-trivial automorphisms of the subgroup, so it
+// requires the Galois keys of GaloisElementsForTraceHoisted and is advantageous over Trace for
+// small subgroups only (i.e. logN close to LogN).
+// The method will return an error if the input and output ciphertexts degree is not one, or if the
+// parameters have no extended basis P.
+func (eval Evaluator) TraceHoisted(ctIn *Ciphertext, logN int, opOut *Ciphertext) (err error) {
+
+	if ctIn.Degree() != 1 || opOut.Degree() != 1 {
+		return fmt.Errorf("ctIn.Degree() != 1 or opOut.Degree() != 1")
+	}
+
+	params := eval.GetRLWEParameters()
+
+	levelQ := utils.Min(ctIn.Level(), opOut.Level())
+	levelP := params.MaxLevelP()
+
+	if levelP == -1 {
+		return fmt.Errorf("cannot TraceHoisted: method requires an extended basis P")
+	}
+
+	opOut.Resize(opOut.Degree(), levelQ)
+
+	*opOut.MetaData = *ctIn.MetaData
+
+	gap := 1 << (params.LogN() - logN - 1)
+
+	if logN == 0 {
+		gap <<= 1
+	}
+
+	if gap == 1 {
+		if ctIn != opOut {
+			opOut.Copy(ctIn)
+		}
+
+		return
+	}
+
+	ringQ := params.RingQ().AtLevel(levelQ)
+	ringQP := params.RingQP().AtLevel(levelQ, levelP)
+
+	if ringQ.Type() == ring.ConjugateInvariant {
+		gap >>= 1 // We skip the last step that applies phi(5^{-1})
+	}
+
+	NInv := new(big.Int).SetUint64(uint64(gap))
+	NInv.ModInverse(NInv, ringQ.ModulusAtLevel[levelQ])
+
+	ctInNTT, err := NewCiphertextAtLevelFromPoly(levelQ, eval.BuffCt.Value[:2])
+
+	// Sanity check, this error should not happen unless the
+	// evaluator's buffer thave been improperly tempered with.
+	if err != nil {
+		return Fault(err)
+	}
+
+	ctInNTT.MetaData = &MetaData{}
+	ctInNTT.IsNTT = true
+
+	// pre-multiplication by (N/n)^-1
+	ringQ.MulScalarBigint(ctIn.Value[0], NInv, ctInNTT.Value[0])
+	ringQ.MulScalarBigint(ctIn.Value[1], NInv, ctInNTT.Value[1])
+
+	if !ctIn.IsNTT {
+		ringQ.NTT(ctInNTT.Value[0], ctInNTT.Value[0])
+		ringQ.NTT(ctInNTT.Value[1], ctInNTT.Value[1])
+	}
+
+	// BuffQP[0:2] are used by AutomorphismHoistedLazy
+
+	// Accumulator mod QP
+	accQP := &Element[ringqp.Poly]{Value: []ringqp.Poly{eval.BuffQP[2], eval.BuffQP[3]}}
+	accQP.MetaData = ctInNTT.MetaData
+
+	// Buffer mod QP to store the result of the lazy key-switches
+	cQP := &Element[ringqp.Poly]{Value: []ringqp.Poly{eval.BuffQP[4], eval.BuffQP[5]}}
+	cQP.MetaData = ctInNTT.MetaData
+
+	// Single decomposition shared by all the key-switches of the subgroup
+	eval.DecomposeNTT(levelQ, levelP, levelP+1, ctInNTT.Value[1], true, eval.BuffDecompQP)
+
+	for j := 1; j < 1<<(params.LogN()-1-logN); j++ {
+
+		galEl := params.GaloisElement(j << logN)
+
+		if j == 1 {
+			if err = eval.AutomorphismHoistedLazy(levelQ, ctInNTT, eval.BuffDecompQP, galEl, accQP); err != nil {
+				return err
+			}
+		} else {
+
+			if err = eval.AutomorphismHoistedLazy(levelQ, ctInNTT, eval.BuffDecompQP, galEl, cQP); err != nil {
+				return err
+			}
+
+			ringQP.Add(accQP.Value[0], cQP.Value[0], accQP.Value[0])
+			ringQP.Add(accQP.Value[1], cQP.Value[1], accQP.Value[1])
+		}
+	}
+
+	// opOut = acc/P + ctInNTT
+	eval.BasisExtender.ModDownQPtoQNTT(levelQ, levelP, accQP.Value[0].Q, accQP.Value[0].P, opOut.Value[0])
+	eval.BasisExtender.ModDownQPtoQNTT(levelQ, levelP, accQP.Value[1].Q, accQP.Value[1].P, opOut.Value[1])
+
+	ringQ.Add(opOut.Value[0], ctInNTT.Value[0], opOut.Value[0])
+	ringQ.Add(opOut.Value[1], ctInNTT.Value[1], opOut.Value[1])
+
+	opOut.IsNTT = true
+
+	if logN == 0 && ringQ.Type() == ring.Standard {
+
+		buff, err := NewCiphertextAtLevelFromPoly(levelQ, []ring.Poly{eval.BuffQP[4].Q, eval.BuffQP[5].Q})
+
+		// Sanity check, this error should not happen unless the
+		// evaluator's buffer thave been improperly tempered with.
+		if err != nil {
+			return Fault(err)
+		}
+
+		buff.MetaData = &MetaData{}
+		buff.IsNTT = true
+
+		if err = eval.Automorphism(opOut, ringQ.NthRoot()-1, buff); err != nil {
+			return err
+		}
+
+		ringQ.Add(opOut.Value[0], buff.Value[0], opOut.Value[0])
+		ringQ.Add(opOut.Value[1], buff.Value[1], opOut.Value[1])
+	}
+
+	if !ctIn.IsNTT {
+		ringQ.INTT(opOut.Value[0], opOut.Value[0])
+		ringQ.INTT(opOut.Value[1], opOut.Value[1])
+		opOut.IsNTT = false
+	}
+
+	return
+}
+
 // GaloisElementsForTrace returns the list of Galois elements requored for the for the `Trace` operation.
 // Trace maps X -> sum((-1)^i * X^{i*n+1}) for 2^{LogN} <= i < N.
 func GaloisElementsForTrace(params ParameterProvider, logN int) (galEls []uint64) {
@@ -144,6 +288,25 @@ func GaloisElementsForTrace(params ParameterProvider, logN int) (galEls []uint64
 	return
 }
 
+// GaloisElementsForTraceHoisted returns the list of Galois elements required for the `TraceHoisted` operation,
+// i.e. all the non-trivial elements GaloisGen^{j*2^{logN}} of the subgroup summed over (and the Galois element
+// of the order two orthogonal subgroup if logN == 0).
+func GaloisElementsForTraceHoisted(params ParameterProvider, logN int) (galEls []uint64) {
+
+	p := params.GetRLWEParameters()
+
+	galEls = []uint64{}
+	for j := 1; j < 1<<(p.LogN()-1-logN); j++ {
+		galEls = append(galEls, p.GaloisElement(j<<logN))
+	}
+
+	if logN == 0 && p.RingType() == ring.Standard {
+		galEls = append(galEls, p.GaloisElementOrderTwoOrthogonalSubgroup())
+	}
+
+	return
+}
+
 // Expand expands a RLWE Ciphertext encrypting sum ai * X^i to 2^logN ciphertexts,
 // each encrypting ai * X^0 for 0 <= i < 2^LogN. That is, it extracts the first 2^logN
 // coefficients, whose degree is a multiple of 2^logGap, of ctIn and returns an RLWE
@@ -195,7 +358,7 @@ func (eval Evaluator) Expand(ctIn *Ciphertext, logN, logGap int) (opOut []*Ciphe
 	// Sanity check, this error should not happen unless the
 	// evaluator's buffer thave been improperly tempered with.
 	if err != nil {
-		panic(err)
+		return nil, Fault(err)
 	}
 
 	tmp.MetaData = ctIn.MetaData
@@ -74,6 +74,7 @@ type Parameters struct {
 	ringType     ring.Type
 	defaultScale Scale
 	nttFlag      bool
+	fingerprint  uint64
 }
 
 // NewParameters returns a new set of generic RLWE parameters from the given ring degree logn, moduli q and p, and
@@ -135,6 +136,8 @@ func NewParameters(logn int, q, p []uint64, xs, xe DistributionLiteral, ringType
 		return Parameters{}, err
 	}
 
+	params.fingerprint = fingerprintOf(params)
+
 	var warning error
 	if params.XsHammingWeight() == 0 {
 		warning = fmt.Errorf("warning secret standard HammingWeight is 0")
@@ -593,6 +596,68 @@ func (p Parameters) SolveDiscreteLogGaloisElement(galEl uint64) (k int) {
 	}
 }
 
+// CheckGaloisElement checks that galEl defines a valid automorphism
+// X^{i} -> X^{i*galEl} over the ring of the receiver, i.e. that galEl
+// is an integer in [1, NthRoot-1] coprime with NthRoot. Since NthRoot
+// is a power of two, coprimality amounts to galEl being odd.
+func (p Parameters) CheckGaloisElement(galEl uint64) (err error) {
+	NthRoot := p.ringQ.NthRoot()
+	if galEl == 0 || galEl >= NthRoot {
+		return fmt.Errorf("invalid Galois element %d: must lie in [1, NthRoot-1=%d]", galEl, NthRoot-1)
+	}
+	if galEl&1 == 0 {
+		return fmt.Errorf("invalid Galois element %d: must be odd to be coprime with NthRoot=%d", galEl, NthRoot)
+	}
+	return
+}
+
+// GaloisElementForRotation returns the Galois element GaloisGen^{k} mod NthRoot
+// generating the automorphism that acts as a cyclic rotation by k positions on
+// batched plaintexts. It is a clearer-named alias of GaloisElement.
+func (p Parameters) GaloisElementForRotation(k int) uint64 {
+	return p.GaloisElement(k)
+}
+
+// GaloisElementForConjugation returns the Galois element GaloisGen^{-1} mod NthRoot
+// generating the automorphism that acts as the complex conjugation on batched
+// plaintexts. Unlike GaloisElementOrderTwoOrthogonalSubgroup, it returns an
+// error instead of panicking when the ring type is ConjugateInvariant, for
+// which the conjugation is not defined.
+func (p Parameters) GaloisElementForConjugation() (galEl uint64, err error) {
+	if p.ringType == ring.ConjugateInvariant {
+		return 0, fmt.Errorf("cannot GaloisElementForConjugation: undefined for RingType ConjugateInvariant")
+	}
+	return p.ringQ.NthRoot() - 1, nil
+}
+
+// RotationFromGaloisElement takes a Galois element of the form GaloisGen^{k} mod NthRoot
+// and returns the rotation k it generates. Unlike SolveDiscreteLogGaloisElement, it
+// returns an error if galEl is not a valid Galois element (see CheckGaloisElement)
+// or does not belong to the subgroup generated by GaloisGen (e.g. the conjugation
+// element), for which no rotation exists.
+func (p Parameters) RotationFromGaloisElement(galEl uint64) (k int, err error) {
+	if err = p.CheckGaloisElement(galEl); err != nil {
+		return 0, err
+	}
+	k = p.SolveDiscreteLogGaloisElement(galEl)
+	if p.GaloisElement(k) != galEl {
+		return 0, fmt.Errorf("invalid Galois element %d: not in the subgroup generated by GaloisGen, no rotation maps to it", galEl)
+	}
+	return
+}
+
+// AllGaloisElements returns the list of all the valid Galois elements for the
+// ring of the receiver, i.e. the NthRoot/2 odd integers in [1, NthRoot-1]
+// (see CheckGaloisElement).
+func (p Parameters) AllGaloisElements() (galEls []uint64) {
+	NthRoot := p.ringQ.NthRoot()
+	galEls = make([]uint64, 0, NthRoot>>1)
+	for galEl := uint64(1); galEl < NthRoot; galEl += 2 {
+		galEls = append(galEls, galEl)
+	}
+	return
+}
+
 // Equal checks two Parameter structs for equality.
 func (p Parameters) Equal(other *Parameters) (res bool) {
 	res = p.logN == other.logN
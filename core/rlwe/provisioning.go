@@ -0,0 +1,143 @@
+package rlwe
+
+import (
+	"fmt"
+
+	"github.com/luxdefi/lattice/v5/utils"
+)
+
+// CircuitDescription describes the homomorphic operations evaluated by a
+// circuit, in terms of the key material they require. A description can be
+// filled in directly, or populated through the Record methods during a
+// dry run of the circuit, and then handed to
+// KeyGenerator.GenEvaluationKeySetForCircuitNew to provision exactly the
+// required keys.
+type CircuitDescription struct {
+	// Rotations lists the plaintext-slot rotations evaluated by the circuit.
+	Rotations []int
+	// Conjugation is set if the circuit evaluates the complex conjugation
+	// (or row rotation).
+	Conjugation bool
+	// InnerSums lists the (batchSize, n) arguments of the calls to
+	// Evaluator.InnerSum.
+	InnerSums [][2]int
+	// Replications lists the (batchSize, n) arguments of the calls to
+	// Evaluator.Replicate.
+	Replications [][2]int
+	// Traces lists the logN arguments of the calls to Evaluator.Trace.
+	Traces []int
+	// GaloisElements lists additional raw Galois elements, for operations
+	// that are not expressible through the fields above.
+	GaloisElements []uint64
+	// Relinearization is set if the circuit multiplies ciphertexts together
+	// and relinearizes the result.
+	Relinearization bool
+}
+
+// RecordRotation records plaintext-slot rotations by the values in ks.
+func (cd *CircuitDescription) RecordRotation(ks ...int) {
+	cd.Rotations = append(cd.Rotations, ks...)
+}
+
+// RecordConjugation records an evaluation of the complex conjugation (or row
+// rotation).
+func (cd *CircuitDescription) RecordConjugation() {
+	cd.Conjugation = true
+}
+
+// RecordInnerSum records a call to Evaluator.InnerSum with the arguments
+// batchSize and n.
+func (cd *CircuitDescription) RecordInnerSum(batchSize, n int) {
+	cd.InnerSums = append(cd.InnerSums, [2]int{batchSize, n})
+}
+
+// RecordReplicate records a call to Evaluator.Replicate with the arguments
+// batchSize and n.
+func (cd *CircuitDescription) RecordReplicate(batchSize, n int) {
+	cd.Replications = append(cd.Replications, [2]int{batchSize, n})
+}
+
+// RecordTrace records a call to Evaluator.Trace with the argument logN.
+func (cd *CircuitDescription) RecordTrace(logN int) {
+	cd.Traces = append(cd.Traces, logN)
+}
+
+// RecordGaloisElement records evaluations of the automorphisms defined by the
+// raw Galois elements galEls.
+func (cd *CircuitDescription) RecordGaloisElement(galEls ...uint64) {
+	cd.GaloisElements = append(cd.GaloisElements, galEls...)
+}
+
+// RecordRelinearization records a relinearization after a ciphertext-ciphertext
+// multiplication.
+func (cd *CircuitDescription) RecordRelinearization() {
+	cd.Relinearization = true
+}
+
+// GaloisElementsRequired returns the sorted and deduplicated set of Galois
+// elements required by the described circuit under the given parameters.
+func (cd CircuitDescription) GaloisElementsRequired(params ParameterProvider) (galEls []uint64, err error) {
+
+	p := params.GetRLWEParameters()
+
+	set := map[uint64]bool{}
+
+	for _, k := range cd.Rotations {
+		set[p.GaloisElement(k)] = true
+	}
+
+	if cd.Conjugation {
+		galEl, err := p.GaloisElementForConjugation()
+		if err != nil {
+			return nil, fmt.Errorf("cannot GaloisElementsRequired: %w", err)
+		}
+		set[galEl] = true
+	}
+
+	for _, args := range cd.InnerSums {
+		for _, galEl := range GaloisElementsForInnerSum(params, args[0], args[1]) {
+			set[galEl] = true
+		}
+	}
+
+	for _, args := range cd.Replications {
+		for _, galEl := range GaloisElementsForReplicate(params, args[0], args[1]) {
+			set[galEl] = true
+		}
+	}
+
+	for _, logN := range cd.Traces {
+		for _, galEl := range GaloisElementsForTrace(params, logN) {
+			set[galEl] = true
+		}
+	}
+
+	for _, galEl := range cd.GaloisElements {
+		if err = p.CheckGaloisElement(galEl); err != nil {
+			return nil, fmt.Errorf("cannot GaloisElementsRequired: %w", err)
+		}
+		set[galEl] = true
+	}
+
+	return utils.GetSortedKeys(set), nil
+}
+
+// GenEvaluationKeySetForCircuitNew generates exactly the key material required
+// by the described circuit (the Galois keys for its rotations, conjugation,
+// inner sums, replications and traces, and the relinearization key if it
+// relinearizes) and packages it into a MemEvaluationKeySet. The optional
+// evkParams apply to all the generated keys.
+func (kgen KeyGenerator) GenEvaluationKeySetForCircuitNew(sk *SecretKey, cd CircuitDescription, evkParams ...EvaluationKeyParameters) (evk *MemEvaluationKeySet, err error) {
+
+	galEls, err := cd.GaloisElementsRequired(kgen.params)
+	if err != nil {
+		return nil, fmt.Errorf("cannot GenEvaluationKeySetForCircuitNew: %w", err)
+	}
+
+	var rlk *RelinearizationKey
+	if cd.Relinearization {
+		rlk = kgen.GenRelinearizationKeyNew(sk, evkParams...)
+	}
+
+	return NewMemEvaluationKeySet(rlk, kgen.GenGaloisKeysNew(galEls, sk, evkParams...)...), nil
+}
@@ -0,0 +1,119 @@
+package rlwe
+
+import (
+	"fmt"
+
+	"github.com/luxdefi/lattice/v5/ring/ringqp"
+)
+
+// ReEncryptionKey is a public key providing a one way, public and non-interactive
+// re-encryption from a ciphertext encrypted under the secret key it was generated
+// from to a ciphertext encrypted under the public key it was generated for.
+//
+// It has the same structure as an EvaluationKey, but its gadget ciphertext is
+// produced with the recipient public key instead of the recipient secret key, so
+// that it can be generated by the delegating party alone: neither the delegator
+// nor the proxy applying the key learns the recipient secret key. The scheme is
+// unidirectional; delegating in the other direction requires a second key.
+type ReEncryptionKey struct {
+	EvaluationKey
+}
+
+// NewReEncryptionKey returns a new ReEncryptionKey with pre-allocated zero-value.
+func NewReEncryptionKey(params ParameterProvider, evkParams ...EvaluationKeyParameters) *ReEncryptionKey {
+	return &ReEncryptionKey{EvaluationKey: *NewEvaluationKey(params, evkParams...)}
+}
+
+// CopyNew creates a deep copy of the target ReEncryptionKey and returns it.
+func (rek ReEncryptionKey) CopyNew() *ReEncryptionKey {
+	return &ReEncryptionKey{EvaluationKey: *rek.EvaluationKey.CopyNew()}
+}
+
+// Equal performs a deep equal.
+func (rek ReEncryptionKey) Equal(other *ReEncryptionKey) bool {
+	return rek.EvaluationKey.Equal(&other.EvaluationKey)
+}
+
+// ReEncryptionKeyGenerator is a structure that stores the elements required to
+// generate re-encryption keys from a delegating secret key and a recipient
+// public key.
+type ReEncryptionKeyGenerator struct {
+	KeyGenerator
+}
+
+// NewReEncryptionKeyGenerator creates a new ReEncryptionKeyGenerator.
+func NewReEncryptionKeyGenerator(params ParameterProvider) *ReEncryptionKeyGenerator {
+	return &ReEncryptionKeyGenerator{KeyGenerator: *NewKeyGenerator(params)}
+}
+
+// GenReEncryptionKeyNew generates a new ReEncryptionKey, that will re-encrypt a
+// Ciphertext encrypted under skFrom into a Ciphertext encrypted under the secret
+// key of pkTo. The generation only requires the public key of the recipient.
+//
+// Each element of the key is a fresh public-key encryption of zero under pkTo,
+// so the noise of the re-encrypted ciphertext grows by the public-key
+// key-switching noise instead of the (smaller) secret-key one.
+func (kgen ReEncryptionKeyGenerator) GenReEncryptionKeyNew(skFrom *SecretKey, pkTo *PublicKey, evkParams ...EvaluationKeyParameters) (rek *ReEncryptionKey) {
+	levelQ, levelP, BaseTwoDecomposition := ResolveEvaluationKeyParameters(kgen.params, evkParams)
+	rek = &ReEncryptionKey{EvaluationKey: *newEvaluationKey(kgen.params, levelQ, levelP, BaseTwoDecomposition)}
+	kgen.GenReEncryptionKey(skFrom, pkTo, rek)
+	return
+}
+
+// GenReEncryptionKey generates a ReEncryptionKey, that will re-encrypt a Ciphertext
+// encrypted under skFrom into a Ciphertext encrypted under the secret key of pkTo
+// (see GenReEncryptionKeyNew).
+func (kgen ReEncryptionKeyGenerator) GenReEncryptionKey(skFrom *SecretKey, pkTo *PublicKey, rek *ReEncryptionKey) {
+
+	enc := kgen.WithKey(pkTo)
+
+	// Samples a public-key encryption of zero for each element of the ReEncryptionKey.
+	for i := 0; i < len(rek.Value); i++ {
+		for j := 0; j < len(rek.Value[i]); j++ {
+			if err := enc.EncryptZero(Element[ringqp.Poly]{MetaData: &MetaData{CiphertextMetaData: CiphertextMetaData{IsNTT: true, IsMontgomery: true}}, Value: []ringqp.Poly(rek.Value[i][j])}); err != nil {
+				// Sanity check, this error should not happen.
+				panic(err)
+			}
+		}
+	}
+
+	// Adds the plaintext (delegating key) to the ReEncryptionKey.
+	if err := AddPolyTimesGadgetVectorToGadgetCiphertext(skFrom.Value.Q, []GadgetCiphertext{rek.GadgetCiphertext}, *kgen.params.RingQP(), kgen.buffQ[0]); err != nil {
+		// Sanity check, this error should not happen.
+		panic(err)
+	}
+
+	if kgen.domain == KeyDomainCoefficient {
+		SwitchGadgetCiphertextDomain(kgen.params, KeyDomainCoefficient, &rek.GadgetCiphertext)
+	}
+}
+
+// ReEncryptor is a structure that applies re-encryption keys, re-encrypting
+// ciphertexts from one key to another without intermediate decryption. It is
+// intended to be instantiated by a proxy holding neither secret key.
+type ReEncryptor struct {
+	*Evaluator
+}
+
+// NewReEncryptor creates a new ReEncryptor.
+func NewReEncryptor(params ParameterProvider) *ReEncryptor {
+	return &ReEncryptor{Evaluator: NewEvaluator(params, nil)}
+}
+
+// ReEncrypt re-encrypts ctIn, decryptable by the secret key rek was generated
+// from, and writes on opOut a ciphertext decryptable by the secret key of the
+// public key rek was generated for. The method will return an error if either
+// ctIn or opOut degree is not equal to 1.
+func (renc ReEncryptor) ReEncrypt(ctIn *Ciphertext, rek *ReEncryptionKey, opOut *Ciphertext) (err error) {
+	if err = renc.ApplyEvaluationKey(ctIn, &rek.EvaluationKey, opOut); err != nil {
+		return fmt.Errorf("cannot ReEncrypt: %w", err)
+	}
+	return
+}
+
+// ShallowCopy creates a shallow copy of this ReEncryptor in which all the read-only
+// data-structures are shared with the receiver and the temporary buffers are
+// reallocated. The receiver and the returned ReEncryptor can be used concurrently.
+func (renc ReEncryptor) ShallowCopy() *ReEncryptor {
+	return &ReEncryptor{Evaluator: renc.Evaluator.ShallowCopy()}
+}
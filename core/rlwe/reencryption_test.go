@@ -0,0 +1,75 @@
+package rlwe
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReEncryption(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN: 10,
+		LogQ: []int{55, 45},
+		LogP: []int{60},
+	})
+	require.NoError(t, err)
+
+	kgen := NewKeyGenerator(params)
+	skA := kgen.GenSecretKeyNew()
+	skB, pkB := kgen.GenKeyPairNew()
+
+	rek := NewReEncryptionKeyGenerator(params).GenReEncryptionKeyNew(skA, pkB)
+
+	level := params.MaxLevel()
+	ringQ := params.RingQ().AtLevel(level)
+
+	pt := NewPlaintext(params, level)
+	for i, s := range ringQ.SubRings[:level+1] {
+		for j := range pt.Value.Coeffs[i] {
+			pt.Value.Coeffs[i][j] = (uint64(j%16) << 40) % s.Modulus
+		}
+	}
+
+	ctA := NewCiphertext(params, 1, level)
+	require.NoError(t, NewEncryptor(params, skA).Encrypt(pt, ctA))
+
+	renc := NewReEncryptor(params)
+
+	ctB := NewCiphertext(params, 1, level)
+	require.NoError(t, renc.ReEncrypt(ctA, rek, ctB))
+
+	// The public-key encryptions of zero in the re-encryption key make the
+	// key-switching noise grow by the fresh public-key noise.
+	NoiseBound := float64(params.LogN()) + math.Log2(params.NoiseFreshPK())
+
+	ptB := NewDecryptor(params, skB).DecryptNew(ctB)
+	ringQ.Sub(ptB.Value, pt.Value, ptB.Value)
+	if ptB.IsNTT {
+		ringQ.INTT(ptB.Value, ptB.Value)
+	}
+	require.GreaterOrEqual(t, NoiseBound, ringQ.Log2OfStandardDeviation(ptB.Value))
+
+	// The re-encrypted ciphertext no longer decrypts under the delegating key.
+	ptA := NewDecryptor(params, skA).DecryptNew(ctB)
+	ringQ.Sub(ptA.Value, pt.Value, ptA.Value)
+	if ptA.IsNTT {
+		ringQ.INTT(ptA.Value, ptA.Value)
+	}
+	require.Less(t, NoiseBound, ringQ.Log2OfStandardDeviation(ptA.Value))
+
+	// Serialization roundtrip.
+	data, err := rek.MarshalBinary()
+	require.NoError(t, err)
+	require.Len(t, data, rek.BinarySize())
+
+	rekOut := new(ReEncryptionKey)
+	require.NoError(t, rekOut.UnmarshalBinary(data))
+	require.True(t, rek.Equal(rekOut))
+
+	ctBis := NewCiphertext(params, 1, level)
+	require.NoError(t, renc.ReEncrypt(ctA, rekOut, ctBis))
+	require.True(t, ctB.Equal(ctBis))
+}
@@ -1,11 +1,17 @@
 package rlwe
 
 import (
+	"bufio"
+	"bytes"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"math"
+	"math/big"
 	"runtime"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -239,6 +245,92 @@ func testKeyGenerator(tc *TestContext, bpw2 int, t *testing.T) {
 
 	})
 
+	// Checks that the additive shares recombine to the secret key and that a
+	// single share is independent of it
+	t.Run(testString(params, params.MaxLevelQ(), params.MaxLevelP(), bpw2, "KeyGenerator/SecretKeyShares"), func(t *testing.T) {
+
+		prng, _ := sampling.NewPRNG()
+
+		share0, share1 := NewSecretKeyShares(params, prng, sk)
+
+		require.True(t, sk.Equal(NewSecretKeyFromShares(params, share0, share1)))
+
+		// The masking share must differ from the key
+		require.False(t, sk.Value.EqualConstantTime(&share0.Value))
+		require.False(t, sk.Value.EqualConstantTime(&share1.Value))
+
+		// Distinct splits of the same key must use distinct masks
+		share2, share3 := NewSecretKeyShares(params, prng, sk)
+		require.False(t, share0.Equal(share2))
+		require.False(t, share1.Equal(share3))
+		require.True(t, sk.Equal(NewSecretKeyFromShares(params, share2, share3)))
+	})
+
+	// Checks that the matrix of pairwise key-switching keys switches between
+	// every ordered pair of keys and that the seeded variant derives the
+	// uniform key components from the seed
+	t.Run(testString(params, params.MaxLevelQ(), params.MaxLevelP(), bpw2, "KeyGenerator/KeySwitchingMatrix"), func(t *testing.T) {
+
+		var NoiseBound = float64(params.LogN() + bpw2)
+
+		evkParams := EvaluationKeyParameters{BaseTwoDecomposition: utils.Pointy(bpw2)}
+
+		sks := []*SecretKey{sk, kgen.GenSecretKeyNew(), kgen.GenSecretKeyNew()}
+
+		m := kgen.GenKeySwitchingMatrixNew(sks, evkParams)
+
+		require.Equal(t, len(sks), len(m))
+
+		for i := range sks {
+
+			require.Nil(t, m[i][i])
+
+			for j := range sks {
+
+				if i == j {
+					continue
+				}
+
+				evk, err := m.SwitchingKey(i, j)
+				require.NoError(t, err)
+
+				// Test that Dec(KS(Enc(0, sks[i]), sks[j]), sks[j]) has a small norm
+				ct := NewEncryptor(params, sks[i]).EncryptZeroNew(params.MaxLevel())
+				require.NoError(t, tc.eval.ApplyEvaluationKey(ct, evk, ct))
+
+				pt := NewDecryptor(params, sks[j]).DecryptNew(ct)
+
+				ringQ := params.RingQ()
+
+				if pt.IsNTT {
+					ringQ.INTT(pt.Value, pt.Value)
+				}
+
+				require.GreaterOrEqual(t, NoiseBound, ringQ.Log2OfStandardDeviation(pt.Value))
+			}
+		}
+
+		_, err := m.SwitchingKey(0, 0)
+		require.Error(t, err)
+		_, err = m.SwitchingKey(0, len(sks))
+		require.Error(t, err)
+
+		// Two generations under the same seed share their uniform components
+		// but not their error components
+		seed := []byte{'k', 's', 'm', 'a', 't', 'r', 'i', 'x'}
+
+		m0, err := kgen.GenSeededKeySwitchingMatrixNew(seed, sks, evkParams)
+		require.NoError(t, err)
+		m1, err := kgen.GenSeededKeySwitchingMatrixNew(seed, sks, evkParams)
+		require.NoError(t, err)
+
+		c0 := m0[0][1].Value[0][0]
+		c1 := m1[0][1].Value[0][0]
+
+		require.True(t, c0[1].Equal(&c1[1]))
+		require.False(t, c0[0].Equal(&c1[0]))
+	})
+
 	// Checks that sum([-as + e, a] + [as])) <= N * 6 * sigma
 	t.Run(testString(params, params.MaxLevelQ(), params.MaxLevelP(), bpw2, "KeyGenerator/GenPublicKey"), func(t *testing.T) {
 
@@ -687,6 +779,120 @@ func testApplyEvaluationKey(tc *TestContext, level, bpw2 int, t *testing.T) {
 	})
 }
 
+func TestMigrator(t *testing.T) {
+
+	paramsOut, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN:    logN,
+		Q:       qi,
+		P:       pj,
+		NTTFlag: true,
+	})
+	require.NoError(t, err)
+
+	paramsIn, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN:    logN - 1,
+		Q:       qi[:3],
+		P:       pj[:1],
+		NTTFlag: true,
+	})
+	require.NoError(t, err)
+
+	skIn := NewKeyGenerator(paramsIn).GenSecretKeyNew()
+	skOut := NewKeyGenerator(paramsOut).GenSecretKeyNew()
+
+	evk := GenMigrationKey(paramsIn, paramsOut, skIn, skOut)
+
+	m, err := NewMigrator(paramsIn, paramsOut, evk)
+	require.NoError(t, err)
+
+	enc := NewEncryptor(paramsIn, skIn)
+	dec := NewDecryptor(paramsOut, skOut)
+
+	NoiseBound := float64(paramsOut.LogN())
+
+	// requireSmallNoise checks that ct decrypts to zero under the target key.
+	requireSmallNoise := func(t *testing.T, ct *Ciphertext) {
+
+		require.Equal(t, paramsIn.MaxLevel(), ct.Level())
+
+		pt := dec.DecryptNew(ct)
+
+		ringQ := paramsOut.RingQ().AtLevel(ct.Level())
+		if pt.IsNTT {
+			ringQ.INTT(pt.Value, pt.Value)
+		}
+
+		require.GreaterOrEqual(t, NoiseBound, ringQ.Log2OfStandardDeviation(pt.Value))
+	}
+
+	t.Run(testString(paramsOut, paramsOut.MaxLevelQ(), paramsOut.MaxLevelP(), 0, "Migrator/IncompatibleParameters"), func(t *testing.T) {
+
+		paramsBad, err := NewParametersFromLiteral(ParametersLiteral{
+			LogN:    logN - 1,
+			Q:       qi[1:4], // not a prefix of the moduli of paramsOut
+			P:       pj[:1],
+			NTTFlag: true,
+		})
+		require.NoError(t, err)
+
+		_, err = NewMigrator(paramsBad, paramsOut, evk)
+		require.Error(t, err)
+	})
+
+	t.Run(testString(paramsOut, paramsOut.MaxLevelQ(), paramsOut.MaxLevelP(), 0, "Migrator/Migrate"), func(t *testing.T) {
+
+		ct, err := m.Migrate(enc.EncryptZeroNew(paramsIn.MaxLevel()))
+		require.NoError(t, err)
+
+		requireSmallNoise(t, ct)
+	})
+
+	t.Run(testString(paramsOut, paramsOut.MaxLevelQ(), paramsOut.MaxLevelP(), 0, "Migrator/MigrateSlice"), func(t *testing.T) {
+
+		cts := make([]*Ciphertext, 8)
+		for i := range cts {
+			cts[i] = enc.EncryptZeroNew(paramsIn.MaxLevel())
+		}
+
+		out, err := m.MigrateSlice(cts, 4)
+		require.NoError(t, err)
+		require.Equal(t, len(cts), len(out))
+
+		for i := range out {
+			requireSmallNoise(t, out[i])
+		}
+	})
+
+	t.Run(testString(paramsOut, paramsOut.MaxLevelQ(), paramsOut.MaxLevelP(), 0, "Migrator/MigrateStream"), func(t *testing.T) {
+
+		count := 5
+
+		in := new(bytes.Buffer)
+		for i := 0; i < count; i++ {
+			_, err := enc.EncryptZeroNew(paramsIn.MaxLevel()).WriteTo(in)
+			require.NoError(t, err)
+		}
+
+		out := new(bytes.Buffer)
+
+		n, err := m.MigrateStream(in, out, 2)
+		require.NoError(t, err)
+		require.Equal(t, count, n)
+
+		r := bufio.NewReader(out)
+		for i := 0; i < count; i++ {
+			ct := new(Ciphertext)
+			_, err := ct.ReadFrom(r)
+			require.NoError(t, err)
+			requireSmallNoise(t, ct)
+		}
+
+		// The stream must have been fully consumed.
+		_, err = r.Peek(1)
+		require.Equal(t, io.EOF, err)
+	})
+}
+
 func testAutomorphism(tc *TestContext, level, bpw2 int, t *testing.T) {
 
 	params := tc.params
@@ -850,6 +1056,122 @@ func testAutomorphism(tc *TestContext, level, bpw2 int, t *testing.T) {
 		// Logs the noise
 		require.GreaterOrEqual(t, NoiseBound, ringQ.Log2OfStandardDeviation(pt.Value))
 	})
+
+	t.Run(testString(params, level, params.MaxLevelP(), bpw2, "Evaluator/AutomorphismHoisted/MismatchedKey"), func(t *testing.T) {
+
+		if bpw2 != 0 {
+			t.Skip("method is not supported if BaseTwoDecomposition != 0")
+		}
+
+		if params.MaxLevelP() < 1 {
+			t.Skip("test requires #P > 1")
+		}
+
+		// Generate a plaintext with values up to 2^30
+		pt := genPlaintext(params, level, 1<<30)
+
+		// Encrypt
+		ct, err := enc.EncryptNew(pt)
+		require.NoError(t, err)
+
+		// Chooses a Galois Element (must be coprime with 2N)
+		galEl := params.GaloisElement(-1)
+
+		// Generates the GaloisKey with a smaller special-prime set than the
+		// one the decomposition is computed for
+		evkParamsMismatched := EvaluationKeyParameters{LevelQ: utils.Pointy(level), LevelP: utils.Pointy(0)}
+		evk := NewMemEvaluationKeySet(nil, kgen.GenGaloisKeyNew(galEl, sk, evkParamsMismatched))
+
+		//Decompose the ciphertext
+		eval.DecomposeNTT(level, params.MaxLevelP(), params.MaxLevelP()+1, ct.Value[1], ct.IsNTT, eval.BuffDecompQP)
+
+		// Evaluate the automorphism
+		eval.WithKey(evk).AutomorphismHoisted(level, ct, eval.BuffDecompQP, galEl, ct)
+
+		// Apply the same automorphism on the plaintext
+		ringQ := params.RingQ().AtLevel(level)
+
+		tmp := ringQ.NewPoly()
+		if pt.IsNTT {
+			ringQ.AutomorphismNTT(pt.Value, galEl, tmp)
+		} else {
+			ringQ.Automorphism(pt.Value, galEl, tmp)
+		}
+
+		// Decrypt
+		dec.Decrypt(ct, pt)
+
+		// Subract the permuted plaintext to the decrypted plaintext
+		ringQ.Sub(pt.Value, tmp, pt.Value)
+
+		// Switch out of NTT if required
+		if pt.IsNTT {
+			ringQ.INTT(pt.Value, pt.Value)
+		}
+
+		// Logs the noise
+		require.GreaterOrEqual(t, NoiseBound, ringQ.Log2OfStandardDeviation(pt.Value))
+	})
+
+	t.Run(testString(params, level, params.MaxLevelP(), bpw2, "Evaluator/AutomorphismHoistedLazy/MismatchedKey"), func(t *testing.T) {
+
+		if bpw2 != 0 {
+			t.Skip("method is not supported if BaseTwoDecomposition != 0")
+		}
+
+		if params.MaxLevelP() < 1 {
+			t.Skip("test requires #P > 1")
+		}
+
+		// Generate a plaintext with values up to 2^30
+		pt := genPlaintext(params, level, 1<<30)
+
+		// Encrypt
+		ct, err := enc.EncryptNew(pt)
+		require.NoError(t, err)
+
+		// Chooses a Galois Element (must be coprime with 2N)
+		galEl := params.GaloisElement(-1)
+
+		// Generates the GaloisKey with a smaller special-prime set than the
+		// one the decomposition is computed for
+		evkParamsMismatched := EvaluationKeyParameters{LevelQ: utils.Pointy(level), LevelP: utils.Pointy(0)}
+		evk := NewMemEvaluationKeySet(nil, kgen.GenGaloisKeyNew(galEl, sk, evkParamsMismatched))
+
+		//Decompose the ciphertext
+		eval.DecomposeNTT(level, params.MaxLevelP(), params.MaxLevelP()+1, ct.Value[1], ct.IsNTT, eval.BuffDecompQP)
+
+		ctQP := NewElementExtended(params, 1, level, params.MaxLevelP())
+
+		// Evaluate the automorphism
+		eval.WithKey(evk).AutomorphismHoistedLazy(level, ct, eval.BuffDecompQP, galEl, ctQP)
+
+		eval.ModDown(level, params.MaxLevelP(), ctQP, ct)
+
+		// Apply the same automorphism on the plaintext
+		ringQ := params.RingQ().AtLevel(level)
+
+		tmp := ringQ.NewPoly()
+		if pt.IsNTT {
+			ringQ.AutomorphismNTT(pt.Value, galEl, tmp)
+		} else {
+			ringQ.Automorphism(pt.Value, galEl, tmp)
+		}
+
+		// Decrypt
+		dec.Decrypt(ct, pt)
+
+		// Subract the permuted plaintext to the decrypted plaintext
+		ringQ.Sub(pt.Value, tmp, pt.Value)
+
+		// Switch out of NTT if required
+		if pt.IsNTT {
+			ringQ.INTT(pt.Value, pt.Value)
+		}
+
+		// Logs the noise
+		require.GreaterOrEqual(t, NoiseBound, ringQ.Log2OfStandardDeviation(pt.Value))
+	})
 }
 
 func testSlotOperations(tc *TestContext, level, bpw2 int, t *testing.T) {
@@ -1105,63 +1427,183 @@ func testSlotOperations(tc *TestContext, level, bpw2 int, t *testing.T) {
 		require.GreaterOrEqual(t, NoiseBound, ringQ.Log2OfStandardDeviation(pt.Value))
 
 	})
-}
 
-func genPlaintext(params Parameters, level, max int) (pt *Plaintext) {
+	t.Run(testString(params, level, params.MaxLevelP(), bpw2, "Evaluator/InnerSum/MixedKeys"), func(t *testing.T) {
 
-	N := params.N()
+		if params.MaxLevelP() < 1 {
+			t.Skip("test requires #P > 1")
+		}
 
-	step := float64(max) / float64(N)
+		batch := 5
+		n := 7
 
-	pt = NewPlaintext(params, level)
+		ringQ := tc.params.RingQ().AtLevel(level)
 
-	for i := 0; i < level+1; i++ {
-		c := pt.Value.Coeffs[i]
-		for j := 0; j < N; j++ {
-			c[j] = uint64(float64(j) * step)
-		}
-	}
+		pt := genPlaintext(params, level, 1<<30)
+		ptInnerSum := *pt.Value.CopyNew()
+		ct, err := enc.EncryptNew(pt)
+		require.NoError(t, err)
 
-	if pt.IsNTT {
-		params.RingQ().AtLevel(level).NTT(pt.Value, pt.Value)
-	}
+		// Galois Keys, generated with a smaller special-prime set than the
+		// one of the parameters
+		evkParamsMismatched := EvaluationKeyParameters{LevelP: utils.Pointy(0)}
+		evk := NewMemEvaluationKeySet(nil, kgen.GenGaloisKeysNew(GaloisElementsForInnerSum(params, batch, n), sk, evkParamsMismatched)...)
 
-	return
-}
+		require.NoError(t, eval.WithKey(evk).InnerSum(ct, batch, n, ct))
 
-func testWriteAndRead(tc *TestContext, bpw2 int, t *testing.T) {
+		dec.Decrypt(ct, pt)
 
-	params := tc.params
+		if pt.IsNTT {
+			ringQ.INTT(pt.Value, pt.Value)
+			ringQ.INTT(ptInnerSum, ptInnerSum)
+		}
 
-	sk, pk := tc.sk, tc.pk
+		polyTmp := ringQ.NewPoly()
 
-	levelQ := params.MaxLevelQ()
-	levelP := params.MaxLevelP()
+		// Applies the same circuit (naively) on the plaintext
+		polyInnerSum := *ptInnerSum.CopyNew()
+		for i := 1; i < n; i++ {
+			galEl := params.GaloisElement(i * batch)
+			ringQ.Automorphism(ptInnerSum, galEl, polyTmp)
+			ringQ.Add(polyInnerSum, polyTmp, polyInnerSum)
+		}
 
-	t.Run(testString(params, levelQ, levelP, bpw2, "WriteAndRead/Element[ring.Poly]"), func(t *testing.T) {
+		ringQ.Sub(pt.Value, polyInnerSum, pt.Value)
 
-		prng, _ := sampling.NewPRNG()
-		sampler := ring.NewUniformSampler(prng, params.RingQ())
+		// The smaller special-prime set of the keys increases the
+		// key-switching noise
+		NoiseBound := float64(params.LogN()) + 2
 
-		op := Element[ring.Poly]{
-			Value: structs.Vector[ring.Poly]{
-				sampler.ReadNew(),
-				sampler.ReadNew(),
-			},
-			MetaData: &MetaData{
-				CiphertextMetaData: CiphertextMetaData{
-					IsNTT: params.NTTFlag(),
-				},
-			},
-		}
+		// Logs the noise
+		require.GreaterOrEqual(t, NoiseBound, ringQ.Log2OfStandardDeviation(pt.Value))
 
-		buffer.RequireSerializerCorrect(t, &op)
 	})
 
-	t.Run(testString(params, levelQ, levelP, bpw2, "WriteAndRead/Element[ringqp.Poly]"), func(t *testing.T) {
+	t.Run(testString(params, level, params.MaxLevelP(), bpw2, "Evaluator/TraceHoisted"), func(t *testing.T) {
 
-		prng, _ := sampling.NewPRNG()
-		sampler := ringqp.NewUniformSampler(prng, *params.RingQP())
+		if params.MaxLevelP() == -1 {
+			t.Skip("test requires #P > 0")
+		}
+
+		logN := params.LogN() - 3
+
+		// The premultiplication by (N/n)^-1 is only exact on the noise for
+		// ring.Type = ring.Standard, so only the single-step trace, which does
+		// not rescale, is verifiable in the ConjugateInvariant ring.
+		if params.RingType() == ring.ConjugateInvariant {
+			logN = params.LogN() - 2
+		}
+
+		ringQ := tc.params.RingQ().AtLevel(level)
+
+		pt := genPlaintext(params, level, 1<<30)
+		ptTrace := *pt.Value.CopyNew()
+		ct, err := enc.EncryptNew(pt)
+		require.NoError(t, err)
+
+		// Galois Keys
+		evk := NewMemEvaluationKeySet(nil, kgen.GenGaloisKeysNew(GaloisElementsForTraceHoisted(params, logN), sk)...)
+
+		wasNTT := pt.IsNTT
+
+		require.NoError(t, eval.WithKey(evk).TraceHoisted(ct, logN, ct))
+
+		dec.Decrypt(ct, pt)
+
+		// The in-place call can leave the output in the NTT domain
+		if pt.IsNTT {
+			ringQ.INTT(pt.Value, pt.Value)
+		}
+
+		if wasNTT {
+			ringQ.INTT(ptTrace, ptTrace)
+		}
+
+		gap := 1 << (params.LogN() - logN - 1)
+		if params.RingType() == ring.ConjugateInvariant {
+			gap >>= 1
+		}
+
+		// pre-multiplication by (N/n)^-1
+		NInv := new(big.Int).SetUint64(uint64(gap))
+		NInv.ModInverse(NInv, ringQ.ModulusAtLevel[level])
+		ringQ.MulScalarBigint(ptTrace, NInv, ptTrace)
+
+		polyTmp := ringQ.NewPoly()
+
+		// Applies the same circuit (naively) on the plaintext
+		polyTrace := *ptTrace.CopyNew()
+		for j := 1; j < 1<<(params.LogN()-1-logN); j++ {
+			galEl := params.GaloisElement(j << logN)
+			ringQ.Automorphism(ptTrace, galEl, polyTmp)
+			ringQ.Add(polyTrace, polyTmp, polyTrace)
+		}
+
+		ringQ.Sub(pt.Value, polyTrace, pt.Value)
+
+		NoiseBound := float64(params.LogN())
+
+		// Logs the noise
+		require.GreaterOrEqual(t, NoiseBound, ringQ.Log2OfStandardDeviation(pt.Value))
+
+	})
+}
+
+func genPlaintext(params Parameters, level, max int) (pt *Plaintext) {
+
+	N := params.N()
+
+	step := float64(max) / float64(N)
+
+	pt = NewPlaintext(params, level)
+
+	for i := 0; i < level+1; i++ {
+		c := pt.Value.Coeffs[i]
+		for j := 0; j < N; j++ {
+			c[j] = uint64(float64(j) * step)
+		}
+	}
+
+	if pt.IsNTT {
+		params.RingQ().AtLevel(level).NTT(pt.Value, pt.Value)
+	}
+
+	return
+}
+
+func testWriteAndRead(tc *TestContext, bpw2 int, t *testing.T) {
+
+	params := tc.params
+
+	sk, pk := tc.sk, tc.pk
+
+	levelQ := params.MaxLevelQ()
+	levelP := params.MaxLevelP()
+
+	t.Run(testString(params, levelQ, levelP, bpw2, "WriteAndRead/Element[ring.Poly]"), func(t *testing.T) {
+
+		prng, _ := sampling.NewPRNG()
+		sampler := ring.NewUniformSampler(prng, params.RingQ())
+
+		op := Element[ring.Poly]{
+			Value: structs.Vector[ring.Poly]{
+				sampler.ReadNew(),
+				sampler.ReadNew(),
+			},
+			MetaData: &MetaData{
+				CiphertextMetaData: CiphertextMetaData{
+					IsNTT: params.NTTFlag(),
+				},
+			},
+		}
+
+		buffer.RequireSerializerCorrect(t, &op)
+	})
+
+	t.Run(testString(params, levelQ, levelP, bpw2, "WriteAndRead/Element[ringqp.Poly]"), func(t *testing.T) {
+
+		prng, _ := sampling.NewPRNG()
+		sampler := ringqp.NewUniformSampler(prng, *params.RingQP())
 
 		op := Element[ringqp.Poly]{
 			Value: structs.Vector[ringqp.Poly]{
@@ -1209,6 +1651,12 @@ func testWriteAndRead(tc *TestContext, bpw2 int, t *testing.T) {
 		buffer.RequireSerializerCorrect(t, sk)
 	})
 
+	t.Run(testString(params, levelQ, levelP, bpw2, "WriteAndRead/SecretKeyShare"), func(t *testing.T) {
+		prng, _ := sampling.NewPRNG()
+		share0, _ := NewSecretKeyShares(params, prng, sk)
+		buffer.RequireSerializerCorrect(t, share0)
+	})
+
 	t.Run(testString(params, levelQ, levelP, bpw2, "WriteAndRead/Pk"), func(t *testing.T) {
 		buffer.RequireSerializerCorrect(t, pk)
 	})
@@ -1257,3 +1705,911 @@ func testMarshaller(tc *TestContext, t *testing.T) {
 		buffer.RequireSerializerCorrect(t, &m)
 	})
 }
+
+func TestParameterBinding(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	paramsA, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN: 10,
+		LogQ: []int{35, 20},
+		LogP: []int{61},
+	})
+	require.NoError(t, err)
+
+	// Same dimensions but different moduli: mixing objects of the two sets
+	// would silently produce garbage without the binding checks.
+	paramsB, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN: 10,
+		LogQ: []int{30, 25},
+		LogP: []int{61},
+	})
+	require.NoError(t, err)
+
+	require.NotZero(t, paramsA.Fingerprint())
+	require.NotEqual(t, paramsA.Fingerprint(), paramsB.Fingerprint())
+
+	skA := NewKeyGenerator(paramsA).GenSecretKeyNew()
+	skB := NewKeyGenerator(paramsB).GenSecretKeyNew()
+
+	ptA := NewPlaintext(paramsA, paramsA.MaxLevel())
+	ctA := NewCiphertext(paramsA, 1, paramsA.MaxLevel())
+	require.NoError(t, NewEncryptor(paramsA, skA).Encrypt(ptA, ctA))
+
+	// An encryptor refuses to write into a ciphertext of another parameter set.
+	err = NewEncryptor(paramsB, skB).Encrypt(NewPlaintext(paramsB, paramsB.MaxLevel()), ctA)
+	require.ErrorIs(t, err, ErrParameterMismatch)
+	require.ErrorIs(t, NewEncryptor(paramsB, skB).EncryptZero(ctA), ErrParameterMismatch)
+
+	// An evaluator refuses operands of another parameter set.
+	evalB := NewEvaluator(paramsB, nil)
+	_, _, err = evalB.InitOutputBinaryOp(ctA.El(), ctA.El(), 2, ctA.El())
+	require.ErrorIs(t, err, ErrParameterMismatch)
+	_, _, err = evalB.InitOutputUnaryOp(ctA.El(), ctA.El())
+	require.ErrorIs(t, err, ErrParameterMismatch)
+
+	// A decryptor panics on a ciphertext of another parameter set.
+	require.Panics(t, func() {
+		NewDecryptor(paramsB, skB).Decrypt(ctA, NewPlaintext(paramsB, paramsB.MaxLevel()))
+	})
+
+	// Deserialized objects carry no binding and are not checked.
+	p, err := ctA.MarshalBinary()
+	require.NoError(t, err)
+	ctSer := new(Ciphertext)
+	require.NoError(t, ctSer.UnmarshalBinary(p))
+	require.Zero(t, ctSer.ParameterFingerprint)
+	_, _, err = evalB.InitOutputUnaryOp(ctSer.El(), ctSer.El())
+	require.NoError(t, err)
+}
+
+func TestStrictMode(t *testing.T) {
+
+	errTest := fmt.Errorf("test error")
+
+	// Without the strict mode, Fault panics with the given error.
+	require.False(t, StrictModeEnabled())
+	require.PanicsWithError(t, errTest.Error(), func() {
+		_ = Fault(errTest)
+	})
+
+	// With the strict mode, Fault returns the error instead.
+	SetStrictMode(true)
+	defer SetStrictMode(false)
+	require.True(t, StrictModeEnabled())
+	require.ErrorIs(t, Fault(errTest), errTest)
+
+	// WithRecovery converts panics into errors and passes errors through.
+	err := WithRecovery(func() error {
+		panic(errTest)
+	})
+	require.ErrorIs(t, err, errTest)
+
+	err = WithRecovery(func() error {
+		panic("not an error")
+	})
+	require.Error(t, err)
+
+	require.ErrorIs(t, WithRecovery(func() error { return errTest }), errTest)
+	require.NoError(t, WithRecovery(func() error { return nil }))
+}
+
+func TestMemEvaluationKeySetConcurrentUse(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN: 10,
+		LogQ: []int{35, 20},
+		LogP: []int{61},
+	})
+	require.NoError(t, err)
+
+	kgen := NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+	rlk := kgen.GenRelinearizationKeyNew(sk)
+
+	gks := make([]*GaloisKey, 4)
+	for i := range gks {
+		gks[i] = kgen.GenGaloisKeyNew(params.GaloisElement(i+1), sk)
+	}
+
+	evk := NewMemEvaluationKeySet(nil, gks[0])
+
+	// Readers and writers race on the set; the test is only meaningful
+	// under the race detector, but also checks that reads always observe
+	// either a missing key or a complete one.
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+
+	for w := 0; w < 2; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			for i := 0; i < 128; i++ {
+				evk.AddGaloisKeys(gks[i%len(gks)])
+				evk.SetRelinearizationKey(rlk)
+			}
+		}()
+	}
+
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			for i := 0; i < 128; i++ {
+				galEl := gks[i%len(gks)].GaloisElement
+				if gk, err := evk.GetGaloisKey(galEl); err == nil {
+					require.Equal(t, galEl, gk.GaloisElement)
+				}
+				_ = evk.GetGaloisKeysList()
+				_, _ = evk.GetRelinearizationKey()
+			}
+		}()
+	}
+
+	close(start)
+	wg.Wait()
+
+	for _, gk := range gks {
+		got, err := evk.GetGaloisKey(gk.GaloisElement)
+		require.NoError(t, err)
+		require.True(t, got.Equal(gk))
+	}
+
+	gotRlk, err := evk.GetRelinearizationKey()
+	require.NoError(t, err)
+	require.True(t, gotRlk.Equal(rlk))
+}
+
+func TestMemEvaluationKeySetSnapshot(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN: 10,
+		LogQ: []int{35, 20},
+		LogP: []int{61},
+	})
+	require.NoError(t, err)
+
+	kgen := NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+
+	gks := make([]*GaloisKey, 3)
+	for i := range gks {
+		gks[i] = kgen.GenGaloisKeyNew(params.GaloisElement(i+1), sk)
+	}
+
+	evk := NewMemEvaluationKeySet(nil, gks[0], gks[1])
+
+	snapshot := evk.Snapshot()
+
+	// Updates on the live set are invisible to the snapshot.
+	evk.AddGaloisKeys(gks[2])
+	evk.RemoveGaloisKeys(gks[0].GaloisElement)
+
+	_, err = snapshot.GetGaloisKey(gks[2].GaloisElement)
+	require.Error(t, err)
+
+	got, err := snapshot.GetGaloisKey(gks[0].GaloisElement)
+	require.NoError(t, err)
+	require.True(t, got.Equal(gks[0]))
+
+	// The live set observes them.
+	_, err = evk.GetGaloisKey(gks[0].GaloisElement)
+	require.Error(t, err)
+
+	got, err = evk.GetGaloisKey(gks[2].GaloisElement)
+	require.NoError(t, err)
+	require.True(t, got.Equal(gks[2]))
+
+	// Removing an absent element is a no-op.
+	evk.RemoveGaloisKeys(gks[0].GaloisElement)
+	require.ElementsMatch(t, []uint64{gks[1].GaloisElement, gks[2].GaloisElement}, evk.GetGaloisKeysList())
+}
+
+func TestGaloisElementHelpers(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN: 10,
+		LogQ: []int{35, 20},
+		LogP: []int{61},
+	})
+	require.NoError(t, err)
+
+	NthRoot := params.RingQ().NthRoot()
+
+	// Valid elements: odd integers in [1, NthRoot-1].
+	require.NoError(t, params.CheckGaloisElement(1))
+	require.NoError(t, params.CheckGaloisElement(params.GaloisElement(3)))
+	require.NoError(t, params.CheckGaloisElement(NthRoot-1))
+	require.Error(t, params.CheckGaloisElement(0))
+	require.Error(t, params.CheckGaloisElement(2))
+	require.Error(t, params.CheckGaloisElement(NthRoot))
+	require.Error(t, params.CheckGaloisElement(NthRoot+1))
+
+	// Rotation and conjugation elements.
+	require.Equal(t, params.GaloisElement(5), params.GaloisElementForRotation(5))
+
+	galEl, err := params.GaloisElementForConjugation()
+	require.NoError(t, err)
+	require.Equal(t, NthRoot-1, galEl)
+
+	// The conjugation is not in the subgroup generated by GaloisGen.
+	_, err = params.RotationFromGaloisElement(galEl)
+	require.Error(t, err)
+	_, err = params.RotationFromGaloisElement(2)
+	require.Error(t, err)
+
+	for _, k := range []int{0, 1, 7, 25} {
+		kOut, err := params.RotationFromGaloisElement(params.GaloisElementForRotation(k))
+		require.NoError(t, err)
+		require.Equal(t, k, kOut)
+	}
+
+	galEls := params.AllGaloisElements()
+	require.Equal(t, int(NthRoot>>1), len(galEls))
+	for _, galEl := range galEls {
+		require.NoError(t, params.CheckGaloisElement(galEl))
+	}
+
+	// The conjugation is undefined over the ConjugateInvariant ring.
+	// <<<<!Insecure parameters!>>>>
+	paramsCI, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN:     10,
+		LogQ:     []int{35, 20},
+		LogP:     []int{61},
+		RingType: ring.ConjugateInvariant,
+	})
+	require.NoError(t, err)
+	_, err = paramsCI.GaloisElementForConjugation()
+	require.Error(t, err)
+}
+
+func TestSwitchCiphertextModulusChain(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	paramsIn, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN: 10,
+		Q:    ring.Qi60[:4],
+	})
+	require.NoError(t, err)
+
+	// The target chain keeps the first and third moduli of the source chain.
+	paramsOut, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN: 10,
+		Q:    []uint64{ring.Qi60[0], ring.Qi60[2]},
+	})
+	require.NoError(t, err)
+
+	skIn := NewKeyGenerator(paramsIn).GenSecretKeyNew()
+
+	ct := NewCiphertext(paramsIn, 1, paramsIn.MaxLevel())
+	require.NoError(t, NewEncryptor(paramsIn, skIn).EncryptZero(ct))
+
+	ctOut, err := SwitchCiphertextModulusChain(paramsIn, ct, paramsOut, paramsOut.MaxLevel())
+	require.NoError(t, err)
+	require.Equal(t, paramsOut.MaxLevel(), ctOut.Level())
+
+	// The same secret under the target parameters.
+	rQIn := paramsIn.RingQ()
+	rQOut := paramsOut.RingQ()
+
+	buff := rQIn.NewPoly()
+	rQIn.INTT(skIn.Value.Q, buff)
+	rQIn.IMForm(buff, buff)
+
+	coeffs := make([]*big.Int, rQIn.N())
+	for i := range coeffs {
+		coeffs[i] = new(big.Int)
+	}
+	rQIn.PolyToBigintCentered(buff, 1, coeffs)
+
+	skOut := NewSecretKey(paramsOut)
+	rQOut.SetCoefficientsBigint(coeffs, skOut.Value.Q)
+	rQOut.NTT(skOut.Value.Q, skOut.Value.Q)
+	rQOut.MForm(skOut.Value.Q, skOut.Value.Q)
+
+	// The fresh encryption noise was divided by the ~2^122 dropped moduli, so
+	// only the rounding noise (~sqrt(N * Var(e_round) * Var(sk))) remains.
+	pt := NewDecryptor(paramsOut, skOut).DecryptNew(ctOut)
+	if pt.IsNTT {
+		rQOut.INTT(pt.Value, pt.Value)
+	}
+	require.Less(t, rQOut.Log2OfStandardDeviation(pt.Value), 6.0)
+
+	// A target chain that is not an in-order subset of the source chain is
+	// rejected.
+	paramsBad, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN: 10,
+		Q:    []uint64{ring.Qi60[2], ring.Qi60[0]},
+	})
+	require.NoError(t, err)
+
+	_, err = SwitchCiphertextModulusChain(paramsIn, ct, paramsBad, paramsBad.MaxLevel())
+	require.Error(t, err)
+}
+
+func TestImportSecretKey(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN: 10,
+		Q:    ring.Qi60[:2],
+		P:    ring.Pi60[:1],
+	})
+	require.NoError(t, err)
+
+	kgen := NewKeyGenerator(params)
+
+	N := params.N()
+
+	coeffs := make([]int64, N)
+	for j := range coeffs {
+		coeffs[j] = int64(j%3) - 1
+	}
+
+	sk, err := kgen.ImportSecretKeyNew(coeffs)
+	require.NoError(t, err)
+
+	// The imported key stores the provided coefficients.
+	rQ := params.RingQ()
+	skCopy := rQ.NewPoly()
+	rQ.INTT(sk.Value.Q, skCopy)
+	rQ.IMForm(skCopy, skCopy)
+
+	got := make([]*big.Int, N)
+	for j := range got {
+		got[j] = new(big.Int)
+	}
+	rQ.PolyToBigintCentered(skCopy, 1, got)
+
+	for j := range coeffs {
+		require.Equal(t, coeffs[j], got[j].Int64())
+	}
+
+	// The imported key decrypts what it encrypts.
+	ct := NewCiphertext(params, 1, params.MaxLevel())
+	require.NoError(t, NewEncryptor(params, sk).EncryptZero(ct))
+
+	pt := NewDecryptor(params, sk).DecryptNew(ct)
+	if pt.IsNTT {
+		rQ.INTT(pt.Value, pt.Value)
+	}
+	require.Less(t, rQ.Log2OfStandardDeviation(pt.Value), 5.0)
+
+	// Out-of-range coefficients are rejected.
+	coeffs[0] = 2
+	require.Error(t, kgen.ImportSecretKey(coeffs, sk))
+	coeffs[0] = -1
+	require.Error(t, kgen.ImportSecretKey(coeffs[:N-1], sk))
+
+	// A fixed Hamming weight is enforced.
+	// <<<<!Insecure parameters!>>>>
+	paramsHW, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN: 10,
+		Q:    ring.Qi60[:2],
+		P:    ring.Pi60[:1],
+		Xs:   ring.Ternary{H: 64},
+	})
+	require.NoError(t, err)
+
+	kgenHW := NewKeyGenerator(paramsHW)
+
+	coeffsHW := make([]int64, N)
+	for j := 0; j < 64; j++ {
+		coeffsHW[j] = 1
+	}
+	_, err = kgenHW.ImportSecretKeyNew(coeffsHW)
+	require.NoError(t, err)
+
+	coeffsHW[0] = 0
+	_, err = kgenHW.ImportSecretKeyNew(coeffsHW)
+	require.Error(t, err)
+
+	// The same seed always derives the same key.
+	seed := []byte{0x01, 0x02, 0x03, 0x04}
+
+	sk1, err := kgen.ImportSecretKeyFromSeedNew(seed)
+	require.NoError(t, err)
+
+	sk2, err := kgen.ImportSecretKeyFromSeedNew(seed)
+	require.NoError(t, err)
+	require.True(t, sk1.Equal(sk2))
+
+	sk3, err := kgen.ImportSecretKeyFromSeedNew([]byte{0x05})
+	require.NoError(t, err)
+	require.False(t, sk1.Equal(sk3))
+}
+
+func TestCircuitKeyProvisioning(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN: 10,
+		Q:    ring.Qi60[:2],
+		P:    ring.Pi60[:1],
+	})
+	require.NoError(t, err)
+
+	kgen := NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+
+	var cd CircuitDescription
+	cd.RecordRotation(1, 2)
+	cd.RecordRotation(1) // duplicates are merged
+	cd.RecordConjugation()
+	cd.RecordInnerSum(1, 4)
+	cd.RecordTrace(params.LogN() - 1)
+	cd.RecordRelinearization()
+
+	galEls, err := cd.GaloisElementsRequired(params)
+	require.NoError(t, err)
+	require.Contains(t, galEls, params.GaloisElement(1))
+	require.Contains(t, galEls, params.GaloisElementOrderTwoOrthogonalSubgroup())
+
+	evk, err := kgen.GenEvaluationKeySetForCircuitNew(sk, cd)
+	require.NoError(t, err)
+
+	// Exactly the required keys were generated.
+	_, err = evk.GetRelinearizationKey()
+	require.NoError(t, err)
+
+	require.ElementsMatch(t, galEls, evk.GetGaloisKeysList())
+
+	for _, galEl := range galEls {
+		_, err = evk.GetGaloisKey(galEl)
+		require.NoError(t, err)
+	}
+
+	// The provisioned set backs an evaluator for the recorded operations.
+	ct := NewCiphertext(params, 1, params.MaxLevel())
+	require.NoError(t, NewEncryptor(params, sk).EncryptZero(ct))
+
+	eval := NewEvaluator(params, evk)
+	require.NoError(t, eval.Automorphism(ct, params.GaloisElement(1), ct))
+
+	// Raw Galois elements are validated.
+	var cdBad CircuitDescription
+	cdBad.RecordGaloisElement(2)
+	_, err = kgen.GenEvaluationKeySetForCircuitNew(sk, cdBad)
+	require.Error(t, err)
+}
+
+func TestKeyDomain(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN: 10,
+		Q:    ring.Qi60[:2],
+		P:    ring.Pi60[:1],
+	})
+	require.NoError(t, err)
+
+	kgen := NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+
+	kgenCoeff := kgen.WithKeyDomain(KeyDomainCoefficient)
+
+	// A public key emitted in the coefficient domain round-trips to the NTT
+	// domain and then encrypts correctly.
+	pk := kgenCoeff.GenPublicKeyNew(sk)
+
+	// A coefficient-domain public key differs from its NTT-domain form but
+	// round-trips exactly.
+	pkRT := pk.CopyNew()
+	SwitchPublicKeyDomain(params, KeyDomainNTT, pkRT)
+	require.False(t, pk.Equal(pkRT))
+	SwitchPublicKeyDomain(params, KeyDomainCoefficient, pkRT)
+	require.True(t, pk.Equal(pkRT))
+
+	SwitchPublicKeyDomain(params, KeyDomainNTT, pk)
+
+	ct := NewCiphertext(params, 1, params.MaxLevel())
+	require.NoError(t, NewEncryptor(params, pk).EncryptZero(ct))
+
+	rQ := params.RingQ()
+	pt := NewDecryptor(params, sk).DecryptNew(ct)
+	if pt.IsNTT {
+		rQ.INTT(pt.Value, pt.Value)
+	}
+	require.Less(t, rQ.Log2OfStandardDeviation(pt.Value), math.Log2(params.NoiseFreshPK())+1)
+
+	// A Galois key emitted in the coefficient domain matches its NTT-domain
+	// counterpart once switched back on load.
+	galEl := params.GaloisElement(1)
+
+	gkCoeff := kgenCoeff.GenGaloisKeyNew(galEl, sk)
+	gkNTT := gkCoeff.CopyNew()
+	SwitchEvaluationKeyDomain(params, KeyDomainNTT, &gkNTT.EvaluationKey)
+
+	ct2 := NewCiphertext(params, 1, params.MaxLevel())
+	require.NoError(t, NewEncryptor(params, sk).EncryptZero(ct2))
+
+	eval := NewEvaluator(params, NewMemEvaluationKeySet(nil, gkNTT))
+	require.NoError(t, eval.Automorphism(ct2, galEl, ct2))
+
+	pt2 := NewDecryptor(params, sk).DecryptNew(ct2)
+	if pt2.IsNTT {
+		rQ.INTT(pt2.Value, pt2.Value)
+	}
+	require.Less(t, rQ.Log2OfStandardDeviation(pt2.Value), math.Log2(params.NoiseFreshSK())+20)
+
+	// The round trip through the coefficient domain is exact.
+	gk := kgen.GenGaloisKeyNew(galEl, sk)
+	gkRT := gk.CopyNew()
+	SwitchEvaluationKeyDomain(params, KeyDomainCoefficient, &gkRT.EvaluationKey)
+	SwitchEvaluationKeyDomain(params, KeyDomainNTT, &gkRT.EvaluationKey)
+	require.True(t, gk.Equal(gkRT))
+}
+
+func TestOptimizeEvaluationKeyParameters(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN: 10,
+		Q:    ring.Qi60[:4],
+		P:    ring.Pi60[:1],
+	})
+	require.NoError(t, err)
+
+	budget := 20.0
+
+	evkParams, err := OptimizeEvaluationKeyParameters(params, budget)
+	require.NoError(t, err)
+
+	levelQ, levelP, base2 := ResolveEvaluationKeyParameters(params, []EvaluationKeyParameters{evkParams})
+	require.Equal(t, params.MaxLevelQ(), levelQ)
+
+	estimate := EstimateKeySwitchingNoise(params, levelQ, levelP, base2)
+	require.LessOrEqual(t, estimate, budget)
+
+	// The default decomposition also meets this budget, so the selection
+	// cannot be larger than it.
+	require.LessOrEqual(t,
+		EvaluationKeyApproximateSize(params, levelQ, levelP, base2),
+		EvaluationKeyApproximateSize(params, params.MaxLevelQ(), params.MaxLevelP(), 0))
+
+	// A key generated under the chosen decomposition key-switches with a
+	// noise close to the estimate.
+	kgen := NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+
+	galEl := params.GaloisElement(1)
+	gk := kgen.GenGaloisKeyNew(galEl, sk, evkParams)
+
+	ct := NewCiphertext(params, 1, params.MaxLevel())
+	require.NoError(t, NewEncryptor(params, sk).EncryptZero(ct))
+
+	eval := NewEvaluator(params, NewMemEvaluationKeySet(nil, gk))
+	require.NoError(t, eval.Automorphism(ct, galEl, ct))
+
+	rQ := params.RingQ()
+	pt := NewDecryptor(params, sk).DecryptNew(ct)
+	if pt.IsNTT {
+		rQ.INTT(pt.Value, pt.Value)
+	}
+	require.Less(t, rQ.Log2OfStandardDeviation(pt.Value), estimate+8)
+
+	// Without an auxiliary modulus only the base-2 decomposition can meet a
+	// moderate budget.
+	// <<<<!Insecure parameters!>>>>
+	paramsNoP, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN: 10,
+		Q:    ring.Qi60[:4],
+	})
+	require.NoError(t, err)
+
+	evkParamsNoP, err := OptimizeEvaluationKeyParameters(paramsNoP, 40.0)
+	require.NoError(t, err)
+	require.Equal(t, -1, *evkParamsNoP.LevelP)
+	require.Greater(t, *evkParamsNoP.BaseTwoDecomposition, 0)
+	require.LessOrEqual(t, EstimateKeySwitchingNoise(paramsNoP, *evkParamsNoP.LevelQ, -1, *evkParamsNoP.BaseTwoDecomposition), 40.0)
+
+	// An unreachable budget is reported.
+	_, err = OptimizeEvaluationKeyParameters(params, 1.0)
+	require.Error(t, err)
+}
+
+func TestKeyArchive(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN: 10,
+		Q:    ring.Qi60[:2],
+		P:    ring.Pi60[:1],
+	})
+	require.NoError(t, err)
+
+	kgen := NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+	skOther := kgen.GenSecretKeyNew()
+
+	pk := kgen.GenPublicKeyNew(sk)
+	rlk := kgen.GenRelinearizationKeyNew(sk)
+
+	galEls := []uint64{params.GaloisElement(1), params.GaloisElement(2)}
+	gks := kgen.GenGaloisKeysNew(galEls, sk)
+
+	evkOther := kgen.GenEvaluationKeyNew(sk, skOther)
+
+	ka := NewKeyArchive()
+	ka.AddPublicKey(params, pk)
+	ka.AddRelinearizationKey(params, rlk)
+	ka.AddGaloisKeys(params, gks...)
+	ka.AddEvaluationKey(params, 7, evkOther)
+
+	data, err := ka.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, ka.BinarySize(), len(data))
+
+	kar, err := OpenKeyArchive(bytes.NewReader(data), params)
+	require.NoError(t, err)
+	require.Equal(t, 5, len(kar.Entries()))
+	require.Equal(t, galEls, kar.GaloisElements())
+
+	// Each key is extracted individually and round-trips exactly.
+	pkOut, err := kar.PublicKey()
+	require.NoError(t, err)
+	require.True(t, pk.Equal(pkOut))
+
+	rlkOut, err := kar.RelinearizationKey()
+	require.NoError(t, err)
+	require.True(t, rlk.Equal(rlkOut))
+
+	for i, galEl := range galEls {
+		gkOut, err := kar.GaloisKey(galEl)
+		require.NoError(t, err)
+		require.True(t, gks[i].Equal(gkOut))
+	}
+
+	evkOut, err := kar.EvaluationKey(7)
+	require.NoError(t, err)
+	require.True(t, evkOther.Equal(evkOut))
+
+	// Missing entries are reported.
+	_, err = kar.GaloisKey(params.GaloisElement(3))
+	require.Error(t, err)
+	_, err = kar.EvaluationKey(8)
+	require.Error(t, err)
+
+	// The extracted key set backs an evaluator.
+	evkSet, err := kar.MemEvaluationKeySet()
+	require.NoError(t, err)
+	require.ElementsMatch(t, galEls, evkSet.GetGaloisKeysList())
+
+	ct := NewCiphertext(params, 1, params.MaxLevel())
+	require.NoError(t, NewEncryptor(params, sk).EncryptZero(ct))
+	require.NoError(t, NewEvaluator(params, evkSet).Automorphism(ct, galEls[0], ct))
+
+	// Extraction under a different parameter set fails with
+	// ErrParameterMismatch.
+	// <<<<!Insecure parameters!>>>>
+	paramsOther, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN: 10,
+		Q:    ring.Qi60[:3],
+		P:    ring.Pi60[:1],
+	})
+	require.NoError(t, err)
+
+	karOther, err := OpenKeyArchive(bytes.NewReader(data), paramsOther)
+	require.NoError(t, err)
+	_, err = karOther.PublicKey()
+	require.True(t, errors.Is(err, ErrParameterMismatch))
+
+	// A corrupted header is rejected.
+	corrupted := append([]byte{}, data...)
+	corrupted[0]++
+	_, err = OpenKeyArchive(bytes.NewReader(corrupted), params)
+	require.Error(t, err)
+}
+
+func TestMemoryFootprint(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN: 10,
+		Q:    ring.Qi60[:4],
+		P:    ring.Pi60[:1],
+	})
+	require.NoError(t, err)
+
+	N := params.N()
+
+	// The footprint of a ciphertext is dominated by its coefficient storage
+	// and exceeds it only by headers and metadata.
+	ct := NewCiphertext(params, 1, params.MaxLevel())
+	coeffs := 2 * (params.MaxLevel() + 1) * N * 8
+	require.GreaterOrEqual(t, ct.MemoryFootprint(), coeffs)
+	require.Less(t, ct.MemoryFootprint(), coeffs+4096)
+
+	kgen := NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+	coeffs = (params.MaxLevelQ() + params.MaxLevelP() + 2) * N * 8
+	require.GreaterOrEqual(t, sk.MemoryFootprint(), coeffs)
+	require.Less(t, sk.MemoryFootprint(), coeffs+4096)
+
+	// A key set is the sum of its keys.
+	rlk := kgen.GenRelinearizationKeyNew(sk)
+	gks := kgen.GenGaloisKeysNew(params.GaloisElements([]int{1, 2}), sk)
+	evk := NewMemEvaluationKeySet(rlk, gks...)
+	require.Equal(t, rlk.MemoryFootprint()+gks[0].MemoryFootprint()+gks[1].MemoryFootprint(), evk.MemoryFootprint())
+
+	// The evaluator footprint counts its own buffers, not the shared key set.
+	eval := NewEvaluator(params, evk)
+	require.GreaterOrEqual(t, eval.MemoryFootprint(), eval.EvaluatorBuffers.MemoryFootprint())
+	require.Less(t, eval.MemoryFootprint(), evk.MemoryFootprint())
+}
+
+func TestMetaDataTags(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN: 10,
+		Q:    ring.Qi60[:2],
+		P:    ring.Pi60[:1],
+	})
+	require.NoError(t, err)
+
+	ct := NewCiphertext(params, 1, params.MaxLevel())
+	require.Nil(t, ct.Tag("dataset"))
+	require.NoError(t, ct.SetTag("dataset", []byte("batch-17")))
+	require.NoError(t, ct.SetTag("layout", []byte{0x01, 0x02}))
+
+	// Tags survive serialization.
+	data, err := ct.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, ct.BinarySize(), len(data))
+
+	ctIn := new(Ciphertext)
+	require.NoError(t, ctIn.UnmarshalBinary(data))
+	require.Equal(t, []byte("batch-17"), ctIn.Tag("dataset"))
+	require.True(t, ct.Equal(ctIn))
+
+	// CopyNew deep copies the tags.
+	cpy := ct.CopyNew()
+	cpy.Tag("layout")[0] = 0xff
+	require.NoError(t, cpy.SetTag("extra", []byte("x")))
+	require.Equal(t, byte(0x01), ct.Tag("layout")[0])
+	require.Nil(t, ct.Tag("extra"))
+
+	// Deleted tags no longer compare equal.
+	ctIn.DeleteTag("layout")
+	require.False(t, ct.Equal(ctIn))
+
+	// The total tag size is bounded.
+	require.Error(t, ct.SetTag("large", make([]byte, MaxTagBytes)))
+	ct.Tags["large"] = make([]byte, MaxTagBytes)
+	_, err = ct.MarshalBinary()
+	require.Error(t, err)
+}
+
+func TestNegacyclicShift(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN: 10,
+		Q:    ring.Qi60[:3],
+		P:    ring.Pi60[:1],
+	})
+	require.NoError(t, err)
+
+	kgen := NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+	dec := NewDecryptor(params, sk)
+
+	// No evaluation keys: the shift must not require any.
+	eval := NewEvaluator(params, nil)
+
+	prng, err := sampling.NewPRNG()
+	require.NoError(t, err)
+
+	ringQ := params.RingQ()
+	N := params.N()
+
+	// Multiplication by X^k commutes with the (linear) decryption map, so
+	// the decryption of the shifted ciphertext must be exactly the shifted
+	// decryption of the input.
+	decryptInCoeffDomain := func(ct *Ciphertext) ring.Poly {
+		pt := dec.DecryptNew(ct)
+		if pt.IsNTT {
+			ringQ.INTT(pt.Value, pt.Value)
+		}
+		return pt.Value
+	}
+
+	for _, ntt := range []bool{true, false} {
+
+		ct := NewCiphertextRandom(prng, params, 1, params.MaxLevel())
+		ct.IsNTT = ntt
+		if !ntt {
+			ringQ.INTT(ct.Value[0], ct.Value[0])
+			ringQ.INTT(ct.Value[1], ct.Value[1])
+		}
+
+		for _, k := range []int{0, 1, 7, -3, N + 5} {
+
+			shifted, err := eval.NegacyclicShiftNew(ct, k)
+			require.NoError(t, err)
+			require.Equal(t, ntt, shifted.IsNTT)
+
+			want := ringQ.NewPoly()
+			ringQ.MultByMonomial(decryptInCoeffDomain(ct), k, want)
+
+			have := decryptInCoeffDomain(shifted)
+			require.True(t, want.Equal(&have))
+		}
+	}
+}
+
+func TestCiphertextCanonicalization(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN: 10,
+		Q:    ring.Qi60[:2],
+		P:    ring.Pi60[:1],
+	})
+	require.NoError(t, err)
+
+	kgen := NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+	enc := NewEncryptor(params, sk)
+
+	ringQ := params.RingQ()
+
+	ct := NewCiphertext(params, 1, params.MaxLevel())
+	require.NoError(t, enc.EncryptZero(ct))
+	require.NoError(t, ct.Canonicalize(params))
+
+	want, err := ct.MarshalBinary()
+	require.NoError(t, err)
+
+	// A variant of the same element in the other domain, with unreduced
+	// coefficients and an allocated but empty tag map.
+	flipDomain := func(ct *Ciphertext) {
+		if ct.IsNTT {
+			ringQ.INTT(ct.Value[0], ct.Value[0])
+			ringQ.INTT(ct.Value[1], ct.Value[1])
+		} else {
+			ringQ.NTT(ct.Value[0], ct.Value[0])
+			ringQ.NTT(ct.Value[1], ct.Value[1])
+		}
+		ct.IsNTT = !ct.IsNTT
+	}
+
+	other := ct.CopyNew()
+	flipDomain(other)
+	for i, s := range ringQ.SubRings {
+		other.Value[0].Coeffs[i][0] += s.Modulus
+	}
+	other.Tags = map[string][]byte{}
+
+	have, err := other.MarshalBinary()
+	require.NoError(t, err)
+	require.NotEqual(t, want, have)
+
+	require.NoError(t, other.Canonicalize(params))
+	require.False(t, other.IsNTT != params.NTTFlag())
+
+	have, err = other.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, want, have)
+
+	// Fingerprints agree on equal ciphertexts regardless of representation
+	// and do not mutate the receiver.
+	fp0, err := ct.Fingerprint(params)
+	require.NoError(t, err)
+	flipDomain(other)
+	fp1, err := other.Fingerprint(params)
+	require.NoError(t, err)
+	require.Equal(t, fp0, fp1)
+	require.True(t, other.IsNTT != params.NTTFlag())
+
+	ct2 := NewCiphertext(params, 1, params.MaxLevel())
+	require.NoError(t, enc.EncryptZero(ct2))
+	fp2, err := ct2.Fingerprint(params)
+	require.NoError(t, err)
+	require.NotEqual(t, fp0, fp2)
+}
@@ -0,0 +1,239 @@
+package rlwe
+
+import (
+	"fmt"
+
+	"github.com/luxdefi/lattice/v5/ring/ringqp"
+	"github.com/luxdefi/lattice/v5/utils"
+)
+
+// CircuitRotationPlan describes the set of rotations and conjugations that a
+// circuit requires, as collected from the planners of composite operations
+// (e.g. the Galois elements of a linear transformation, of the trace or of
+// the bootstrapping). It is consumed by KeyGenerator.GenGaloisKeysForCircuit,
+// which generates exactly the minimal set of Galois keys evaluating the plan.
+type CircuitRotationPlan struct {
+	// Rotations lists the cyclic slot rotations applied by the circuit.
+	// Duplicates and the identity rotation are ignored.
+	Rotations []int
+
+	// Conjugation indicates whether the circuit applies the conjugation
+	// (i.e. the row rotation). It is not defined for the ConjugateInvariant
+	// ring type.
+	Conjugation bool
+
+	// GaloisElements lists additional raw Galois elements required by the
+	// circuit, e.g. those returned by GaloisElementsForTrace or
+	// GaloisElementsForExpand, which are not plain slot rotations.
+	GaloisElements []uint64
+
+	// Decompose replaces the keys of the rotations of the plan by the keys
+	// of the signed power-of-two rotations composing them, reducing the
+	// number of keys to at most 2log(N) at the cost of up to log(N)
+	// sequential key-switches (see Evaluator.RotateComposed) and of their
+	// accumulated noise.
+	Decompose bool
+}
+
+// decomposeRotation returns the signed power-of-two rotations whose
+// composition is the rotation by k.
+func decomposeRotation(k int) (rotations []int) {
+
+	abs := k
+	if k < 0 {
+		abs = -k
+	}
+
+	for i := 0; abs>>i != 0; i++ {
+		if (abs>>i)&1 == 1 {
+			p := 1 << i
+			if k < 0 {
+				p = -p
+			}
+			rotations = append(rotations, p)
+		}
+	}
+
+	return
+}
+
+// GaloisElementsForCircuit returns the minimal set of Galois elements whose
+// keys are required to evaluate the rotations of the given plan, sorted and
+// without duplicates.
+func GaloisElementsForCircuit(params ParameterProvider, plan CircuitRotationPlan) (galEls []uint64) {
+
+	p := params.GetRLWEParameters()
+
+	set := map[uint64]bool{}
+
+	for _, k := range plan.Rotations {
+
+		if k == 0 {
+			continue
+		}
+
+		if plan.Decompose {
+			for _, ki := range decomposeRotation(k) {
+				set[p.GaloisElementForRotation(ki)] = true
+			}
+		} else {
+			set[p.GaloisElementForRotation(k)] = true
+		}
+	}
+
+	if plan.Conjugation {
+		set[p.GaloisElementOrderTwoOrthogonalSubgroup()] = true
+	}
+
+	for _, galEl := range plan.GaloisElements {
+		set[galEl] = true
+	}
+
+	return utils.GetSortedKeys(set)
+}
+
+// GenGaloisKeysForCircuit generates the minimal set of Galois keys required
+// to evaluate the rotations of the given plan with the secret key sk. If
+// plan.Decompose is set, the rotations are covered by the keys of their
+// signed power-of-two components, which are composed at evaluation time with
+// Evaluator.RotateComposed, trading key memory for latency and noise.
+func (kgen KeyGenerator) GenGaloisKeysForCircuit(plan CircuitRotationPlan, sk *SecretKey, evkParams ...EvaluationKeyParameters) (gks []*GaloisKey) {
+	return kgen.GenGaloisKeysNew(GaloisElementsForCircuit(kgen.params, plan), sk, evkParams...)
+}
+
+// composeGaloisElement returns a decomposition of galEl into Galois elements
+// whose keys are all present in the evaluator's key set, realizing the
+// rotation of galEl as a composition of signed power-of-two rotations (see
+// AllowKeyComposition). Both the rotation k of galEl and its complement
+// k - N/2 are attempted, so that e.g. a rotation by -6 can be composed from
+// the keys of the rotations -2 and -4. An error is returned if no candidate
+// is covered by the key set.
+func (eval Evaluator) composeGaloisElement(galEl uint64) (galEls []uint64, err error) {
+
+	k, err := eval.params.RotationFromGaloisElement(galEl)
+	if err != nil {
+		return nil, err
+	}
+
+	// Order of the subgroup generated by GaloisGen.
+	order := int(eval.params.RingQ().NthRoot() >> 2)
+
+	for _, candidate := range []int{k, k - order} {
+
+		components := decomposeRotation(candidate)
+
+		covered := len(components) > 0
+
+		galEls = galEls[:0]
+		for _, ki := range components {
+
+			g := eval.params.GaloisElementForRotation(ki)
+
+			// A component equal to the requested element would recurse
+			// on the same missing key.
+			if g == galEl {
+				covered = false
+				break
+			}
+
+			if _, err = eval.GetGaloisKey(g); err != nil {
+				covered = false
+				break
+			}
+
+			galEls = append(galEls, g)
+		}
+
+		if covered {
+			return galEls, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no composition of available power-of-two Galois keys realizes the rotation by %d", k)
+}
+
+// automorphismComposed applies the automorphism of galEl on ctIn as the
+// composition of the automorphisms returned by composeGaloisElement, and
+// returns the result on opOut.
+func (eval Evaluator) automorphismComposed(ctIn *Ciphertext, galEl uint64, opOut *Ciphertext) (err error) {
+
+	galEls, err := eval.composeGaloisElement(galEl)
+	if err != nil {
+		return err
+	}
+
+	ct := ctIn
+	for _, g := range galEls {
+		if err = eval.Automorphism(ct, g, opOut); err != nil {
+			return err
+		}
+		ct = opOut
+	}
+
+	return
+}
+
+// automorphismComposedLazy is the counterpart of automorphismComposed for
+// AutomorphismHoistedLazy: the composed automorphism is lifted back to the
+// representation modulo QP and scaled by P, with a zero P part (see
+// automorphismLazy).
+func (eval Evaluator) automorphismComposedLazy(levelQ int, ctIn *Ciphertext, galEl uint64, ctQP *Element[ringqp.Poly]) (err error) {
+
+	ct := NewCiphertext(eval.params, 1, levelQ)
+	*ct.MetaData = *ctIn.MetaData
+
+	if err = eval.automorphismComposed(ctIn, galEl, ct); err != nil {
+		return err
+	}
+
+	levelP := eval.params.MaxLevelP()
+
+	ringQP := eval.params.RingQP().AtLevel(levelQ, levelP)
+	ringQ := ringQP.RingQ
+
+	if ctQP.IsNTT && !ct.IsNTT {
+		ringQ.NTT(ct.Value[0], ct.Value[0])
+		ringQ.NTT(ct.Value[1], ct.Value[1])
+	} else if !ctQP.IsNTT && ct.IsNTT {
+		ringQ.INTT(ct.Value[0], ct.Value[0])
+		ringQ.INTT(ct.Value[1], ct.Value[1])
+	}
+
+	if levelP > -1 {
+		P := ringQP.RingP.ModulusAtLevel[levelP]
+		ringQ.MulScalarBigint(ct.Value[0], P, ctQP.Value[0].Q)
+		ringQ.MulScalarBigint(ct.Value[1], P, ctQP.Value[1].Q)
+		ctQP.Value[0].P.Zero()
+		ctQP.Value[1].P.Zero()
+	} else {
+		ctQP.Value[0].Q.CopyLvl(levelQ, ct.Value[0])
+		ctQP.Value[1].Q.CopyLvl(levelQ, ct.Value[1])
+	}
+
+	return
+}
+
+// RotateComposed applies the rotation by k on ctIn by composing the
+// automorphisms of the signed power-of-two rotations of its binary
+// decomposition, and returns the result on opOut. It evaluates rotations
+// with the log(N) keys of a CircuitRotationPlan generated with Decompose
+// set, at the cost of one key-switch, and its noise, per non-zero bit of k.
+func (eval Evaluator) RotateComposed(ctIn *Ciphertext, k int, opOut *Ciphertext) (err error) {
+
+	if k == 0 {
+		if opOut != ctIn {
+			opOut.Copy(ctIn)
+		}
+		return
+	}
+
+	ct := ctIn
+	for _, ki := range decomposeRotation(k) {
+		if err = eval.Automorphism(ct, eval.params.GaloisElementForRotation(ki), opOut); err != nil {
+			return fmt.Errorf("cannot RotateComposed: %w", err)
+		}
+		ct = opOut
+	}
+
+	return
+}
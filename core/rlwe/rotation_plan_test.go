@@ -0,0 +1,194 @@
+package rlwe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitRotationPlan(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN: 10,
+		LogQ: []int{55, 45},
+		LogP: []int{60},
+	})
+	require.NoError(t, err)
+
+	plan := CircuitRotationPlan{
+		Rotations:      []int{0, 1, 1, -6, 13},
+		Conjugation:    true,
+		GaloisElements: []uint64{params.GaloisElement(33)},
+	}
+
+	// The identity rotation and duplicates are ignored.
+	want := map[uint64]bool{
+		params.GaloisElement(1):                          true,
+		params.GaloisElement(-6):                         true,
+		params.GaloisElement(13):                         true,
+		params.GaloisElement(33):                         true,
+		params.GaloisElementOrderTwoOrthogonalSubgroup(): true,
+	}
+
+	galEls := GaloisElementsForCircuit(params, plan)
+	require.Equal(t, len(want), len(galEls))
+	for _, galEl := range galEls {
+		require.True(t, want[galEl])
+	}
+
+	// With key decomposition, the rotations are covered by the keys of
+	// their signed power-of-two components: 1 -> {1}, -6 -> {-2, -4} and
+	// 13 -> {1, 4, 8}.
+	plan.Decompose = true
+
+	want = map[uint64]bool{
+		params.GaloisElement(1):                          true,
+		params.GaloisElement(4):                          true,
+		params.GaloisElement(8):                          true,
+		params.GaloisElement(-2):                         true,
+		params.GaloisElement(-4):                         true,
+		params.GaloisElement(33):                         true,
+		params.GaloisElementOrderTwoOrthogonalSubgroup(): true,
+	}
+
+	galEls = GaloisElementsForCircuit(params, plan)
+	require.Equal(t, len(want), len(galEls))
+	for _, galEl := range galEls {
+		require.True(t, want[galEl])
+	}
+
+	kgen := NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+	enc := NewEncryptor(params, sk)
+	dec := NewDecryptor(params, sk)
+
+	eval := NewEvaluator(params, NewMemEvaluationKeySet(nil, kgen.GenGaloisKeysForCircuit(plan, sk)...))
+
+	level := params.MaxLevel()
+	ringQ := params.RingQ().AtLevel(level)
+
+	pt := NewPlaintext(params, level)
+	for i, s := range ringQ.SubRings[:level+1] {
+		for j := range pt.Value.Coeffs[i] {
+			pt.Value.Coeffs[i][j] = (uint64(j%16) << 40) % s.Modulus
+		}
+	}
+
+	ct := NewCiphertext(params, 1, level)
+	require.NoError(t, enc.Encrypt(pt, ct))
+
+	// The composed rotation matches the automorphism of the direct
+	// Galois element, up to the noise of one key-switch per component.
+	require.NoError(t, eval.RotateComposed(ct, 13, ct))
+
+	ref := ringQ.NewPoly()
+	ringQ.Automorphism(pt.Value, params.GaloisElement(13), ref)
+
+	ptOut := NewPlaintext(params, level)
+	dec.Decrypt(ct, ptOut)
+
+	ringQ.Sub(ptOut.Value, ref, ptOut.Value)
+	require.GreaterOrEqual(t, float64(params.LogN()), ringQ.Log2OfStandardDeviation(ptOut.Value))
+
+	// The identity rotation is a copy.
+	ctZero := NewCiphertext(params, 1, level)
+	require.NoError(t, eval.RotateComposed(ct, 0, ctZero))
+	require.True(t, ct.Equal(ctZero))
+
+	// Rotations with a missing component key are rejected.
+	require.Error(t, eval.RotateComposed(ct, 3, ctZero))
+}
+
+func TestAllowKeyComposition(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN: 10,
+		LogQ: []int{55, 45},
+		LogP: []int{60},
+	})
+	require.NoError(t, err)
+
+	kgen := NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+	enc := NewEncryptor(params, sk)
+	dec := NewDecryptor(params, sk)
+
+	// Sparse key set: only the power-of-two rotation keys of the
+	// decomposed plan are generated.
+	plan := CircuitRotationPlan{
+		Rotations: []int{5, -6},
+		Decompose: true,
+	}
+
+	eval := NewEvaluator(params, NewMemEvaluationKeySet(nil, kgen.GenGaloisKeysForCircuit(plan, sk)...))
+
+	level := params.MaxLevel()
+	ringQ := params.RingQ().AtLevel(level)
+
+	pt := NewPlaintext(params, level)
+	for i, s := range ringQ.SubRings[:level+1] {
+		for j := range pt.Value.Coeffs[i] {
+			pt.Value.Coeffs[i][j] = (uint64(j%16) << 40) % s.Modulus
+		}
+	}
+
+	ct := NewCiphertext(params, 1, level)
+	require.NoError(t, enc.Encrypt(pt, ct))
+
+	ctOut := NewCiphertext(params, 1, level)
+
+	// Without the option, the exact key for the rotation by 5 is missing.
+	require.Error(t, eval.Automorphism(ct, params.GaloisElement(5), ctOut))
+
+	evalC := eval.AllowKeyComposition(true)
+
+	for _, k := range []int{
+		5,  // composed from the keys of the rotations 1 and 4
+		-6, // composed from the keys of the rotations -2 and -4
+	} {
+
+		galEl := params.GaloisElement(k)
+
+		require.NoError(t, evalC.Automorphism(ct, galEl, ctOut))
+
+		ref := ringQ.NewPoly()
+		ringQ.Automorphism(pt.Value, galEl, ref)
+
+		ptOut := NewPlaintext(params, level)
+		dec.Decrypt(ctOut, ptOut)
+
+		ringQ.Sub(ptOut.Value, ref, ptOut.Value)
+		require.GreaterOrEqual(t, float64(params.LogN()), ringQ.Log2OfStandardDeviation(ptOut.Value))
+	}
+
+	// Rotations that no composition of the available keys covers, and
+	// elements outside of the rotation subgroup, are still rejected.
+	require.Error(t, evalC.Automorphism(ct, params.GaloisElement(3), ctOut))
+	require.Error(t, evalC.Automorphism(ct, params.GaloisElementOrderTwoOrthogonalSubgroup(), ctOut))
+
+	// The hoisted variants compose as well, and preserve the shared
+	// decomposition for subsequent hoisted operations.
+	eval.DecomposeNTT(level, params.MaxLevelP(), params.MaxLevelP()+1, ct.Value[1], ct.IsNTT, eval.BuffDecompQP)
+
+	require.NoError(t, evalC.AutomorphismHoisted(level, ct, eval.BuffDecompQP, params.GaloisElement(5), ctOut))
+
+	ref := ringQ.NewPoly()
+	ringQ.Automorphism(pt.Value, params.GaloisElement(5), ref)
+
+	ptOut := NewPlaintext(params, level)
+	dec.Decrypt(ctOut, ptOut)
+
+	ringQ.Sub(ptOut.Value, ref, ptOut.Value)
+	require.GreaterOrEqual(t, float64(params.LogN()), ringQ.Log2OfStandardDeviation(ptOut.Value))
+
+	// The hoisted automorphism with a key present in the set still matches
+	// after the composed fallback reused the evaluator buffers.
+	require.NoError(t, evalC.AutomorphismHoisted(level, ct, eval.BuffDecompQP, params.GaloisElement(4), ctOut))
+
+	ringQ.Automorphism(pt.Value, params.GaloisElement(4), ref)
+	dec.Decrypt(ctOut, ptOut)
+	ringQ.Sub(ptOut.Value, ref, ptOut.Value)
+	require.GreaterOrEqual(t, float64(params.LogN()), ringQ.Log2OfStandardDeviation(ptOut.Value))
+}
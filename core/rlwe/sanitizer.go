@@ -0,0 +1,142 @@
+package rlwe
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/luxdefi/lattice/v5/utils/sampling"
+)
+
+// Sanitizer rerandomizes ciphertexts so that servers can return them
+// without leaking anything about the evaluated circuit beyond its result.
+// Sanitize adds a fresh public-key encryption of zero, making the
+// randomness of the ciphertext independent of the computation, and floods
+// the noise with a uniform error 2^{lambda} times larger than the noise
+// bound of the input, drowning the circuit-dependent noise up to a
+// statistical distance of 2^{-lambda}.
+//
+// The flooding noise must fit in the modulus at the level of the
+// ciphertext, with enough margin left for the message: sanitization
+// consumes lambda + logNoiseBound bits of the noise budget.
+type Sanitizer struct {
+	enc    *Encryptor
+	params Parameters
+	prng   sampling.PRNG
+
+	// lambda is the statistical security parameter, in bits.
+	lambda int
+
+	// logNoiseBound is the default log2 bound on the noise magnitude of
+	// the ciphertexts to sanitize, used when a ciphertext does not carry
+	// a tracked bound (see NoiseBound).
+	logNoiseBound float64
+}
+
+// NewSanitizer instantiates a new Sanitizer from the given public key,
+// statistical security parameter lambda and default log2 noise bound for
+// the ciphertexts to sanitize. Ciphertexts carrying a tracked noise bound
+// (see NoiseEstimator) are flooded according to their own bound instead.
+func NewSanitizer(params ParameterProvider, pk *PublicKey, lambda int, logNoiseBound float64) (san *Sanitizer, err error) {
+
+	if lambda < 1 {
+		return nil, fmt.Errorf("cannot NewSanitizer: lambda must be positive")
+	}
+
+	prng, err := sampling.NewPRNG()
+	if err != nil {
+		return nil, fmt.Errorf("cannot NewSanitizer: %w", err)
+	}
+
+	return &Sanitizer{
+		enc:           NewEncryptor(params, pk),
+		params:        *params.GetRLWEParameters(),
+		prng:          prng,
+		lambda:        lambda,
+		logNoiseBound: logNoiseBound,
+	}, nil
+}
+
+// Sanitize rerandomizes the ciphertext in place: it adds a fresh
+// encryption of zero under the public key and floods the noise with a
+// uniform error of 2^{lambda + logNoiseBound} bits, where logNoiseBound is
+// the tracked noise bound of the ciphertext if it carries one and the
+// default of the Sanitizer otherwise. The ciphertext must be of degree one
+// (i.e. relinearized) and the flooding noise must fit in the modulus at
+// its level.
+func (san *Sanitizer) Sanitize(ct *Ciphertext) (err error) {
+
+	if ct.Degree() != 1 {
+		return fmt.Errorf("cannot Sanitize: ct.Degree() != 1")
+	}
+
+	bound := san.logNoiseBound
+	if b, ok := NoiseBound(ct.MetaData); ok {
+		bound = b
+	}
+
+	logFlood := int(math.Ceil(bound)) + san.lambda
+
+	level := ct.Level()
+
+	ringQ := san.params.RingQ().AtLevel(level)
+
+	var logQ float64
+	for _, qi := range san.params.Q()[:level+1] {
+		logQ += math.Log2(float64(qi))
+	}
+
+	if float64(logFlood)+2 > logQ {
+		return fmt.Errorf("cannot Sanitize: flooding noise of %d bits does not fit in the %f-bit modulus at level %d", logFlood, logQ, level)
+	}
+
+	// Rerandomization: ct += Enc_pk(0).
+	zero := NewCiphertext(san.params, 1, level)
+	*zero.MetaData = *ct.MetaData
+
+	if err = san.enc.EncryptZero(zero); err != nil {
+		return fmt.Errorf("cannot Sanitize: %w", err)
+	}
+
+	ringQ.Add(ct.Value[0], zero.Value[0], ct.Value[0])
+	ringQ.Add(ct.Value[1], zero.Value[1], ct.Value[1])
+
+	// Noise flooding: ct[0] += e, with the coefficients of e uniform in
+	// [-2^{logFlood}, 2^{logFlood}).
+	N := san.params.N()
+
+	// 64 bits of slack make the bias of the modular reduction negligible.
+	bytes := make([]byte, (logFlood+1+64+7)>>3)
+	mask := new(big.Int).Lsh(big.NewInt(1), uint(logFlood)+1)
+	half := new(big.Int).Lsh(big.NewInt(1), uint(logFlood))
+
+	coeffs := make([]*big.Int, N)
+	for i := range coeffs {
+
+		if _, err = san.prng.Read(bytes); err != nil {
+			return fmt.Errorf("cannot Sanitize: %w", err)
+		}
+
+		c := new(big.Int).SetBytes(bytes)
+		c.Mod(c, mask)
+		c.Sub(c, half)
+
+		coeffs[i] = c
+	}
+
+	e := ringQ.NewPoly()
+	ringQ.SetCoefficientsBigint(coeffs, e)
+
+	if ct.IsNTT {
+		ringQ.NTT(e, e)
+	}
+
+	ringQ.Add(ct.Value[0], e, ct.Value[0])
+
+	// Updates the tracked noise bound, if any.
+	if _, ok := NoiseBound(ct.MetaData); ok {
+		return SetNoiseBound(ct.MetaData, float64(logFlood)+1)
+	}
+
+	return
+}
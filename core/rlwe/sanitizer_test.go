@@ -0,0 +1,82 @@
+package rlwe
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSanitizer(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN: 10,
+		LogQ: []int{55, 45},
+		LogP: []int{60},
+	})
+	require.NoError(t, err)
+
+	kgen := NewKeyGenerator(params)
+	sk, pk := kgen.GenKeyPairNew()
+	enc := NewEncryptor(params, sk)
+
+	lambda := 40
+	logNoiseBound := math.Log2(6 * params.NoiseFreshSK())
+
+	san, err := NewSanitizer(params, pk, lambda, logNoiseBound)
+	require.NoError(t, err)
+
+	// Message coefficients scaled by 2^{50}, well above the flooding
+	// noise of roughly 2^{45} bits.
+	level := params.MaxLevel()
+	ringQ := params.RingQ().AtLevel(level)
+
+	pt := NewPlaintext(params, level)
+	for i, s := range ringQ.SubRings[:level+1] {
+		for j := range pt.Value.Coeffs[i] {
+			pt.Value.Coeffs[i][j] = (uint64(j%4) << 50) % s.Modulus
+		}
+	}
+
+	ct := NewCiphertext(params, 1, level)
+	require.NoError(t, enc.Encrypt(pt, ct))
+
+	before := ct.CopyNew()
+
+	require.NoError(t, san.Sanitize(ct))
+
+	// The ciphertext has been rerandomized.
+	require.False(t, ct.Equal(before))
+
+	// The message is preserved up to the flooding noise.
+	est := NewNoiseEstimator(params)
+	noise := est.MeasureNoise(ct, pt, sk)
+	require.Less(t, noise, 50.0)
+
+	// The noise has been flooded up to the statistical bound.
+	require.Greater(t, noise, float64(lambda))
+
+	// A tracked noise bound is used and updated.
+	ct2 := NewCiphertext(params, 1, level)
+	require.NoError(t, enc.Encrypt(pt, ct2))
+	require.NoError(t, est.MarkFreshSK(ct2))
+	require.NoError(t, san.Sanitize(ct2))
+	bound, ok := NoiseBound(ct2.MetaData)
+	require.True(t, ok)
+	require.Greater(t, bound, float64(lambda))
+	require.Greater(t, bound, est.MeasureNoise(ct2, pt, sk))
+
+	// Degree-two ciphertexts are rejected.
+	require.Error(t, san.Sanitize(NewCiphertext(params, 2, level)))
+
+	// The flooding noise must fit in the modulus at the level of the
+	// ciphertext.
+	sanWide, err := NewSanitizer(params, pk, 60, logNoiseBound)
+	require.NoError(t, err)
+	require.Error(t, sanWide.Sanitize(NewCiphertext(params, 1, 0)))
+
+	// Invalid security parameter.
+	_, err = NewSanitizer(params, pk, 0, logNoiseBound)
+	require.Error(t, err)
+}
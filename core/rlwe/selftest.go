@@ -0,0 +1,145 @@
+package rlwe
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// selfTestNoiseSlack is the margin in bits added to the noise estimate of
+// each check: the measured noise of a healthy deployment stays within a
+// fraction of a bit of the estimate, while a misconfiguration (corrupted or
+// mismatched keys, wrong parameters) produces noise close to uniform, i.e.
+// tens of bits above it.
+const selfTestNoiseSlack = 4.0
+
+// SelfTestCheck is the outcome of a single check of Parameters.SelfTest.
+type SelfTestCheck struct {
+	// Name identifies the check.
+	Name string
+	// Noise is the measured log2 of the standard deviation of the noise.
+	Noise float64
+	// Bound is the largest noise admissible for the check to pass.
+	Bound float64
+	// Passed reports whether Noise is below Bound.
+	Passed bool
+}
+
+// SelfTestReport holds the outcome of Parameters.SelfTest, one entry per
+// check in the order they were run.
+type SelfTestReport struct {
+	Checks []SelfTestCheck
+}
+
+// Passed reports whether all the checks of the report passed.
+func (r SelfTestReport) Passed() bool {
+	for _, check := range r.Checks {
+		if !check.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns a human-readable summary of the report, one line per check.
+func (r SelfTestReport) String() string {
+	var sb strings.Builder
+	for _, check := range r.Checks {
+		status := "PASS"
+		if !check.Passed {
+			status = "FAIL"
+		}
+		fmt.Fprintf(&sb, "%s %s: noise=%.2f, bound=%.2f\n", status, check.Name, check.Noise, check.Bound)
+	}
+	return sb.String()
+}
+
+// SelfTest runs a quick encrypt/evaluate/decrypt round-trip on freshly
+// generated keys and checks the measured noise of each step against the
+// estimate for the parameters. It is meant to be called once at service
+// start-up so that a misconfigured deployment fails fast with a structured
+// diagnostic instead of producing corrupt results. The returned error is
+// non-nil if a step could not be run or if any check failed; the report
+// details the outcome of each check either way.
+func (p Parameters) SelfTest() (report *SelfTestReport, err error) {
+
+	report = &SelfTestReport{}
+
+	kgen := NewKeyGenerator(p)
+	sk := kgen.GenSecretKeyNew()
+	pk := kgen.GenPublicKeyNew(sk)
+
+	levelQ := p.MaxLevelQ()
+	levelP := p.MaxLevelP()
+	ringQ := p.RingQ().AtLevel(levelQ)
+
+	measure := func(ct *Ciphertext, sk *SecretKey) float64 {
+		pt := NewDecryptor(p, sk).DecryptNew(ct)
+		if pt.IsNTT {
+			ringQ.INTT(pt.Value, pt.Value)
+		}
+		return ringQ.Log2OfStandardDeviation(pt.Value)
+	}
+
+	check := func(name string, noise, bound float64) {
+		report.Checks = append(report.Checks, SelfTestCheck{
+			Name:   name,
+			Noise:  noise,
+			Bound:  bound,
+			Passed: noise <= bound,
+		})
+	}
+
+	// Fresh secret-key encryption: the noise is the error distribution.
+	ct := NewCiphertext(p, 1, levelQ)
+	if err = NewEncryptor(p, sk).EncryptZero(ct); err != nil {
+		return report, fmt.Errorf("cannot SelfTest: %w", err)
+	}
+	check("encrypt/decrypt (sk)", measure(ct, sk), math.Log2(p.NoiseFreshSK())+selfTestNoiseSlack)
+
+	// Fresh public-key encryption: checks the consistency of the public key
+	// with the secret key.
+	if err = NewEncryptor(p, pk).EncryptZero(ct); err != nil {
+		return report, fmt.Errorf("cannot SelfTest: %w", err)
+	}
+	check("encrypt/decrypt (pk)", measure(ct, sk), math.Log2(p.NoiseFreshPK())+selfTestNoiseSlack)
+
+	// The noise after one key switch of a fresh secret-key encryption: the
+	// variance of the fresh noise and of the key-switch noise add.
+	freshStd := p.NoiseFreshSK()
+	ksStd := math.Exp2(EstimateKeySwitchingNoise(p, levelQ, levelP, 0))
+	ksBound := 0.5*math.Log2(freshStd*freshStd+ksStd*ksStd) + selfTestNoiseSlack
+
+	// Key switch to a second secret key: checks the consistency of the
+	// evaluation-key generation and of its application.
+	skOut := kgen.GenSecretKeyNew()
+	evk := kgen.GenEvaluationKeyNew(sk, skOut)
+	eval := NewEvaluator(p, nil)
+
+	if err = NewEncryptor(p, sk).EncryptZero(ct); err != nil {
+		return report, fmt.Errorf("cannot SelfTest: %w", err)
+	}
+	if err = eval.ApplyEvaluationKey(ct, evk, ct); err != nil {
+		return report, fmt.Errorf("cannot SelfTest: %w", err)
+	}
+	check("key switch", measure(ct, skOut), ksBound)
+
+	// Automorphism with a Galois key: checks the Galois-key generation and
+	// the automorphism evaluation.
+	galEl := p.GaloisElement(1)
+	evalGal := NewEvaluator(p, NewMemEvaluationKeySet(nil, kgen.GenGaloisKeysNew([]uint64{galEl}, sk)...))
+
+	if err = NewEncryptor(p, sk).EncryptZero(ct); err != nil {
+		return report, fmt.Errorf("cannot SelfTest: %w", err)
+	}
+	if err = evalGal.Automorphism(ct, galEl, ct); err != nil {
+		return report, fmt.Errorf("cannot SelfTest: %w", err)
+	}
+	check("automorphism", measure(ct, sk), ksBound)
+
+	if !report.Passed() {
+		return report, fmt.Errorf("self-test failed:\n%s", report)
+	}
+
+	return report, nil
+}
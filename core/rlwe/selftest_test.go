@@ -0,0 +1,29 @@
+package rlwe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxdefi/lattice/v5/ring"
+)
+
+func TestSelfTest(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN: 10,
+		Q:    ring.Qi60[:2],
+		P:    ring.Pi60[:1],
+	})
+	require.NoError(t, err)
+
+	report, err := params.SelfTest()
+	require.NoError(t, err)
+	require.True(t, report.Passed())
+	require.Len(t, report.Checks, 4)
+	for _, check := range report.Checks {
+		require.True(t, check.Passed, report.String())
+		require.Less(t, check.Noise, check.Bound)
+	}
+}
@@ -0,0 +1,53 @@
+package rlwe
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+var strictMode atomic.Bool
+
+// SetStrictMode enables or disables the strict mode. When the strict mode is
+// enabled, evaluators and encoders report malformed inputs and internal
+// invariant violations through their error return value instead of panicking,
+// so that a malformed request cannot crash a long-running service. Methods
+// whose signature has no error return (e.g. Decryptor.Decrypt or the ring
+// level accessors) still panic on misuse and can be protected with
+// WithRecovery. The mode is global and safe for concurrent use.
+func SetStrictMode(enabled bool) {
+	strictMode.Store(enabled)
+}
+
+// StrictModeEnabled returns whether the strict mode is enabled (see SetStrictMode).
+func StrictModeEnabled() bool {
+	return strictMode.Load()
+}
+
+// Fault reports a malformed input or an invariant violation from a method
+// with an error return: it returns the error if the strict mode is enabled
+// and panics otherwise (see SetStrictMode). It is meant to be used as
+// `return Fault(err)` at sites that would otherwise panic unconditionally.
+func Fault(err error) error {
+	if StrictModeEnabled() {
+		return err
+	}
+	panic(err)
+}
+
+// WithRecovery runs f and converts any panic raised during its execution into
+// a returned error. It protects calls to methods whose signature has no error
+// return (e.g. Decryptor.Decrypt) in services that must not crash on
+// malformed inputs.
+func WithRecovery(f func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			switch r := r.(type) {
+			case error:
+				err = fmt.Errorf("recovered from panic: %w", r)
+			default:
+				err = fmt.Errorf("recovered from panic: %v", r)
+			}
+		}
+	}()
+	return f()
+}
@@ -1,6 +1,7 @@
 package rlwe
 
 import (
+	"fmt"
 	"math"
 	"math/big"
 
@@ -281,3 +282,74 @@ func ExtendBasisSmallNormAndCenterNTTMontgomery(rQ, rP *ring.Ring, polQ, buff, p
 	rP.NTT(polP, polP)
 	rP.MForm(polP, polP)
 }
+
+// SwitchCiphertextModulusChain switches ctIn, defined over the moduli chain of
+// paramsIn at the level of ctIn, to the chain of paramsOut at the level
+// levelOut, dropping (with a rounded division by their product) the moduli of
+// the source chain that do not appear in the target chain. The target chain
+// must be an in-order subset of the source chain. The scale of the returned
+// ciphertext is the scale of ctIn divided by the product of the dropped
+// moduli. This utility is intended for aligning ciphertexts produced under an
+// imported parameter set with a local one; rescaling within a single
+// parameter set should use the scheme evaluators instead.
+func SwitchCiphertextModulusChain(paramsIn Parameters, ctIn *Ciphertext, paramsOut Parameters, levelOut int) (ctOut *Ciphertext, err error) {
+
+	if paramsIn.N() != paramsOut.N() {
+		return nil, fmt.Errorf("cannot SwitchCiphertextModulusChain: ring degree %d of paramsIn does not match ring degree %d of paramsOut", paramsIn.N(), paramsOut.N())
+	}
+
+	if paramsIn.RingType() != paramsOut.RingType() {
+		return nil, fmt.Errorf("cannot SwitchCiphertextModulusChain: ring type of paramsIn does not match ring type of paramsOut")
+	}
+
+	if levelOut < 0 || levelOut > paramsOut.MaxLevel() {
+		return nil, fmt.Errorf("cannot SwitchCiphertextModulusChain: levelOut=%d lies outside [0, %d]", levelOut, paramsOut.MaxLevel())
+	}
+
+	rIn := paramsIn.RingQ().AtLevel(ctIn.Level())
+	rOut := paramsOut.RingQ().AtLevel(levelOut)
+
+	// The dropped moduli are those of the source chain that do not appear in
+	// the target chain; the kept ones must match the target chain in order.
+	var dropped []int
+	for i, j := 0, 0; i < ctIn.Level()+1; i++ {
+		if j < levelOut+1 && rIn.SubRings[i].Modulus == rOut.SubRings[j].Modulus {
+			j++
+		} else {
+			dropped = append(dropped, i)
+		}
+	}
+
+	if ctIn.Level()+1-len(dropped) != levelOut+1 {
+		return nil, fmt.Errorf("cannot SwitchCiphertextModulusChain: the target chain is not an in-order subset of the source chain")
+	}
+
+	ctOut = NewCiphertext(paramsOut, ctIn.Degree(), levelOut)
+
+	// Copies the metadata but rebinds the ciphertext to paramsOut.
+	fingerprint := ctOut.ParameterFingerprint
+	*ctOut.MetaData = *ctIn.MetaData
+	ctOut.ParameterFingerprint = fingerprint
+
+	var buff ring.Poly
+	if ctIn.IsNTT {
+		buff = rIn.NewPoly()
+	}
+
+	for i := range ctIn.Value {
+		if ctIn.IsNTT {
+			err = rIn.DivRoundByModuliNTT(rOut, dropped, ctIn.Value[i], buff, ctOut.Value[i])
+		} else {
+			err = rIn.DivRoundByModuli(rOut, dropped, ctIn.Value[i], ctOut.Value[i])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot SwitchCiphertextModulusChain: %w", err)
+		}
+	}
+
+	for _, i := range dropped {
+		ctOut.Scale = ctOut.Scale.Div(NewScale(rIn.SubRings[i].Modulus))
+	}
+
+	return
+}
@@ -0,0 +1,36 @@
+package rlwe
+
+// This file declares the serialization format version of the serializable
+// types of the package (see lattice/utils/compat). The encodings of all the
+// types below have been stable since format version 1, so payloads written by
+// the previous releases deserialize directly without an upgrade step.
+
+// FormatVersion returns the format version in which the current encoding of the object was introduced.
+func (sk SecretKey) FormatVersion() uint8 { return 1 }
+
+// FormatVersion returns the format version in which the current encoding of the object was introduced.
+func (p PublicKey) FormatVersion() uint8 { return 1 }
+
+// FormatVersion returns the format version in which the current encoding of the object was introduced.
+func (evk EvaluationKey) FormatVersion() uint8 { return 1 }
+
+// FormatVersion returns the format version in which the current encoding of the object was introduced.
+func (rlk RelinearizationKey) FormatVersion() uint8 { return 1 }
+
+// FormatVersion returns the format version in which the current encoding of the object was introduced.
+func (gk GaloisKey) FormatVersion() uint8 { return 1 }
+
+// FormatVersion returns the format version in which the current encoding of the object was introduced.
+func (evk *MemEvaluationKeySet) FormatVersion() uint8 { return 1 }
+
+// FormatVersion returns the format version in which the current encoding of the object was introduced.
+func (ct GadgetCiphertext) FormatVersion() uint8 { return 1 }
+
+// FormatVersion returns the format version in which the current encoding of the object was introduced.
+func (op Element[T]) FormatVersion() uint8 { return 1 }
+
+// FormatVersion returns the format version in which the current encoding of the object was introduced.
+func (m MetaData) FormatVersion() uint8 { return 1 }
+
+// FormatVersion returns the format version in which the current encoding of the object was introduced.
+func (p Parameters) FormatVersion() uint8 { return 1 }
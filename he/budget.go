@@ -0,0 +1,113 @@
+package he
+
+// Op identifies one operation of a plan simulated by EvaluateBudget. The
+// operations mirror the calls of the scheme evaluators, with each
+// multiplication accounting for the rescaling that must eventually follow
+// it (a multiplication of a plan must therefore not be paired with an
+// additional OpRescale).
+type Op int
+
+const (
+	// OpAdd is an addition or a subtraction with a ciphertext, a plaintext
+	// or a scalar. It consumes no level and does not change the degree.
+	OpAdd Op = iota
+	// OpMulPlain is a multiplication with a plaintext or a scalar, followed
+	// by its rescaling. It does not change the degree.
+	OpMulPlain
+	// OpMul is a multiplication with a degree-one ciphertext without
+	// relinearization, followed by its rescaling. It increases the degree
+	// by one.
+	OpMul
+	// OpMulRelin is a multiplication with a degree-one ciphertext followed
+	// by a relinearization and its rescaling.
+	OpMulRelin
+	// OpRelin is a relinearization back to degree one, e.g. after a series
+	// of OpMul.
+	OpRelin
+	// OpRescale is a rescaling not already accounted for by a
+	// multiplication, e.g. after an accumulation of MulThenAdd.
+	OpRescale
+	// OpRotate is a rotation or a conjugation, which requires a degree-one
+	// operand: a preliminary relinearization is recorded if the degree is
+	// larger.
+	OpRotate
+)
+
+// RelinearizationRequirement records that the operation at the index Op of a
+// plan requires a relinearization key to reduce a ciphertext of degree
+// Degree back to degree one.
+type RelinearizationRequirement struct {
+	Op     int
+	Degree int
+}
+
+// Budget reports the outcome of the simulation of a plan of operations by
+// EvaluateBudget, surfacing the level, degree and key bookkeeping that the
+// evaluators perform internally.
+type Budget struct {
+	// Feasible is true if every operation of the plan fits within the level
+	// budget of the ciphertext.
+	Feasible bool
+	// FailsAt is the index of the first operation of the plan exceeding the
+	// level budget, or -1 if the plan is feasible.
+	FailsAt int
+	// Level is the level of the ciphertext after the plan, or before the
+	// failing operation.
+	Level int
+	// Degree is the degree of the ciphertext after the plan, or before the
+	// failing operation.
+	Degree int
+	// RemainingMultiplications is the number of further multiplications,
+	// each followed by its rescaling (equivalently, the number of further
+	// rescalings), that the remaining levels allow.
+	RemainingMultiplications int
+	// Relinearizations lists, in the order of the plan, the operations
+	// requiring a relinearization key, along with the degree of the
+	// ciphertext they relinearize.
+	Relinearizations []RelinearizationRequirement
+}
+
+// EvaluateBudget simulates, without evaluating it, a plan of operations on a
+// ciphertext of the given level and degree, and reports how many more
+// multiplications and rescalings are possible and which operations require a
+// relinearization key (see Budget).
+//
+// levelsPerRescaling is the number of levels consumed by one rescaling: 1
+// for heint and params.LevelsConsumedPerRescaling() for hefloat. The degree
+// bookkeeping assumes that the second operand of every ciphertext
+// multiplication is of degree one.
+func EvaluateBudget(level, degree, levelsPerRescaling int, plan []Op) (budget Budget) {
+
+	budget = Budget{FailsAt: -1, Level: level, Degree: degree}
+
+	for i, op := range plan {
+
+		switch op {
+		case OpMulPlain, OpMul, OpMulRelin, OpRescale:
+			if budget.Level < levelsPerRescaling {
+				budget.FailsAt = i
+				budget.RemainingMultiplications = budget.Level / levelsPerRescaling
+				return
+			}
+			budget.Level -= levelsPerRescaling
+		}
+
+		switch op {
+		case OpMul:
+			budget.Degree++
+		case OpMulRelin:
+			budget.Relinearizations = append(budget.Relinearizations, RelinearizationRequirement{Op: i, Degree: budget.Degree + 1})
+			budget.Degree = 1
+		case OpRelin, OpRotate:
+			if budget.Degree > 1 {
+				budget.Relinearizations = append(budget.Relinearizations, RelinearizationRequirement{Op: i, Degree: budget.Degree})
+				budget.Degree = 1
+			}
+		}
+	}
+
+	budget.Feasible = true
+	budget.RemainingMultiplications = budget.Level / levelsPerRescaling
+
+	return
+}
@@ -0,0 +1,57 @@
+package he
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBudget(t *testing.T) {
+
+	t.Run("Feasible", func(t *testing.T) {
+
+		budget := EvaluateBudget(6, 1, 1, []Op{OpAdd, OpMulRelin, OpMulPlain, OpRescale})
+
+		require.True(t, budget.Feasible)
+		require.Equal(t, -1, budget.FailsAt)
+		require.Equal(t, 3, budget.Level)
+		require.Equal(t, 1, budget.Degree)
+		require.Equal(t, 3, budget.RemainingMultiplications)
+		require.Equal(t, []RelinearizationRequirement{{Op: 1, Degree: 2}}, budget.Relinearizations)
+	})
+
+	t.Run("Infeasible", func(t *testing.T) {
+
+		budget := EvaluateBudget(2, 1, 1, []Op{OpMulRelin, OpMulRelin, OpMulRelin})
+
+		require.False(t, budget.Feasible)
+		require.Equal(t, 2, budget.FailsAt)
+		require.Equal(t, 0, budget.Level)
+		require.Equal(t, 0, budget.RemainingMultiplications)
+	})
+
+	t.Run("LevelsPerRescaling", func(t *testing.T) {
+
+		budget := EvaluateBudget(5, 1, 2, []Op{OpMulRelin, OpMulPlain})
+
+		require.True(t, budget.Feasible)
+		require.Equal(t, 1, budget.Level)
+		require.Equal(t, 0, budget.RemainingMultiplications)
+
+		require.False(t, EvaluateBudget(5, 1, 2, []Op{OpMulRelin, OpMulPlain, OpRescale}).Feasible)
+	})
+
+	t.Run("Degree", func(t *testing.T) {
+
+		// Two unrelinearized multiplications followed by a rotation, which
+		// requires a preliminary relinearization of the degree-3 ciphertext.
+		budget := EvaluateBudget(6, 1, 1, []Op{OpMul, OpMul, OpRotate, OpMulRelin})
+
+		require.True(t, budget.Feasible)
+		require.Equal(t, 1, budget.Degree)
+		require.Equal(t, []RelinearizationRequirement{{Op: 2, Degree: 3}, {Op: 3, Degree: 2}}, budget.Relinearizations)
+
+		// Additions and rotations of degree-one ciphertexts require no key.
+		require.Nil(t, EvaluateBudget(6, 1, 1, []Op{OpAdd, OpRotate, OpRelin}).Relinearizations)
+	})
+}
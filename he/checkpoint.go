@@ -0,0 +1,288 @@
+package he
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/utils"
+	"github.com/luxdefi/lattice/v5/utils/buffer"
+)
+
+// Checkpoint stores the resumable state of a long-running encrypted
+// computation: a caller-chosen step marker, named intermediate ciphertexts
+// and named power bases. A batch job periodically writes its checkpoint to
+// disk with WriteTo and, after a restart, reads it back with ReadFrom and
+// resumes from Step with a freshly instantiated evaluator (evaluator
+// buffers, such as cached hoisted decompositions, are cheap scratch state
+// that is regenerated on the first operation and is not persisted).
+//
+// Objects are stored by reference: the caller must not mutate a ciphertext
+// or power basis between setting it and writing the checkpoint.
+type Checkpoint struct {
+	// Step is a caller-chosen marker of the progress of the computation,
+	// e.g. the index of the last completed batch.
+	Step uint64
+
+	Ciphertexts map[string]*rlwe.Ciphertext
+	PowerBases  map[string]*PowerBasis
+}
+
+// NewCheckpoint creates an empty Checkpoint at step zero.
+func NewCheckpoint() *Checkpoint {
+	return &Checkpoint{
+		Ciphertexts: map[string]*rlwe.Ciphertext{},
+		PowerBases:  map[string]*PowerBasis{},
+	}
+}
+
+// SetCiphertext records ct under the given name, replacing any previous
+// ciphertext with that name.
+func (c *Checkpoint) SetCiphertext(name string, ct *rlwe.Ciphertext) {
+	c.Ciphertexts[name] = ct
+}
+
+// Ciphertext returns the ciphertext recorded under the given name.
+func (c *Checkpoint) Ciphertext(name string) (ct *rlwe.Ciphertext, err error) {
+	if ct = c.Ciphertexts[name]; ct == nil {
+		return nil, fmt.Errorf("checkpoint contains no ciphertext %q", name)
+	}
+	return
+}
+
+// SetPowerBasis records basis under the given name, replacing any previous
+// power basis with that name.
+func (c *Checkpoint) SetPowerBasis(name string, basis *PowerBasis) {
+	c.PowerBases[name] = basis
+}
+
+// PowerBasis returns the power basis recorded under the given name.
+func (c *Checkpoint) PowerBasis(name string) (basis *PowerBasis, err error) {
+	if basis = c.PowerBases[name]; basis == nil {
+		return nil, fmt.Errorf("checkpoint contains no power basis %q", name)
+	}
+	return
+}
+
+// BinarySize returns the serialized size of the object in bytes.
+func (c Checkpoint) BinarySize() (size int) {
+
+	size = 8 + 8 + 8 // Step and the two map lengths
+
+	for name, ct := range c.Ciphertexts {
+		size += 8 + len(name) + ct.BinarySize()
+	}
+
+	for name, basis := range c.PowerBases {
+		size += 8 + len(name) + basis.BinarySize()
+	}
+
+	return
+}
+
+// writeName writes a length-prefixed name on w.
+func writeName(w buffer.Writer, name string) (n int64, err error) {
+
+	var inc int64
+
+	if inc, err = buffer.WriteUint64(w, uint64(len(name))); err != nil {
+		return n + inc, err
+	}
+
+	n += inc
+
+	inc, err = buffer.Write(w, []byte(name))
+
+	return n + inc, err
+}
+
+// readName reads a length-prefixed name from r.
+func readName(r buffer.Reader, name *string) (n int64, err error) {
+
+	var inc int64
+
+	var size uint64
+	if inc, err = buffer.ReadUint64(r, &size); err != nil {
+		return n + inc, err
+	}
+
+	n += inc
+
+	p := make([]byte, size)
+	if inc, err = buffer.Read(r, p); err != nil {
+		return n + inc, err
+	}
+
+	*name = string(p)
+
+	return n + inc, nil
+}
+
+// WriteTo writes the object on an io.Writer. It implements the io.WriterTo
+// interface, and will write exactly object.BinarySize() bytes on w.
+//
+// Unless w implements the buffer.Writer interface (see lattice/utils/buffer/writer.go),
+// it will be wrapped into a bufio.Writer. Since this requires allocations, it
+// is preferable to pass a buffer.Writer directly:
+//
+//   - When writing multiple times to a io.Writer, it is preferable to first wrap the
+//     io.Writer in a pre-allocated bufio.Writer.
+//   - When writing to a pre-allocated var b []byte, it is preferable to pass
+//     buffer.NewBuffer(b) as w (see lattice/utils/buffer/buffer.go).
+func (c Checkpoint) WriteTo(w io.Writer) (n int64, err error) {
+	switch w := w.(type) {
+	case buffer.Writer:
+
+		var inc int64
+
+		if inc, err = buffer.WriteUint64(w, c.Step); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		if inc, err = buffer.WriteUint64(w, uint64(len(c.Ciphertexts))); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		for _, name := range utils.GetSortedKeys(c.Ciphertexts) {
+
+			if inc, err = writeName(w, name); err != nil {
+				return n + inc, err
+			}
+
+			n += inc
+
+			if inc, err = c.Ciphertexts[name].WriteTo(w); err != nil {
+				return n + inc, err
+			}
+
+			n += inc
+		}
+
+		if inc, err = buffer.WriteUint64(w, uint64(len(c.PowerBases))); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		for _, name := range utils.GetSortedKeys(c.PowerBases) {
+
+			if inc, err = writeName(w, name); err != nil {
+				return n + inc, err
+			}
+
+			n += inc
+
+			if inc, err = c.PowerBases[name].WriteTo(w); err != nil {
+				return n + inc, err
+			}
+
+			n += inc
+		}
+
+		return n, w.Flush()
+
+	default:
+		return buffer.WriteWithPool(w, c.WriteTo)
+	}
+}
+
+// ReadFrom reads on the object from an io.Writer. It implements the
+// io.ReaderFrom interface.
+//
+// Unless r implements the buffer.Reader interface (see see lattice/utils/buffer/reader.go),
+// it will be wrapped into a bufio.Reader. Since this requires allocation, it
+// is preferable to pass a buffer.Reader directly:
+//
+//   - When reading multiple values from a io.Reader, it is preferable to first
+//     first wrap io.Reader in a pre-allocated bufio.Reader.
+//   - When reading from a var b []byte, it is preferable to pass a buffer.NewBuffer(b)
+//     as w (see lattice/utils/buffer/buffer.go).
+func (c *Checkpoint) ReadFrom(r io.Reader) (n int64, err error) {
+	switch r := r.(type) {
+	case buffer.Reader:
+
+		var inc int64
+
+		if inc, err = buffer.ReadUint64(r, &c.Step); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		var count uint64
+		if inc, err = buffer.ReadUint64(r, &count); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		c.Ciphertexts = make(map[string]*rlwe.Ciphertext, count)
+		for i := uint64(0); i < count; i++ {
+
+			var name string
+			if inc, err = readName(r, &name); err != nil {
+				return n + inc, err
+			}
+
+			n += inc
+
+			ct := new(rlwe.Ciphertext)
+			if inc, err = ct.ReadFrom(r); err != nil {
+				return n + inc, err
+			}
+
+			n += inc
+
+			c.Ciphertexts[name] = ct
+		}
+
+		if inc, err = buffer.ReadUint64(r, &count); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		c.PowerBases = make(map[string]*PowerBasis, count)
+		for i := uint64(0); i < count; i++ {
+
+			var name string
+			if inc, err = readName(r, &name); err != nil {
+				return n + inc, err
+			}
+
+			n += inc
+
+			basis := new(PowerBasis)
+			if inc, err = basis.ReadFrom(r); err != nil {
+				return n + inc, err
+			}
+
+			n += inc
+
+			c.PowerBases[name] = basis
+		}
+
+		return n, nil
+
+	default:
+		return buffer.ReadWithPool(r, c.ReadFrom)
+	}
+}
+
+// MarshalBinary encodes the object into a binary form on a newly allocated slice of bytes.
+func (c Checkpoint) MarshalBinary() (data []byte, err error) {
+	buf := buffer.NewBufferSize(c.BinarySize())
+	_, err = c.WriteTo(buf)
+	return buf.Bytes(), err
+}
+
+// UnmarshalBinary decodes a slice of bytes generated by
+// MarshalBinary or WriteTo on the object.
+func (c *Checkpoint) UnmarshalBinary(data []byte) (err error) {
+	_, err = c.ReadFrom(buffer.NewBuffer(data))
+	return
+}
@@ -0,0 +1,118 @@
+package he_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/he"
+	"github.com/luxdefi/lattice/v5/he/hefloat"
+	"github.com/luxdefi/lattice/v5/utils/bignum"
+	"github.com/luxdefi/lattice/v5/utils/buffer"
+	"github.com/luxdefi/lattice/v5/utils/sampling"
+)
+
+func TestCheckpoint(t *testing.T) {
+
+	t.Run("WriteAndRead", func(t *testing.T) {
+
+		var err error
+		var params rlwe.Parameters
+		if params, err = rlwe.NewParametersFromLiteral(rlwe.ParametersLiteral{
+			LogN: 10,
+			Q:    []uint64{0x200000440001, 0x7fff80001},
+			P:    []uint64{0x3ffffffb80001, 0x4000000800001},
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		levelQ := params.MaxLevelQ()
+
+		prng, _ := sampling.NewPRNG()
+
+		basis := he.NewPowerBasis(rlwe.NewCiphertextRandom(prng, params, 1, levelQ), bignum.Chebyshev)
+		basis.Value[2] = rlwe.NewCiphertextRandom(prng, params, 1, levelQ)
+		basis.Value[4] = rlwe.NewCiphertextRandom(prng, params, 2, levelQ)
+
+		ckpt := he.NewCheckpoint()
+		ckpt.Step = 42
+		ckpt.SetCiphertext("acc", rlwe.NewCiphertextRandom(prng, params, 1, levelQ))
+		ckpt.SetCiphertext("carry", rlwe.NewCiphertextRandom(prng, params, 1, levelQ-1))
+		ckpt.SetPowerBasis("x", &basis)
+
+		buffer.RequireSerializerCorrect(t, ckpt)
+
+		_, err = ckpt.Ciphertext("missing")
+		require.Error(t, err)
+		_, err = ckpt.PowerBasis("missing")
+		require.Error(t, err)
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+
+		// <<<<!Insecure parameters!>>>>
+		params, err := hefloat.NewParametersFromLiteral(hefloat.ParametersLiteral{
+			LogN:            10,
+			LogQ:            []int{55, 45, 45},
+			LogP:            []int{60},
+			LogDefaultScale: 45,
+		})
+		require.NoError(t, err)
+
+		kgen := hefloat.NewKeyGenerator(params)
+		sk := kgen.GenSecretKeyNew()
+		evk := rlwe.NewMemEvaluationKeySet(kgen.GenRelinearizationKeyNew(sk))
+
+		ecd := hefloat.NewEncoder(params)
+		enc := hefloat.NewEncryptor(params, sk)
+		dec := hefloat.NewDecryptor(params, sk)
+
+		values := make([]float64, params.MaxSlots())
+		for i := range values {
+			values[i] = sampling.RandFloat64(-1, 1)
+		}
+
+		pt := hefloat.NewPlaintext(params, params.MaxLevel())
+		require.NoError(t, ecd.Encode(values, pt))
+
+		ct, err := enc.EncryptNew(pt)
+		require.NoError(t, err)
+
+		// First half of the job: generates X^2 and checkpoints.
+		basis := he.NewPowerBasis(ct, bignum.Monomial)
+		require.NoError(t, basis.GenPower(2, false, hefloat.NewEvaluator(params, evk)))
+
+		ckpt := he.NewCheckpoint()
+		ckpt.Step = 1
+		ckpt.SetPowerBasis("x", &basis)
+
+		data, err := ckpt.MarshalBinary()
+		require.NoError(t, err)
+
+		// Second half, after a restart: restores the checkpoint and extends
+		// the power basis to X^4 with a fresh evaluator, reusing the
+		// checkpointed X^2.
+		loaded := he.NewCheckpoint()
+		require.NoError(t, loaded.UnmarshalBinary(data))
+		require.Equal(t, uint64(1), loaded.Step)
+
+		restored, err := loaded.PowerBasis("x")
+		require.NoError(t, err)
+
+		have := make([]float64, params.MaxSlots())
+		require.NoError(t, ecd.Decode(dec.DecryptNew(restored.Value[2]), have))
+		for i := range values {
+			require.InDelta(t, values[i]*values[i], have[i], 1e-4)
+		}
+
+		require.NoError(t, restored.GenPower(4, false, hefloat.NewEvaluator(params, evk)))
+
+		require.NoError(t, ecd.Decode(dec.DecryptNew(restored.Value[4]), have))
+
+		for i := range values {
+			want := values[i] * values[i] * values[i] * values[i]
+			require.InDelta(t, want, have[i], 1e-4)
+		}
+	})
+}
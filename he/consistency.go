@@ -0,0 +1,107 @@
+package he
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+)
+
+// Tolerance specifies by how much a decrypted value may deviate from its
+// reference before being reported as inconsistent. The zero value is the
+// policy of the exact schemes (BGV and BFV), for which decryption must
+// reproduce the reference bit for bit; approximate schemes (CKKS) set
+// Epsilon and/or Relative to their expected precision.
+type Tolerance struct {
+	// Epsilon is the maximum accepted absolute error.
+	Epsilon float64
+	// Relative is the maximum accepted relative error, checked when the
+	// absolute error exceeds Epsilon and the reference value is non-zero.
+	Relative float64
+}
+
+// accepts reports whether a deviation of diff from the reference value want
+// falls within the tolerance.
+func (tol Tolerance) accepts(want, diff float64) bool {
+	if diff <= tol.Epsilon {
+		return true
+	}
+	return want != 0 && diff/math.Abs(want) <= tol.Relative
+}
+
+// ConsistencyChecker compares encrypted results against plaintext references
+// under a per-scheme tolerance policy. It is a testing utility: it decrypts
+// with the secret key and reports the first slot whose decrypted value
+// deviates from the reference by more than the tolerance, unifying the
+// verification code of the scheme integration tests.
+type ConsistencyChecker struct {
+	ecd SlotEncoder
+	dec *rlwe.Decryptor
+	tol Tolerance
+}
+
+// NewConsistencyChecker instantiates a ConsistencyChecker decrypting with sk
+// and decoding with ecd. See the heint and hefloat front-ends for
+// constructors applying the tolerance policy of their scheme.
+func NewConsistencyChecker(params rlwe.ParameterProvider, sk *rlwe.SecretKey, ecd SlotEncoder, tol Tolerance) *ConsistencyChecker {
+	return &ConsistencyChecker{ecd: ecd, dec: rlwe.NewDecryptor(params, sk), tol: tol}
+}
+
+// Check decrypts ct and compares its first len(want) plaintext slots against
+// the reference want, which must be a []uint64, []int64, []float64 or
+// []complex128 of the scheme's plaintext element type. It returns nil if
+// every slot is within the tolerance, and an error identifying the first
+// inconsistent slot otherwise. Integer references are always compared
+// exactly.
+func (c ConsistencyChecker) Check(want interface{}, ct *rlwe.Ciphertext) (err error) {
+
+	pt := c.dec.DecryptNew(ct)
+
+	switch want := want.(type) {
+	case []uint64:
+		have := make([]uint64, len(want))
+		if err = c.ecd.DecodeSlots(pt, have); err != nil {
+			return fmt.Errorf("cannot Check: %w", err)
+		}
+		for i := range want {
+			if have[i] != want[i] {
+				return fmt.Errorf("cannot Check: slot %d: have %d but want %d", i, have[i], want[i])
+			}
+		}
+	case []int64:
+		have := make([]int64, len(want))
+		if err = c.ecd.DecodeSlots(pt, have); err != nil {
+			return fmt.Errorf("cannot Check: %w", err)
+		}
+		for i := range want {
+			if have[i] != want[i] {
+				return fmt.Errorf("cannot Check: slot %d: have %d but want %d", i, have[i], want[i])
+			}
+		}
+	case []float64:
+		have := make([]float64, len(want))
+		if err = c.ecd.DecodeSlots(pt, have); err != nil {
+			return fmt.Errorf("cannot Check: %w", err)
+		}
+		for i := range want {
+			if !c.tol.accepts(want[i], math.Abs(have[i]-want[i])) {
+				return fmt.Errorf("cannot Check: slot %d: have %v but want %v (absolute error %.5e exceeds the tolerance)", i, have[i], want[i], math.Abs(have[i]-want[i]))
+			}
+		}
+	case []complex128:
+		have := make([]complex128, len(want))
+		if err = c.ecd.DecodeSlots(pt, have); err != nil {
+			return fmt.Errorf("cannot Check: %w", err)
+		}
+		for i := range want {
+			if !c.tol.accepts(cmplx.Abs(want[i]), cmplx.Abs(have[i]-want[i])) {
+				return fmt.Errorf("cannot Check: slot %d: have %v but want %v (absolute error %.5e exceeds the tolerance)", i, have[i], want[i], cmplx.Abs(have[i]-want[i]))
+			}
+		}
+	default:
+		return fmt.Errorf("cannot Check: want must be []uint64, []int64, []float64 or []complex128 but is %T", want)
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package he_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxdefi/lattice/v5/he/hefloat"
+	"github.com/luxdefi/lattice/v5/he/heint"
+)
+
+func TestConsistencyChecker(t *testing.T) {
+
+	t.Run("hefloat", func(t *testing.T) {
+
+		// <<<<!Insecure parameters!>>>>
+		params, err := hefloat.NewParametersFromLiteral(hefloat.ParametersLiteral{
+			LogN:            10,
+			LogQ:            []int{55, 45, 45},
+			LogP:            []int{60},
+			LogDefaultScale: 45,
+		})
+		require.NoError(t, err)
+
+		kgen := hefloat.NewKeyGenerator(params)
+		sk := kgen.GenSecretKeyNew()
+
+		ecd := hefloat.NewEncoder(params)
+		enc := hefloat.NewEncryptor(params, sk)
+
+		values := make([]float64, params.MaxSlots())
+		for i := range values {
+			values[i] = float64(i) / float64(len(values))
+		}
+
+		pt := hefloat.NewPlaintext(params, params.MaxLevel())
+		require.NoError(t, ecd.Encode(values, pt))
+		ct, err := enc.EncryptNew(pt)
+		require.NoError(t, err)
+
+		checker := hefloat.NewConsistencyChecker(params, sk, 1e-6, 0)
+		require.NoError(t, checker.Check(values, ct))
+
+		// A reference deviating by more than the absolute tolerance fails,
+		// unless covered by the relative tolerance.
+		wrong := make([]float64, len(values))
+		copy(wrong, values)
+		wrong[3] *= 1 + 1e-3
+		require.Error(t, checker.Check(wrong, ct))
+		require.NoError(t, hefloat.NewConsistencyChecker(params, sk, 1e-6, 1e-2).Check(wrong, ct))
+
+		// An unsupported reference type is reported.
+		require.Error(t, checker.Check("values", ct))
+	})
+
+	t.Run("heint", func(t *testing.T) {
+
+		// <<<<!Insecure parameters!>>>>
+		params, err := heint.NewParametersFromLiteral(heint.ParametersLiteral{
+			LogN:             10,
+			LogQ:             []int{55, 45},
+			LogP:             []int{60},
+			PlaintextModulus: 0x101,
+		})
+		require.NoError(t, err)
+
+		kgen := heint.NewKeyGenerator(params)
+		sk := kgen.GenSecretKeyNew()
+
+		ecd := heint.NewEncoder(params)
+		enc := heint.NewEncryptor(params, sk)
+
+		T := params.PlaintextModulus()
+		values := make([]uint64, params.MaxSlots())
+		for i := range values {
+			values[i] = uint64(i) % T
+		}
+
+		pt := heint.NewPlaintext(params, params.MaxLevel())
+		require.NoError(t, ecd.Encode(values, pt))
+		ct, err := enc.EncryptNew(pt)
+		require.NoError(t, err)
+
+		checker := heint.NewConsistencyChecker(params, sk)
+		require.NoError(t, checker.Check(values, ct))
+
+		// The policy of the exact schemes tolerates no deviation.
+		wrong := make([]uint64, len(values))
+		copy(wrong, values)
+		wrong[3]++
+		err = checker.Check(wrong, ct)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "slot 3")
+	})
+}
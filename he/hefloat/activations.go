@@ -0,0 +1,193 @@
+package hefloat
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/luxdefi/lattice/v5/utils/bignum"
+)
+
+// ActivationKind identifies one of the curated machine-learning activation
+// functions for which pre-fit polynomial approximations are provided.
+type ActivationKind int
+
+const (
+	// ReLU is max(0, x), approximated as x * (0.5 + 0.5 * poly-sign(x)).
+	ReLU ActivationKind = iota
+	// GELU is x * Phi(x), with Phi the standard Gaussian CDF (tanh formulation).
+	GELU
+	// Sigmoid is 1 / (1 + exp(-x)).
+	Sigmoid
+	// Tanh is the hyperbolic tangent.
+	Tanh
+	// SoftmaxExp is exp(x), the per-slot kernel of the softmax. A full softmax
+	// is assembled by first subtracting an approximate maximum (see the
+	// MinimaxCompositePolynomial-based sign/max circuits in comparisons.go),
+	// evaluating SoftmaxExp and normalizing with GoldschmidtDivisionNew.
+	SoftmaxExp
+)
+
+// String returns the name of the activation.
+func (kind ActivationKind) String() string {
+	switch kind {
+	case ReLU:
+		return "ReLU"
+	case GELU:
+		return "GELU"
+	case Sigmoid:
+		return "Sigmoid"
+	case Tanh:
+		return "Tanh"
+	case SoftmaxExp:
+		return "SoftmaxExp"
+	default:
+		return "Unknown"
+	}
+}
+
+// Func returns the exact activation function over big.Float, which the
+// polynomial approximations returned by NewActivation interpolate.
+func (kind ActivationKind) Func(prec uint) (func(x *big.Float) (y *big.Float), error) {
+
+	half := bignum.NewFloat(0.5, prec)
+	one := bignum.NewFloat(1.0, prec)
+
+	switch kind {
+	case ReLU:
+		return func(x *big.Float) (y *big.Float) {
+			if x.Cmp(new(big.Float)) < 0 {
+				return bignum.NewFloat(0, prec)
+			}
+			return new(big.Float).Set(x)
+		}, nil
+	case GELU:
+		// 0.5 * x * (1 + erf(x/sqrt(2))), with erf evaluated through the
+		// tanh formulation: erf(x/sqrt(2)) ~ tanh(sqrt(2/pi)*(x + 0.044715*x^3)).
+		c0 := bignum.NewFloat(0.7978845608028654, prec) // sqrt(2/pi)
+		c1 := bignum.NewFloat(0.044715, prec)
+		return func(x *big.Float) (y *big.Float) {
+			y = new(big.Float).Mul(x, x)
+			y.Mul(y, x)
+			y.Mul(y, c1)
+			y.Add(y, x)
+			y.Mul(y, c0)
+			y = bignum.TanH(y)
+			y.Add(y, one)
+			y.Mul(y, x)
+			y.Mul(y, half)
+			return
+		}, nil
+	case Sigmoid:
+		return func(x *big.Float) (y *big.Float) {
+			y = bignum.Exp(new(big.Float).Neg(x))
+			y.Add(y, one)
+			y.Quo(one, y)
+			return
+		}, nil
+	case Tanh:
+		return func(x *big.Float) (y *big.Float) {
+			return bignum.TanH(x)
+		}, nil
+	case SoftmaxExp:
+		return func(x *big.Float) (y *big.Float) {
+			return bignum.Exp(x)
+		}, nil
+	default:
+		return nil, fmt.Errorf("invalid ActivationKind: %d", kind)
+	}
+}
+
+// Activation is a pre-fit polynomial approximation of an activation function
+// over a given interval, directly consumable by the PolynomialEvaluator.
+type Activation struct {
+	Kind     ActivationKind
+	Interval bignum.Interval
+	poly     bignum.Polynomial
+}
+
+// NewActivation fits a Chebyshev approximation of degree degree of the given
+// activation over [a, b]. The smoother the activation (Sigmoid, Tanh, GELU,
+// SoftmaxExp), the lower the degree required for a given precision; ReLU has a
+// kink in zero and converges slowly, so for high-precision ReLU over wide
+// intervals the minimax sign circuits of comparisons.go should be preferred.
+func NewActivation(kind ActivationKind, a, b float64, degree int) (act Activation, err error) {
+
+	if b <= a {
+		return Activation{}, fmt.Errorf("cannot NewActivation: invalid interval [%f, %f]", a, b)
+	}
+
+	if degree < 1 {
+		return Activation{}, fmt.Errorf("cannot NewActivation: degree must be at least 1 but is %d", degree)
+	}
+
+	const prec = 128
+
+	f, err := kind.Func(prec)
+	if err != nil {
+		return Activation{}, fmt.Errorf("cannot NewActivation: %w", err)
+	}
+
+	interval := bignum.Interval{
+		Nodes: degree,
+		A:     *bignum.NewFloat(a, prec),
+		B:     *bignum.NewFloat(b, prec),
+	}
+
+	return Activation{
+		Kind:     kind,
+		Interval: interval,
+		poly:     bignum.ChebyshevApproximation(f, interval),
+	}, nil
+}
+
+// Polynomial returns the approximation as a Polynomial consumable by
+// PolynomialEvaluator.Evaluate. Inputs must lie in the fitting interval.
+func (act Activation) Polynomial() Polynomial {
+	return NewPolynomial(act.poly)
+}
+
+// Depth returns the multiplicative depth required to evaluate the activation.
+func (act Activation) Depth() int {
+	return act.poly.Depth()
+}
+
+// MaxError returns an estimate of the maximum absolute approximation error
+// over the fitting interval, obtained by sampling the difference between the
+// activation and its polynomial approximation on samples equidistant points.
+// The returned value is the precision metadata to check against the target
+// precision of the circuit.
+func (act Activation) MaxError(samples int) (maxErr *big.Float, err error) {
+
+	if samples < 2 {
+		return nil, fmt.Errorf("cannot MaxError: samples must be at least 2 but is %d", samples)
+	}
+
+	prec := act.Interval.A.Prec()
+
+	f, err := act.Kind.Func(prec)
+	if err != nil {
+		return nil, fmt.Errorf("cannot MaxError: %w", err)
+	}
+
+	step := new(big.Float).Sub(&act.Interval.B, &act.Interval.A)
+	step.Quo(step, bignum.NewFloat(samples-1, prec))
+
+	x := new(big.Float).Set(&act.Interval.A)
+
+	maxErr = new(big.Float)
+
+	for i := 0; i < samples; i++ {
+
+		diff := act.poly.Evaluate(x)[0]
+		diff.Sub(diff, f(x))
+		diff.Abs(diff)
+
+		if diff.Cmp(maxErr) > 0 {
+			maxErr.Set(diff)
+		}
+
+		x.Add(x, step)
+	}
+
+	return
+}
@@ -0,0 +1,46 @@
+package hefloat
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestActivations(t *testing.T) {
+
+	for _, tc := range []struct {
+		kind    ActivationKind
+		a, b    float64
+		degree  int
+		log2Err float64
+	}{
+		{Sigmoid, -8, 8, 31, 8},
+		{Tanh, -4, 4, 31, 8},
+		{GELU, -4, 4, 31, 8},
+		{SoftmaxExp, -8, 0, 31, 16},
+		{ReLU, -1, 1, 63, 4},
+	} {
+		t.Run(tc.kind.String(), func(t *testing.T) {
+
+			act, err := NewActivation(tc.kind, tc.a, tc.b, tc.degree)
+			require.NoError(t, err)
+
+			require.Equal(t, tc.degree, act.Polynomial().Degree())
+			require.Greater(t, act.Depth(), 0)
+
+			maxErr, err := act.MaxError(512)
+			require.NoError(t, err)
+
+			bound := new(big.Float).SetMantExp(big.NewFloat(1), -int(tc.log2Err))
+			require.True(t, maxErr.Cmp(bound) <= 0, "max error %v above 2^-%v", maxErr, tc.log2Err)
+		})
+	}
+
+	_, err := NewActivation(ReLU, 1, -1, 15)
+	require.Error(t, err)
+	_, err = NewActivation(ReLU, -1, 1, 0)
+	require.Error(t, err)
+	_, err = NewActivation(ActivationKind(99), -1, 1, 15)
+	require.Error(t, err)
+}
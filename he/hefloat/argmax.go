@@ -0,0 +1,124 @@
+package hefloat
+
+import (
+	"fmt"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+)
+
+// ArgmaxEvaluator is an evaluator providing an API for the homomorphic argmax
+// and argmin of n values packed in the slots of a ciphertext, returning an
+// encrypted one-hot indicator of the extremum: 1 in its slot and 0 in the
+// others.
+//
+// The circuit reduces the n values to their maximum with a rotation-based
+// tournament (log2(n) rounds of pairwise smooth maxima between the running
+// ciphertext and its rotation), then maps the input to the indicator by
+// comparing each slot against the reduced maximum with the sign
+// approximation: the comparison evaluates to 1/2 at the maximum and to 0
+// elsewhere, and is doubled to produce the one-hot.
+//
+// Use must ensure that:
+//   - n is a power of two dividing params.MaxSlots(); if n < params.MaxSlots(),
+//     the n values must be replicated in every block of n slots, so that the
+//     rotations of the tournament wrap around within each block.
+//   - The values are in the interval [-1/2, 1/2], so that their pairwise sums
+//     and differences remain in the interval [-1, 1] of the sign approximation.
+//   - The extremum is unique and separated from the runner-up by more than
+//     the precision of the sign approximation, otherwise the tied slots share
+//     the indicator.
+//
+// All fields of this struct are public, enabling custom instantiations.
+type ArgmaxEvaluator struct {
+	*Evaluator
+	// Comparisons evaluates the pairwise extrema of the tournament and the
+	// final indicator.
+	Comparisons *ComparisonEvaluator
+}
+
+// NewArgmaxEvaluator instantiates a new ArgmaxEvaluator.
+func NewArgmaxEvaluator(eval *Evaluator, cmp *ComparisonEvaluator) *ArgmaxEvaluator {
+	return &ArgmaxEvaluator{Evaluator: eval, Comparisons: cmp}
+}
+
+// GaloisElementsForArgmax returns the list of Galois elements required to
+// evaluate the argmax or argmin of n values, i.e. those of the power-of-two
+// rotations of the tournament.
+func GaloisElementsForArgmax(params Parameters, n int) []uint64 {
+	var rots []int
+	for shift := n >> 1; shift > 0; shift >>= 1 {
+		rots = append(rots, shift)
+	}
+	return params.GaloisElements(rots)
+}
+
+// Argmax returns an encrypted one-hot indicator of the maximum of the n
+// values packed in the slots of op0: 1 in the slot of the maximum and 0 in
+// the others (see ArgmaxEvaluator for the constraints on op0 and n).
+func (eval ArgmaxEvaluator) Argmax(op0 *rlwe.Ciphertext, n int) (onehot *rlwe.Ciphertext, err error) {
+	if onehot, err = eval.tournament(op0, n, true); err != nil {
+		return nil, fmt.Errorf("cannot Argmax: %w", err)
+	}
+	return
+}
+
+// Argmin returns an encrypted one-hot indicator of the minimum of the n
+// values packed in the slots of op0: 1 in the slot of the minimum and 0 in
+// the others (see ArgmaxEvaluator for the constraints on op0 and n).
+func (eval ArgmaxEvaluator) Argmin(op0 *rlwe.Ciphertext, n int) (onehot *rlwe.Ciphertext, err error) {
+	if onehot, err = eval.tournament(op0, n, false); err != nil {
+		return nil, fmt.Errorf("cannot Argmin: %w", err)
+	}
+	return
+}
+
+// tournament reduces the n values of op0 to their extremum with log2(n)
+// rounds of rotations and pairwise smooth extrema, and maps the input to the
+// one-hot indicator of the extremum.
+func (eval ArgmaxEvaluator) tournament(op0 *rlwe.Ciphertext, n int, max bool) (onehot *rlwe.Ciphertext, err error) {
+
+	params := eval.GetParameters()
+
+	if n < 2 || n&(n-1) != 0 || params.MaxSlots()%n != 0 {
+		return nil, fmt.Errorf("n must be a power of two dividing params.MaxSlots() but is %d", n)
+	}
+
+	ext := op0.CopyNew()
+
+	for shift := n >> 1; shift > 0; shift >>= 1 {
+
+		var rot *rlwe.Ciphertext
+		if rot, err = eval.RotateNew(ext, shift); err != nil {
+			return nil, err
+		}
+
+		if max {
+			ext, err = eval.Comparisons.Max(ext, rot)
+		} else {
+			ext, err = eval.Comparisons.Min(ext, rot)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The mask evaluates to 1/2 at the extremum (where op0 equals the reduced
+	// extremum) and to 0 elsewhere.
+	if max {
+		onehot, err = eval.Comparisons.MaskGreaterThan(op0, ext)
+	} else {
+		onehot, err = eval.Comparisons.MaskLessThan(op0, ext)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Doubles the mask to produce the one-hot indicator.
+	if err = eval.Add(onehot, onehot, onehot); err != nil {
+		return nil, err
+	}
+
+	return
+}
@@ -0,0 +1,95 @@
+package hefloat_test
+
+import (
+	"testing"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/he/hefloat"
+	"github.com/luxdefi/lattice/v5/he/hefloat/bootstrapping"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestArgmax(t *testing.T) {
+
+	params, err := hefloat.NewParametersFromLiteral(testInsecurePrec90)
+	require.NoError(t, err)
+
+	var tc *testContext
+	if tc, err = genTestParams(params); err != nil {
+		t.Fatal(err)
+	}
+
+	enc := tc.encryptorSk
+	sk := tc.sk
+	ecd := tc.encoder
+	dec := tc.decryptor
+	kgen := tc.kgen
+
+	n := 8
+	slots := params.MaxSlots()
+
+	btp := bootstrapping.NewSecretKeyBootstrapper(params, sk)
+
+	galEls := append(
+		hefloat.GaloisElementsForArgmax(params, n),
+		params.GaloisElementForComplexConjugation(),
+	)
+
+	evk := rlwe.NewMemEvaluationKeySet(kgen.GenRelinearizationKeyNew(sk), kgen.GenGaloisKeysNew(galEls, sk)...)
+
+	eval := tc.evaluator.WithKey(evk)
+
+	argmax := hefloat.NewArgmaxEvaluator(eval, hefloat.NewComparisonEvaluator(params, eval, btp))
+
+	// n distinct values in [-1/2, 1/2], replicated in every block of n slots.
+	block := []float64{-0.25, 0.1875, 0.4375, -0.4375, 0.0625, 0.375, -0.125, 0.3125}
+	idxMax, idxMin := 2, 3
+
+	values := make([]float64, slots)
+	for i := range values {
+		values[i] = block[i%n]
+	}
+
+	pt := hefloat.NewPlaintext(params, params.MaxLevel())
+	require.NoError(t, ecd.Encode(values, pt))
+	ct, err := enc.EncryptNew(pt)
+	require.NoError(t, err)
+
+	_, err = argmax.Argmax(ct, 3)
+	require.Error(t, err)
+
+	t.Run(GetTestName(params, "Argmax"), func(t *testing.T) {
+
+		onehot, err := argmax.Argmax(ct, n)
+		require.NoError(t, err)
+
+		have := make([]float64, slots)
+		require.NoError(t, ecd.Decode(dec.DecryptNew(onehot), have))
+
+		for i := range have {
+			want := 0.0
+			if i%n == idxMax {
+				want = 1
+			}
+			require.InDelta(t, want, have[i], 1e-3)
+		}
+	})
+
+	t.Run(GetTestName(params, "Argmin"), func(t *testing.T) {
+
+		onehot, err := argmax.Argmin(ct, n)
+		require.NoError(t, err)
+
+		have := make([]float64, slots)
+		require.NoError(t, ecd.Decode(dec.DecryptNew(onehot), have))
+
+		for i := range have {
+			want := 0.0
+			if i%n == idxMin {
+				want = 1
+			}
+			require.InDelta(t, want, have[i], 1e-3)
+		}
+	})
+}
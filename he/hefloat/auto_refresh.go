@@ -0,0 +1,236 @@
+package hefloat
+
+import (
+	"fmt"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/he"
+	"github.com/luxdefi/lattice/v5/utils"
+)
+
+// RefreshPoint records a bootstrapping automatically inserted by an
+// AutoRefreshEvaluator. Collecting the refresh points of a pilot run
+// (e.g. over a SecretKeyBootstrapper) gives a plan of the refresh
+// locations of a pipeline, which can then be hard-coded in production.
+type RefreshPoint struct {
+	// Op is the name of the operation that triggered the refresh.
+	Op string
+	// Index is the zero-based index of the triggering operation in the
+	// sequence of operations evaluated since the last Reset.
+	Index int
+	// Level is the level of the refreshed operand.
+	Level int
+	// LogPrec is the certified precision lower bound, in bits, of the
+	// refreshed operand (see PrecisionLowerBound), or zero if the
+	// operand was not tracked.
+	LogPrec float64
+}
+
+// AutoRefreshEvaluator wraps a PrecisionTracker and a Bootstrapper and
+// inserts bootstrapping automatically, relieving the caller from
+// hard-coding refresh points: before each operation consuming levels,
+// operands whose level budget would be exhausted by the operation (i.e.
+// that could no longer be both rescaled and handed to the Bootstrapper
+// afterwards) are refreshed. Each inserted refresh is recorded as a
+// RefreshPoint (see Plan).
+//
+// The operands given to the wrapped operations are left untouched: a
+// refreshed operand is bootstrapped on a copy. The Bootstrapper's
+// OutputLevel must cover the level consumption of the deepest single
+// operation of the pipeline, else that operation will still fail.
+//
+// All fields of this struct are public, enabling custom instantiations.
+type AutoRefreshEvaluator struct {
+	*PrecisionTracker
+	// Bootstrapper refreshes the operands whose level budget is
+	// exhausted.
+	Bootstrapper he.Bootstrapper[rlwe.Ciphertext]
+	// RefreshErrorBound is the worst-case additional slot error of one
+	// invocation of the Bootstrapper, added to the tracked error bound
+	// at each refresh. The zero default leaves the bound unchanged,
+	// which is only valid for (near) exact bootstrappers.
+	RefreshErrorBound float64
+	// MinPrecision is an optional floor, in bits, on the certified
+	// precision of refreshed operands: since bootstrapping restores
+	// levels but not precision, refreshing an operand whose precision
+	// lower bound is below the floor returns an error instead of
+	// silently continuing. The zero default disables the check.
+	MinPrecision float64
+
+	index int
+	plan  []RefreshPoint
+}
+
+// NewAutoRefreshEvaluator instantiates a new AutoRefreshEvaluator
+// wrapping the given PrecisionTracker and Bootstrapper.
+func NewAutoRefreshEvaluator(tracker *PrecisionTracker, btp he.Bootstrapper[rlwe.Ciphertext]) *AutoRefreshEvaluator {
+	return &AutoRefreshEvaluator{PrecisionTracker: tracker, Bootstrapper: btp}
+}
+
+// Plan returns the refresh points inserted since the last Reset, in
+// evaluation order.
+func (eval *AutoRefreshEvaluator) Plan() []RefreshPoint {
+	plan := make([]RefreshPoint, len(eval.plan))
+	copy(plan, eval.plan)
+	return plan
+}
+
+// Reset clears the recorded refresh points and the operation counter.
+func (eval *AutoRefreshEvaluator) Reset() {
+	eval.index = 0
+	eval.plan = nil
+}
+
+// Add adds op1 to op0 and returns the result in opOut (see
+// PrecisionTracker.Add). Additions consume no levels, so no refresh is
+// inserted.
+func (eval *AutoRefreshEvaluator) Add(op0 *rlwe.Ciphertext, op1 rlwe.Operand, opOut *rlwe.Ciphertext) (err error) {
+	defer func() { eval.index++ }()
+	return eval.PrecisionTracker.Add(op0, op1, opOut)
+}
+
+// Sub subtracts op1 from op0 and returns the result in opOut (see
+// PrecisionTracker.Sub). Subtractions consume no levels, so no refresh
+// is inserted.
+func (eval *AutoRefreshEvaluator) Sub(op0 *rlwe.Ciphertext, op1 rlwe.Operand, opOut *rlwe.Ciphertext) (err error) {
+	defer func() { eval.index++ }()
+	return eval.PrecisionTracker.Sub(op0, op1, opOut)
+}
+
+// Mul multiplies op0 with op1 without relinearization and returns the
+// result in opOut (see PrecisionTracker.Mul), refreshing the ciphertext
+// operands beforehand if the product could no longer be rescaled and
+// bootstrapped.
+func (eval *AutoRefreshEvaluator) Mul(op0 *rlwe.Ciphertext, op1 rlwe.Operand, opOut *rlwe.Ciphertext) (err error) {
+	defer func() { eval.index++ }()
+	if op0, op1, err = eval.refreshOperands("Mul", op0, op1, opOut); err != nil {
+		return fmt.Errorf("cannot Mul: %w", err)
+	}
+	return eval.PrecisionTracker.Mul(op0, op1, opOut)
+}
+
+// MulRelin multiplies op0 with op1 with relinearization and returns the
+// result in opOut (see PrecisionTracker.MulRelin), refreshing the
+// ciphertext operands beforehand if the product could no longer be
+// rescaled and bootstrapped.
+func (eval *AutoRefreshEvaluator) MulRelin(op0 *rlwe.Ciphertext, op1 rlwe.Operand, opOut *rlwe.Ciphertext) (err error) {
+	defer func() { eval.index++ }()
+	if op0, op1, err = eval.refreshOperands("MulRelin", op0, op1, opOut); err != nil {
+		return fmt.Errorf("cannot MulRelin: %w", err)
+	}
+	return eval.PrecisionTracker.MulRelin(op0, op1, opOut)
+}
+
+// Rescale divides op0 by the last modulus and returns the result in
+// opOut (see PrecisionTracker.Rescale), refreshing op0 beforehand if
+// the rescaling would fail or leave it below the Bootstrapper's minimum
+// input level.
+func (eval *AutoRefreshEvaluator) Rescale(op0, opOut *rlwe.Ciphertext) (err error) {
+	defer func() { eval.index++ }()
+	if op0, err = eval.maybeRefresh("Rescale", op0, 1); err != nil {
+		return fmt.Errorf("cannot Rescale: %w", err)
+	}
+	return eval.PrecisionTracker.Rescale(op0, opOut)
+}
+
+// Polynomial evaluates the polynomial p on ct and returns the result in
+// a new ciphertext (see PrecisionTracker.Polynomial), refreshing ct
+// beforehand if the depth of the evaluation would exhaust its level
+// budget.
+func (eval *AutoRefreshEvaluator) Polynomial(ct *rlwe.Ciphertext, p interface{}, targetScale rlwe.Scale) (opOut *rlwe.Ciphertext, err error) {
+	defer func() { eval.index++ }()
+
+	_, depth, err := polynomialList(p)
+	if err != nil {
+		return nil, fmt.Errorf("cannot Polynomial: %w", err)
+	}
+
+	// One extra rescaling for the adjustment to the target scale.
+	if ct, err = eval.maybeRefresh("Polynomial", ct, depth+1); err != nil {
+		return nil, fmt.Errorf("cannot Polynomial: %w", err)
+	}
+
+	return eval.PrecisionTracker.Polynomial(ct, p, targetScale)
+}
+
+// refreshOperands refreshes the ciphertext operands of a multiplication
+// whose level budget would be exhausted by the product and its
+// rescaling. If a refresh occurred, opOut (which at that point can only
+// alias the original, untouched operands) is resized to the level of
+// the refreshed inputs, since the multiplication truncates its result
+// to the level of the output.
+func (eval *AutoRefreshEvaluator) refreshOperands(op string, op0 *rlwe.Ciphertext, op1 rlwe.Operand, opOut *rlwe.Ciphertext) (*rlwe.Ciphertext, rlwe.Operand, error) {
+
+	orig := op0
+
+	op0, err := eval.maybeRefresh(op, op0, 1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refreshed := op0 != orig
+
+	if ct, ok := op1.(*rlwe.Ciphertext); ok {
+		if ct == orig {
+			// Aliased operands (e.g. squarings) are refreshed once.
+			op1 = ct
+			if refreshed {
+				op1 = op0
+			}
+		} else {
+			ct0 := ct
+			if ct, err = eval.maybeRefresh(op, ct, 1); err != nil {
+				return nil, nil, err
+			}
+			refreshed = refreshed || ct != ct0
+			op1 = ct
+		}
+	}
+
+	if refreshed {
+		level := op0.Level()
+		if ct, ok := op1.(*rlwe.Ciphertext); ok {
+			level = utils.Min(level, ct.Level())
+		}
+		if opOut.Level() < level {
+			opOut.Resize(opOut.Degree(), level)
+		}
+	}
+
+	return op0, op1, nil
+}
+
+// maybeRefresh bootstraps a copy of ct if consuming depth more
+// rescalings would leave it below the Bootstrapper's minimum input
+// level, i.e. exactly when the remaining level budget no longer covers
+// the operation. The original ciphertext is left untouched and the
+// refresh is recorded in the plan.
+func (eval *AutoRefreshEvaluator) maybeRefresh(op string, ct *rlwe.Ciphertext, depth int) (*rlwe.Ciphertext, error) {
+
+	if ct.Level() >= eval.Bootstrapper.MinimumInputLevel()+depth*eval.GetParameters().LevelsConsumedPerRescaling() {
+		return ct, nil
+	}
+
+	logprec, tracked := PrecisionLowerBound(ct.MetaData)
+
+	if tracked && eval.MinPrecision > 0 && logprec < eval.MinPrecision {
+		return nil, fmt.Errorf("refresh before %s: certified precision %.2f bits is below the floor of %.2f bits", op, logprec, eval.MinPrecision)
+	}
+
+	eval.plan = append(eval.plan, RefreshPoint{Op: op, Index: eval.index, Level: ct.Level(), LogPrec: logprec})
+
+	e, m, ok := bounds(ct.MetaData)
+
+	ct, err := eval.Bootstrapper.Bootstrap(ct.CopyNew())
+	if err != nil {
+		return nil, fmt.Errorf("refresh before %s: %w", op, err)
+	}
+
+	if ok {
+		if err = eval.setBounds(ct, e+eval.RefreshErrorBound, m, true); err != nil {
+			return nil, fmt.Errorf("refresh before %s: %w", op, err)
+		}
+	}
+
+	return ct, nil
+}
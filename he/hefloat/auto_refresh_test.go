@@ -0,0 +1,143 @@
+package hefloat_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/he/hefloat"
+	"github.com/luxdefi/lattice/v5/he/hefloat/bootstrapping"
+	"github.com/luxdefi/lattice/v5/utils/bignum"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAutoRefresh(t *testing.T) {
+
+	params, err := hefloat.NewParametersFromLiteral(testInsecurePrec45)
+	require.NoError(t, err)
+
+	kgen := rlwe.NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+	evk := rlwe.NewMemEvaluationKeySet(kgen.GenRelinearizationKeyNew(sk))
+
+	ecd := hefloat.NewEncoder(params)
+	enc := rlwe.NewEncryptor(params, sk)
+	dec := rlwe.NewDecryptor(params, sk)
+
+	tracker := hefloat.NewPrecisionTracker(hefloat.NewEvaluator(params, evk))
+
+	values := make([]float64, params.MaxSlots())
+	for i := range values {
+		values[i] = 0.99
+	}
+
+	newTrackedCiphertext := func(btp *bootstrapping.SecretKeyBootstrapper) (eval *hefloat.AutoRefreshEvaluator, ct *rlwe.Ciphertext) {
+		pt := hefloat.NewPlaintext(params, params.MaxLevel())
+		require.NoError(t, ecd.Encode(values, pt))
+		ct, err := enc.EncryptNew(pt)
+		require.NoError(t, err)
+		require.NoError(t, tracker.Track(ct, 1))
+		return hefloat.NewAutoRefreshEvaluator(tracker, btp), ct
+	}
+
+	t.Run("SquaringChain", func(t *testing.T) {
+
+		btp := bootstrapping.NewSecretKeyBootstrapper(params, sk)
+		eval, ct := newTrackedCiphertext(btp)
+
+		// Squares the ciphertext more times than the parameters have
+		// levels, so the chain cannot complete without refreshes.
+		squarings := params.MaxLevel() + 3
+		want := values[0]
+		for i := 0; i < squarings; i++ {
+			require.NoError(t, eval.MulRelin(ct, ct, ct))
+			require.NoError(t, eval.Rescale(ct, ct))
+			want *= want
+		}
+
+		require.Greater(t, btp.Counter, 0)
+
+		have := make([]float64, params.MaxSlots())
+		require.NoError(t, ecd.Decode(dec.DecryptNew(ct), have))
+		for i := range have {
+			require.InDelta(t, want, have[i], 1e-6)
+		}
+
+		// The bounds survived the refreshes and still certify the result.
+		bound, ok := hefloat.ErrorBound(ct.MetaData)
+		require.True(t, ok)
+		require.GreaterOrEqual(t, bound, math.Abs(want-have[0]))
+
+		// The plan locates each refresh in the operation sequence.
+		plan := eval.Plan()
+		require.Equal(t, btp.Counter, len(plan))
+		for i, pt := range plan {
+			require.Equal(t, "MulRelin", pt.Op)
+			require.Less(t, pt.Level, params.MaxLevel())
+			require.Greater(t, pt.LogPrec, 0.0)
+			if i > 0 {
+				require.Greater(t, pt.Index, plan[i-1].Index)
+			}
+		}
+
+		eval.Reset()
+		require.Empty(t, eval.Plan())
+	})
+
+	t.Run("Polynomial", func(t *testing.T) {
+
+		btp := bootstrapping.NewSecretKeyBootstrapper(params, sk)
+		eval, ct := newTrackedCiphertext(btp)
+
+		poly := bignum.NewPolynomial(bignum.Monomial, []float64{1, 1, 0.5}, nil)
+
+		// Drains the levels so the polynomial evaluation must refresh.
+		for ct.Level() > 1 {
+			require.NoError(t, eval.MulRelin(ct, ct, ct))
+			require.NoError(t, eval.Rescale(ct, ct))
+		}
+		require.Equal(t, 0, btp.Counter)
+
+		ct, err := eval.Polynomial(ct, poly, params.DefaultScale())
+		require.NoError(t, err)
+		require.Equal(t, 1, btp.Counter)
+
+		x := values[0]
+		for i := 0; i < params.MaxLevel()-1; i++ {
+			x *= x
+		}
+		want := 1 + x + 0.5*x*x
+
+		have := make([]float64, params.MaxSlots())
+		require.NoError(t, ecd.Decode(dec.DecryptNew(ct), have))
+		for i := range have {
+			require.InDelta(t, want, have[i], 1e-6)
+		}
+
+		plan := eval.Plan()
+		require.Len(t, plan, 1)
+		require.Equal(t, "Polynomial", plan[0].Op)
+	})
+
+	t.Run("PrecisionFloor", func(t *testing.T) {
+
+		btp := bootstrapping.NewSecretKeyBootstrapper(params, sk)
+		eval, ct := newTrackedCiphertext(btp)
+
+		// An unreachable precision floor turns the first refresh into an
+		// error instead of silently continuing with a drained budget.
+		eval.MinPrecision = 1000
+
+		var err error
+		for i := 0; i < params.MaxLevel()+1 && err == nil; i++ {
+			if err = eval.MulRelin(ct, ct, ct); err == nil {
+				err = eval.Rescale(ct, ct)
+			}
+		}
+
+		require.Error(t, err)
+		require.Equal(t, 0, btp.Counter)
+		require.Empty(t, eval.Plan())
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/luxdefi/lattice/v5/core/rlwe"
 	"github.com/luxdefi/lattice/v5/he"
 	"github.com/luxdefi/lattice/v5/ring"
+	"github.com/luxdefi/lattice/v5/utils/metrics"
 )
 
 // Ensures that the Evaluator complies to the he.Bootstrapper interface
@@ -24,8 +25,17 @@ func (eval Evaluator) Bootstrap(ct *rlwe.Ciphertext) (*rlwe.Ciphertext, error) {
 // BootstrapMany bootstraps a list of ciphertext and returns the list of bootstrapped ciphertexts.
 func (eval Evaluator) BootstrapMany(cts []rlwe.Ciphertext) ([]rlwe.Ciphertext, error) {
 
+	metrics.IncCounter(metrics.Bootstrap, int64(len(cts)))
+
 	var err error
 
+	// The residual parameters are a sub-chain of the bootstrapping parameters:
+	// the inputs are re-bound to the bootstrapping parameters for the duration
+	// of the circuit and the outputs back to the residual parameters.
+	for i := range cts {
+		cts[i].ParameterFingerprint = eval.BootstrappingParameters.Fingerprint()
+	}
+
 	switch eval.ResidualParameters.RingType() {
 	case ring.ConjugateInvariant:
 
@@ -75,6 +85,7 @@ func (eval Evaluator) BootstrapMany(cts []rlwe.Ciphertext) ([]rlwe.Ciphertext, e
 
 	for i := range cts {
 		cts[i].Scale = eval.ResidualParameters.DefaultScale()
+		cts[i].ParameterFingerprint = eval.ResidualParameters.Fingerprint()
 	}
 
 	return cts, err
@@ -31,6 +31,20 @@ import (
 // 5) SlotsToCoeffs: homomorphic decoding
 func (eval Evaluator) Evaluate(ctIn *rlwe.Ciphertext) (ctOut *rlwe.Ciphertext, err error) {
 
+	// The input lives in a sub-chain of the bootstrapping parameters: it is
+	// re-bound to the bootstrapping parameters for the duration of the circuit
+	// and the output back to the residual parameters (unless the residual
+	// parameters are conjugate invariant, in which case the output is still in
+	// the standard ring and is re-bound by EvaluateConjugateInvariant).
+	if ctIn.MetaData != nil {
+		ctIn.ParameterFingerprint = eval.BootstrappingParameters.Fingerprint()
+	}
+	defer func() {
+		if ctOut != nil && ctOut.MetaData != nil && eval.ResidualParameters.RingType() == ring.Standard {
+			ctOut.ParameterFingerprint = eval.ResidualParameters.Fingerprint()
+		}
+	}()
+
 	if eval.IterationsParameters == nil && eval.ResidualParameters.PrecisionMode() != ckks.PREC128 {
 		ctOut, _, err = eval.bootstrap(ctIn)
 		return
@@ -448,7 +462,7 @@ func (eval Evaluator) ModUp(ctIn *rlwe.Ciphertext) (ctOut *rlwe.Ciphertext, err
 
 	// Scale the message from Q0/|m| to QL/|m|, where QL is the largest modulus used during the bootstrapping.
 	if scale := (eval.Mod1Parameters.ScalingFactor().Float64() / eval.Mod1Parameters.MessageRatio()) / ctIn.Scale.Float64(); scale > 1 {
-		if err = eval.ScaleUp(ctIn, rlwe.NewScale(scale), ctIn); err != nil {
+		if err = eval.Evaluator.ScaleUp(ctIn, rlwe.NewScale(scale), ctIn); err != nil {
 			return nil, err
 		}
 	}
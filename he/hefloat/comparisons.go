@@ -136,6 +136,151 @@ func (eval ComparisonEvaluator) Min(op0, op1 *rlwe.Ciphertext) (min *rlwe.Cipher
 	return stepdiff, nil
 }
 
+// Select evaluates mask * a + (1-mask) * b slot-wise, i.e. selects the slot of a where
+// the mask is 1 and the slot of b where the mask is 0. The mask is expected to hold
+// values in [0, 1], such as produced by Step, MaskGreaterThan, MaskLessThan or
+// MaskInInterval; intermediate mask values interpolate linearly between the two inputs.
+// Level and scale alignment between the operands is handled internally, bootstrapping
+// them if the remaining levels do not support the multiplication.
+// Use must ensure that a.Scale = b.Scale.
+//
+// This method ensures that c.Scale = params.DefaultScale.
+func (eval ComparisonEvaluator) Select(mask, a, b *rlwe.Ciphertext) (c *rlwe.Ciphertext, err error) {
+
+	params := eval.Parameters
+
+	// diff = a - b
+	var diff *rlwe.Ciphertext
+	if diff, err = eval.SubNew(a, b); err != nil {
+		return
+	}
+
+	// Required for the scale matching before the multiplication with the mask.
+	if diff.Level() < params.LevelsConsumedPerRescaling()*2 {
+		if diff, err = eval.Bootstrap(diff); err != nil {
+			return
+		}
+	}
+
+	// Required for the following multiplication
+	if mask.Level() < params.LevelsConsumedPerRescaling() {
+		if mask, err = eval.Bootstrap(mask); err != nil {
+			return
+		}
+	}
+
+	// Scales diff such that mask * diff has the default scale after the rescaling.
+	level := utils.Min(diff.Level(), mask.Level())
+
+	ratio := rlwe.NewScale(1)
+	for i := 0; i < params.LevelsConsumedPerRescaling(); i++ {
+		ratio = ratio.Mul(rlwe.NewScale(params.Q()[level-i]))
+	}
+
+	ratio = ratio.Div(diff.Scale)
+	if err = eval.Mul(diff, &ratio.Value, diff); err != nil {
+		return
+	}
+
+	if err = eval.Rescale(diff, diff); err != nil {
+		return
+	}
+	diff.Scale = diff.Scale.Mul(ratio)
+
+	// c = mask * (a - b)
+	if err = eval.MulRelin(diff, mask, diff); err != nil {
+		return
+	}
+
+	if err = eval.Rescale(diff, diff); err != nil {
+		return
+	}
+
+	// c = mask * a + (1 - mask) * b
+	if err = eval.Add(diff, b, diff); err != nil {
+		return
+	}
+
+	return diff, nil
+}
+
+// MaskGreaterThan returns an encrypted mask evaluating m = 1 if op0 > op1, 0 if op0 < op1, else 0.5.
+// Use must ensure that:
+//   - op0 - op1 is in the interval [-1, 1].
+//   - op0.Scale = op1.Scale.
+//
+// This method ensures that mask.Scale = params.DefaultScale.
+func (eval ComparisonEvaluator) MaskGreaterThan(op0, op1 *rlwe.Ciphertext) (mask *rlwe.Ciphertext, err error) {
+
+	var diff *rlwe.Ciphertext
+	if diff, err = eval.SubNew(op0, op1); err != nil {
+		return
+	}
+
+	return eval.Step(diff)
+}
+
+// MaskLessThan returns an encrypted mask evaluating m = 1 if op0 < op1, 0 if op0 > op1, else 0.5.
+// Use must ensure that:
+//   - op0 - op1 is in the interval [-1, 1].
+//   - op0.Scale = op1.Scale.
+//
+// This method ensures that mask.Scale = params.DefaultScale.
+func (eval ComparisonEvaluator) MaskLessThan(op0, op1 *rlwe.Ciphertext) (mask *rlwe.Ciphertext, err error) {
+
+	var diff *rlwe.Ciphertext
+	if diff, err = eval.SubNew(op1, op0); err != nil {
+		return
+	}
+
+	return eval.Step(diff)
+}
+
+// MaskInInterval returns an encrypted mask evaluating m = 1 if a < op0 < b, else 0
+// (and 0.5 on the boundaries), computed as step((op0-a)/2) - step((op0-b)/2).
+// Use must ensure that op0 and the plaintext boundaries a and b are in the interval [-1, 1].
+//
+// This method ensures that mask.Scale = params.DefaultScale.
+func (eval ComparisonEvaluator) MaskInInterval(op0 *rlwe.Ciphertext, a, b float64) (mask *rlwe.Ciphertext, err error) {
+
+	var stepA, stepB *rlwe.Ciphertext
+
+	if stepA, err = eval.stepAboveThreshold(op0, a); err != nil {
+		return
+	}
+
+	if stepB, err = eval.stepAboveThreshold(op0, b); err != nil {
+		return
+	}
+
+	if err = eval.Sub(stepA, stepB, stepA); err != nil {
+		return
+	}
+
+	return stepA, nil
+}
+
+// stepAboveThreshold evaluates the step function on (op0-t)/2, i.e. an approximation
+// of 1 if op0 > t, 0 if op0 < t. The halving maps the difference back into the
+// domain [-1, 1] of the step function.
+func (eval ComparisonEvaluator) stepAboveThreshold(op0 *rlwe.Ciphertext, t float64) (step *rlwe.Ciphertext, err error) {
+
+	var shifted *rlwe.Ciphertext
+	if shifted, err = eval.SubNew(op0, t); err != nil {
+		return
+	}
+
+	if err = eval.Mul(shifted, 0.5, shifted); err != nil {
+		return
+	}
+
+	if err = eval.Rescale(shifted, shifted); err != nil {
+		return
+	}
+
+	return eval.Step(shifted)
+}
+
 func (eval ComparisonEvaluator) stepdiff(op0, op1 *rlwe.Ciphertext) (stepdiff *rlwe.Ciphertext, err error) {
 	params := eval.Parameters
 
@@ -152,3 +152,126 @@ func TestComparisons(t *testing.T) {
 		})
 	}
 }
+
+func TestSelect(t *testing.T) {
+
+	paramsLiteral := testInsecurePrec90
+
+	if testing.Short() {
+		paramsLiteral.LogN = 10
+	}
+
+	params, err := hefloat.NewParametersFromLiteral(paramsLiteral)
+	require.NoError(t, err)
+
+	var tc *testContext
+	if tc, err = genTestParams(params); err != nil {
+		t.Fatal(err)
+	}
+
+	enc := tc.encryptorSk
+	sk := tc.sk
+	ecd := tc.encoder
+	dec := tc.decryptor
+	kgen := tc.kgen
+
+	btp := bootstrapping.NewSecretKeyBootstrapper(params, sk)
+
+	galKeys := []*rlwe.GaloisKey{kgen.GenGaloisKeyNew(params.GaloisElementForComplexConjugation(), sk)}
+
+	eval := tc.evaluator.WithKey(rlwe.NewMemEvaluationKeySet(kgen.GenRelinearizationKeyNew(sk), galKeys...))
+
+	CmpEval := hefloat.NewComparisonEvaluator(params, eval, btp)
+
+	t.Run(GetTestName(params, "Select/MaskGreaterThan"), func(t *testing.T) {
+
+		values0, _, ct0 := newTestVectors(tc, enc, complex(-0.5, 0), complex(0.5, 0), t)
+		values1, _, ct1 := newTestVectors(tc, enc, complex(-0.5, 0), complex(0.5, 0), t)
+
+		var mask *rlwe.Ciphertext
+		mask, err = CmpEval.MaskGreaterThan(ct0, ct1)
+		require.NoError(t, err)
+
+		var sel *rlwe.Ciphertext
+		sel, err = CmpEval.Select(mask, ct0, ct1)
+		require.NoError(t, err)
+
+		have := make([]*big.Float, params.MaxSlots())
+
+		require.NoError(t, ecd.Decode(dec.DecryptNew(sel), have))
+
+		want := make([]*big.Float, params.MaxSlots())
+
+		for i := range have {
+
+			if values0[i][0].Cmp(values1[i][0]) == -1 {
+				want[i] = values1[i][0]
+			} else {
+				want[i] = values0[i][0]
+			}
+		}
+
+		hefloat.VerifyTestVectors(params, ecd, nil, want, have, params.LogDefaultScale(), 0, *printPrecisionStats, t)
+	})
+
+	t.Run(GetTestName(params, "Select/MaskLessThan"), func(t *testing.T) {
+
+		values0, _, ct0 := newTestVectors(tc, enc, complex(-0.5, 0), complex(0.5, 0), t)
+		values1, _, ct1 := newTestVectors(tc, enc, complex(-0.5, 0), complex(0.5, 0), t)
+
+		var mask *rlwe.Ciphertext
+		mask, err = CmpEval.MaskLessThan(ct0, ct1)
+		require.NoError(t, err)
+
+		var sel *rlwe.Ciphertext
+		sel, err = CmpEval.Select(mask, ct0, ct1)
+		require.NoError(t, err)
+
+		have := make([]*big.Float, params.MaxSlots())
+
+		require.NoError(t, ecd.Decode(dec.DecryptNew(sel), have))
+
+		want := make([]*big.Float, params.MaxSlots())
+
+		for i := range have {
+
+			if values0[i][0].Cmp(values1[i][0]) == 1 {
+				want[i] = values1[i][0]
+			} else {
+				want[i] = values0[i][0]
+			}
+		}
+
+		hefloat.VerifyTestVectors(params, ecd, nil, want, have, params.LogDefaultScale(), 0, *printPrecisionStats, t)
+	})
+
+	t.Run(GetTestName(params, "MaskInInterval"), func(t *testing.T) {
+
+		a, b := -0.5, 0.3
+
+		values, _, ct := newTestVectors(tc, enc, complex(-1, 0), complex(1, 0), t)
+
+		var mask *rlwe.Ciphertext
+		mask, err = CmpEval.MaskInInterval(ct, a, b)
+		require.NoError(t, err)
+
+		have := make([]*big.Float, params.MaxSlots())
+
+		require.NoError(t, ecd.Decode(dec.DecryptNew(mask), have))
+
+		bigA := new(big.Float).SetFloat64(a)
+		bigB := new(big.Float).SetFloat64(b)
+
+		for i := range have {
+
+			want := 0.0
+			if values[i][0].Cmp(bigA) == 1 && values[i][0].Cmp(bigB) == -1 {
+				want = 1
+			}
+
+			haveF64, _ := have[i].Float64()
+
+			require.InDelta(t, want, haveF64, 1e-3)
+		}
+	})
+}
@@ -0,0 +1,280 @@
+package hefloat
+
+import (
+	"fmt"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/ring"
+	"github.com/luxdefi/lattice/v5/utils"
+)
+
+// DecisionTreeNode is a node of a plaintext decision tree evaluated over
+// encrypted features. Internal nodes test one feature against a threshold
+// (left: feature < threshold, right: feature > threshold), leaves carry one
+// score per class.
+type DecisionTreeNode struct {
+	// Feature is the index of the tested feature (internal nodes only).
+	Feature int
+	// Threshold is the split threshold (internal nodes only).
+	Threshold float64
+	// Left and Right are the children of an internal node, both nil on a leaf.
+	Left, Right *DecisionTreeNode
+	// Scores holds the per-class scores of a leaf.
+	Scores []float64
+}
+
+// IsLeaf returns true if the node is a leaf.
+func (n *DecisionTreeNode) IsLeaf() bool {
+	return n.Left == nil && n.Right == nil
+}
+
+// DecisionTreeEvaluator is an evaluator providing an API for the inference
+// of plaintext decision trees and random forests over encrypted features,
+// the usual template of private scoring services.
+//
+// The features are given as one ciphertext per feature, the i-th slot of
+// each holding the feature value of the i-th sample, so a single evaluation
+// scores up to params.MaxSlots() samples at once. Feature values and
+// thresholds must lie in [-1, 1], the domain of the step function of the
+// ComparisonEvaluator; samples falling on a threshold yield soft indicators
+// and interpolate between the two branches.
+//
+// Each distinct (feature, threshold) node test is evaluated once per call,
+// shared between the paths and, for forests, between the trees. The circuit
+// is slot-wise and only requires the relinearization key and, for the
+// standard ring, the Galois key for the complex conjugation (see
+// GaloisElementsForDecisionTree).
+//
+// All fields of this struct are public, enabling custom instantiations.
+type DecisionTreeEvaluator struct {
+	*Evaluator
+	// Comparisons evaluates the step function mapping feature/threshold
+	// tests to branch indicators.
+	Comparisons *ComparisonEvaluator
+}
+
+// NewDecisionTreeEvaluator instantiates a new DecisionTreeEvaluator.
+func NewDecisionTreeEvaluator(eval *Evaluator, cmp *ComparisonEvaluator) *DecisionTreeEvaluator {
+	return &DecisionTreeEvaluator{Evaluator: eval, Comparisons: cmp}
+}
+
+// GaloisElementsForDecisionTree returns the list of Galois elements required
+// to evaluate decision trees: the circuit is slot-wise, so no rotation keys
+// are needed, and only the standard ring requires the complex conjugation
+// element for the underlying step function.
+func GaloisElementsForDecisionTree(params Parameters) []uint64 {
+	if params.RingType() == ring.Standard {
+		return []uint64{params.GaloisElementForComplexConjugation()}
+	}
+	return nil
+}
+
+// EvaluateTree evaluates the plaintext tree rooted in root over the
+// encrypted features and returns the encrypted class scores, one ciphertext
+// per class: the i-th slot of the c-th result holds the score of the class c
+// for the i-th sample. See the DecisionTreeEvaluator doc for the packing and
+// normalization requirements.
+func (eval *DecisionTreeEvaluator) EvaluateTree(features []*rlwe.Ciphertext, root *DecisionTreeNode, numClasses int) (scores []*rlwe.Ciphertext, err error) {
+	if scores, err = eval.evaluateTrees(features, []*DecisionTreeNode{root}, numClasses); err != nil {
+		return nil, fmt.Errorf("cannot EvaluateTree: %w", err)
+	}
+	return
+}
+
+// EvaluateForest evaluates the plaintext forest over the encrypted features
+// and returns the encrypted class scores, one ciphertext per class, each
+// holding the sum of the scores of the trees. Node tests shared between
+// trees are evaluated once.
+func (eval *DecisionTreeEvaluator) EvaluateForest(features []*rlwe.Ciphertext, roots []*DecisionTreeNode, numClasses int) (scores []*rlwe.Ciphertext, err error) {
+	if scores, err = eval.evaluateTrees(features, roots, numClasses); err != nil {
+		return nil, fmt.Errorf("cannot EvaluateForest: %w", err)
+	}
+	return
+}
+
+// treeLeaf is a leaf reached during the traversal, with the encrypted
+// product of the branch indicators along its path.
+type treeLeaf struct {
+	path   *rlwe.Ciphertext
+	scores []float64
+}
+
+// nodeTest identifies a node test, deduplicating the step evaluations.
+type nodeTest struct {
+	feature   int
+	threshold float64
+}
+
+func (eval *DecisionTreeEvaluator) evaluateTrees(features []*rlwe.Ciphertext, roots []*DecisionTreeNode, numClasses int) (scores []*rlwe.Ciphertext, err error) {
+
+	if numClasses < 1 {
+		return nil, fmt.Errorf("numClasses must be at least 1")
+	}
+
+	indicators := map[nodeTest]*rlwe.Ciphertext{}
+
+	var leaves []treeLeaf
+	for _, root := range roots {
+		if root.IsLeaf() {
+			return nil, fmt.Errorf("tree must have at least one internal node")
+		}
+		if leaves, err = eval.walk(features, root, nil, numClasses, indicators, leaves); err != nil {
+			return nil, err
+		}
+	}
+
+	// The class scores are weighted sums of the path products, which the
+	// traversal left at depth-dependent levels and scales.
+	paths := make([]*rlwe.Ciphertext, len(leaves))
+	for i := range leaves {
+		paths[i] = leaves[i].path
+	}
+
+	if err = eval.AlignScalesAndLevels(paths...); err != nil {
+		return nil, err
+	}
+
+	// Required for the final weighted sum.
+	if paths[0].Level() < eval.GetParameters().LevelsConsumedPerRescaling() {
+
+		for i := range paths {
+			if paths[i], err = eval.Comparisons.Bootstrap(paths[i]); err != nil {
+				return nil, err
+			}
+		}
+
+		if err = eval.AlignScalesAndLevels(paths...); err != nil {
+			return nil, err
+		}
+	}
+
+	scores = make([]*rlwe.Ciphertext, numClasses)
+	for c := range scores {
+
+		acc, err := eval.MulNew(paths[0], leaves[0].scores[c])
+		if err != nil {
+			return nil, err
+		}
+
+		for i := 1; i < len(leaves); i++ {
+			if err = eval.MulThenAdd(paths[i], leaves[i].scores[c], acc); err != nil {
+				return nil, err
+			}
+		}
+
+		if err = eval.Rescale(acc, acc); err != nil {
+			return nil, err
+		}
+
+		scores[c] = acc
+	}
+
+	return
+}
+
+// walk traverses the tree, extending the encrypted path product with the
+// branch indicator of each visited node and collecting the leaves.
+func (eval *DecisionTreeEvaluator) walk(features []*rlwe.Ciphertext, node *DecisionTreeNode, path *rlwe.Ciphertext, numClasses int, indicators map[nodeTest]*rlwe.Ciphertext, leaves []treeLeaf) ([]treeLeaf, error) {
+
+	if node.IsLeaf() {
+		if len(node.Scores) != numClasses {
+			return nil, fmt.Errorf("leaf has %d scores, expected %d", len(node.Scores), numClasses)
+		}
+		return append(leaves, treeLeaf{path: path, scores: node.Scores}), nil
+	}
+
+	if node.Left == nil || node.Right == nil {
+		return nil, fmt.Errorf("internal node must have two children")
+	}
+
+	right, err := eval.indicator(features, node, indicators)
+	if err != nil {
+		return nil, err
+	}
+
+	// left = 1 - right
+	left, err := eval.MulNew(right, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = eval.Add(left, 1, left); err != nil {
+		return nil, err
+	}
+
+	rightPath, err := eval.extendPath(path, right)
+	if err != nil {
+		return nil, err
+	}
+
+	leftPath, err := eval.extendPath(path, left)
+	if err != nil {
+		return nil, err
+	}
+
+	if leaves, err = eval.walk(features, node.Left, leftPath, numClasses, indicators, leaves); err != nil {
+		return nil, err
+	}
+
+	return eval.walk(features, node.Right, rightPath, numClasses, indicators, leaves)
+}
+
+// indicator returns the encrypted branch indicator of the node test,
+// evaluating the step function once per distinct (feature, threshold) pair.
+func (eval *DecisionTreeEvaluator) indicator(features []*rlwe.Ciphertext, node *DecisionTreeNode, indicators map[nodeTest]*rlwe.Ciphertext) (b *rlwe.Ciphertext, err error) {
+
+	if node.Feature < 0 || node.Feature >= len(features) {
+		return nil, fmt.Errorf("node tests feature %d but %d features were given", node.Feature, len(features))
+	}
+
+	key := nodeTest{feature: node.Feature, threshold: node.Threshold}
+
+	if b, ok := indicators[key]; ok {
+		return b, nil
+	}
+
+	if b, err = eval.Comparisons.stepAboveThreshold(features[node.Feature], node.Threshold); err != nil {
+		return nil, err
+	}
+
+	indicators[key] = b
+
+	return
+}
+
+// extendPath multiplies the path product with the branch indicator, leaving
+// both operands untouched (the indicator may be shared between paths).
+func (eval *DecisionTreeEvaluator) extendPath(path, b *rlwe.Ciphertext) (next *rlwe.Ciphertext, err error) {
+
+	if path == nil {
+		return b.CopyNew(), nil
+	}
+
+	path, b = path.CopyNew(), b.CopyNew()
+
+	// Required for the following multiplication.
+	if utils.Min(path.Level(), b.Level()) < eval.GetParameters().LevelsConsumedPerRescaling()*2 {
+
+		if path, err = eval.Comparisons.Bootstrap(path); err != nil {
+			return nil, err
+		}
+
+		if b, err = eval.Comparisons.Bootstrap(b); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = eval.AlignScalesAndLevels(path, b); err != nil {
+		return nil, err
+	}
+
+	if next, err = eval.MulRelinNew(path, b); err != nil {
+		return nil, err
+	}
+
+	if err = eval.Rescale(next, next); err != nil {
+		return nil, err
+	}
+
+	return
+}
@@ -0,0 +1,143 @@
+package hefloat_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/he/hefloat"
+	"github.com/luxdefi/lattice/v5/he/hefloat/bootstrapping"
+)
+
+func TestDecisionTree(t *testing.T) {
+
+	paramsLiteral := testInsecurePrec90
+
+	if testing.Short() {
+		paramsLiteral.LogN = 10
+	}
+
+	params, err := hefloat.NewParametersFromLiteral(paramsLiteral)
+	require.NoError(t, err)
+
+	var tc *testContext
+	if tc, err = genTestParams(params); err != nil {
+		t.Fatal(err)
+	}
+
+	enc := tc.encryptorSk
+	sk := tc.sk
+	ecd := tc.encoder
+	dec := tc.decryptor
+	kgen := tc.kgen
+
+	btp := bootstrapping.NewSecretKeyBootstrapper(params, sk)
+
+	galEls := hefloat.GaloisElementsForDecisionTree(params)
+	require.Equal(t, []uint64{params.GaloisElementForComplexConjugation()}, galEls)
+
+	eval := tc.evaluator.WithKey(rlwe.NewMemEvaluationKeySet(kgen.GenRelinearizationKeyNew(sk), kgen.GenGaloisKeysNew(galEls, sk)...))
+
+	dtEval := hefloat.NewDecisionTreeEvaluator(eval, hefloat.NewComparisonEvaluator(params, eval, btp))
+
+	slots := params.MaxSlots()
+
+	// Feature values on a grid keeping a margin of 0.1 to every threshold,
+	// so the plaintext reference is unambiguous.
+	grid := []float64{-0.9, -0.7, -0.5, -0.3, -0.1, 0.1, 0.3, 0.5, 0.7, 0.9}
+
+	features := make([][]float64, 2)
+	cts := make([]*rlwe.Ciphertext, 2)
+	for f := range features {
+		features[f] = make([]float64, slots)
+		for i := range features[f] {
+			features[f][i] = grid[(i*(f+3)+f)%len(grid)]
+		}
+
+		pt := hefloat.NewPlaintext(params, params.MaxLevel())
+		require.NoError(t, ecd.Encode(features[f], pt))
+		cts[f], err = enc.EncryptNew(pt)
+		require.NoError(t, err)
+	}
+
+	leaf := func(scores ...float64) *hefloat.DecisionTreeNode {
+		return &hefloat.DecisionTreeNode{Scores: scores}
+	}
+
+	tree0 := &hefloat.DecisionTreeNode{
+		Feature: 0, Threshold: 0,
+		Left:  &hefloat.DecisionTreeNode{Feature: 1, Threshold: -0.4, Left: leaf(1, 0), Right: leaf(0, 1)},
+		Right: &hefloat.DecisionTreeNode{Feature: 1, Threshold: 0.4, Left: leaf(0.5, 0.5), Right: leaf(0, 0)},
+	}
+
+	// tree1 shares the root test of tree0, which is then evaluated once.
+	tree1 := &hefloat.DecisionTreeNode{
+		Feature: 0, Threshold: 0,
+		Left:  leaf(0.25, 0.75),
+		Right: leaf(0.5, 0.25),
+	}
+
+	var reference func(node *hefloat.DecisionTreeNode, i int) []float64
+	reference = func(node *hefloat.DecisionTreeNode, i int) []float64 {
+		if node.IsLeaf() {
+			return node.Scores
+		}
+		if features[node.Feature][i] > node.Threshold {
+			return reference(node.Right, i)
+		}
+		return reference(node.Left, i)
+	}
+
+	t.Run(GetTestName(params, "EvaluateTree"), func(t *testing.T) {
+
+		scores, err := dtEval.EvaluateTree(cts, tree0, 2)
+		require.NoError(t, err)
+		require.Len(t, scores, 2)
+
+		for c := range scores {
+
+			have := make([]float64, slots)
+			require.NoError(t, ecd.Decode(dec.DecryptNew(scores[c]), have))
+
+			for i := 0; i < slots; i++ {
+				require.InDelta(t, reference(tree0, i)[c], have[i], 1e-3)
+			}
+		}
+	})
+
+	t.Run(GetTestName(params, "EvaluateForest"), func(t *testing.T) {
+
+		scores, err := dtEval.EvaluateForest(cts, []*hefloat.DecisionTreeNode{tree0, tree1}, 2)
+		require.NoError(t, err)
+		require.Len(t, scores, 2)
+
+		for c := range scores {
+
+			have := make([]float64, slots)
+			require.NoError(t, ecd.Decode(dec.DecryptNew(scores[c]), have))
+
+			for i := 0; i < slots; i++ {
+				want := reference(tree0, i)[c] + reference(tree1, i)[c]
+				require.InDelta(t, want, have[i], 1e-3)
+			}
+		}
+	})
+
+	t.Run(GetTestName(params, "InvalidTrees"), func(t *testing.T) {
+
+		_, err := dtEval.EvaluateTree(cts, leaf(1, 0), 2)
+		require.Error(t, err)
+
+		_, err = dtEval.EvaluateTree(cts, tree0, 3)
+		require.Error(t, err)
+
+		badFeature := &hefloat.DecisionTreeNode{Feature: 7, Threshold: 0, Left: leaf(1), Right: leaf(0)}
+		_, err = dtEval.EvaluateTree(cts, badFeature, 1)
+		require.Error(t, err)
+
+		oneChild := &hefloat.DecisionTreeNode{Feature: 0, Threshold: 0, Left: leaf(1)}
+		_, err = dtEval.EvaluateTree(cts, oneChild, 1)
+		require.Error(t, err)
+	})
+}
@@ -2,10 +2,23 @@
 package hefloat
 
 import (
+	"encoding/binary"
 	"testing"
 
 	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/he"
 	"github.com/luxdefi/lattice/v5/schemes/ckks"
+	"github.com/luxdefi/lattice/v5/utils/sampling"
+)
+
+// The front-end implements the scheme agnostic interfaces of the he package.
+var (
+	_ he.Encryptor   = (*rlwe.Encryptor)(nil)
+	_ he.Decryptor   = (*rlwe.Decryptor)(nil)
+	_ he.SlotEncoder = (*Encoder)(nil)
+	_ he.Evaluator   = (*Evaluator)(nil)
+	_ he.SlotRotator = (*Evaluator)(nil)
+	_ he.Conjugator  = (*Evaluator)(nil)
 )
 
 type Float interface {
@@ -83,6 +96,18 @@ func (ecd Encoder) ShallowCopy() *Encoder {
 	return &Encoder{Encoder: *ecd.Encoder.ShallowCopy()}
 }
 
+// EncodeSlots encodes values on the plaintext slots. It implements the
+// he.SlotEncoder interface, mapping to Encode.
+func (ecd Encoder) EncodeSlots(values interface{}, pt *rlwe.Plaintext) (err error) {
+	return ecd.Encoder.Encode(values, pt)
+}
+
+// DecodeSlots decodes the plaintext slots into values. It implements the
+// he.SlotEncoder interface, mapping to Decode.
+func (ecd Encoder) DecodeSlots(pt *rlwe.Plaintext, values interface{}) (err error) {
+	return ecd.Encoder.Decode(pt, values)
+}
+
 type Evaluator struct {
 	ckks.Evaluator
 }
@@ -103,6 +128,27 @@ func (eval Evaluator) ShallowCopy() *Evaluator {
 	return &Evaluator{Evaluator: *eval.Evaluator.ShallowCopy()}
 }
 
+// RotateSlots cyclically rotates the plaintext slots of op0 by k positions to
+// the left. It implements the he.SlotRotator interface, mapping to Rotate.
+func (eval Evaluator) RotateSlots(op0 *rlwe.Ciphertext, k int, opOut *rlwe.Ciphertext) (err error) {
+	return eval.Evaluator.Rotate(op0, k, opOut)
+}
+
+// RemainingMultiplications returns the number of further multiplications,
+// each followed by its rescaling (equivalently, the number of further
+// rescalings), that the level of op0 allows, i.e.
+// op0.Level() / params.LevelsConsumedPerRescaling().
+func (eval Evaluator) RemainingMultiplications(op0 *rlwe.Ciphertext) int {
+	return op0.Level() / eval.GetParameters().LevelsConsumedPerRescaling()
+}
+
+// Budget simulates, without evaluating it, the given plan of operations on
+// op0 and reports how many more multiplications and rescalings are possible
+// and which operations require a relinearization key (see he.EvaluateBudget).
+func (eval Evaluator) Budget(op0 *rlwe.Ciphertext, plan []he.Op) he.Budget {
+	return he.EvaluateBudget(op0.Level(), op0.Degree(), eval.GetParameters().LevelsConsumedPerRescaling(), plan)
+}
+
 func GetPrecisionStats(params Parameters, encoder *Encoder, decryptor *rlwe.Decryptor, want, have interface{}, logprec float64, computeDCF bool) (prec ckks.PrecisionStats) {
 	return ckks.GetPrecisionStats(params.Parameters, &encoder.Encoder, decryptor, want, have, logprec, computeDCF)
 }
@@ -110,3 +156,48 @@ func GetPrecisionStats(params Parameters, encoder *Encoder, decryptor *rlwe.Decr
 func VerifyTestVectors(params Parameters, encoder *Encoder, decryptor *rlwe.Decryptor, valuesWant, valuesHave interface{}, log2MinPrec int, logprec float64, printPrecisionStats bool, t *testing.T) {
 	ckks.VerifyTestVectors(params.Parameters, &encoder.Encoder, decryptor, valuesWant, valuesHave, log2MinPrec, logprec, printPrecisionStats, t)
 }
+
+// NewConsistencyChecker instantiates a he.ConsistencyChecker applying the
+// tolerance policy of the approximate schemes: decrypted values must match
+// the reference up to the absolute error epsilon or the relative error
+// relative.
+func NewConsistencyChecker(params Parameters, sk *rlwe.SecretKey, epsilon, relative float64) *he.ConsistencyChecker {
+	return he.NewConsistencyChecker(params, sk, NewEncoder(params), he.Tolerance{Epsilon: epsilon, Relative: relative})
+}
+
+// Power computes op0^k, for k >= 1, with a balanced addition chain of
+// multiplications, relinearizing and rescaling the intermediate powers
+// automatically (see he.Power).
+func (eval Evaluator) Power(op0 *rlwe.Ciphertext, k int) (opOut *rlwe.Ciphertext, err error) {
+	return he.Power(eval, op0, k)
+}
+
+// maskSampler samples mask slot values uniformly in [-1, 1).
+type maskSampler struct{}
+
+// SampleNew derives slots uniform values in [-1, 1) from prng, each with 53
+// bits of precision. It implements the he.MaskSampler interface.
+func (maskSampler) SampleNew(prng sampling.PRNG, slots int) (values interface{}, err error) {
+
+	buf := make([]byte, 8)
+	mv := make([]float64, slots)
+
+	for i := range mv {
+		if _, err = prng.Read(buf); err != nil {
+			return nil, err
+		}
+
+		mv[i] = 2*float64(binary.LittleEndian.Uint64(buf)>>11)/(1<<53) - 1
+	}
+
+	return mv, nil
+}
+
+// NewMasker instantiates a he.Masker deriving masks uniform in [-1, 1) from
+// a PRF keyed by key (at most 64 bytes). The masks being encoded at the scale
+// of the masked ciphertext, masking and unmasking are exact inverses, but the
+// masking is only statistically hiding: values must be normalized so that the
+// mask distribution covers their domain.
+func NewMasker(params Parameters, key []byte) *he.Masker {
+	return he.NewMasker(params, key, NewEncoder(params), maskSampler{})
+}
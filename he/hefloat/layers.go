@@ -0,0 +1,398 @@
+package hefloat
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+)
+
+// PackedTensor represents an encrypted H x W x C tensor packed one channel per
+// ciphertext, each channel stored in row-major order in the first H*W slots.
+// Layer helpers operating on this abstraction report the rotations they
+// require, so that inference graphs can be assembled from building blocks and
+// the Galois keys generated once from the aggregated report.
+type PackedTensor struct {
+	Channels []*rlwe.Ciphertext
+	H, W     int
+}
+
+// Layer is an encrypted neural-network layer operating on the packed-tensor
+// abstraction. GaloisElements reports the Galois elements the layer's
+// evaluation requires, so that the necessary Galois keys can be generated
+// before any ciphertext is processed.
+type Layer interface {
+	GaloisElements(params Parameters) []uint64
+	Evaluate(eval *Evaluator, in *PackedTensor) (out *PackedTensor, err error)
+}
+
+// Sequential is a sequence of layers evaluated in order.
+type Sequential []Layer
+
+// GaloisElements returns the union of the Galois elements required by the
+// layers, without duplicates.
+func (model Sequential) GaloisElements(params Parameters) (galEls []uint64) {
+	m := map[uint64]bool{}
+	for _, layer := range model {
+		for _, galEl := range layer.GaloisElements(params) {
+			if !m[galEl] {
+				m[galEl] = true
+				galEls = append(galEls, galEl)
+			}
+		}
+	}
+	return
+}
+
+// Evaluate evaluates the layers in order on the input tensor.
+func (model Sequential) Evaluate(eval *Evaluator, in *PackedTensor) (out *PackedTensor, err error) {
+	out = in
+	for i, layer := range model {
+		if out, err = layer.Evaluate(eval, out); err != nil {
+			return nil, fmt.Errorf("cannot Evaluate: layer %d: %w", i, err)
+		}
+	}
+	return
+}
+
+// Conv2D is a 2D convolution with stride 1 and valid padding. Weights are
+// indexed as [co][ci][kh][kw]. The output tensor keeps the input packing, with
+// the output pixel (y, x) stored in slot y*W+x; the slots of the last KH-1
+// rows and KW-1 columns contain invalid values and must be masked or ignored
+// downstream.
+type Conv2D struct {
+	Weights [][][][]float64
+	Bias    []float64
+	width   int
+}
+
+// dims returns (co, ci, kh, kw) of the convolution weights.
+func (c Conv2D) dims() (co, ci, kh, kw int) {
+	co = len(c.Weights)
+	if co > 0 {
+		ci = len(c.Weights[0])
+		if ci > 0 {
+			kh = len(c.Weights[0][0])
+			if kh > 0 {
+				kw = len(c.Weights[0][0][0])
+			}
+		}
+	}
+	return
+}
+
+// rotations returns the slot rotations required for a given input width.
+func (c Conv2D) rotations(W int) (rots []int) {
+	_, _, kh, kw := c.dims()
+	for dy := 0; dy < kh; dy++ {
+		for dx := 0; dx < kw; dx++ {
+			if rot := dy*W + dx; rot != 0 {
+				rots = append(rots, rot)
+			}
+		}
+	}
+	return
+}
+
+// GaloisElements reports the Galois elements required to evaluate the layer on
+// inputs of the width it was declared for.
+func (c Conv2D) GaloisElements(params Parameters) []uint64 {
+	return c.GaloisElementsForWidth(params, c.width)
+}
+
+// GaloisElementsForWidth reports the Galois elements required to evaluate the
+// layer on an input of the given width.
+func (c Conv2D) GaloisElementsForWidth(params Parameters, W int) []uint64 {
+	return params.GaloisElements(c.rotations(W))
+}
+
+// NewConv2D creates a Conv2D layer for inputs of width W with the given
+// weights, indexed as [co][ci][kh][kw], and optional (nil) per-output-channel bias.
+func NewConv2D(weights [][][][]float64, bias []float64, W int) *Conv2D {
+	return &Conv2D{Weights: weights, Bias: bias, width: W}
+}
+
+// Evaluate applies the convolution. The input must have as many channels as
+// the layer's ci dimension; the output has co channels and consumes one level.
+func (c Conv2D) Evaluate(eval *Evaluator, in *PackedTensor) (out *PackedTensor, err error) {
+
+	co, ci, kh, kw := c.dims()
+
+	if len(in.Channels) != ci {
+		return nil, fmt.Errorf("cannot Evaluate: layer expects %d input channels but tensor has %d", ci, len(in.Channels))
+	}
+
+	// Pre-rotates each input channel once for every kernel offset, shared
+	// across all the output channels.
+	rotated := make([]map[int]*rlwe.Ciphertext, ci)
+	for i, ct := range in.Channels {
+		rots := append([]int{0}, c.rotations(in.W)...)
+		if rotated[i], err = eval.RotateHoistedNew(ct, rots); err != nil {
+			return nil, fmt.Errorf("cannot Evaluate: %w", err)
+		}
+	}
+
+	out = &PackedTensor{Channels: make([]*rlwe.Ciphertext, co), H: in.H, W: in.W}
+
+	for o := 0; o < co; o++ {
+
+		var acc *rlwe.Ciphertext
+
+		for i := 0; i < ci; i++ {
+			for dy := 0; dy < kh; dy++ {
+				for dx := 0; dx < kw; dx++ {
+
+					w := c.Weights[o][i][dy][dx]
+					ct := rotated[i][dy*in.W+dx]
+
+					if acc == nil {
+						if acc, err = eval.MulNew(ct, w); err != nil {
+							return nil, fmt.Errorf("cannot Evaluate: %w", err)
+						}
+					} else if err = eval.MulThenAdd(ct, w, acc); err != nil {
+						return nil, fmt.Errorf("cannot Evaluate: %w", err)
+					}
+				}
+			}
+		}
+
+		if err = eval.Rescale(acc, acc); err != nil {
+			return nil, fmt.Errorf("cannot Evaluate: %w", err)
+		}
+
+		if c.Bias != nil {
+			if err = eval.Add(acc, c.Bias[o], acc); err != nil {
+				return nil, fmt.Errorf("cannot Evaluate: %w", err)
+			}
+		}
+
+		out.Channels[o] = acc
+	}
+
+	return
+}
+
+// AvgPool2D is an average pooling of kernel size K x K with stride 1 and valid
+// padding, with the same output packing convention as Conv2D.
+type AvgPool2D struct {
+	K     int
+	width int
+}
+
+// NewAvgPool2D creates an AvgPool2D layer of kernel size K for inputs of width W.
+func NewAvgPool2D(K, W int) *AvgPool2D {
+	return &AvgPool2D{K: K, width: W}
+}
+
+// GaloisElements reports the Galois elements required to evaluate the layer.
+func (p AvgPool2D) GaloisElements(params Parameters) []uint64 {
+	return p.GaloisElementsForWidth(params, p.width)
+}
+
+// GaloisElementsForWidth reports the Galois elements required to evaluate the
+// layer on an input of the given width.
+func (p AvgPool2D) GaloisElementsForWidth(params Parameters, W int) (galEls []uint64) {
+	var rots []int
+	for dy := 0; dy < p.K; dy++ {
+		for dx := 0; dx < p.K; dx++ {
+			if rot := dy*W + dx; rot != 0 {
+				rots = append(rots, rot)
+			}
+		}
+	}
+	return params.GaloisElements(rots)
+}
+
+// Evaluate applies the pooling channel-wise; the output consumes one level.
+func (p AvgPool2D) Evaluate(eval *Evaluator, in *PackedTensor) (out *PackedTensor, err error) {
+
+	out = &PackedTensor{Channels: make([]*rlwe.Ciphertext, len(in.Channels)), H: in.H, W: in.W}
+
+	for i, ct := range in.Channels {
+
+		acc := ct.CopyNew()
+
+		for dy := 0; dy < p.K; dy++ {
+			for dx := 0; dx < p.K; dx++ {
+				if rot := dy*in.W + dx; rot != 0 {
+					var rotated *rlwe.Ciphertext
+					if rotated, err = eval.RotateNew(ct, rot); err != nil {
+						return nil, fmt.Errorf("cannot Evaluate: %w", err)
+					}
+					if err = eval.Add(acc, rotated, acc); err != nil {
+						return nil, fmt.Errorf("cannot Evaluate: %w", err)
+					}
+				}
+			}
+		}
+
+		if err = eval.Mul(acc, 1/float64(p.K*p.K), acc); err != nil {
+			return nil, fmt.Errorf("cannot Evaluate: %w", err)
+		}
+
+		if err = eval.Rescale(acc, acc); err != nil {
+			return nil, fmt.Errorf("cannot Evaluate: %w", err)
+		}
+
+		out.Channels[i] = acc
+	}
+
+	return
+}
+
+// Dense is a fully connected layer y = Wx + b evaluated with the diagonal
+// method on a single-channel input of n = H*W slots. The weight matrix is
+// indexed as [out][in], with in <= n. For large dense layers, the BSGS-based
+// LinearTransformation should be preferred.
+type Dense struct {
+	Weights [][]float64
+	Bias    []float64
+	n       int
+}
+
+// NewDense creates a Dense layer for flattened inputs of n slots.
+func NewDense(weights [][]float64, bias []float64, n int) *Dense {
+	return &Dense{Weights: weights, Bias: bias, n: n}
+}
+
+// diagonals returns the non-zero generalized diagonals of the weight matrix,
+// embedded into vectors of n slots.
+func (d Dense) diagonals() (diags map[int][]float64) {
+
+	rows := len(d.Weights)
+	cols := 0
+	if rows > 0 {
+		cols = len(d.Weights[0])
+	}
+
+	n := d.n
+
+	diags = map[int][]float64{}
+
+	for k := 0; k < n; k++ {
+		var diag []float64
+		for i := 0; i < rows; i++ {
+			if j := (i + k) % n; j < cols {
+				if w := d.Weights[i][j]; w != 0 {
+					if diag == nil {
+						diag = make([]float64, n)
+					}
+					diag[i] = w
+				}
+			}
+		}
+		if diag != nil {
+			diags[k] = diag
+		}
+	}
+
+	return
+}
+
+// GaloisElements reports the Galois elements required to evaluate the layer.
+func (d Dense) GaloisElements(params Parameters) (galEls []uint64) {
+	var rots []int
+	for k := range d.diagonals() {
+		if k != 0 {
+			rots = append(rots, k)
+		}
+	}
+	return params.GaloisElements(rots)
+}
+
+// Evaluate applies the dense layer on a single-channel tensor; the output is a
+// single-channel tensor of height 1 and width len(Weights), and consumes one level.
+//
+// The diagonal method requires the rotations to wrap around the n first slots,
+// which holds when n equals the number of slots of the parameters. For n
+// smaller than the number of slots, inputs must be replicated (see
+// GaloisElementsForReplicate) or the matrix embedded at the full slot count.
+func (d Dense) Evaluate(eval *Evaluator, in *PackedTensor) (out *PackedTensor, err error) {
+
+	if len(in.Channels) != 1 {
+		return nil, fmt.Errorf("cannot Evaluate: Dense expects a single-channel tensor but got %d channels", len(in.Channels))
+	}
+
+	ct := in.Channels[0]
+
+	var acc *rlwe.Ciphertext
+
+	diags := d.diagonals()
+
+	rots := make([]int, 0, len(diags))
+	for k := range diags {
+		rots = append(rots, k)
+	}
+
+	rotated, err := eval.RotateHoistedNew(ct, rots)
+	if err != nil {
+		return nil, fmt.Errorf("cannot Evaluate: %w", err)
+	}
+
+	for _, k := range rots {
+		if acc == nil {
+			if acc, err = eval.MulNew(rotated[k], diags[k]); err != nil {
+				return nil, fmt.Errorf("cannot Evaluate: %w", err)
+			}
+		} else if err = eval.MulThenAdd(rotated[k], diags[k], acc); err != nil {
+			return nil, fmt.Errorf("cannot Evaluate: %w", err)
+		}
+	}
+
+	if acc == nil {
+		return nil, fmt.Errorf("cannot Evaluate: all-zero weight matrix")
+	}
+
+	if err = eval.Rescale(acc, acc); err != nil {
+		return nil, fmt.Errorf("cannot Evaluate: %w", err)
+	}
+
+	if d.Bias != nil {
+		bias := make([]float64, d.n)
+		copy(bias, d.Bias)
+		if err = eval.Add(acc, bias, acc); err != nil {
+			return nil, fmt.Errorf("cannot Evaluate: %w", err)
+		}
+	}
+
+	return &PackedTensor{Channels: []*rlwe.Ciphertext{acc}, H: 1, W: len(d.Weights)}, nil
+}
+
+// GaloisElementsForWidth implements the Layer interface consistency with the
+// other layers; Dense rotations do not depend on the input width.
+func (d Dense) GaloisElementsForWidth(params Parameters, _ int) []uint64 {
+	return d.GaloisElements(params)
+}
+
+// FoldBatchNorm folds a batch-normalization layer (gamma, beta, mean, variance,
+// eps) into the preceding convolution, rescaling its weights and bias in place
+// so that no extra multiplicative depth is consumed at inference time.
+func (c *Conv2D) FoldBatchNorm(gamma, beta, mean, variance []float64, eps float64) error {
+
+	co, _, _, _ := c.dims()
+
+	if len(gamma) != co || len(beta) != co || len(mean) != co || len(variance) != co {
+		return fmt.Errorf("cannot FoldBatchNorm: expected %d per-channel statistics", co)
+	}
+
+	if c.Bias == nil {
+		c.Bias = make([]float64, co)
+	}
+
+	for o := 0; o < co; o++ {
+
+		scale := gamma[o] / math.Sqrt(variance[o]+eps)
+
+		for i := range c.Weights[o] {
+			for dy := range c.Weights[o][i] {
+				for dx := range c.Weights[o][i][dy] {
+					c.Weights[o][i][dy][dx] *= scale
+				}
+			}
+		}
+
+		c.Bias[o] = beta[o] + (c.Bias[o]-mean[o])*scale
+	}
+
+	return nil
+}
@@ -0,0 +1,159 @@
+package hefloat
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+)
+
+func testLayerContext(t *testing.T, galEls []uint64) (params Parameters, enc *rlwe.Encryptor, dec *rlwe.Decryptor, ecd *Encoder, eval *Evaluator) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN:            10,
+		LogQ:            []int{55, 45, 45},
+		LogP:            []int{61},
+		LogDefaultScale: 45,
+	})
+	require.NoError(t, err)
+
+	kgen := NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+
+	evk := rlwe.NewMemEvaluationKeySet(nil, kgen.GenGaloisKeysNew(galEls, sk)...)
+
+	return params, NewEncryptor(params, sk), NewDecryptor(params, sk), NewEncoder(params), NewEvaluator(params, evk)
+}
+
+func encryptSlots(t *testing.T, params Parameters, ecd *Encoder, enc *rlwe.Encryptor, values []float64) *rlwe.Ciphertext {
+	pt := NewPlaintext(params, params.MaxLevel())
+	require.NoError(t, ecd.Encode(values, pt))
+	ct, err := enc.EncryptNew(pt)
+	require.NoError(t, err)
+	return ct
+}
+
+func decryptSlots(t *testing.T, ecd *Encoder, dec *rlwe.Decryptor, ct *rlwe.Ciphertext, n int) []float64 {
+	values := make([]float64, n)
+	require.NoError(t, ecd.Decode(dec.DecryptNew(ct), values))
+	return values
+}
+
+func TestConv2DAndAvgPool2D(t *testing.T) {
+
+	H, W := 4, 4
+
+	conv := NewConv2D([][][][]float64{
+		{{{1, -1}, {0.5, 0.25}}},
+		{{{-0.5, 2}, {1, 0}}},
+	}, []float64{0.25, -0.5}, W)
+
+	require.NoError(t, conv.FoldBatchNorm(
+		[]float64{1.5, 0.5},
+		[]float64{0.1, -0.1},
+		[]float64{0.2, 0.3},
+		[]float64{1.1, 0.9},
+		1e-5,
+	))
+
+	pool := NewAvgPool2D(2, W)
+
+	model := Sequential{conv, pool}
+
+	params, enc, dec, ecd, eval := testLayerContext(t, model.GaloisElements(params0(t)))
+
+	image := make([]float64, H*W)
+	for i := range image {
+		image[i] = math.Sin(float64(i))
+	}
+
+	in := &PackedTensor{Channels: []*rlwe.Ciphertext{encryptSlots(t, params, ecd, enc, image)}, H: H, W: W}
+
+	out, err := model.Evaluate(eval, in)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(out.Channels))
+
+	// Plaintext reference.
+	for o := 0; o < 2; o++ {
+
+		convRef := make([]float64, H*W)
+		for y := 0; y < H-1; y++ {
+			for x := 0; x < W-1; x++ {
+				acc := conv.Bias[o]
+				for dy := 0; dy < 2; dy++ {
+					for dx := 0; dx < 2; dx++ {
+						acc += conv.Weights[o][0][dy][dx] * image[(y+dy)*W+(x+dx)]
+					}
+				}
+				convRef[y*W+x] = acc
+			}
+		}
+
+		have := decryptSlots(t, ecd, dec, out.Channels[o], H*W)
+
+		// Only the valid region of the pooling over the valid region of the
+		// convolution is checked.
+		for y := 0; y < H-2; y++ {
+			for x := 0; x < W-2; x++ {
+				want := (convRef[y*W+x] + convRef[y*W+x+1] + convRef[(y+1)*W+x] + convRef[(y+1)*W+x+1]) / 4
+				require.InDelta(t, want, have[y*W+x], 1e-4)
+			}
+		}
+	}
+}
+
+// params0 returns the parameters used by testLayerContext, for computing the
+// Galois element report before the context exists.
+func params0(t *testing.T) Parameters {
+	params, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN:            10,
+		LogQ:            []int{55, 45, 45},
+		LogP:            []int{61},
+		LogDefaultScale: 45,
+	})
+	require.NoError(t, err)
+	return params
+}
+
+func TestDense(t *testing.T) {
+
+	n := 8
+
+	weights := [][]float64{
+		{1, 0, 0, 0.5, 0, 0, 0, -1},
+		{0, 2, 0, 0, 0, 0.25, 0, 0},
+		{-1, 0, 1, 0, -0.5, 0, 0, 0},
+	}
+	bias := []float64{0.5, -0.25, 1}
+
+	dense := NewDense(weights, bias, n)
+
+	params, enc, dec, ecd, eval := testLayerContext(t, dense.GaloisElements(params0(t)))
+
+	vector := []float64{0.25, -0.5, 1, 0.75, -1, 0.5, -0.25, 0.125}
+
+	// The diagonal method requires the input to wrap around modulo n, which is
+	// obtained by replicating it over all the slots.
+	replicated := make([]float64, params.MaxSlots())
+	for i := range replicated {
+		replicated[i] = vector[i%n]
+	}
+
+	in := &PackedTensor{Channels: []*rlwe.Ciphertext{encryptSlots(t, params, ecd, enc, replicated)}, H: 1, W: n}
+
+	out, err := dense.Evaluate(eval, in)
+	require.NoError(t, err)
+
+	have := decryptSlots(t, ecd, dec, out.Channels[0], len(weights))
+
+	for i := range weights {
+		want := bias[i]
+		for j := range weights[i] {
+			want += weights[i][j] * vector[j]
+		}
+		require.InDelta(t, want, have[i], 1e-4)
+	}
+}
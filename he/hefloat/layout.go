@@ -0,0 +1,143 @@
+package hefloat
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+)
+
+// Layout identifies the packing of the values of a plaintext or a ciphertext:
+// slot-packed values live in the evaluation (slot) domain, coefficient-packed
+// values in the coefficients of the underlying polynomial. The two encodings
+// are related by the ring isomorphism evaluated by the homomorphic DFT
+// circuits, and mixing them up is a recurring source of silent bugs: the
+// helpers of this file make the layout of each operand explicit and reject
+// mismatched inputs.
+type Layout int
+
+const (
+	// LayoutSlots designates the slot-packed encoding (IsBatched = true), on
+	// which slot-wise addition and multiplication act element-wise.
+	LayoutSlots = Layout(0)
+	// LayoutCoefficients designates the coefficient-packed encoding
+	// (IsBatched = false), on which multiplication acts as a negacyclic
+	// convolution.
+	LayoutCoefficients = Layout(1)
+)
+
+// String implements the fmt.Stringer interface.
+func (l Layout) String() string {
+	switch l {
+	case LayoutSlots:
+		return "Slots"
+	case LayoutCoefficients:
+		return "Coefficients"
+	default:
+		return "Unknown"
+	}
+}
+
+// LayoutOf returns the layout recorded in the metadata of a plaintext or a
+// ciphertext.
+func LayoutOf(metadata *rlwe.MetaData) Layout {
+	if metadata.IsBatched {
+		return LayoutSlots
+	}
+	return LayoutCoefficients
+}
+
+// ConvertLayout re-encodes ptIn on ptOut in the given layout, plaintext-side,
+// at the level and scale of ptOut. The first Slots(ptIn) real values are
+// converted (the imaginary part, which the coefficient-packed encoding cannot
+// represent, is discarded). For the homomorphic counterpart, see
+// DFTEvaluator.ToSlotsNew and DFTEvaluator.ToCoeffsNew.
+func ConvertLayout(ecd *Encoder, ptIn *rlwe.Plaintext, layout Layout, ptOut *rlwe.Plaintext) (err error) {
+
+	n := ptIn.Slots()
+
+	ptOut.IsBatched = layout == LayoutSlots
+
+	if ecd.Prec() <= 53 {
+
+		values := make([]float64, n)
+
+		if err = ecd.Decode(ptIn, values); err != nil {
+			return fmt.Errorf("cannot ConvertLayout: %w", err)
+		}
+
+		if err = ecd.Encode(values, ptOut); err != nil {
+			return fmt.Errorf("cannot ConvertLayout: %w", err)
+		}
+
+		return
+	}
+
+	values := make([]*big.Float, n)
+
+	if err = ecd.Decode(ptIn, values); err != nil {
+		return fmt.Errorf("cannot ConvertLayout: %w", err)
+	}
+
+	if err = ecd.Encode(values, ptOut); err != nil {
+		return fmt.Errorf("cannot ConvertLayout: %w", err)
+	}
+
+	return
+}
+
+// ToSlotsNew homomorphically converts a coefficient-packed ciphertext into
+// its slot-packed encoding(s) by evaluating the HomomorphicEncode DFT
+// circuit, checking the layout of the input and recording the layout of the
+// outputs in their metadata. See DFTEvaluator.CoeffsToSlotsNew for the
+// distribution of the values between ctReal and ctImag.
+func (eval *DFTEvaluator) ToSlotsNew(ctIn *rlwe.Ciphertext, ctsMatrices DFTMatrix) (ctReal, ctImag *rlwe.Ciphertext, err error) {
+
+	if layout := LayoutOf(ctIn.MetaData); layout != LayoutCoefficients {
+		return nil, nil, fmt.Errorf("cannot ToSlotsNew: ctIn layout is %s, expected %s", layout, LayoutCoefficients)
+	}
+
+	if ctsMatrices.Type != HomomorphicEncode {
+		return nil, nil, fmt.Errorf("cannot ToSlotsNew: ctsMatrices.Type must be HomomorphicEncode")
+	}
+
+	if ctReal, ctImag, err = eval.CoeffsToSlotsNew(ctIn, ctsMatrices); err != nil {
+		return nil, nil, fmt.Errorf("cannot ToSlotsNew: %w", err)
+	}
+
+	ctReal.IsBatched = true
+	if ctImag != nil {
+		ctImag.IsBatched = true
+	}
+
+	return
+}
+
+// ToCoeffsNew homomorphically converts slot-packed ciphertext(s) into the
+// coefficient-packed encoding by evaluating the HomomorphicDecode DFT
+// circuit, checking the layout of the inputs and recording the layout of the
+// output in its metadata. ctImag can be nil, see DFTEvaluator.SlotsToCoeffsNew.
+func (eval *DFTEvaluator) ToCoeffsNew(ctReal, ctImag *rlwe.Ciphertext, stcMatrices DFTMatrix) (opOut *rlwe.Ciphertext, err error) {
+
+	if layout := LayoutOf(ctReal.MetaData); layout != LayoutSlots {
+		return nil, fmt.Errorf("cannot ToCoeffsNew: ctReal layout is %s, expected %s", layout, LayoutSlots)
+	}
+
+	if ctImag != nil {
+		if layout := LayoutOf(ctImag.MetaData); layout != LayoutSlots {
+			return nil, fmt.Errorf("cannot ToCoeffsNew: ctImag layout is %s, expected %s", layout, LayoutSlots)
+		}
+	}
+
+	if stcMatrices.Type != HomomorphicDecode {
+		return nil, fmt.Errorf("cannot ToCoeffsNew: stcMatrices.Type must be HomomorphicDecode")
+	}
+
+	if opOut, err = eval.SlotsToCoeffsNew(ctReal, ctImag, stcMatrices); err != nil {
+		return nil, fmt.Errorf("cannot ToCoeffsNew: %w", err)
+	}
+
+	opOut.IsBatched = false
+
+	return
+}
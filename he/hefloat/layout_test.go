@@ -0,0 +1,96 @@
+package hefloat_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxdefi/lattice/v5/he/hefloat"
+	"github.com/luxdefi/lattice/v5/utils/sampling"
+)
+
+func TestLayout(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := hefloat.NewParametersFromLiteral(hefloat.ParametersLiteral{
+		LogN:            10,
+		LogQ:            []int{60, 45, 45},
+		LogP:            []int{61},
+		LogDefaultScale: 45,
+	})
+	require.NoError(t, err)
+
+	ecd := hefloat.NewEncoder(params)
+
+	t.Run("LayoutOf", func(t *testing.T) {
+
+		pt := hefloat.NewPlaintext(params, params.MaxLevel())
+		require.Equal(t, hefloat.LayoutSlots, hefloat.LayoutOf(pt.MetaData))
+
+		pt.IsBatched = false
+		require.Equal(t, hefloat.LayoutCoefficients, hefloat.LayoutOf(pt.MetaData))
+
+		require.Equal(t, "Slots", hefloat.LayoutSlots.String())
+		require.Equal(t, "Coefficients", hefloat.LayoutCoefficients.String())
+	})
+
+	t.Run("ConvertLayout", func(t *testing.T) {
+
+		values := make([]float64, params.MaxSlots())
+		for i := range values {
+			values[i] = sampling.RandFloat64(-1, 1)
+		}
+
+		ptSlots := hefloat.NewPlaintext(params, params.MaxLevel())
+		require.NoError(t, ecd.Encode(values, ptSlots))
+
+		// Slots -> coefficients: the values become the leading coefficients.
+		ptCoeffs := hefloat.NewPlaintext(params, params.MaxLevel())
+		require.NoError(t, hefloat.ConvertLayout(ecd, ptSlots, hefloat.LayoutCoefficients, ptCoeffs))
+		require.Equal(t, hefloat.LayoutCoefficients, hefloat.LayoutOf(ptCoeffs.MetaData))
+
+		coeffs := make([]float64, params.MaxSlots())
+		require.NoError(t, ecd.Decode(ptCoeffs, coeffs))
+		for i := range values {
+			require.InDelta(t, values[i], coeffs[i], 1e-8)
+		}
+
+		// Coefficients -> slots: the roundtrip recovers the values.
+		ptBack := hefloat.NewPlaintext(params, params.MaxLevel())
+		require.NoError(t, hefloat.ConvertLayout(ecd, ptCoeffs, hefloat.LayoutSlots, ptBack))
+		require.Equal(t, hefloat.LayoutSlots, hefloat.LayoutOf(ptBack.MetaData))
+
+		have := make([]float64, params.MaxSlots())
+		require.NoError(t, ecd.Decode(ptBack, have))
+		for i := range values {
+			require.InDelta(t, values[i], have[i], 1e-8)
+		}
+	})
+
+	t.Run("HomomorphicLayoutChecks", func(t *testing.T) {
+
+		eval := hefloat.NewDFTEvaluator(params, hefloat.NewEvaluator(params, nil))
+
+		ctSlots := hefloat.NewCiphertext(params, 1, params.MaxLevel())
+		ctCoeffs := hefloat.NewCiphertext(params, 1, params.MaxLevel())
+		ctCoeffs.IsBatched = false
+
+		// Mismatched input layouts are rejected before any evaluation.
+		_, _, err := eval.ToSlotsNew(ctSlots, hefloat.DFTMatrix{})
+		require.Error(t, err)
+
+		_, err = eval.ToCoeffsNew(ctCoeffs, nil, hefloat.DFTMatrix{})
+		require.Error(t, err)
+
+		_, err = eval.ToCoeffsNew(ctSlots, ctCoeffs, hefloat.DFTMatrix{})
+		require.Error(t, err)
+
+		// So are matrices of the wrong type.
+		stc := hefloat.DFTMatrix{DFTMatrixLiteral: hefloat.DFTMatrixLiteral{Type: hefloat.HomomorphicDecode}}
+		_, _, err = eval.ToSlotsNew(ctCoeffs, stc)
+		require.Error(t, err)
+
+		_, err = eval.ToCoeffsNew(ctSlots, nil, hefloat.DFTMatrix{})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,73 @@
+package hefloat
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+)
+
+// This file exposes the homomorphic modular reduction circuit (EvalMod) used
+// by the bootstrapping as a standalone component, so it can be reused for
+// scheme switching or custom refresh procedures without instantiating the
+// whole bootstrapper.
+
+// NewMod1EvaluatorFromLiteral instantiates a Mod1Evaluator directly from a
+// Mod1ParametersLiteral, validating the literal and wiring the internal
+// polynomial evaluator in one call. The circuit is slot-wise and only
+// requires the relinearization key.
+func NewMod1EvaluatorFromLiteral(params Parameters, eval EvaluatorForMod1, literal Mod1ParametersLiteral) (*Mod1Evaluator, error) {
+
+	if literal.Mod1Type == SinContinuous && literal.DoubleAngle != 0 {
+		return nil, fmt.Errorf("cannot NewMod1EvaluatorFromLiteral: cannot use double angle formula for Mod1Type = SinContinuous -> must use Mod1Type = Cos")
+	}
+
+	if literal.Mod1Type == CosDiscrete && literal.Mod1Degree < 2*(literal.K-1) {
+		return nil, fmt.Errorf("cannot NewMod1EvaluatorFromLiteral: Mod1Type 'hefloat.CosDiscrete' uses a minimum degree of 2*(K-1) but Mod1Degree is smaller")
+	}
+
+	mod1Parameters, err := NewMod1ParametersFromLiteral(params, literal)
+	if err != nil {
+		return nil, fmt.Errorf("cannot NewMod1EvaluatorFromLiteral: %w", err)
+	}
+
+	return NewMod1Evaluator(eval, NewPolynomialEvaluator(params, eval), mod1Parameters), nil
+}
+
+// EvaluateNormalizedNew applies the homomorphic modular reduction by q to a
+// ciphertext whose input has not yet been normalized: it first scales ct up
+// to ScalingFactor/MessageRatio (the scale at which the circuit operates),
+// divides it by K*QDiff to map the values into the range of the sine
+// approximation and evaluates the modular reduction, returning the result at
+// the scale at which the circuit operates (ScalingFactor/MessageRatio). The
+// input is expected at scale 2^round(log2(Q[0]))/MessageRatio, the convention
+// of the bootstrapping circuit after the raising step. ct is not modified.
+func (eval Mod1Evaluator) EvaluateNormalizedNew(ct *rlwe.Ciphertext) (ctOut *rlwe.Ciphertext, err error) {
+
+	evm := eval.Mod1Parameters
+
+	ctOut = ct.CopyNew()
+
+	// Scale the message from 2^round(log2(Q[0]))/MessageRatio up to ScalingFactor/MessageRatio
+	if scale := (evm.ScalingFactor().Float64() / evm.MessageRatio()) / ctOut.Scale.Float64(); scale > 1 {
+		if err = eval.ScaleUp(ctOut, rlwe.NewScale(math.Round(scale)), ctOut); err != nil {
+			return nil, fmt.Errorf("cannot EvaluateNormalizedNew: %w", err)
+		}
+	}
+
+	// Normalize by 1/K to the range of the approximation (folded in the
+	// CoeffsToSlots matrices during the bootstrapping)
+	if err = eval.Mul(ctOut, 1/(evm.K()*evm.QDiff()), ctOut); err != nil {
+		return nil, fmt.Errorf("cannot EvaluateNormalizedNew: %w", err)
+	}
+
+	if err = eval.Rescale(ctOut, ctOut); err != nil {
+		return nil, fmt.Errorf("cannot EvaluateNormalizedNew: %w", err)
+	}
+
+	if ctOut, err = eval.EvaluateNew(ctOut); err != nil {
+		return nil, fmt.Errorf("cannot EvaluateNormalizedNew: %w", err)
+	}
+
+	return
+}
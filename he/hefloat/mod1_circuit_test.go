@@ -0,0 +1,100 @@
+package hefloat_test
+
+import (
+	"math"
+	"runtime"
+	"testing"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/he/hefloat"
+	"github.com/luxdefi/lattice/v5/ring"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMod1Standalone(t *testing.T) {
+	var err error
+
+	if runtime.GOARCH == "wasm" {
+		t.Skip("skipping homomorphic mod tests for GOARCH=wasm")
+	}
+
+	// <<<<!Insecure parameters!>>>>
+	var params hefloat.Parameters
+	if params, err = hefloat.NewParametersFromLiteral(hefloat.ParametersLiteral{
+		LogN:            10,
+		LogQ:            []int{55, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 53},
+		LogP:            []int{61, 61, 61, 61, 61},
+		Xs:              ring.Ternary{H: 192},
+		LogDefaultScale: 45,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	kgen := rlwe.NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+	ecd := hefloat.NewEncoder(params)
+	enc := rlwe.NewEncryptor(params, sk)
+	dec := rlwe.NewDecryptor(params, sk)
+	eval := hefloat.NewEvaluator(params, rlwe.NewMemEvaluationKeySet(kgen.GenRelinearizationKeyNew(sk)))
+
+	evm := hefloat.Mod1ParametersLiteral{
+		LevelStart:      12,
+		Mod1Type:        hefloat.CosDiscrete,
+		LogMessageRatio: 8,
+		K:               12,
+		Mod1Degree:      30,
+		DoubleAngle:     3,
+		LogScale:        60,
+	}
+
+	t.Run("EvaluateNormalizedNew", func(t *testing.T) {
+
+		var mod1Parameters hefloat.Mod1Parameters
+		mod1Parameters, err = hefloat.NewMod1ParametersFromLiteral(params, evm)
+		require.NoError(t, err)
+
+		values, _, ciphertext := newTestVectorsMod1(params, enc, ecd, mod1Parameters, t)
+
+		// Scale the message to 2^round(log2(Q[0]))/MessageRatio, the
+		// convention of the bootstrapping circuit after the raising step.
+		scale := rlwe.NewScale(math.Exp2(math.Round(math.Log2(float64(params.Q()[0]) / mod1Parameters.MessageRatio()))))
+		scale = scale.Div(ciphertext.Scale)
+		require.NoError(t, eval.ScaleUp(ciphertext, rlwe.NewScale(math.Round(scale.Float64())), ciphertext))
+
+		var evalMod1 *hefloat.Mod1Evaluator
+		evalMod1, err = hefloat.NewMod1EvaluatorFromLiteral(params, eval, evm)
+		require.NoError(t, err)
+
+		ciphertext, err = evalMod1.EvaluateNormalizedNew(ciphertext)
+		require.NoError(t, err)
+
+		// PlaintextCircuit
+		for i := range values {
+			x := values[i]
+
+			x /= mod1Parameters.MessageRatio()
+			x /= mod1Parameters.QDiff()
+			x = math.Sin(6.28318530717958 * x)
+			x *= mod1Parameters.MessageRatio()
+			x *= mod1Parameters.QDiff()
+			x /= 6.28318530717958
+
+			values[i] = x
+		}
+
+		hefloat.VerifyTestVectors(params, ecd, dec, values, ciphertext, params.LogDefaultScale(), 0, *printPrecisionStats, t)
+	})
+
+	t.Run("InvalidLiterals", func(t *testing.T) {
+
+		sin := evm
+		sin.Mod1Type = hefloat.SinContinuous
+		_, err = hefloat.NewMod1EvaluatorFromLiteral(params, eval, sin)
+		require.Error(t, err)
+
+		lowDegree := evm
+		lowDegree.Mod1Degree = 2*(evm.K-1) - 1
+		_, err = hefloat.NewMod1EvaluatorFromLiteral(params, eval, lowDegree)
+		require.Error(t, err)
+	})
+}
@@ -14,6 +14,7 @@ import (
 type EvaluatorForMod1 interface {
 	he.Evaluator
 	DropLevel(*rlwe.Ciphertext, int)
+	ScaleUp(op0 *rlwe.Ciphertext, scale rlwe.Scale, opOut *rlwe.Ciphertext) (err error)
 	GetParameters() *Parameters
 }
 
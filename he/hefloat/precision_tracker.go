@@ -0,0 +1,393 @@
+package hefloat
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/he"
+	"github.com/luxdefi/lattice/v5/utils"
+	"github.com/luxdefi/lattice/v5/utils/bignum"
+)
+
+// Reserved metadata tag keys under which the PrecisionTracker stores its
+// per-ciphertext bounds (see rlwe.MetaData.SetTag). Since user-defined
+// tags survive serialization, the bounds follow the ciphertexts across
+// process and network boundaries.
+const (
+	// ErrorBoundTag is the metadata tag key storing the absolute
+	// slot-error bound of a ciphertext.
+	ErrorBoundTag = "hefloat/error-bound"
+	// MagnitudeBoundTag is the metadata tag key storing the maximum
+	// slot-magnitude bound of a ciphertext.
+	MagnitudeBoundTag = "hefloat/magnitude-bound"
+)
+
+// SetErrorBound attaches the given absolute slot-error bound to the
+// metadata.
+func SetErrorBound(md *rlwe.MetaData, bound float64) (err error) {
+	return setFloatTag(md, ErrorBoundTag, bound)
+}
+
+// ErrorBound returns the absolute slot-error bound attached to the
+// metadata, or false if none is attached.
+func ErrorBound(md *rlwe.MetaData) (bound float64, ok bool) {
+	return floatTag(md, ErrorBoundTag)
+}
+
+// SetMagnitudeBound attaches the given maximum slot-magnitude bound to
+// the metadata.
+func SetMagnitudeBound(md *rlwe.MetaData, bound float64) (err error) {
+	return setFloatTag(md, MagnitudeBoundTag, bound)
+}
+
+// MagnitudeBound returns the maximum slot-magnitude bound attached to
+// the metadata, or false if none is attached.
+func MagnitudeBound(md *rlwe.MetaData) (bound float64, ok bool) {
+	return floatTag(md, MagnitudeBoundTag)
+}
+
+// PrecisionLowerBound returns a guaranteed lower bound, in bits, on the
+// absolute precision of the tracked values, i.e. -log2 of the attached
+// error bound. It returns false if no error bound is attached.
+func PrecisionLowerBound(md *rlwe.MetaData) (logprec float64, ok bool) {
+	bound, ok := ErrorBound(md)
+	if !ok || bound <= 0 {
+		return 0, ok
+	}
+	return -math.Log2(bound), true
+}
+
+func setFloatTag(md *rlwe.MetaData, key string, v float64) (err error) {
+	buff := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buff, math.Float64bits(v))
+	return md.SetTag(key, buff)
+}
+
+func floatTag(md *rlwe.MetaData, key string) (v float64, ok bool) {
+	buff := md.Tag(key)
+	if len(buff) != 8 {
+		return 0, false
+	}
+	return math.Float64frombits(binary.LittleEndian.Uint64(buff)), true
+}
+
+// PrecisionTracker wraps an Evaluator and propagates per-ciphertext
+// error bounds through the operations using interval arithmetic, so that
+// the numerical accuracy of an encrypted result can be certified at
+// decryption (see PrecisionLowerBound).
+//
+// Each tracked ciphertext carries two bounds: the maximum magnitude M of
+// its slot values and the maximum absolute error E between its slot
+// values and the exact (unencrypted) computation. Both are worst-case
+// bounds, propagated as:
+//   - Add/Sub: M = M0 + M1 and E = E0 + E1.
+//   - Mul/MulRelin: M = M0*M1 and E = M0*E1 + M1*E0 + E0*E1, plus a
+//     relinearization noise term for MulRelin.
+//   - Rescale: E grows by the worst-case rounding error of the division
+//     by the dropped modulus.
+//   - Polynomial: interval arithmetic over the coefficients, see the
+//     Polynomial method.
+//
+// The scheme noise terms assume the worst-case coefficient-domain bounds
+// rlwe.Parameters.NoiseBound for the fresh noise and the Hamming weight
+// of the secret for the rounding terms, inflated by the slot count for
+// the coefficient-to-slot transformation. The resulting bounds are loose
+// but guaranteed; untracked operands (without attached bounds) cause the
+// output bounds to be cleared instead of silently becoming stale.
+type PrecisionTracker struct {
+	*Evaluator
+}
+
+// NewPrecisionTracker instantiates a new PrecisionTracker wrapping the
+// given Evaluator.
+func NewPrecisionTracker(eval *Evaluator) *PrecisionTracker {
+	return &PrecisionTracker{Evaluator: eval}
+}
+
+// Track attaches initial bounds to a freshly encrypted ciphertext whose
+// slot values have magnitude at most the given bound. The initial error
+// bound accounts for the encoding rounding and the fresh encryption
+// noise.
+func (t PrecisionTracker) Track(ct *rlwe.Ciphertext, magnitude float64) (err error) {
+
+	params := t.GetParameters()
+
+	e := float64(params.N()) * (0.5 + params.NoiseBound()) / ct.Scale.Float64()
+
+	if err = SetMagnitudeBound(ct.MetaData, magnitude); err != nil {
+		return fmt.Errorf("cannot Track: %w", err)
+	}
+
+	if err = SetErrorBound(ct.MetaData, e); err != nil {
+		return fmt.Errorf("cannot Track: %w", err)
+	}
+
+	return
+}
+
+// Add adds op1 to op0 and returns the result in opOut, propagating the
+// attached bounds (see Evaluator.Add).
+func (t PrecisionTracker) Add(op0 *rlwe.Ciphertext, op1 rlwe.Operand, opOut *rlwe.Ciphertext) (err error) {
+
+	e0, m0, ok0 := bounds(op0.MetaData)
+	e1, m1, ok1 := operandBounds(op1)
+
+	if err = t.Evaluator.Add(op0, op1, opOut); err != nil {
+		return
+	}
+
+	return t.setBounds(opOut, e0+e1, m0+m1, ok0 && ok1)
+}
+
+// Sub subtracts op1 from op0 and returns the result in opOut,
+// propagating the attached bounds (see Evaluator.Sub).
+func (t PrecisionTracker) Sub(op0 *rlwe.Ciphertext, op1 rlwe.Operand, opOut *rlwe.Ciphertext) (err error) {
+
+	e0, m0, ok0 := bounds(op0.MetaData)
+	e1, m1, ok1 := operandBounds(op1)
+
+	if err = t.Evaluator.Sub(op0, op1, opOut); err != nil {
+		return
+	}
+
+	return t.setBounds(opOut, e0+e1, m0+m1, ok0 && ok1)
+}
+
+// Mul multiplies op0 with op1 without relinearization and returns the
+// result in opOut, propagating the attached bounds (see Evaluator.Mul).
+func (t PrecisionTracker) Mul(op0 *rlwe.Ciphertext, op1 rlwe.Operand, opOut *rlwe.Ciphertext) (err error) {
+
+	e0, m0, ok0 := bounds(op0.MetaData)
+	e1, m1, ok1 := operandBounds(op1)
+
+	if err = t.Evaluator.Mul(op0, op1, opOut); err != nil {
+		return
+	}
+
+	return t.setBounds(opOut, m0*e1+m1*e0+e0*e1, m0*m1, ok0 && ok1)
+}
+
+// MulRelin multiplies op0 with op1 with relinearization and returns the
+// result in opOut, propagating the attached bounds (see
+// Evaluator.MulRelin).
+func (t PrecisionTracker) MulRelin(op0 *rlwe.Ciphertext, op1 rlwe.Operand, opOut *rlwe.Ciphertext) (err error) {
+
+	e0, m0, ok0 := bounds(op0.MetaData)
+	e1, m1, ok1 := operandBounds(op1)
+
+	if err = t.Evaluator.MulRelin(op0, op1, opOut); err != nil {
+		return
+	}
+
+	e := m0*e1 + m1*e0 + e0*e1 + t.keySwitchErrorBound(opOut)
+
+	return t.setBounds(opOut, e, m0*m1, ok0 && ok1)
+}
+
+// Rescale divides op0 by the last modulus and returns the result in
+// opOut, propagating the attached bounds (see Evaluator.Rescale).
+func (t PrecisionTracker) Rescale(op0, opOut *rlwe.Ciphertext) (err error) {
+
+	e0, m0, ok0 := bounds(op0.MetaData)
+
+	if err = t.Evaluator.Rescale(op0, opOut); err != nil {
+		return
+	}
+
+	return t.setBounds(opOut, e0+t.rescaleErrorBound(opOut), m0, ok0)
+}
+
+// Polynomial evaluates the polynomial p on ct and returns the result in
+// a new ciphertext, propagating the attached bounds (see
+// PolynomialEvaluator.Evaluate). The accepted polynomial types are the
+// ones of PolynomialEvaluator.Evaluate.
+//
+// For polynomials in the monomial basis, the output bounds are computed
+// by interval arithmetic over the coefficients. For polynomials in the
+// Chebyshev basis, the caller must guarantee that the slot values lie in
+// the approximation interval, in which case the basis polynomials have
+// magnitude at most 1 and derivative at most i^2 on the (normalized)
+// interval. A rounding term per rescaling of the evaluation is added to
+// the error bound.
+func (t PrecisionTracker) Polynomial(ct *rlwe.Ciphertext, p interface{}, targetScale rlwe.Scale) (opOut *rlwe.Ciphertext, err error) {
+
+	e0, m0, ok0 := bounds(ct.MetaData)
+
+	params := *t.GetParameters()
+
+	if opOut, err = NewPolynomialEvaluator(params, t.Evaluator).Evaluate(ct, p, targetScale); err != nil {
+		return nil, err
+	}
+
+	if !ok0 {
+		return opOut, t.setBounds(opOut, 0, 0, false)
+	}
+
+	polys, depth, err := polynomialList(p)
+	if err != nil {
+		return nil, fmt.Errorf("cannot Polynomial: %w", err)
+	}
+
+	var e, m float64
+	for _, pol := range polys {
+		ei, mi := polynomialBounds(pol, e0, m0)
+		e = math.Max(e, ei)
+		m = math.Max(m, mi)
+	}
+
+	e += float64(depth) * t.rescaleErrorBound(opOut)
+
+	return opOut, t.setBounds(opOut, e, m, true)
+}
+
+// keySwitchErrorBound returns the worst-case slot-error contribution of
+// a key-switching operation at the scale of the given ciphertext.
+func (t PrecisionTracker) keySwitchErrorBound(ct *rlwe.Ciphertext) float64 {
+	params := t.GetParameters()
+	return float64(params.N()) * params.NoiseBound() / ct.Scale.Float64()
+}
+
+// rescaleErrorBound returns the worst-case slot-error contribution of a
+// rescaling at the scale of the given ciphertext.
+func (t PrecisionTracker) rescaleErrorBound(ct *rlwe.Ciphertext) float64 {
+	params := t.GetParameters()
+	return float64(params.N()) * 0.5 * float64(1+params.XsHammingWeight()) / ct.Scale.Float64()
+}
+
+// setBounds attaches the given bounds to the ciphertext, or clears them
+// if the inputs were not tracked.
+func (t PrecisionTracker) setBounds(ct *rlwe.Ciphertext, e, m float64, tracked bool) (err error) {
+
+	if !tracked {
+		ct.DeleteTag(ErrorBoundTag)
+		ct.DeleteTag(MagnitudeBoundTag)
+		return
+	}
+
+	if err = SetErrorBound(ct.MetaData, e); err != nil {
+		return
+	}
+
+	return SetMagnitudeBound(ct.MetaData, m)
+}
+
+// bounds returns the error and magnitude bounds attached to the
+// metadata.
+func bounds(md *rlwe.MetaData) (e, m float64, ok bool) {
+	var okE, okM bool
+	e, okE = ErrorBound(md)
+	m, okM = MagnitudeBound(md)
+	return e, m, okE && okM
+}
+
+// operandBounds returns the error and magnitude bounds of an operand.
+// Scalar operands are exact; operands carrying metadata are looked up
+// for attached bounds.
+func operandBounds(op rlwe.Operand) (e, m float64, ok bool) {
+	switch op := op.(type) {
+	case *rlwe.Ciphertext:
+		return bounds(op.MetaData)
+	case *rlwe.Plaintext:
+		return bounds(op.MetaData)
+	case complex128:
+		return 0, cmplxAbs(op), true
+	case float64:
+		return 0, math.Abs(op), true
+	case int:
+		return 0, math.Abs(float64(op)), true
+	case int64:
+		return 0, math.Abs(float64(op)), true
+	case uint64:
+		return 0, float64(op), true
+	case *big.Float:
+		f, _ := op.Float64()
+		return 0, math.Abs(f), true
+	case *big.Int:
+		f, _ := new(big.Float).SetInt(op).Float64()
+		return 0, math.Abs(f), true
+	case *bignum.Complex:
+		return 0, complexAbs(op), true
+	default:
+		return 0, 0, false
+	}
+}
+
+func cmplxAbs(c complex128) float64 {
+	return math.Hypot(real(c), imag(c))
+}
+
+func complexAbs(c *bignum.Complex) float64 {
+	re, _ := c.Real().Float64()
+	im, _ := c.Imag().Float64()
+	return math.Hypot(re, im)
+}
+
+// polynomialList normalizes the accepted polynomial types to a list of
+// bignum.Polynomial and the evaluation depth.
+func polynomialList(p interface{}) (polys []bignum.Polynomial, depth int, err error) {
+	switch p := p.(type) {
+	case bignum.Polynomial:
+		polys = []bignum.Polynomial{p}
+	case Polynomial:
+		polys = []bignum.Polynomial{p.Polynomial}
+	case he.Polynomial:
+		polys = []bignum.Polynomial{p.Polynomial}
+	case PolynomialVector:
+		for _, pol := range p.Value {
+			polys = append(polys, pol.Polynomial)
+		}
+	case he.PolynomialVector:
+		for _, pol := range p.Value {
+			polys = append(polys, pol.Polynomial)
+		}
+	default:
+		return nil, 0, fmt.Errorf("invalid polynomial type: %T", p)
+	}
+
+	for _, pol := range polys {
+		depth = utils.Max(depth, pol.Depth())
+	}
+
+	return
+}
+
+// polynomialBounds evaluates the error and magnitude bounds of the
+// polynomial pol on slot values of magnitude at most m with error at
+// most e, by interval arithmetic over the coefficients.
+func polynomialBounds(pol bignum.Polynomial, e, m float64) (eOut, mOut float64) {
+
+	switch pol.Basis {
+	case bignum.Chebyshev:
+
+		a, _ := pol.A.Float64()
+		b, _ := pol.B.Float64()
+
+		// Error on the variable normalized to [-1, 1], on which
+		// |T_i| <= 1 and |T_i'| <= i^2.
+		eNorm := 2 * e / (b - a)
+
+		for i, c := range pol.Coeffs {
+			if c == nil {
+				continue
+			}
+			abs := complexAbs(c)
+			mOut += abs
+			eOut += abs * math.Min(2, float64(i*i)*eNorm)
+		}
+
+	default:
+		for i, c := range pol.Coeffs {
+			if c == nil {
+				continue
+			}
+			abs := complexAbs(c)
+			mOut += abs * math.Pow(m, float64(i))
+			eOut += abs * (math.Pow(m+e, float64(i)) - math.Pow(m, float64(i)))
+		}
+	}
+
+	return
+}
@@ -0,0 +1,172 @@
+package hefloat_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/he/hefloat"
+	"github.com/luxdefi/lattice/v5/utils/bignum"
+	"github.com/luxdefi/lattice/v5/utils/sampling"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrecisionTracker(t *testing.T) {
+
+	params, err := hefloat.NewParametersFromLiteral(testInsecurePrec45)
+	require.NoError(t, err)
+
+	kgen := rlwe.NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+	evk := rlwe.NewMemEvaluationKeySet(kgen.GenRelinearizationKeyNew(sk))
+
+	ecd := hefloat.NewEncoder(params)
+	enc := rlwe.NewEncryptor(params, sk)
+	dec := rlwe.NewDecryptor(params, sk)
+
+	tracker := hefloat.NewPrecisionTracker(hefloat.NewEvaluator(params, evk))
+
+	values := make([]float64, params.MaxSlots())
+	for i := range values {
+		values[i] = sampling.RandFloat64(-1, 1)
+	}
+
+	// maxErr decrypts ct and returns the maximum absolute error with
+	// respect to want.
+	maxErr := func(ct *rlwe.Ciphertext, want []float64) (err float64) {
+		have := make([]float64, len(want))
+		require.NoError(t, ecd.Decode(dec.DecryptNew(ct), have))
+		for i := range want {
+			err = math.Max(err, math.Abs(want[i]-have[i]))
+		}
+		return
+	}
+
+	newTrackedCiphertext := func() (ct *rlwe.Ciphertext) {
+		pt := hefloat.NewPlaintext(params, params.MaxLevel())
+		require.NoError(t, ecd.Encode(values, pt))
+		ct, err := enc.EncryptNew(pt)
+		require.NoError(t, err)
+		require.NoError(t, tracker.Track(ct, 1))
+		return
+	}
+
+	t.Run("Fresh", func(t *testing.T) {
+
+		ct := newTrackedCiphertext()
+
+		bound, ok := hefloat.ErrorBound(ct.MetaData)
+		require.True(t, ok)
+		require.GreaterOrEqual(t, bound, maxErr(ct, values))
+
+		logprec, ok := hefloat.PrecisionLowerBound(ct.MetaData)
+		require.True(t, ok)
+		require.Greater(t, logprec, 0.0)
+	})
+
+	t.Run("MulRelinRescale", func(t *testing.T) {
+
+		ct := newTrackedCiphertext()
+		ctOut := ct.CopyNew()
+
+		require.NoError(t, tracker.MulRelin(ct, ct, ctOut))
+		require.NoError(t, tracker.Rescale(ctOut, ctOut))
+
+		want := make([]float64, len(values))
+		for i := range want {
+			want[i] = values[i] * values[i]
+		}
+
+		bound, ok := hefloat.ErrorBound(ctOut.MetaData)
+		require.True(t, ok)
+		require.GreaterOrEqual(t, bound, maxErr(ctOut, want))
+
+		// The error bound of the product is larger than the fresh one.
+		fresh, _ := hefloat.ErrorBound(ct.MetaData)
+		require.Greater(t, bound, fresh)
+
+		magnitude, ok := hefloat.MagnitudeBound(ctOut.MetaData)
+		require.True(t, ok)
+		require.GreaterOrEqual(t, magnitude, 1.0)
+	})
+
+	t.Run("AddScalar", func(t *testing.T) {
+
+		ct := newTrackedCiphertext()
+		ctOut := ct.CopyNew()
+
+		require.NoError(t, tracker.Add(ct, 0.5, ctOut))
+
+		want := make([]float64, len(values))
+		for i := range want {
+			want[i] = values[i] + 0.5
+		}
+
+		bound, ok := hefloat.ErrorBound(ctOut.MetaData)
+		require.True(t, ok)
+		require.GreaterOrEqual(t, bound, maxErr(ctOut, want))
+
+		magnitude, ok := hefloat.MagnitudeBound(ctOut.MetaData)
+		require.True(t, ok)
+		require.GreaterOrEqual(t, magnitude, 1.5)
+	})
+
+	t.Run("Polynomial", func(t *testing.T) {
+
+		ct := newTrackedCiphertext()
+
+		poly := bignum.NewPolynomial(bignum.Monomial, []float64{1, 1, 0.5}, nil)
+
+		ctOut, err := tracker.Polynomial(ct, poly, params.DefaultScale())
+		require.NoError(t, err)
+
+		want := make([]float64, len(values))
+		for i := range want {
+			want[i] = 1 + values[i] + 0.5*values[i]*values[i]
+		}
+
+		bound, ok := hefloat.ErrorBound(ctOut.MetaData)
+		require.True(t, ok)
+		require.GreaterOrEqual(t, bound, maxErr(ctOut, want))
+
+		magnitude, ok := hefloat.MagnitudeBound(ctOut.MetaData)
+		require.True(t, ok)
+		require.GreaterOrEqual(t, magnitude, 2.5)
+	})
+
+	t.Run("Untracked", func(t *testing.T) {
+
+		pt := hefloat.NewPlaintext(params, params.MaxLevel())
+		require.NoError(t, ecd.Encode(values, pt))
+		ct, err := enc.EncryptNew(pt)
+		require.NoError(t, err)
+
+		ctOut := newTrackedCiphertext()
+
+		// An untracked operand clears the bounds of the output.
+		require.NoError(t, tracker.Add(ct, ctOut.CopyNew(), ctOut))
+
+		_, ok := hefloat.ErrorBound(ctOut.MetaData)
+		require.False(t, ok)
+		_, ok = hefloat.MagnitudeBound(ctOut.MetaData)
+		require.False(t, ok)
+	})
+
+	t.Run("Serialization", func(t *testing.T) {
+
+		ct := newTrackedCiphertext()
+
+		data, err := ct.MarshalBinary()
+		require.NoError(t, err)
+
+		ctOut := new(rlwe.Ciphertext)
+		require.NoError(t, ctOut.UnmarshalBinary(data))
+
+		boundIn, ok := hefloat.ErrorBound(ct.MetaData)
+		require.True(t, ok)
+		boundOut, ok := hefloat.ErrorBound(ctOut.MetaData)
+		require.True(t, ok)
+		require.Equal(t, boundIn, boundOut)
+	})
+}
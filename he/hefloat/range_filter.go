@@ -0,0 +1,78 @@
+package hefloat
+
+import (
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/ring"
+)
+
+// This file implements the encrypted range-query filter circuit: given
+// encrypted values and lower/upper bounds, it produces an encrypted 0/1
+// indicator per slot evaluating a < value < b. The bounds can be plaintext
+// (see ComparisonEvaluator.MaskInInterval) or encrypted (see
+// ComparisonEvaluator.MaskInIntervalEncrypted), and the resulting mask can be
+// aggregated (e.g. with statistics helpers) or applied to other columns with
+// ComparisonEvaluator.Select.
+
+// MaskInIntervalEncrypted returns an encrypted mask evaluating m = 1 if
+// a < op0 < b, else 0 (and 0.5 on the boundaries), computed as
+// step((op0-a)/2) - step((op0-b)/2), with the boundaries a and b themselves
+// encrypted, slot-wise.
+// Use must ensure that:
+//   - op0, a and b hold values in the interval [-1, 1].
+//   - op0.Scale = a.Scale = b.Scale.
+//
+// This method ensures that mask.Scale = params.DefaultScale.
+func (eval ComparisonEvaluator) MaskInIntervalEncrypted(op0, a, b *rlwe.Ciphertext) (mask *rlwe.Ciphertext, err error) {
+
+	var stepA, stepB *rlwe.Ciphertext
+
+	if stepA, err = eval.stepAboveEncryptedThreshold(op0, a); err != nil {
+		return
+	}
+
+	if stepB, err = eval.stepAboveEncryptedThreshold(op0, b); err != nil {
+		return
+	}
+
+	if err = eval.Sub(stepA, stepB, stepA); err != nil {
+		return
+	}
+
+	return stepA, nil
+}
+
+// stepAboveEncryptedThreshold evaluates the step function on (op0-t)/2, i.e.
+// an approximation of 1 if op0 > t, 0 if op0 < t, with an encrypted
+// threshold. The halving maps the difference back into the domain [-1, 1] of
+// the step function.
+func (eval ComparisonEvaluator) stepAboveEncryptedThreshold(op0, t *rlwe.Ciphertext) (step *rlwe.Ciphertext, err error) {
+
+	var shifted *rlwe.Ciphertext
+	if shifted, err = eval.SubNew(op0, t); err != nil {
+		return
+	}
+
+	if err = eval.Mul(shifted, 0.5, shifted); err != nil {
+		return
+	}
+
+	if err = eval.Rescale(shifted, shifted); err != nil {
+		return
+	}
+
+	return eval.Step(shifted)
+}
+
+// GaloisElementsForRangeFilter returns the list of Galois elements required
+// to evaluate the range filter circuit. The circuit is purely slot-wise, so
+// no rotation keys are required: the list is empty for the conjugate
+// invariant ring and holds the sole complex conjugation element for the
+// standard ring, which the underlying polynomial evaluation of the step
+// function uses to cancel the imaginary part. The evaluation additionally
+// requires the relinearization key.
+func GaloisElementsForRangeFilter(params Parameters) []uint64 {
+	if params.RingType() == ring.Standard {
+		return []uint64{params.GaloisElementForComplexConjugation()}
+	}
+	return nil
+}
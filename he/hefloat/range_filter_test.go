@@ -0,0 +1,101 @@
+package hefloat_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/he/hefloat"
+	"github.com/luxdefi/lattice/v5/he/hefloat/bootstrapping"
+	"github.com/luxdefi/lattice/v5/ring"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRangeFilter(t *testing.T) {
+
+	paramsLiteral := testInsecurePrec90
+
+	if testing.Short() {
+		paramsLiteral.LogN = 10
+	}
+
+	params, err := hefloat.NewParametersFromLiteral(paramsLiteral)
+	require.NoError(t, err)
+
+	var tc *testContext
+	if tc, err = genTestParams(params); err != nil {
+		t.Fatal(err)
+	}
+
+	enc := tc.encryptorSk
+	sk := tc.sk
+	ecd := tc.encoder
+	dec := tc.decryptor
+	kgen := tc.kgen
+
+	btp := bootstrapping.NewSecretKeyBootstrapper(params, sk)
+
+	// The range filter circuit is slot-wise: only the relinearization key and
+	// the reported Galois elements are required.
+	galEls := hefloat.GaloisElementsForRangeFilter(params)
+	require.Equal(t, []uint64{params.GaloisElementForComplexConjugation()}, galEls)
+
+	eval := tc.evaluator.WithKey(rlwe.NewMemEvaluationKeySet(kgen.GenRelinearizationKeyNew(sk), kgen.GenGaloisKeysNew(galEls, sk)...))
+
+	CmpEval := hefloat.NewComparisonEvaluator(params, eval, btp)
+
+	t.Run(GetTestName(params, "MaskInIntervalEncrypted"), func(t *testing.T) {
+
+		a, b := -0.5, 0.3
+
+		values, _, ct := newTestVectors(tc, enc, complex(-1, 0), complex(1, 0), t)
+
+		bound := func(v float64) *rlwe.Ciphertext {
+			boundValues := make([]float64, params.MaxSlots())
+			for i := range boundValues {
+				boundValues[i] = v
+			}
+			pt := hefloat.NewPlaintext(params, params.MaxLevel())
+			require.NoError(t, ecd.Encode(boundValues, pt))
+			ctBound, err := enc.EncryptNew(pt)
+			require.NoError(t, err)
+			return ctBound
+		}
+
+		var mask *rlwe.Ciphertext
+		mask, err = CmpEval.MaskInIntervalEncrypted(ct, bound(a), bound(b))
+		require.NoError(t, err)
+
+		have := make([]*big.Float, params.MaxSlots())
+
+		require.NoError(t, ecd.Decode(dec.DecryptNew(mask), have))
+
+		bigA := new(big.Float).SetFloat64(a)
+		bigB := new(big.Float).SetFloat64(b)
+
+		for i := range have {
+
+			want := 0.0
+			if values[i][0].Cmp(bigA) == 1 && values[i][0].Cmp(bigB) == -1 {
+				want = 1
+			}
+
+			haveF64, _ := have[i].Float64()
+
+			require.InDelta(t, want, haveF64, 1e-3)
+		}
+	})
+
+	t.Run("GaloisElements/ConjugateInvariant", func(t *testing.T) {
+
+		paramsLiteral := testInsecurePrec90
+		paramsLiteral.LogN = 10
+		paramsLiteral.RingType = ring.ConjugateInvariant
+
+		paramsCI, err := hefloat.NewParametersFromLiteral(paramsLiteral)
+		require.NoError(t, err)
+
+		require.Empty(t, hefloat.GaloisElementsForRangeFilter(paramsCI))
+	})
+}
@@ -0,0 +1,113 @@
+package hefloat_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxdefi/lattice/v5/he/hefloat"
+	"github.com/luxdefi/lattice/v5/utils/buffer"
+)
+
+func TestSession(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := hefloat.NewParametersFromLiteral(hefloat.ParametersLiteral{
+		LogN:            10,
+		LogQ:            []int{55, 45, 45},
+		LogP:            []int{60},
+		LogDefaultScale: 45,
+	})
+	require.NoError(t, err)
+
+	s := hefloat.NewSession(params, params.GaloisElement(1))
+
+	slots := params.MaxSlots()
+	values := make([]float64, slots)
+	for i := range values {
+		values[i] = float64(i) / float64(slots)
+	}
+
+	ct, err := s.EncryptNew(values)
+	require.NoError(t, err)
+
+	// The session evaluators use the bundled relinearization and Galois keys.
+	require.NoError(t, s.WithEvaluator(func(eval *hefloat.Evaluator) (err error) {
+		if err = eval.MulRelin(ct, ct, ct); err != nil {
+			return
+		}
+		if err = eval.Rescale(ct, ct); err != nil {
+			return
+		}
+		return eval.Rotate(ct, 1, ct)
+	}))
+
+	have := make([]float64, slots)
+	require.NoError(t, s.Decrypt(ct, have))
+
+	for i := range have {
+		want := values[(i+1)%slots] * values[(i+1)%slots]
+		require.InDelta(t, want, have[i], 1e-3)
+	}
+
+	// Evaluators are recycled through the pool.
+	eval := s.GetEvaluator()
+	s.PutEvaluator(eval)
+	require.Same(t, eval, s.GetEvaluator())
+	s.PutEvaluator(eval)
+
+	// Shallow copies are safe to use concurrently.
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			sc := s.ShallowCopy()
+
+			ct, err := sc.EncryptNew(values)
+			if err != nil {
+				errs[w] = err
+				return
+			}
+
+			if errs[w] = sc.WithEvaluator(func(eval *hefloat.Evaluator) error {
+				return eval.Rotate(ct, 1, ct)
+			}); errs[w] != nil {
+				return
+			}
+
+			have := make([]float64, slots)
+			errs[w] = sc.Decrypt(ct, have)
+		}(w)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	// The session round-trips through its serialization.
+	buf := buffer.NewBufferSize(s.BinarySize())
+	n, err := s.WriteTo(buf)
+	require.NoError(t, err)
+	require.Equal(t, int64(s.BinarySize()), n)
+
+	loaded := new(hefloat.Session)
+	_, err = loaded.ReadFrom(buffer.NewBuffer(buf.Bytes()))
+	require.NoError(t, err)
+
+	require.True(t, loaded.Params.Equal(&s.Params))
+	require.True(t, loaded.SecretKey.Equal(s.SecretKey))
+	require.True(t, loaded.EvaluationKeys.Equal(s.EvaluationKeys))
+
+	require.NoError(t, loaded.Decrypt(ct, have))
+
+	// An evaluation-only session encrypts with the public key and cannot
+	// decrypt.
+	public := hefloat.NewSessionFromKeys(params, nil, s.PublicKey, s.EvaluationKeys)
+	_, err = public.EncryptNew(values)
+	require.NoError(t, err)
+	require.Error(t, public.Decrypt(ct, have))
+}
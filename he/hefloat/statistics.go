@@ -0,0 +1,228 @@
+package hefloat
+
+import (
+	"fmt"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+)
+
+// StatisticsEvaluator is an evaluator providing an API for common aggregate
+// statistics over a ciphertext packing one sample per slot: sums, masked
+// partial sums, mean, variance via sum-of-squares, and interval counts and
+// histograms via comparison circuits.
+//
+// Aggregations are computed with InnerSum over the first n slots: the
+// aggregate over the slots [0, n) is returned in the slot 0 (and more
+// generally the slot i holds the aggregate over the slots [i, i+n)). The
+// required rotation keys can be generated from GaloisElementsForStatistics.
+//
+// The counting circuits approximate the indicator of an interval with the
+// smooth step function of the ComparisonEvaluator, and thus inherit its
+// requirements: samples and interval boundaries must lie in [-1, 1] and
+// samples too close to a boundary are counted fractionally.
+//
+// All fields of this struct are public, enabling custom instantiations.
+type StatisticsEvaluator struct {
+	*Evaluator
+	// Comparisons evaluates the step functions used by Count and Histogram.
+	// It can be nil if only the arithmetic statistics are used.
+	Comparisons *ComparisonEvaluator
+}
+
+// NewStatisticsEvaluator instantiates a new StatisticsEvaluator.
+// The ComparisonEvaluator is only used by Count and Histogram and can be nil
+// if only the arithmetic statistics are used.
+func NewStatisticsEvaluator(eval *Evaluator, cmp *ComparisonEvaluator) *StatisticsEvaluator {
+	return &StatisticsEvaluator{Evaluator: eval, Comparisons: cmp}
+}
+
+// GaloisElementsForStatistics returns the list of Galois elements required to
+// aggregate n slots with the methods of the StatisticsEvaluator.
+func GaloisElementsForStatistics(params Parameters, n int) []uint64 {
+	return params.GaloisElementsForInnerSum(1, n)
+}
+
+// Sum returns the sum of the n first slots of op0 in the slot 0 of the result.
+func (eval *StatisticsEvaluator) Sum(op0 *rlwe.Ciphertext, n int) (sum *rlwe.Ciphertext, err error) {
+	sum = op0.CopyNew()
+	if err = eval.InnerSum(sum, 1, n, sum); err != nil {
+		return nil, fmt.Errorf("cannot Sum: %w", err)
+	}
+	return
+}
+
+// MaskedSum returns the sum of the slots of op0 selected by the mask in the
+// slot 0 of the result. The mask is a plaintext vector of n slots, typically
+// holding 0s and 1s to select a subgroup of the samples, but arbitrary
+// weights (e.g. for a weighted sum) are valid. The masking consumes one
+// level.
+func (eval *StatisticsEvaluator) MaskedSum(op0 *rlwe.Ciphertext, mask []float64, n int) (sum *rlwe.Ciphertext, err error) {
+
+	masked, err := eval.MulNew(op0, mask)
+	if err != nil {
+		return nil, fmt.Errorf("cannot MaskedSum: %w", err)
+	}
+
+	if err = eval.Rescale(masked, masked); err != nil {
+		return nil, fmt.Errorf("cannot MaskedSum: %w", err)
+	}
+
+	if err = eval.InnerSum(masked, 1, n, masked); err != nil {
+		return nil, fmt.Errorf("cannot MaskedSum: %w", err)
+	}
+
+	return masked, nil
+}
+
+// Mean returns the mean of the n first slots of op0 in the slot 0 of the
+// result. The method consumes one level.
+func (eval *StatisticsEvaluator) Mean(op0 *rlwe.Ciphertext, n int) (mean *rlwe.Ciphertext, err error) {
+
+	if mean, err = eval.Sum(op0, n); err != nil {
+		return nil, fmt.Errorf("cannot Mean: %w", err)
+	}
+
+	if err = eval.Mul(mean, 1/float64(n), mean); err != nil {
+		return nil, fmt.Errorf("cannot Mean: %w", err)
+	}
+
+	if err = eval.Rescale(mean, mean); err != nil {
+		return nil, fmt.Errorf("cannot Mean: %w", err)
+	}
+
+	return
+}
+
+// Variance returns the population variance of the n first slots of op0 in the
+// slot 0 of the result, computed as E[x^2] - E[x]^2. The mean, which is
+// obtained as a by-product, is also returned. The method consumes two levels.
+func (eval *StatisticsEvaluator) Variance(op0 *rlwe.Ciphertext, n int) (variance, mean *rlwe.Ciphertext, err error) {
+
+	if mean, err = eval.Mean(op0, n); err != nil {
+		return nil, nil, fmt.Errorf("cannot Variance: %w", err)
+	}
+
+	// E[x^2]
+	var sq *rlwe.Ciphertext
+	if sq, err = eval.MulRelinNew(op0, op0); err != nil {
+		return nil, nil, fmt.Errorf("cannot Variance: %w", err)
+	}
+
+	if err = eval.Rescale(sq, sq); err != nil {
+		return nil, nil, fmt.Errorf("cannot Variance: %w", err)
+	}
+
+	if variance, err = eval.Mean(sq, n); err != nil {
+		return nil, nil, fmt.Errorf("cannot Variance: %w", err)
+	}
+
+	// E[x]^2
+	var meanSq *rlwe.Ciphertext
+	if meanSq, err = eval.MulRelinNew(mean, mean); err != nil {
+		return nil, nil, fmt.Errorf("cannot Variance: %w", err)
+	}
+
+	if err = eval.Rescale(meanSq, meanSq); err != nil {
+		return nil, nil, fmt.Errorf("cannot Variance: %w", err)
+	}
+
+	if err = eval.Sub(variance, meanSq, variance); err != nil {
+		return nil, nil, fmt.Errorf("cannot Variance: %w", err)
+	}
+
+	return
+}
+
+// Count returns the number of samples among the n first slots of op0 lying in
+// the interval (a, b) in the slot 0 of the result. Samples and boundaries
+// must lie in [-1, 1]; a sample within the transition width of the step
+// function around a boundary contributes fractionally to the count.
+func (eval *StatisticsEvaluator) Count(op0 *rlwe.Ciphertext, a, b float64, n int) (count *rlwe.Ciphertext, err error) {
+
+	if eval.Comparisons == nil {
+		return nil, fmt.Errorf("cannot Count: evaluator was instantiated without a ComparisonEvaluator")
+	}
+
+	stepA, err := eval.stepAboveThreshold(op0, a)
+	if err != nil {
+		return nil, fmt.Errorf("cannot Count: %w", err)
+	}
+
+	stepB, err := eval.stepAboveThreshold(op0, b)
+	if err != nil {
+		return nil, fmt.Errorf("cannot Count: %w", err)
+	}
+
+	// Indicator of (a, b): step(x-a) - step(x-b)
+	if err = eval.Sub(stepA, stepB, stepA); err != nil {
+		return nil, fmt.Errorf("cannot Count: %w", err)
+	}
+
+	if count, err = eval.Sum(stepA, n); err != nil {
+		return nil, fmt.Errorf("cannot Count: %w", err)
+	}
+
+	return
+}
+
+// Histogram returns, for each of the len(boundaries)-1 buckets delimited by
+// the sorted boundaries, the number of samples among the n first slots of op0
+// lying in the bucket, each count in the slot 0 of the corresponding result.
+// The step function is evaluated once per boundary and shared between the two
+// buckets it delimits. Samples and boundaries must lie in [-1, 1]; a sample
+// within the transition width of the step function around a boundary is split
+// fractionally between the two adjacent buckets.
+func (eval *StatisticsEvaluator) Histogram(op0 *rlwe.Ciphertext, boundaries []float64, n int) (counts []*rlwe.Ciphertext, err error) {
+
+	if eval.Comparisons == nil {
+		return nil, fmt.Errorf("cannot Histogram: evaluator was instantiated without a ComparisonEvaluator")
+	}
+
+	if len(boundaries) < 2 {
+		return nil, fmt.Errorf("cannot Histogram: at least two boundaries are required")
+	}
+
+	steps := make([]*rlwe.Ciphertext, len(boundaries))
+	for i := range boundaries {
+		if steps[i], err = eval.stepAboveThreshold(op0, boundaries[i]); err != nil {
+			return nil, fmt.Errorf("cannot Histogram: %w", err)
+		}
+	}
+
+	counts = make([]*rlwe.Ciphertext, len(boundaries)-1)
+	for i := range counts {
+
+		// Indicator of the bucket [boundaries[i], boundaries[i+1])
+		var indicator *rlwe.Ciphertext
+		if indicator, err = eval.SubNew(steps[i], steps[i+1]); err != nil {
+			return nil, fmt.Errorf("cannot Histogram: %w", err)
+		}
+
+		if counts[i], err = eval.Sum(indicator, n); err != nil {
+			return nil, fmt.Errorf("cannot Histogram: %w", err)
+		}
+	}
+
+	return
+}
+
+// stepAboveThreshold evaluates the smooth step function on (op0-t)/2, i.e. an
+// approximation of 1 if x > t, 0 if x < t. The halving maps the difference
+// back into the domain [-1, 1] of the step function.
+func (eval *StatisticsEvaluator) stepAboveThreshold(op0 *rlwe.Ciphertext, t float64) (step *rlwe.Ciphertext, err error) {
+
+	shifted, err := eval.SubNew(op0, t)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = eval.Mul(shifted, 0.5, shifted); err != nil {
+		return nil, err
+	}
+
+	if err = eval.Rescale(shifted, shifted); err != nil {
+		return nil, err
+	}
+
+	return eval.Comparisons.Step(shifted)
+}
@@ -0,0 +1,221 @@
+package hefloat_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/he/hefloat"
+	"github.com/luxdefi/lattice/v5/he/hefloat/bootstrapping"
+	"github.com/luxdefi/lattice/v5/utils/sampling"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatistics(t *testing.T) {
+
+	params, err := hefloat.NewParametersFromLiteral(testInsecurePrec45)
+	require.NoError(t, err)
+
+	var tc *testContext
+	if tc, err = genTestParams(params); err != nil {
+		t.Fatal(err)
+	}
+
+	enc := tc.encryptorSk
+	sk := tc.sk
+	ecd := tc.encoder
+	dec := tc.decryptor
+	kgen := tc.kgen
+
+	n := params.MaxSlots()
+
+	galEls := hefloat.GaloisElementsForStatistics(params, n)
+	evk := rlwe.NewMemEvaluationKeySet(kgen.GenRelinearizationKeyNew(sk), kgen.GenGaloisKeysNew(galEls, sk)...)
+
+	stats := hefloat.NewStatisticsEvaluator(tc.evaluator.WithKey(evk), nil)
+
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = sampling.RandFloat64(-1, 1)
+	}
+
+	newCiphertext := func() (ct *rlwe.Ciphertext) {
+		pt := hefloat.NewPlaintext(params, params.MaxLevel())
+		require.NoError(t, ecd.Encode(values, pt))
+		ct, err := enc.EncryptNew(pt)
+		require.NoError(t, err)
+		return
+	}
+
+	// slot0 decrypts ct and returns the value of its slot 0.
+	slot0 := func(ct *rlwe.Ciphertext) float64 {
+		have := make([]float64, params.MaxSlots())
+		require.NoError(t, ecd.Decode(dec.DecryptNew(ct), have))
+		return have[0]
+	}
+
+	t.Run(GetTestName(params, "Sum"), func(t *testing.T) {
+
+		sum, err := stats.Sum(newCiphertext(), n)
+		require.NoError(t, err)
+
+		want := 0.0
+		for i := range values {
+			want += values[i]
+		}
+
+		require.InDelta(t, want, slot0(sum), 1e-3)
+	})
+
+	t.Run(GetTestName(params, "MaskedSum"), func(t *testing.T) {
+
+		mask := make([]float64, n)
+		for i := 0; i < n; i += 2 {
+			mask[i] = 1
+		}
+
+		sum, err := stats.MaskedSum(newCiphertext(), mask, n)
+		require.NoError(t, err)
+
+		want := 0.0
+		for i := 0; i < n; i += 2 {
+			want += values[i]
+		}
+
+		require.InDelta(t, want, slot0(sum), 1e-3)
+	})
+
+	t.Run(GetTestName(params, "Mean"), func(t *testing.T) {
+
+		mean, err := stats.Mean(newCiphertext(), n)
+		require.NoError(t, err)
+
+		want := 0.0
+		for i := range values {
+			want += values[i]
+		}
+		want /= float64(n)
+
+		require.InDelta(t, want, slot0(mean), 1e-3)
+	})
+
+	t.Run(GetTestName(params, "Variance"), func(t *testing.T) {
+
+		variance, mean, err := stats.Variance(newCiphertext(), n)
+		require.NoError(t, err)
+
+		wantMean := 0.0
+		for i := range values {
+			wantMean += values[i]
+		}
+		wantMean /= float64(n)
+
+		wantVar := 0.0
+		for i := range values {
+			wantVar += (values[i] - wantMean) * (values[i] - wantMean)
+		}
+		wantVar /= float64(n)
+
+		require.InDelta(t, wantMean, slot0(mean), 1e-3)
+		require.InDelta(t, wantVar, slot0(variance), 1e-3)
+	})
+
+	t.Run(GetTestName(params, "Count/NoComparisonEvaluator"), func(t *testing.T) {
+		_, err := stats.Count(newCiphertext(), -0.5, 0.5, n)
+		require.Error(t, err)
+	})
+}
+
+func TestStatisticsCounts(t *testing.T) {
+
+	params, err := hefloat.NewParametersFromLiteral(testInsecurePrec90)
+	require.NoError(t, err)
+
+	var tc *testContext
+	if tc, err = genTestParams(params); err != nil {
+		t.Fatal(err)
+	}
+
+	enc := tc.encryptorSk
+	sk := tc.sk
+	ecd := tc.encoder
+	dec := tc.decryptor
+	kgen := tc.kgen
+
+	n := params.MaxSlots()
+
+	btp := bootstrapping.NewSecretKeyBootstrapper(params, sk)
+
+	galEls := append(
+		hefloat.GaloisElementsForStatistics(params, n),
+		params.GaloisElementForComplexConjugation(),
+	)
+
+	evk := rlwe.NewMemEvaluationKeySet(kgen.GenRelinearizationKeyNew(sk), kgen.GenGaloisKeysNew(galEls, sk)...)
+
+	eval := tc.evaluator.WithKey(evk)
+
+	stats := hefloat.NewStatisticsEvaluator(eval, hefloat.NewComparisonEvaluator(params, eval, btp))
+
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = sampling.RandFloat64(-1, 1)
+	}
+
+	pt := hefloat.NewPlaintext(params, params.MaxLevel())
+	require.NoError(t, ecd.Encode(values, pt))
+	ct, err := enc.EncryptNew(pt)
+	require.NoError(t, err)
+
+	// slot0 decrypts ct and returns the value of its slot 0.
+	slot0 := func(ct *rlwe.Ciphertext) float64 {
+		have := make([]float64, params.MaxSlots())
+		require.NoError(t, ecd.Decode(dec.DecryptNew(ct), have))
+		return have[0]
+	}
+
+	t.Run(GetTestName(params, "Count"), func(t *testing.T) {
+
+		a, b := -0.5, 0.3
+
+		count, err := stats.Count(ct, a, b, n)
+		require.NoError(t, err)
+
+		want := 0.0
+		for i := range values {
+			if a < values[i] && values[i] < b {
+				want++
+			}
+		}
+
+		require.InDelta(t, want, slot0(count), 0.5)
+	})
+
+	t.Run(GetTestName(params, "Histogram"), func(t *testing.T) {
+
+		boundaries := []float64{-1, -0.25, 0.25, 1}
+
+		counts, err := stats.Histogram(ct, boundaries, n)
+		require.NoError(t, err)
+		require.Equal(t, len(boundaries)-1, len(counts))
+
+		total := 0.0
+		for i := range counts {
+
+			want := 0.0
+			for j := range values {
+				if boundaries[i] < values[j] && values[j] < boundaries[i+1] {
+					want++
+				}
+			}
+
+			have := slot0(counts[i])
+			require.InDelta(t, want, have, 0.5)
+			total += have
+		}
+
+		// The buckets cover [-1, 1], so the counts sum to n.
+		require.InDelta(t, float64(n), total, math.Sqrt(float64(n)))
+	})
+}
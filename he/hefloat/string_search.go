@@ -0,0 +1,247 @@
+package hefloat
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+)
+
+// StringSearchEvaluator is an evaluator providing an API for searching an
+// encrypted pattern within an encrypted text packed one symbol per slot,
+// returning per-position match indicators.
+//
+// The text is given as one or several ciphertexts (chunks), each packing
+// params.MaxSlots() consecutive symbols, and the pattern as one ciphertext
+// per pattern symbol, the i-th symbol replicated in every slot (see
+// EncodeSearchPattern). The search computes, for each position j, the score
+// sum_i (text[j+i] - pattern[i])^2 with rotations of the text, and maps it
+// to an indicator with the step function of the ComparisonEvaluator: 1 where
+// the score is below the threshold (a match), 0 elsewhere.
+//
+// Symbols must be normalized such that the scores lie in [0, 1], which
+// NormalizeSearchSymbol ensures for symbols of a finite alphabet; the
+// matching threshold separating a zero score from the smallest score a
+// single mismatched symbol can produce is returned by SearchMatchThreshold.
+//
+// All fields of this struct are public, enabling custom instantiations.
+type StringSearchEvaluator struct {
+	*Evaluator
+	// Comparisons evaluates the step function mapping scores to match
+	// indicators.
+	Comparisons *ComparisonEvaluator
+}
+
+// NewStringSearchEvaluator instantiates a new StringSearchEvaluator.
+func NewStringSearchEvaluator(eval *Evaluator, cmp *ComparisonEvaluator) *StringSearchEvaluator {
+	return &StringSearchEvaluator{Evaluator: eval, Comparisons: cmp}
+}
+
+// GaloisElementsForStringSearch returns the list of Galois elements required
+// to search for a pattern of patternLen symbols.
+func GaloisElementsForStringSearch(params Parameters, patternLen int) []uint64 {
+	rots := make([]int, patternLen-1)
+	for i := range rots {
+		rots[i] = i + 1
+	}
+	return params.GaloisElements(rots)
+}
+
+// NormalizeSearchSymbol maps a symbol of an alphabet of the given size into
+// [0, 1/sqrt(patternLen)], which bounds the match scores of a pattern of
+// patternLen symbols by 1, as required by the step function.
+func NormalizeSearchSymbol(symbol, alphabetSize, patternLen int) float64 {
+	return float64(symbol) / (float64(alphabetSize) * math.Sqrt(float64(patternLen)))
+}
+
+// SearchMatchThreshold returns a matching threshold for symbols normalized
+// with NormalizeSearchSymbol: half the score contributed by a single
+// mismatch of adjacent symbols, separating a zero score (a match) from any
+// score a mismatch can produce.
+func SearchMatchThreshold(alphabetSize, patternLen int) float64 {
+	return 1 / (2 * float64(alphabetSize) * float64(alphabetSize) * float64(patternLen))
+}
+
+// EncodeSearchPattern encodes the normalized symbols of a pattern for the
+// search circuit, one plaintext per symbol with the symbol replicated in
+// every slot, ready to be encrypted.
+func EncodeSearchPattern(ecd *Encoder, params Parameters, symbols []float64, level int) (pts []*rlwe.Plaintext, err error) {
+
+	pts = make([]*rlwe.Plaintext, len(symbols))
+
+	replicated := make([]float64, params.MaxSlots())
+
+	for i := range symbols {
+
+		for j := range replicated {
+			replicated[j] = symbols[i]
+		}
+
+		pts[i] = NewPlaintext(params, level)
+		if err = ecd.Encode(replicated, pts[i]); err != nil {
+			return nil, fmt.Errorf("cannot EncodeSearchPattern: %w", err)
+		}
+	}
+
+	return
+}
+
+// Search returns the per-position match indicators of the pattern in a text
+// packed in a single ciphertext: the slot j of the result holds 1 if the
+// pattern occurs at the position j, 0 otherwise. The text is treated as
+// cyclic: the last len(pattern)-1 positions compare against the beginning of
+// the text. See the StringSearchEvaluator doc for the packing and
+// normalization requirements.
+func (eval *StringSearchEvaluator) Search(text *rlwe.Ciphertext, pattern []*rlwe.Ciphertext, threshold float64) (match *rlwe.Ciphertext, err error) {
+
+	var scores *rlwe.Ciphertext
+	if scores, err = eval.matchScores(text, nil, pattern); err != nil {
+		return nil, fmt.Errorf("cannot Search: %w", err)
+	}
+
+	if match, err = eval.matchIndicator(scores, threshold); err != nil {
+		return nil, fmt.Errorf("cannot Search: %w", err)
+	}
+
+	return
+}
+
+// SearchChunks returns the per-position match indicators of the pattern in a
+// text spanning several ciphertexts of params.MaxSlots() symbols each: the
+// slot j of the k-th result holds 1 if the pattern occurs at the position
+// k*params.MaxSlots()+j, 0 otherwise. Matches straddling two chunks are
+// handled by stitching each chunk with the beginning of the next one, at the
+// cost of one additional level; the last len(pattern)-1 positions of the
+// last chunk have no successor and their indicators are invalid.
+func (eval *StringSearchEvaluator) SearchChunks(text []*rlwe.Ciphertext, pattern []*rlwe.Ciphertext, threshold float64) (match []*rlwe.Ciphertext, err error) {
+
+	match = make([]*rlwe.Ciphertext, len(text))
+
+	for k := range text {
+
+		var next *rlwe.Ciphertext
+		if k < len(text)-1 {
+			next = text[k+1]
+		}
+
+		var scores *rlwe.Ciphertext
+		if scores, err = eval.matchScores(text[k], next, pattern); err != nil {
+			return nil, fmt.Errorf("cannot SearchChunks: chunk %d: %w", k, err)
+		}
+
+		if match[k], err = eval.matchIndicator(scores, threshold); err != nil {
+			return nil, fmt.Errorf("cannot SearchChunks: chunk %d: %w", k, err)
+		}
+	}
+
+	return
+}
+
+// matchScores computes the score sum_i (text[j+i] - pattern[i])^2 in the
+// slot j. With a next chunk, the rotations of the chunk and of the next one
+// are stitched with complementary plaintext masks, so that the positions
+// within len(pattern)-1 of the end of the chunk compare against the
+// beginning of the next one instead of wrapping around.
+func (eval *StringSearchEvaluator) matchScores(text, next *rlwe.Ciphertext, pattern []*rlwe.Ciphertext) (scores *rlwe.Ciphertext, err error) {
+
+	params := eval.GetParameters()
+
+	m := len(pattern)
+	slots := params.MaxSlots()
+
+	rots := make([]int, m)
+	for i := range rots {
+		rots[i] = i
+	}
+
+	rotated, err := eval.RotateHoistedNew(text, rots)
+	if err != nil {
+		return nil, err
+	}
+
+	var rotatedNext map[int]*rlwe.Ciphertext
+	if next != nil {
+		if rotatedNext, err = eval.RotateHoistedNew(next, rots); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := 0; i < m; i++ {
+
+		var diff *rlwe.Ciphertext
+		if diff, err = eval.SubNew(rotated[i], pattern[i]); err != nil {
+			return nil, err
+		}
+
+		if next != nil {
+
+			// The rotation by i wraps the i first slots of the chunk into its
+			// i last positions; the masks substitute them with the i first
+			// slots of the next chunk.
+			maskText := make([]float64, slots)
+			maskNext := make([]float64, slots)
+			for j := 0; j < slots-i; j++ {
+				maskText[j] = 1
+			}
+			for j := slots - i; j < slots; j++ {
+				maskNext[j] = 1
+			}
+
+			if diff, err = eval.MulNew(diff, maskText); err != nil {
+				return nil, err
+			}
+
+			if i > 0 {
+
+				var diffNext *rlwe.Ciphertext
+				if diffNext, err = eval.SubNew(rotatedNext[i], pattern[i]); err != nil {
+					return nil, err
+				}
+
+				if err = eval.MulThenAdd(diffNext, maskNext, diff); err != nil {
+					return nil, err
+				}
+			}
+
+			if err = eval.Rescale(diff, diff); err != nil {
+				return nil, err
+			}
+		}
+
+		if err = eval.MulRelin(diff, diff, diff); err != nil {
+			return nil, err
+		}
+
+		if scores == nil {
+			scores = diff
+		} else if err = eval.Add(scores, diff, scores); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = eval.Rescale(scores, scores); err != nil {
+		return nil, err
+	}
+
+	return
+}
+
+// matchIndicator evaluates the step function on (threshold-scores)/2, i.e.
+// an approximation of 1 where the score is below the threshold, 0 elsewhere.
+func (eval *StringSearchEvaluator) matchIndicator(scores *rlwe.Ciphertext, threshold float64) (match *rlwe.Ciphertext, err error) {
+
+	var shifted *rlwe.Ciphertext
+	if shifted, err = eval.SubNew(scores, threshold); err != nil {
+		return nil, err
+	}
+
+	if err = eval.Mul(shifted, -0.5, shifted); err != nil {
+		return nil, err
+	}
+
+	if err = eval.Rescale(shifted, shifted); err != nil {
+		return nil, err
+	}
+
+	return eval.Comparisons.Step(shifted)
+}
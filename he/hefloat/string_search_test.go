@@ -0,0 +1,155 @@
+package hefloat_test
+
+import (
+	"testing"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/he/hefloat"
+	"github.com/luxdefi/lattice/v5/he/hefloat/bootstrapping"
+	"github.com/luxdefi/lattice/v5/utils/sampling"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringSearch(t *testing.T) {
+
+	params, err := hefloat.NewParametersFromLiteral(testInsecurePrec90)
+	require.NoError(t, err)
+
+	var tc *testContext
+	if tc, err = genTestParams(params); err != nil {
+		t.Fatal(err)
+	}
+
+	enc := tc.encryptorSk
+	sk := tc.sk
+	ecd := tc.encoder
+	dec := tc.decryptor
+	kgen := tc.kgen
+
+	alphabet := 256
+	m := 4
+	slots := params.MaxSlots()
+
+	btp := bootstrapping.NewSecretKeyBootstrapper(params, sk)
+
+	galEls := append(
+		hefloat.GaloisElementsForStringSearch(params, m),
+		params.GaloisElementForComplexConjugation(),
+	)
+
+	evk := rlwe.NewMemEvaluationKeySet(kgen.GenRelinearizationKeyNew(sk), kgen.GenGaloisKeysNew(galEls, sk)...)
+
+	eval := tc.evaluator.WithKey(evk)
+
+	search := hefloat.NewStringSearchEvaluator(eval, hefloat.NewComparisonEvaluator(params, eval, btp))
+
+	threshold := hefloat.SearchMatchThreshold(alphabet, m)
+
+	// Random pattern over the alphabet.
+	pattern := make([]int, m)
+	for i := range pattern {
+		pattern[i] = int(sampling.RandUint64() % uint64(alphabet))
+	}
+
+	normalized := make([]float64, m)
+	for i := range pattern {
+		normalized[i] = hefloat.NormalizeSearchSymbol(pattern[i], alphabet, m)
+	}
+
+	pts, err := hefloat.EncodeSearchPattern(ecd, params, normalized, params.MaxLevel())
+	require.NoError(t, err)
+
+	ctPattern := make([]*rlwe.Ciphertext, m)
+	for i := range pts {
+		ctPattern[i], err = enc.EncryptNew(pts[i])
+		require.NoError(t, err)
+	}
+
+	// newText returns a random text of n symbols with the pattern planted at
+	// the given positions, along with its encryption in chunks of slots
+	// symbols.
+	newText := func(n int, positions ...int) (text []int, chunks []*rlwe.Ciphertext) {
+
+		text = make([]int, n)
+		for i := range text {
+			text[i] = int(sampling.RandUint64() % uint64(alphabet))
+		}
+
+		for _, p := range positions {
+			copy(text[p:], pattern)
+		}
+
+		for i := 0; i < n; i += slots {
+
+			values := make([]float64, slots)
+			for j := range values {
+				values[j] = hefloat.NormalizeSearchSymbol(text[i+j], alphabet, m)
+			}
+
+			pt := hefloat.NewPlaintext(params, params.MaxLevel())
+			require.NoError(t, ecd.Encode(values, pt))
+
+			ct, err := enc.EncryptNew(pt)
+			require.NoError(t, err)
+
+			chunks = append(chunks, ct)
+		}
+
+		return
+	}
+
+	// matches returns the expected indicator at the position j of a text,
+	// comparing the pattern against the window starting at j modulo n.
+	matches := func(text []int, j int) float64 {
+		for i := range pattern {
+			if text[(j+i)%len(text)] != pattern[i] {
+				return 0
+			}
+		}
+		return 1
+	}
+
+	t.Run(GetTestName(params, "Search"), func(t *testing.T) {
+
+		text, chunks := newText(slots, 3, 100, slots-m)
+
+		match, err := search.Search(chunks[0], ctPattern, threshold)
+		require.NoError(t, err)
+
+		have := make([]float64, slots)
+		require.NoError(t, ecd.Decode(dec.DecryptNew(match), have))
+
+		for j := 0; j < slots; j++ {
+			require.InDelta(t, matches(text, j), have[j], 1e-3)
+		}
+	})
+
+	t.Run(GetTestName(params, "SearchChunks"), func(t *testing.T) {
+
+		// Plants an occurrence straddling the two chunks.
+		text, chunks := newText(2*slots, 5, slots-2, slots+42)
+
+		match, err := search.SearchChunks(chunks, ctPattern, threshold)
+		require.NoError(t, err)
+
+		require.Equal(t, len(chunks), len(match))
+
+		for k := range match {
+
+			have := make([]float64, slots)
+			require.NoError(t, ecd.Decode(dec.DecryptNew(match[k]), have))
+
+			for j := 0; j < slots; j++ {
+
+				// The indicators of the last m-1 positions of the last chunk
+				// are invalid.
+				if k == len(match)-1 && j >= slots-m+1 {
+					continue
+				}
+
+				require.InDelta(t, matches(text, k*slots+j), have[j], 1e-3)
+			}
+		}
+	})
+}
@@ -2,8 +2,24 @@
 package heint
 
 import (
+	"encoding/binary"
+	"math/bits"
+
 	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/he"
 	"github.com/luxdefi/lattice/v5/schemes/bgv"
+	"github.com/luxdefi/lattice/v5/utils/sampling"
+)
+
+// The front-end implements the scheme agnostic interfaces of the he package.
+var (
+	_ he.Encryptor                = (*rlwe.Encryptor)(nil)
+	_ he.Decryptor                = (*rlwe.Decryptor)(nil)
+	_ he.SlotEncoder              = (*Encoder)(nil)
+	_ he.Evaluator                = (*Evaluator)(nil)
+	_ he.SlotRotator              = (*Evaluator)(nil)
+	_ he.RowRotator               = (*Evaluator)(nil)
+	_ he.ScaleInvariantMultiplier = (*Evaluator)(nil)
 )
 
 type Integer interface {
@@ -73,6 +89,18 @@ func (ecd Encoder) ShallowCopy() *Encoder {
 	return &Encoder{Encoder: *ecd.Encoder.ShallowCopy()}
 }
 
+// EncodeSlots encodes values on the plaintext slots. It implements the
+// he.SlotEncoder interface, mapping to Encode.
+func (ecd Encoder) EncodeSlots(values interface{}, pt *rlwe.Plaintext) (err error) {
+	return ecd.Encoder.Encode(values, pt)
+}
+
+// DecodeSlots decodes the plaintext slots into values. It implements the
+// he.SlotEncoder interface, mapping to Decode.
+func (ecd Encoder) DecodeSlots(pt *rlwe.Plaintext, values interface{}) (err error) {
+	return ecd.Encoder.Decode(pt, values)
+}
+
 type Evaluator struct {
 	bgv.Evaluator
 }
@@ -92,3 +120,75 @@ func (eval Evaluator) WithKey(evk rlwe.EvaluationKeySet) *Evaluator {
 func (eval Evaluator) ShallowCopy() *Evaluator {
 	return &Evaluator{Evaluator: *eval.Evaluator.ShallowCopy()}
 }
+
+// RotateSlots cyclically rotates the plaintext slots of op0 by k positions to
+// the left. It implements the he.SlotRotator interface, mapping to
+// RotateColumns.
+func (eval Evaluator) RotateSlots(op0 *rlwe.Ciphertext, k int, opOut *rlwe.Ciphertext) (err error) {
+	return eval.Evaluator.RotateColumns(op0, k, opOut)
+}
+
+// RemainingMultiplications returns the number of further multiplications,
+// each followed by its rescaling (equivalently, the number of further
+// rescalings), that the level of op0 allows, i.e. op0.Level().
+func (eval Evaluator) RemainingMultiplications(op0 *rlwe.Ciphertext) int {
+	return op0.Level()
+}
+
+// Budget simulates, without evaluating it, the given plan of operations on
+// op0 and reports how many more multiplications and rescalings are possible
+// and which operations require a relinearization key (see he.EvaluateBudget).
+func (eval Evaluator) Budget(op0 *rlwe.Ciphertext, plan []he.Op) he.Budget {
+	return he.EvaluateBudget(op0.Level(), op0.Degree(), 1, plan)
+}
+
+// NewConsistencyChecker instantiates a he.ConsistencyChecker applying the
+// tolerance policy of the exact schemes: decrypted values must match the
+// reference exactly.
+func NewConsistencyChecker(params Parameters, sk *rlwe.SecretKey) *he.ConsistencyChecker {
+	return he.NewConsistencyChecker(params, sk, NewEncoder(params), he.Tolerance{})
+}
+
+// Power computes op0^k, for k >= 1, with a balanced addition chain of
+// multiplications, relinearizing and rescaling the intermediate powers
+// automatically (see he.Power).
+func (eval Evaluator) Power(op0 *rlwe.Ciphertext, k int) (opOut *rlwe.Ciphertext, err error) {
+	return he.Power(eval, op0, k)
+}
+
+// maskSampler samples mask slot values uniformly in the plaintext space
+// Z_{t}, the one-time-pad distribution of the exact schemes.
+type maskSampler struct {
+	t uint64
+}
+
+// SampleNew derives slots uniform values in [0, t) from prng, by rejection
+// sampling. It implements the he.MaskSampler interface.
+func (s maskSampler) SampleNew(prng sampling.PRNG, slots int) (values interface{}, err error) {
+
+	mask := uint64(1<<bits.Len64(s.t-1)) - 1
+
+	buf := make([]byte, 8)
+	mv := make([]uint64, slots)
+
+	for i := range mv {
+		for {
+			if _, err = prng.Read(buf); err != nil {
+				return nil, err
+			}
+
+			if v := binary.LittleEndian.Uint64(buf) & mask; v < s.t {
+				mv[i] = v
+				break
+			}
+		}
+	}
+
+	return mv, nil
+}
+
+// NewMasker instantiates a he.Masker deriving one-time-pad masks uniform in
+// the plaintext space Z_{t} from a PRF keyed by key (at most 64 bytes).
+func NewMasker(params Parameters, key []byte) *he.Masker {
+	return he.NewMasker(params, key, NewEncoder(params), maskSampler{t: params.PlaintextModulus()})
+}
@@ -313,6 +313,80 @@ func testLinearTransformation(tc *testContext, t *testing.T) {
 		verifyTestVectors(tc, tc.decryptor, values, ciphertext, t)
 	})
 
+	t.Run(GetTestName("Evaluator/LinearTransformation/MixedKeys", tc.params, level), func(t *testing.T) {
+
+		params := tc.params
+
+		values, _, ciphertext := newTestVectorsLvl(level, tc.params.DefaultScale(), tc, tc.encryptorSk)
+
+		diagonals := make(heint.Diagonals[uint64])
+
+		totSlots := values.N()
+
+		indexes := []int{-15, -4, -1, 0, 1, 2, 3, 4, 15}
+
+		for _, i := range indexes {
+			diagonals[i] = make([]uint64, totSlots)
+			for j := range diagonals[i] {
+				diagonals[i][j] = 1
+			}
+		}
+
+		// Applies the same circuit (naively) on the plaintext
+		tmp := make([]uint64, totSlots)
+		copy(tmp, values.Coeffs[0])
+
+		subRing := tc.params.RingT().SubRings[0]
+
+		for _, i := range indexes {
+			if i != 0 {
+				subRing.Add(values.Coeffs[0], utils.RotateSlotsNew(tmp, i), values.Coeffs[0])
+			}
+		}
+
+		// Evaluates the linear transformation with and without BSGS
+		for _, logBSGSRatio := range []int{-1, 1} {
+
+			ltparams := heint.LinearTransformationParameters{
+				DiagonalsIndexList:       indexes,
+				Level:                    ciphertext.Level(),
+				Scale:                    tc.params.DefaultScale(),
+				LogDimensions:            ciphertext.LogDimensions,
+				LogBabyStepGianStepRatio: logBSGSRatio,
+			}
+
+			// Allocate the linear transformation
+			linTransf := heint.NewLinearTransformation(params, ltparams)
+
+			// Encode on the linear transformation
+			require.NoError(t, heint.EncodeLinearTransformation[uint64](tc.encoder, diagonals, linTransf))
+
+			galEls := heint.GaloisElementsForLinearTransformation(params, ltparams)
+
+			// Every other Galois key is generated with a base-two decomposition:
+			// the evaluator detects the gadget parameters of each key and adapts
+			// the key-switch accordingly.
+			evkParamsB2 := rlwe.EvaluationKeyParameters{LevelP: utils.Pointy(0), BaseTwoDecomposition: utils.Pointy(16)}
+
+			gks := make([]*rlwe.GaloisKey, len(galEls))
+			for i, galEl := range galEls {
+				if i&1 == 1 {
+					gks[i] = tc.kgen.GenGaloisKeyNew(galEl, tc.sk, evkParamsB2)
+				} else {
+					gks[i] = tc.kgen.GenGaloisKeyNew(galEl, tc.sk)
+				}
+			}
+
+			eval := tc.evaluator.WithKey(rlwe.NewMemEvaluationKeySet(nil, gks...))
+			ltEval := heint.NewLinearTransformationEvaluator(eval)
+
+			res := ciphertext.CopyNew()
+			require.NoError(t, ltEval.Evaluate(ciphertext, linTransf, res))
+
+			verifyTestVectors(tc, tc.decryptor, values, res, t)
+		}
+	})
+
 	t.Run("Evaluator/PolyEval", func(t *testing.T) {
 
 		t.Run("Single", func(t *testing.T) {
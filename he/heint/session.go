@@ -0,0 +1,350 @@
+package heint
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/utils/buffer"
+	"github.com/luxdefi/lattice/v5/utils/metrics"
+)
+
+// Session bundles the parameters, the key material and the front-end objects
+// (encoder, encryptor, decryptor and a pool of evaluators) that every
+// application otherwise assembles by hand. A Session is created with
+// NewSession (fresh keys) or NewSessionFromKeys (existing keys), can be
+// persisted with WriteTo and restored with ReadFrom, and is handed to other
+// goroutines through ShallowCopy.
+type Session struct {
+	Params Parameters
+
+	// SecretKey is nil for evaluation-only sessions restored or assembled
+	// without it, in which case Decryptor is nil too and encryption uses
+	// PublicKey.
+	SecretKey      *rlwe.SecretKey
+	PublicKey      *rlwe.PublicKey
+	EvaluationKeys *rlwe.MemEvaluationKeySet
+
+	Encoder   *Encoder
+	Encryptor *rlwe.Encryptor
+	Decryptor *rlwe.Decryptor
+
+	pool *evaluatorPool
+}
+
+// evaluatorPool is a pool of evaluators shared by a Session and its shallow
+// copies.
+type evaluatorPool struct {
+	sync.Mutex
+	free []*Evaluator
+}
+
+// NewSession generates a fresh secret key, public key, relinearization key
+// and Galois keys for the given Galois elements, and bundles them with the
+// front-end objects into a Session.
+func NewSession(params Parameters, galEls ...uint64) *Session {
+
+	kgen := NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+
+	return NewSessionFromKeys(
+		params,
+		sk,
+		kgen.GenPublicKeyNew(sk),
+		rlwe.NewMemEvaluationKeySet(kgen.GenRelinearizationKeyNew(sk), kgen.GenGaloisKeysNew(galEls, sk)...))
+}
+
+// NewSessionFromKeys bundles existing key material with the front-end objects
+// into a Session. The secret key may be nil for an evaluation-only session,
+// in which case the Decryptor is nil and encryption uses the public key.
+func NewSessionFromKeys(params Parameters, sk *rlwe.SecretKey, pk *rlwe.PublicKey, evk *rlwe.MemEvaluationKeySet) *Session {
+
+	s := &Session{
+		Params:         params,
+		SecretKey:      sk,
+		PublicKey:      pk,
+		EvaluationKeys: evk,
+		Encoder:        NewEncoder(params),
+		pool:           &evaluatorPool{},
+	}
+
+	if sk != nil {
+		s.Encryptor = NewEncryptor(params, sk)
+		s.Decryptor = NewDecryptor(params, sk)
+	} else if pk != nil {
+		s.Encryptor = NewEncryptor(params, pk)
+	}
+
+	return s
+}
+
+// GetRLWEParameters returns a pointer to the underlying rlwe.Parameters.
+func (s *Session) GetRLWEParameters() *rlwe.Parameters {
+	return s.Params.GetRLWEParameters()
+}
+
+// EncryptNew encodes values at the maximum level and the default scale and
+// encrypts them.
+func (s *Session) EncryptNew(values interface{}) (ct *rlwe.Ciphertext, err error) {
+
+	if s.Encryptor == nil {
+		return nil, fmt.Errorf("cannot EncryptNew: session has no encryption key")
+	}
+
+	pt := NewPlaintext(s.Params, s.Params.MaxLevel())
+
+	if err = s.Encoder.Encode(values, pt); err != nil {
+		return nil, fmt.Errorf("cannot EncryptNew: %w", err)
+	}
+
+	return s.Encryptor.EncryptNew(pt)
+}
+
+// Decrypt decrypts ct and decodes it into values.
+func (s *Session) Decrypt(ct *rlwe.Ciphertext, values interface{}) (err error) {
+
+	if s.Decryptor == nil {
+		return fmt.Errorf("cannot Decrypt: session has no secret key")
+	}
+
+	return s.Encoder.Decode(s.Decryptor.DecryptNew(ct), values)
+}
+
+// GetEvaluator takes an evaluator from the session pool, instantiating a new
+// one with the session evaluation keys if the pool is empty. The evaluator
+// must be returned with PutEvaluator once no longer used. Safe for concurrent
+// use.
+func (s *Session) GetEvaluator() (eval *Evaluator) {
+
+	s.pool.Lock()
+	if n := len(s.pool.free); n > 0 {
+		eval = s.pool.free[n-1]
+		s.pool.free = s.pool.free[:n-1]
+	}
+	s.pool.Unlock()
+
+	if eval == nil {
+		metrics.IncCounter(metrics.EvaluatorPoolMiss, 1)
+		eval = NewEvaluator(s.Params, s.EvaluationKeys)
+	} else {
+		metrics.IncCounter(metrics.EvaluatorPoolHit, 1)
+	}
+
+	return
+}
+
+// PutEvaluator returns an evaluator taken with GetEvaluator to the session
+// pool. Safe for concurrent use.
+func (s *Session) PutEvaluator(eval *Evaluator) {
+	s.pool.Lock()
+	s.pool.free = append(s.pool.free, eval)
+	s.pool.Unlock()
+}
+
+// WithEvaluator runs f with an evaluator from the session pool, returning it
+// to the pool afterwards. Safe for concurrent use.
+func (s *Session) WithEvaluator(f func(eval *Evaluator) error) (err error) {
+	eval := s.GetEvaluator()
+	defer s.PutEvaluator(eval)
+	return f(eval)
+}
+
+// ShallowCopy returns a copy of the session sharing the parameters, the key
+// material and the evaluator pool, with fresh encoder, encryptor and
+// decryptor buffers, safe to hand to another goroutine.
+func (s *Session) ShallowCopy() *Session {
+
+	cpy := &Session{
+		Params:         s.Params,
+		SecretKey:      s.SecretKey,
+		PublicKey:      s.PublicKey,
+		EvaluationKeys: s.EvaluationKeys,
+		Encoder:        s.Encoder.ShallowCopy(),
+		pool:           s.pool,
+	}
+
+	if s.Encryptor != nil {
+		cpy.Encryptor = s.Encryptor.ShallowCopy()
+	}
+
+	if s.Decryptor != nil {
+		cpy.Decryptor = s.Decryptor.ShallowCopy()
+	}
+
+	return cpy
+}
+
+// BinarySize returns the serialized size of the session in bytes.
+func (s *Session) BinarySize() (size int) {
+
+	p, err := s.Params.MarshalBinary()
+	if err != nil {
+		return 0
+	}
+
+	size = 8 + len(p)
+
+	size++
+	if s.SecretKey != nil {
+		size += s.SecretKey.BinarySize()
+	}
+
+	size++
+	if s.PublicKey != nil {
+		size += s.PublicKey.BinarySize()
+	}
+
+	return size + s.EvaluationKeys.BinarySize()
+}
+
+// WriteTo writes the session (parameters and key material) on an io.Writer.
+// It implements the io.WriterTo interface, and will write exactly
+// object.BinarySize() bytes on w.
+func (s *Session) WriteTo(w io.Writer) (n int64, err error) {
+	switch w := w.(type) {
+	case buffer.Writer:
+
+		var inc int64
+
+		var p []byte
+		if p, err = s.Params.MarshalBinary(); err != nil {
+			return n, fmt.Errorf("cannot WriteTo: %w", err)
+		}
+
+		if inc, err = buffer.WriteUint64(w, uint64(len(p))); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		if inc, err = buffer.Write(w, p); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		var keys []io.WriterTo
+		if s.SecretKey != nil {
+			keys = append(keys, s.SecretKey)
+		} else {
+			keys = append(keys, nil)
+		}
+		if s.PublicKey != nil {
+			keys = append(keys, s.PublicKey)
+		} else {
+			keys = append(keys, nil)
+		}
+
+		for _, key := range keys {
+
+			var hasKey uint8
+			if key != nil {
+				hasKey = 1
+			}
+
+			if inc, err = buffer.WriteUint8(w, hasKey); err != nil {
+				return n + inc, err
+			}
+
+			n += inc
+
+			if hasKey == 1 {
+				if inc, err = key.WriteTo(w); err != nil {
+					return n + inc, err
+				}
+
+				n += inc
+			}
+		}
+
+		if inc, err = s.EvaluationKeys.WriteTo(w); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		return n, w.Flush()
+
+	default:
+		return buffer.WriteWithPool(w, s.WriteTo)
+	}
+}
+
+// ReadFrom reads the session (parameters and key material) from an io.Reader
+// and rebuilds the front-end objects. It implements the io.ReaderFrom
+// interface.
+func (s *Session) ReadFrom(r io.Reader) (n int64, err error) {
+	switch r := r.(type) {
+	case buffer.Reader:
+
+		var inc int64
+
+		var size uint64
+		if inc, err = buffer.ReadUint64(r, &size); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		p := make([]byte, size)
+		if inc, err = buffer.Read(r, p); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		var params Parameters
+		if err = params.UnmarshalBinary(p); err != nil {
+			return n, fmt.Errorf("cannot ReadFrom: %w", err)
+		}
+
+		var hasKey uint8
+
+		if inc, err = buffer.ReadUint8(r, &hasKey); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		var sk *rlwe.SecretKey
+		if hasKey == 1 {
+			sk = new(rlwe.SecretKey)
+			if inc, err = sk.ReadFrom(r); err != nil {
+				return n + inc, err
+			}
+
+			n += inc
+		}
+
+		if inc, err = buffer.ReadUint8(r, &hasKey); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		var pk *rlwe.PublicKey
+		if hasKey == 1 {
+			pk = new(rlwe.PublicKey)
+			if inc, err = pk.ReadFrom(r); err != nil {
+				return n + inc, err
+			}
+
+			n += inc
+		}
+
+		evk := new(rlwe.MemEvaluationKeySet)
+		if inc, err = evk.ReadFrom(r); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		*s = *NewSessionFromKeys(params, sk, pk, evk)
+
+		return n, nil
+
+	default:
+		return buffer.ReadWithPool(r, s.ReadFrom)
+	}
+}
@@ -0,0 +1,113 @@
+package heint_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxdefi/lattice/v5/he/heint"
+	"github.com/luxdefi/lattice/v5/utils/buffer"
+)
+
+func TestSession(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := heint.NewParametersFromLiteral(heint.ParametersLiteral{
+		LogN:             10,
+		LogQ:             []int{55, 45},
+		LogP:             []int{60},
+		PlaintextModulus: 0x101,
+	})
+	require.NoError(t, err)
+
+	s := heint.NewSession(params, params.GaloisElement(1))
+
+	T := params.PlaintextModulus()
+	slots := params.MaxSlots()
+	values := make([]uint64, slots)
+	for i := range values {
+		values[i] = uint64(i) % T
+	}
+
+	ct, err := s.EncryptNew(values)
+	require.NoError(t, err)
+
+	// The session evaluators use the bundled relinearization and Galois keys.
+	require.NoError(t, s.WithEvaluator(func(eval *heint.Evaluator) (err error) {
+		if err = eval.MulRelin(ct, ct, ct); err != nil {
+			return
+		}
+		return eval.RotateColumns(ct, 1, ct)
+	}))
+
+	have := make([]uint64, slots)
+	require.NoError(t, s.Decrypt(ct, have))
+
+	// RotateColumns rotates each of the two rows of slots/2 values independently.
+	half := slots >> 1
+	for i := 0; i < half; i++ {
+		require.Equal(t, (values[(i+1)%half]*values[(i+1)%half])%T, have[i])
+		require.Equal(t, (values[half+(i+1)%half]*values[half+(i+1)%half])%T, have[half+i])
+	}
+
+	// Evaluators are recycled through the pool.
+	eval := s.GetEvaluator()
+	s.PutEvaluator(eval)
+	require.Same(t, eval, s.GetEvaluator())
+	s.PutEvaluator(eval)
+
+	// Shallow copies are safe to use concurrently.
+	var wg sync.WaitGroup
+	errs := make([]error, 4)
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			sc := s.ShallowCopy()
+
+			ct, err := sc.EncryptNew(values)
+			if err != nil {
+				errs[w] = err
+				return
+			}
+
+			if errs[w] = sc.WithEvaluator(func(eval *heint.Evaluator) error {
+				return eval.RotateColumns(ct, 1, ct)
+			}); errs[w] != nil {
+				return
+			}
+
+			have := make([]uint64, slots)
+			errs[w] = sc.Decrypt(ct, have)
+		}(w)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+
+	// The session round-trips through its serialization.
+	buf := buffer.NewBufferSize(s.BinarySize())
+	n, err := s.WriteTo(buf)
+	require.NoError(t, err)
+	require.Equal(t, int64(s.BinarySize()), n)
+
+	loaded := new(heint.Session)
+	_, err = loaded.ReadFrom(buffer.NewBuffer(buf.Bytes()))
+	require.NoError(t, err)
+
+	require.True(t, loaded.Params.Equal(&s.Params))
+	require.True(t, loaded.SecretKey.Equal(s.SecretKey))
+	require.True(t, loaded.EvaluationKeys.Equal(s.EvaluationKeys))
+
+	require.NoError(t, loaded.Decrypt(ct, have))
+
+	// An evaluation-only session encrypts with the public key and cannot
+	// decrypt.
+	public := heint.NewSessionFromKeys(params, nil, s.PublicKey, s.EvaluationKeys)
+	_, err = public.EncryptNew(values)
+	require.NoError(t, err)
+	require.Error(t, public.Decrypt(ct, have))
+}
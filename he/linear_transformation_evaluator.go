@@ -199,29 +199,51 @@ func MultiplyByDiagMatrix(eval EvaluatorForLinearTransformation, ctIn *rlwe.Ciph
 
 		galEl := params.GaloisElement(k)
 
-		var evk *rlwe.GaloisKey
-		var err error
-		if evk, err = eval.CheckAndGetGaloisKey(galEl); err != nil {
-			return fmt.Errorf("cannot MultiplyByDiagMatrix: Automorphism: CheckAndGetGaloisKey: %w", err)
-		}
+		// A missing key is left to AutomorphismHoistedLazy, which may still
+		// realize the rotation by key composition (see rlwe.Evaluator.AllowKeyComposition).
+		evk, evkErr := eval.CheckAndGetGaloisKey(galEl)
+
+		ct0QP, ct1QP := tmp0QP, tmp1QP
+
+		if evkErr != nil || evk.LevelP() != levelP || evk.BaseTwoDecomposition != 0 {
+
+			// The shared decomposition BuffDecompQP is computed for the default
+			// special-prime set and without base-two decomposition; keys with
+			// different gadget parameters cannot consume it and are handled with
+			// the non-hoisted key-switch instead.
+			ctInQ := &rlwe.Ciphertext{}
+			ctInQ.Value = []ring.Poly{ctInTmp0, ctInTmp1}
+			ctInQ.MetaData = ctIn.MetaData
+
+			if err = eval.AutomorphismHoistedLazy(levelQ, ctInQ, BuffDecompQP, galEl, cQP); err != nil {
+				return fmt.Errorf("cannot MultiplyByDiagMatrix: %w", err)
+			}
+
+			ct0QP, ct1QP = cQP.Value[0], cQP.Value[1]
 
-		index := eval.AutomorphismIndex(galEl)
+			// The non-hoisted key-switch of the fallback uses the buffer
+			// holding c0 * P as scratch.
+			ringQ.MulScalarBigint(ctInTmp0, ringP.ModulusAtLevel[levelP], ct0TimesP)
 
-		eval.GadgetProductHoistedLazy(levelQ, BuffDecompQP, &evk.GadgetCiphertext, cQP)
-		ringQ.Add(cQP.Value[0].Q, ct0TimesP, cQP.Value[0].Q)
-		ringQP.AutomorphismNTTWithIndex(cQP.Value[0], index, tmp0QP)
-		ringQP.AutomorphismNTTWithIndex(cQP.Value[1], index, tmp1QP)
+		} else {
+			index := eval.AutomorphismIndex(galEl)
+
+			eval.GadgetProductHoistedLazy(levelQ, BuffDecompQP, &evk.GadgetCiphertext, cQP)
+			ringQ.Add(cQP.Value[0].Q, ct0TimesP, cQP.Value[0].Q)
+			ringQP.AutomorphismNTTWithIndex(cQP.Value[0], index, ct0QP)
+			ringQP.AutomorphismNTTWithIndex(cQP.Value[1], index, ct1QP)
+		}
 
 		pt := matrix.Vec[k]
 
 		if i == 0 {
 			// keyswitch(c1_Q) = (d0_QP, d1_QP)
-			ringQP.MulCoeffsMontgomery(pt, tmp0QP, c0OutQP)
-			ringQP.MulCoeffsMontgomery(pt, tmp1QP, c1OutQP)
+			ringQP.MulCoeffsMontgomery(pt, ct0QP, c0OutQP)
+			ringQP.MulCoeffsMontgomery(pt, ct1QP, c1OutQP)
 		} else {
 			// keyswitch(c1_Q) = (d0_QP, d1_QP)
-			ringQP.MulCoeffsMontgomeryThenAdd(pt, tmp0QP, c0OutQP)
-			ringQP.MulCoeffsMontgomeryThenAdd(pt, tmp1QP, c1OutQP)
+			ringQP.MulCoeffsMontgomeryThenAdd(pt, ct0QP, c0OutQP)
+			ringQP.MulCoeffsMontgomeryThenAdd(pt, ct1QP, c1OutQP)
 		}
 
 		if i%QiOverF == QiOverF-1 {
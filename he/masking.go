@@ -0,0 +1,134 @@
+package he
+
+import (
+	"fmt"
+
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/utils/sampling"
+)
+
+// MaskSampler derives pseudo-random plaintext slot values from a PRNG. It is
+// implemented by the heint and hefloat front-ends: the exact schemes sample
+// uniformly in the plaintext space (a perfect one-time pad), while the
+// approximate schemes sample uniformly in [-1, 1).
+type MaskSampler interface {
+	// SampleNew derives slots pseudo-random slot values from prng, returned
+	// as a slice of the scheme's plaintext element type.
+	SampleNew(prng sampling.PRNG, slots int) (values interface{}, err error)
+}
+
+// maskKey indexes the cache of encoded masks: the same identifier yields
+// distinct encodings at different levels or scales.
+type maskKey struct {
+	id    string
+	level int
+	scale float64
+}
+
+// Masker derives per-ciphertext plaintext masks from a PRF keyed by the
+// caller, for one-time-pad style masking in interactive protocols: both
+// parties holding the key derive the same mask from a public identifier
+// (e.g. a protocol round or ciphertext tag), one adds it before sending and
+// the other subtracts it after processing.
+//
+// Masks are encoded at the level, scale and metadata of the ciphertext they
+// mask, so that Mask and Unmask are exact inverses at any point of a circuit,
+// and the encodings are cached so that repeatedly masking with the same
+// identifier at the same level and scale does not re-encode.
+type Masker struct {
+	params  rlwe.ParameterProvider
+	key     []byte
+	ecd     SlotEncoder
+	sampler MaskSampler
+	masks   map[maskKey]*rlwe.Plaintext
+}
+
+// NewMasker instantiates a Masker deriving masks from the PRF keyed by key
+// (at most 64 bytes), sampling slot values with sampler and encoding them
+// with ecd. See the heint and hefloat front-ends for constructors applying
+// the mask distribution of their scheme.
+func NewMasker(params rlwe.ParameterProvider, key []byte, ecd SlotEncoder, sampler MaskSampler) *Masker {
+	return &Masker{
+		params:  params,
+		key:     append([]byte{}, key...),
+		ecd:     ecd,
+		sampler: sampler,
+		masks:   map[maskKey]*rlwe.Plaintext{},
+	}
+}
+
+// MaskPlaintext derives the mask of the identifier id encoded at the level,
+// scale and metadata of ct, caching the encoding. The same key, identifier,
+// level and scale always derive the same mask.
+func (m *Masker) MaskPlaintext(id []byte, ct *rlwe.Ciphertext) (pt *rlwe.Plaintext, err error) {
+
+	k := maskKey{id: string(id), level: ct.Level(), scale: ct.Scale.Float64()}
+
+	if pt, ok := m.masks[k]; ok {
+		return pt, nil
+	}
+
+	// PRF(key, id), from which the per-identifier mask stream is expanded.
+	prf, err := blake2b.New512(m.key)
+	if err != nil {
+		return nil, fmt.Errorf("cannot MaskPlaintext: %w", err)
+	}
+
+	if _, err = prf.Write(id); err != nil {
+		return nil, fmt.Errorf("cannot MaskPlaintext: %w", err)
+	}
+
+	prng, err := sampling.NewKeyedPRNG(prf.Sum(nil))
+	if err != nil {
+		return nil, fmt.Errorf("cannot MaskPlaintext: %w", err)
+	}
+
+	values, err := m.sampler.SampleNew(prng, ct.Slots())
+	if err != nil {
+		return nil, fmt.Errorf("cannot MaskPlaintext: %w", err)
+	}
+
+	pt = rlwe.NewPlaintext(m.params, ct.Level())
+	pt.MetaData = ct.MetaData.CopyNew()
+
+	if err = m.ecd.EncodeSlots(values, pt); err != nil {
+		return nil, fmt.Errorf("cannot MaskPlaintext: %w", err)
+	}
+
+	m.masks[k] = pt
+
+	return pt, nil
+}
+
+// Mask adds the mask of the identifier id on ct, in place.
+func (m *Masker) Mask(eval Evaluator, id []byte, ct *rlwe.Ciphertext) (err error) {
+
+	pt, err := m.MaskPlaintext(id, ct)
+	if err != nil {
+		return fmt.Errorf("cannot Mask: %w", err)
+	}
+
+	if err = eval.Add(ct, pt, ct); err != nil {
+		return fmt.Errorf("cannot Mask: %w", err)
+	}
+
+	return
+}
+
+// Unmask subtracts the mask of the identifier id from ct, in place, undoing
+// Mask.
+func (m *Masker) Unmask(eval Evaluator, id []byte, ct *rlwe.Ciphertext) (err error) {
+
+	pt, err := m.MaskPlaintext(id, ct)
+	if err != nil {
+		return fmt.Errorf("cannot Unmask: %w", err)
+	}
+
+	if err = eval.Sub(ct, pt, ct); err != nil {
+		return fmt.Errorf("cannot Unmask: %w", err)
+	}
+
+	return
+}
@@ -0,0 +1,140 @@
+package he_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxdefi/lattice/v5/he/hefloat"
+	"github.com/luxdefi/lattice/v5/he/heint"
+)
+
+func TestMasker(t *testing.T) {
+
+	key := []byte("masking test key")
+
+	t.Run("hefloat", func(t *testing.T) {
+
+		// <<<<!Insecure parameters!>>>>
+		params, err := hefloat.NewParametersFromLiteral(hefloat.ParametersLiteral{
+			LogN:            10,
+			LogQ:            []int{55, 45, 45},
+			LogP:            []int{60},
+			LogDefaultScale: 45,
+		})
+		require.NoError(t, err)
+
+		kgen := hefloat.NewKeyGenerator(params)
+		sk := kgen.GenSecretKeyNew()
+
+		ecd := hefloat.NewEncoder(params)
+		enc := hefloat.NewEncryptor(params, sk)
+		dec := hefloat.NewDecryptor(params, sk)
+		eval := hefloat.NewEvaluator(params, nil)
+
+		values := make([]float64, params.MaxSlots())
+		for i := range values {
+			values[i] = float64(i) / float64(len(values))
+		}
+
+		pt := hefloat.NewPlaintext(params, params.MaxLevel())
+		require.NoError(t, ecd.Encode(values, pt))
+		ct, err := enc.EncryptNew(pt)
+		require.NoError(t, err)
+
+		masker := hefloat.NewMasker(params, key)
+
+		require.NoError(t, masker.Mask(eval, []byte("round 1"), ct))
+
+		// The mask hides the values.
+		masked := make([]float64, params.MaxSlots())
+		require.NoError(t, ecd.Decode(dec.DecryptNew(ct), masked))
+		var moved int
+		for i := range masked {
+			if diff := masked[i] - values[i]; diff > 1e-3 || diff < -1e-3 {
+				moved++
+			}
+		}
+		require.Greater(t, moved, len(masked)/2)
+
+		// Unmasking with the same identifier recovers the values.
+		require.NoError(t, masker.Unmask(eval, []byte("round 1"), ct))
+
+		have := make([]float64, params.MaxSlots())
+		require.NoError(t, ecd.Decode(dec.DecryptNew(ct), have))
+		for i := range have {
+			require.InDelta(t, values[i], have[i], 1e-6)
+		}
+
+		// The same key and identifier derive the same mask, a different
+		// identifier a different one.
+		other := hefloat.NewMasker(params, key)
+
+		pt0, err := masker.MaskPlaintext([]byte("round 1"), ct)
+		require.NoError(t, err)
+		pt1, err := other.MaskPlaintext([]byte("round 1"), ct)
+		require.NoError(t, err)
+		pt2, err := other.MaskPlaintext([]byte("round 2"), ct)
+		require.NoError(t, err)
+
+		require.True(t, pt0.Value.Equal(&pt1.Value))
+		require.False(t, pt0.Value.Equal(&pt2.Value))
+
+		// The encoding is cached.
+		pt3, err := masker.MaskPlaintext([]byte("round 1"), ct)
+		require.NoError(t, err)
+		require.True(t, pt0 == pt3)
+	})
+
+	t.Run("heint", func(t *testing.T) {
+
+		// <<<<!Insecure parameters!>>>>
+		params, err := heint.NewParametersFromLiteral(heint.ParametersLiteral{
+			LogN:             10,
+			LogQ:             []int{55, 45},
+			LogP:             []int{60},
+			PlaintextModulus: 0x101,
+		})
+		require.NoError(t, err)
+
+		kgen := heint.NewKeyGenerator(params)
+		sk := kgen.GenSecretKeyNew()
+
+		ecd := heint.NewEncoder(params)
+		enc := heint.NewEncryptor(params, sk)
+		dec := heint.NewDecryptor(params, sk)
+		eval := heint.NewEvaluator(params, nil)
+
+		T := params.PlaintextModulus()
+		values := make([]uint64, params.MaxSlots())
+		for i := range values {
+			values[i] = uint64(i) % T
+		}
+
+		pt := heint.NewPlaintext(params, params.MaxLevel())
+		require.NoError(t, ecd.Encode(values, pt))
+		ct, err := enc.EncryptNew(pt)
+		require.NoError(t, err)
+
+		masker := heint.NewMasker(params, key)
+
+		require.NoError(t, masker.Mask(eval, []byte("round 1"), ct))
+
+		// The mask is a one-time pad: uniform in Z_{T}.
+		masked := make([]uint64, params.MaxSlots())
+		require.NoError(t, ecd.Decode(dec.DecryptNew(ct), masked))
+		var moved int
+		for i := range masked {
+			if masked[i] != values[i] {
+				moved++
+			}
+		}
+		require.Greater(t, moved, len(masked)/2)
+
+		require.NoError(t, masker.Unmask(eval, []byte("round 1"), ct))
+
+		have := make([]uint64, params.MaxSlots())
+		require.NoError(t, ecd.Decode(dec.DecryptNew(ct), have))
+		require.Equal(t, values, have)
+	})
+}
@@ -0,0 +1,265 @@
+// Package pir implements the building blocks of single-server Private Information
+// Retrieval (PIR) from RLWE-based homomorphic encryption: database preprocessing
+// (plaintext packing and NTT), compact queries made of RGSW encryptions of the
+// index bits, server-side selection with a tree of external products, and
+// client-side response decoding.
+//
+// The database is preprocessed once with ProcessDatabase, which packs each row
+// into the coefficients of a scaled, NTT-domain plaintext polynomial. A query
+// for row i is a vector of ceil(log2(#rows)) RGSW ciphertexts, one per bit of i.
+// The server folds the database with a multiplexer tree, halving the number of
+// candidate rows with each index bit via the external product
+//
+//	sel = ct0 + RGSW(bit) x (ct1 - ct0)
+//
+// so that a database of n rows is reduced to the single requested row with n-1
+// external products. The response is a single RLWE ciphertext that the client
+// decrypts and decodes back into the row bytes.
+package pir
+
+import (
+	"fmt"
+	"math/big"
+	"math/bits"
+
+	"github.com/luxdefi/lattice/v5/core/rgsw"
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/utils/bignum"
+	"github.com/luxdefi/lattice/v5/utils/structs"
+)
+
+// messageBase is the number of values a single plaintext coefficient can take.
+// Rows are packed one byte per coefficient.
+const messageBase = 256
+
+// Database stores a preprocessed database: each row is packed into the
+// coefficients of an NTT-domain polynomial, scaled by floor(Q/messageBase).
+// The rows are held by a RowStore, by default in memory (see
+// ProcessDatabase); databases too large to materialize in RAM can be served
+// on demand from storage instead (see NewDatabase and RowStore).
+type Database struct {
+	params  rlwe.Parameters
+	store   RowStore
+	rowSize int
+}
+
+// Rows returns the number of rows of the database.
+func (db *Database) Rows() int {
+	return db.store.Rows()
+}
+
+// RowSize returns the size of a database row in bytes.
+func (db *Database) RowSize() int {
+	return db.rowSize
+}
+
+// ProcessDatabase packs the given rows into scaled, NTT-domain plaintext
+// polynomials. Rows can be at most params.N() bytes long; shorter rows are
+// zero-padded to the longest one.
+func ProcessDatabase(params rlwe.Parameters, rows [][]byte) (db *Database, err error) {
+
+	rowSize := 0
+	for _, row := range rows {
+		if len(row) > rowSize {
+			rowSize = len(row)
+		}
+	}
+
+	if rowSize > params.N() {
+		return nil, fmt.Errorf("cannot ProcessDatabase: row size %d exceeds params.N()=%d", rowSize, params.N())
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("cannot ProcessDatabase: empty database")
+	}
+
+	ringQ := params.RingQ()
+	delta := scalingFactor(params)
+
+	store := make(memoryRowStore, len(rows))
+
+	for i, row := range rows {
+		p := ringQ.NewPoly()
+		for j, b := range row {
+			for k := range p.Coeffs {
+				p.Coeffs[k][j] = uint64(b)
+			}
+		}
+		ringQ.MulScalarBigint(p, delta, p)
+		ringQ.NTT(p, p)
+		store[i] = p
+	}
+
+	db = &Database{params: params, store: store, rowSize: rowSize}
+
+	return
+}
+
+// scalingFactor returns floor(Q/messageBase), the factor by which the packed
+// row coefficients are scaled to tolerate the noise of the external products.
+func scalingFactor(params rlwe.Parameters) *big.Int {
+	return new(big.Int).Quo(params.RingQ().ModulusAtLevel[params.MaxLevel()], new(big.Int).SetUint64(messageBase))
+}
+
+// Query is a PIR query: RGSW encryptions of the bits of the requested row
+// index, from the least significant bit up.
+type Query struct {
+	Bits structs.Vector[rgsw.Ciphertext]
+}
+
+// Client generates queries and decodes responses. It holds the secret key and
+// must remain on the querier's side.
+type Client struct {
+	params    rlwe.Parameters
+	encryptor *rgsw.Encryptor
+	decryptor *rlwe.Decryptor
+}
+
+// NewClient instantiates a new PIR client from the parameters and the client's
+// secret key.
+func NewClient(params rlwe.Parameters, sk *rlwe.SecretKey) *Client {
+	return &Client{
+		params:    params,
+		encryptor: rgsw.NewEncryptor(params, sk),
+		decryptor: rlwe.NewDecryptor(params, sk),
+	}
+}
+
+// GenQueryNew generates a query for the row of the given index in a database
+// of rows rows.
+func (c *Client) GenQueryNew(index, rows int) (query *Query, err error) {
+
+	if rows < 1 || index < 0 || index >= rows {
+		return nil, fmt.Errorf("cannot GenQueryNew: index %d out of bounds for %d rows", index, rows)
+	}
+
+	params := c.params
+	nbBits := bits.Len(uint(rows - 1))
+
+	query = &Query{Bits: make(structs.Vector[rgsw.Ciphertext], nbBits)}
+
+	pt := rlwe.NewPlaintext(params, params.MaxLevel())
+	pt.IsNTT = false
+
+	for i := 0; i < nbBits; i++ {
+
+		bit := uint64(index>>i) & 1
+
+		for j := range pt.Value.Coeffs {
+			pt.Value.Coeffs[j][0] = bit
+		}
+
+		ct := rgsw.NewCiphertext(params, params.MaxLevelQ(), params.MaxLevelP(), 0)
+		if err = c.encryptor.Encrypt(pt, ct); err != nil {
+			return nil, fmt.Errorf("cannot GenQueryNew: %w", err)
+		}
+
+		query.Bits[i] = *ct
+	}
+
+	return
+}
+
+// DecodeResponseNew decrypts and decodes a response generated by
+// Server.GenResponseNew, returning the requested row.
+func (c *Client) DecodeResponseNew(response *rlwe.Ciphertext, rowSize int) (row []byte, err error) {
+
+	params := c.params
+
+	if rowSize < 0 || rowSize > params.N() {
+		return nil, fmt.Errorf("cannot DecodeResponseNew: invalid row size %d", rowSize)
+	}
+
+	pt := c.decryptor.DecryptNew(response)
+
+	ringQ := params.RingQ().AtLevel(pt.Level())
+
+	if pt.IsNTT {
+		ringQ.INTT(pt.Value, pt.Value)
+	}
+
+	coeffs := make([]*big.Int, params.N())
+	for i := range coeffs {
+		coeffs[i] = new(big.Int)
+	}
+
+	ringQ.PolyToBigintCentered(pt.Value, 1, coeffs)
+
+	delta := scalingFactor(params)
+	base := new(big.Int).SetUint64(messageBase)
+
+	row = make([]byte, rowSize)
+	for i := range row {
+		bignum.DivRound(coeffs[i], delta, coeffs[i])
+		row[i] = byte(coeffs[i].Mod(coeffs[i], base).Uint64())
+	}
+
+	return
+}
+
+// Server answers queries over a preprocessed database. It only ever sees the
+// query ciphertexts and learns nothing about the requested index.
+type Server struct {
+	db   *Database
+	eval *rgsw.Evaluator
+}
+
+// NewServer instantiates a new PIR server over the given preprocessed database.
+func NewServer(db *Database) *Server {
+	return &Server{db: db, eval: rgsw.NewEvaluator(db.params, nil)}
+}
+
+// GenResponseNew evaluates the query over the database and returns the
+// response ciphertext, which encrypts the requested row.
+func (s *Server) GenResponseNew(query *Query) (response *rlwe.Ciphertext, err error) {
+
+	params := s.db.params
+
+	if n := 1 << len(query.Bits); n < s.db.Rows() {
+		return nil, fmt.Errorf("cannot GenResponseNew: query addresses %d rows but the database has %d", n, s.db.Rows())
+	}
+
+	ringQ := params.RingQ()
+
+	// Database rows as trivial (unencrypted) RLWE ciphertexts, padded with
+	// zeros to the number of rows addressed by the query.
+	cur := make([]*rlwe.Ciphertext, 1<<len(query.Bits))
+	for i := range cur {
+		ct := rlwe.NewCiphertext(params, 1, params.MaxLevel())
+		ct.IsNTT = true
+		if i < s.db.Rows() {
+			if err = s.db.store.ReadRow(i, ct.Value[0]); err != nil {
+				return nil, fmt.Errorf("cannot GenResponseNew: row %d: %w", i, err)
+			}
+		}
+		cur[i] = ct
+	}
+
+	for i := range query.Bits {
+
+		bit := &query.Bits[i]
+		next := cur[: len(cur)/2 : len(cur)/2]
+
+		for j := range next {
+
+			ct0, ct1 := cur[2*j], cur[2*j+1]
+
+			// ct0 + RGSW(bit) x (ct1 - ct0)
+			diff := rlwe.NewCiphertext(params, 1, params.MaxLevel())
+			diff.IsNTT = true
+			ringQ.Sub(ct1.Value[0], ct0.Value[0], diff.Value[0])
+			ringQ.Sub(ct1.Value[1], ct0.Value[1], diff.Value[1])
+
+			s.eval.ExternalProduct(diff, bit, diff)
+
+			ringQ.Add(diff.Value[0], ct0.Value[0], diff.Value[0])
+			ringQ.Add(diff.Value[1], ct0.Value[1], diff.Value[1])
+
+			next[j] = diff
+		}
+
+		cur = next
+	}
+
+	return cur[0], nil
+}
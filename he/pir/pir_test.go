@@ -0,0 +1,99 @@
+package pir
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+)
+
+func TestPIR(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := rlwe.NewParametersFromLiteral(rlwe.ParametersLiteral{
+		LogN:    10,
+		LogQ:    []int{50, 40},
+		LogP:    []int{61},
+		NTTFlag: true,
+	})
+	require.NoError(t, err)
+
+	rows := [][]byte{
+		[]byte("row 0: alice"),
+		[]byte("row 1: bob"),
+		[]byte("row 2: carol"),
+		[]byte("row 3: dan"),
+		[]byte("row 4: erin"),
+	}
+
+	db, err := ProcessDatabase(params, rows)
+	require.NoError(t, err)
+	require.Equal(t, len(rows), db.Rows())
+
+	sk := rlwe.NewKeyGenerator(params).GenSecretKeyNew()
+
+	client := NewClient(params, sk)
+	server := NewServer(db)
+
+	for index := range rows {
+
+		query, err := client.GenQueryNew(index, db.Rows())
+		require.NoError(t, err)
+
+		response, err := server.GenResponseNew(query)
+		require.NoError(t, err)
+
+		row, err := client.DecodeResponseNew(response, db.RowSize())
+		require.NoError(t, err)
+
+		want := make([]byte, db.RowSize())
+		copy(want, rows[index])
+
+		require.Equal(t, want, row)
+	}
+
+	// Out-of-bounds queries are rejected.
+	_, err = client.GenQueryNew(-1, db.Rows())
+	require.Error(t, err)
+	_, err = client.GenQueryNew(db.Rows(), db.Rows())
+	require.Error(t, err)
+
+	t.Run("RowStore", func(t *testing.T) {
+
+		// Serializes the rows and serves them on demand from the serialized
+		// form, as a database too large for RAM would be served from a
+		// memory-mapped file.
+		buf := new(bytes.Buffer)
+		n, err := db.WriteRowsTo(buf)
+		require.NoError(t, err)
+		require.Equal(t, int(n), buf.Len())
+
+		store := NewRowStoreFromReaderAt(params, bytes.NewReader(buf.Bytes()), db.Rows())
+
+		dbOnDemand, err := NewDatabase(params, store, db.RowSize())
+		require.NoError(t, err)
+
+		server := NewServer(dbOnDemand)
+
+		index := 3
+
+		query, err := client.GenQueryNew(index, dbOnDemand.Rows())
+		require.NoError(t, err)
+
+		response, err := server.GenResponseNew(query)
+		require.NoError(t, err)
+
+		row, err := client.DecodeResponseNew(response, dbOnDemand.RowSize())
+		require.NoError(t, err)
+
+		want := make([]byte, dbOnDemand.RowSize())
+		copy(want, rows[index])
+
+		require.Equal(t, want, row)
+
+		// Out-of-range rows surface the error of the backing store.
+		require.Error(t, store.ReadRow(db.Rows(), params.RingQ().NewPoly()))
+	})
+}
@@ -0,0 +1,126 @@
+package pir
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/ring"
+)
+
+// RowStore abstracts the backing store of the preprocessed rows of a
+// Database. ProcessDatabase materializes every row in memory, which is the
+// fastest option but requires RAM proportional to the size of the database;
+// read-mostly databases too large for this can instead be served on demand
+// from storage by instantiating a Database over a custom RowStore with
+// NewDatabase (see NewRowStoreFromReaderAt for a store reading rows
+// serialized by WriteRowsTo, e.g. from a memory-mapped file; stores over
+// compressed or remote storage can be implemented likewise).
+type RowStore interface {
+	// Rows returns the number of rows of the store.
+	Rows() int
+	// ReadRow reads the row of the given index into p, a polynomial over
+	// the full modulus Q of the parameters.
+	ReadRow(i int, p ring.Poly) error
+}
+
+// memoryRowStore is the RowStore of ProcessDatabase, keeping every row
+// materialized in memory.
+type memoryRowStore []ring.Poly
+
+// Rows returns the number of rows of the store.
+func (s memoryRowStore) Rows() int {
+	return len(s)
+}
+
+// ReadRow copies the row of the given index into p.
+func (s memoryRowStore) ReadRow(i int, p ring.Poly) error {
+	p.Copy(s[i])
+	return nil
+}
+
+// NewDatabase instantiates a Database over a custom RowStore holding rows
+// preprocessed with the given parameters. rowSize is the size in bytes of
+// the rows that were packed into the store (see Database.RowSize).
+func NewDatabase(params rlwe.Parameters, store RowStore, rowSize int) (db *Database, err error) {
+
+	if rowSize < 0 || rowSize > params.N() {
+		return nil, fmt.Errorf("cannot NewDatabase: row size %d exceeds params.N()=%d", rowSize, params.N())
+	}
+
+	if store.Rows() == 0 {
+		return nil, fmt.Errorf("cannot NewDatabase: empty store")
+	}
+
+	return &Database{params: params, store: store, rowSize: rowSize}, nil
+}
+
+// WriteRowsTo serializes the rows of the database back-to-back on w, in the
+// fixed-size format expected by NewRowStoreFromReaderAt, and returns the
+// number of bytes written.
+func (db *Database) WriteRowsTo(w io.Writer) (n int64, err error) {
+
+	bw := bufio.NewWriter(w)
+
+	p := db.params.RingQ().NewPoly()
+
+	for i := 0; i < db.store.Rows(); i++ {
+
+		if err = db.store.ReadRow(i, p); err != nil {
+			return n, fmt.Errorf("cannot WriteRowsTo: row %d: %w", i, err)
+		}
+
+		var inc int64
+		if inc, err = p.WriteTo(bw); err != nil {
+			return n, fmt.Errorf("cannot WriteRowsTo: row %d: %w", i, err)
+		}
+
+		n += inc
+	}
+
+	if err = bw.Flush(); err != nil {
+		return n, fmt.Errorf("cannot WriteRowsTo: %w", err)
+	}
+
+	return
+}
+
+// readerAtRowStore serves rows on demand from rows serialized back-to-back
+// at fixed offsets.
+type readerAtRowStore struct {
+	r    io.ReaderAt
+	rows int
+	size int
+}
+
+// NewRowStoreFromReaderAt returns a RowStore serving rows rows on demand
+// from r, which holds the rows serialized back-to-back by WriteRowsTo; r
+// can notably be an os.File or a bytes.Reader over a memory-mapped file.
+// Each ReadRow reads and deserializes only the requested row, so the
+// database is never fully materialized in memory. The store is safe for
+// concurrent use if r is.
+func NewRowStoreFromReaderAt(params rlwe.Parameters, r io.ReaderAt, rows int) RowStore {
+	return &readerAtRowStore{r: r, rows: rows, size: params.RingQ().NewPoly().BinarySize()}
+}
+
+// Rows returns the number of rows of the store.
+func (s *readerAtRowStore) Rows() int {
+	return s.rows
+}
+
+// ReadRow reads and deserializes the row of the given index into p.
+func (s *readerAtRowStore) ReadRow(i int, p ring.Poly) (err error) {
+
+	data := make([]byte, s.size)
+
+	if _, err = s.r.ReadAt(data, int64(i)*int64(s.size)); err != nil {
+		return fmt.Errorf("cannot ReadRow: %w", err)
+	}
+
+	if err = p.UnmarshalBinary(data); err != nil {
+		return fmt.Errorf("cannot ReadRow: %w", err)
+	}
+
+	return
+}
@@ -1,7 +1,6 @@
 package he
 
 import (
-	"bufio"
 	"fmt"
 	"io"
 	"math/bits"
@@ -175,6 +174,27 @@ func (p *PowerBasis) genPower(n int, lazy, rescale bool, eval Evaluator) (rescal
 	return false, nil
 }
 
+// Power computes op0^k, for k >= 1, with a balanced addition chain of
+// multiplications (see SplitDegree), relinearizing and rescaling the
+// intermediate powers automatically so that the multiplicative depth is
+// ceil(log2(k)) and the output scale matches the one of a single
+// multiplication. It is a convenience wrapper around PowerBasis for
+// circuits that only need a single power of a ciphertext.
+func Power(eval Evaluator, op0 *rlwe.Ciphertext, k int) (opOut *rlwe.Ciphertext, err error) {
+
+	if k < 1 {
+		return nil, fmt.Errorf("cannot Power: k must be at least 1 but is %d", k)
+	}
+
+	p := NewPowerBasis(op0, bignum.Monomial)
+
+	if err = p.GenPower(k, false, eval); err != nil {
+		return nil, fmt.Errorf("cannot Power: %w", err)
+	}
+
+	return p.Value[k], nil
+}
+
 // BinarySize returns the serialized size of the object in bytes.
 func (p PowerBasis) BinarySize() (size int) {
 	return 1 + p.Value.BinarySize()
@@ -209,7 +229,7 @@ func (p PowerBasis) WriteTo(w io.Writer) (n int64, err error) {
 		return n + inc, err
 
 	default:
-		return p.WriteTo(bufio.NewWriter(w))
+		return buffer.WriteWithPool(w, p.WriteTo)
 	}
 }
 
@@ -248,7 +268,7 @@ func (p *PowerBasis) ReadFrom(r io.Reader) (n int64, err error) {
 		return n + inc, err
 
 	default:
-		return p.ReadFrom(bufio.NewReader(r))
+		return buffer.ReadWithPool(r, p.ReadFrom)
 	}
 }
 
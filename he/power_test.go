@@ -0,0 +1,65 @@
+package he_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/he"
+	"github.com/luxdefi/lattice/v5/he/hefloat"
+)
+
+func TestPower(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := hefloat.NewParametersFromLiteral(hefloat.ParametersLiteral{
+		LogN:            10,
+		LogQ:            []int{55, 45, 45, 45},
+		LogP:            []int{60},
+		LogDefaultScale: 45,
+	})
+	require.NoError(t, err)
+
+	kgen := hefloat.NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+
+	eval := hefloat.NewEvaluator(params, rlwe.NewMemEvaluationKeySet(kgen.GenRelinearizationKeyNew(sk)))
+
+	ecd := hefloat.NewEncoder(params)
+	enc := hefloat.NewEncryptor(params, sk)
+	dec := hefloat.NewDecryptor(params, sk)
+
+	values := make([]float64, params.MaxSlots())
+	for i := range values {
+		values[i] = 0.9 * float64(i%16) / 15
+	}
+
+	pt := hefloat.NewPlaintext(params, params.MaxLevel())
+	require.NoError(t, ecd.Encode(values, pt))
+	ct, err := enc.EncryptNew(pt)
+	require.NoError(t, err)
+
+	_, err = he.Power(eval, ct, 0)
+	require.Error(t, err)
+
+	have := make([]float64, params.MaxSlots())
+	for _, k := range []int{1, 2, 5, 8} {
+
+		res, err := eval.Power(ct, k)
+		require.NoError(t, err)
+
+		// The chain has depth ceil(log2(k)).
+		depth := 0
+		for 1<<depth < k {
+			depth++
+		}
+		require.Equal(t, params.MaxLevel()-depth, res.Level())
+
+		require.NoError(t, ecd.Decode(dec.DecryptNew(res), have))
+		for i := 0; i < 32; i++ {
+			require.InDelta(t, math.Pow(values[i], float64(k)), have[i], 1e-6)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package he
+
+import (
+	"fmt"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/utils"
+)
+
+// RotationPlan is the output of OptimizeRotationSet: a set of base rotation
+// steps, each backed by one Galois key, together with a decomposition of
+// every declared rotation into a bounded composition of these steps. The
+// plan is consumed by RotateAny, which evaluates an arbitrary rotation by
+// chaining the base steps of its decomposition.
+type RotationPlan struct {
+	slots           int
+	maxCompositions int
+	steps           []int
+	paths           map[int][]int
+}
+
+// OptimizeRotationSet selects a set of at most budget rotation steps such
+// that every rotation of the declared workload can be evaluated with at most
+// maxCompositions successive rotations by the selected steps. The rotations
+// argument is the multiset of rotations performed by the workload (for
+// example the Rotations field of a rlwe.CircuitDescription populated during
+// a dry run): repeated rotations carry more weight and are assigned shorter
+// compositions. The slots argument is the size of the rotation group, i.e.
+// the number of plaintext slots of the scheme.
+//
+// The selection is a deterministic greedy heuristic over the declared
+// rotations and the signed powers of two, intended for offline planning: it
+// returns the first key set found that covers the workload, or an error if
+// it cannot find one within the budget.
+func OptimizeRotationSet(rotations []int, slots, budget, maxCompositions int) (p *RotationPlan, err error) {
+
+	if slots < 2 {
+		return nil, fmt.Errorf("cannot OptimizeRotationSet: slots must be at least 2 but is %d", slots)
+	}
+
+	if budget < 1 {
+		return nil, fmt.Errorf("cannot OptimizeRotationSet: budget must be at least 1 but is %d", budget)
+	}
+
+	if maxCompositions < 1 {
+		return nil, fmt.Errorf("cannot OptimizeRotationSet: maxCompositions must be at least 1 but is %d", maxCompositions)
+	}
+
+	// Multiplicities of the normalized, non-trivial rotations.
+	counts := map[int]int{}
+	for _, k := range rotations {
+		if k = ((k % slots) + slots) % slots; k != 0 {
+			counts[k]++
+		}
+	}
+
+	targets := utils.GetSortedKeys(counts)
+
+	p = &RotationPlan{
+		slots:           slots,
+		maxCompositions: maxCompositions,
+		paths:           map[int][]int{},
+	}
+
+	if len(targets) == 0 {
+		return p, nil
+	}
+
+	// If the budget accommodates one key per distinct rotation, the direct
+	// plan is optimal: every rotation is a single composition.
+	if len(targets) <= budget {
+		for _, t := range targets {
+			s := signedRotation(t, slots)
+			p.steps = append(p.steps, s)
+			p.paths[t] = []int{s}
+		}
+		return p, nil
+	}
+
+	// Candidate steps: the declared rotations themselves and the signed
+	// powers of two, which compose into any rotation in at most log(slots)
+	// steps.
+	candidateSet := map[int]bool{}
+	for _, t := range targets {
+		candidateSet[signedRotation(t, slots)] = true
+	}
+	for i := 1; i < slots; i <<= 1 {
+		candidateSet[signedRotation(i, slots)] = true
+		candidateSet[signedRotation(-i, slots)] = true
+	}
+	candidates := utils.GetSortedKeys(candidateSet)
+
+	// Greedily adds the candidate that covers the largest weight of not yet
+	// reachable rotations, using the total progress towards the remaining
+	// rotations as a tie breaker so that useful intermediate steps are
+	// selected even when they do not complete a composition on their own.
+	var steps []int
+	for len(steps) < budget {
+
+		dist, _ := compositions(slots, maxCompositions, steps)
+
+		covered := true
+		for _, t := range targets {
+			if dist[t] < 0 {
+				covered = false
+				break
+			}
+		}
+		if covered {
+			break
+		}
+
+		bestCovered, bestProgress, bestStep := -1, -1, 0
+		for _, c := range candidates {
+
+			selected := false
+			for _, s := range steps {
+				if s == c {
+					selected = true
+					break
+				}
+			}
+			if selected {
+				continue
+			}
+
+			dist, _ := compositions(slots, maxCompositions, append(steps, c))
+
+			coveredWeight, progress := 0, 0
+			for _, t := range targets {
+				if d := dist[t]; d >= 0 {
+					coveredWeight += counts[t]
+					progress += counts[t] * (maxCompositions + 1 - d)
+				}
+			}
+
+			if coveredWeight > bestCovered || (coveredWeight == bestCovered && progress > bestProgress) {
+				bestCovered, bestProgress, bestStep = coveredWeight, progress, c
+			}
+		}
+
+		steps = append(steps, bestStep)
+	}
+
+	dist, last := compositions(slots, maxCompositions, steps)
+
+	for _, t := range targets {
+		if dist[t] < 0 {
+			return nil, fmt.Errorf("cannot OptimizeRotationSet: no key set of size at most %d reaches all rotations within %d compositions", budget, maxCompositions)
+		}
+		p.paths[t] = path(t, slots, last)
+	}
+
+	p.steps = steps
+
+	return p, nil
+}
+
+// signedRotation maps k to its representative in (-slots/2, slots/2].
+func signedRotation(k, slots int) int {
+	if k = ((k % slots) + slots) % slots; k > slots/2 {
+		k -= slots
+	}
+	return k
+}
+
+// compositions runs a breadth-first search over the rotation group and
+// returns, for every rotation, the minimal number of compositions of the
+// given steps evaluating it (-1 if it is not reachable within maxCompositions)
+// along with the last step of one such shortest composition.
+func compositions(slots, maxCompositions int, steps []int) (dist, last []int) {
+
+	dist = make([]int, slots)
+	last = make([]int, slots)
+	for i := range dist {
+		dist[i] = -1
+	}
+	dist[0] = 0
+
+	frontier := []int{0}
+	for d := 1; d <= maxCompositions && len(frontier) > 0; d++ {
+		var next []int
+		for _, u := range frontier {
+			for _, s := range steps {
+				if v := ((u+s)%slots + slots) % slots; dist[v] < 0 {
+					dist[v], last[v] = d, s
+					next = append(next, v)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	return
+}
+
+// path reconstructs a shortest composition evaluating the rotation t from
+// the last-step table of a breadth-first search.
+func path(t, slots int, last []int) (steps []int) {
+	for t != 0 {
+		s := last[t]
+		steps = append(steps, s)
+		t = ((t-s)%slots + slots) % slots
+	}
+	return
+}
+
+// Steps returns the selected base rotation steps, one Galois key each.
+func (p RotationPlan) Steps() []int {
+	return p.steps
+}
+
+// MaxCompositions returns the composition bound the plan was optimized for.
+func (p RotationPlan) MaxCompositions() int {
+	return p.maxCompositions
+}
+
+// GaloisElements returns the Galois elements of the Galois keys required to
+// evaluate the plan, to be passed to rlwe.KeyGenerator.GenGaloisKeysNew.
+func (p RotationPlan) GaloisElements(params rlwe.ParameterProvider) (galEls []uint64) {
+	return params.GetRLWEParameters().GaloisElements(p.steps)
+}
+
+// Decomposition returns the rotation by k as a sequence of at most
+// MaxCompositions base steps of the plan. Rotations of the declared workload
+// are returned from the plan; other rotations are decomposed on the fly, and
+// an error is returned if the base steps do not reach them within the
+// composition bound.
+func (p RotationPlan) Decomposition(k int) (steps []int, err error) {
+
+	if k = ((k % p.slots) + p.slots) % p.slots; k == 0 {
+		return nil, nil
+	}
+
+	if steps, ok := p.paths[k]; ok {
+		return steps, nil
+	}
+
+	dist, last := compositions(p.slots, p.maxCompositions, p.steps)
+
+	if dist[k] < 0 {
+		return nil, fmt.Errorf("cannot Decomposition: rotation by %d is not reachable within %d compositions", k, p.maxCompositions)
+	}
+
+	return path(k, p.slots, last), nil
+}
+
+// RotateAny evaluates the rotation of op0 by k positions following the plan:
+// the rotation is decomposed into base steps of the plan and evaluated as a
+// chain of at most plan.MaxCompositions() rotations, so that only the Galois
+// keys of plan.GaloisElements need to be loaded in the evaluator. The
+// evaluator must implement the SlotRotator capability.
+func RotateAny(eval Evaluator, plan *RotationPlan, op0 *rlwe.Ciphertext, k int, opOut *rlwe.Ciphertext) (err error) {
+
+	rot, ok := eval.(SlotRotator)
+	if !ok {
+		return fmt.Errorf("cannot RotateAny: evaluator does not implement he.SlotRotator")
+	}
+
+	steps, err := plan.Decomposition(k)
+	if err != nil {
+		return fmt.Errorf("cannot RotateAny: %w", err)
+	}
+
+	if len(steps) == 0 {
+		if op0 != opOut {
+			opOut.Copy(op0)
+		}
+		return nil
+	}
+
+	if err = rot.RotateSlots(op0, steps[0], opOut); err != nil {
+		return fmt.Errorf("cannot RotateAny: %w", err)
+	}
+
+	for _, s := range steps[1:] {
+		if err = rot.RotateSlots(opOut, s, opOut); err != nil {
+			return fmt.Errorf("cannot RotateAny: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package he_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/he"
+	"github.com/luxdefi/lattice/v5/he/hefloat"
+)
+
+func TestRotationPlan(t *testing.T) {
+
+	slots := 1 << 9
+
+	// checkPlan verifies that every rotation of the workload decomposes into
+	// at most maxCompositions base steps of the plan, and that the steps of
+	// the decomposition sum to the rotation.
+	checkPlan := func(t *testing.T, p *he.RotationPlan, rotations []int, budget, maxCompositions int) {
+		require.LessOrEqual(t, len(p.Steps()), budget)
+		for _, k := range rotations {
+			steps, err := p.Decomposition(k)
+			require.NoError(t, err)
+			require.LessOrEqual(t, len(steps), maxCompositions)
+			sum := 0
+			for _, s := range steps {
+				sum += s
+			}
+			require.Equal(t, ((k%slots)+slots)%slots, ((sum%slots)+slots)%slots)
+		}
+	}
+
+	t.Run("Direct", func(t *testing.T) {
+		rotations := []int{1, 1, -2, 17}
+		p, err := he.OptimizeRotationSet(rotations, slots, 4, 1)
+		require.NoError(t, err)
+		require.Equal(t, 3, len(p.Steps()))
+		checkPlan(t, p, rotations, 4, 1)
+	})
+
+	t.Run("Composed", func(t *testing.T) {
+		rotations := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+		p, err := he.OptimizeRotationSet(rotations, slots, 4, 4)
+		require.NoError(t, err)
+		checkPlan(t, p, rotations, 4, 4)
+
+		// Rotations outside the declared workload are decomposed on the fly
+		// when the base steps reach them.
+		if _, err := p.Decomposition(10 + slots); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("Infeasible", func(t *testing.T) {
+		_, err := he.OptimizeRotationSet([]int{1, 2, 3}, slots, 2, 1)
+		require.Error(t, err)
+	})
+
+	t.Run("RotateAny", func(t *testing.T) {
+
+		// <<<<!Insecure parameters!>>>>
+		params, err := hefloat.NewParametersFromLiteral(hefloat.ParametersLiteral{
+			LogN:            10,
+			LogQ:            []int{55, 45, 45},
+			LogP:            []int{60},
+			LogDefaultScale: 45,
+		})
+		require.NoError(t, err)
+
+		rotations := []int{1, 3, 5, 7, 11, 13}
+		p, err := he.OptimizeRotationSet(rotations, params.MaxSlots(), 3, 3)
+		require.NoError(t, err)
+		checkPlan(t, p, rotations, 3, 3)
+
+		kgen := hefloat.NewKeyGenerator(params)
+		sk := kgen.GenSecretKeyNew()
+
+		eval := hefloat.NewEvaluator(params, rlwe.NewMemEvaluationKeySet(nil, kgen.GenGaloisKeysNew(p.GaloisElements(params), sk)...))
+
+		ecd := hefloat.NewEncoder(params)
+		enc := hefloat.NewEncryptor(params, sk)
+		dec := hefloat.NewDecryptor(params, sk)
+
+		values := make([]float64, params.MaxSlots())
+		for i := range values {
+			values[i] = float64(i)
+		}
+
+		pt := hefloat.NewPlaintext(params, params.MaxLevel())
+		require.NoError(t, ecd.Encode(values, pt))
+		ct, err := enc.EncryptNew(pt)
+		require.NoError(t, err)
+
+		have := make([]float64, params.MaxSlots())
+		for _, k := range rotations {
+
+			out := hefloat.NewCiphertext(params, 1, ct.Level())
+			require.NoError(t, he.RotateAny(eval, p, ct, k, out))
+
+			require.NoError(t, ecd.Decode(dec.DecryptNew(out), have))
+			for i := 0; i < 16; i++ {
+				require.InDelta(t, values[(i+k)%len(values)], have[i], 1e-4)
+			}
+		}
+	})
+}
@@ -0,0 +1,110 @@
+package he
+
+import (
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+)
+
+// Encryptor defines a set of common and scheme agnostic methods provided by an
+// Encryptor struct. It is implemented by *rlwe.Encryptor, returned by the
+// NewEncryptor constructors of the hefloat and heint front-ends.
+type Encryptor interface {
+	Encrypt(pt *rlwe.Plaintext, ct interface{}) (err error)
+	EncryptNew(pt *rlwe.Plaintext) (ct *rlwe.Ciphertext, err error)
+	EncryptZero(ct interface{}) (err error)
+}
+
+// Decryptor defines a set of common and scheme agnostic methods provided by a
+// Decryptor struct. It is implemented by *rlwe.Decryptor, returned by the
+// NewDecryptor constructors of the hefloat and heint front-ends.
+type Decryptor interface {
+	Decrypt(ct *rlwe.Ciphertext, pt *rlwe.Plaintext)
+	DecryptNew(ct *rlwe.Ciphertext) (pt *rlwe.Plaintext)
+}
+
+// SlotEncoder defines a set of common and scheme agnostic methods to encode
+// and decode slices of values on plaintext slots, implemented by the
+// hefloat.Encoder and heint.Encoder front-ends. The values argument is a slice
+// of the scheme's plaintext element type: []complex128, []float64,
+// []*bignum.Complex or []*big.Float for hefloat and []uint64 or []int64 for
+// heint.
+type SlotEncoder interface {
+	EncodeSlots(values interface{}, pt *rlwe.Plaintext) (err error)
+	DecodeSlots(pt *rlwe.Plaintext, values interface{}) (err error)
+}
+
+// SlotRotator is implemented by evaluators that cyclically rotate the
+// plaintext slots of a ciphertext (hefloat.Evaluator, where it maps to
+// Rotate, and heint.Evaluator, where it maps to RotateColumns).
+type SlotRotator interface {
+	RotateSlots(op0 *rlwe.Ciphertext, k int, opOut *rlwe.Ciphertext) (err error)
+}
+
+// Conjugator is implemented by evaluators that evaluate the complex
+// conjugation (hefloat.Evaluator).
+type Conjugator interface {
+	Conjugate(op0 *rlwe.Ciphertext, opOut *rlwe.Ciphertext) (err error)
+}
+
+// RowRotator is implemented by evaluators that swap the rows of the plaintext
+// matrix (heint.Evaluator).
+type RowRotator interface {
+	RotateRows(op0, opOut *rlwe.Ciphertext) (err error)
+}
+
+// ScaleInvariantMultiplier is implemented by evaluators that provide
+// BFV-style scale-invariant tensoring (heint.Evaluator).
+type ScaleInvariantMultiplier interface {
+	MulScaleInvariant(op0 *rlwe.Ciphertext, op1 rlwe.Operand, opOut *rlwe.Ciphertext) (err error)
+	MulRelinScaleInvariant(op0 *rlwe.Ciphertext, op1 rlwe.Operand, opOut *rlwe.Ciphertext) (err error)
+}
+
+// Capability identifies a scheme-specific operation that is not part of the
+// scheme agnostic Evaluator interface. Generic code discovers the
+// capabilities of an evaluator with HasCapability or Capabilities, and
+// accesses the corresponding operations by asserting the evaluator to the
+// capability's interface (see SlotRotator, Conjugator, RowRotator and
+// ScaleInvariantMultiplier).
+type Capability int
+
+const (
+	// CapabilitySlotRotation indicates that the evaluator implements SlotRotator.
+	CapabilitySlotRotation Capability = iota
+	// CapabilityConjugation indicates that the evaluator implements Conjugator.
+	CapabilityConjugation
+	// CapabilityRowRotation indicates that the evaluator implements RowRotator.
+	CapabilityRowRotation
+	// CapabilityScaleInvariantTensoring indicates that the evaluator
+	// implements ScaleInvariantMultiplier.
+	CapabilityScaleInvariantTensoring
+)
+
+// HasCapability reports whether the evaluator supports the scheme-specific
+// operations identified by c.
+func HasCapability(eval Evaluator, c Capability) (ok bool) {
+	switch c {
+	case CapabilitySlotRotation:
+		_, ok = eval.(SlotRotator)
+	case CapabilityConjugation:
+		_, ok = eval.(Conjugator)
+	case CapabilityRowRotation:
+		_, ok = eval.(RowRotator)
+	case CapabilityScaleInvariantTensoring:
+		_, ok = eval.(ScaleInvariantMultiplier)
+	}
+	return
+}
+
+// Capabilities returns the scheme-specific capabilities of the evaluator.
+func Capabilities(eval Evaluator) (caps []Capability) {
+	for _, c := range []Capability{
+		CapabilitySlotRotation,
+		CapabilityConjugation,
+		CapabilityRowRotation,
+		CapabilityScaleInvariantTensoring,
+	} {
+		if HasCapability(eval, c) {
+			caps = append(caps, c)
+		}
+	}
+	return
+}
@@ -0,0 +1,102 @@
+package he_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/he"
+	"github.com/luxdefi/lattice/v5/he/hefloat"
+	"github.com/luxdefi/lattice/v5/he/heint"
+)
+
+// doubleAndRotate encrypts values, doubles them and rotates the slots by one
+// position to the left, going only through the scheme agnostic interfaces.
+func doubleAndRotate(t *testing.T, ecd he.SlotEncoder, enc he.Encryptor, dec he.Decryptor, eval he.Evaluator, pt *rlwe.Plaintext, values, have interface{}) {
+
+	require.NoError(t, ecd.EncodeSlots(values, pt))
+
+	ct, err := enc.EncryptNew(pt)
+	require.NoError(t, err)
+
+	require.NoError(t, eval.Add(ct, ct, ct))
+
+	require.True(t, he.HasCapability(eval, he.CapabilitySlotRotation))
+	require.NoError(t, eval.(he.SlotRotator).RotateSlots(ct, 1, ct))
+
+	require.NoError(t, ecd.DecodeSlots(dec.DecryptNew(ct), have))
+}
+
+func TestSchemeAgnosticFrontEnds(t *testing.T) {
+
+	t.Run("hefloat", func(t *testing.T) {
+
+		// <<<<!Insecure parameters!>>>>
+		params, err := hefloat.NewParametersFromLiteral(hefloat.ParametersLiteral{
+			LogN:            10,
+			LogQ:            []int{55, 45, 45},
+			LogP:            []int{60},
+			LogDefaultScale: 45,
+		})
+		require.NoError(t, err)
+
+		kgen := hefloat.NewKeyGenerator(params)
+		sk := kgen.GenSecretKeyNew()
+		evk := rlwe.NewMemEvaluationKeySet(nil, kgen.GenGaloisKeysNew([]uint64{params.GaloisElement(1)}, sk)...)
+
+		eval := hefloat.NewEvaluator(params, evk)
+
+		slots := params.MaxSlots()
+		values := make([]float64, slots)
+		for i := range values {
+			values[i] = float64(i)
+		}
+
+		have := make([]float64, slots)
+		doubleAndRotate(t, hefloat.NewEncoder(params), hefloat.NewEncryptor(params, sk), hefloat.NewDecryptor(params, sk), eval, hefloat.NewPlaintext(params, params.MaxLevel()), values, have)
+
+		for i := range have {
+			require.InDelta(t, 2*values[(i+1)%slots], have[i], 1e-3)
+		}
+
+		require.Equal(t, []he.Capability{he.CapabilitySlotRotation, he.CapabilityConjugation}, he.Capabilities(eval))
+	})
+
+	t.Run("heint", func(t *testing.T) {
+
+		// <<<<!Insecure parameters!>>>>
+		params, err := heint.NewParametersFromLiteral(heint.ParametersLiteral{
+			LogN:             10,
+			LogQ:             []int{55, 45},
+			LogP:             []int{60},
+			PlaintextModulus: 0x101,
+		})
+		require.NoError(t, err)
+
+		kgen := heint.NewKeyGenerator(params)
+		sk := kgen.GenSecretKeyNew()
+		evk := rlwe.NewMemEvaluationKeySet(nil, kgen.GenGaloisKeysNew([]uint64{params.GaloisElement(1)}, sk)...)
+
+		eval := heint.NewEvaluator(params, evk)
+
+		T := params.PlaintextModulus()
+		slots := params.MaxSlots()
+		values := make([]uint64, slots)
+		for i := range values {
+			values[i] = uint64(i) % T
+		}
+
+		have := make([]uint64, slots)
+		doubleAndRotate(t, heint.NewEncoder(params), heint.NewEncryptor(params, sk), heint.NewDecryptor(params, sk), eval, heint.NewPlaintext(params, params.MaxLevel()), values, have)
+
+		// RotateSlots rotates each of the two rows of slots/2 values independently.
+		half := slots >> 1
+		for i := 0; i < half; i++ {
+			require.Equal(t, (2*values[(i+1)%half])%T, have[i])
+			require.Equal(t, (2*values[half+(i+1)%half])%T, have[half+i])
+		}
+
+		require.Equal(t, []he.Capability{he.CapabilitySlotRotation, he.CapabilityRowRotation, he.CapabilityScaleInvariantTensoring}, he.Capabilities(eval))
+	})
+}
@@ -0,0 +1,385 @@
+package he
+
+import (
+	"fmt"
+	"hash/fnv"
+	"io"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/utils/buffer"
+)
+
+// TraceOp identifies the Evaluator operation of a TraceEntry.
+type TraceOp uint8
+
+const (
+	TraceOpAdd TraceOp = iota
+	TraceOpSub
+	TraceOpMul
+	TraceOpMulRelin
+	TraceOpMulThenAdd
+	TraceOpRelinearize
+	TraceOpRescale
+	// TraceOpRotate and TraceOpConjugate identify scheme-specific
+	// operations, which are not intercepted by the TraceRecorder and are
+	// recorded manually with Record.
+	TraceOpRotate
+	TraceOpConjugate
+)
+
+// String returns the name of the Evaluator operation.
+func (op TraceOp) String() string {
+	switch op {
+	case TraceOpAdd:
+		return "Add"
+	case TraceOpSub:
+		return "Sub"
+	case TraceOpMul:
+		return "Mul"
+	case TraceOpMulRelin:
+		return "MulRelin"
+	case TraceOpMulThenAdd:
+		return "MulThenAdd"
+	case TraceOpRelinearize:
+		return "Relinearize"
+	case TraceOpRescale:
+		return "Rescale"
+	case TraceOpRotate:
+		return "Rotate"
+	case TraceOpConjugate:
+		return "Conjugate"
+	default:
+		return fmt.Sprintf("TraceOp(%d)", uint8(op))
+	}
+}
+
+// TraceEntry records one evaluator operation: its op code and the
+// fingerprints of its operands and of its result. In1 is zero for unary
+// operations.
+type TraceEntry struct {
+	Op            TraceOp
+	In0, In1, Out uint64
+}
+
+// String returns a human readable representation of the entry.
+func (e TraceEntry) String() string {
+	return fmt.Sprintf("%s(%016x, %016x) -> %016x", e.Op, e.In0, e.In1, e.Out)
+}
+
+// Fingerprint returns a deterministic 64-bit digest of an operand of an
+// evaluator operation: the FNV-64a digest of its serialization for
+// serializable operands (ciphertexts, plaintexts, ...) and of its textual
+// representation for scalar operands. A nil operand has fingerprint zero.
+func Fingerprint(op interface{}) uint64 {
+
+	if op == nil {
+		return 0
+	}
+
+	h := fnv.New64a()
+
+	switch op := op.(type) {
+	case io.WriterTo:
+		// Writing to the hash cannot fail.
+		if _, err := op.WriteTo(h); err != nil {
+			// Sanity check, this error should not happen.
+			panic(err)
+		}
+	default:
+		fmt.Fprintf(h, "%T %v", op, op)
+	}
+
+	return h.Sum64()
+}
+
+// Trace is a compact binary log of evaluator operations recorded by a
+// TraceRecorder.
+type Trace struct {
+	Entries []TraceEntry
+}
+
+// FirstDivergence returns the index of the first entry at which the two
+// traces differ, comparing op codes and fingerprints, or -1 if the traces
+// are identical. If one trace is a strict prefix of the other, the length
+// of the shorter one is returned.
+func (t Trace) FirstDivergence(other Trace) int {
+
+	for i := range t.Entries {
+
+		if i >= len(other.Entries) {
+			return i
+		}
+
+		if t.Entries[i] != other.Entries[i] {
+			return i
+		}
+	}
+
+	if len(other.Entries) > len(t.Entries) {
+		return len(t.Entries)
+	}
+
+	return -1
+}
+
+// Equal performs a deep equal.
+func (t Trace) Equal(other *Trace) bool {
+	return t.FirstDivergence(*other) == -1
+}
+
+// BinarySize returns the serialized size of the object in bytes.
+func (t Trace) BinarySize() int {
+	return 8 + 25*len(t.Entries)
+}
+
+// WriteTo writes the object on an io.Writer. It implements the io.WriterTo
+// interface, and will write exactly object.BinarySize() bytes on w.
+//
+// Unless w implements the buffer.Writer interface (see lattice/utils/buffer/writer.go),
+// it will be wrapped into a bufio.Writer. Since this requires allocations, it
+// is preferable to pass a buffer.Writer directly:
+//
+//   - When writing multiple times to a io.Writer, it is preferable to first wrap the
+//     io.Writer in a pre-allocated bufio.Writer.
+//   - When writing to a pre-allocated var b []byte, it is preferable to pass
+//     buffer.NewBuffer(b) as w (see lattice/utils/buffer/buffer.go).
+func (t Trace) WriteTo(w io.Writer) (n int64, err error) {
+	switch w := w.(type) {
+	case buffer.Writer:
+
+		var inc int64
+
+		if inc, err = buffer.WriteUint64(w, uint64(len(t.Entries))); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		for _, e := range t.Entries {
+
+			if inc, err = buffer.WriteUint8(w, uint8(e.Op)); err != nil {
+				return n + inc, err
+			}
+
+			n += inc
+
+			for _, fp := range []uint64{e.In0, e.In1, e.Out} {
+
+				if inc, err = buffer.WriteUint64(w, fp); err != nil {
+					return n + inc, err
+				}
+
+				n += inc
+			}
+		}
+
+		return n, w.Flush()
+
+	default:
+		return buffer.WriteWithPool(w, t.WriteTo)
+	}
+}
+
+// ReadFrom reads on the object from an io.Writer. It implements the
+// io.ReaderFrom interface.
+//
+// Unless r implements the buffer.Reader interface (see see lattice/utils/buffer/reader.go),
+// it will be wrapped into a bufio.Reader. Since this requires allocation, it
+// is preferable to pass a buffer.Reader directly:
+//
+//   - When reading multiple values from a io.Reader, it is preferable to first
+//     first wrap io.Reader in a pre-allocated bufio.Reader.
+//   - When reading from a var b []byte, it is preferable to pass a buffer.NewBuffer(b)
+//     as w (see lattice/utils/buffer/buffer.go).
+func (t *Trace) ReadFrom(r io.Reader) (n int64, err error) {
+	switch r := r.(type) {
+	case buffer.Reader:
+
+		var inc int64
+
+		var count uint64
+		if inc, err = buffer.ReadUint64(r, &count); err != nil {
+			return n + inc, err
+		}
+
+		n += inc
+
+		t.Entries = make([]TraceEntry, count)
+
+		for i := range t.Entries {
+
+			var op uint8
+			if inc, err = buffer.ReadUint8(r, &op); err != nil {
+				return n + inc, err
+			}
+
+			n += inc
+
+			t.Entries[i].Op = TraceOp(op)
+
+			for _, fp := range []*uint64{&t.Entries[i].In0, &t.Entries[i].In1, &t.Entries[i].Out} {
+
+				if inc, err = buffer.ReadUint64(r, fp); err != nil {
+					return n + inc, err
+				}
+
+				n += inc
+			}
+		}
+
+		return
+
+	default:
+		return buffer.ReadWithPool(r, t.ReadFrom)
+	}
+}
+
+// TraceRecorder wraps an Evaluator, delegating every operation to it and
+// recording the operation with the fingerprints of its operands and of its
+// result into a Trace. Recording is opt-in and transparent to the
+// computation: code written against the Evaluator interface runs unmodified
+// over the recorder.
+//
+// Fingerprints are deterministic across processes and platforms, so a
+// heisenbug in a long encrypted computation can be reproduced offline by
+// re-running the computation over a second recorder (e.g. on another
+// machine or build) and comparing the traces with FirstDivergence, which
+// bisects to the first operation producing a different result.
+//
+// The recorder intercepts the operations of the scheme agnostic Evaluator
+// interface; scheme-specific operations (rotations, conjugations, ...) can
+// be added to the trace manually with Record.
+type TraceRecorder struct {
+	Evaluator
+	Trace *Trace
+}
+
+// NewTraceRecorder instantiates a new TraceRecorder over the given
+// Evaluator, recording into an empty Trace.
+func NewTraceRecorder(eval Evaluator) *TraceRecorder {
+	return &TraceRecorder{Evaluator: eval, Trace: &Trace{}}
+}
+
+// Record appends an entry for an operation evaluated outside of the
+// recorder, with the fingerprints of the operands in0 and in1 (which may be
+// nil for unary operations) and of the result out.
+func (rec *TraceRecorder) Record(op TraceOp, in0, in1, out interface{}) {
+	rec.record(op, Fingerprint(in0), Fingerprint(in1), Fingerprint(out))
+}
+
+// record appends an entry with already computed fingerprints.
+func (rec *TraceRecorder) record(op TraceOp, in0, in1, out uint64) {
+	rec.Trace.Entries = append(rec.Trace.Entries, TraceEntry{Op: op, In0: in0, In1: in1, Out: out})
+}
+
+// Add evaluates opOut = op0 + op1 and records the operation.
+func (rec *TraceRecorder) Add(op0 *rlwe.Ciphertext, op1 rlwe.Operand, opOut *rlwe.Ciphertext) (err error) {
+	in0, in1 := Fingerprint(op0), Fingerprint(op1)
+	if err = rec.Evaluator.Add(op0, op1, opOut); err != nil {
+		return
+	}
+	rec.record(TraceOpAdd, in0, in1, Fingerprint(opOut))
+	return
+}
+
+// AddNew evaluates opOut = op0 + op1 and records the operation.
+func (rec *TraceRecorder) AddNew(op0 *rlwe.Ciphertext, op1 rlwe.Operand) (opOut *rlwe.Ciphertext, err error) {
+	in0, in1 := Fingerprint(op0), Fingerprint(op1)
+	if opOut, err = rec.Evaluator.AddNew(op0, op1); err != nil {
+		return
+	}
+	rec.record(TraceOpAdd, in0, in1, Fingerprint(opOut))
+	return
+}
+
+// Sub evaluates opOut = op0 - op1 and records the operation.
+func (rec *TraceRecorder) Sub(op0 *rlwe.Ciphertext, op1 rlwe.Operand, opOut *rlwe.Ciphertext) (err error) {
+	in0, in1 := Fingerprint(op0), Fingerprint(op1)
+	if err = rec.Evaluator.Sub(op0, op1, opOut); err != nil {
+		return
+	}
+	rec.record(TraceOpSub, in0, in1, Fingerprint(opOut))
+	return
+}
+
+// SubNew evaluates opOut = op0 - op1 and records the operation.
+func (rec *TraceRecorder) SubNew(op0 *rlwe.Ciphertext, op1 rlwe.Operand) (opOut *rlwe.Ciphertext, err error) {
+	in0, in1 := Fingerprint(op0), Fingerprint(op1)
+	if opOut, err = rec.Evaluator.SubNew(op0, op1); err != nil {
+		return
+	}
+	rec.record(TraceOpSub, in0, in1, Fingerprint(opOut))
+	return
+}
+
+// Mul evaluates opOut = op0 * op1 and records the operation.
+func (rec *TraceRecorder) Mul(op0 *rlwe.Ciphertext, op1 rlwe.Operand, opOut *rlwe.Ciphertext) (err error) {
+	in0, in1 := Fingerprint(op0), Fingerprint(op1)
+	if err = rec.Evaluator.Mul(op0, op1, opOut); err != nil {
+		return
+	}
+	rec.record(TraceOpMul, in0, in1, Fingerprint(opOut))
+	return
+}
+
+// MulNew evaluates opOut = op0 * op1 and records the operation.
+func (rec *TraceRecorder) MulNew(op0 *rlwe.Ciphertext, op1 rlwe.Operand) (opOut *rlwe.Ciphertext, err error) {
+	in0, in1 := Fingerprint(op0), Fingerprint(op1)
+	if opOut, err = rec.Evaluator.MulNew(op0, op1); err != nil {
+		return
+	}
+	rec.record(TraceOpMul, in0, in1, Fingerprint(opOut))
+	return
+}
+
+// MulRelin evaluates opOut = op0 * op1, relinearized, and records the
+// operation.
+func (rec *TraceRecorder) MulRelin(op0 *rlwe.Ciphertext, op1 rlwe.Operand, opOut *rlwe.Ciphertext) (err error) {
+	in0, in1 := Fingerprint(op0), Fingerprint(op1)
+	if err = rec.Evaluator.MulRelin(op0, op1, opOut); err != nil {
+		return
+	}
+	rec.record(TraceOpMulRelin, in0, in1, Fingerprint(opOut))
+	return
+}
+
+// MulRelinNew evaluates opOut = op0 * op1, relinearized, and records the
+// operation.
+func (rec *TraceRecorder) MulRelinNew(op0 *rlwe.Ciphertext, op1 rlwe.Operand) (opOut *rlwe.Ciphertext, err error) {
+	in0, in1 := Fingerprint(op0), Fingerprint(op1)
+	if opOut, err = rec.Evaluator.MulRelinNew(op0, op1); err != nil {
+		return
+	}
+	rec.record(TraceOpMulRelin, in0, in1, Fingerprint(opOut))
+	return
+}
+
+// MulThenAdd evaluates opOut = opOut + op0 * op1 and records the operation.
+func (rec *TraceRecorder) MulThenAdd(op0 *rlwe.Ciphertext, op1 rlwe.Operand, opOut *rlwe.Ciphertext) (err error) {
+	in0, in1 := Fingerprint(op0), Fingerprint(op1)
+	if err = rec.Evaluator.MulThenAdd(op0, op1, opOut); err != nil {
+		return
+	}
+	rec.record(TraceOpMulThenAdd, in0, in1, Fingerprint(opOut))
+	return
+}
+
+// Relinearize evaluates op1 = relinearize(op0) and records the operation.
+func (rec *TraceRecorder) Relinearize(op0, op1 *rlwe.Ciphertext) (err error) {
+	in0 := Fingerprint(op0)
+	if err = rec.Evaluator.Relinearize(op0, op1); err != nil {
+		return
+	}
+	rec.record(TraceOpRelinearize, in0, 0, Fingerprint(op1))
+	return
+}
+
+// Rescale evaluates op1 = rescale(op0) and records the operation.
+func (rec *TraceRecorder) Rescale(op0, op1 *rlwe.Ciphertext) (err error) {
+	in0 := Fingerprint(op0)
+	if err = rec.Evaluator.Rescale(op0, op1); err != nil {
+		return
+	}
+	rec.record(TraceOpRescale, in0, 0, Fingerprint(op1))
+	return
+}
@@ -0,0 +1,95 @@
+package he_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/he"
+	"github.com/luxdefi/lattice/v5/he/hefloat"
+)
+
+func TestTrace(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := hefloat.NewParametersFromLiteral(hefloat.ParametersLiteral{
+		LogN:            10,
+		LogQ:            []int{55, 45, 45},
+		LogP:            []int{60},
+		LogDefaultScale: 45,
+	})
+	require.NoError(t, err)
+
+	kgen := hefloat.NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+
+	eval := hefloat.NewEvaluator(params, rlwe.NewMemEvaluationKeySet(kgen.GenRelinearizationKeyNew(sk)))
+
+	ecd := hefloat.NewEncoder(params)
+	enc := hefloat.NewEncryptor(params, sk)
+
+	newCt := func(v float64) *rlwe.Ciphertext {
+		values := make([]float64, params.MaxSlots())
+		for i := range values {
+			values[i] = v
+		}
+		pt := hefloat.NewPlaintext(params, params.MaxLevel())
+		require.NoError(t, ecd.Encode(values, pt))
+		ct, err := enc.EncryptNew(pt)
+		require.NoError(t, err)
+		return ct
+	}
+
+	ct0, ct1 := newCt(0.5), newCt(0.25)
+
+	// run evaluates a small deterministic circuit through the scheme
+	// agnostic interface.
+	run := func(eval he.Evaluator) {
+		ct, err := eval.MulRelinNew(ct0, ct1)
+		require.NoError(t, err)
+		require.NoError(t, eval.Rescale(ct, ct))
+		require.NoError(t, eval.Add(ct, ct1, ct))
+	}
+
+	rec0 := he.NewTraceRecorder(eval)
+	run(rec0)
+	require.Equal(t, 3, len(rec0.Trace.Entries))
+
+	// Re-running the same computation over the same inputs produces the
+	// same trace.
+	rec1 := he.NewTraceRecorder(eval)
+	run(rec1)
+	require.True(t, rec0.Trace.Equal(rec1.Trace))
+	require.Equal(t, -1, rec0.Trace.FirstDivergence(*rec1.Trace))
+
+	// A diverging computation is bisected to its first differing operation.
+	rec2 := he.NewTraceRecorder(eval)
+	ct, err := rec2.MulRelinNew(ct0, ct1)
+	require.NoError(t, err)
+	require.NoError(t, rec2.Rescale(ct, ct))
+	require.NoError(t, rec2.Sub(ct, ct1, ct))
+	require.Equal(t, 2, rec0.Trace.FirstDivergence(*rec2.Trace))
+
+	// A truncated trace diverges at its length.
+	truncated := he.Trace{Entries: rec0.Trace.Entries[:2]}
+	require.Equal(t, 2, rec0.Trace.FirstDivergence(truncated))
+	require.Equal(t, 2, truncated.FirstDivergence(*rec0.Trace))
+
+	// Scheme-specific operations are recorded manually.
+	rec0.Record(he.TraceOpRotate, ct0, 1, ct1)
+	require.Equal(t, 4, len(rec0.Trace.Entries))
+	require.Contains(t, rec0.Trace.Entries[3].String(), "Rotate")
+
+	// Serialization round-trip.
+	buf := new(bytes.Buffer)
+	n, err := rec0.Trace.WriteTo(buf)
+	require.NoError(t, err)
+	require.Equal(t, rec0.Trace.BinarySize(), int(n))
+
+	read := new(he.Trace)
+	_, err = read.ReadFrom(buf)
+	require.NoError(t, err)
+	require.True(t, rec0.Trace.Equal(read))
+}
@@ -11,6 +11,13 @@ type AdditiveShare struct {
 	Value ring.Poly
 }
 
+// Equal performs a deep equal. The comparison runs in constant time with
+// respect to the share coefficients, so that it is safe to use on secret
+// material (see ring.Poly.EqualConstantTime).
+func (share AdditiveShare) Equal(other *AdditiveShare) bool {
+	return share.Value.EqualConstantTime(&other.Value)
+}
+
 // AdditiveShareBigint is a type for storing additively shared values
 // in Z (positional domain).
 type AdditiveShareBigint struct {
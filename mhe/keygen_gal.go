@@ -1,7 +1,6 @@
 package mhe
 
 import (
-	"bufio"
 	"fmt"
 	"io"
 
@@ -135,7 +134,7 @@ func (share GaloisKeyGenShare) WriteTo(w io.Writer) (n int64, err error) {
 		return n, err
 
 	default:
-		return share.WriteTo(bufio.NewWriter(w))
+		return buffer.WriteWithPool(w, share.WriteTo)
 	}
 }
 
@@ -166,7 +165,7 @@ func (share *GaloisKeyGenShare) ReadFrom(r io.Reader) (n int64, err error) {
 
 		return n + inc, nil
 	default:
-		return share.ReadFrom(bufio.NewReader(r))
+		return buffer.ReadWithPool(r, share.ReadFrom)
 	}
 }
 
@@ -285,6 +285,9 @@ func (mltp MaskedLinearTransformationProtocol) Transform(ct *rlwe.Ciphertext, tr
 
 	ciphertextOut.Scale = mltp.s2e.params.DefaultScale()
 
+	// The output is an encryption under the output parameters.
+	ciphertextOut.ParameterFingerprint = mltp.s2e.params.GetRLWEParameters().Fingerprint()
+
 	return
 }
 
@@ -1,7 +1,6 @@
 package mhe
 
 import (
-	"bufio"
 	"io"
 
 	"github.com/luxdefi/lattice/v5/utils/buffer"
@@ -39,7 +38,7 @@ func (share RefreshShare) WriteTo(w io.Writer) (n int64, err error) {
 		inc, err = share.ShareToEncShare.WriteTo(w)
 		return n + inc, err
 	default:
-		return share.WriteTo(bufio.NewWriter(w))
+		return buffer.WriteWithPool(w, share.WriteTo)
 	}
 }
 
@@ -64,7 +63,7 @@ func (share *RefreshShare) ReadFrom(r io.Reader) (n int64, err error) {
 		inc, err = share.ShareToEncShare.ReadFrom(r)
 		return n + inc, err
 	default:
-		return share.ReadFrom(bufio.NewReader(r))
+		return buffer.ReadWithPool(r, share.ReadFrom)
 	}
 }
 
@@ -57,6 +57,13 @@ type ShamirSecretShare struct {
 	ringqp.Poly
 }
 
+// Equal performs a deep equal. The comparison runs in constant time with
+// respect to the share coefficients, so that it is safe to use on secret
+// material (see ringqp.Poly.EqualConstantTime).
+func (share ShamirSecretShare) Equal(other *ShamirSecretShare) bool {
+	return share.Poly.EqualConstantTime(&other.Poly)
+}
+
 // NewThresholdizer creates a new Thresholdizer instance from parameters.
 func NewThresholdizer(params rlwe.ParameterProvider) Thresholdizer {
 
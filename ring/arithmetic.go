@@ -0,0 +1,124 @@
+package ring
+
+import (
+	"fmt"
+)
+
+// ArithmeticMode selects the coefficient representation and the modular
+// reduction used by the multiplication methods of an Arithmetic.
+type ArithmeticMode int
+
+const (
+	// MontgomeryArithmetic multiplies operands held in the Montgomery form,
+	// with Montgomery reduction. It is the fastest mode and the one used
+	// internally by the library; operands must be brought to and from the
+	// Montgomery form with MForm and IMForm.
+	MontgomeryArithmetic ArithmeticMode = iota
+	// BarrettArithmetic multiplies operands held in the standard
+	// representation, with Barrett reduction exclusively; MForm and IMForm
+	// become copies. It is slower than MontgomeryArithmetic, but avoiding
+	// the Montgomery form simplifies the interoperability with systems that
+	// exchange standard-representation coefficients, and provides a
+	// baseline to benchmark the overhead of the representation changes.
+	BarrettArithmetic
+)
+
+// Arithmetic is a view of a Ring whose representation-dependent methods
+// (MForm, IMForm and the generic coefficient-wise multiplications) use the
+// backend selected by an ArithmeticMode, so that the same calling code can
+// run over the Montgomery or the Barrett backend. All the other methods of
+// the embedded Ring (additions, NTT, sampling, ...) are representation
+// agnostic and are unaffected by the mode.
+type Arithmetic struct {
+	Ring
+	mode ArithmeticMode
+}
+
+// Arithmetic returns a view of the receiver whose representation-dependent
+// methods use the given mode.
+func (r Ring) Arithmetic(mode ArithmeticMode) Arithmetic {
+	switch mode {
+	case MontgomeryArithmetic, BarrettArithmetic:
+	default:
+		// Sanity check, this error should not happen.
+		panic(fmt.Errorf("invalid ArithmeticMode: %d", mode))
+	}
+	return Arithmetic{Ring: r, mode: mode}
+}
+
+// Mode returns the ArithmeticMode of the target.
+func (a Arithmetic) Mode() ArithmeticMode {
+	return a.mode
+}
+
+// MForm brings p1 to the operand representation of the mode and writes the
+// result on p2: the Montgomery form for MontgomeryArithmetic, the identity
+// (a copy) for BarrettArithmetic.
+func (a Arithmetic) MForm(p1, p2 Poly) {
+	if a.mode == MontgomeryArithmetic {
+		a.Ring.MForm(p1, p2)
+		return
+	}
+	p2.Copy(p1)
+}
+
+// IMForm brings p1 back from the operand representation of the mode and
+// writes the result on p2: the inverse Montgomery form for
+// MontgomeryArithmetic, the identity (a copy) for BarrettArithmetic.
+func (a Arithmetic) IMForm(p1, p2 Poly) {
+	if a.mode == MontgomeryArithmetic {
+		a.Ring.IMForm(p1, p2)
+		return
+	}
+	p2.Copy(p1)
+}
+
+// MulCoeffs evaluates p3 = p1 * p2 coefficient-wise in the ring, with the
+// reduction of the mode, on operands in the representation of the mode.
+func (a Arithmetic) MulCoeffs(p1, p2, p3 Poly) {
+	if a.mode == MontgomeryArithmetic {
+		a.MulCoeffsMontgomery(p1, p2, p3)
+		return
+	}
+	a.MulCoeffsBarrett(p1, p2, p3)
+}
+
+// MulCoeffsLazy evaluates p3 = p1 * p2 coefficient-wise in the ring, with
+// the reduction of the mode, with p3 in [0, 2*modulus-1].
+func (a Arithmetic) MulCoeffsLazy(p1, p2, p3 Poly) {
+	if a.mode == MontgomeryArithmetic {
+		a.MulCoeffsMontgomeryLazy(p1, p2, p3)
+		return
+	}
+	a.MulCoeffsBarrettLazy(p1, p2, p3)
+}
+
+// MulCoeffsThenAdd evaluates p3 = p3 + p1 * p2 coefficient-wise in the
+// ring, with the reduction of the mode.
+func (a Arithmetic) MulCoeffsThenAdd(p1, p2, p3 Poly) {
+	if a.mode == MontgomeryArithmetic {
+		a.MulCoeffsMontgomeryThenAdd(p1, p2, p3)
+		return
+	}
+	a.MulCoeffsBarrettThenAdd(p1, p2, p3)
+}
+
+// MulCoeffsThenAddLazy evaluates p3 = p3 + p1 * p2 coefficient-wise in the
+// ring, with the reduction of the mode, with p3 in [0, 2*modulus-1].
+func (a Arithmetic) MulCoeffsThenAddLazy(p1, p2, p3 Poly) {
+	if a.mode == MontgomeryArithmetic {
+		a.MulCoeffsMontgomeryThenAddLazy(p1, p2, p3)
+		return
+	}
+	a.MulCoeffsBarrettThenAddLazy(p1, p2, p3)
+}
+
+// MulCoeffsThenSub evaluates p3 = p3 - p1 * p2 coefficient-wise in the
+// ring, with the reduction of the mode.
+func (a Arithmetic) MulCoeffsThenSub(p1, p2, p3 Poly) {
+	if a.mode == MontgomeryArithmetic {
+		a.MulCoeffsMontgomeryThenSub(p1, p2, p3)
+		return
+	}
+	a.MulCoeffsBarrettThenSub(p1, p2, p3)
+}
@@ -0,0 +1,68 @@
+package ring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxdefi/lattice/v5/utils/sampling"
+)
+
+func TestArithmetic(t *testing.T) {
+
+	r, err := NewRing(1<<6, Qi60[:2])
+	require.NoError(t, err)
+
+	prng, err := sampling.NewPRNG()
+	require.NoError(t, err)
+
+	sampler := NewUniformSampler(prng, r)
+
+	p1 := sampler.ReadNew()
+	p2 := sampler.ReadNew()
+
+	montgomery := r.Arithmetic(MontgomeryArithmetic)
+	barrett := r.Arithmetic(BarrettArithmetic)
+
+	require.Equal(t, MontgomeryArithmetic, montgomery.Mode())
+	require.Equal(t, BarrettArithmetic, barrett.Mode())
+
+	// The same calling sequence produces the same result over both backends.
+	eval := func(a Arithmetic) (p Poly) {
+		m1, m2 := r.NewPoly(), r.NewPoly()
+		a.MForm(p1, m1)
+		a.MForm(p2, m2)
+
+		p = r.NewPoly()
+		a.MulCoeffs(m1, m2, p)
+		a.MulCoeffsThenAdd(m1, m2, p)
+		a.MulCoeffsThenSub(m1, m1, p)
+		a.IMForm(p, p)
+		return
+	}
+
+	require.True(t, r.Equal(eval(montgomery), eval(barrett)))
+
+	// The lazy variants agree after reduction.
+	evalLazy := func(a Arithmetic) (p Poly) {
+		m1, m2 := r.NewPoly(), r.NewPoly()
+		a.MForm(p1, m1)
+		a.MForm(p2, m2)
+
+		p = r.NewPoly()
+		a.MulCoeffsLazy(m1, m2, p)
+		a.MulCoeffsThenAddLazy(m1, m2, p)
+		r.Reduce(p, p)
+		a.IMForm(p, p)
+		return
+	}
+
+	require.True(t, r.Equal(evalLazy(montgomery), evalLazy(barrett)))
+
+	// In the Barrett mode, the operand representation is the standard one.
+	pt := r.NewPoly()
+	barrett.MForm(p1, pt)
+	require.True(t, r.Equal(p1, pt))
+
+	require.Panics(t, func() { r.Arithmetic(ArithmeticMode(-1)) })
+}
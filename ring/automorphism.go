@@ -3,6 +3,7 @@ package ring
 import (
 	"fmt"
 	"math/bits"
+	"sync"
 	"unsafe"
 
 	"github.com/luxdefi/lattice/v5/utils"
@@ -33,10 +34,66 @@ func AutomorphismNTTIndex(N int, NthRoot, GalEl uint64) (index []uint64, err err
 	return
 }
 
+// automorphismIndexCache caches the automorphism look-up tables generated
+// since the start of the process, keyed by (N, NthRoot, galEl). Tables are
+// read-only once generated and shared between all the callers of
+// AutomorphismNTTIndexShared; a table is 8N bytes and the set of Galois
+// elements used by a process is small, so entries are never evicted.
+var automorphismIndexCache = struct {
+	sync.RWMutex
+	table map[[3]uint64][]uint64
+}{table: map[[3]uint64][]uint64{}}
+
+// AutomorphismNTTIndexShared returns the look-up table for the automorphism
+// X^{i} -> X^{i*k mod NthRoot}, generating and caching it if the tuple
+// (N, NthRoot, GalEl) is seen for the first time. The returned table is
+// shared across callers and must not be modified; use AutomorphismNTTIndex
+// to obtain a private copy instead.
+func AutomorphismNTTIndexShared(N int, NthRoot, GalEl uint64) (index []uint64, err error) {
+
+	key := [3]uint64{uint64(N), NthRoot, GalEl}
+
+	automorphismIndexCache.RLock()
+	index, ok := automorphismIndexCache.table[key]
+	automorphismIndexCache.RUnlock()
+
+	if ok {
+		return
+	}
+
+	if index, err = AutomorphismNTTIndex(N, NthRoot, GalEl); err != nil {
+		return nil, err
+	}
+
+	automorphismIndexCache.Lock()
+	// The table might have been generated concurrently; the first cached
+	// entry wins so that all callers share the same backing.
+	if cached, ok := automorphismIndexCache.table[key]; ok {
+		index = cached
+	} else {
+		automorphismIndexCache.table[key] = index
+	}
+	automorphismIndexCache.Unlock()
+
+	return
+}
+
+// WarmupAutomorphismNTTIndex precomputes and caches the look-up tables for a
+// declared set of Galois elements (see AutomorphismNTTIndexShared), so that
+// evaluators instantiated later do not pay the generation cost.
+func WarmupAutomorphismNTTIndex(N int, NthRoot uint64, galEls []uint64) (err error) {
+	for _, galEl := range galEls {
+		if _, err = AutomorphismNTTIndexShared(N, NthRoot, galEl); err != nil {
+			return
+		}
+	}
+	return
+}
+
 // AutomorphismNTT applies the automorphism X^{i} -> X^{i*gen} on a polynomial in the NTT domain.
 // It must be noted that the result cannot be in-place.
 func (r Ring) AutomorphismNTT(polIn Poly, gen uint64, polOut Poly) {
-	index, err := AutomorphismNTTIndex(r.N(), r.NthRoot(), gen)
+	index, err := AutomorphismNTTIndexShared(r.N(), r.NthRoot(), gen)
 	// Sanity check, this error should not happen.
 	if err != nil {
 		panic(err)
@@ -1,8 +1,10 @@
 package ring
 
 import (
+	"encoding/binary"
 	"math"
 	"math/bits"
+	"sync"
 	"unsafe"
 
 	"github.com/luxdefi/lattice/v5/utils/bignum"
@@ -48,29 +50,96 @@ func genmodDownConstants(ringQ, ringP *Ring) (constants [][]uint64) {
 	return
 }
 
-// NewBasisExtender creates a new BasisExtender, enabling RNS basis extension from Q to P and P to Q.
-func NewBasisExtender(ringQ, ringP *Ring) (be *BasisExtender) {
+// basisExtenderConstants holds the moduli-dependent constants of a
+// BasisExtender. They only depend on the moduli chains Q and P and are
+// read-only once generated, so they are shared between all the instances
+// created for the same pair of chains (see basisExtenderConstantsFor).
+type basisExtenderConstants struct {
+	constantsQtoP        []ModUpConstants
+	constantsPtoQ        []ModUpConstants
+	modDownConstantsPtoQ [][]uint64
+	modDownConstantsQtoP [][]uint64
+}
 
-	be = new(BasisExtender)
-
-	be.ringQ = ringQ
-	be.ringP = ringP
+// basisExtenderCache caches the constants of the basis extensions
+// instantiated since the start of the process, keyed by the concatenation of
+// the two moduli chains. Entries are a few MB at most and the number of
+// distinct moduli chains used by a process is small, so entries are never
+// evicted.
+var basisExtenderCache = struct {
+	sync.RWMutex
+	table map[string]*basisExtenderConstants
+}{table: map[string]*basisExtenderConstants{}}
+
+// basisExtenderConstantsFor returns the basis extension constants for the
+// moduli chains of ringQ and ringP, generating and caching them if the pair
+// is seen for the first time.
+func basisExtenderConstantsFor(ringQ, ringP *Ring) (constants *basisExtenderConstants) {
 
 	Q := ringQ.ModuliChain()
 	P := ringP.ModuliChain()
 
-	be.constantsQtoP = make([]ModUpConstants, ringQ.ModuliChainLength())
+	key := make([]byte, 0, (len(Q)+len(P)+1)<<3)
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], uint64(len(Q)))
+	key = append(key, b[:]...)
+	for _, qi := range append(append([]uint64{}, Q...), P...) {
+		binary.LittleEndian.PutUint64(b[:], qi)
+		key = append(key, b[:]...)
+	}
+
+	basisExtenderCache.RLock()
+	constants, ok := basisExtenderCache.table[string(key)]
+	basisExtenderCache.RUnlock()
+
+	if ok {
+		return
+	}
+
+	constants = new(basisExtenderConstants)
+
+	constants.constantsQtoP = make([]ModUpConstants, len(Q))
 	for i := range Q {
-		be.constantsQtoP[i] = GenModUpConstants(Q[:i+1], P)
+		constants.constantsQtoP[i] = GenModUpConstants(Q[:i+1], P)
 	}
 
-	be.constantsPtoQ = make([]ModUpConstants, ringP.ModuliChainLength())
+	constants.constantsPtoQ = make([]ModUpConstants, len(P))
 	for i := range P {
-		be.constantsPtoQ[i] = GenModUpConstants(P[:i+1], Q)
+		constants.constantsPtoQ[i] = GenModUpConstants(P[:i+1], Q)
 	}
 
-	be.modDownConstantsPtoQ = genmodDownConstants(ringQ, ringP)
-	be.modDownConstantsQtoP = genmodDownConstants(ringP, ringQ)
+	constants.modDownConstantsPtoQ = genmodDownConstants(ringQ, ringP)
+	constants.modDownConstantsQtoP = genmodDownConstants(ringP, ringQ)
+
+	basisExtenderCache.Lock()
+	// The constants might have been generated concurrently; the first
+	// cached entry wins so that all instances share the same backing.
+	if cached, ok := basisExtenderCache.table[string(key)]; ok {
+		constants = cached
+	} else {
+		basisExtenderCache.table[string(key)] = constants
+	}
+	basisExtenderCache.Unlock()
+
+	return
+}
+
+// NewBasisExtender creates a new BasisExtender, enabling RNS basis extension from Q to P and P to Q.
+// The moduli-dependent constants are shared between all the BasisExtender instances created for the
+// same pair of moduli chains, so short-lived instances are cheap to create.
+func NewBasisExtender(ringQ, ringP *Ring) (be *BasisExtender) {
+
+	be = new(BasisExtender)
+
+	be.ringQ = ringQ
+	be.ringP = ringP
+
+	constants := basisExtenderConstantsFor(ringQ, ringP)
+
+	be.constantsQtoP = constants.constantsQtoP
+	be.constantsPtoQ = constants.constantsPtoQ
+	be.modDownConstantsPtoQ = constants.modDownConstantsPtoQ
+	be.modDownConstantsQtoP = constants.modDownConstantsQtoP
 
 	be.buffQ = ringQ.NewPoly()
 	be.buffP = ringP.NewPoly()
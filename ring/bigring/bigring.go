@@ -0,0 +1,195 @@
+// Package bigring implements exact polynomial arithmetic over the ring
+// Z_Q[X]/(X^N + 1) with big.Int coefficients, over the same cyclotomic as the
+// RNS rings of the ring package. It trades speed for exactness and is meant
+// for reference implementations, noise analysis and debugging of RNS code,
+// not for production evaluation.
+package bigring
+
+import (
+	"fmt"
+	"math/big"
+	"math/bits"
+
+	"github.com/luxdefi/lattice/v5/ring"
+)
+
+// Ring is a ring Z_Q[X]/(X^N + 1) with exact big.Int coefficient arithmetic.
+type Ring struct {
+	n       int
+	modulus *big.Int
+}
+
+// Poly is a polynomial of the ring, stored as N big.Int coefficients in the
+// positional (non-NTT) domain.
+type Poly struct {
+	Coeffs []*big.Int
+}
+
+// NewRing creates a new Ring of degree N (a power of two) and coefficient
+// modulus Q (not necessarily prime nor a product of NTT-friendly primes).
+func NewRing(N int, Q *big.Int) (r *Ring, err error) {
+	if N < 1 || bits.OnesCount64(uint64(N)) != 1 {
+		return nil, fmt.Errorf("invalid ring degree: N=%d is not a power of two", N)
+	}
+	if Q == nil || Q.Sign() < 1 {
+		return nil, fmt.Errorf("invalid modulus: Q must be a strictly positive integer")
+	}
+	return &Ring{n: N, modulus: new(big.Int).Set(Q)}, nil
+}
+
+// NewRingFromRNSRing creates a new Ring over the same cyclotomic and with the
+// same coefficient modulus (at the level of rQ) as the given RNS ring.
+func NewRingFromRNSRing(rQ *ring.Ring) (r *Ring, err error) {
+	return NewRing(rQ.N(), rQ.Modulus())
+}
+
+// N returns the degree of the ring.
+func (r Ring) N() int {
+	return r.n
+}
+
+// Modulus returns a copy of the coefficient modulus of the ring.
+func (r Ring) Modulus() *big.Int {
+	return new(big.Int).Set(r.modulus)
+}
+
+// NewPoly creates a new zero polynomial of the ring.
+func (r Ring) NewPoly() Poly {
+	coeffs := make([]*big.Int, r.n)
+	for i := range coeffs {
+		coeffs[i] = new(big.Int)
+	}
+	return Poly{Coeffs: coeffs}
+}
+
+// Add evaluates p3 = p1 + p2 (mod Q).
+func (r Ring) Add(p1, p2, p3 Poly) {
+	for i := 0; i < r.n; i++ {
+		p3.Coeffs[i].Add(p1.Coeffs[i], p2.Coeffs[i])
+		p3.Coeffs[i].Mod(p3.Coeffs[i], r.modulus)
+	}
+}
+
+// Sub evaluates p3 = p1 - p2 (mod Q).
+func (r Ring) Sub(p1, p2, p3 Poly) {
+	for i := 0; i < r.n; i++ {
+		p3.Coeffs[i].Sub(p1.Coeffs[i], p2.Coeffs[i])
+		p3.Coeffs[i].Mod(p3.Coeffs[i], r.modulus)
+	}
+}
+
+// Neg evaluates p2 = -p1 (mod Q).
+func (r Ring) Neg(p1, p2 Poly) {
+	for i := 0; i < r.n; i++ {
+		p2.Coeffs[i].Neg(p1.Coeffs[i])
+		p2.Coeffs[i].Mod(p2.Coeffs[i], r.modulus)
+	}
+}
+
+// MulScalar evaluates p2 = p1 * scalar (mod Q).
+func (r Ring) MulScalar(p1 Poly, scalar *big.Int, p2 Poly) {
+	for i := 0; i < r.n; i++ {
+		p2.Coeffs[i].Mul(p1.Coeffs[i], scalar)
+		p2.Coeffs[i].Mod(p2.Coeffs[i], r.modulus)
+	}
+}
+
+// MulCoeffs evaluates p3 = p1 * p2 (mod Q), the negacyclic convolution of p1
+// and p2 modulo X^N + 1. The algorithm is selected automatically from the
+// ring degree: the schoolbook algorithm, in O(N^2) big.Int multiplications,
+// for small degrees and the Karatsuba algorithm, in O(N^log2(3)), above.
+// Both are exact for any modulus, so this path remains functional for
+// parameter sets whose moduli admit no NTT, at reduced speed.
+func (r Ring) MulCoeffs(p1, p2, p3 Poly) {
+
+	if r.n >= karatsubaThreshold {
+		r.mulCoeffsKaratsuba(p1, p2, p3)
+		return
+	}
+
+	r.mulCoeffsSchoolbook(p1, p2, p3)
+}
+
+// mulCoeffsSchoolbook evaluates p3 = p1 * p2 (mod Q) with the schoolbook
+// algorithm, in O(N^2) big.Int multiplications.
+func (r Ring) mulCoeffsSchoolbook(p1, p2, p3 Poly) {
+
+	acc := make([]*big.Int, r.n)
+	for i := range acc {
+		acc[i] = new(big.Int)
+	}
+
+	tmp := new(big.Int)
+
+	for i := 0; i < r.n; i++ {
+		c1 := p1.Coeffs[i]
+		if c1.Sign() == 0 {
+			continue
+		}
+		for j := 0; j < r.n; j++ {
+			tmp.Mul(c1, p2.Coeffs[j])
+			if k := i + j; k < r.n {
+				acc[k].Add(acc[k], tmp)
+			} else {
+				// X^N = -1: the wrapped coefficients are subtracted.
+				acc[k-r.n].Sub(acc[k-r.n], tmp)
+			}
+		}
+	}
+
+	for i := 0; i < r.n; i++ {
+		p3.Coeffs[i].Mod(acc[i], r.modulus)
+	}
+}
+
+// Reduce evaluates p2 = p1 (mod Q), mapping all coefficients to [0, Q-1].
+func (r Ring) Reduce(p1, p2 Poly) {
+	for i := 0; i < r.n; i++ {
+		p2.Coeffs[i].Mod(p1.Coeffs[i], r.modulus)
+	}
+}
+
+// Equal returns whether p1 = p2 (mod Q).
+func (r Ring) Equal(p1, p2 Poly) bool {
+	tmp1, tmp2 := new(big.Int), new(big.Int)
+	for i := 0; i < r.n; i++ {
+		tmp1.Mod(p1.Coeffs[i], r.modulus)
+		tmp2.Mod(p2.Coeffs[i], r.modulus)
+		if tmp1.Cmp(tmp2) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// SetRNSPoly sets the coefficients of p from the RNS polynomial pol, which
+// must be in the positional (non-NTT) domain. The ring rQ must have the same
+// degree and modulus as the receiver.
+func (r Ring) SetRNSPoly(rQ *ring.Ring, pol ring.Poly, p Poly) (err error) {
+	if err = r.checkRNSRing(rQ); err != nil {
+		return fmt.Errorf("cannot SetRNSPoly: %w", err)
+	}
+	rQ.PolyToBigint(pol, 1, p.Coeffs)
+	return
+}
+
+// ToRNSPoly sets the coefficients of the RNS polynomial pol from p, in the
+// positional (non-NTT) domain. The ring rQ must have the same degree and
+// modulus as the receiver.
+func (r Ring) ToRNSPoly(rQ *ring.Ring, p Poly, pol ring.Poly) (err error) {
+	if err = r.checkRNSRing(rQ); err != nil {
+		return fmt.Errorf("cannot ToRNSPoly: %w", err)
+	}
+	rQ.SetCoefficientsBigint(p.Coeffs, pol)
+	return
+}
+
+func (r Ring) checkRNSRing(rQ *ring.Ring) (err error) {
+	if rQ.N() != r.n {
+		return fmt.Errorf("ring degree mismatch: %d != %d", rQ.N(), r.n)
+	}
+	if rQ.Modulus().Cmp(r.modulus) != 0 {
+		return fmt.Errorf("ring modulus mismatch")
+	}
+	return
+}
@@ -0,0 +1,113 @@
+package bigring
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxdefi/lattice/v5/ring"
+	"github.com/luxdefi/lattice/v5/utils/sampling"
+)
+
+func TestBigRing(t *testing.T) {
+
+	rQ, err := ring.NewRing(1<<6, ring.Qi60[:2])
+	require.NoError(t, err)
+
+	r, err := NewRingFromRNSRing(rQ)
+	require.NoError(t, err)
+
+	require.Equal(t, rQ.N(), r.N())
+	require.Equal(t, rQ.Modulus(), r.Modulus())
+
+	_, err = NewRing(3, big.NewInt(17))
+	require.Error(t, err, "N not a power of two")
+	_, err = NewRing(4, big.NewInt(0))
+	require.Error(t, err, "invalid modulus")
+
+	prng, err := sampling.NewPRNG()
+	require.NoError(t, err)
+
+	sampler := ring.NewUniformSampler(prng, rQ)
+
+	p1RNS := sampler.ReadNew()
+	p2RNS := sampler.ReadNew()
+
+	p1, p2 := r.NewPoly(), r.NewPoly()
+	require.NoError(t, r.SetRNSPoly(rQ, p1RNS, p1))
+	require.NoError(t, r.SetRNSPoly(rQ, p2RNS, p2))
+
+	t.Run("RNSRoundTrip", func(t *testing.T) {
+		polTest := rQ.NewPoly()
+		require.NoError(t, r.ToRNSPoly(rQ, p1, polTest))
+		require.True(t, rQ.Equal(p1RNS, polTest))
+	})
+
+	t.Run("AddSubNeg", func(t *testing.T) {
+		p3, p4 := r.NewPoly(), r.NewPoly()
+
+		// (p1 + p2) - p2 = p1
+		r.Add(p1, p2, p3)
+		r.Sub(p3, p2, p3)
+		require.True(t, r.Equal(p1, p3))
+
+		// p1 + (-p1) = 0
+		r.Neg(p1, p4)
+		r.Add(p1, p4, p4)
+		require.True(t, r.Equal(p4, r.NewPoly()))
+
+		// Matches the RNS ring addition.
+		r.Add(p1, p2, p3)
+		polWant := rQ.NewPoly()
+		rQ.Add(p1RNS, p2RNS, polWant)
+		polTest := rQ.NewPoly()
+		require.NoError(t, r.ToRNSPoly(rQ, p3, polTest))
+		require.True(t, rQ.Equal(polWant, polTest))
+	})
+
+	t.Run("MulCoeffs", func(t *testing.T) {
+		// The exact negacyclic convolution matches the NTT-based RNS product.
+		p3 := r.NewPoly()
+		r.MulCoeffs(p1, p2, p3)
+
+		p1NTT, p2NTT, polWant := rQ.NewPoly(), rQ.NewPoly(), rQ.NewPoly()
+		rQ.NTT(p1RNS, p1NTT)
+		rQ.NTT(p2RNS, p2NTT)
+		rQ.MForm(p1NTT, p1NTT)
+		rQ.MulCoeffsMontgomery(p1NTT, p2NTT, polWant)
+		rQ.INTT(polWant, polWant)
+
+		polTest := rQ.NewPoly()
+		require.NoError(t, r.ToRNSPoly(rQ, p3, polTest))
+		require.True(t, rQ.Equal(polWant, polTest))
+	})
+
+	t.Run("MulCoeffsKaratsuba", func(t *testing.T) {
+		// The Karatsuba and schoolbook algorithms agree.
+		p3, p4 := r.NewPoly(), r.NewPoly()
+		r.mulCoeffsKaratsuba(p1, p2, p3)
+		r.mulCoeffsSchoolbook(p1, p2, p4)
+		require.True(t, r.Equal(p3, p4))
+	})
+
+	t.Run("MulScalar", func(t *testing.T) {
+		p3 := r.NewPoly()
+		scalar := big.NewInt(0xfffff)
+		r.MulScalar(p1, scalar, p3)
+
+		polWant := rQ.NewPoly()
+		rQ.MulScalarBigint(p1RNS, scalar, polWant)
+
+		polTest := rQ.NewPoly()
+		require.NoError(t, r.ToRNSPoly(rQ, p3, polTest))
+		require.True(t, rQ.Equal(polWant, polTest))
+	})
+
+	t.Run("RingMismatch", func(t *testing.T) {
+		rQSmall, err := ring.NewRing(1<<5, ring.Qi60[:2])
+		require.NoError(t, err)
+		require.Error(t, r.SetRNSPoly(rQSmall, rQSmall.NewPoly(), p1))
+		require.Error(t, r.ToRNSPoly(rQSmall, p1, rQSmall.NewPoly()))
+	})
+}
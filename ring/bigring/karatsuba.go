@@ -0,0 +1,89 @@
+package bigring
+
+import "math/big"
+
+// karatsubaThreshold is the number of coefficients below which the
+// schoolbook algorithm outperforms the Karatsuba recursion.
+const karatsubaThreshold = 32
+
+// mulCoeffsKaratsuba evaluates p3 = p1 * p2 (mod Q), the negacyclic
+// convolution of p1 and p2 modulo X^N + 1, with the Karatsuba algorithm in
+// O(N^log2(3)) big.Int multiplications. Like the schoolbook algorithm, it
+// is exact for any modulus, in particular for moduli admitting no NTT, but
+// asymptotically much faster.
+func (r Ring) mulCoeffsKaratsuba(p1, p2, p3 Poly) {
+
+	// Full product of degree 2N-2, wrapped negacyclically afterwards.
+	prod := karatsuba(p1.Coeffs, p2.Coeffs)
+
+	for i := 0; i < r.n; i++ {
+		// X^N = -1: the wrapped coefficients are subtracted.
+		if i+r.n < len(prod) {
+			prod[i].Sub(prod[i], prod[i+r.n])
+		}
+		p3.Coeffs[i].Mod(prod[i], r.modulus)
+	}
+}
+
+// karatsuba returns the full product of two integer polynomials given by
+// their coefficients, of length 2*len(a)-1. Both inputs must have the same
+// power-of-two length. Inputs of fewer than karatsubaThreshold coefficients
+// are multiplied with the schoolbook algorithm.
+func karatsuba(a, b []*big.Int) (c []*big.Int) {
+
+	n := len(a)
+
+	c = make([]*big.Int, 2*n-1)
+	for i := range c {
+		c[i] = new(big.Int)
+	}
+
+	if n < karatsubaThreshold {
+		tmp := new(big.Int)
+		for i := range a {
+			if a[i].Sign() == 0 {
+				continue
+			}
+			for j := range b {
+				tmp.Mul(a[i], b[j])
+				c[i+j].Add(c[i+j], tmp)
+			}
+		}
+		return
+	}
+
+	m := n >> 1
+
+	a0, a1 := a[:m], a[m:]
+	b0, b1 := b[:m], b[m:]
+
+	z0 := karatsuba(a0, b0)
+	z2 := karatsuba(a1, b1)
+
+	// z1 = (a0 + a1) * (b0 + b1) - z0 - z2
+	sa := make([]*big.Int, m)
+	sb := make([]*big.Int, m)
+	for i := 0; i < m; i++ {
+		sa[i] = new(big.Int).Add(a0[i], a1[i])
+		sb[i] = new(big.Int).Add(b0[i], b1[i])
+	}
+
+	z1 := karatsuba(sa, sb)
+	for i := range z1 {
+		z1[i].Sub(z1[i], z0[i])
+		z1[i].Sub(z1[i], z2[i])
+	}
+
+	// c = z0 + z1*X^m + z2*X^2m
+	for i := range z0 {
+		c[i].Add(c[i], z0[i])
+	}
+	for i := range z1 {
+		c[i+m].Add(c[i+m], z1[i])
+	}
+	for i := range z2 {
+		c[i+2*m].Add(c[i+2*m], z2[i])
+	}
+
+	return
+}
@@ -0,0 +1,47 @@
+package ring
+
+import (
+	"fmt"
+)
+
+// InversePolyNTT computes the multiplicative inverse p2 = p1^-1 of the
+// polynomial p1 modulo (X^N + 1, q), with p1 and p2 in the NTT domain. The
+// inverse exists if and only if no NTT coefficient of p1 is zero; a clear
+// error is returned otherwise. The result can be in-place.
+func (r Ring) InversePolyNTT(p1, p2 Poly) (err error) {
+
+	for i, s := range r.SubRings[:r.level+1] {
+
+		qi := s.Modulus
+
+		p1Coeffs, p2Coeffs := p1.Coeffs[i], p2.Coeffs[i]
+
+		for j := range p1Coeffs {
+			if p1Coeffs[j] == 0 {
+				return fmt.Errorf("cannot InversePolyNTT: polynomial is not invertible modulo (X^N+1, %d)", qi)
+			}
+			p2Coeffs[j] = ModExp(p1Coeffs[j], qi-2, qi)
+		}
+	}
+
+	return
+}
+
+// InversePoly computes the multiplicative inverse p2 = p1^-1 of the
+// polynomial p1 modulo (X^N + 1, q), with p1 and p2 in the positional
+// (non-NTT) domain, through pointwise inversion in the NTT domain. The
+// inverse exists if and only if no NTT coefficient of p1 is zero; a clear
+// error is returned otherwise and p2 is left in an undefined state. The
+// result can be in-place.
+func (r Ring) InversePoly(p1, p2 Poly) (err error) {
+
+	r.NTT(p1, p2)
+
+	if err = r.InversePolyNTT(p2, p2); err != nil {
+		return err
+	}
+
+	r.INTT(p2, p2)
+
+	return
+}
@@ -151,6 +151,24 @@ func (r Ring) INTTLazy(p1, p2 Poly) {
 	}
 }
 
+// NTTThenMForm evaluates p2 = MForm(NTT(p1)), with the Montgomery conversion
+// replacing the final reduction pass of the NTT, saving a full pass over the
+// coefficients compared to NTT followed by MForm.
+func (r Ring) NTTThenMForm(p1, p2 Poly) {
+	for i, s := range r.SubRings[:r.level+1] {
+		s.NTTThenMForm(p1.Coeffs[i], p2.Coeffs[i])
+	}
+}
+
+// INTTThenIMForm evaluates p2 = IMForm(INTT(p1)), with the inverse Montgomery
+// conversion folded into the final scaling by N^-1 of the INTT, saving a full
+// pass over the coefficients compared to INTT followed by IMForm.
+func (r Ring) INTTThenIMForm(p1, p2 Poly) {
+	for i, s := range r.SubRings[:r.level+1] {
+		s.INTTThenIMForm(p1.Coeffs[i], p2.Coeffs[i])
+	}
+}
+
 // butterfly computes X, Y = U + V*Psi, U - V*Psi mod Q.
 func butterfly(U, V, Psi, twoQ, fourQ, Q, MRedConstant uint64) (uint64, uint64) {
 	if U >= fourQ {
@@ -193,6 +211,15 @@ func INTTStandardLazy(p1, p2 []uint64, N int, NInv, Q, MRedConstant uint64, root
 	mulscalarmontgomerylazyvec(p2, NInv, p2, Q, MRedConstant)
 }
 
+// INTTStandardThenIMForm evaluates p2 = IMForm(INTTStandard(p1)) in the given SubRing.
+// NInv is stored in the Montgomery form: switching it back to the standard
+// representation folds the inverse Montgomery conversion into the final
+// scaling by N^-1, in a single pass over the coefficients.
+func INTTStandardThenIMForm(p1, p2 []uint64, N int, NInv, Q, MRedConstant uint64, roots []uint64) {
+	inttCoreLazy(p1, p2, N, Q, MRedConstant, roots)
+	mulscalarmontgomeryvec(p2, IMForm(NInv, Q, MRedConstant), p2, Q, MRedConstant)
+}
+
 // nttCoreLazy computes the NTT on the input coefficients using the input parameters with output values in the range [0, 2*modulus-1].
 func nttCoreLazy(p1, p2 []uint64, N int, Q, MRedConstant uint64, roots []uint64) {
 
@@ -203,6 +230,8 @@ func nttCoreLazy(p1, p2 []uint64, N int, Q, MRedConstant uint64, roots []uint64)
 
 	if N < MinimumRingDegreeForLoopUnrolledNTT {
 		nttLazy(p1, p2, N, Q, MRedConstant, roots)
+	} else if N >= MinimumRingDegreeForBlockedNTT {
+		nttLazyBlocked(p1, p2, N, Q, MRedConstant, roots)
 	} else {
 		nttUnrolled16Lazy(p1, p2, N, Q, MRedConstant, roots)
 	}
@@ -547,6 +576,8 @@ func inttCoreLazy(p1, p2 []uint64, N int, Q, MRedConstant uint64, roots []uint64
 
 	if N < MinimumRingDegreeForLoopUnrolledNTT {
 		inttLazy(p1, p2, N, Q, MRedConstant, roots)
+	} else if N >= MinimumRingDegreeForBlockedNTT {
+		inttLazyBlocked(p1, p2, N, Q, MRedConstant, roots)
 	} else {
 		inttLazyUnrolled16(p1, p2, N, Q, MRedConstant, roots)
 	}
@@ -723,6 +754,15 @@ func INTTConjugateInvariantLazy(p1, p2 []uint64, N int, NInv, Q, MRedConstant ui
 	mulscalarmontgomerylazyvec(p2, NInv, p2, Q, MRedConstant)
 }
 
+// INTTConjugateInvariantThenIMForm evaluates p2 = IMForm(INTTConjugateInvariant(p1)) in the closed sub-ring Z[X + X^-1]/(X^2N +1) of Z[X]/(X^2N+1).
+// NInv is stored in the Montgomery form: switching it back to the standard
+// representation folds the inverse Montgomery conversion into the final
+// scaling by N^-1, in a single pass over the coefficients.
+func INTTConjugateInvariantThenIMForm(p1, p2 []uint64, N int, NInv, Q, MRedConstant uint64, roots []uint64) {
+	inttCoreConjugateInvariantLazy(p1, p2, N, Q, MRedConstant, roots)
+	mulscalarmontgomeryvec(p2, IMForm(NInv, Q, MRedConstant), p2, Q, MRedConstant)
+}
+
 // nttCoreConjugateInvariantLazy evaluates p2 = NTT(p1) in the sub-ring Z[X + X^-1]/(X^2N +1) of Z[X]/(X^2N+1) with p2 [0, 2*modulus-1].
 func nttCoreConjugateInvariantLazy(p1, p2 []uint64, N int, Q, MRedConstant uint64, roots []uint64) {
 
@@ -0,0 +1,235 @@
+package ring
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// MinimumRingDegreeForBlockedNTT is the minimum ring degree from which
+	// the NTT is evaluated with cache-blocked passes. Below this degree the
+	// working set of a transform fits in the L2 cache and the straightforward
+	// butterfly ordering is already optimal.
+	MinimumRingDegreeForBlockedNTT = 1 << 16
+
+	// nttBlockSize is the number of coefficients processed per block by the
+	// blocked NTT (64KB of 64-bit words), chosen so that one block and its
+	// roots fit comfortably in the L2 cache.
+	nttBlockSize = 1 << 13
+)
+
+// nttConcurrency is the number of goroutines cooperating on the block phase
+// of a single blocked transform (see SetBlockedNTTConcurrency).
+var nttConcurrency = int64(1)
+
+// SetBlockedNTTConcurrency sets the number of goroutines cooperating on a
+// single blocked transform (i.e. for ring degrees of at least
+// MinimumRingDegreeForBlockedNTT). The default value of 1 keeps the
+// transform single-threaded; values below 1 are clamped to 1. The setting
+// is process-wide and can be changed at any time, including concurrently
+// with running transforms.
+func SetBlockedNTTConcurrency(n int) {
+	if n < 1 {
+		n = 1
+	}
+	atomic.StoreInt64(&nttConcurrency, int64(n))
+}
+
+// BlockedNTTConcurrency returns the number of goroutines cooperating on a
+// single blocked transform (see SetBlockedNTTConcurrency).
+func BlockedNTTConcurrency() int {
+	return int(atomic.LoadInt64(&nttConcurrency))
+}
+
+// nttLazyBlocked is a cache-blocked re-ordering of nttLazy for large ring
+// degrees: the first stages are evaluated globally until the butterfly
+// groups shrink to nttBlockSize coefficients, after which each block is an
+// independent sub-transform that is completed depth-first while it is hot
+// in cache. The butterflies evaluated are exactly those of nttLazy, so the
+// output is bit-for-bit identical. The independent blocks are optionally
+// distributed over BlockedNTTConcurrency goroutines.
+func nttLazyBlocked(p1, p2 []uint64, N int, Q, MRedConstant uint64, roots []uint64) {
+
+	// Sanity check
+	if N <= nttBlockSize || N&(nttBlockSize-1) != 0 {
+		panic(fmt.Sprintf("unsafe call of nttLazyBlocked: N=%d must be a power of two larger than %d", N, nttBlockSize))
+	}
+
+	var j1, j2, t int
+	var F uint64
+
+	fourQ := 4 * Q
+	twoQ := 2 * Q
+
+	m0 := N / nttBlockSize
+
+	// Copy the result of the first round of butterflies on p2 with approximate reduction
+	t = N >> 1
+	F = roots[1]
+
+	for jx, jy := 0, t; jx < t; jx, jy = jx+1, jy+1 {
+		p2[jx], p2[jy] = butterfly(p1[jx], p1[jy], F, twoQ, fourQ, Q, MRedConstant)
+	}
+
+	// Global passes, until the butterfly groups span a single block
+	for m := 2; m < m0; m <<= 1 {
+
+		t >>= 1
+
+		for i := 0; i < m; i++ {
+
+			j1 = (i * t) << 1
+
+			j2 = j1 + t
+
+			F = roots[m+i]
+
+			for jx, jy := j1, j1+t; jx < j2; jx, jy = jx+1, jy+1 {
+				p2[jx], p2[jy] = butterfly(p2[jx], p2[jy], F, twoQ, fourQ, Q, MRedConstant)
+			}
+		}
+	}
+
+	// Block i is the sub-transform of the contiguous coefficients
+	// [i*nttBlockSize, (i+1)*nttBlockSize); its local stage of m groups uses
+	// the roots roots[m*(m0+i)+k] for the groups k in [0, m).
+	block := func(i int) {
+
+		q := p2[i*nttBlockSize : (i+1)*nttBlockSize]
+
+		tLoc := nttBlockSize
+
+		for m := 1; m < nttBlockSize; m <<= 1 {
+
+			tLoc >>= 1
+
+			for k := 0; k < m; k++ {
+
+				j1 := (k * tLoc) << 1
+
+				j2 := j1 + tLoc
+
+				F := roots[m*(m0+i)+k]
+
+				for jx, jy := j1, j1+tLoc; jx < j2; jx, jy = jx+1, jy+1 {
+					q[jx], q[jy] = butterfly(q[jx], q[jy], F, twoQ, fourQ, Q, MRedConstant)
+				}
+			}
+		}
+	}
+
+	forEachNTTBlock(m0, block)
+}
+
+// inttLazyBlocked is the cache-blocked mirror of inttLazy for large ring
+// degrees: each block of nttBlockSize coefficients is first completed
+// depth-first as an independent sub-transform while it is hot in cache,
+// after which the remaining large-stride stages are evaluated globally. The
+// butterflies evaluated are exactly those of inttLazy, so the output is
+// bit-for-bit identical. The independent blocks are optionally distributed
+// over BlockedNTTConcurrency goroutines.
+func inttLazyBlocked(p1, p2 []uint64, N int, Q, MRedConstant uint64, roots []uint64) {
+
+	// Sanity check
+	if N <= nttBlockSize || N&(nttBlockSize-1) != 0 {
+		panic(fmt.Sprintf("unsafe call of inttLazyBlocked: N=%d must be a power of two larger than %d", N, nttBlockSize))
+	}
+
+	var h, t int
+	var F uint64
+
+	twoQ := Q << 1
+	fourQ := Q << 2
+
+	m0 := N / nttBlockSize
+
+	// Block i is the sub-transform of the contiguous coefficients
+	// [i*nttBlockSize, (i+1)*nttBlockSize); its local stage of h groups uses
+	// the roots roots[h*(m0+i)+k] for the groups k in [0, h).
+	block := func(i int) {
+
+		off := i * nttBlockSize
+
+		// Copy the result of the first round of butterflies on p2 with approximate reduction
+		hLoc := nttBlockSize >> 1
+
+		for k, j1 := 0, off; k < hLoc; k, j1 = k+1, j1+2 {
+			F := roots[hLoc*(m0+i)+k]
+			p2[j1], p2[j1+1] = invbutterfly(p1[j1], p1[j1+1], F, twoQ, fourQ, Q, MRedConstant)
+		}
+
+		tLoc := 2
+
+		for m := nttBlockSize >> 1; m > 1; m >>= 1 {
+
+			hLoc = m >> 1
+
+			for k, j1 := 0, off; k < hLoc; k, j1 = k+1, j1+2*tLoc {
+
+				F := roots[hLoc*(m0+i)+k]
+
+				for jx, jy := j1, j1+tLoc; jx < j1+tLoc; jx, jy = jx+1, jy+1 {
+					p2[jx], p2[jy] = invbutterfly(p2[jx], p2[jy], F, twoQ, fourQ, Q, MRedConstant)
+				}
+			}
+
+			tLoc <<= 1
+		}
+	}
+
+	forEachNTTBlock(m0, block)
+
+	// Global passes, once the butterfly groups span more than a single block
+	t = nttBlockSize
+
+	for m := m0; m > 1; m >>= 1 {
+
+		h = m >> 1
+
+		for i, j1, j2 := 0, 0, t; i < h; i, j1, j2 = i+1, j1+2*t, j2+2*t {
+
+			F = roots[h+i]
+
+			for jx, jy := j1, j1+t; jx < j2; jx, jy = jx+1, jy+1 {
+				p2[jx], p2[jy] = invbutterfly(p2[jx], p2[jy], F, twoQ, fourQ, Q, MRedConstant)
+			}
+		}
+
+		t <<= 1
+	}
+}
+
+// forEachNTTBlock evaluates block(i) for the m0 independent blocks of a
+// blocked transform, distributing them over BlockedNTTConcurrency goroutines
+// if the latter is larger than one. Blocks operate on disjoint coefficient
+// ranges, so no synchronization beyond the final join is needed.
+func forEachNTTBlock(m0 int, block func(i int)) {
+
+	c := BlockedNTTConcurrency()
+
+	if c <= 1 {
+		for i := 0; i < m0; i++ {
+			block(i)
+		}
+		return
+	}
+
+	if c > m0 {
+		c = m0
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(c)
+
+	for w := 0; w < c; w++ {
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < m0; i += c {
+				block(i)
+			}
+		}(w)
+	}
+
+	wg.Wait()
+}
@@ -5,6 +5,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxdefi/lattice/v5/utils/sampling"
 )
 
 var testVector = []struct {
@@ -117,3 +120,51 @@ func TestNTT(t *testing.T) {
 		})
 	}
 }
+
+func TestFusedNTT(t *testing.T) {
+
+	standard, err := NewRing(16, Qi60[:2])
+	require.NoError(t, err)
+
+	conjugateInvariant, err := NewRingConjugateInvariant(16, Qi60[:2])
+	require.NoError(t, err)
+
+	prng, err := sampling.NewPRNG()
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name string
+		r    *Ring
+	}{
+		{"Standard", standard},
+		{"ConjugateInvariant", conjugateInvariant},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+
+			r := tc.r
+
+			p := NewUniformSampler(prng, r).ReadNew()
+
+			// The fused forward pass matches NTT followed by MForm.
+			want := r.NewPoly()
+			r.NTT(p, want)
+			r.MForm(want, want)
+
+			got := r.NewPoly()
+			r.NTTThenMForm(p, got)
+			require.True(t, r.Equal(want, got))
+
+			// The fused backward pass matches INTT followed by IMForm.
+			wantInv := r.NewPoly()
+			r.INTT(got, wantInv)
+			r.IMForm(wantInv, wantInv)
+
+			gotInv := r.NewPoly()
+			r.INTTThenIMForm(got, gotInv)
+			require.True(t, r.Equal(wantInv, gotInv))
+
+			// The fused round-trip is the identity.
+			require.True(t, r.Equal(p, gotInv))
+		})
+	}
+}
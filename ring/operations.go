@@ -84,6 +84,13 @@ func (r Ring) MulCoeffsBarrettThenAddLazy(p1, p2, p3 Poly) {
 	}
 }
 
+// MulCoeffsBarrettThenSub evaluates p3 = p3 - p1 * p2 coefficient-wise in the ring, with Barrett reduction.
+func (r Ring) MulCoeffsBarrettThenSub(p1, p2, p3 Poly) {
+	for i, s := range r.SubRings[:r.level+1] {
+		s.MulCoeffsBarrettThenSub(p1.Coeffs[i], p2.Coeffs[i], p3.Coeffs[i])
+	}
+}
+
 // MulCoeffsMontgomery evaluates p3 = p1 * p2 coefficient-wise in the ring, with Montgomery reduction.
 func (r Ring) MulCoeffsMontgomery(p1, p2, p3 Poly) {
 	for i, s := range r.SubRings[:r.level+1] {
@@ -1,7 +1,6 @@
 package ring
 
 import (
-	"bufio"
 	"io"
 
 	"github.com/luxdefi/lattice/v5/utils"
@@ -94,11 +93,44 @@ func (pol Poly) Equal(other *Poly) bool {
 	return pol.Coeffs.Equal(other.Coeffs)
 }
 
+// EqualConstantTime returns true if the receiver Poly is equal to the provided
+// other Poly, in time independent of the position of a potential coefficient
+// mismatch. Unlike Equal, it is suitable for comparing secret polynomials.
+func (pol Poly) EqualConstantTime(other *Poly) (v bool) {
+	if len(pol.Coeffs) != len(other.Coeffs) {
+		return false
+	}
+	v = true
+	for i := range pol.Coeffs {
+		v = utils.EqualSliceConstantTime(pol.Coeffs[i], other.Coeffs[i]) && v
+	}
+	return
+}
+
+// FormatVersion returns the format version in which the current encoding of
+// the object was introduced (see lattice/utils/compat). The encoding of Poly
+// has been stable since format version 1.
+func (pol Poly) FormatVersion() uint8 { return 1 }
+
 // BinarySize returns the serialized size of the object in bytes.
 func (pol Poly) BinarySize() (size int) {
 	return pol.Coeffs.BinarySize()
 }
 
+// MemoryFootprint returns the deep in-RAM size of the polynomial in bytes:
+// the allocated coefficient storage plus the slice headers, as opposed to the
+// serialized size returned by BinarySize.
+func (pol Poly) MemoryFootprint() (size int) {
+	size = sliceHeaderBytes
+	for _, coeffs := range pol.Coeffs {
+		size += sliceHeaderBytes + 8*cap(coeffs)
+	}
+	return
+}
+
+// sliceHeaderBytes is the in-RAM size of a slice header on a 64-bit platform.
+const sliceHeaderBytes = 24
+
 // WriteTo writes the object on an io.Writer. It implements the io.WriterTo
 // interface, and will write exactly object.BinarySize() bytes on w.
 //
@@ -118,7 +150,7 @@ func (pol Poly) WriteTo(w io.Writer) (n int64, err error) {
 		}
 		return n, w.Flush()
 	default:
-		return pol.WriteTo(bufio.NewWriter(w))
+		return buffer.WriteWithPool(w, pol.WriteTo)
 	}
 }
 
@@ -141,7 +173,7 @@ func (pol *Poly) ReadFrom(r io.Reader) (n int64, err error) {
 		}
 		return n, nil
 	default:
-		return pol.ReadFrom(bufio.NewReader(r))
+		return buffer.ReadWithPool(r, pol.ReadFrom)
 	}
 }
 
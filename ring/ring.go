@@ -597,6 +597,88 @@ func newRingFromparametersLiteral(p ringParametersLiteral) (r *Ring, err error)
 	return
 }
 
+// centeredCoeffs reconstructs the coefficients of the polynomial with respect
+// to the full modulus of the ring and centers them in (-Q/2, Q/2].
+func (r Ring) centeredCoeffs(poly Poly) (coeffs []*big.Int) {
+	coeffs = make([]*big.Int, r.N())
+	for i := range coeffs {
+		coeffs[i] = new(big.Int)
+	}
+	r.PolyToBigintCentered(poly, 1, coeffs)
+	return
+}
+
+// NormInf returns the infinity norm of the polynomial, i.e. the maximum
+// absolute value of its coefficients centered with respect to the full
+// modulus of the ring.
+func (r Ring) NormInf(poly Poly) (norm *big.Int) {
+	norm = new(big.Int)
+	tmp := new(big.Int)
+	for _, coeff := range r.centeredCoeffs(poly) {
+		if tmp.Abs(coeff); tmp.Cmp(norm) == 1 {
+			norm.Set(tmp)
+		}
+	}
+	return
+}
+
+// NormL2 returns the L2 norm of the polynomial, i.e. the square root of the
+// sum of the squares of its coefficients centered with respect to the full
+// modulus of the ring.
+func (r Ring) NormL2(poly Poly) (norm *big.Float) {
+	prec := uint(128)
+	norm = bignum.NewFloat(0, prec)
+	tmp := bignum.NewFloat(0, prec)
+	acc := new(big.Int)
+	sum := new(big.Int)
+	for _, coeff := range r.centeredCoeffs(poly) {
+		sum.Add(sum, acc.Mul(coeff, coeff))
+	}
+	return norm.Sqrt(tmp.SetInt(sum))
+}
+
+// Mean returns the mean of the coefficients of the polynomial centered with
+// respect to the full modulus of the ring.
+func (r Ring) Mean(poly Poly) (mean *big.Float) {
+	prec := uint(128)
+	sum := new(big.Int)
+	for _, coeff := range r.centeredCoeffs(poly) {
+		sum.Add(sum, coeff)
+	}
+	mean = bignum.NewFloat(0, prec)
+	mean.SetInt(sum)
+	return mean.Quo(mean, bignum.NewFloat(float64(r.N()), prec))
+}
+
+// Variance returns the sample variance of the coefficients of the polynomial
+// centered with respect to the full modulus of the ring.
+func (r Ring) Variance(poly Poly) (variance *big.Float) {
+
+	prec := uint(128)
+
+	coeffs := r.centeredCoeffs(poly)
+
+	mean := bignum.NewFloat(0, prec)
+	tmp := bignum.NewFloat(0, prec)
+
+	for _, coeff := range coeffs {
+		mean.Add(mean, tmp.SetInt(coeff))
+	}
+
+	mean.Quo(mean, bignum.NewFloat(float64(r.N()), prec))
+
+	variance = bignum.NewFloat(0, prec)
+
+	for _, coeff := range coeffs {
+		tmp.SetInt(coeff)
+		tmp.Sub(tmp, mean)
+		tmp.Mul(tmp, tmp)
+		variance.Add(variance, tmp)
+	}
+
+	return variance.Quo(variance, bignum.NewFloat(float64(r.N()-1), prec))
+}
+
 // Log2OfStandardDeviation returns base 2 logarithm of the standard deviation of the coefficients
 // of the polynomial.
 func (r Ring) Log2OfStandardDeviation(poly Poly) (std float64) {
@@ -914,3 +914,357 @@ func testShift(t *testing.T) {
 	require.Equal(t, p2.Coeffs[0], []uint64{3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 0, 1, 2})
 
 }
+
+func TestBasisExtenderConstantsCache(t *testing.T) {
+
+	ringQ, err := NewRing(1<<4, Qi60[:3])
+	require.NoError(t, err)
+	ringP, err := NewRing(1<<4, Pi60[:2])
+	require.NoError(t, err)
+
+	be1 := NewBasisExtender(ringQ, ringP)
+	be2 := NewBasisExtender(ringQ, ringP)
+
+	// Instances created for the same pair of moduli chains share the cached
+	// constants, but each has its own buffers.
+	require.Same(t, &be1.constantsQtoP[0].qoverqiinvqi[0], &be2.constantsQtoP[0].qoverqiinvqi[0])
+	require.Same(t, &be1.constantsPtoQ[0].qoverqiinvqi[0], &be2.constantsPtoQ[0].qoverqiinvqi[0])
+	require.Same(t, &be1.modDownConstantsPtoQ[0][0], &be2.modDownConstantsPtoQ[0][0])
+	require.Same(t, &be1.modDownConstantsQtoP[0][0], &be2.modDownConstantsQtoP[0][0])
+	require.NotSame(t, &be1.buffQ.Coeffs[0][0], &be2.buffQ.Coeffs[0][0])
+
+	// A different pair of chains gets its own constants.
+	ringP2, err := NewRing(1<<4, Pi60[:1])
+	require.NoError(t, err)
+
+	be3 := NewBasisExtender(ringQ, ringP2)
+	require.NotSame(t, &be1.constantsQtoP[0].qoverqiinvqi[0], &be3.constantsQtoP[0].qoverqiinvqi[0])
+}
+
+func TestPolyStatistics(t *testing.T) {
+
+	r, err := NewRing(1<<4, Qi60[:2])
+	require.NoError(t, err)
+
+	// Coefficients [3, -4, 0, 1, 0, ..., 0] stored modulo Q.
+	coeffs := make([]*big.Int, r.N())
+	for i := range coeffs {
+		coeffs[i] = big.NewInt(0)
+	}
+	coeffs[0] = big.NewInt(3)
+	coeffs[1] = new(big.Int).Sub(r.Modulus(), big.NewInt(4))
+	coeffs[3] = big.NewInt(1)
+
+	p := r.NewPoly()
+	r.SetCoefficientsBigint(coeffs, p)
+
+	require.Equal(t, big.NewInt(4), r.NormInf(p))
+
+	normL2, _ := r.NormL2(p).Float64()
+	require.InDelta(t, math.Sqrt(9+16+0+1), normL2, 1e-12)
+
+	mean, _ := r.Mean(p).Float64()
+	require.InDelta(t, (3.0-4.0+1.0)/16.0, mean, 1e-12)
+
+	variance, _ := r.Variance(p).Float64()
+	require.InDelta(t, 1.7333333333333334, variance, 1e-9)
+}
+
+func TestTraceAndSubring(t *testing.T) {
+
+	r, err := NewRing(1<<5, Qi60[:2])
+	require.NoError(t, err)
+
+	rSub, err := NewRing(1<<4, Qi60[:2])
+	require.NoError(t, err)
+
+	prng, err := sampling.NewPRNG()
+	require.NoError(t, err)
+
+	p1 := NewUniformSampler(prng, r).ReadNew()
+
+	// The normalized trace to the sub-ring of degree 16 keeps the even
+	// coefficients and zeroes the odd ones.
+	p2 := r.NewPoly()
+	require.NoError(t, r.Trace(p1, 4, p2))
+	for i := range p2.Coeffs {
+		for j := range p2.Coeffs[i] {
+			if j%2 == 0 {
+				require.Equal(t, p1.Coeffs[i][j], p2.Coeffs[i][j])
+			} else {
+				require.Zero(t, p2.Coeffs[i][j])
+			}
+		}
+	}
+
+	// Projection extracts the coefficients kept by the trace and embedding
+	// maps them back: embed(project(trace(p))) = trace(p).
+	pSub := rSub.NewPoly()
+	require.NoError(t, r.ProjectToSubring(rSub, p2, pSub))
+	for i := range pSub.Coeffs {
+		for j := range pSub.Coeffs[i] {
+			require.Equal(t, p2.Coeffs[i][2*j], pSub.Coeffs[i][j])
+		}
+	}
+
+	p3 := r.NewPoly()
+	require.NoError(t, r.EmbedFromSubring(rSub, pSub, p3))
+	require.True(t, r.Equal(p2, p3))
+
+	// The embedding is a ring homomorphism: it commutes with the product.
+	qSub := NewUniformSampler(prng, rSub).ReadNew()
+	prodSub := rSub.NewPoly()
+	rSub.NTT(pSub, pSub)
+	rSub.NTT(qSub, qSub)
+	rSub.MForm(pSub, pSub)
+	rSub.MulCoeffsMontgomery(pSub, qSub, prodSub)
+	rSub.INTT(prodSub, prodSub)
+	rSub.INTT(pSub, pSub)
+	rSub.IMForm(pSub, pSub)
+	rSub.INTT(qSub, qSub)
+
+	q3, prod3, prodWant := r.NewPoly(), r.NewPoly(), r.NewPoly()
+	require.NoError(t, r.EmbedFromSubring(rSub, qSub, q3))
+	require.NoError(t, r.EmbedFromSubring(rSub, prodSub, prodWant))
+
+	r.NTT(p3, p3)
+	r.NTT(q3, q3)
+	r.MForm(p3, p3)
+	r.MulCoeffsMontgomery(p3, q3, prod3)
+	r.INTT(prod3, prod3)
+	require.True(t, r.Equal(prodWant, prod3))
+
+	// Invalid inputs.
+	require.Error(t, r.Trace(p1, -1, p2))
+	require.Error(t, r.Trace(p1, r.LogN()+1, p2))
+	require.Error(t, rSub.ProjectToSubring(r, p1, pSub))
+
+	rOther, err := NewRing(1<<4, Qi60[2:4])
+	require.NoError(t, err)
+	require.Error(t, r.ProjectToSubring(rOther, p1, rOther.NewPoly()))
+}
+
+func TestAutomorphismNTTIndexShared(t *testing.T) {
+
+	N := 1 << 4
+	NthRoot := uint64(2 * N)
+
+	require.NoError(t, WarmupAutomorphismNTTIndex(N, NthRoot, []uint64{3, 5}))
+
+	// Shared tables are generated once and shared across callers.
+	idx1, err := AutomorphismNTTIndexShared(N, NthRoot, 5)
+	require.NoError(t, err)
+	idx2, err := AutomorphismNTTIndexShared(N, NthRoot, 5)
+	require.NoError(t, err)
+	require.Same(t, &idx1[0], &idx2[0])
+
+	// The shared table matches the raw generator output.
+	want, err := AutomorphismNTTIndex(N, NthRoot, 5)
+	require.NoError(t, err)
+	require.Equal(t, want, idx1)
+
+	// The raw generator returns a private copy.
+	require.NotSame(t, &want[0], &idx1[0])
+
+	_, err = AutomorphismNTTIndexShared(N-1, NthRoot, 5)
+	require.Error(t, err)
+	require.Error(t, WarmupAutomorphismNTTIndex(N, NthRoot+1, []uint64{5}))
+}
+
+func TestInversePoly(t *testing.T) {
+
+	r, err := NewRing(1<<4, Qi60[:2])
+	require.NoError(t, err)
+
+	prng, err := sampling.NewPRNG()
+	require.NoError(t, err)
+
+	p1 := NewUniformSampler(prng, r).ReadNew()
+
+	p2 := r.NewPoly()
+	require.NoError(t, r.InversePoly(p1, p2))
+
+	// p1 * p1^-1 = 1
+	prod := r.NewPoly()
+	r.NTT(p1, p1)
+	r.NTT(p2, p2)
+	r.MForm(p1, p1)
+	r.MulCoeffsMontgomery(p1, p2, prod)
+	r.INTT(prod, prod)
+
+	one := r.NewPoly()
+	for i := range one.Coeffs {
+		one.Coeffs[i][0] = 1
+	}
+	require.True(t, r.Equal(prod, one))
+
+	// The zero polynomial is not invertible.
+	require.ErrorContains(t, r.InversePoly(r.NewPoly(), p2), "not invertible")
+
+	// A polynomial with a zero NTT coefficient is not invertible either.
+	r.INTT(p1, p1)
+	r.IMForm(p1, p1)
+	pNTT := r.NewPoly()
+	r.NTT(p1, pNTT)
+	pNTT.Coeffs[0][0] = 0
+	require.ErrorContains(t, r.InversePolyNTT(pNTT, p2), "not invertible")
+}
+
+func TestSparsePoly(t *testing.T) {
+
+	r, err := NewRing(1<<4, Qi60[:2])
+	require.NoError(t, err)
+
+	prng, err := sampling.NewPRNG()
+	require.NoError(t, err)
+
+	// A low-weight polynomial: 1 - 3X^3 + 7X^15.
+	mask := r.NewPoly()
+	for i, s := range r.SubRings[:r.level+1] {
+		mask.Coeffs[i][0] = 1
+		mask.Coeffs[i][3] = s.Modulus - 3
+		mask.Coeffs[i][15] = 7
+	}
+
+	sp := r.SparsePolyFromPoly(mask)
+	require.Equal(t, 3, sp.Weight())
+	require.Equal(t, []int{0, 3, 15}, sp.Degrees)
+
+	// Round-trip through the dense representation.
+	maskTest := r.NewPoly()
+	r.SparsePolyToPoly(sp, maskTest)
+	require.True(t, r.Equal(mask, maskTest))
+
+	// The sparse product matches the NTT-based product.
+	p1 := NewUniformSampler(prng, r).ReadNew()
+
+	p2 := r.NewPoly()
+	require.NoError(t, r.MulSparse(p1, sp, p2))
+
+	p1NTT, maskNTT, polWant := r.NewPoly(), r.NewPoly(), r.NewPoly()
+	r.NTT(p1, p1NTT)
+	r.NTT(mask, maskNTT)
+	r.MForm(p1NTT, p1NTT)
+	r.MulCoeffsMontgomery(p1NTT, maskNTT, polWant)
+	r.INTT(polWant, polWant)
+
+	require.True(t, r.Equal(polWant, p2))
+
+	// Degrees outside [0, N-1] are rejected.
+	require.Error(t, r.MulSparse(p1, SparsePoly{Degrees: []int{r.N()}, Coeffs: [][]uint64{{1}, {1}}}, p2))
+}
+
+func TestBlockedNTT(t *testing.T) {
+
+	r, err := NewRing(MinimumRingDegreeForBlockedNTT, Qi60[:1])
+	require.NoError(t, err)
+
+	prng, err := sampling.NewPRNG()
+	require.NoError(t, err)
+
+	p := NewUniformSampler(prng, r).ReadNew()
+
+	s := r.SubRings[0]
+
+	N := r.N()
+
+	// The blocked forward transform evaluates the exact same butterflies as
+	// nttLazy, so the outputs must match bit-for-bit.
+	ref := make([]uint64, N)
+	nttLazy(p.Coeffs[0], ref, N, s.Modulus, s.MRedConstant, s.RootsForward)
+
+	got := make([]uint64, N)
+	nttLazyBlocked(p.Coeffs[0], got, N, s.Modulus, s.MRedConstant, s.RootsForward)
+	require.Equal(t, ref, got)
+
+	// The unrolled transform uses a different lazy reduction schedule, so it
+	// is only expected to agree modulo Q.
+	refU := make([]uint64, N)
+	nttUnrolled16Lazy(p.Coeffs[0], refU, N, s.Modulus, s.MRedConstant, s.RootsForward)
+	for j := range got {
+		require.Equal(t, refU[j]%s.Modulus, got[j]%s.Modulus)
+	}
+
+	// Same for the inverse transform.
+	refInv := make([]uint64, N)
+	inttLazy(ref, refInv, N, s.Modulus, s.MRedConstant, s.RootsBackward)
+
+	gotInv := make([]uint64, N)
+	inttLazyBlocked(got, gotInv, N, s.Modulus, s.MRedConstant, s.RootsBackward)
+	require.Equal(t, refInv, gotInv)
+
+	// Multi-threaded cooperation within one transform does not change the output.
+	SetBlockedNTTConcurrency(4)
+	defer SetBlockedNTTConcurrency(1)
+	require.Equal(t, 4, BlockedNTTConcurrency())
+
+	nttLazyBlocked(p.Coeffs[0], got, N, s.Modulus, s.MRedConstant, s.RootsForward)
+	require.Equal(t, ref, got)
+
+	inttLazyBlocked(got, gotInv, N, s.Modulus, s.MRedConstant, s.RootsBackward)
+	require.Equal(t, refInv, gotInv)
+
+	// NTT/INTT round-trip through the Ring API, which dispatches to the
+	// blocked transforms at this degree.
+	p2 := r.NewPoly()
+	r.NTT(p, p2)
+	r.INTT(p2, p2)
+	require.True(t, p.Equal(&p2))
+}
+
+func TestDivRoundByModuli(t *testing.T) {
+
+	r, err := NewRing(1<<4, Qi60[:3])
+	require.NoError(t, err)
+
+	// Target chain: the first and last moduli, dropping the middle one.
+	rOut, err := NewRing(1<<4, []uint64{Qi60[0], Qi60[2]})
+	require.NoError(t, err)
+
+	dropped := []int{1}
+
+	QD := new(big.Int).SetUint64(Qi60[1])
+
+	// x = y*QD + e with |e| < QD/2 must divide back to exactly y.
+	N := r.N()
+	coeffs := make([]*big.Int, N)
+	want := make([]*big.Int, N)
+	for j := range coeffs {
+		y := int64(j) - int64(N)/2
+		e := int64(3 - 2*(j%4))
+		want[j] = big.NewInt(y)
+		coeffs[j] = new(big.Int).Mul(want[j], QD)
+		coeffs[j].Add(coeffs[j], big.NewInt(e))
+	}
+
+	p0 := r.NewPoly()
+	r.SetCoefficientsBigint(coeffs, p0)
+
+	p1 := rOut.NewPoly()
+	require.NoError(t, r.DivRoundByModuli(rOut, dropped, p0, p1))
+
+	got := make([]*big.Int, N)
+	for j := range got {
+		got[j] = new(big.Int)
+	}
+	rOut.PolyToBigintCentered(p1, 1, got)
+
+	for j := range got {
+		require.Equal(t, 0, want[j].Cmp(got[j]))
+	}
+
+	// The NTT variant agrees.
+	pNTT := r.NewPoly()
+	r.NTT(p0, pNTT)
+
+	p2 := rOut.NewPoly()
+	require.NoError(t, r.DivRoundByModuliNTT(rOut, dropped, pNTT, r.NewPoly(), p2))
+	rOut.INTT(p2, p2)
+	require.True(t, p1.Equal(&p2))
+
+	// Mismatched target chains are rejected.
+	require.Error(t, r.DivRoundByModuli(rOut, []int{0}, p0, p1))
+	require.Error(t, r.DivRoundByModuli(rOut, nil, p0, p1))
+	require.Error(t, r.DivRoundByModuli(rOut, []int{1, 1}, p0, p1))
+	require.Error(t, r.DivRoundByModuli(rOut, []int{3}, p0, p1))
+}
@@ -1,7 +1,6 @@
 package ringqp
 
 import (
-	"bufio"
 	"io"
 
 	"github.com/luxdefi/lattice/v5/ring"
@@ -52,6 +51,15 @@ func (p Poly) Equal(other *Poly) (v bool) {
 	return p.Q.Equal(&other.Q) && p.P.Equal(&other.P)
 }
 
+// EqualConstantTime returns true if the receiver Poly is equal to the provided
+// other Poly, in time independent of the position of a potential coefficient
+// mismatch. Unlike Equal, it is suitable for comparing secret polynomials.
+func (p Poly) EqualConstantTime(other *Poly) (v bool) {
+	eQ := p.Q.EqualConstantTime(&other.Q)
+	eP := p.P.EqualConstantTime(&other.P)
+	return eQ && eP
+}
+
 // Copy copies the coefficients of other on the target polynomial.
 // This method simply calls the Copy method for each of its sub-polynomials.
 func (p *Poly) Copy(other Poly) {
@@ -85,12 +93,23 @@ func (p *Poly) Resize(levelQ, levelP int) {
 	p.P.Resize(levelP)
 }
 
+// FormatVersion returns the format version in which the current encoding of
+// the object was introduced (see lattice/utils/compat). The encoding of Poly
+// has been stable since format version 1.
+func (p Poly) FormatVersion() uint8 { return 1 }
+
 // BinarySize returns the serialized size of the object in bytes.
 // It assumes that each coefficient takes 8 bytes.
 func (p Poly) BinarySize() (dataLen int) {
 	return p.Q.BinarySize() + p.P.BinarySize()
 }
 
+// MemoryFootprint returns the deep in-RAM size of the polynomial in bytes
+// (see ring.Poly.MemoryFootprint).
+func (p Poly) MemoryFootprint() (size int) {
+	return p.Q.MemoryFootprint() + p.P.MemoryFootprint()
+}
+
 // WriteTo writes the object on an io.Writer. It implements the io.WriterTo
 // interface, and will write exactly object.BinarySize() bytes on w.
 //
@@ -124,7 +143,7 @@ func (p Poly) WriteTo(w io.Writer) (n int64, err error) {
 		return n, w.Flush()
 
 	default:
-		return p.WriteTo(bufio.NewWriter(w))
+		return buffer.WriteWithPool(w, p.WriteTo)
 	}
 }
 
@@ -160,7 +179,7 @@ func (p *Poly) ReadFrom(r io.Reader) (n int64, err error) {
 		return
 
 	default:
-		return p.ReadFrom(bufio.NewReader(r))
+		return buffer.ReadWithPool(r, p.ReadFrom)
 	}
 }
 
@@ -1,5 +1,10 @@
 package ring
 
+import (
+	"fmt"
+	"math/big"
+)
+
 // DivFloorByLastModulusNTT divides (floored) the polynomial by its last modulus.
 // The input must be in the NTT domain.
 // Output poly level must be equal or one less than input level.
@@ -190,3 +195,113 @@ func (r Ring) DivRoundByLastModulusMany(nbRescales int, p0, buff, p1 Poly) {
 		}
 	}
 }
+
+// DivRoundByModuli switches p0 from the moduli chain of the receiver at the
+// current level to the chain of rOut, obtained by removing the moduli at the
+// indices listed in dropped, writing on p1 the rounded division of p0 by the
+// product of the dropped moduli. It generalizes DivRoundByLastModulusMany to
+// an arbitrary subset of the chain and is intended for one-shot alignment of
+// polynomials produced under a foreign modulus chain; rescaling along the
+// chain should keep using the DivRoundByLastModulus variants, which are much
+// cheaper. The polynomials must be in the positional (non-NTT) domain.
+func (r Ring) DivRoundByModuli(rOut *Ring, dropped []int, p0, p1 Poly) (err error) {
+
+	if err = r.checkDroppedModuli(rOut, dropped); err != nil {
+		return fmt.Errorf("cannot DivRoundByModuli: %w", err)
+	}
+
+	if len(dropped) == 0 {
+		if !p0.Equal(&p1) {
+			p1.Copy(p0)
+		}
+		return
+	}
+
+	QD := new(big.Int).SetUint64(1)
+	for _, i := range dropped {
+		QD.Mul(QD, new(big.Int).SetUint64(r.SubRings[i].Modulus))
+	}
+
+	half := new(big.Int).Rsh(QD, 1)
+
+	coeffs := make([]*big.Int, r.N())
+	for j := range coeffs {
+		coeffs[j] = new(big.Int)
+	}
+
+	r.PolyToBigintCentered(p0, 1, coeffs)
+
+	// Rounded division of the centered coefficients by the product of the
+	// dropped moduli.
+	for _, c := range coeffs {
+		if c.Sign() >= 0 {
+			c.Add(c, half)
+		} else {
+			c.Sub(c, half)
+		}
+		c.Quo(c, QD)
+	}
+
+	rOut.SetCoefficientsBigint(coeffs, p1)
+
+	return
+}
+
+// DivRoundByModuliNTT switches p0 from the moduli chain of the receiver at
+// the current level to the chain of rOut, obtained by removing the moduli at
+// the indices listed in dropped, writing on p1 the rounded division of p0 by
+// the product of the dropped moduli (see DivRoundByModuli). The input must be
+// in the NTT domain and the output is returned in the NTT domain of rOut.
+// The buffer buff must have the level of the receiver.
+func (r Ring) DivRoundByModuliNTT(rOut *Ring, dropped []int, p0, buff, p1 Poly) (err error) {
+
+	if err = r.checkDroppedModuli(rOut, dropped); err != nil {
+		return fmt.Errorf("cannot DivRoundByModuliNTT: %w", err)
+	}
+
+	r.INTT(p0, buff)
+
+	if err = r.DivRoundByModuli(rOut, dropped, buff, p1); err != nil {
+		return
+	}
+
+	rOut.NTT(p1, p1)
+
+	return
+}
+
+// checkDroppedModuli checks that the moduli chain of rOut is the chain of the
+// receiver at the current level with the moduli at the indices listed in
+// dropped removed.
+func (r Ring) checkDroppedModuli(rOut *Ring, dropped []int) (err error) {
+
+	if rOut.N() != r.N() {
+		return fmt.Errorf("output ring degree %d does not match the ring degree %d", rOut.N(), r.N())
+	}
+
+	drop := make([]bool, r.level+1)
+	for _, i := range dropped {
+		if i < 0 || i > r.level {
+			return fmt.Errorf("dropped index %d lies outside [0, level=%d]", i, r.level)
+		}
+		if drop[i] {
+			return fmt.Errorf("dropped index %d is listed twice", i)
+		}
+		drop[i] = true
+	}
+
+	if kept := r.level + 1 - len(dropped); kept != rOut.level+1 {
+		return fmt.Errorf("output ring has %d moduli but %d moduli are kept", rOut.level+1, kept)
+	}
+
+	for i, j := 0, 0; i < r.level+1; i++ {
+		if !drop[i] {
+			if rOut.SubRings[j].Modulus != r.SubRings[i].Modulus {
+				return fmt.Errorf("output ring moduli chain does not match the kept moduli")
+			}
+			j++
+		}
+	}
+
+	return
+}
@@ -0,0 +1,110 @@
+package ring
+
+import (
+	"fmt"
+)
+
+// SparsePoly is a sparse representation of a polynomial in the positional
+// (non-NTT) domain, storing only its nonzero monomials. It is the natural
+// representation for masks, test polynomials and other low-weight
+// polynomials, for which the dense NTT-based multiplication is wasteful.
+type SparsePoly struct {
+	// Degrees stores the degrees of the nonzero monomials, sorted in
+	// increasing order and lying in [0, N-1].
+	Degrees []int
+	// Coeffs[i][j] is the coefficient of X^{Degrees[j]} modulo the i-th
+	// modulus of the chain.
+	Coeffs [][]uint64
+}
+
+// Weight returns the number of nonzero monomials of the polynomial.
+func (sp SparsePoly) Weight() int {
+	return len(sp.Degrees)
+}
+
+// SparsePolyFromPoly extracts the sparse representation of the polynomial p,
+// which must be in the positional (non-NTT) domain. A monomial is kept if its
+// coefficient is nonzero modulo at least one modulus of the chain.
+func (r Ring) SparsePolyFromPoly(p Poly) (sp SparsePoly) {
+
+	level := r.level
+
+	for j := 0; j < r.N(); j++ {
+		for i := 0; i < level+1; i++ {
+			if p.Coeffs[i][j] != 0 {
+				sp.Degrees = append(sp.Degrees, j)
+				break
+			}
+		}
+	}
+
+	sp.Coeffs = make([][]uint64, level+1)
+	for i := 0; i < level+1; i++ {
+		sp.Coeffs[i] = make([]uint64, len(sp.Degrees))
+		for j, d := range sp.Degrees {
+			sp.Coeffs[i][j] = p.Coeffs[i][d]
+		}
+	}
+
+	return
+}
+
+// SparsePolyToPoly expands the sparse polynomial sp into the dense
+// polynomial p, in the positional (non-NTT) domain.
+func (r Ring) SparsePolyToPoly(sp SparsePoly, p Poly) {
+	for i := 0; i < r.level+1; i++ {
+		pCoeffs := p.Coeffs[i]
+		for j := range pCoeffs {
+			pCoeffs[j] = 0
+		}
+		for j, d := range sp.Degrees {
+			pCoeffs[d] = sp.Coeffs[i][j]
+		}
+	}
+}
+
+// MulSparse evaluates p2 = p1 * sp, the negacyclic product of a dense
+// polynomial by a sparse one, without NTT. The cost is O(N * sp.Weight()), so
+// it outperforms the NTT-based product when the weight is below ~log(N). The
+// polynomials must be in the positional (non-NTT) domain and the result
+// cannot be in-place.
+func (r Ring) MulSparse(p1 Poly, sp SparsePoly, p2 Poly) (err error) {
+
+	N := r.N()
+
+	for _, d := range sp.Degrees {
+		if d < 0 || d >= N {
+			return fmt.Errorf("cannot MulSparse: monomial degree %d lies outside [0, N-1=%d]", d, N-1)
+		}
+	}
+
+	for i, s := range r.SubRings[:r.level+1] {
+
+		p1Coeffs, p2Coeffs := p1.Coeffs[i], p2.Coeffs[i]
+
+		for j := 0; j < N; j++ {
+			p2Coeffs[j] = 0
+		}
+
+		for t, d := range sp.Degrees {
+
+			c := MForm(sp.Coeffs[i][t], s.Modulus, s.BRedConstant)
+
+			if c == 0 {
+				continue
+			}
+
+			for j := 0; j < N; j++ {
+				v := MRed(p1Coeffs[j], c, s.Modulus, s.MRedConstant)
+				if k := j + d; k < N {
+					p2Coeffs[k] = CRed(p2Coeffs[k]+v, s.Modulus)
+				} else {
+					// X^N = -1: the wrapped coefficients are subtracted.
+					p2Coeffs[k-N] = CRed(p2Coeffs[k-N]+s.Modulus-v, s.Modulus)
+				}
+			}
+		}
+	}
+
+	return
+}
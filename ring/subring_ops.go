@@ -91,6 +91,13 @@ func (s *SubRing) MulCoeffsBarrettThenAddLazy(p1, p2, p3 []uint64) {
 	mulcoeffsbarrettthenaddlazyvec(p1, p2, p3, s.Modulus, s.BRedConstant)
 }
 
+// MulCoeffsBarrettThenSub evaluates p3 = p3 - (p1*p2) (mod modulus).
+// Iteration is done with respect to len(p1).
+// All input must have a size which is a multiple of 8.
+func (s *SubRing) MulCoeffsBarrettThenSub(p1, p2, p3 []uint64) {
+	mulcoeffsbarrettthensubvec(p1, p2, p3, s.Modulus, s.BRedConstant)
+}
+
 // MulCoeffsMontgomery evaluates p3 = p1*p2 (mod modulus).
 // Iteration is done with respect to len(p1).
 // All input must have a size which is a multiple of 8.
@@ -251,6 +258,32 @@ func (s *SubRing) INTTLazy(p1, p2 []uint64) {
 	s.ntt.BackwardLazy(p1, p2)
 }
 
+// NTTThenMForm evaluates p2 = MForm(NTT(p1)). The Montgomery conversion,
+// which is exact for the lazy output of the NTT, replaces the final
+// reduction pass, saving a full pass over the coefficients compared to NTT
+// followed by MForm.
+func (s *SubRing) NTTThenMForm(p1, p2 []uint64) {
+	s.ntt.ForwardLazy(p1, p2)
+	mformvec(p2, p2, s.Modulus, s.BRedConstant)
+}
+
+// INTTThenIMForm evaluates p2 = IMForm(INTT(p1)), with the inverse
+// Montgomery conversion folded into the final scaling by N^-1 of the INTT,
+// saving a full pass over the coefficients compared to INTT followed by
+// IMForm.
+func (s *SubRing) INTTThenIMForm(p1, p2 []uint64) {
+	switch s.ntt.(type) {
+	case NumberTheoreticTransformerStandard:
+		INTTStandardThenIMForm(p1, p2, s.N, s.NInv, s.Modulus, s.MRedConstant, s.RootsBackward)
+	case NumberTheoreticTransformerConjugateInvariant:
+		INTTConjugateInvariantThenIMForm(p1, p2, s.N, s.NInv, s.Modulus, s.MRedConstant, s.RootsBackward)
+	default:
+		// Unfused fallback for custom transformers.
+		s.ntt.Backward(p1, p2)
+		imformvec(p2, p2, s.Modulus, s.MRedConstant)
+	}
+}
+
 // MForm evaluates p2 = p1 * 2^64 (mod modulus).
 // Iteration is done with respect to len(p1).
 // All input must have a size which is a multiple of 8.
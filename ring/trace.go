@@ -0,0 +1,109 @@
+package ring
+
+import (
+	"fmt"
+)
+
+// Trace evaluates the normalized trace map (n/N) * Tr_{R_N/R_n}(p1) of the
+// sub-ring R_n = Z_Q[Y]/(Y^n + 1) with Y = X^{N/n} and n = 2^logn, and writes
+// the result on p2. The normalized trace keeps the coefficients of p1 whose
+// degree is a multiple of N/n and zeroes all the others. The polynomials must
+// be in the positional (non-NTT) domain.
+//
+// The homomorphic equivalent on ciphertexts, with the matching key material,
+// is provided by rlwe.Evaluator.Trace and rlwe.GaloisElementsForTrace.
+func (r Ring) Trace(p1 Poly, logn int, p2 Poly) (err error) {
+
+	if logn < 0 || logn > r.LogN() {
+		return fmt.Errorf("cannot Trace: logn=%d must lie in [0, LogN=%d]", logn, r.LogN())
+	}
+
+	gap := r.N() >> logn
+
+	for i := 0; i < r.level+1; i++ {
+		p1Coeffs, p2Coeffs := p1.Coeffs[i], p2.Coeffs[i]
+		for j := 0; j < r.N(); j++ {
+			if j%gap == 0 {
+				p2Coeffs[j] = p1Coeffs[j]
+			} else {
+				p2Coeffs[j] = 0
+			}
+		}
+	}
+
+	return
+}
+
+// ProjectToSubring projects p1 onto the sub-ring rSub = Z_Q[Y]/(Y^n + 1) with
+// Y = X^{N/n}, writing the coefficient of X^{j*N/n} of p1 on the coefficient
+// of Y^j of p2. Coefficients of p1 whose degree is not a multiple of N/n are
+// discarded (apply Trace first to fold them if needed). The sub-ring must
+// have a smaller or equal degree and the same moduli chain at the current
+// level. The polynomials must be in the positional (non-NTT) domain.
+func (r Ring) ProjectToSubring(rSub *Ring, p1, p2 Poly) (err error) {
+
+	if err = r.checkSubring(rSub); err != nil {
+		return fmt.Errorf("cannot ProjectToSubring: %w", err)
+	}
+
+	gap := r.N() / rSub.N()
+
+	for i := 0; i < r.level+1; i++ {
+		p1Coeffs, p2Coeffs := p1.Coeffs[i], p2.Coeffs[i]
+		for j := 0; j < rSub.N(); j++ {
+			p2Coeffs[j] = p1Coeffs[j*gap]
+		}
+	}
+
+	return
+}
+
+// EmbedFromSubring embeds the polynomial p1 of the sub-ring rSub =
+// Z_Q[Y]/(Y^n + 1) into the receiver ring through Y -> X^{N/n}, writing the
+// coefficient of Y^j of p1 on the coefficient of X^{j*N/n} of p2 and zeroing
+// all the others. The sub-ring must have a smaller or equal degree and the
+// same moduli chain at the current level. The polynomials must be in the
+// positional (non-NTT) domain.
+func (r Ring) EmbedFromSubring(rSub *Ring, p1, p2 Poly) (err error) {
+
+	if err = r.checkSubring(rSub); err != nil {
+		return fmt.Errorf("cannot EmbedFromSubring: %w", err)
+	}
+
+	gap := r.N() / rSub.N()
+
+	for i := 0; i < r.level+1; i++ {
+		p1Coeffs, p2Coeffs := p1.Coeffs[i], p2.Coeffs[i]
+		for j := r.N() - 1; j >= 0; j-- {
+			if j%gap == 0 {
+				p2Coeffs[j] = p1Coeffs[j/gap]
+			} else {
+				p2Coeffs[j] = 0
+			}
+		}
+	}
+
+	return
+}
+
+// checkSubring checks that rSub is a valid sub-ring of the receiver: a
+// smaller or equal power-of-two degree and the same moduli chain at the
+// current level.
+func (r Ring) checkSubring(rSub *Ring) (err error) {
+
+	if rSub.N() > r.N() {
+		return fmt.Errorf("sub-ring degree %d is larger than the ring degree %d", rSub.N(), r.N())
+	}
+
+	if rSub.level != r.level {
+		return fmt.Errorf("sub-ring level %d does not match the ring level %d", rSub.level, r.level)
+	}
+
+	for i := 0; i < r.level+1; i++ {
+		if rSub.SubRings[i].Modulus != r.SubRings[i].Modulus {
+			return fmt.Errorf("sub-ring moduli chain does not match the ring moduli chain")
+		}
+	}
+
+	return
+}
@@ -310,6 +310,30 @@ func mulcoeffsbarrettthenaddlazyvec(p1, p2, p3 []uint64, modulus uint64, brc []u
 	}
 }
 
+func mulcoeffsbarrettthensubvec(p1, p2, p3 []uint64, modulus uint64, brc []uint64) {
+
+	N := len(p1)
+
+	for j := 0; j < N; j = j + 8 {
+
+		/* #nosec G103 -- behavior and consequences well understood, possible buffer overflow if len(p1)%8 */
+		x := (*[8]uint64)(unsafe.Pointer(&p1[j]))
+		/* #nosec G103 -- behavior and consequences well understood, possible buffer overflow if len(p2)%8 */
+		y := (*[8]uint64)(unsafe.Pointer(&p2[j]))
+		/* #nosec G103 -- behavior and consequences well understood, possible buffer overflow if len(p3)%8 */
+		z := (*[8]uint64)(unsafe.Pointer(&p3[j]))
+
+		z[0] = CRed(z[0]+(modulus-BRed(x[0], y[0], modulus, brc)), modulus)
+		z[1] = CRed(z[1]+(modulus-BRed(x[1], y[1], modulus, brc)), modulus)
+		z[2] = CRed(z[2]+(modulus-BRed(x[2], y[2], modulus, brc)), modulus)
+		z[3] = CRed(z[3]+(modulus-BRed(x[3], y[3], modulus, brc)), modulus)
+		z[4] = CRed(z[4]+(modulus-BRed(x[4], y[4], modulus, brc)), modulus)
+		z[5] = CRed(z[5]+(modulus-BRed(x[5], y[5], modulus, brc)), modulus)
+		z[6] = CRed(z[6]+(modulus-BRed(x[6], y[6], modulus, brc)), modulus)
+		z[7] = CRed(z[7]+(modulus-BRed(x[7], y[7], modulus, brc)), modulus)
+	}
+}
+
 func mulcoeffsmontgomeryvec(p1, p2, p3 []uint64, modulus, mrc uint64) {
 
 	N := len(p1)
@@ -0,0 +1,187 @@
+package bfv
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/schemes/bgv"
+)
+
+// CircuitEvaluator is the interface through which a DifferentialCircuit
+// evaluates its operations. It is implemented by both *bgv.Evaluator and
+// *Evaluator and is restricted to the operations whose semantics modulo
+// the plaintext modulus match across the two schemes (notably excluding
+// the BGV rescaling).
+type CircuitEvaluator interface {
+	Add(op0 *rlwe.Ciphertext, op1 rlwe.Operand, opOut *rlwe.Ciphertext) (err error)
+	AddNew(op0 *rlwe.Ciphertext, op1 rlwe.Operand) (opOut *rlwe.Ciphertext, err error)
+	Sub(op0 *rlwe.Ciphertext, op1 rlwe.Operand, opOut *rlwe.Ciphertext) (err error)
+	SubNew(op0 *rlwe.Ciphertext, op1 rlwe.Operand) (opOut *rlwe.Ciphertext, err error)
+	Mul(op0 *rlwe.Ciphertext, op1 rlwe.Operand, opOut *rlwe.Ciphertext) (err error)
+	MulNew(op0 *rlwe.Ciphertext, op1 rlwe.Operand) (opOut *rlwe.Ciphertext, err error)
+	MulRelin(op0 *rlwe.Ciphertext, op1 rlwe.Operand, opOut *rlwe.Ciphertext) (err error)
+	MulRelinNew(op0 *rlwe.Ciphertext, op1 rlwe.Operand) (opOut *rlwe.Ciphertext, err error)
+	RelinearizeNew(op0 *rlwe.Ciphertext) (opOut *rlwe.Ciphertext, err error)
+	RotateColumns(op0 *rlwe.Ciphertext, k int, opOut *rlwe.Ciphertext) (err error)
+	RotateColumnsNew(op0 *rlwe.Ciphertext, k int) (opOut *rlwe.Ciphertext, err error)
+	RotateRows(op0, opOut *rlwe.Ciphertext) (err error)
+	RotateRowsNew(op0 *rlwe.Ciphertext) (opOut *rlwe.Ciphertext, err error)
+}
+
+var _ CircuitEvaluator = (*bgv.Evaluator)(nil)
+var _ CircuitEvaluator = (*Evaluator)(nil)
+
+// DifferentialCircuit describes an integer circuit for RunDifferential.
+type DifferentialCircuit struct {
+	// Inputs holds the plaintext input vectors of the circuit, each
+	// encrypted into one ciphertext. Values must be reduced modulo the
+	// plaintext modulus.
+	Inputs [][]uint64
+	// Rotations lists the column rotations used by the circuit, for
+	// which Galois keys are generated (the row-rotation key is always
+	// provided).
+	Rotations []int
+	// Evaluate evaluates the circuit over the encrypted inputs and
+	// returns its encrypted result.
+	Evaluate func(eval CircuitEvaluator, inputs []*rlwe.Ciphertext) (*rlwe.Ciphertext, error)
+}
+
+// DifferentialReport is the outcome of a differential evaluation: the
+// decoded result of each scheme, the slots on which they diverge and the
+// wall-clock evaluation time per scheme, the latter being a cheap way of
+// picking the faster scheme for a given workload.
+type DifferentialReport struct {
+	// BGV and BFV hold the decoded result of the respective scheme.
+	BGV, BFV []uint64
+	// Divergences lists the indices of the slots on which the two
+	// decoded results differ. Any divergence is a correctness bug in
+	// one of the two evaluators (or an exhausted noise budget).
+	Divergences []int
+	// TimeBGV and TimeBFV are the wall-clock durations of the circuit
+	// evaluation (excluding key generation, encryption and decryption)
+	// under the respective scheme.
+	TimeBGV, TimeBFV time.Duration
+}
+
+// Diverged returns true if the two schemes decoded to different results.
+func (r DifferentialReport) Diverged() bool {
+	return len(r.Divergences) > 0
+}
+
+// RunDifferential evaluates the circuit through both the BGV and the BFV
+// evaluators under matched parameters (same ring, moduli chain and
+// plaintext modulus, instantiated from the given literal) and reports the
+// decoded results, their divergences and the evaluation time per scheme.
+func RunDifferential(paramsLiteral ParametersLiteral, circuit DifferentialCircuit) (report *DifferentialReport, err error) {
+
+	if circuit.Evaluate == nil {
+		return nil, fmt.Errorf("cannot RunDifferential: circuit.Evaluate is nil")
+	}
+
+	if len(circuit.Inputs) == 0 {
+		return nil, fmt.Errorf("cannot RunDifferential: circuit has no inputs")
+	}
+
+	report = &DifferentialReport{}
+
+	paramsBGV, err := bgv.NewParametersFromLiteral(bgv.ParametersLiteral(paramsLiteral))
+	if err != nil {
+		return nil, fmt.Errorf("cannot RunDifferential: %w", err)
+	}
+
+	kgenBGV := rlwe.NewKeyGenerator(paramsBGV)
+	skBGV := kgenBGV.GenSecretKeyNew()
+	evkBGV := rlwe.NewMemEvaluationKeySet(kgenBGV.GenRelinearizationKeyNew(skBGV), kgenBGV.GenGaloisKeysNew(galoisElements(paramsBGV, circuit.Rotations), skBGV)...)
+
+	if report.BGV, report.TimeBGV, err = runDifferentialArm(differentialArm{
+		encoder:   bgv.NewEncoder(paramsBGV),
+		encryptor: rlwe.NewEncryptor(paramsBGV, skBGV),
+		decryptor: rlwe.NewDecryptor(paramsBGV, skBGV),
+		evaluator: bgv.NewEvaluator(paramsBGV, evkBGV),
+		newPt:     func() *rlwe.Plaintext { return bgv.NewPlaintext(paramsBGV, paramsBGV.MaxLevel()) },
+		slots:     paramsBGV.MaxSlots(),
+	}, circuit); err != nil {
+		return nil, fmt.Errorf("cannot RunDifferential: BGV: %w", err)
+	}
+
+	paramsBFV, err := NewParametersFromLiteral(paramsLiteral)
+	if err != nil {
+		return nil, fmt.Errorf("cannot RunDifferential: %w", err)
+	}
+
+	kgenBFV := NewKeyGenerator(paramsBFV)
+	skBFV := kgenBFV.GenSecretKeyNew()
+	evkBFV := rlwe.NewMemEvaluationKeySet(kgenBFV.GenRelinearizationKeyNew(skBFV), kgenBFV.GenGaloisKeysNew(galoisElements(paramsBFV.Parameters, circuit.Rotations), skBFV)...)
+
+	if report.BFV, report.TimeBFV, err = runDifferentialArm(differentialArm{
+		encoder:   NewEncoder(paramsBFV),
+		encryptor: NewEncryptor(paramsBFV, skBFV),
+		decryptor: NewDecryptor(paramsBFV, skBFV),
+		evaluator: NewEvaluator(paramsBFV, evkBFV),
+		newPt:     func() *rlwe.Plaintext { return NewPlaintext(paramsBFV, paramsBFV.MaxLevel()) },
+		slots:     paramsBFV.MaxSlots(),
+	}, circuit); err != nil {
+		return nil, fmt.Errorf("cannot RunDifferential: BFV: %w", err)
+	}
+
+	for i := range report.BGV {
+		if report.BGV[i] != report.BFV[i] {
+			report.Divergences = append(report.Divergences, i)
+		}
+	}
+
+	return
+}
+
+// differentialArm bundles the scheme-specific components of one arm of a
+// differential evaluation.
+type differentialArm struct {
+	encoder interface {
+		Encode(values bgv.IntegerSlice, pt *rlwe.Plaintext) (err error)
+		Decode(pt *rlwe.Plaintext, values bgv.IntegerSlice) (err error)
+	}
+	encryptor *rlwe.Encryptor
+	decryptor *rlwe.Decryptor
+	evaluator CircuitEvaluator
+	newPt     func() *rlwe.Plaintext
+	slots     int
+}
+
+// runDifferentialArm encrypts the inputs, times the circuit evaluation
+// and returns the decoded result.
+func runDifferentialArm(arm differentialArm, circuit DifferentialCircuit) (values []uint64, elapsed time.Duration, err error) {
+
+	inputs := make([]*rlwe.Ciphertext, len(circuit.Inputs))
+	for i := range inputs {
+
+		pt := arm.newPt()
+		if err = arm.encoder.Encode(circuit.Inputs[i], pt); err != nil {
+			return nil, 0, err
+		}
+
+		if inputs[i], err = arm.encryptor.EncryptNew(pt); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	start := time.Now()
+
+	ct, err := circuit.Evaluate(arm.evaluator, inputs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	elapsed = time.Since(start)
+
+	values = make([]uint64, arm.slots)
+
+	return values, elapsed, arm.encoder.Decode(arm.decryptor.DecryptNew(ct), values)
+}
+
+// galoisElements returns the Galois elements of the given column
+// rotations and of the row rotation.
+func galoisElements(params bgv.Parameters, rotations []int) (galEls []uint64) {
+	galEls = params.GaloisElements(rotations)
+	return append(galEls, params.GaloisElementForRowRotation())
+}
@@ -0,0 +1,82 @@
+package bfv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/utils/sampling"
+)
+
+func TestRunDifferential(t *testing.T) {
+
+	paramsLiteral := testInsecure
+	paramsLiteral.PlaintextModulus = testPlaintextModulus[1]
+
+	params, err := NewParametersFromLiteral(paramsLiteral)
+	require.NoError(t, err)
+
+	tMod := params.PlaintextModulus()
+	slots := params.MaxSlots()
+	half := slots >> 1
+
+	inputs := make([][]uint64, 3)
+	for i := range inputs {
+		inputs[i] = make([]uint64, slots)
+		for j := range inputs[i] {
+			inputs[i][j] = sampling.RandUint64() % tMod
+		}
+	}
+
+	// ((a + b) * c) + (a rotated by one column)
+	circuit := DifferentialCircuit{
+		Inputs:    inputs,
+		Rotations: []int{1},
+		Evaluate: func(eval CircuitEvaluator, inputs []*rlwe.Ciphertext) (*rlwe.Ciphertext, error) {
+
+			sum, err := eval.AddNew(inputs[0], inputs[1])
+			if err != nil {
+				return nil, err
+			}
+
+			prod, err := eval.MulRelinNew(sum, inputs[2])
+			if err != nil {
+				return nil, err
+			}
+
+			rot, err := eval.RotateColumnsNew(inputs[0], 1)
+			if err != nil {
+				return nil, err
+			}
+
+			return prod, eval.Add(prod, rot, prod)
+		},
+	}
+
+	report, err := RunDifferential(paramsLiteral, circuit)
+	require.NoError(t, err)
+
+	require.False(t, report.Diverged())
+	require.Empty(t, report.Divergences)
+	require.Len(t, report.BGV, slots)
+	require.Len(t, report.BFV, slots)
+	require.Positive(t, report.TimeBGV)
+	require.Positive(t, report.TimeBFV)
+
+	// Both schemes must also agree with the plaintext reference, the
+	// column rotation acting on each row of the 2 x N/2 plaintext matrix.
+	a, b, c := inputs[0], inputs[1], inputs[2]
+	for i := 0; i < slots; i++ {
+		rot := a[(i/half)*half+((i+1)%half)]
+		want := ((a[i]+b[i])%tMod*(c[i]%tMod) + rot) % tMod
+		require.Equal(t, want, report.BGV[i])
+		require.Equal(t, want, report.BFV[i])
+	}
+
+	_, err = RunDifferential(paramsLiteral, DifferentialCircuit{Inputs: inputs})
+	require.Error(t, err)
+
+	_, err = RunDifferential(paramsLiteral, DifferentialCircuit{Evaluate: circuit.Evaluate})
+	require.Error(t, err)
+}
@@ -0,0 +1,69 @@
+package bgv
+
+import (
+	"fmt"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/utils"
+)
+
+// DropToLevel reduces the level of op0 to the given level and returns the result in op0.
+// No rescaling is applied during this procedure.
+// Returns an error if level is negative or larger than the current level of op0.
+func (eval Evaluator) DropToLevel(op0 *rlwe.Ciphertext, level int) (err error) {
+	if level < 0 || level > op0.Level() {
+		return fmt.Errorf("cannot DropToLevel: level must be in [0, op0.Level()=%d] but is %d", op0.Level(), level)
+	}
+	eval.DropLevel(op0, op0.Level()-level)
+	return
+}
+
+// AlignScalesAndLevels updates the input ciphertexts in place so that they all share the same
+// level and scale, enabling them to be added or compared without further manual alignment.
+// It generalizes MatchScalesAndLevel to an arbitrary number of ciphertexts: scales are matched
+// pairwise with scalar multiplications minimizing the added noise (see MatchScalesAndLevel),
+// and all ciphertexts are then dropped to the minimum level among the inputs.
+//
+// Plaintexts can be aligned with the same call by wrapping them in a ciphertext, i.e.,
+// &rlwe.Ciphertext{Element: pt.Element}.
+func (eval Evaluator) AlignScalesAndLevels(cts ...*rlwe.Ciphertext) {
+
+	if len(cts) < 2 {
+		return
+	}
+
+	level := cts[0].Level()
+	for _, ct := range cts[1:] {
+		level = utils.Min(level, ct.Level())
+	}
+
+	ringQ := eval.parameters.RingQ()
+
+	mulScalar := func(ct *rlwe.Ciphertext, r uint64) {
+		if r == 1 {
+			return
+		}
+		rQ := ringQ.AtLevel(ct.Level())
+		for _, el := range ct.Value {
+			rQ.MulScalar(el, r, el)
+		}
+		ct.Scale = ct.Scale.Mul(eval.parameters.NewScale(r))
+	}
+
+	for i := 1; i < len(cts); i++ {
+
+		r0, r1, _ := eval.matchScalesBinary(cts[0].Scale.Uint64(), cts[i].Scale.Uint64())
+
+		// Multiplying all the previously aligned ciphertexts by r0 preserves their pairwise
+		// scale equality while bringing them to a scale compatible with cts[i] * r1.
+		for _, ct := range cts[:i] {
+			mulScalar(ct, r0)
+		}
+
+		mulScalar(cts[i], r1)
+	}
+
+	for _, ct := range cts {
+		ct.Resize(ct.Degree(), level)
+	}
+}
@@ -0,0 +1,47 @@
+package bgv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlignScalesAndLevels(t *testing.T) {
+
+	p := testInsecure
+	p.PlaintextModulus = testPlaintextModulus[0]
+
+	params, err := NewParametersFromLiteral(p)
+	require.NoError(t, err)
+
+	tc, err := genTestParams(params)
+	require.NoError(t, err)
+
+	values0, _, ct0 := newTestVectorsLvl(params.MaxLevel(), params.NewScale(1), tc, tc.encryptorSk)
+	values1, _, ct1 := newTestVectorsLvl(params.MaxLevel()-1, params.NewScale(7), tc, tc.encryptorSk)
+	values2, _, ct2 := newTestVectorsLvl(1, params.NewScale(3), tc, tc.encryptorSk)
+
+	tc.evaluator.AlignScalesAndLevels(ct0, ct1, ct2)
+
+	require.Equal(t, 1, ct0.Level())
+	require.Equal(t, 1, ct1.Level())
+	require.Equal(t, 1, ct2.Level())
+	require.True(t, ct0.Scale.Equal(ct1.Scale))
+	require.True(t, ct1.Scale.Equal(ct2.Scale))
+
+	require.NoError(t, tc.evaluator.Add(ct0, ct1, ct0))
+	require.NoError(t, tc.evaluator.Add(ct0, ct2, ct0))
+
+	ringT := params.RingT()
+	want := ringT.NewPoly()
+	ringT.Add(values0, values1, want)
+	ringT.Add(want, values2, want)
+
+	verifyTestVectors(tc, tc.decryptor, want, ct0, t)
+
+	// DropToLevel input validation.
+	require.Error(t, tc.evaluator.DropToLevel(ct0, -1))
+	require.Error(t, tc.evaluator.DropToLevel(ct0, ct0.Level()+1))
+	require.NoError(t, tc.evaluator.DropToLevel(ct0, 0))
+	require.Equal(t, 0, ct0.Level())
+}
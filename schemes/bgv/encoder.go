@@ -471,11 +471,24 @@ func (ecd Encoder) Decode(pt *rlwe.Plaintext, values IntegerSlice) (err error) {
 // ShallowCopy returns a lightweight copy of the target object
 // that can be used concurrently with the original object.
 func (ecd Encoder) ShallowCopy() *Encoder {
+
+	var bufB []*big.Int
+
+	if len(ecd.bufB) > 0 {
+
+		bufB = make([]*big.Int, len(ecd.bufB))
+
+		for i := range bufB {
+			bufB[i] = new(big.Int)
+		}
+	}
+
 	return &Encoder{
 		parameters:  ecd.parameters,
 		indexMatrix: ecd.indexMatrix,
 		bufQ:        ecd.parameters.RingQ().NewPoly(),
 		bufT:        ecd.parameters.RingT().NewPoly(),
+		bufB:        bufB,
 		paramsQP:    ecd.paramsQP,
 		qHalf:       ecd.qHalf,
 		tInvModQ:    ecd.tInvModQ,
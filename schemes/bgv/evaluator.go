@@ -80,6 +80,19 @@ func (eval Evaluator) BuffQ() [3]ring.Poly {
 	return eval.buffQ
 }
 
+// MemoryFootprint returns the deep in-RAM size of the evaluator's own
+// buffers in bytes (see rlwe.Evaluator.MemoryFootprint).
+func (eval Evaluator) MemoryFootprint() (size int) {
+	size = eval.Evaluator.MemoryFootprint()
+	for i := range eval.buffQ {
+		size += eval.buffQ[i].MemoryFootprint()
+	}
+	for i := range eval.buffQMul {
+		size += eval.buffQMul[i].MemoryFootprint()
+	}
+	return
+}
+
 func newEvaluatorBuffer(params Parameters) *evaluatorBuffers {
 
 	ringQ := params.RingQ()
@@ -236,7 +249,7 @@ func (eval Evaluator) Add(op0 *rlwe.Ciphertext, op1 rlwe.Operand, opOut *rlwe.Ci
 		// improperly tempered with. If it does happen, there is no way to
 		// recover from it.
 		if err != nil {
-			panic(err)
+			return rlwe.Fault(err)
 		}
 
 		pt.MetaData = op0.MetaData // Sets the metadata, notably matches scalses
@@ -375,7 +388,7 @@ func (eval Evaluator) Sub(op0 *rlwe.Ciphertext, op1 rlwe.Operand, opOut *rlwe.Ci
 		// improperly tempered with. If it does happen, there is no way to
 		// recover from it.
 		if err != nil {
-			panic(err)
+			return rlwe.Fault(err)
 		}
 
 		pt.MetaData = op0.MetaData // Sets the metadata, notably matches scales
@@ -500,7 +513,7 @@ func (eval Evaluator) Mul(op0 *rlwe.Ciphertext, op1 rlwe.Operand, opOut *rlwe.Ci
 		// improperly tempered with. If it does happen, there is no way to
 		// recover from it.
 		if err != nil {
-			panic(err)
+			return rlwe.Fault(err)
 		}
 
 		pt.MetaData = op0.MetaData.CopyNew() // Sets the metadata, notably matches scales
@@ -762,7 +775,7 @@ func (eval Evaluator) MulScaleInvariant(op0 *rlwe.Ciphertext, op1 rlwe.Operand,
 		// improperly tempered with. If it does happen, there is no way to
 		// recover from it.
 		if err != nil {
-			panic(err)
+			return rlwe.Fault(err)
 		}
 		pt.MetaData = op0.MetaData.CopyNew() // Sets the metadata, notably matches scales
 		pt.Scale = rlwe.NewScale(1)
@@ -870,7 +883,7 @@ func (eval Evaluator) MulRelinScaleInvariant(op0 *rlwe.Ciphertext, op1 rlwe.Oper
 		// improperly tempered with. If it does happen, there is no way to
 		// recover from it.
 		if err != nil {
-			panic(err)
+			return rlwe.Fault(err)
 		}
 
 		pt.MetaData = op0.MetaData.CopyNew() // Sets the metadata, notably matches scales
@@ -1171,7 +1184,7 @@ func (eval Evaluator) MulThenAdd(op0 *rlwe.Ciphertext, op1 rlwe.Operand, opOut *
 		// improperly tempered with. If it does happen, there is no way to
 		// recover from it.
 		if err != nil {
-			panic(err)
+			return rlwe.Fault(err)
 		}
 		pt.MetaData = op0.MetaData.CopyNew() // Sets the metadata, notably matches scales
 
@@ -0,0 +1,74 @@
+package ckks
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/utils"
+)
+
+// DropToLevel reduces the level of op0 to the given level and returns the result in op0.
+// No rescaling is applied during this procedure.
+// Returns an error if level is negative or larger than the current level of op0.
+func (eval Evaluator) DropToLevel(op0 *rlwe.Ciphertext, level int) (err error) {
+	if level < 0 || level > op0.Level() {
+		return fmt.Errorf("cannot DropToLevel: level must be in [0, op0.Level()=%d] but is %d", op0.Level(), level)
+	}
+	eval.DropLevel(op0, op0.Level()-level)
+	return
+}
+
+// AlignScalesAndLevels updates the input ciphertexts in place so that they all share the same
+// level and scale, enabling them to be added or compared without further manual alignment.
+//
+// The common scale is the maximum scale among the inputs. Ciphertexts with a smaller scale are
+// brought to it with a scalar multiplication when the scale ratio is an integer (which does not
+// consume a level) and with SetScale otherwise (which consumes params.LevelsConsumedPerRescaling()
+// levels). All ciphertexts are then dropped to the minimum remaining level.
+//
+// Plaintexts can be aligned with the same call by wrapping them in a ciphertext, i.e.,
+// &rlwe.Ciphertext{Element: pt.Element}.
+func (eval Evaluator) AlignScalesAndLevels(cts ...*rlwe.Ciphertext) (err error) {
+
+	if len(cts) < 2 {
+		return
+	}
+
+	maxScale := cts[0].Scale
+	for _, ct := range cts[1:] {
+		maxScale = maxScale.Max(ct.Scale)
+	}
+
+	for _, ct := range cts {
+
+		if ct.Scale.Equal(maxScale) {
+			continue
+		}
+
+		ratio := maxScale.Div(ct.Scale)
+
+		if r, acc := ratio.Value.Uint64(); acc == big.Exact {
+			if err = eval.ScaleUp(ct, rlwe.NewScale(r), ct); err != nil {
+				return fmt.Errorf("cannot AlignScalesAndLevels: %w", err)
+			}
+		} else {
+			if err = eval.SetScale(ct, maxScale); err != nil {
+				return fmt.Errorf("cannot AlignScalesAndLevels: %w", err)
+			}
+		}
+	}
+
+	level := cts[0].Level()
+	for _, ct := range cts[1:] {
+		level = utils.Min(level, ct.Level())
+	}
+
+	for _, ct := range cts {
+		if err = eval.DropToLevel(ct, level); err != nil {
+			return fmt.Errorf("cannot AlignScalesAndLevels: %w", err)
+		}
+	}
+
+	return
+}
@@ -37,7 +37,7 @@ func NewDomainSwitcher(params Parameters, comlexToRealEvk, realToComplexEvk *rlw
 
 	// Sanity check, this error should not happen unless the
 	// algorithm has been modified to provide invalid inputs.
-	if s.automorphismIndex, err = ring.AutomorphismNTTIndex(s.stdRingQ.N(), s.stdRingQ.NthRoot(), s.stdRingQ.NthRoot()-1); err != nil {
+	if s.automorphismIndex, err = ring.AutomorphismNTTIndexShared(s.stdRingQ.N(), s.stdRingQ.NthRoot(), s.stdRingQ.NthRoot()-1); err != nil {
 		panic(err)
 	}
 
@@ -81,7 +81,11 @@ func (switcher DomainSwitcher) ComplexToReal(eval *Evaluator, ctIn, opOut *rlwe.
 
 	switcher.conjugateRingQ.AtLevel(level).FoldStandardToConjugateInvariant(evalRLWE.BuffQP[1].Q, switcher.automorphismIndex, opOut.Value[0])
 	switcher.conjugateRingQ.AtLevel(level).FoldStandardToConjugateInvariant(evalRLWE.BuffQP[2].Q, switcher.automorphismIndex, opOut.Value[1])
+	// opOut belongs to the conjugate invariant parameters: it keeps its own
+	// parameter binding instead of inheriting ctIn's.
+	fingerprint := opOut.ParameterFingerprint
 	*opOut.MetaData = *ctIn.MetaData
+	opOut.ParameterFingerprint = fingerprint
 	opOut.Scale = ctIn.Scale.Mul(rlwe.NewScale(2))
 	return
 }
@@ -124,6 +128,10 @@ func (switcher DomainSwitcher) RealToComplex(eval *Evaluator, ctIn, opOut *rlwe.
 	evalRLWE.GadgetProduct(level, opOut.Value[1], &switcher.ciToStd.GadgetCiphertext, ctTmp)
 	switcher.stdRingQ.AtLevel(level).Add(opOut.Value[0], evalRLWE.BuffQP[1].Q, opOut.Value[0])
 	opOut.Value[1].CopyLvl(level, evalRLWE.BuffQP[2].Q)
+	// opOut belongs to the standard parameters: it keeps its own parameter
+	// binding instead of inheriting ctIn's.
+	fingerprint := opOut.ParameterFingerprint
 	*opOut.MetaData = *ctIn.MetaData
+	opOut.ParameterFingerprint = fingerprint
 	return
 }
@@ -923,3 +923,59 @@ func testBridge(tc *testContext, t *testing.T) {
 		VerifyTestVectors(tc.params, tc.encoder, tc.decryptor, values, ciCTHave, tc.params.LogDefaultScale(), 0, *printPrecisionStats, t)
 	})
 }
+
+func TestConjugateWithoutGaloisKey(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN:            10,
+		LogQ:            []int{55, 45},
+		LogP:            []int{60},
+		LogDefaultScale: 45,
+	})
+	require.NoError(t, err)
+
+	kgen := rlwe.NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+	ecd := NewEncoder(params)
+	enc := rlwe.NewEncryptor(params, sk)
+	dec := rlwe.NewDecryptor(params, sk)
+
+	// No evaluation keys given to the evaluator.
+	eval := NewEvaluator(params, nil)
+
+	values := make([]float64, params.N())
+	for i := range values {
+		values[i] = sampling.RandFloat64(-1, 1)
+	}
+
+	// On a coefficient-encoded (real-valued) plaintext, the conjugation is
+	// the identity and requires no Galois key.
+	pt := NewPlaintext(params, params.MaxLevel())
+	pt.IsBatched = false
+	require.NoError(t, ecd.Encode(values, pt))
+
+	ct, err := enc.EncryptNew(pt)
+	require.NoError(t, err)
+
+	ctConj, err := eval.ConjugateNew(ct)
+	require.NoError(t, err)
+
+	have := make([]float64, params.N())
+	require.NoError(t, ecd.Decode(dec.DecryptNew(ctConj), have))
+
+	for i := range values {
+		require.InDelta(t, values[i], have[i], 1e-4)
+	}
+
+	// On a batched plaintext the conjugation is an automorphism and still
+	// requires the corresponding Galois key.
+	ptBatched := NewPlaintext(params, params.MaxLevel())
+	require.NoError(t, ecd.Encode(values[:params.MaxSlots()], ptBatched))
+
+	ctBatched, err := enc.EncryptNew(ptBatched)
+	require.NoError(t, err)
+
+	_, err = eval.ConjugateNew(ctBatched)
+	require.Error(t, err)
+}
@@ -0,0 +1,194 @@
+package ckks
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/utils"
+	"github.com/luxdefi/lattice/v5/utils/bignum"
+)
+
+// OverflowPolicy defines how the typed decode methods handle slot values
+// falling outside of the range of the output type.
+type OverflowPolicy int
+
+const (
+	// OverflowSaturate clamps out-of-range values to the closest
+	// representable value of the output type.
+	OverflowSaturate = OverflowPolicy(0)
+	// OverflowError aborts the decoding with an error on the first
+	// out-of-range value.
+	OverflowError = OverflowPolicy(1)
+)
+
+// decodeSlots decodes the slots of pt on the internal complex buffer of the
+// encoder, which the typed decode methods then convert directly into the
+// caller-provided buffer, avoiding the intermediate slice of the generic
+// Decode path.
+func (ecd Encoder) decodeSlots(pt *rlwe.Plaintext) (slots int, err error) {
+
+	logSlots := pt.LogDimensions.Cols
+	slots = 1 << logSlots
+
+	if maxLogCols := ecd.parameters.LogMaxDimensions().Cols; logSlots > maxLogCols || logSlots < 0 {
+		return 0, fmt.Errorf("ensure that %d <= logSlots (%d) <= %d", 0, logSlots, maxLogCols)
+	}
+
+	if !pt.IsBatched {
+		return 0, fmt.Errorf("method requires IsBatched = true")
+	}
+
+	if pt.IsNTT {
+		ecd.parameters.RingQ().AtLevel(pt.Level()).INTT(pt.Value, ecd.buff)
+	} else {
+		ecd.buff.CopyLvl(pt.Level(), pt.Value)
+	}
+
+	if ecd.prec <= 53 {
+
+		buffCmplx := ecd.buffCmplx.([]complex128)
+
+		if err = ecd.plaintextToComplex(pt.Level(), pt.Scale, logSlots, ecd.buff, buffCmplx); err != nil {
+			return
+		}
+
+		return slots, ecd.FFT(buffCmplx[:slots], logSlots)
+	}
+
+	buffCmplx := ecd.buffCmplx.([]*bignum.Complex)
+
+	if err = ecd.plaintextToComplex(pt.Level(), pt.Scale, logSlots, ecd.buff, buffCmplx[:slots]); err != nil {
+		return
+	}
+
+	return slots, ecd.FFT(buffCmplx[:slots], logSlots)
+}
+
+// slotReal returns the real part of the i-th decoded slot as a float64.
+func (ecd Encoder) slotReal(i int) float64 {
+	if ecd.prec <= 53 {
+		return real(ecd.buffCmplx.([]complex128)[i])
+	}
+	v, _ := ecd.buffCmplx.([]*bignum.Complex)[i][0].Float64()
+	return v
+}
+
+// DecodeFloat32 decodes pt directly into the caller-provided values buffer,
+// converting up to min(len(values), slots) slots to float32. Values outside
+// of the float32 range are handled according to the policy. It returns the
+// largest absolute error introduced by the conversions, clamping excluded.
+func (ecd Encoder) DecodeFloat32(pt *rlwe.Plaintext, values []float32, policy OverflowPolicy) (maxErr float64, err error) {
+
+	slots, err := ecd.decodeSlots(pt)
+	if err != nil {
+		return 0, fmt.Errorf("cannot DecodeFloat32: %w", err)
+	}
+
+	for i := 0; i < utils.Min(len(values), slots); i++ {
+
+		v := ecd.slotReal(i)
+
+		switch {
+		case v > math.MaxFloat32:
+			if policy == OverflowError {
+				return maxErr, fmt.Errorf("cannot DecodeFloat32: slot %d (%f) overflows float32", i, v)
+			}
+			values[i] = math.MaxFloat32
+		case v < -math.MaxFloat32:
+			if policy == OverflowError {
+				return maxErr, fmt.Errorf("cannot DecodeFloat32: slot %d (%f) overflows float32", i, v)
+			}
+			values[i] = -math.MaxFloat32
+		default:
+			values[i] = float32(v)
+			maxErr = math.Max(maxErr, math.Abs(v-float64(values[i])))
+		}
+	}
+
+	return
+}
+
+// DecodeInt64 decodes pt directly into the caller-provided values buffer,
+// rounding up to min(len(values), slots) slots to the nearest int64. Values
+// outside of the int64 range are handled according to the policy. It returns
+// the largest absolute rounding error, clamping excluded.
+func (ecd Encoder) DecodeInt64(pt *rlwe.Plaintext, values []int64, policy OverflowPolicy) (maxErr float64, err error) {
+
+	slots, err := ecd.decodeSlots(pt)
+	if err != nil {
+		return 0, fmt.Errorf("cannot DecodeInt64: %w", err)
+	}
+
+	for i := 0; i < utils.Min(len(values), slots); i++ {
+
+		v := ecd.slotReal(i)
+		r := math.Round(v)
+
+		// The comparison bounds are exact float64 values enclosing the int64
+		// range.
+		switch {
+		case r >= 0x1p63:
+			if policy == OverflowError {
+				return maxErr, fmt.Errorf("cannot DecodeInt64: slot %d (%f) overflows int64", i, v)
+			}
+			values[i] = math.MaxInt64
+		case r < -0x1p63:
+			if policy == OverflowError {
+				return maxErr, fmt.Errorf("cannot DecodeInt64: slot %d (%f) overflows int64", i, v)
+			}
+			values[i] = math.MinInt64
+		default:
+			values[i] = int64(r)
+			maxErr = math.Max(maxErr, math.Abs(v-r))
+		}
+	}
+
+	return
+}
+
+// DecodeBigInt decodes pt directly into the caller-provided values buffer,
+// rounding up to min(len(values), slots) slots to the nearest integer. Nil
+// entries are allocated. It returns the largest absolute rounding error.
+func (ecd Encoder) DecodeBigInt(pt *rlwe.Plaintext, values []*big.Int) (maxErr float64, err error) {
+
+	slots, err := ecd.decodeSlots(pt)
+	if err != nil {
+		return 0, fmt.Errorf("cannot DecodeBigInt: %w", err)
+	}
+
+	half := new(big.Float).SetFloat64(0.5)
+	tmp := new(big.Float).SetPrec(ecd.Prec())
+
+	for i := 0; i < utils.Min(len(values), slots); i++ {
+
+		if values[i] == nil {
+			values[i] = new(big.Int)
+		}
+
+		if ecd.prec <= 53 {
+			tmp.SetFloat64(ecd.slotReal(i))
+		} else {
+			tmp.Set(ecd.buffCmplx.([]*bignum.Complex)[i][0])
+		}
+
+		// Round = floor +/- 0.5
+		if tmp.Signbit() {
+			tmp.Sub(tmp, half)
+		} else {
+			tmp.Add(tmp, half)
+		}
+
+		tmp.Int(values[i])
+
+		tmp.Sub(tmp, new(big.Float).SetInt(values[i]))
+		e, _ := tmp.Float64()
+
+		// The rounding error is the distance to the integer, i.e. the
+		// truncated fractional part recentered around zero.
+		maxErr = math.Max(maxErr, math.Abs(math.Abs(e)-0.5))
+	}
+
+	return
+}
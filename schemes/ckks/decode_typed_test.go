@@ -0,0 +1,128 @@
+package ckks
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+)
+
+func TestDecodeTyped(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN:            10,
+		LogQ:            []int{60, 45, 45, 45},
+		LogP:            []int{60},
+		LogDefaultScale: 45,
+	})
+	require.NoError(t, err)
+
+	kgen := rlwe.NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+
+	ecd := NewEncoder(params)
+	enc := rlwe.NewEncryptor(params, sk)
+	dec := rlwe.NewDecryptor(params, sk)
+
+	slots := params.MaxSlots()
+
+	encrypt := func(values []float64) *rlwe.Plaintext {
+		pt := NewPlaintext(params, params.MaxLevel())
+		require.NoError(t, ecd.Encode(values, pt))
+		ct, err := enc.EncryptNew(pt)
+		require.NoError(t, err)
+		return dec.DecryptNew(ct)
+	}
+
+	t.Run("Float32", func(t *testing.T) {
+
+		values := make([]float64, slots)
+		for j := range values {
+			values[j] = float64(j%31)/31 - 0.5
+		}
+
+		have := make([]float32, slots)
+		maxErr, err := ecd.DecodeFloat32(encrypt(values), have, OverflowSaturate)
+		require.NoError(t, err)
+
+		for j := range values {
+			require.InDelta(t, values[j], float64(have[j]), 1e-6)
+		}
+		require.Less(t, maxErr, 1e-6)
+	})
+
+	t.Run("Float32Overflow", func(t *testing.T) {
+
+		values := make([]float64, slots)
+		values[0], values[1] = 1e39, -1e39
+
+		have := make([]float32, slots)
+		pt := encrypt(values)
+
+		_, err := ecd.DecodeFloat32(pt, have, OverflowSaturate)
+		require.NoError(t, err)
+		require.Equal(t, float32(math.MaxFloat32), have[0])
+		require.Equal(t, float32(-math.MaxFloat32), have[1])
+
+		_, err = ecd.DecodeFloat32(pt, have, OverflowError)
+		require.Error(t, err)
+	})
+
+	t.Run("Int64", func(t *testing.T) {
+
+		values := make([]float64, slots)
+		for j := range values {
+			values[j] = float64(j%97 - 48)
+		}
+
+		have := make([]int64, slots)
+		maxErr, err := ecd.DecodeInt64(encrypt(values), have, OverflowSaturate)
+		require.NoError(t, err)
+
+		for j := range values {
+			require.Equal(t, int64(values[j]), have[j])
+		}
+
+		// The rounding error on exact integers is the decryption noise.
+		require.Less(t, maxErr, 1e-6)
+	})
+
+	t.Run("Int64Overflow", func(t *testing.T) {
+
+		values := make([]float64, slots)
+		values[0], values[1] = 2e19, -2e19
+
+		have := make([]int64, slots)
+		pt := encrypt(values)
+
+		_, err := ecd.DecodeInt64(pt, have, OverflowSaturate)
+		require.NoError(t, err)
+		require.Equal(t, int64(math.MaxInt64), have[0])
+		require.Equal(t, int64(math.MinInt64), have[1])
+
+		_, err = ecd.DecodeInt64(pt, have, OverflowError)
+		require.Error(t, err)
+	})
+
+	t.Run("BigInt", func(t *testing.T) {
+
+		values := make([]float64, slots)
+		for j := range values {
+			values[j] = float64(j%97 - 48)
+		}
+
+		// Nil entries are allocated by the decoding.
+		have := make([]*big.Int, slots)
+		maxErr, err := ecd.DecodeBigInt(encrypt(values), have)
+		require.NoError(t, err)
+
+		for j := range values {
+			require.Equal(t, int64(values[j]), have[j].Int64())
+		}
+		require.Less(t, maxErr, 1e-6)
+	})
+}
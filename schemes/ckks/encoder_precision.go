@@ -0,0 +1,75 @@
+package ckks
+
+import (
+	"math"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+)
+
+// EncodingPrecisionForScale returns the internal big.Float precision in bits
+// auto-derived for encoding values at the given scale, following the policy
+// of Parameters.EncodingPrecision: max(53, ceil(log2(scale))), i.e. float64
+// arithmetic unless the scale exceeds the float64 mantissa.
+func EncodingPrecisionForScale(scale rlwe.Scale) (prec uint) {
+	if log2scale := math.Log2(scale.Float64()); log2scale <= 53 {
+		prec = 53
+	} else {
+		prec = uint(math.Ceil(log2scale))
+	}
+
+	return
+}
+
+// AtPrecision returns an Encoder sharing the parameters of the receiver but
+// operating at the given internal big.Float precision in bits, reallocating
+// the roots and buffers if the precision differs. If prec is zero, the
+// precision is auto-derived from the parameters' default scale (see
+// Parameters.EncodingPrecision). If prec is the precision of the receiver,
+// the receiver itself is returned.
+func (ecd *Encoder) AtPrecision(prec uint) *Encoder {
+
+	if prec == 0 {
+		prec = ecd.parameters.EncodingPrecision()
+	}
+
+	if prec == ecd.prec {
+		return ecd
+	}
+
+	return NewEncoder(ecd.parameters, prec)
+}
+
+// EncodingExactness reports whether the internal precision of an Encoder is
+// sufficient to encode values at a given scale without the internal
+// arithmetic limiting the accuracy (see Encoder.Exactness).
+type EncodingExactness struct {
+	// Prec is the internal precision in bits of the Encoder.
+	Prec uint
+	// Required is the internal precision in bits below which the internal
+	// arithmetic limits the accuracy of the encoding at the given scale.
+	Required uint
+	// Margin is the headroom Prec - Required in bits, negative if the
+	// internal precision is insufficient.
+	Margin int
+	// Exact reports whether Prec >= Required.
+	Exact bool
+}
+
+// Exactness reports whether the internal precision of the Encoder is
+// sufficient to encode values at the given scale: the special Fourier
+// transform of the slot encoding can lose up to one bit of accuracy per
+// butterfly stage, so encoding at the full accuracy of the scale requires an
+// internal precision of at least ceil(log2(scale)) + LogMaxSlots bits. An
+// Encoder whose report is not Exact still encodes correctly, but with an
+// error above the rounding error of the scale, by up to -Margin bits.
+func (ecd Encoder) Exactness(scale rlwe.Scale) (report EncodingExactness) {
+
+	required := uint(math.Ceil(math.Log2(scale.Float64()))) + uint(ecd.parameters.LogMaxSlots())
+
+	return EncodingExactness{
+		Prec:     ecd.prec,
+		Required: required,
+		Margin:   int(ecd.prec) - int(required),
+		Exact:    ecd.prec >= required,
+	}
+}
@@ -0,0 +1,72 @@
+package ckks
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/utils/bignum"
+)
+
+func TestEncoderPrecision(t *testing.T) {
+
+	params, err := NewParametersFromLiteral(testInsecurePrec90)
+	require.NoError(t, err)
+
+	require.Equal(t, uint(53), EncodingPrecisionForScale(rlwe.NewScale(1<<45)))
+	require.Equal(t, uint(90), EncodingPrecisionForScale(params.DefaultScale()))
+
+	ecd := NewEncoder(params)
+	require.Equal(t, params.EncodingPrecision(), ecd.Prec())
+
+	// The receiver is returned if the precision does not change, and a zero
+	// precision is auto-derived from the parameters.
+	require.True(t, ecd == ecd.AtPrecision(ecd.Prec()))
+	require.True(t, ecd == ecd.AtPrecision(0))
+	require.Equal(t, uint(160), ecd.AtPrecision(160).Prec())
+
+	// The auto-derived precision encodes at the full accuracy of the scale
+	// only up to the accumulated error of the Fourier transform.
+	report := ecd.Exactness(params.DefaultScale())
+	require.Equal(t, uint(90), report.Prec)
+	require.Equal(t, uint(90+params.LogMaxSlots()), report.Required)
+	require.Equal(t, -params.LogMaxSlots(), report.Margin)
+	require.False(t, report.Exact)
+	require.True(t, ecd.AtPrecision(128).Exactness(params.DefaultScale()).Exact)
+
+	// Non-dyadic values, so that their accuracy is limited by the internal
+	// precision of the encoder.
+	values := make([]*big.Float, params.MaxSlots())
+	for i := range values {
+		values[i] = new(big.Float).Quo(bignum.NewFloat(1, 160), bignum.NewFloat(float64(i+3), 160))
+	}
+
+	maxErr := func(prec uint) *big.Float {
+
+		pt := NewPlaintext(params, params.MaxLevel())
+		require.NoError(t, ecd.AtPrecision(prec).Encode(values, pt))
+
+		have := make([]*big.Float, params.MaxSlots())
+		for i := range have {
+			have[i] = bignum.NewFloat(0, 160)
+		}
+		require.NoError(t, ecd.AtPrecision(prec).Decode(pt, have))
+
+		err := new(big.Float)
+		for i := range have {
+			if diff := new(big.Float).Sub(have[i], values[i]); diff.Abs(diff).Cmp(err) > 0 {
+				err = diff
+			}
+		}
+
+		return err
+	}
+
+	// At scale 2^{90}, a float64 internal arithmetic limits the accuracy to
+	// ~2^{-53} while a 128-bit internal precision recovers it.
+	require.True(t, maxErr(53).Cmp(bignum.NewFloat(math.Exp2(-60), 160)) > 0)
+	require.True(t, maxErr(128).Cmp(bignum.NewFloat(math.Exp2(-80), 160)) < 0)
+}
@@ -17,6 +17,7 @@ type Evaluator struct {
 	*Encoder
 	*evaluatorBuffers
 	*rlwe.Evaluator
+	rescaling RescalingStrategy
 }
 
 // NewEvaluator creates a new Evaluator, that can be used to do homomorphic
@@ -49,6 +50,16 @@ func (eval Evaluator) BuffQ() [3]ring.Poly {
 	return eval.buffQ
 }
 
+// MemoryFootprint returns the deep in-RAM size of the evaluator's own
+// buffers in bytes (see rlwe.Evaluator.MemoryFootprint).
+func (eval Evaluator) MemoryFootprint() (size int) {
+	size = eval.Evaluator.MemoryFootprint()
+	for i := range eval.buffQ {
+		size += eval.buffQ[i].MemoryFootprint()
+	}
+	return
+}
+
 func newEvaluatorBuffers(parameters Parameters) *evaluatorBuffers {
 	buff := new(evaluatorBuffers)
 	ringQ := parameters.RingQ()
@@ -77,7 +88,9 @@ func (eval Evaluator) Add(op0 *rlwe.Ciphertext, op1 rlwe.Operand, opOut *rlwe.Ci
 		opOut.Resize(degree, level)
 
 		// Generic inplace evaluation
-		eval.evaluateInPlace(level, op0, op1.El(), opOut, eval.GetParameters().RingQ().AtLevel(level).Add)
+		if err = eval.evaluateInPlace(level, op0, op1.El(), opOut, eval.GetParameters().RingQ().AtLevel(level).Add); err != nil {
+			return err
+		}
 
 	case complex128, float64, int, int64, uint, uint64, *big.Int, *big.Float, *bignum.Complex:
 
@@ -115,7 +128,7 @@ func (eval Evaluator) Add(op0 *rlwe.Ciphertext, op1 rlwe.Operand, opOut *rlwe.Ci
 		// Sanity check, this error should not happen unless the evaluator's buffers
 		// were improperly tempered with.
 		if err != nil {
-			panic(err)
+			return rlwe.Fault(err)
 		}
 
 		pt.MetaData = op0.MetaData // Sets the metadata, notably matches scales
@@ -126,7 +139,9 @@ func (eval Evaluator) Add(op0 *rlwe.Ciphertext, op1 rlwe.Operand, opOut *rlwe.Ci
 		}
 
 		// Generic in place evaluation
-		eval.evaluateInPlace(level, op0, pt.El(), opOut, eval.GetParameters().RingQ().AtLevel(level).Add)
+		if err = eval.evaluateInPlace(level, op0, pt.El(), opOut, eval.GetParameters().RingQ().AtLevel(level).Add); err != nil {
+			return err
+		}
 	default:
 		return fmt.Errorf("invalid op1.(type): must be rlwe.ElementInterface[ring.Poly], complex128, float64, int, int64, uint, uint64, *big.Int, *big.Float, *bignum.Complex, []complex128, []float64, []*big.Float or []*bignum.Complex, but is %T", op1)
 	}
@@ -167,7 +182,9 @@ func (eval Evaluator) Sub(op0 *rlwe.Ciphertext, op1 rlwe.Operand, opOut *rlwe.Ci
 		opOut.Resize(degree, level)
 
 		// Generic inplace evaluation
-		eval.evaluateInPlace(level, op0, op1.El(), opOut, eval.GetParameters().RingQ().AtLevel(level).Sub)
+		if err = eval.evaluateInPlace(level, op0, op1.El(), opOut, eval.GetParameters().RingQ().AtLevel(level).Sub); err != nil {
+			return err
+		}
 
 		// Negates high degree ciphertext coefficients if the degree of the second operand is larger than the first operand
 		if op0.Degree() < op1.Degree() {
@@ -211,7 +228,7 @@ func (eval Evaluator) Sub(op0 *rlwe.Ciphertext, op1 rlwe.Operand, opOut *rlwe.Ci
 		// Sanity check, this error should not happen unless the evaluator's buffers
 		// were improperly tempered with.
 		if err != nil {
-			panic(err)
+			return rlwe.Fault(err)
 		}
 
 		pt.MetaData = op0.MetaData
@@ -222,7 +239,9 @@ func (eval Evaluator) Sub(op0 *rlwe.Ciphertext, op1 rlwe.Operand, opOut *rlwe.Ci
 		}
 
 		// Generic inplace evaluation
-		eval.evaluateInPlace(level, op0, pt.El(), opOut, eval.GetParameters().RingQ().AtLevel(level).Sub)
+		if err = eval.evaluateInPlace(level, op0, pt.El(), opOut, eval.GetParameters().RingQ().AtLevel(level).Sub); err != nil {
+			return err
+		}
 
 	default:
 		return fmt.Errorf("invalid op1.(type): must be rlwe.ElementInterface[ring.Poly], complex128, float64, int, int64, uint, uint64, *big.Int, *big.Float, *bignum.Complex, []complex128, []float64, []*big.Float or []*bignum.Complex, but is %T", op1)
@@ -243,7 +262,7 @@ func (eval Evaluator) SubNew(op0 *rlwe.Ciphertext, op1 rlwe.Operand) (opOut *rlw
 	return opOut, eval.Sub(op0, op1, opOut)
 }
 
-func (eval Evaluator) evaluateInPlace(level int, c0 *rlwe.Ciphertext, c1 *rlwe.Element[ring.Poly], opOut *rlwe.Ciphertext, evaluate func(ring.Poly, ring.Poly, ring.Poly)) {
+func (eval Evaluator) evaluateInPlace(level int, c0 *rlwe.Ciphertext, c1 *rlwe.Element[ring.Poly], opOut *rlwe.Ciphertext, evaluate func(ring.Poly, ring.Poly, ring.Poly)) (err error) {
 
 	var tmp0, tmp1 *rlwe.Ciphertext
 
@@ -255,7 +274,6 @@ func (eval Evaluator) evaluateInPlace(level int, c0 *rlwe.Ciphertext, c1 *rlwe.E
 
 	cmp := c0.Scale.Cmp(c1.Scale)
 
-	var err error
 
 	// Checks whether or not the receiver element is the same as one of the input elements
 	// and acts accordingly to avoid unnecessary element creation or element overwriting,
@@ -275,7 +293,7 @@ func (eval Evaluator) evaluateInPlace(level int, c0 *rlwe.Ciphertext, c1 *rlwe.E
 				// Sanity check, this error should not happen unless the evaluator's buffers
 				// were improperly tempered with.
 				if err != nil {
-					panic(err)
+					return rlwe.Fault(err)
 				}
 				*tmp1.MetaData = *opOut.MetaData
 
@@ -338,7 +356,7 @@ func (eval Evaluator) evaluateInPlace(level int, c0 *rlwe.Ciphertext, c1 *rlwe.E
 				// Sanity check, this error should not happen unless the evaluator's buffers
 				// were improperly tempered with.
 				if err != nil {
-					panic(err)
+					return rlwe.Fault(err)
 				}
 				*tmp0.MetaData = *opOut.MetaData
 
@@ -368,7 +386,7 @@ func (eval Evaluator) evaluateInPlace(level int, c0 *rlwe.Ciphertext, c1 *rlwe.E
 				// Sanity check, this error should not happen unless the evaluator's buffers
 				// were improperly tempered with.
 				if err != nil {
-					panic(err)
+					return rlwe.Fault(err)
 				}
 				*tmp1.MetaData = *opOut.MetaData
 
@@ -392,7 +410,7 @@ func (eval Evaluator) evaluateInPlace(level int, c0 *rlwe.Ciphertext, c1 *rlwe.E
 				// Sanity check, this error should not happen unless the evaluator's buffers
 				// were improperly tempered with.
 				if err != nil {
-					panic(err)
+					return rlwe.Fault(err)
 				}
 				*tmp0.MetaData = *opOut.MetaData
 
@@ -428,6 +446,8 @@ func (eval Evaluator) evaluateInPlace(level int, c0 *rlwe.Ciphertext, c1 *rlwe.E
 			opOut.El().Value[i].CopyLvl(level, tmp1.Value[i])
 		}
 	}
+
+	return
 }
 
 func (eval Evaluator) evaluateWithScalar(level int, p0 []ring.Poly, RNSReal, RNSImag ring.RNSScalar, p1 []ring.Poly, evaluate func(ring.Poly, ring.RNSScalar, ring.RNSScalar, ring.Poly)) {
@@ -492,7 +512,8 @@ func (eval Evaluator) DropLevel(op0 *rlwe.Ciphertext, levels int) {
 }
 
 // Rescale divides op0 by the last prime of the moduli chain and repeats this procedure
-// params.LevelsConsumedPerRescaling() times.
+// params.LevelsConsumedPerRescaling() times, following the evaluator's RescalingStrategy
+// (rounded division by default, see WithRescalingStrategy).
 //
 // Returns an error if:
 //   - Either op0 or opOut MetaData are nil
@@ -511,6 +532,13 @@ func (eval Evaluator) Rescale(op0, opOut *rlwe.Ciphertext) (err error) {
 		return fmt.Errorf("cannot Rescale: input Ciphertext level is too low")
 	}
 
+	if eval.rescaling == RescalingExact && op0.Level() <= 2*nbRescales-1 {
+		return fmt.Errorf("cannot Rescale: exact rescaling consumes %d levels but the input Ciphertext level is %d", 2*nbRescales, op0.Level())
+	}
+
+	// The scale the exact strategy adjusts to after the division.
+	target := op0.Scale.Div(params.DefaultScale())
+
 	if op0 != opOut {
 		opOut.Resize(op0.Degree(), op0.Level()-nbRescales)
 	}
@@ -524,13 +552,23 @@ func (eval Evaluator) Rescale(op0, opOut *rlwe.Ciphertext) (err error) {
 	}
 
 	for i := range opOut.Value {
-		ringQ.DivRoundByLastModulusManyNTT(nbRescales, op0.Value[i], eval.buffQ[0], opOut.Value[i])
+		if eval.rescaling == RescalingFlooring {
+			ringQ.DivFloorByLastModulusManyNTT(nbRescales, op0.Value[i], eval.buffQ[0], opOut.Value[i])
+		} else {
+			ringQ.DivRoundByLastModulusManyNTT(nbRescales, op0.Value[i], eval.buffQ[0], opOut.Value[i])
+		}
 	}
 
 	if op0 == opOut {
 		opOut.Resize(op0.Degree(), op0.Level()-nbRescales)
 	}
 
+	if eval.rescaling == RescalingExact {
+		if err = eval.SetScale(opOut, target); err != nil {
+			return fmt.Errorf("cannot Rescale: %w", err)
+		}
+	}
+
 	return
 }
 
@@ -700,7 +738,7 @@ func (eval Evaluator) Mul(op0 *rlwe.Ciphertext, op1 rlwe.Operand, opOut *rlwe.Ci
 		// Sanity check, this error should not happen unless the evaluator's buffers
 		// were improperly tempered with.
 		if err != nil {
-			panic(err)
+			return rlwe.Fault(err)
 		}
 
 		*pt.MetaData = *op0.MetaData
@@ -1024,7 +1062,7 @@ func (eval Evaluator) MulThenAdd(op0 *rlwe.Ciphertext, op1 rlwe.Operand, opOut *
 		// Sanity check, this error should not happen unless the evaluator's buffers
 		// were improperly tempered with.
 		if err != nil {
-			panic(err)
+			return rlwe.Fault(err)
 		}
 		pt.MetaData = op0.MetaData.CopyNew()
 		pt.Scale = scaleRLWE
@@ -1215,12 +1253,21 @@ func (eval Evaluator) ConjugateNew(op0 *rlwe.Ciphertext) (opOut *rlwe.Ciphertext
 
 // Conjugate conjugates op0 (which is equivalent to a row rotation) and returns the result in opOut.
 // The method will return an error if the evaluator hasn't been given an evaluation key set with the appropriate GaloisKey.
+// Coefficient-encoded (non-batched) plaintexts are real-valued, so on them the conjugation is the
+// identity and is applied without a Galois key.
 func (eval Evaluator) Conjugate(op0 *rlwe.Ciphertext, opOut *rlwe.Ciphertext) (err error) {
 
 	if eval.GetParameters().RingType() == ring.ConjugateInvariant {
 		return fmt.Errorf("cannot Conjugate: method is not supported when parameters.RingType() == ring.ConjugateInvariant")
 	}
 
+	if !op0.IsBatched {
+		if op0 != opOut {
+			opOut.Copy(op0)
+		}
+		return
+	}
+
 	if err = eval.Automorphism(op0, eval.GetParameters().GaloisElementOrderTwoOrthogonalSubgroup(), opOut); err != nil {
 		return fmt.Errorf("cannot Conjugate: %w", err)
 	}
@@ -1276,6 +1323,7 @@ func (eval Evaluator) ShallowCopy() *Evaluator {
 		Encoder:          eval.Encoder.ShallowCopy(),
 		Evaluator:        eval.Evaluator.ShallowCopy(),
 		evaluatorBuffers: newEvaluatorBuffers(*eval.GetParameters()),
+		rescaling:        eval.rescaling,
 	}
 }
 
@@ -1286,5 +1334,6 @@ func (eval Evaluator) WithKey(evk rlwe.EvaluationKeySet) *Evaluator {
 		Evaluator:        eval.Evaluator.WithKey(evk),
 		Encoder:          eval.Encoder,
 		evaluatorBuffers: eval.evaluatorBuffers,
+		rescaling:        eval.rescaling,
 	}
 }
@@ -0,0 +1,72 @@
+package ckks
+
+import (
+	"fmt"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+)
+
+// MulThenAddAccumulator is a buffer accumulating ciphertext-plaintext
+// products at the raised intermediate scale of MulThenAdd, deferring the
+// normalization to a single call to Evaluator.FinalizeMulThenAdd. In
+// dot-product-heavy circuits summing many plaintext products (e.g. encrypted
+// embeddings similarity search), this replaces one rescale per product by a
+// single rescale per sum.
+type MulThenAddAccumulator struct {
+	// Value is the accumulated sum, at the raised scale.
+	Value *rlwe.Ciphertext
+
+	empty bool
+}
+
+// NewMulThenAddAccumulator allocates a zeroed MulThenAddAccumulator at the
+// given level, which must match the level of the ciphertexts that will be
+// accumulated. The scale of the accumulator is set from the first
+// accumulated operand.
+func NewMulThenAddAccumulator(params Parameters, level int) *MulThenAddAccumulator {
+	return &MulThenAddAccumulator{
+		Value: NewCiphertext(params, 1, level),
+		empty: true,
+	}
+}
+
+// Reset zeroes the accumulator, enabling its reuse for a new sum.
+func (acc *MulThenAddAccumulator) Reset() {
+	for i := range acc.Value.Value {
+		acc.Value.Value[i].Zero()
+	}
+	acc.empty = true
+}
+
+// MulThenAddLazy multiplies op0 with op1 and adds the product on acc at the
+// raised scale op0.Scale * Q[level], without rescaling. The accumulated sum
+// is normalized back to the scale of the operands with
+// Evaluator.FinalizeMulThenAdd.
+//
+// The same types are accepted for op1 as for MulThenAdd, and all the
+// accumulated op0 must share the scale of the first one.
+func (eval Evaluator) MulThenAddLazy(op0 *rlwe.Ciphertext, op1 rlwe.Operand, acc *MulThenAddAccumulator) (err error) {
+
+	if acc.empty {
+		acc.Value.Scale = op0.Scale
+		acc.empty = false
+	}
+
+	if err = eval.MulThenAdd(op0, op1, acc.Value); err != nil {
+		return fmt.Errorf("cannot MulThenAddLazy: %w", err)
+	}
+
+	return
+}
+
+// FinalizeMulThenAdd normalizes the sum accumulated on acc with a single
+// rescale and writes the result on opOut. The accumulator is left untouched,
+// so the sum can be extended with further calls to MulThenAddLazy.
+func (eval Evaluator) FinalizeMulThenAdd(acc *MulThenAddAccumulator, opOut *rlwe.Ciphertext) (err error) {
+
+	if err = eval.Rescale(acc.Value, opOut); err != nil {
+		return fmt.Errorf("cannot FinalizeMulThenAdd: %w", err)
+	}
+
+	return
+}
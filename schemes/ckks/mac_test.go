@@ -0,0 +1,85 @@
+package ckks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+)
+
+func TestMulThenAddAccumulator(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN:            10,
+		LogQ:            []int{55, 45, 45},
+		LogP:            []int{60},
+		LogDefaultScale: 45,
+	})
+	require.NoError(t, err)
+
+	kgen := rlwe.NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+
+	ecd := NewEncoder(params)
+	enc := rlwe.NewEncryptor(params, sk)
+	dec := rlwe.NewDecryptor(params, sk)
+	eval := NewEvaluator(params, nil)
+
+	slots := params.MaxSlots()
+	n := 8
+
+	values := make([][]float64, n)
+	weights := make([][]float64, n)
+	cts := make([]*rlwe.Ciphertext, n)
+
+	for i := range values {
+
+		values[i] = make([]float64, slots)
+		weights[i] = make([]float64, slots)
+		for j := range values[i] {
+			values[i][j] = float64((i+1)*(j%17)) / float64(17*n)
+			weights[i][j] = float64((i+2)*(j%13)) / float64(13*n)
+		}
+
+		pt := NewPlaintext(params, params.MaxLevel())
+		require.NoError(t, ecd.Encode(values[i], pt))
+		cts[i], err = enc.EncryptNew(pt)
+		require.NoError(t, err)
+	}
+
+	acc := NewMulThenAddAccumulator(params, params.MaxLevel())
+	for i := range cts {
+		require.NoError(t, eval.MulThenAddLazy(cts[i], weights[i], acc))
+	}
+
+	res := NewCiphertext(params, 1, params.MaxLevel())
+	require.NoError(t, eval.FinalizeMulThenAdd(acc, res))
+
+	// A single rescale normalizes the whole sum.
+	require.Equal(t, params.MaxLevel()-params.LevelsConsumedPerRescaling(), res.Level())
+
+	want := make([]float64, slots)
+	for i := range cts {
+		for j := range want {
+			want[j] += values[i][j] * weights[i][j]
+		}
+	}
+
+	have := make([]float64, slots)
+	require.NoError(t, ecd.Decode(dec.DecryptNew(res), have))
+	for j := range want {
+		require.InDelta(t, want[j], have[j], 1e-8)
+	}
+
+	// The accumulator is reusable after a Reset.
+	acc.Reset()
+	require.NoError(t, eval.MulThenAddLazy(cts[0], weights[0], acc))
+	require.NoError(t, eval.FinalizeMulThenAdd(acc, res))
+
+	require.NoError(t, ecd.Decode(dec.DecryptNew(res), have))
+	for j := range want {
+		require.InDelta(t, values[0][j]*weights[0][j], have[j], 1e-8)
+	}
+}
@@ -0,0 +1,153 @@
+package ckks
+
+import (
+	"fmt"
+	"math/bits"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/ring"
+	"github.com/luxdefi/lattice/v5/utils"
+)
+
+// smallNormBits is the largest bit-size of a scalar for which the binary
+// double-and-add evaluation over ring additions is used; above it, the
+// generic RNS scalar multiplication is cheaper and the methods of this
+// file fall back to it.
+const smallNormBits = 16
+
+// MulIntegerNew multiplies op0 by a signed integer and returns the result
+// in a newly created element (see MulInteger).
+func (eval Evaluator) MulIntegerNew(op0 *rlwe.Ciphertext, scalar int64) (opOut *rlwe.Ciphertext, err error) {
+	opOut = NewCiphertext(*eval.GetParameters(), op0.Degree(), op0.Level())
+	return opOut, eval.MulInteger(op0, scalar, opOut)
+}
+
+// MulInteger multiplies op0 by a signed integer and returns the result in
+// opOut, with opOut.Scale = op0.Scale (like the generic integer path, no
+// rescaling is required afterwards).
+//
+// Scalars of norm at most 2^16 are evaluated by binary double-and-add over
+// ring additions, skipping the RNS decomposition and the per-coefficient
+// Montgomery multiplications of the generic scalar path, which is
+// measurably faster for the small weights of quantized models (e.g. int8);
+// larger scalars fall back to Evaluator.Mul.
+func (eval Evaluator) MulInteger(op0 *rlwe.Ciphertext, scalar int64, opOut *rlwe.Ciphertext) (err error) {
+
+	k, neg := uint64(scalar), false
+	if scalar < 0 {
+		k, neg = uint64(-scalar), true
+	}
+
+	if bits.Len64(k) > smallNormBits {
+		if err = eval.Mul(op0, scalar, opOut); err != nil {
+			return fmt.Errorf("cannot MulInteger: %w", err)
+		}
+		return
+	}
+
+	_, level, err := eval.InitOutputUnaryOp(op0.El(), opOut.El())
+	if err != nil {
+		return fmt.Errorf("cannot MulInteger: %w", err)
+	}
+
+	opOut.Resize(op0.Degree(), level)
+
+	ringQ := eval.GetParameters().RingQ().AtLevel(level)
+
+	for i := range opOut.Value {
+
+		if k == 0 {
+			opOut.Value[i].Zero()
+			continue
+		}
+
+		p0 := op0.Value[i]
+		if utils.Alias1D(p0.Coeffs[0], opOut.Value[i].Coeffs[0]) {
+			eval.buffQ[0].CopyLvl(level, p0)
+			p0 = eval.buffQ[0]
+		}
+
+		mulSmallNorm(ringQ, level, k, p0, opOut.Value[i])
+
+		if neg {
+			ringQ.Neg(opOut.Value[i], opOut.Value[i])
+		}
+	}
+
+	opOut.Scale = op0.Scale
+
+	return
+}
+
+// MulIntegerThenAdd multiplies op0 by a signed integer and adds the result
+// on opOut (see MulInteger), the usual inner loop of an encrypted dot
+// product with quantized plaintext weights.
+//
+// The procedure will return an error if:
+//   - op0.Scale != opOut.Scale.
+//   - opOut = op0.
+func (eval Evaluator) MulIntegerThenAdd(op0 *rlwe.Ciphertext, scalar int64, opOut *rlwe.Ciphertext) (err error) {
+
+	k, neg := uint64(scalar), false
+	if scalar < 0 {
+		k, neg = uint64(-scalar), true
+	}
+
+	if bits.Len64(k) > smallNormBits {
+		if err = eval.MulThenAdd(op0, scalar, opOut); err != nil {
+			return fmt.Errorf("cannot MulIntegerThenAdd: %w", err)
+		}
+		return
+	}
+
+	_, level, err := eval.InitOutputUnaryOp(op0.El(), opOut.El())
+	if err != nil {
+		return fmt.Errorf("cannot MulIntegerThenAdd: %w", err)
+	}
+
+	if op0.El() == opOut.El() {
+		return fmt.Errorf("cannot MulIntegerThenAdd: opOut must be different from op0")
+	}
+
+	if op0.Scale.Cmp(opOut.Scale) != 0 {
+		return fmt.Errorf("cannot MulIntegerThenAdd: op0.Scale != opOut.Scale is not supported")
+	}
+
+	if k == 0 {
+		return
+	}
+
+	opOut.Resize(op0.Degree(), opOut.Level())
+
+	ringQ := eval.GetParameters().RingQ().AtLevel(level)
+
+	for i := range op0.Value {
+
+		mulSmallNorm(ringQ, level, k, op0.Value[i], eval.buffQ[0])
+
+		if neg {
+			ringQ.Sub(opOut.Value[i], eval.buffQ[0], opOut.Value[i])
+		} else {
+			ringQ.Add(opOut.Value[i], eval.buffQ[0], opOut.Value[i])
+		}
+	}
+
+	return
+}
+
+// mulSmallNorm evaluates pOut = k * p0 by binary double-and-add over ring
+// additions, from the most significant bit of k (absorbed by the initial
+// copy) down. Expects k >= 1 and p0 not aliased with pOut.
+func mulSmallNorm(ringQ *ring.Ring, level int, k uint64, p0, pOut ring.Poly) {
+
+	pOut.CopyLvl(level, p0)
+
+	for i := bits.Len64(k) - 2; i >= 0; i-- {
+
+		ringQ.Add(pOut, pOut, pOut)
+
+		if (k>>i)&1 == 1 {
+			ringQ.Add(pOut, p0, pOut)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package ckks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+)
+
+func TestMulInteger(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN:            10,
+		LogQ:            []int{60, 45, 45},
+		LogP:            []int{60},
+		LogDefaultScale: 45,
+	})
+	require.NoError(t, err)
+
+	kgen := rlwe.NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+
+	ecd := NewEncoder(params)
+	enc := rlwe.NewEncryptor(params, sk)
+	dec := rlwe.NewDecryptor(params, sk)
+
+	eval := NewEvaluator(params, nil)
+
+	slots := params.MaxSlots()
+
+	values := make([]float64, slots)
+	for j := range values {
+		values[j] = float64(j%31)/31 - 0.5
+	}
+
+	encrypt := func() *rlwe.Ciphertext {
+		pt := NewPlaintext(params, params.MaxLevel())
+		require.NoError(t, ecd.Encode(values, pt))
+		ct, err := enc.EncryptNew(pt)
+		require.NoError(t, err)
+		return ct
+	}
+
+	verify := func(ct *rlwe.Ciphertext, want func(x float64) float64) {
+		have := make([]float64, slots)
+		require.NoError(t, ecd.Decode(dec.DecryptNew(ct), have))
+		for j := range values {
+			require.InDelta(t, want(values[j]), have[j], 1e-8)
+		}
+	}
+
+	t.Run("MulIntegerNew", func(t *testing.T) {
+
+		ct := encrypt()
+
+		for _, scalar := range []int64{7, -3, 1, 0, 127, -128} {
+
+			ctOut, err := eval.MulIntegerNew(ct, scalar)
+			require.NoError(t, err)
+
+			// No scale consumed: the result does not require a rescaling.
+			require.Equal(t, 0, ct.Scale.Cmp(ctOut.Scale))
+
+			verify(ctOut, func(x float64) float64 { return float64(scalar) * x })
+		}
+	})
+
+	t.Run("MulIntegerInPlace", func(t *testing.T) {
+
+		ct := encrypt()
+
+		require.NoError(t, eval.MulInteger(ct, 5, ct))
+		require.NoError(t, eval.MulInteger(ct, -5, ct))
+
+		verify(ct, func(x float64) float64 { return -25 * x })
+	})
+
+	t.Run("MulIntegerFallback", func(t *testing.T) {
+
+		ct := encrypt()
+
+		// Above the small-norm bound, delegates to the generic path. The
+		// encryption noise is amplified by the scalar, hence the tolerance.
+		require.NoError(t, eval.MulInteger(ct, 1<<20, ct))
+
+		have := make([]float64, slots)
+		require.NoError(t, ecd.Decode(dec.DecryptNew(ct), have))
+		for j := range values {
+			require.InDelta(t, float64(int64(1)<<20)*values[j], have[j], 1e-2)
+		}
+	})
+
+	t.Run("MulIntegerThenAdd", func(t *testing.T) {
+
+		ct := encrypt()
+
+		// Encrypted dot product with int8 weights.
+		weights := []int64{13, -7, 100}
+
+		acc, err := eval.MulIntegerNew(ct, weights[0])
+		require.NoError(t, err)
+		for _, w := range weights[1:] {
+			require.NoError(t, eval.MulIntegerThenAdd(ct, w, acc))
+		}
+
+		verify(acc, func(x float64) float64 { return (13 - 7 + 100) * x })
+
+		require.Error(t, eval.MulIntegerThenAdd(ct, 1, ct))
+
+		ctHighScale := encrypt()
+		ctHighScale.Scale = ctHighScale.Scale.Mul(rlwe.NewScale(2))
+		require.Error(t, eval.MulIntegerThenAdd(ctHighScale, 1, acc))
+	})
+}
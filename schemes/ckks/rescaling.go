@@ -0,0 +1,51 @@
+package ckks
+
+import (
+	"fmt"
+)
+
+// RescalingStrategy selects how Evaluator.Rescale divides a ciphertext by the
+// last moduli of the chain, i.e. how the rounding error introduced at each
+// level drop is traded against the cost of the operation. The strategy is
+// selected per circuit with Evaluator.WithRescalingStrategy.
+type RescalingStrategy uint8
+
+const (
+	// RescalingRounded is the default strategy: the coefficients are divided
+	// by the last moduli with rounding, for an unbiased error of at most 1/2
+	// per coefficient, and the ciphertext scale is divided by the exact value
+	// of the moduli.
+	RescalingRounded RescalingStrategy = iota
+	// RescalingFlooring divides the coefficients by the last moduli with
+	// flooring, saving the rounding pass at the cost of an error in [0, 1)
+	// per coefficient.
+	RescalingFlooring
+	// RescalingExact performs the rounded division and then adjusts the
+	// ciphertext so that its scale is exactly the input scale divided by the
+	// default scaling factor, instead of by the moduli approximating it. The
+	// adjustment multiplies by the rational correction and consumes a second
+	// rescaling, i.e. Rescale consumes 2*params.LevelsConsumedPerRescaling()
+	// levels, but the scale does not drift away from the default scaling
+	// factor over the course of a deep circuit.
+	RescalingExact
+)
+
+// WithRescalingStrategy creates a shallow copy of the receiver Evaluator for
+// which Rescale follows the given strategy and where the temporary buffers
+// are shared. The receiver and the returned Evaluators cannot be used
+// concurrently.
+func (eval Evaluator) WithRescalingStrategy(strategy RescalingStrategy) *Evaluator {
+	switch strategy {
+	case RescalingRounded, RescalingFlooring, RescalingExact:
+	default:
+		// Sanity check, this error should not happen.
+		panic(fmt.Errorf("invalid RescalingStrategy: %d", strategy))
+	}
+	eval.rescaling = strategy
+	return &eval
+}
+
+// RescalingStrategy returns the strategy followed by Rescale.
+func (eval Evaluator) RescalingStrategy() RescalingStrategy {
+	return eval.rescaling
+}
@@ -0,0 +1,94 @@
+package ckks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+)
+
+func TestRescalingStrategy(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN:            10,
+		LogQ:            []int{55, 45, 45, 45},
+		LogP:            []int{60},
+		LogDefaultScale: 45,
+	})
+	require.NoError(t, err)
+
+	kgen := rlwe.NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+
+	ecd := NewEncoder(params)
+	enc := rlwe.NewEncryptor(params, sk)
+	dec := rlwe.NewDecryptor(params, sk)
+	eval := NewEvaluator(params, nil)
+
+	require.Equal(t, RescalingRounded, eval.RescalingStrategy())
+
+	values := make([]float64, params.MaxSlots())
+	for i := range values {
+		values[i] = float64(i) / float64(len(values))
+	}
+
+	pt := NewPlaintext(params, params.MaxLevel())
+	require.NoError(t, ecd.Encode(values, pt))
+	ct, err := enc.EncryptNew(pt)
+	require.NoError(t, err)
+
+	want := make([]float64, len(values))
+	for i := range want {
+		want[i] = values[i] * values[i]
+	}
+
+	have := make([]float64, len(values))
+
+	// The flooring and rounding divisions agree up to the scheme's precision.
+	for _, strategy := range []RescalingStrategy{RescalingRounded, RescalingFlooring} {
+
+		eval := eval.WithRescalingStrategy(strategy)
+		require.Equal(t, strategy, eval.RescalingStrategy())
+
+		res := NewCiphertext(params, 1, ct.Level())
+		require.NoError(t, eval.Mul(ct, pt, res))
+		require.NoError(t, eval.Rescale(res, res))
+		require.Equal(t, params.MaxLevel()-params.LevelsConsumedPerRescaling(), res.Level())
+
+		require.NoError(t, ecd.Decode(dec.DecryptNew(res), have))
+		for i := 0; i < 16; i++ {
+			require.InDelta(t, want[i], have[i], 1e-8)
+		}
+	}
+
+	// The exact strategy consumes a second rescaling but lands exactly on the
+	// default scaling factor.
+	{
+		eval := eval.WithRescalingStrategy(RescalingExact)
+
+		res := NewCiphertext(params, 1, ct.Level())
+		require.NoError(t, eval.Mul(ct, pt, res))
+		require.NoError(t, eval.Rescale(res, res))
+		require.Equal(t, params.MaxLevel()-2*params.LevelsConsumedPerRescaling(), res.Level())
+		require.True(t, res.Scale.Equal(params.DefaultScale()))
+
+		require.NoError(t, ecd.Decode(dec.DecryptNew(res), have))
+		for i := 0; i < 16; i++ {
+			require.InDelta(t, want[i], have[i], 1e-8)
+		}
+
+		// The default strategies leave the scale at the exact rational value.
+		res2 := NewCiphertext(params, 1, ct.Level())
+		require.NoError(t, NewEvaluator(params, nil).Mul(ct, pt, res2))
+		require.NoError(t, NewEvaluator(params, nil).Rescale(res2, res2))
+		require.False(t, res2.Scale.Equal(params.DefaultScale()))
+	}
+
+	// The strategy is propagated by ShallowCopy and WithKey.
+	require.Equal(t, RescalingFlooring, eval.WithRescalingStrategy(RescalingFlooring).ShallowCopy().RescalingStrategy())
+	require.Equal(t, RescalingFlooring, eval.WithRescalingStrategy(RescalingFlooring).WithKey(rlwe.NewMemEvaluationKeySet(nil)).RescalingStrategy())
+
+	require.Panics(t, func() { eval.WithRescalingStrategy(RescalingStrategy(255)) })
+}
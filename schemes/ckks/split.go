@@ -0,0 +1,126 @@
+package ckks
+
+import (
+	"fmt"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+)
+
+// SplitNew splits op0 into n shares, the i-th share being op0 rotated by
+// i*(slots/n) positions, i.e. holding the i-th range of slots/n contiguous
+// slots of op0 in its leading slots. Each share can then be evaluated
+// independently on its leading slots/n slots, e.g. by a worker goroutine or a
+// remote machine, and the results reassembled with RecombineNew. The
+// rotations share a single decomposition of op0 (see RotateHoisted).
+//
+// The method returns an error if n does not divide the number of slots of op0
+// or if the evaluator was not provided the Galois keys for the rotations (see
+// GaloisElementsForSplit).
+func (eval Evaluator) SplitNew(op0 *rlwe.Ciphertext, n int) (opOut []*rlwe.Ciphertext, err error) {
+
+	slots := op0.Slots()
+
+	if n < 1 || slots%n != 0 {
+		return nil, fmt.Errorf("cannot SplitNew: n=%d must divide the number of slots %d", n, slots)
+	}
+
+	chunk := slots / n
+
+	rotations := make([]int, n-1)
+	for i := 1; i < n; i++ {
+		rotations[i-1] = i * chunk
+	}
+
+	cts, err := eval.RotateHoistedNew(op0, rotations)
+	if err != nil {
+		return nil, fmt.Errorf("cannot SplitNew: %w", err)
+	}
+
+	opOut = make([]*rlwe.Ciphertext, n)
+	opOut[0] = op0.CopyNew()
+	for i := 1; i < n; i++ {
+		opOut[i] = cts[i*chunk]
+	}
+
+	return
+}
+
+// RecombineNew reassembles the shares produced by SplitNew, after they have
+// been independently evaluated, into a single ciphertext: the leading
+// slots/n slots of the i-th share are masked and rotated back to the i-th
+// slot range. The shares are first aligned in place with
+// AlignScalesAndLevels, and the masking consumes
+// params.LevelsConsumedPerRescaling() levels, the output being rescaled back
+// to the aligned scale.
+//
+// The method returns an error if the evaluator was not provided the Galois
+// keys for the rotations (see GaloisElementsForSplit).
+func (eval Evaluator) RecombineNew(cts []*rlwe.Ciphertext) (opOut *rlwe.Ciphertext, err error) {
+
+	n := len(cts)
+
+	if n == 0 {
+		return nil, fmt.Errorf("cannot RecombineNew: empty list of shares")
+	}
+
+	if n == 1 {
+		return cts[0].CopyNew(), nil
+	}
+
+	slots := cts[0].Slots()
+
+	if slots%n != 0 {
+		return nil, fmt.Errorf("cannot RecombineNew: n=%d must divide the number of slots %d", n, slots)
+	}
+
+	chunk := slots / n
+
+	if err = eval.AlignScalesAndLevels(cts...); err != nil {
+		return nil, fmt.Errorf("cannot RecombineNew: %w", err)
+	}
+
+	// The same mask selects the leading chunk of every share, the rotation
+	// back to the i-th slot range being applied after the masking.
+	mask := make([]float64, chunk)
+	for i := range mask {
+		mask[i] = 1
+	}
+
+	for i := 0; i < n; i++ {
+
+		var tmp *rlwe.Ciphertext
+		if tmp, err = eval.MulNew(cts[i], mask); err != nil {
+			return nil, fmt.Errorf("cannot RecombineNew: %w", err)
+		}
+
+		if i > 0 {
+			if err = eval.Rotate(tmp, -i*chunk, tmp); err != nil {
+				return nil, fmt.Errorf("cannot RecombineNew: %w", err)
+			}
+		}
+
+		if opOut == nil {
+			opOut = tmp
+		} else if err = eval.Add(opOut, tmp, opOut); err != nil {
+			return nil, fmt.Errorf("cannot RecombineNew: %w", err)
+		}
+	}
+
+	if err = eval.Rescale(opOut, opOut); err != nil {
+		return nil, fmt.Errorf("cannot RecombineNew: %w", err)
+	}
+
+	return
+}
+
+// GaloisElementsForSplit returns the list of Galois elements required by
+// SplitNew and RecombineNew to split a ciphertext of the given number of
+// slots into n shares.
+func (p Parameters) GaloisElementsForSplit(slots, n int) (galEls []uint64) {
+	chunk := slots / n
+	galEls = make([]uint64, 0, 2*(n-1))
+	for i := 1; i < n; i++ {
+		galEls = append(galEls, p.GaloisElement(i*chunk), p.GaloisElement(-i*chunk))
+	}
+	return
+}
@@ -0,0 +1,82 @@
+package ckks
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+)
+
+func TestSplitRecombine(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := NewParametersFromLiteral(ParametersLiteral{
+		LogN:            10,
+		LogQ:            []int{55, 45, 45, 45},
+		LogP:            []int{60},
+		LogDefaultScale: 45,
+	})
+	require.NoError(t, err)
+
+	kgen := rlwe.NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+
+	ecd := NewEncoder(params)
+	enc := rlwe.NewEncryptor(params, sk)
+	dec := rlwe.NewDecryptor(params, sk)
+
+	slots := params.MaxSlots()
+	n := 4
+
+	galEls := params.GaloisElementsForSplit(slots, n)
+	evk := rlwe.NewMemEvaluationKeySet(kgen.GenRelinearizationKeyNew(sk), kgen.GenGaloisKeysNew(galEls, sk)...)
+	eval := NewEvaluator(params, evk)
+
+	values := make([]float64, slots)
+	for i := range values {
+		values[i] = float64(i) / float64(slots)
+	}
+
+	pt := NewPlaintext(params, params.MaxLevel())
+	require.NoError(t, ecd.Encode(values, pt))
+	ct, err := enc.EncryptNew(pt)
+	require.NoError(t, err)
+
+	shares, err := eval.SplitNew(ct, n)
+	require.NoError(t, err)
+	require.Len(t, shares, n)
+
+	// Each worker squares the leading slots/n slots of its share.
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int, eval *Evaluator) {
+			defer wg.Done()
+			if errs[i] = eval.MulRelin(shares[i], shares[i], shares[i]); errs[i] != nil {
+				return
+			}
+			errs[i] = eval.Rescale(shares[i], shares[i])
+		}(i, eval.ShallowCopy())
+	}
+	wg.Wait()
+	for i := range errs {
+		require.NoError(t, errs[i])
+	}
+
+	res, err := eval.RecombineNew(shares)
+	require.NoError(t, err)
+
+	want := make([]float64, slots)
+	for i := range want {
+		want[i] = values[i] * values[i]
+	}
+
+	have := make([]float64, slots)
+	require.NoError(t, ecd.Decode(dec.DecryptNew(res), have))
+	for i := range want {
+		require.InDelta(t, want[i], have[i], 1e-8)
+	}
+}
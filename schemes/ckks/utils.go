@@ -129,26 +129,68 @@ func StandardDeviation(vec interface{}, scale rlwe.Scale) (std float64) {
 // The real part is put in a left N/2 coefficient and the imaginary in the right N/2 coefficients.
 func Complex128ToFixedPointCRT(r *ring.Ring, values []complex128, scale float64, coeffs [][]uint64) {
 
-	for i, v := range values {
-		SingleFloat64ToFixedPointCRT(r, i, real(v), scale, coeffs)
-	}
-
 	var start int
 	if r.Type() == ring.Standard {
-		slots := len(values)
+		start = 2 * len(values)
+	} else {
+		start = len(values)
+	}
+
+	var fitsSingleWord = true
+	for _, v := range values {
+		if math.Abs(real(v))*scale >= 1.8446744073709552e+19 || math.Abs(imag(v))*scale >= 1.8446744073709552e+19 {
+			fitsSingleWord = false
+			break
+		}
+	}
+
+	if !fitsSingleWord {
+		// Values too large for the single-word conversion take the
+		// arbitrary-precision path, coefficient by coefficient.
 		for i, v := range values {
-			SingleFloat64ToFixedPointCRT(r, i+slots, imag(v), scale, coeffs)
+			SingleFloat64ToFixedPointCRT(r, i, real(v), scale, coeffs)
 		}
 
-		start = 2 * len(values)
+		if r.Type() == ring.Standard {
+			slots := len(values)
+			for i, v := range values {
+				SingleFloat64ToFixedPointCRT(r, i+slots, imag(v), scale, coeffs)
+			}
+		}
 
 	} else {
-		start = len(values)
+
+		moduli := r.ModuliChain()[:r.Level()+1]
+		brc := r.BRedConstants()
+		slots := len(values)
+
+		// One pass per modulus: the modulus, its Barrett constants and the
+		// destination row are hoisted out of the coefficient loop, which
+		// then writes each row of the CRT polynomial contiguously.
+		if r.Type() == ring.Standard {
+			for j, qi := range moduli {
+				row, b := coeffs[j], brc[j]
+				for i, v := range values {
+					row[i] = float64ToFixedPointCoeff(real(v), scale, qi, b)
+					row[i+slots] = float64ToFixedPointCoeff(imag(v), scale, qi, b)
+				}
+			}
+		} else {
+			for j, qi := range moduli {
+				row, b := coeffs[j], brc[j]
+				for i, v := range values {
+					row[i] = float64ToFixedPointCoeff(real(v), scale, qi, b)
+				}
+			}
+		}
 	}
 
 	end := len(coeffs[0])
-	for i := start; i < end; i++ {
-		SingleFloat64ToFixedPointCRT(r, i, 0, 0, coeffs)
+	for j := range coeffs {
+		row := coeffs[j]
+		for i := start; i < end; i++ {
+			row[i] = 0
+		}
 	}
 }
 
@@ -158,13 +200,67 @@ func Float64ToFixedPointCRT(r *ring.Ring, values []float64, scale float64, coeff
 	start := len(values)
 	end := len(coeffs[0])
 
-	for i := 0; i < start; i++ {
-		SingleFloat64ToFixedPointCRT(r, i, values[i], scale, coeffs)
+	var fitsSingleWord = true
+	for _, v := range values {
+		if math.Abs(v)*scale >= 1.8446744073709552e+19 {
+			fitsSingleWord = false
+			break
+		}
+	}
+
+	if !fitsSingleWord {
+		// Values too large for the single-word conversion take the
+		// arbitrary-precision path, coefficient by coefficient.
+		for i := 0; i < start; i++ {
+			SingleFloat64ToFixedPointCRT(r, i, values[i], scale, coeffs)
+		}
+
+	} else {
+
+		moduli := r.ModuliChain()[:r.Level()+1]
+		brc := r.BRedConstants()
+
+		// One pass per modulus: the modulus, its Barrett constants and the
+		// destination row are hoisted out of the coefficient loop, which
+		// then writes each row of the CRT polynomial contiguously.
+		for j, qi := range moduli {
+			row, b := coeffs[j], brc[j]
+			for i, v := range values {
+				row[i] = float64ToFixedPointCoeff(v, scale, qi, b)
+			}
+		}
+	}
+
+	for j := range coeffs {
+		row := coeffs[j]
+		for i := start; i < end; i++ {
+			row[i] = 0
+		}
+	}
+}
+
+// float64ToFixedPointCoeff encodes a single float64 modulo qi, with the same
+// rounding and lazy reduction as SingleFloat64ToFixedPointCRT. The caller must
+// ensure that |value*scale| < 2^64.
+func float64ToFixedPointCoeff(value, scale float64, qi uint64, brc []uint64) uint64 {
+
+	if value == 0 {
+		return 0
+	}
+
+	if value < 0 {
+		c := uint64(value*-scale + 0.5)
+		if c > qi {
+			return qi - ring.BRedAdd(c, qi, brc)
+		}
+		return qi - c
 	}
 
-	for i := start; i < end; i++ {
-		SingleFloat64ToFixedPointCRT(r, i, 0, 0, coeffs)
+	c := uint64(value*scale + 0.5)
+	if c > 0x1fffffffffffffff {
+		return ring.BRedAdd(c, qi, brc)
 	}
+	return c
 }
 
 // SingleFloat64ToFixedPointCRT encodes a single float64 on a CRT polynomialon in the i-th coefficient.
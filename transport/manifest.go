@@ -0,0 +1,184 @@
+package transport
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/utils"
+)
+
+// Signer signs a message with a caller-provided signature scheme (e.g.,
+// ed25519 or an HSM-backed signer).
+type Signer interface {
+	Sign(message []byte) (signature []byte, err error)
+}
+
+// Verifier verifies a signature produced by the matching Signer. It must
+// return a non-nil error if the signature is invalid.
+type Verifier interface {
+	Verify(message, signature []byte) error
+}
+
+// Fingerprint returns the SHA-256 digest of the serialization of the given
+// object (parameters, keys, ciphertexts, ...).
+func Fingerprint(obj encoding.BinaryMarshaler) (digest [32]byte, err error) {
+	p, err := obj.MarshalBinary()
+	if err != nil {
+		return digest, fmt.Errorf("cannot Fingerprint: %w", err)
+	}
+	return sha256.Sum256(p), nil
+}
+
+// Manifest binds together the fingerprints of a parameter set, a public key
+// and the evaluation keys of one party. Signing the manifest and verifying it
+// on import binds the whole public material to a single identity, preventing
+// key-substitution attacks between services: no individual key can be swapped
+// without invalidating the signature.
+type Manifest struct {
+	Parameters         [32]byte
+	PublicKey          [32]byte
+	RelinearizationKey [32]byte
+	HasRelinearization bool
+	GaloisKeys         map[uint64][32]byte
+}
+
+// NewManifest fingerprints the given public material into a Manifest. The
+// public key and the evaluation key set are optional (nil).
+func NewManifest(params encoding.BinaryMarshaler, pk *rlwe.PublicKey, evk rlwe.EvaluationKeySet) (m *Manifest, err error) {
+
+	m = &Manifest{GaloisKeys: map[uint64][32]byte{}}
+
+	if m.Parameters, err = Fingerprint(params); err != nil {
+		return nil, fmt.Errorf("cannot NewManifest: %w", err)
+	}
+
+	if pk != nil {
+		if m.PublicKey, err = Fingerprint(pk); err != nil {
+			return nil, fmt.Errorf("cannot NewManifest: %w", err)
+		}
+	}
+
+	if !utils.IsNil(evk) {
+
+		if rlk, err := evk.GetRelinearizationKey(); err == nil {
+			if m.RelinearizationKey, err = Fingerprint(rlk); err != nil {
+				return nil, fmt.Errorf("cannot NewManifest: %w", err)
+			}
+			m.HasRelinearization = true
+		}
+
+		for _, galEl := range evk.GetGaloisKeysList() {
+			gk, err := evk.GetGaloisKey(galEl)
+			if err != nil {
+				return nil, fmt.Errorf("cannot NewManifest: %w", err)
+			}
+			if m.GaloisKeys[galEl], err = Fingerprint(gk); err != nil {
+				return nil, fmt.Errorf("cannot NewManifest: %w", err)
+			}
+		}
+	}
+
+	return
+}
+
+// Bytes returns a deterministic serialization of the manifest, which is the
+// message signed by Sign.
+func (m Manifest) Bytes() []byte {
+
+	buf := new(bytes.Buffer)
+
+	buf.Write(m.Parameters[:])
+	buf.Write(m.PublicKey[:])
+
+	if m.HasRelinearization {
+		buf.WriteByte(1)
+		buf.Write(m.RelinearizationKey[:])
+	} else {
+		buf.WriteByte(0)
+	}
+
+	galEls := utils.GetSortedKeys(m.GaloisKeys)
+	binary.Write(buf, binary.LittleEndian, uint64(len(galEls)))
+	for _, galEl := range galEls {
+		binary.Write(buf, binary.LittleEndian, galEl)
+		digest := m.GaloisKeys[galEl]
+		buf.Write(digest[:])
+	}
+
+	return buf.Bytes()
+}
+
+// Sign signs the manifest with the caller-provided signer.
+func (m Manifest) Sign(signer Signer) (signature []byte, err error) {
+	if signature, err = signer.Sign(m.Bytes()); err != nil {
+		return nil, fmt.Errorf("cannot Sign: %w", err)
+	}
+	return
+}
+
+// VerifySignature verifies that signature is a valid signature of the manifest
+// under the caller-provided verifier.
+func (m Manifest) VerifySignature(verifier Verifier, signature []byte) error {
+	if err := verifier.Verify(m.Bytes(), signature); err != nil {
+		return fmt.Errorf("cannot VerifySignature: %w", err)
+	}
+	return nil
+}
+
+// Verify checks the imported public material against the manifest, returning
+// an error on the first object whose fingerprint does not match. It must be
+// called after VerifySignature to provide any protection.
+func (m Manifest) Verify(params encoding.BinaryMarshaler, pk *rlwe.PublicKey, evk rlwe.EvaluationKeySet) error {
+
+	digest, err := Fingerprint(params)
+	if err != nil {
+		return fmt.Errorf("cannot Verify: %w", err)
+	}
+	if digest != m.Parameters {
+		return fmt.Errorf("cannot Verify: parameters fingerprint mismatch")
+	}
+
+	if pk != nil {
+		if digest, err = Fingerprint(pk); err != nil {
+			return fmt.Errorf("cannot Verify: %w", err)
+		}
+		if digest != m.PublicKey {
+			return fmt.Errorf("cannot Verify: public key fingerprint mismatch")
+		}
+	}
+
+	if !utils.IsNil(evk) {
+
+		if m.HasRelinearization {
+			rlk, err := evk.GetRelinearizationKey()
+			if err != nil {
+				return fmt.Errorf("cannot Verify: %w", err)
+			}
+			if digest, err = Fingerprint(rlk); err != nil {
+				return fmt.Errorf("cannot Verify: %w", err)
+			}
+			if digest != m.RelinearizationKey {
+				return fmt.Errorf("cannot Verify: relinearization key fingerprint mismatch")
+			}
+		}
+
+		for galEl, want := range m.GaloisKeys {
+			gk, err := evk.GetGaloisKey(galEl)
+			if err != nil {
+				return fmt.Errorf("cannot Verify: %w", err)
+			}
+			if digest, err = Fingerprint(gk); err != nil {
+				return fmt.Errorf("cannot Verify: %w", err)
+			}
+			if digest != want {
+				return fmt.Errorf("cannot Verify: Galois key %d fingerprint mismatch", galEl)
+			}
+		}
+	}
+
+	return nil
+}
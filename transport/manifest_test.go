@@ -0,0 +1,70 @@
+package transport
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+)
+
+type ed25519Signer struct {
+	sk ed25519.PrivateKey
+}
+
+func (s ed25519Signer) Sign(message []byte) ([]byte, error) {
+	return ed25519.Sign(s.sk, message), nil
+}
+
+type ed25519Verifier struct {
+	pk ed25519.PublicKey
+}
+
+func (v ed25519Verifier) Verify(message, signature []byte) error {
+	if !ed25519.Verify(v.pk, message, signature) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+func TestManifest(t *testing.T) {
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := rlwe.NewParametersFromLiteral(rlwe.ParametersLiteral{
+		LogN: 10,
+		LogQ: []int{35, 20},
+		LogP: []int{61},
+	})
+	require.NoError(t, err)
+
+	kgen := rlwe.NewKeyGenerator(params)
+	sk, pk := kgen.GenKeyPairNew()
+	evk := rlwe.NewMemEvaluationKeySet(kgen.GenRelinearizationKeyNew(sk), kgen.GenGaloisKeysNew(params.GaloisElements([]int{1, 2}), sk)...)
+
+	manifest, err := NewManifest(&params, pk, evk)
+	require.NoError(t, err)
+
+	sigPk, sigSk, err := ed25519.GenerateKey(nil)
+	require.NoError(t, err)
+
+	signature, err := manifest.Sign(ed25519Signer{sk: sigSk})
+	require.NoError(t, err)
+
+	require.NoError(t, manifest.VerifySignature(ed25519Verifier{pk: sigPk}, signature))
+	require.NoError(t, manifest.Verify(&params, pk, evk))
+
+	// A tampered manifest no longer verifies.
+	tampered := *manifest
+	tampered.PublicKey[0] ^= 1
+	require.Error(t, tampered.VerifySignature(ed25519Verifier{pk: sigPk}, signature))
+
+	// A substituted public key is detected on import.
+	_, otherPk := kgen.GenKeyPairNew()
+	require.Error(t, manifest.Verify(&params, otherPk, evk))
+
+	// A substituted evaluation key set is detected on import.
+	otherEvk := rlwe.NewMemEvaluationKeySet(kgen.GenRelinearizationKeyNew(sk), kgen.GenGaloisKeysNew(params.GaloisElements([]int{1, 2}), sk)...)
+	require.Error(t, manifest.Verify(&params, pk, otherEvk))
+}
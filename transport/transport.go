@@ -0,0 +1,89 @@
+// Package transport provides scaffolding for shipping parameters, keys and
+// ciphertexts over the wire. It contains the proto service definitions (see
+// lattice.proto) and dependency-free helpers adapting the library's
+// serialization (io.WriterTo / io.ReaderFrom) to chunked streams, so that
+// server and client implementations generated from the proto file do not have
+// to reimplement the framing of large objects.
+package transport
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// DefaultChunkSize is the default payload size of a stream chunk. It is kept
+// well below the usual 4MB gRPC message-size limit.
+const DefaultChunkSize = 1 << 20
+
+// SendFunc sends one chunk of payload over a stream. The totalSize and first
+// arguments allow the implementation to populate the chunk metadata; totalSize
+// is the serialized size of the whole object and first is true on the first
+// chunk only.
+type SendFunc func(payload []byte, totalSize uint64, first bool) error
+
+// RecvFunc receives the payload of the next chunk from a stream. It must
+// return io.EOF once the stream is exhausted.
+type RecvFunc func() (payload []byte, err error)
+
+// SendObject serializes obj and sends it over a stream as chunks of at most
+// chunkSize bytes. If chunkSize is not strictly positive, DefaultChunkSize is
+// used. The object must also implement the BinarySize() int method, which all
+// the serializable types of this library do.
+func SendObject(obj io.WriterTo, chunkSize int, send SendFunc) (err error) {
+
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+
+	sizer, ok := obj.(interface{ BinarySize() int })
+	if !ok {
+		return fmt.Errorf("cannot SendObject: %T does not implement BinarySize", obj)
+	}
+
+	totalSize := sizer.BinarySize()
+
+	buf := bytes.NewBuffer(make([]byte, 0, totalSize))
+	if _, err = obj.WriteTo(buf); err != nil {
+		return fmt.Errorf("cannot SendObject: %w", err)
+	}
+
+	p := buf.Bytes()
+
+	for first := true; first || len(p) > 0; first = false {
+		n := len(p)
+		if n > chunkSize {
+			n = chunkSize
+		}
+		if err = send(p[:n], uint64(totalSize), first); err != nil {
+			return fmt.Errorf("cannot SendObject: %w", err)
+		}
+		p = p[n:]
+	}
+
+	return
+}
+
+// RecvObject receives an object sent with SendObject from a stream and
+// deserializes it into obj.
+func RecvObject(obj io.ReaderFrom, recv RecvFunc) (err error) {
+
+	buf := new(bytes.Buffer)
+
+	for {
+		payload, err := recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("cannot RecvObject: %w", err)
+		}
+		buf.Write(payload)
+	}
+
+	if _, err = obj.ReadFrom(buf); err != nil {
+		return fmt.Errorf("cannot RecvObject: %w", err)
+	}
+
+	return
+}
@@ -0,0 +1,52 @@
+package transport
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxdefi/lattice/v5/ring"
+	"github.com/luxdefi/lattice/v5/utils/sampling"
+)
+
+func TestSendRecvObject(t *testing.T) {
+
+	r, err := ring.NewRing(128, []uint64{0x3fffffa8001, 0x1000090001})
+	require.NoError(t, err)
+
+	prng, err := sampling.NewPRNG()
+	require.NoError(t, err)
+
+	want := ring.NewUniformSampler(prng, r).ReadNew()
+
+	for _, chunkSize := range []int{0, 64, 1 << 20} {
+
+		// Emulates a stream with an in-memory chunk queue.
+		var chunks [][]byte
+		var sizes []uint64
+		var firsts []bool
+
+		require.NoError(t, SendObject(want, chunkSize, func(payload []byte, totalSize uint64, first bool) error {
+			chunks = append(chunks, append([]byte{}, payload...))
+			sizes = append(sizes, totalSize)
+			firsts = append(firsts, first)
+			return nil
+		}))
+
+		require.True(t, firsts[0])
+		require.Equal(t, uint64(want.BinarySize()), sizes[0])
+
+		var have ring.Poly
+		require.NoError(t, RecvObject(&have, func() ([]byte, error) {
+			if len(chunks) == 0 {
+				return nil, io.EOF
+			}
+			payload := chunks[0]
+			chunks = chunks[1:]
+			return payload, nil
+		}))
+
+		require.True(t, want.Equal(&have))
+	}
+}
@@ -210,11 +210,12 @@ func (p *Polynomial) Evaluate(x interface{}) (y *Complex) {
 
 		scalar, constant := p.ChangeOfBasis()
 
+		// u = scalar * x + constant, the change of basis is an affine map with
+		// real coefficients, so the constant only applies to the real part.
 		xcmplx[0].Mul(xcmplx[0], scalar)
 		xcmplx[1].Mul(xcmplx[1], scalar)
 
 		xcmplx[0].Add(xcmplx[0], constant)
-		xcmplx[1].Add(xcmplx[1], constant)
 
 		TPrev := &Complex{new(big.Float).SetInt64(1), new(big.Float)}
 
@@ -0,0 +1,92 @@
+package buffer
+
+import (
+	"bufio"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultPooledBufferSize is the size in bytes of the pooled bufio wrappers
+// used by the WriteTo/ReadFrom fallback paths.
+const DefaultPooledBufferSize = 1 << 16
+
+var pooledBufferSize int64 = DefaultPooledBufferSize
+
+// SetPooledBufferSize sets the size in bytes of the pooled bufio wrappers.
+// Pooled wrappers of a different size are discarded when returned to the
+// pool, so the setting takes effect progressively.
+func SetPooledBufferSize(size int) {
+	atomic.StoreInt64(&pooledBufferSize, int64(size))
+}
+
+// PooledBufferSize returns the size in bytes of the pooled bufio wrappers.
+func PooledBufferSize() int {
+	return int(atomic.LoadInt64(&pooledBufferSize))
+}
+
+var writerPool = sync.Pool{
+	New: func() interface{} {
+		return bufio.NewWriterSize(nil, PooledBufferSize())
+	},
+}
+
+var readerPool = sync.Pool{
+	New: func() interface{} {
+		return bufio.NewReaderSize(nil, PooledBufferSize())
+	},
+}
+
+// GetPooledWriter returns a pooled bufio.Writer of PooledBufferSize writing
+// to w. The caller must flush it and return it with PutPooledWriter once
+// done.
+func GetPooledWriter(w io.Writer) (bw *bufio.Writer) {
+	bw = writerPool.Get().(*bufio.Writer)
+	bw.Reset(w)
+	return
+}
+
+// PutPooledWriter returns a pooled bufio.Writer to the pool, releasing its
+// reference to the underlying io.Writer. Unflushed data is discarded.
+func PutPooledWriter(bw *bufio.Writer) {
+	bw.Reset(nil)
+	if bw.Size() == PooledBufferSize() {
+		writerPool.Put(bw)
+	}
+}
+
+// GetPooledReader returns a pooled bufio.Reader of PooledBufferSize reading
+// from r. The caller must return it with PutPooledReader once done.
+func GetPooledReader(r io.Reader) (br *bufio.Reader) {
+	br = readerPool.Get().(*bufio.Reader)
+	br.Reset(r)
+	return
+}
+
+// PutPooledReader returns a pooled bufio.Reader to the pool, releasing its
+// reference to the underlying io.Reader. Buffered unread data is discarded.
+func PutPooledReader(br *bufio.Reader) {
+	br.Reset(nil)
+	if br.Size() == PooledBufferSize() {
+		readerPool.Put(br)
+	}
+}
+
+// WriteWithPool invokes writeTo on w wrapped into a pooled bufio.Writer,
+// avoiding the allocation of the WriteTo fallback path for writers that do
+// not implement the Writer interface. The wrapped writeTo is expected to
+// flush, as the WriteTo methods of this library do.
+func WriteWithPool(w io.Writer, writeTo func(io.Writer) (int64, error)) (n int64, err error) {
+	bw := GetPooledWriter(w)
+	defer PutPooledWriter(bw)
+	return writeTo(bw)
+}
+
+// ReadWithPool invokes readFrom on r wrapped into a pooled bufio.Reader,
+// avoiding the allocation of the ReadFrom fallback path for readers that do
+// not implement the Reader interface.
+func ReadWithPool(r io.Reader, readFrom func(io.Reader) (int64, error)) (n int64, err error) {
+	br := GetPooledReader(r)
+	defer PutPooledReader(br)
+	return readFrom(br)
+}
@@ -0,0 +1,128 @@
+package compat
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxdefi/lattice/v5/ring"
+)
+
+// testObj is a minimal serializable type whose current encoding was
+// introduced in format version 2: a version 1 payload stores its value as a
+// little-endian uint32 and must be upgraded to the uint64 encoding.
+type testObj struct {
+	value uint64
+}
+
+func (o testObj) FormatVersion() uint8 { return 2 }
+
+func (o testObj) BinarySize() int { return 8 }
+
+func (o testObj) WriteTo(w io.Writer) (n int64, err error) {
+	var p [8]byte
+	binary.LittleEndian.PutUint64(p[:], o.value)
+	inc, err := w.Write(p[:])
+	return int64(inc), err
+}
+
+func (o *testObj) ReadFrom(r io.Reader) (n int64, err error) {
+	var p [8]byte
+	inc, err := io.ReadFull(r, p[:])
+	o.value = binary.LittleEndian.Uint64(p[:])
+	return int64(inc), err
+}
+
+func TestVersionedRoundTrip(t *testing.T) {
+
+	in := &testObj{value: 0xdeadbeef}
+
+	buf := new(bytes.Buffer)
+	nWrite, err := WriteVersioned(buf, in)
+	require.NoError(t, err)
+	require.Equal(t, int64(headerSize+in.BinarySize()), nWrite)
+
+	out := new(testObj)
+	nRead, err := ReadVersioned(bytes.NewReader(buf.Bytes()), out)
+	require.NoError(t, err)
+	require.Equal(t, nWrite, nRead)
+	require.Equal(t, in.value, out.value)
+}
+
+func TestVersionedRoundTripPoly(t *testing.T) {
+
+	r, err := ring.NewRing(1<<4, ring.Qi60[:2])
+	require.NoError(t, err)
+
+	in := r.NewPoly()
+	for i := range in.Coeffs {
+		for j := range in.Coeffs[i] {
+			in.Coeffs[i][j] = uint64(i + j)
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	_, err = WriteVersioned(buf, &in)
+	require.NoError(t, err)
+
+	out := new(ring.Poly)
+	_, err = ReadVersioned(bytes.NewReader(buf.Bytes()), out)
+	require.NoError(t, err)
+	require.True(t, in.Equal(out))
+}
+
+// writeEnvelope writes a versioned envelope with an arbitrary version and
+// payload, emulating a peer running an older release.
+func writeEnvelope(version uint8, payload []byte) []byte {
+	buf := new(bytes.Buffer)
+	buf.Write(magic[:])
+	buf.WriteByte(version)
+	var size [8]byte
+	binary.LittleEndian.PutUint64(size[:], uint64(len(payload)))
+	buf.Write(size[:])
+	buf.Write(payload)
+	return buf.Bytes()
+}
+
+func TestVersionedUpgrade(t *testing.T) {
+
+	// Version 1 payload: uint32 encoding of the value.
+	payload := make([]byte, 4)
+	binary.LittleEndian.PutUint32(payload, 0xdeadbeef)
+	data := writeEnvelope(1, payload)
+
+	// Without a registered upgrade, the payload cannot be read.
+	out := new(testObj)
+	_, err := ReadVersioned(bytes.NewReader(data), out)
+	require.ErrorContains(t, err, "no upgrade registered")
+
+	RegisterUpgrade(out, 1, func(payload []byte) ([]byte, error) {
+		upgraded := make([]byte, 8)
+		binary.LittleEndian.PutUint64(upgraded, uint64(binary.LittleEndian.Uint32(payload)))
+		return upgraded, nil
+	})
+
+	_, err = ReadVersioned(bytes.NewReader(data), out)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0xdeadbeef), out.value)
+}
+
+func TestVersionedInvalidEnvelope(t *testing.T) {
+
+	// Unsupported versions are rejected.
+	out := new(testObj)
+	_, err := ReadVersioned(bytes.NewReader(writeEnvelope(CurrentFormatVersion+1, nil)), out)
+	require.ErrorContains(t, err, "unsupported format version")
+
+	_, err = ReadVersioned(bytes.NewReader(writeEnvelope(MinSupportedFormatVersion-1, nil)), out)
+	require.ErrorContains(t, err, "unsupported format version")
+
+	// Payloads without the envelope magic are rejected.
+	data := writeEnvelope(CurrentFormatVersion, nil)
+	data[0] ^= 0xff
+	_, err = ReadVersioned(bytes.NewReader(data), out)
+	require.ErrorContains(t, err, "invalid magic")
+}
@@ -0,0 +1,69 @@
+// Package concurrency implements helpers for the concurrent use of the library's
+// non thread-safe types, such as the scheme evaluators, encoders and encryptors.
+package concurrency
+
+import (
+	"runtime"
+)
+
+// ShallowCopyable is an interface for types that can produce shallow copies of
+// themselves that can be used concurrently with the receiver, such as the
+// rlwe.Evaluator or the scheme-specific evaluators and encoders.
+type ShallowCopyable[T any] interface {
+	ShallowCopy() T
+}
+
+// Pool is a concurrency-safe pool handing out shallow copies of a template
+// object to goroutines. The pool is bounded: at most size objects are ever
+// allocated, and Get blocks when all of them are checked out.
+//
+// The zero value is not usable; a Pool must be instantiated with NewPool.
+type Pool[T ShallowCopyable[T]] struct {
+	pool chan T
+}
+
+// NewPool instantiates a new Pool of size shallow copies of the template
+// object. If size is not strictly positive, it defaults to runtime.GOMAXPROCS(0).
+//
+// The template itself is not stored in the pool and remains usable by the caller.
+func NewPool[T ShallowCopyable[T]](template T, size int) *Pool[T] {
+
+	if size <= 0 {
+		size = runtime.GOMAXPROCS(0)
+	}
+
+	pool := make(chan T, size)
+
+	for i := 0; i < size; i++ {
+		pool <- template.ShallowCopy()
+	}
+
+	return &Pool[T]{pool: pool}
+}
+
+// Get checks an object out of the pool, blocking until one is available.
+// The object must be returned with Put once the caller is done with it.
+func (p *Pool[T]) Get() T {
+	return <-p.pool
+}
+
+// Put returns an object checked out with Get to the pool.
+func (p *Pool[T]) Put(obj T) {
+	p.pool <- obj
+}
+
+// Borrow checks an object out of the pool, calls f with it and returns it to
+// the pool, propagating the error returned by f. It is a convenience wrapper
+// around Get and Put for callers that do not need to hold the object across
+// function boundaries.
+func (p *Pool[T]) Borrow(f func(obj T) error) error {
+	obj := p.Get()
+	defer p.Put(obj)
+	return f(obj)
+}
+
+// Size returns the number of objects managed by the pool, i.e., the maximum
+// number of goroutines that can hold an object concurrently.
+func (p *Pool[T]) Size() int {
+	return cap(p.pool)
+}
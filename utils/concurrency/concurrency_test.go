@@ -0,0 +1,61 @@
+package concurrency
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testEvaluator struct {
+	id int
+}
+
+func (eval *testEvaluator) ShallowCopy() *testEvaluator {
+	return &testEvaluator{id: eval.id + 1}
+}
+
+func TestPool(t *testing.T) {
+
+	size := 4
+
+	pool := NewPool[*testEvaluator](&testEvaluator{}, size)
+
+	require.Equal(t, size, pool.Size())
+
+	// Checks that the pool hands out distinct shallow copies.
+	objs := map[*testEvaluator]bool{}
+	for i := 0; i < size; i++ {
+		obj := pool.Get()
+		require.False(t, objs[obj])
+		objs[obj] = true
+	}
+
+	for obj := range objs {
+		pool.Put(obj)
+	}
+
+	// Checks that concurrent borrowers never share an object.
+	var mu sync.Mutex
+	inUse := map[*testEvaluator]bool{}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4*size; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.NoError(t, pool.Borrow(func(obj *testEvaluator) error {
+				mu.Lock()
+				require.False(t, inUse[obj])
+				inUse[obj] = true
+				mu.Unlock()
+
+				mu.Lock()
+				inUse[obj] = false
+				mu.Unlock()
+				return nil
+			}))
+		}()
+	}
+	wg.Wait()
+}
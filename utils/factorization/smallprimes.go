@@ -0,0 +1,24 @@
+package factorization
+
+// smallPrimeBound is the exclusive upper bound of the primes stored in smallPrimes.
+const smallPrimeBound = 100000
+
+// sieveSmallPrimes returns all the primes below smallPrimeBound, computed with a
+// sieve of Eratosthenes. Reduced-footprint builds use it to populate smallPrimes
+// at program initialization instead of storing a large literal (see
+// smallprimes_sieve.go and smallprimes_table.go).
+func sieveSmallPrimes() (primes []uint64) {
+
+	composite := make([]bool, smallPrimeBound)
+
+	for i := 2; i < smallPrimeBound; i++ {
+		if !composite[i] {
+			primes = append(primes, uint64(i))
+			for j := i * i; j < smallPrimeBound; j += i {
+				composite[j] = true
+			}
+		}
+	}
+
+	return
+}
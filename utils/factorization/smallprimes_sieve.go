@@ -0,0 +1,9 @@
+//go:build lattice_smallfootprint || tinygo
+
+package factorization
+
+// smallPrimes is computed at program initialization instead of being stored as a
+// large literal, trading a sub-millisecond startup cost for a substantially
+// smaller binary. This matters for WebAssembly and TinyGo targets, which is why
+// the tinygo build tag selects this path automatically.
+var smallPrimes = sieveSmallPrimes()
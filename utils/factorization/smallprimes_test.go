@@ -0,0 +1,14 @@
+package factorization
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestSmallPrimes checks that the sieve used on reduced-footprint builds produces
+// exactly the content of the precomputed table. Since smallPrimes is selected by
+// build tags, this test validates both build profiles regardless of the tags in use.
+func TestSmallPrimes(t *testing.T) {
+	require.Equal(t, sieveSmallPrimes(), smallPrimes)
+}
@@ -0,0 +1,78 @@
+package metrics
+
+import (
+	"expvar"
+	"fmt"
+	"math"
+	"sync"
+)
+
+// ExpvarRecorder is a Recorder publishing counters and histogram summaries
+// into an expvar.Map, so that they are served by the standard /debug/vars
+// endpoint.
+type ExpvarRecorder struct {
+	mu sync.Mutex
+	m  *expvar.Map
+}
+
+// NewExpvarRecorder returns a Recorder publishing into m, e.g.
+//
+//	metrics.SetRecorder(metrics.NewExpvarRecorder(expvar.NewMap("lattice")))
+func NewExpvarRecorder(m *expvar.Map) *ExpvarRecorder {
+	return &ExpvarRecorder{m: m}
+}
+
+// Map returns the expvar.Map the recorder publishes into.
+func (r *ExpvarRecorder) Map() *expvar.Map {
+	return r.m
+}
+
+// IncCounter adds delta to the counter identified by name.
+func (r *ExpvarRecorder) IncCounter(name string, delta int64) {
+	r.m.Add(name, delta)
+}
+
+// Observe records value in the histogram summary identified by name.
+func (r *ExpvarRecorder) Observe(name string, value float64) {
+
+	r.mu.Lock()
+	v := r.m.Get(name)
+	if v == nil {
+		h := &expvarHistogram{min: math.Inf(1), max: math.Inf(-1)}
+		r.m.Set(name, h)
+		v = h
+	}
+	r.mu.Unlock()
+
+	if h, ok := v.(*expvarHistogram); ok {
+		h.observe(value)
+	}
+}
+
+// expvarHistogram is a summary (count, sum, min, max) of the observed values,
+// rendered as JSON by the expvar endpoint.
+type expvarHistogram struct {
+	mu       sync.Mutex
+	count    int64
+	sum      float64
+	min, max float64
+}
+
+func (h *expvarHistogram) observe(value float64) {
+	h.mu.Lock()
+	h.count++
+	h.sum += value
+	h.min = math.Min(h.min, value)
+	h.max = math.Max(h.max, value)
+	h.mu.Unlock()
+}
+
+// String implements the expvar.Var interface.
+func (h *expvarHistogram) String() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.count == 0 {
+		return `{"count":0}`
+	}
+	return fmt.Sprintf(`{"count":%d,"sum":%g,"min":%g,"max":%g,"avg":%g}`, h.count, h.sum, h.min, h.max, h.sum/float64(h.count))
+}
@@ -0,0 +1,88 @@
+// Package metrics defines a minimal instrumentation interface for the
+// cryptographic operations of the library, so that operators can monitor and
+// capacity-plan services built on top of it. Instrumentation is disabled by
+// default and is enabled by installing a Recorder with SetRecorder; the
+// package provides an expvar-backed Recorder (see NewExpvarRecorder), and the
+// interface maps directly onto Prometheus counters and histograms for
+// services that already expose a registry.
+package metrics
+
+import (
+	"sync/atomic"
+)
+
+// Counter and histogram names recorded by the library.
+const (
+	// Encrypt counts plaintext encryptions (rlwe.Encryptor).
+	Encrypt = "encrypt"
+	// Decrypt counts ciphertext decryptions (rlwe.Decryptor).
+	Decrypt = "decrypt"
+	// KeySwitch counts gadget products, the inner key-switching operation
+	// underlying relinearization, automorphisms and evaluation-key application.
+	KeySwitch = "keyswitch"
+	// Automorphism counts Galois automorphism evaluations.
+	Automorphism = "automorphism"
+	// Relinearize counts relinearizations.
+	Relinearize = "relinearize"
+	// Bootstrap counts bootstrapping operations.
+	Bootstrap = "bootstrap"
+	// WrittenBytes measures the serialized size, in bytes, of each ciphertext
+	// or plaintext written out.
+	WrittenBytes = "written_bytes"
+	// ReadBytes measures the serialized size, in bytes, of each ciphertext or
+	// plaintext read in.
+	ReadBytes = "read_bytes"
+	// EvaluatorPoolHit counts evaluator requests served from a session pool.
+	EvaluatorPoolHit = "evaluator_pool_hit"
+	// EvaluatorPoolMiss counts evaluator requests that instantiated a new
+	// evaluator.
+	EvaluatorPoolMiss = "evaluator_pool_miss"
+)
+
+// Recorder receives the events instrumented throughout the library.
+// Implementations must be safe for concurrent use, as events are recorded
+// from the caller's goroutine.
+type Recorder interface {
+	// IncCounter adds delta to the counter identified by name.
+	IncCounter(name string, delta int64)
+	// Observe records value in the histogram identified by name.
+	Observe(name string, value float64)
+}
+
+// holder wraps the recorder so that the atomic.Value always stores a single
+// concrete type.
+type holder struct {
+	r Recorder
+}
+
+var current atomic.Value
+
+// SetRecorder installs r as the global recorder receiving the events
+// instrumented throughout the library. A nil recorder disables
+// instrumentation (the default).
+func SetRecorder(r Recorder) {
+	current.Store(holder{r: r})
+}
+
+func get() Recorder {
+	if h, ok := current.Load().(holder); ok {
+		return h.r
+	}
+	return nil
+}
+
+// IncCounter adds delta to the counter identified by name on the installed
+// recorder, if any.
+func IncCounter(name string, delta int64) {
+	if r := get(); r != nil {
+		r.IncCounter(name, delta)
+	}
+}
+
+// Observe records value in the histogram identified by name on the installed
+// recorder, if any.
+func Observe(name string, value float64) {
+	if r := get(); r != nil {
+		r.Observe(name, value)
+	}
+}
@@ -0,0 +1,139 @@
+package metrics_test
+
+import (
+	"encoding/json"
+	"expvar"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luxdefi/lattice/v5/core/rlwe"
+	"github.com/luxdefi/lattice/v5/utils/buffer"
+	"github.com/luxdefi/lattice/v5/utils/metrics"
+)
+
+// testRecorder accumulates the recorded events in memory.
+type testRecorder struct {
+	mu        sync.Mutex
+	counters  map[string]int64
+	observed  map[string]int
+	lastValue map[string]float64
+}
+
+func newTestRecorder() *testRecorder {
+	return &testRecorder{
+		counters:  map[string]int64{},
+		observed:  map[string]int{},
+		lastValue: map[string]float64{},
+	}
+}
+
+func (r *testRecorder) IncCounter(name string, delta int64) {
+	r.mu.Lock()
+	r.counters[name] += delta
+	r.mu.Unlock()
+}
+
+func (r *testRecorder) Observe(name string, value float64) {
+	r.mu.Lock()
+	r.observed[name]++
+	r.lastValue[name] = value
+	r.mu.Unlock()
+}
+
+func (r *testRecorder) counter(name string) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.counters[name]
+}
+
+func TestRecorderInstrumentation(t *testing.T) {
+
+	rec := newTestRecorder()
+	metrics.SetRecorder(rec)
+	defer metrics.SetRecorder(nil)
+
+	// <<<<!Insecure parameters!>>>>
+	params, err := rlwe.NewParametersFromLiteral(rlwe.ParametersLiteral{
+		LogN: 10,
+		Q:    []uint64{0x200000440001, 0x7fff80001},
+		P:    []uint64{0x3ffffffb80001},
+	})
+	require.NoError(t, err)
+
+	kgen := rlwe.NewKeyGenerator(params)
+	sk := kgen.GenSecretKeyNew()
+	galEl := params.GaloisElement(1)
+	evk := rlwe.NewMemEvaluationKeySet(nil, kgen.GenGaloisKeysNew([]uint64{galEl}, sk)...)
+
+	enc := rlwe.NewEncryptor(params, sk)
+	dec := rlwe.NewDecryptor(params, sk)
+	eval := rlwe.NewEvaluator(params, evk)
+
+	pt := rlwe.NewPlaintext(params, params.MaxLevel())
+	ct := rlwe.NewCiphertext(params, 1, params.MaxLevel())
+	require.NoError(t, enc.Encrypt(pt, ct))
+	require.Equal(t, int64(1), rec.counter(metrics.Encrypt))
+
+	require.NoError(t, eval.Automorphism(ct, galEl, ct))
+	require.Equal(t, int64(1), rec.counter(metrics.Automorphism))
+	require.Equal(t, int64(1), rec.counter(metrics.KeySwitch))
+
+	dec.Decrypt(ct, pt)
+	require.Equal(t, int64(1), rec.counter(metrics.Decrypt))
+
+	// Serialization sizes are observed.
+	buf := buffer.NewBufferSize(ct.BinarySize())
+	n, err := ct.WriteTo(buf)
+	require.NoError(t, err)
+	require.Equal(t, 1, rec.observed[metrics.WrittenBytes])
+	require.Equal(t, float64(n), rec.lastValue[metrics.WrittenBytes])
+
+	ctIn := new(rlwe.Ciphertext)
+	_, err = ctIn.ReadFrom(buffer.NewBuffer(buf.Bytes()))
+	require.NoError(t, err)
+	require.Equal(t, 1, rec.observed[metrics.ReadBytes])
+	require.Equal(t, float64(n), rec.lastValue[metrics.ReadBytes])
+
+	// A nil recorder disables instrumentation.
+	metrics.SetRecorder(nil)
+	require.NoError(t, eval.Automorphism(ct, galEl, ct))
+	require.Equal(t, int64(1), rec.counter(metrics.Automorphism))
+}
+
+func TestExpvarRecorder(t *testing.T) {
+
+	rec := metrics.NewExpvarRecorder(new(expvar.Map).Init())
+
+	rec.IncCounter(metrics.KeySwitch, 1)
+	rec.IncCounter(metrics.KeySwitch, 2)
+	rec.Observe(metrics.WrittenBytes, 100)
+	rec.Observe(metrics.WrittenBytes, 300)
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				rec.IncCounter(metrics.Encrypt, 1)
+				rec.Observe(metrics.ReadBytes, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	m := map[string]json.RawMessage{}
+	require.NoError(t, json.Unmarshal([]byte(expvarString(rec)), &m))
+
+	require.JSONEq(t, "3", string(m[metrics.KeySwitch]))
+	require.JSONEq(t, "400", string(m[metrics.Encrypt]))
+	require.JSONEq(t, `{"count":2,"sum":400,"min":100,"max":300,"avg":200}`, string(m[metrics.WrittenBytes]))
+	require.JSONEq(t, `{"count":400,"sum":400,"min":1,"max":1,"avg":1}`, string(m[metrics.ReadBytes]))
+}
+
+// expvarString renders the map backing rec, as the /debug/vars endpoint would.
+func expvarString(rec *metrics.ExpvarRecorder) string {
+	return rec.Map().String()
+}
@@ -27,6 +27,21 @@ func EqualSlice[V comparable](a, b []V) (v bool) {
 	return
 }
 
+// EqualSliceConstantTime checks the equality between two slices of integers in
+// time independent of the position of a potential mismatch (but not of the
+// slice lengths). Unlike EqualSlice, it is suitable for comparing secret
+// values such as secret-key coefficients.
+func EqualSliceConstantTime[V constraints.Integer](a, b []V) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	var acc V
+	for i := range a {
+		acc |= a[i] ^ b[i]
+	}
+	return acc == 0
+}
+
 // MaxSlice returns the maximum value in the slice.
 func MaxSlice[V constraints.Ordered](slice []V) (max V) {
 	for _, c := range slice {
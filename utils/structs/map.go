@@ -1,7 +1,6 @@
 package structs
 
 import (
-	"bufio"
 	"fmt"
 	"io"
 
@@ -75,7 +74,7 @@ func (m *Map[K, T]) WriteTo(w io.Writer) (n int64, err error) {
 		return
 
 	default:
-		return m.WriteTo(bufio.NewWriter(w))
+		return buffer.WriteWithPool(w, m.WriteTo)
 	}
 }
 
@@ -130,7 +129,7 @@ func (m *Map[K, T]) ReadFrom(r io.Reader) (n int64, err error) {
 		return
 
 	default:
-		return m.ReadFrom(bufio.NewReader(r))
+		return buffer.ReadWithPool(r, m.ReadFrom)
 	}
 }
 
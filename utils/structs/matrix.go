@@ -1,7 +1,6 @@
 package structs
 
 import (
-	"bufio"
 	"fmt"
 	"io"
 
@@ -101,7 +100,7 @@ func (m Matrix[T]) WriteTo(w io.Writer) (n int64, err error) {
 		return n, w.Flush()
 
 	default:
-		return m.WriteTo(bufio.NewWriter(w))
+		return buffer.WriteWithPool(w, m.WriteTo)
 	}
 }
 
@@ -148,7 +147,7 @@ func (m *Matrix[T]) ReadFrom(r io.Reader) (n int64, err error) {
 		return n, nil
 
 	default:
-		return m.ReadFrom(bufio.NewReader(r))
+		return buffer.ReadWithPool(r, m.ReadFrom)
 	}
 }
 
@@ -0,0 +1,69 @@
+package structs
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// Shared wraps a long-lived object with an explicit reference count, so that
+// owners of multi-hundred-MB objects (e.g. evaluation-key sets or
+// bootstrapping keys) can release them deterministically instead of waiting
+// on the garbage collector. A cache handing out a key set acquires a
+// reference per user and releases its own on eviction: the release hook (e.g.
+// a pool return) runs exactly once, when the last reference is dropped.
+//
+// The counter is atomic, so references can be acquired and released
+// concurrently. The value must not be used after the reference holding it has
+// been released.
+type Shared[T any] struct {
+	value   *T
+	refs    int64
+	release func(*T)
+}
+
+// NewShared wraps the value with an initial reference count of one. The
+// release hook, which may be nil, is called with the value when the last
+// reference is released, e.g. to return the value to a pool.
+func NewShared[T any](value *T, release func(*T)) *Shared[T] {
+	return &Shared[T]{value: value, refs: 1, release: release}
+}
+
+// Value returns the wrapped value.
+func (s *Shared[T]) Value() *T {
+	return s.value
+}
+
+// Acquire increments the reference count and returns s, so a handle can be
+// passed on in a single expression. Acquiring a fully released wrapper is a
+// misuse and panics.
+func (s *Shared[T]) Acquire() *Shared[T] {
+	if atomic.AddInt64(&s.refs, 1) <= 1 {
+		panic(fmt.Errorf("cannot Acquire: the last reference has already been released"))
+	}
+	return s
+}
+
+// Release decrements the reference count. When the count reaches zero, the
+// wrapped value is dropped and the release hook, if any, is called with it.
+// Releasing more times than acquired is a misuse and panics.
+func (s *Shared[T]) Release() {
+
+	refs := atomic.AddInt64(&s.refs, -1)
+
+	if refs < 0 {
+		panic(fmt.Errorf("cannot Release: the last reference has already been released"))
+	}
+
+	if refs == 0 {
+		value := s.value
+		s.value = nil
+		if s.release != nil {
+			s.release(value)
+		}
+	}
+}
+
+// Refs returns the current reference count.
+func (s *Shared[T]) Refs() int64 {
+	return atomic.LoadInt64(&s.refs)
+}
@@ -0,0 +1,72 @@
+package structs
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestShared(t *testing.T) {
+
+	t.Run("Lifecycle", func(t *testing.T) {
+
+		released := 0
+		value := new(int)
+
+		s := NewShared(value, func(v *int) {
+			require.Same(t, value, v)
+			released++
+		})
+
+		require.Equal(t, int64(1), s.Refs())
+		require.Same(t, value, s.Value())
+
+		require.Same(t, s, s.Acquire())
+		require.Equal(t, int64(2), s.Refs())
+
+		s.Release()
+		require.Equal(t, 0, released)
+
+		// The hook runs exactly once, on the last release.
+		s.Release()
+		require.Equal(t, 1, released)
+		require.Nil(t, s.Value())
+
+		require.Panics(t, func() { s.Release() })
+		require.Panics(t, func() { s.Acquire() })
+	})
+
+	t.Run("NilHook", func(t *testing.T) {
+		s := NewShared(new(int), nil)
+		s.Release()
+		require.Nil(t, s.Value())
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+
+		released := 0
+		s := NewShared(new(int), func(*int) { released++ })
+
+		n := 64
+		for i := 0; i < n; i++ {
+			s.Acquire()
+		}
+
+		var wg sync.WaitGroup
+		wg.Add(n)
+		for i := 0; i < n; i++ {
+			go func() {
+				defer wg.Done()
+				s.Release()
+			}()
+		}
+		wg.Wait()
+
+		require.Equal(t, int64(1), s.Refs())
+		require.Equal(t, 0, released)
+
+		s.Release()
+		require.Equal(t, 1, released)
+	})
+}
@@ -1,6 +1,9 @@
 package structs
 
 import (
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -71,3 +74,77 @@ func testMatrix[T constraints.Float | constraints.Integer](t *testing.T) {
 	require.NoError(t, vNew.UnmarshalBinary(data))
 	require.True(t, cmp.Equal(v, vNew)) // also tests Equatable
 }
+
+func TestSyncMap(t *testing.T) {
+
+	newVector := func(scale uint64) *Vector[uint64] {
+		v := Vector[uint64](make([]uint64, 8))
+		for i := range v {
+			v[i] = scale * uint64(i)
+		}
+		return &v
+	}
+
+	t.Run("SyncMap/Serialization", func(t *testing.T) {
+
+		m := make(Map[uint64, Vector[uint64]])
+		sm := new(SyncMap[uint64, Vector[uint64]])
+
+		for _, key := range []uint64{5, 1, 3} {
+			m[key] = newVector(key)
+			sm.Set(key, newVector(key))
+		}
+
+		// Same serialization behavior as Map
+		want, err := m.MarshalBinary()
+		require.NoError(t, err)
+		have, err := sm.MarshalBinary()
+		require.NoError(t, err)
+		require.Equal(t, want, have)
+		require.Equal(t, m.BinarySize(), sm.BinarySize())
+
+		smNew := new(SyncMap[uint64, Vector[uint64]])
+		require.NoError(t, smNew.UnmarshalBinary(have))
+		require.Equal(t, []uint64{1, 3, 5}, smNew.Keys())
+		for _, key := range smNew.Keys() {
+			val, ok := smNew.Get(key)
+			require.True(t, ok)
+			require.True(t, cmp.Equal(*m[key], *val))
+		}
+	})
+
+	t.Run("SyncMap/GetOrCompute", func(t *testing.T) {
+
+		sm := new(SyncMap[uint64, Vector[uint64]])
+
+		var computed int32
+
+		var wg sync.WaitGroup
+		for g := 0; g < 8; g++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for key := uint64(0); key < 16; key++ {
+					val, err := sm.GetOrCompute(key, func() (*Vector[uint64], error) {
+						atomic.AddInt32(&computed, 1)
+						return newVector(key), nil
+					})
+					require.NoError(t, err)
+					require.Equal(t, key, (*val)[1])
+				}
+			}()
+		}
+		wg.Wait()
+
+		// Each missing key is computed exactly once
+		require.Equal(t, int32(16), atomic.LoadInt32(&computed))
+		require.Equal(t, 16, sm.Len())
+
+		_, err := sm.GetOrCompute(100, func() (*Vector[uint64], error) {
+			return nil, fmt.Errorf("cannot compute")
+		})
+		require.Error(t, err)
+		_, ok := sm.Get(100)
+		require.False(t, ok)
+	})
+}
@@ -0,0 +1,153 @@
+package structs
+
+import (
+	"io"
+	"sync"
+
+	"github.com/luxdefi/lattice/v5/utils"
+	"golang.org/x/exp/constraints"
+)
+
+// SyncMap is a concurrency-safe variant of Map: reads take a shared lock and
+// writes an exclusive one, so the map can be read by many evaluator
+// goroutines while being extended on demand (e.g. evaluation-key sets or
+// caches). Its serialization behavior is identical to Map.
+//
+// The zero value is ready to use.
+type SyncMap[K constraints.Integer, T any] struct {
+	mu sync.RWMutex
+	m  Map[K, T]
+}
+
+// Get returns the value stored under the key, or nil and false if the key is
+// not present.
+func (sm *SyncMap[K, T]) Get(key K) (val *T, ok bool) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	val, ok = sm.m[key]
+	return
+}
+
+// Set stores the value under the key, overwriting any previous value.
+func (sm *SyncMap[K, T]) Set(key K, val *T) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	if sm.m == nil {
+		sm.m = make(Map[K, T])
+	}
+	sm.m[key] = val
+}
+
+// GetOrCompute returns the value stored under the key, computing and storing
+// it with f if the key is not present. The computation is carried under the
+// exclusive lock, so concurrent calls for a missing key compute it exactly
+// once; if f returns an error, nothing is stored and the error is returned.
+func (sm *SyncMap[K, T]) GetOrCompute(key K, f func() (*T, error)) (val *T, err error) {
+
+	sm.mu.RLock()
+	val, ok := sm.m[key]
+	sm.mu.RUnlock()
+
+	if ok {
+		return
+	}
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	// The key might have been stored between the two locks
+	if val, ok = sm.m[key]; ok {
+		return
+	}
+
+	if val, err = f(); err != nil {
+		return nil, err
+	}
+
+	if sm.m == nil {
+		sm.m = make(Map[K, T])
+	}
+	sm.m[key] = val
+
+	return
+}
+
+// Delete removes the key and its value from the map.
+func (sm *SyncMap[K, T]) Delete(key K) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	delete(sm.m, key)
+}
+
+// Len returns the number of stored values.
+func (sm *SyncMap[K, T]) Len() int {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return len(sm.m)
+}
+
+// Keys returns the sorted list of the stored keys.
+func (sm *SyncMap[K, T]) Keys() []K {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return utils.GetSortedKeys(sm.m)
+}
+
+// Range calls f on each key and value in ascending key order until f returns
+// false. The shared lock is held for the duration of the iteration, so f must
+// not call the methods of sm that take the exclusive lock.
+func (sm *SyncMap[K, T]) Range(f func(key K, val *T) bool) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	for _, key := range utils.GetSortedKeys(sm.m) {
+		if !f(key, sm.m[key]) {
+			return
+		}
+	}
+}
+
+// CopyNew creates a copy of the object.
+func (sm *SyncMap[K, T]) CopyNew() *SyncMap[K, T] {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return &SyncMap[K, T]{m: *sm.m.CopyNew()}
+}
+
+// WriteTo writes the object on an io.Writer, with the same format as
+// Map.WriteTo. It implements the io.WriterTo interface, and will write
+// exactly object.BinarySize() bytes on w.
+func (sm *SyncMap[K, T]) WriteTo(w io.Writer) (n int64, err error) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.m.WriteTo(w)
+}
+
+// ReadFrom reads on the object from an io.Writer, accepting the format of
+// Map.WriteTo. It implements the io.ReaderFrom interface.
+func (sm *SyncMap[K, T]) ReadFrom(r io.Reader) (n int64, err error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.m.ReadFrom(r)
+}
+
+// BinarySize returns the serialized size of the object in bytes.
+func (sm *SyncMap[K, T]) BinarySize() (size int) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.m.BinarySize()
+}
+
+// MarshalBinary encodes the object into a binary form on a newly allocated slice of bytes.
+func (sm *SyncMap[K, T]) MarshalBinary() (p []byte, err error) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.m.MarshalBinary()
+}
+
+// UnmarshalBinary decodes a slice of bytes generated by
+// MarshalBinary or WriteTo on the object.
+func (sm *SyncMap[K, T]) UnmarshalBinary(p []byte) (err error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+	return sm.m.UnmarshalBinary(p)
+}
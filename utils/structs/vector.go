@@ -1,7 +1,6 @@
 package structs
 
 import (
-	"bufio"
 	"fmt"
 	"io"
 
@@ -142,7 +141,7 @@ func (v Vector[T]) WriteTo(w io.Writer) (n int64, err error) {
 		return n, w.Flush()
 
 	default:
-		return v.WriteTo(bufio.NewWriter(w))
+		return buffer.WriteWithPool(w, v.WriteTo)
 	}
 }
 
@@ -231,7 +230,7 @@ func (v *Vector[T]) ReadFrom(r io.Reader) (n int64, err error) {
 		return n, nil
 
 	default:
-		return v.ReadFrom(bufio.NewReader(r))
+		return buffer.ReadWithPool(r, v.ReadFrom)
 	}
 }
 
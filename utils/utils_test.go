@@ -14,6 +14,14 @@ func TestAllDistinct(t *testing.T) {
 	require.False(t, AllDistinct([]uint64{1, 2, 3, 4, 5, 5}))
 }
 
+func TestEqualSliceConstantTime(t *testing.T) {
+	require.True(t, EqualSliceConstantTime([]uint64{}, []uint64{}))
+	require.True(t, EqualSliceConstantTime([]uint64{1, 2, 3}, []uint64{1, 2, 3}))
+	require.False(t, EqualSliceConstantTime([]uint64{1, 2, 3}, []uint64{1, 2}))
+	require.False(t, EqualSliceConstantTime([]uint64{1, 2, 3}, []uint64{1, 2, 4}))
+	require.False(t, EqualSliceConstantTime([]uint64{0, 2, 3}, []uint64{1, 2, 3}))
+}
+
 func TestRotateUint64(t *testing.T) {
 	s := []uint64{0, 1, 2, 3, 4, 5, 6, 7}
 	sout := make([]uint64, len(s))